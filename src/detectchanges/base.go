@@ -0,0 +1,160 @@
+package detectchanges
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// emptyTree is git's well-known empty tree object. Diffing against it lists
+	// every file in the head commit as added.
+	emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+	// zeroSHA is sent as "before" when a push creates a new branch.
+	zeroSHA = "0000000000000000000000000000000000000000"
+
+	deepenBy          = 100 // Commits fetched per deepening step on shallow clones.
+	maxDeepenAttempts = 5   // Deepening steps before falling back to a full unshallow.
+)
+
+// resolveBase picks the commit to diff against, mirroring tj-actions/changed-files:
+//   - BASE_SHA when given;
+//   - with SINCE_LAST_PUSH, the last pushed commit of the project, when the
+//     push state has one that is still in the history;
+//   - for pull requests, the merge-base of the target branch and head;
+//   - for pushes, the "before" commit from the event payload;
+//   - otherwise the parent of head, or the empty tree for a root commit.
+//
+// Missing history is fetched from the remote, so shallow checkouts work.
+func resolveBase(cfg config, git gitFunc) (string, error) {
+	if cfg.BaseSHA != "" {
+		if err := ensureCommit(cfg, git, cfg.BaseSHA); err != nil {
+			return "", err
+		}
+		return cfg.BaseSHA, nil
+	}
+
+	if cfg.SinceLastPush {
+		if base, ok := lastPushBase(cfg, git); ok {
+			return base, nil
+		}
+	}
+
+	if isPullRequestEvent(cfg.EventName) && cfg.BaseRef != "" {
+		return pullRequestBase(cfg, git)
+	}
+
+	if cfg.EventName == "push" {
+		before, err := eventBefore(cfg.EventPath)
+		if err != nil {
+			return "", err
+		}
+		if before != "" && before != zeroSHA {
+			if err := ensureCommit(cfg, git, before); err == nil {
+				return before, nil
+			}
+			logs.Warnf("Push base %s is not available (force push?), falling back to the parent commit", before)
+		}
+	}
+
+	return parentBase(cfg, git)
+}
+
+func isPullRequestEvent(name string) bool {
+	return name == "pull_request" || name == "pull_request_target"
+}
+
+// pullRequestBase fetches the target branch and returns its merge-base with head,
+// deepening shallow history until the two lines of commits meet.
+func pullRequestBase(cfg config, git gitFunc) (string, error) {
+	remoteRef := "refs/remotes/" + cfg.Remote + "/" + cfg.BaseRef
+	refspec := "+refs/heads/" + cfg.BaseRef + ":" + remoteRef
+	args := []string{"fetch", "--no-tags", "--quiet"}
+	if isShallow(git) {
+		// Keep shallow clones shallow; history is deepened below only if needed.
+		args = append(args, "--depth=1")
+	}
+	if _, err := git(append(args, cfg.Remote, refspec)...); err != nil {
+		return "", fmt.Errorf("cannot fetch base branch %q: %w", cfg.BaseRef, err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		out, err := git("merge-base", remoteRef, cfg.SHA)
+		if err == nil {
+			return strings.TrimSpace(out), nil
+		}
+		if !isShallow(git) {
+			return "", fmt.Errorf("cannot find merge base of %q and %s: %w", cfg.BaseRef, cfg.SHA, err)
+		}
+
+		args := []string{"fetch", "--no-tags", "--quiet", fmt.Sprintf("--deepen=%d", deepenBy), cfg.Remote, refspec}
+		if attempt >= maxDeepenAttempts {
+			args = []string{"fetch", "--no-tags", "--quiet", "--unshallow", cfg.Remote}
+		}
+		if _, err := git(args...); err != nil {
+			return "", fmt.Errorf("cannot fetch history to find merge base: %w", err)
+		}
+	}
+}
+
+// parentBase returns the parent of head, fetching one more commit on shallow
+// clones. A root commit is compared against the empty tree.
+func parentBase(cfg config, git gitFunc) (string, error) {
+	parent := cfg.SHA + "^"
+	if out, err := git("rev-parse", "--verify", "--quiet", parent+"^{commit}"); err == nil {
+		return strings.TrimSpace(out), nil
+	}
+
+	if isShallow(git) {
+		if _, err := git("fetch", "--no-tags", "--quiet", "--deepen=1", cfg.Remote); err != nil {
+			return "", fmt.Errorf("cannot fetch the parent commit: %w", err)
+		}
+		if out, err := git("rev-parse", "--verify", "--quiet", parent+"^{commit}"); err == nil {
+			return strings.TrimSpace(out), nil
+		}
+	}
+
+	return emptyTree, nil
+}
+
+// ensureCommit makes sure rev exists locally, fetching it by SHA if needed.
+func ensureCommit(cfg config, git gitFunc, rev string) error {
+	if _, err := git("cat-file", "-e", rev+"^{commit}"); err == nil {
+		return nil
+	}
+	if _, err := git("fetch", "--no-tags", "--quiet", "--depth=1", cfg.Remote, rev); err != nil {
+		return fmt.Errorf("commit %s is not available: %w", rev, err)
+	}
+	return nil
+}
+
+func isShallow(git gitFunc) bool {
+	out, err := git("rev-parse", "--is-shallow-repository")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// eventBefore reads the "before" commit from a push event payload.
+// A missing payload yields an empty result.
+func eventBefore(eventPath string) (string, error) {
+	if eventPath == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(eventPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("cannot read event payload: %w", err)
+	}
+
+	var event struct {
+		Before string `json:"before"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return "", fmt.Errorf("cannot parse event payload: %w", err)
+	}
+	return event.Before, nil
+}
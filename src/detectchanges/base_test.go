@@ -0,0 +1,238 @@
+package detectchanges
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeGit answers git commands from a table keyed by the space-joined arguments.
+// Unknown commands fail, like a git invocation on a missing object would.
+type fakeGit struct {
+	responses map[string]string
+	// handler, when set, is consulted before responses and may change state.
+	handler func(args string) (string, bool)
+	calls   []string
+}
+
+func (f *fakeGit) run(args ...string) (string, error) {
+	key := strings.Join(args, " ")
+	f.calls = append(f.calls, key)
+	if f.handler != nil {
+		if out, ok := f.handler(key); ok {
+			return out, nil
+		}
+	}
+	if out, ok := f.responses[key]; ok {
+		return out, nil
+	}
+	return "", errors.New("unknown command: " + key)
+}
+
+func writeEvent(t *testing.T, payload string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(p, []byte(payload), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestResolveBase_ExplicitBaseSHA(t *testing.T) {
+	cfg := config{BaseSHA: "abc123", SHA: "HEAD", EventName: "pull_request", BaseRef: "main", Remote: "origin"}
+
+	git := &fakeGit{responses: map[string]string{"cat-file -e abc123^{commit}": ""}}
+	got, err := resolveBase(cfg, git.run)
+	if err != nil || got != "abc123" {
+		t.Fatalf("expected abc123, got %q (%v)", got, err)
+	}
+
+	// Missing commits are fetched by SHA.
+	fetched := false
+	git = &fakeGit{handler: func(args string) (string, bool) {
+		if args == "fetch --no-tags --quiet --depth=1 origin abc123" {
+			fetched = true
+			return "", true
+		}
+		return "", false
+	}}
+	if got, err := resolveBase(cfg, git.run); err != nil || got != "abc123" || !fetched {
+		t.Fatalf("expected fetched abc123, got %q (%v), fetched=%v", got, err, fetched)
+	}
+
+	git = &fakeGit{}
+	if _, err := resolveBase(cfg, git.run); err == nil || !strings.Contains(err.Error(), "commit abc123 is not available") {
+		t.Fatalf("expected unavailable commit error, got %v", err)
+	}
+}
+
+func TestResolveBase_PullRequest(t *testing.T) {
+	cfg := config{SHA: "HEAD", EventName: "pull_request", BaseRef: "main", Remote: "origin"}
+	refspec := "+refs/heads/main:refs/remotes/origin/main"
+
+	t.Run("full clone", func(t *testing.T) {
+		git := &fakeGit{responses: map[string]string{
+			"rev-parse --is-shallow-repository":         "false\n",
+			"fetch --no-tags --quiet origin " + refspec: "",
+			"merge-base refs/remotes/origin/main HEAD":  "base1\n",
+		}}
+		got, err := resolveBase(cfg, git.run)
+		if err != nil || got != "base1" {
+			t.Fatalf("expected base1, got %q (%v)", got, err)
+		}
+	})
+
+	t.Run("shallow clone is deepened until the merge base is found", func(t *testing.T) {
+		deepened := 0
+		git := &fakeGit{handler: func(args string) (string, bool) {
+			switch args {
+			case "rev-parse --is-shallow-repository":
+				return "true\n", true
+			case "fetch --no-tags --quiet --depth=1 origin " + refspec:
+				return "", true
+			case "fetch --no-tags --quiet --deepen=100 origin " + refspec:
+				deepened++
+				return "", true
+			case "merge-base refs/remotes/origin/main HEAD":
+				return "base2\n", deepened == 2
+			}
+			return "", false
+		}}
+		got, err := resolveBase(cfg, git.run)
+		if err != nil || got != "base2" {
+			t.Fatalf("expected base2, got %q (%v)", got, err)
+		}
+	})
+
+	t.Run("falls back to unshallow", func(t *testing.T) {
+		unshallowed := false
+		git := &fakeGit{handler: func(args string) (string, bool) {
+			switch args {
+			case "rev-parse --is-shallow-repository":
+				return "true\n", true
+			case "fetch --no-tags --quiet --depth=1 origin " + refspec,
+				"fetch --no-tags --quiet --deepen=100 origin " + refspec:
+				return "", true
+			case "fetch --no-tags --quiet --unshallow origin":
+				unshallowed = true
+				return "", true
+			case "merge-base refs/remotes/origin/main HEAD":
+				return "base3\n", unshallowed
+			}
+			return "", false
+		}}
+		got, err := resolveBase(cfg, git.run)
+		if err != nil || got != "base3" {
+			t.Fatalf("expected base3, got %q (%v)", got, err)
+		}
+
+		var deepens int
+		for _, c := range git.calls {
+			if strings.Contains(c, "--deepen=") {
+				deepens++
+			}
+		}
+		if deepens != maxDeepenAttempts {
+			t.Fatalf("expected %d deepening fetches, got %d", maxDeepenAttempts, deepens)
+		}
+	})
+
+	t.Run("unrelated histories", func(t *testing.T) {
+		git := &fakeGit{responses: map[string]string{
+			"rev-parse --is-shallow-repository":         "false\n",
+			"fetch --no-tags --quiet origin " + refspec: "",
+		}}
+		if _, err := resolveBase(cfg, git.run); err == nil || !strings.Contains(err.Error(), `cannot find merge base of "main"`) {
+			t.Fatalf("expected merge base error, got %v", err)
+		}
+	})
+
+	t.Run("fetch failure", func(t *testing.T) {
+		git := &fakeGit{responses: map[string]string{"rev-parse --is-shallow-repository": "false\n"}}
+		if _, err := resolveBase(cfg, git.run); err == nil || !strings.Contains(err.Error(), `cannot fetch base branch "main"`) {
+			t.Fatalf("expected fetch error, got %v", err)
+		}
+	})
+}
+
+func TestResolveBase_Push(t *testing.T) {
+	before := "1111111111111111111111111111111111111111"
+
+	git := &fakeGit{responses: map[string]string{"cat-file -e " + before + "^{commit}": ""}}
+	cfg := config{SHA: "HEAD", EventName: "push", EventPath: writeEvent(t, `{"before":"`+before+`"}`), Remote: "origin"}
+	if got, err := resolveBase(cfg, git.run); err != nil || got != before {
+		t.Fatalf("expected %s, got %q (%v)", before, got, err)
+	}
+
+	// A new branch sends the zero SHA; the parent commit is used instead.
+	git = &fakeGit{responses: map[string]string{"rev-parse --verify --quiet HEAD^^{commit}": "parent\n"}}
+	cfg.EventPath = writeEvent(t, `{"before":"`+zeroSHA+`"}`)
+	if got, err := resolveBase(cfg, git.run); err != nil || got != "parent" {
+		t.Fatalf("expected parent, got %q (%v)", got, err)
+	}
+
+	// A force-pushed "before" that cannot be fetched also falls back to the parent.
+	cfg.EventPath = writeEvent(t, `{"before":"`+before+`"}`)
+	if got, err := resolveBase(cfg, git.run); err != nil || got != "parent" {
+		t.Fatalf("expected parent, got %q (%v)", got, err)
+	}
+
+	cfg.EventPath = writeEvent(t, `not json`)
+	if _, err := resolveBase(cfg, git.run); err == nil || !strings.Contains(err.Error(), "cannot parse event payload") {
+		t.Fatalf("expected payload error, got %v", err)
+	}
+}
+
+func TestResolveBase_Parent(t *testing.T) {
+	cfg := config{SHA: "HEAD", EventName: "workflow_dispatch", Remote: "origin"}
+
+	t.Run("shallow clone fetches the parent", func(t *testing.T) {
+		fetched := false
+		git := &fakeGit{handler: func(args string) (string, bool) {
+			switch args {
+			case "rev-parse --is-shallow-repository":
+				return "true\n", true
+			case "fetch --no-tags --quiet --deepen=1 origin":
+				fetched = true
+				return "", true
+			case "rev-parse --verify --quiet HEAD^^{commit}":
+				return "parent\n", fetched
+			}
+			return "", false
+		}}
+		if got, err := resolveBase(cfg, git.run); err != nil || got != "parent" {
+			t.Fatalf("expected parent, got %q (%v)", got, err)
+		}
+	})
+
+	t.Run("root commit uses the empty tree", func(t *testing.T) {
+		git := &fakeGit{responses: map[string]string{"rev-parse --is-shallow-repository": "false\n"}}
+		if got, err := resolveBase(cfg, git.run); err != nil || got != emptyTree {
+			t.Fatalf("expected empty tree, got %q (%v)", got, err)
+		}
+	})
+}
+
+func TestEventBefore(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "no payload", path: "", want: ""},
+		{name: "missing file", path: filepath.Join(t.TempDir(), "missing.json"), want: ""},
+		{name: "push payload", path: writeEvent(t, `{"before":"abc","after":"def"}`), want: "abc"},
+		{name: "other payload", path: writeEvent(t, `{"action":"opened"}`), want: ""},
+	} {
+		got, err := eventBefore(tt.path)
+		if err != nil || got != tt.want {
+			t.Fatalf("%s: expected %q, got %q (%v)", tt.name, tt.want, got, err)
+		}
+	}
+
+	if !isPullRequestEvent("pull_request_target") || isPullRequestEvent("push") {
+		t.Fatal("unexpected pull request event detection")
+	}
+}
@@ -0,0 +1,95 @@
+package detectchanges
+
+import (
+	"fmt"
+	"strings"
+)
+
+// change is one entry of "git diff --name-status".
+type change struct {
+	Status  byte   // A, C, M, R, or T
+	Path    string // path in head
+	OldPath string // source path for renames and copies
+}
+
+// diffChanges lists files added, copied, modified, renamed, or changed in type
+// between base and head. Renames are detected, so a moved file is reported once
+// under its new path. Deleted files are left out since there is nothing to upload.
+func diffChanges(git gitFunc, base, head string) ([]change, error) {
+	out, err := git("diff", "--name-status", "-z", "-M", "--diff-filter=ACMRT", base, head, "--")
+	if err != nil {
+		return nil, err
+	}
+	return parseNameStatus(out)
+}
+
+// parseNameStatus parses NUL-delimited "git diff --name-status -z" output.
+func parseNameStatus(out string) ([]change, error) {
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	if len(fields) == 1 && fields[0] == "" {
+		return nil, nil
+	}
+
+	var changes []change
+	for i := 0; i < len(fields); {
+		status := fields[i]
+		if status == "" {
+			return nil, fmt.Errorf("unexpected empty status in git diff output")
+		}
+
+		c := change{Status: status[0]}
+		switch c.Status {
+		case 'R', 'C':
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("truncated git diff output for status %q", status)
+			}
+			c.OldPath, c.Path = fields[i+1], fields[i+2]
+			i += 3
+		default:
+			if i+1 >= len(fields) {
+				return nil, fmt.Errorf("truncated git diff output for status %q", status)
+			}
+			c.Path = fields[i+1]
+			i += 2
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// detectChanges returns the changed files matching the stored patterns, in diff order.
+func detectChanges(cfg config, git gitFunc) ([]string, error) {
+	patterns, err := readPatterns(cfg.PathsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	ignored, err := readIgnorePatterns(cfg.IgnoreFile)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Exclude = append(patterns.Exclude, ignored...)
+
+	base, err := resolveBase(cfg, git)
+	if err != nil {
+		return nil, err
+	}
+	logs.Infof("Comparing %s...%s", base, cfg.SHA)
+
+	changes, err := diffChanges(git, base, cfg.SHA)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, c := range changes {
+		if !patterns.match(c.Path) {
+			continue
+		}
+		if c.Status == 'R' {
+			logs.Infof("Renamed: %s -> %s", c.OldPath, c.Path)
+		}
+		files = append(files, c.Path)
+	}
+	return files, nil
+}
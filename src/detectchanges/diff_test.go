@@ -0,0 +1,129 @@
+package detectchanges
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	out := "M\x00locales/en/app.json\x00R087\x00locales/en/old.json\x00locales/en/new.json\x00A\x00locales/en/with, comma.json\x00C100\x00a.json\x00b.json\x00"
+
+	got, err := parseNameStatus(out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []change{
+		{Status: 'M', Path: "locales/en/app.json"},
+		{Status: 'R', Path: "locales/en/new.json", OldPath: "locales/en/old.json"},
+		{Status: 'A', Path: "locales/en/with, comma.json"},
+		{Status: 'C', Path: "b.json", OldPath: "a.json"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got, err := parseNameStatus(""); err != nil || got != nil {
+		t.Fatalf("expected no changes, got %v (%v)", got, err)
+	}
+
+	for _, bad := range []string{"M\x00", "R100\x00old.json\x00", "\x00\x00a.json\x00"} {
+		if _, err := parseNameStatus(bad); err == nil {
+			t.Fatalf("%q: expected parse error", bad)
+		}
+	}
+}
+
+func TestDetectChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) (string, error) {
+		return gitIn(dir, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	}
+	mustGit := func(args ...string) {
+		t.Helper()
+		if _, err := git(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		full := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	long := `{"greeting":"Hello there","farewell":"Goodbye","thanks":"Thank you very much"}`
+	writeFile("locales/en/app.json", "{}")
+	writeFile("locales/en/old.json", long)
+	writeFile("locales/en/gone.json", "{}")
+	writeFile("locales/en/fixtures/sample.json", "{}")
+	writeFile("locales/fr/app.json", "{}")
+	mustGit("init", "-q")
+	mustGit("add", ".")
+	mustGit("commit", "-q", "-m", "base")
+	base, err := git("rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile("locales/en/app.json", `{"a":"b"}`)
+	writeFile("locales/en/fixtures/sample.json", `{"a":"b"}`)
+	writeFile("locales/fr/app.json", `{"a":"b"}`)
+	writeFile("locales/en/added.json", "{}")
+	mustGit("mv", "locales/en/old.json", "locales/en/new.json")
+	mustGit("rm", "-q", "locales/en/gone.json")
+	mustGit("add", ".")
+	mustGit("commit", "-q", "-m", "change")
+
+	pathsFile := filepath.Join(t.TempDir(), "paths.txt")
+	if err := os.WriteFile(pathsFile, []byte("locales/en/**/*.json\n!locales/en/fixtures/**\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config{PathsFile: pathsFile, BaseSHA: strings.TrimSpace(base), SHA: "HEAD", Remote: "origin"}
+	got, err := detectChanges(cfg, git)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"locales/en/added.json", "locales/en/app.json", "locales/en/new.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// Without a base, the parent commit is used, which yields the same range here.
+	cfg.BaseSHA = ""
+	if got, err := detectChanges(cfg, git); err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v (%v)", want, got, err)
+	}
+
+	ignoreFile := filepath.Join(t.TempDir(), "paths.ignore.txt")
+	if err := os.WriteFile(ignoreFile, []byte("locales/en/added.json\nlocales/en/added.json/**\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.IgnoreFile = ignoreFile
+	if got, err := detectChanges(cfg, git); err != nil || !reflect.DeepEqual(got, want[1:]) {
+		t.Fatalf("expected %v, got %v (%v)", want[1:], got, err)
+	}
+
+	cfg.IgnoreFile = filepath.Join(t.TempDir(), "missing.txt")
+	if _, err := detectChanges(cfg, git); err == nil || !strings.Contains(err.Error(), "cannot read ignored paths") {
+		t.Fatalf("expected ignore file error, got %v", err)
+	}
+	cfg.IgnoreFile = ""
+
+	cfg.PathsFile = filepath.Join(t.TempDir(), "missing.txt")
+	if _, err := detectChanges(cfg, git); err == nil || !strings.Contains(err.Error(), "cannot read translation paths") {
+		t.Fatalf("expected paths file error, got %v", err)
+	}
+}
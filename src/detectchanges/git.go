@@ -0,0 +1,30 @@
+package detectchanges
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// gitFunc runs a git command in the working directory and returns its stdout.
+type gitFunc func(args ...string) (string, error)
+
+// runGit runs git in the current directory.
+func runGit(args ...string) (string, error) {
+	return gitIn("", args...)
+}
+
+func gitIn(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
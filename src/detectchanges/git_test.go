@@ -0,0 +1,22 @@
+package detectchanges
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestGitIn(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	out, err := gitIn(t.TempDir(), "--version")
+	if err != nil || !strings.HasPrefix(out, "git version") {
+		t.Fatalf("unexpected result %q (%v)", out, err)
+	}
+
+	if _, err := gitIn(t.TempDir(), "rev-parse", "HEAD"); err == nil || !strings.Contains(err.Error(), "git rev-parse failed") {
+		t.Fatalf("expected error outside a repository, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package detectchanges
+
+import (
+	"regexp"
+
+	"lokalise-push-action/internal/pushstate"
+)
+
+var commitSHARe = regexp.MustCompile(`^[0-9a-fA-F]{4,64}$`)
+
+// lastPushBase returns the last pushed commit of cfg.ProjectID from the push
+// state, so files changed by runs that were skipped or failed since are
+// pushed too. It reports false, after saying why, when there is no usable
+// commit and the event base applies instead: nothing was recorded yet, the
+// state can't be read, or the commit is gone from the history (force push).
+func lastPushBase(cfg config, git gitFunc) (string, bool) {
+	state, err := pushstate.Load(cfg.CacheDir, cfg.ProjectID)
+	if err != nil {
+		logs.Warnf("Cannot read the last push of project %s, falling back to the event base: %v", cfg.ProjectID, err)
+		return "", false
+	}
+
+	sha := state.LastPushedSHA
+	if sha == "" {
+		logs.Infof("No push of project %s recorded yet, falling back to the event base", cfg.ProjectID)
+		return "", false
+	}
+	// The state comes from a cache, so only a plain commit SHA is passed to git.
+	if !commitSHARe.MatchString(sha) {
+		logs.Warnf("Ignoring the last push of project %s: %q is not a commit SHA", cfg.ProjectID, sha)
+		return "", false
+	}
+	if err := ensureCommit(cfg, git, sha); err != nil {
+		logs.Warnf("Last pushed commit %s is not available (force push?), falling back to the event base", sha)
+		return "", false
+	}
+
+	logs.Infof("Detecting changes since the last push of project %s (%s)", cfg.ProjectID, sha)
+	return sha, true
+}
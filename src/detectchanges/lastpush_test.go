@@ -0,0 +1,86 @@
+package detectchanges
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lokalise-push-action/internal/pushstate"
+)
+
+// saveLastPush records sha as the last push of project in a new cache.
+func saveLastPush(t *testing.T, project, sha string) string {
+	t.Helper()
+	cache := t.TempDir()
+	state, err := pushstate.Load(cache, project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.LastPushedSHA = sha
+	if err := state.Save(cache); err != nil {
+		t.Fatal(err)
+	}
+	return cache
+}
+
+func TestResolveBase_SinceLastPush(t *testing.T) {
+	const last = "1111111111111111111111111111111111111111"
+	const before = "2222222222222222222222222222222222222222"
+	event := writeEvent(t, `{"before":"`+before+`"}`)
+
+	newConfig := func(cache string) config {
+		return config{
+			SHA: "HEAD", EventName: "push", EventPath: event, Remote: "origin",
+			SinceLastPush: true, CacheDir: cache, ProjectID: "111.abc",
+		}
+	}
+	eventGit := map[string]string{"cat-file -e " + before + "^{commit}": ""}
+
+	t.Run("diffs against the last pushed commit", func(t *testing.T) {
+		git := &fakeGit{responses: map[string]string{"cat-file -e " + last + "^{commit}": ""}}
+		if got, err := resolveBase(newConfig(saveLastPush(t, "111.abc", last)), git.run); err != nil || got != last {
+			t.Fatalf("expected %s, got %q (%v)", last, got, err)
+		}
+	})
+
+	t.Run("explicit BASE_SHA wins", func(t *testing.T) {
+		cfg := newConfig(saveLastPush(t, "111.abc", last))
+		cfg.BaseSHA = "abc123"
+		git := &fakeGit{responses: map[string]string{"cat-file -e abc123^{commit}": ""}}
+		if got, err := resolveBase(cfg, git.run); err != nil || got != "abc123" {
+			t.Fatalf("expected abc123, got %q (%v)", got, err)
+		}
+	})
+
+	fallbacks := map[string]func(t *testing.T) string{
+		"no push recorded": func(t *testing.T) string { return t.TempDir() },
+		"other project":    func(t *testing.T) string { return saveLastPush(t, "222.def", last) },
+		"commit not in the history": func(t *testing.T) string {
+			return saveLastPush(t, "111.abc", "3333333333333333333333333333333333333333")
+		},
+		"not a commit SHA": func(t *testing.T) string { return saveLastPush(t, "111.abc", "--upload-pack=evil") },
+		"unreadable state": func(t *testing.T) string {
+			cache := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(cache, "111.abc"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(cache, "111.abc", "state.json"), []byte("{"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			return cache
+		},
+	}
+	for name, cache := range fallbacks {
+		t.Run(name+" falls back to the event base", func(t *testing.T) {
+			git := &fakeGit{responses: eventGit}
+			if got, err := resolveBase(newConfig(cache(t)), git.run); err != nil || got != before {
+				t.Fatalf("expected %s, got %q (%v)", before, got, err)
+			}
+			for _, call := range git.calls {
+				if call == "cat-file -e --upload-pack=evil^{commit}" {
+					t.Fatalf("expected an invalid SHA to never reach git")
+				}
+			}
+		})
+	}
+}
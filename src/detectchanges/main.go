@@ -0,0 +1,121 @@
+package detectchanges
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/ghoutput"
+	"lokalise-push-action/internal/logging"
+)
+
+// exitFunc is a function variable that defaults to os.Exit.
+// Overridable in tests to assert exit behavior without terminating the process.
+var exitFunc = os.Exit
+
+// logs is the logger of the command; Main configures it from the environment.
+var logs = logging.New(os.Stdout, os.Stderr)
+
+// Main lists the changed translation files, configured through environment variables.
+// It exits the process on failure.
+func Main() {
+	if err := logs.Configure(); err != nil {
+		returnWithError(err.Error())
+	}
+	if err := run(); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// Check reads and validates the configuration of Main without running it.
+func Check() error {
+	_, err := validateEnvironment()
+	return envconf.Join([]error{logs.Configure(), err})
+}
+
+func run() error {
+	return runWith(
+		validateEnvironment,
+		detectChanges,
+		ghoutput.Writer(logs),
+	)
+}
+
+type detectFunc func(config, gitFunc) ([]string, error)
+
+func runWith(
+	validate func() (config, error),
+	detect detectFunc,
+	write func(string, string) bool,
+) error {
+	// Read and validate inputs from the environment.
+	cfg, err := validate()
+	if err != nil {
+		return err
+	}
+
+	files, err := detect(cfg, runGit)
+	if err != nil {
+		return fmt.Errorf("cannot detect changed files: %w", err)
+	}
+
+	// Watched files such as lokalise.yml are not uploaded; a change to any of
+	// them makes the action push all translation files instead.
+	files, watched := splitWatched(files, cfg.Watch)
+	for _, f := range watched {
+		logs.Infof("Watched file changed: %s", f)
+	}
+	logs.Infof("Found %d changed translation files", len(files))
+
+	// Outputs mirror the ones previously provided by tj-actions/changed-files.
+	anyChanged := "false"
+	if len(files) > 0 {
+		anyChanged = "true"
+	}
+	if !write("any_changed", anyChanged) {
+		return fmt.Errorf("cannot write any_changed to GITHUB_OUTPUT")
+	}
+	if !write("all_changed_files", strings.Join(files, ",")) {
+		return fmt.Errorf("cannot write all_changed_files to GITHUB_OUTPUT")
+	}
+	// all_changed_files can't hold names with commas, so the upload reads this
+	// NUL-terminated list instead.
+	listPath, err := writeChangedList(cfg.TempDir, files)
+	if err != nil {
+		return fmt.Errorf("cannot write changed files list: %w", err)
+	}
+	if !write("changed_files_path", listPath) {
+		return fmt.Errorf("cannot write changed_files_path to GITHUB_OUTPUT")
+	}
+	if len(cfg.Watch) > 0 && !write("watched_changed", strconv.FormatBool(len(watched) > 0)) {
+		return fmt.Errorf("cannot write watched_changed to GITHUB_OUTPUT")
+	}
+
+	return nil
+}
+
+// writeChangedList writes files, each terminated by a NUL byte, to a new file
+// in dir (see os.CreateTemp) and returns its path.
+func writeChangedList(dir string, files []string) (string, error) {
+	var b strings.Builder
+	for _, f := range files {
+		b.WriteString(f)
+		b.WriteByte(0)
+	}
+
+	f, err := os.CreateTemp(dir, "lokalise-changed-files-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.WriteString(b.String())
+	return f.Name(), errors.Join(err, f.Close())
+}
+
+// returnWithError prints an error and exits with a non-zero code.
+func returnWithError(message string) {
+	logs.Errorf("%s", message)
+	exitFunc(1)
+}
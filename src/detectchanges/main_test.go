@@ -0,0 +1,228 @@
+package detectchanges
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Override exitFunc for testing.
+	exitFunc = func(code int) {
+		panic(fmt.Sprintf("Exit called with code %d", code))
+	}
+
+	code := m.Run()
+
+	// Restore exitFunc after testing.
+	exitFunc = os.Exit
+
+	os.Exit(code)
+}
+
+func TestRunWith(t *testing.T) {
+	tempDir := t.TempDir()
+	validate := func() (config, error) {
+		return config{PathsFile: defaultPathsFile, SHA: "HEAD", TempDir: tempDir}, nil
+	}
+
+	// changedList drops changed_files_path from writes and returns the list it names.
+	changedList := func(t *testing.T, writes map[string]string) string {
+		t.Helper()
+		path := writes["changed_files_path"]
+		if filepath.Dir(path) != tempDir {
+			t.Fatalf("expected the changed files list under %s, got %q", tempDir, path)
+		}
+		delete(writes, "changed_files_path")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("cannot read the changed files list: %v", err)
+		}
+		return string(data)
+	}
+
+	t.Run("writes changed files", func(t *testing.T) {
+		detect := func(cfg config, git gitFunc) ([]string, error) {
+			if cfg.SHA != "HEAD" || git == nil {
+				t.Fatalf("unexpected detect call: %+v", cfg)
+			}
+			return []string{"locales/en/a.json", "locales/en/b.json"}, nil
+		}
+
+		writes := make(map[string]string)
+		err := runWith(validate, detect, func(key, value string) bool {
+			writes[key] = value
+			return true
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := changedList(t, writes), "locales/en/a.json\x00locales/en/b.json\x00"; got != want {
+			t.Fatalf("expected list %q, got %q", want, got)
+		}
+		want := map[string]string{"any_changed": "true", "all_changed_files": "locales/en/a.json,locales/en/b.json"}
+		if !reflect.DeepEqual(writes, want) {
+			t.Fatalf("expected %v, got %v", want, writes)
+		}
+	})
+
+	t.Run("reports no changes", func(t *testing.T) {
+		detect := func(config, gitFunc) ([]string, error) {
+			return nil, nil
+		}
+
+		writes := make(map[string]string)
+		if err := runWith(validate, detect, func(key, value string) bool {
+			writes[key] = value
+			return true
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := changedList(t, writes); got != "" {
+			t.Fatalf("expected an empty list, got %q", got)
+		}
+		want := map[string]string{"any_changed": "false", "all_changed_files": ""}
+		if !reflect.DeepEqual(writes, want) {
+			t.Fatalf("expected %v, got %v", want, writes)
+		}
+	})
+
+	t.Run("separates watched files", func(t *testing.T) {
+		validate := func() (config, error) {
+			return config{PathsFile: defaultPathsFile, SHA: "HEAD", TempDir: tempDir, Watch: []string{"lokalise.yml"}}, nil
+		}
+		for _, tt := range []struct {
+			changed []string
+			want    map[string]string
+		}{
+			{
+				changed: []string{"locales/en/a.json", "lokalise.yml"},
+				want:    map[string]string{"any_changed": "true", "all_changed_files": "locales/en/a.json", "watched_changed": "true"},
+			},
+			{
+				changed: []string{"lokalise.yml"},
+				want:    map[string]string{"any_changed": "false", "all_changed_files": "", "watched_changed": "true"},
+			},
+			{
+				changed: []string{"locales/en/a.json"},
+				want:    map[string]string{"any_changed": "true", "all_changed_files": "locales/en/a.json", "watched_changed": "false"},
+			},
+		} {
+			writes := make(map[string]string)
+			detect := func(config, gitFunc) ([]string, error) { return tt.changed, nil }
+			if err := runWith(validate, detect, func(key, value string) bool {
+				writes[key] = value
+				return true
+			}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			changedList(t, writes)
+			if !reflect.DeepEqual(writes, tt.want) {
+				t.Fatalf("%v: expected %v, got %v", tt.changed, tt.want, writes)
+			}
+		}
+	})
+
+	t.Run("returns validation error", func(t *testing.T) {
+		err := runWith(
+			func() (config, error) { return config{}, errors.New("bad env") },
+			func(config, gitFunc) ([]string, error) {
+				t.Fatal("detect should not be called")
+				return nil, nil
+			},
+			func(string, string) bool {
+				t.Fatal("write should not be called")
+				return true
+			},
+		)
+		if err == nil || err.Error() != "bad env" {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("wraps detection error", func(t *testing.T) {
+		err := runWith(validate, func(config, gitFunc) ([]string, error) {
+			return nil, errors.New("no history")
+		}, func(string, string) bool {
+			t.Fatal("write should not be called")
+			return true
+		})
+		if err == nil || !strings.Contains(err.Error(), "cannot detect changed files: no history") {
+			t.Fatalf("expected wrapped error, got %v", err)
+		}
+	})
+
+	t.Run("returns write error", func(t *testing.T) {
+		detect := func(config, gitFunc) ([]string, error) {
+			return []string{"a.json"}, nil
+		}
+		err := runWith(validate, detect, func(key, _ string) bool {
+			return key != "all_changed_files"
+		})
+		if err == nil || !strings.Contains(err.Error(), "cannot write all_changed_files") {
+			t.Fatalf("expected write error, got %v", err)
+		}
+	})
+
+	t.Run("keeps commas in the changed files list", func(t *testing.T) {
+		detect := func(config, gitFunc) ([]string, error) {
+			return []string{"locales/en/a, b.json"}, nil
+		}
+		writes := make(map[string]string)
+		if err := runWith(validate, detect, func(key, value string) bool {
+			writes[key] = value
+			return true
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := changedList(t, writes), "locales/en/a, b.json\x00"; got != want {
+			t.Fatalf("expected list %q, got %q", want, got)
+		}
+	})
+
+	t.Run("reports a list write error", func(t *testing.T) {
+		validate := func() (config, error) {
+			return config{PathsFile: defaultPathsFile, SHA: "HEAD", TempDir: filepath.Join(tempDir, "missing")}, nil
+		}
+		detect := func(config, gitFunc) ([]string, error) { return nil, nil }
+		err := runWith(validate, detect, func(string, string) bool { return true })
+		if err == nil || !strings.Contains(err.Error(), "cannot write changed files list") {
+			t.Fatalf("expected list write error, got %v", err)
+		}
+	})
+}
+
+func TestReturnWithError(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil || r != "Exit called with code 1" {
+			t.Fatalf("expected exit panic, got %v", r)
+		}
+	}()
+	returnWithError("boom")
+}
+
+func TestCheck(t *testing.T) {
+	t.Setenv("BASE_SHA", "")
+	t.Setenv("SHA", "")
+	t.Setenv("GITHUB_BASE_REF", "")
+	if err := Check(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Setenv("SHA", "--output=x")
+	if err := Check(); err == nil || !strings.Contains(err.Error(), "invalid SHA") {
+		t.Fatalf("expected invalid SHA error, got %v", err)
+	}
+
+	t.Setenv("LOG_LEVEL", "verbose")
+	if err := Check(); err == nil || !strings.Contains(err.Error(), "invalid LOG_LEVEL") {
+		t.Fatalf("expected invalid LOG_LEVEL error, got %v", err)
+	}
+}
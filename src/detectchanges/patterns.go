@@ -0,0 +1,137 @@
+package detectchanges
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// patternSet holds the globs written by storetranslationpaths. A file matches
+// when any include glob matches it and no exclude ("!"-prefixed) glob does.
+type patternSet struct {
+	Include []string
+	Exclude []string
+}
+
+// readPatterns loads one glob per line from path, skipping blank lines.
+func readPatterns(path string) (patternSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return patternSet{}, fmt.Errorf("cannot read translation paths: %w", err)
+	}
+	return parsePatterns(string(data))
+}
+
+// readIgnorePatterns loads the exclusion globs from the optional ignore file.
+// Every line excludes; a leading "!" is accepted and ignored.
+func readIgnorePatterns(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read ignored paths: %w", err)
+	}
+
+	var patterns []string
+	for line := range strings.SplitSeq(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		pattern, _, err := unanchor(strings.TrimPrefix(trimmed, "!"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignored path pattern %q: %w", line, err)
+		}
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("invalid ignored path pattern %q: %w", line, doublestar.ErrBadPattern)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+func parsePatterns(raw string) (patternSet, error) {
+	var set patternSet
+	for line := range strings.SplitSeq(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		pattern, exclude := strings.CutPrefix(line, "!")
+		pattern, magicExclude, err := unanchor(pattern)
+		if err != nil {
+			return patternSet{}, fmt.Errorf("invalid translation path pattern %q: %w", line, err)
+		}
+		exclude = exclude || magicExclude
+		if !doublestar.ValidatePattern(pattern) {
+			return patternSet{}, fmt.Errorf("invalid translation path pattern %q: %w", line, doublestar.ErrBadPattern)
+		}
+
+		if exclude {
+			set.Exclude = append(set.Exclude, pattern)
+		} else {
+			set.Include = append(set.Include, pattern)
+		}
+	}
+
+	if len(set.Include) == 0 {
+		return patternSet{}, fmt.Errorf("no translation path patterns to match")
+	}
+	return set, nil
+}
+
+// unanchor strips the anchoring added by PATHSPEC_STYLE: a "./" prefix or
+// ":(glob)" magic, where ":(glob,exclude)" marks an exclusion.
+func unanchor(line string) (pattern string, exclude bool, err error) {
+	rest, ok := strings.CutPrefix(line, ":(")
+	if !ok {
+		return strings.TrimPrefix(line, "./"), false, nil
+	}
+
+	magic, pattern, ok := strings.Cut(rest, ")")
+	if !ok {
+		return "", false, fmt.Errorf("unterminated pathspec magic")
+	}
+	for word := range strings.SplitSeq(magic, ",") {
+		switch word {
+		case "glob":
+		case "exclude":
+			exclude = true
+		default:
+			return "", false, fmt.Errorf("unsupported pathspec magic %q", word)
+		}
+	}
+	return pattern, exclude, nil
+}
+
+func (s patternSet) match(file string) bool {
+	return matchesAny(file, s.Include) && !matchesAny(file, s.Exclude)
+}
+
+// splitWatched separates the files matching a WATCH_PATTERNS glob, which only
+// trigger a push, from the translation files to upload.
+func splitWatched(files, watch []string) (translations, watched []string) {
+	for _, f := range files {
+		if matchesAny(f, watch) {
+			watched = append(watched, f)
+		} else {
+			translations = append(translations, f)
+		}
+	}
+	return translations, watched
+}
+
+func matchesAny(file string, patterns []string) bool {
+	for _, p := range patterns {
+		// Patterns are validated when parsed, so Match cannot fail here.
+		if ok, _ := doublestar.Match(p, file); ok {
+			return true
+		}
+	}
+	return false
+}
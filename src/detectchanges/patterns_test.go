@@ -0,0 +1,144 @@
+package detectchanges
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePatterns(t *testing.T) {
+	got, err := parsePatterns("locales/en/**/*.json\n\n  locales/en.yaml  \n!locales/en/fixtures/**\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := patternSet{
+		Include: []string{"locales/en/**/*.json", "locales/en.yaml"},
+		Exclude: []string{"locales/en/fixtures/**"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	for raw, wantErr := range map[string]string{
+		"locales/[en.json":     "invalid translation path pattern",
+		"!locales/fixtures/**": "no translation path patterns to match",
+		"\n\n":                 "no translation path patterns to match",
+	} {
+		if _, err := parsePatterns(raw); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%q: expected error containing %q, got %v", raw, wantErr, err)
+		}
+	}
+}
+
+func TestParsePatterns_Anchored(t *testing.T) {
+	got, err := parsePatterns("./locales/en/**/*.json\n!./locales/en/fixtures/**\n:(glob)i18n/en.yml\n:(glob,exclude)i18n/tmp/**\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := patternSet{
+		Include: []string{"locales/en/**/*.json", "i18n/en.yml"},
+		Exclude: []string{"locales/en/fixtures/**", "i18n/tmp/**"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	for raw, wantErr := range map[string]string{
+		":(glob":               "unterminated pathspec magic",
+		":(icase)locales/en/*": "unsupported pathspec magic",
+	} {
+		if _, err := parsePatterns(raw); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%q: expected error containing %q, got %v", raw, wantErr, err)
+		}
+	}
+}
+
+func TestPatternSetMatch(t *testing.T) {
+	set := patternSet{
+		Include: []string{"locales/en/**/*.json", "i18n/en.yml", "web/en/**/*.{json,yaml}", `apps/\[legacy\] dir/en.json`, `\#i18n/en.json`},
+		Exclude: []string{"locales/en/fixtures/**"},
+	}
+
+	for file, want := range map[string]bool{
+		"locales/en/app.json":             true,
+		"locales/en/pages/home.json":      true,
+		"locales/en/fixtures/sample.json": false,
+		"locales/fr/app.json":             false,
+		"i18n/en.yml":                     true,
+		"i18n/fr.yml":                     false,
+		"web/en/app.yaml":                 true,
+		"web/en/app.yml":                  false,
+		"apps/[legacy] dir/en.json":       true,
+		"apps/l dir/en.json":              false,
+		"#i18n/en.json":                   true,
+	} {
+		if got := set.match(file); got != want {
+			t.Fatalf("%s: expected %v, got %v", file, want, got)
+		}
+	}
+}
+
+func TestReadPatterns(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "paths.txt")
+	if err := os.WriteFile(p, []byte("locales/en.json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readPatterns(p)
+	if err != nil || !reflect.DeepEqual(got.Include, []string{"locales/en.json"}) {
+		t.Fatalf("unexpected result %+v (%v)", got, err)
+	}
+
+	if _, err := readPatterns(p + ".missing"); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
+
+func TestReadIgnorePatterns(t *testing.T) {
+	if got, err := readIgnorePatterns(""); err != nil || got != nil {
+		t.Fatalf("expected no patterns, got %v (%v)", got, err)
+	}
+
+	p := filepath.Join(t.TempDir(), "paths.ignore.txt")
+	if err := os.WriteFile(p, []byte("locales/vendor\n\n!locales/vendor/**\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readIgnorePatterns(p)
+	if err != nil || !reflect.DeepEqual(got, []string{"locales/vendor", "locales/vendor/**"}) {
+		t.Fatalf("unexpected result %v (%v)", got, err)
+	}
+
+	if err := os.WriteFile(p, []byte("./locales/vendor\n:(glob)locales/tmp/**\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = readIgnorePatterns(p)
+	if err != nil || !reflect.DeepEqual(got, []string{"locales/vendor", "locales/tmp/**"}) {
+		t.Fatalf("unexpected result %v (%v)", got, err)
+	}
+
+	if err := os.WriteFile(p, []byte("locales/[en\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readIgnorePatterns(p); err == nil || !strings.Contains(err.Error(), "invalid ignored path pattern") {
+		t.Fatalf("expected pattern error, got %v", err)
+	}
+
+	if _, err := readIgnorePatterns(p + ".missing"); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
+
+func TestSplitWatched(t *testing.T) {
+	translations, watched := splitWatched(
+		[]string{"locales/en.json", "lokalise.yml", "config/i18n.web.ts", "config/app.ts"},
+		[]string{"lokalise.yml", "config/i18n.*.ts"},
+	)
+	if want := []string{"locales/en.json", "config/app.ts"}; !reflect.DeepEqual(translations, want) {
+		t.Fatalf("expected translations %v, got %v", want, translations)
+	}
+	if want := []string{"lokalise.yml", "config/i18n.web.ts"}; !reflect.DeepEqual(watched, want) {
+		t.Fatalf("expected watched %v, got %v", want, watched)
+	}
+}
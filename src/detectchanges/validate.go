@@ -0,0 +1,128 @@
+package detectchanges
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/pathnorm"
+	"lokalise-push-action/internal/pushstate"
+)
+
+// defaultPathsFile is where storetranslationpaths writes the watched patterns.
+const defaultPathsFile = ".git/lokalise-action/paths.txt"
+
+// config describes the commit range to inspect and the patterns to filter by.
+type config struct {
+	PathsFile  string   // newline-separated patterns, "!" lines exclude
+	IgnoreFile string   // optional newline-separated exclusion patterns
+	Watch      []string // WATCH_PATTERNS globs; matching files trigger a full push
+	BaseSHA    string   // explicit base commit; skips base detection when set
+	SHA        string   // head commit, defaults to HEAD
+	EventName  string   // GITHUB_EVENT_NAME
+	BaseRef    string   // GITHUB_BASE_REF, set for pull request events
+	EventPath  string   // GITHUB_EVENT_PATH, used to read "before" on push events
+	Remote     string
+	TempDir    string // RUNNER_TEMP, where the changed files list is written
+
+	// SinceLastPush diffs against the last pushed commit of ProjectID
+	// recorded in the push state under CacheDir.
+	SinceLastPush bool
+	CacheDir      string // LOKALISE_CACHE_DIR
+	ProjectID     string // first LOKALISE_PROJECT_ID entry
+}
+
+// validateEnvironment reads the configuration from the environment, reporting
+// every invalid variable at once.
+func validateEnvironment() (config, error) {
+	pathsFile := strings.TrimSpace(os.Getenv("PATHS_FILE"))
+	if pathsFile == "" {
+		pathsFile = defaultPathsFile
+	}
+
+	ignoreFile := strings.TrimSpace(os.Getenv("PATHS_IGNORE_FILE"))
+	if ignoreFile != "" {
+		ignoreFile = filepath.Clean(ignoreFile)
+	}
+
+	var errs []error
+
+	watch, err := envconf.ParseWatchPatternsEnv(pathnorm.EnsureRepoRelativePattern)
+	errs = append(errs, err)
+
+	baseSHA, err := parseRevEnv("BASE_SHA")
+	errs = append(errs, err)
+
+	sha, err := parseRevEnv("SHA")
+	errs = append(errs, err)
+	if sha == "" {
+		sha = "HEAD"
+	}
+
+	baseRef, err := parseRevEnv("GITHUB_BASE_REF")
+	errs = append(errs, err)
+
+	sinceLastPush, err := envconf.ParseBoolEnv("SINCE_LAST_PUSH")
+	errs = append(errs, err)
+
+	var cacheDir, projectID string
+	if sinceLastPush {
+		cacheDir, projectID, err = parseLastPushEnv()
+		errs = append(errs, err)
+	}
+
+	if err := envconf.Join(errs); err != nil {
+		return config{}, err
+	}
+
+	return config{
+		PathsFile:  filepath.Clean(pathsFile),
+		IgnoreFile: ignoreFile,
+		Watch:      watch,
+		BaseSHA:    baseSHA,
+		SHA:        sha,
+		EventName:  strings.TrimSpace(os.Getenv("GITHUB_EVENT_NAME")),
+		BaseRef:    baseRef,
+		EventPath:  strings.TrimSpace(os.Getenv("GITHUB_EVENT_PATH")),
+		Remote:     "origin",
+		TempDir:    strings.TrimSpace(os.Getenv("RUNNER_TEMP")),
+
+		SinceLastPush: sinceLastPush,
+		CacheDir:      cacheDir,
+		ProjectID:     projectID,
+	}, nil
+}
+
+// parseLastPushEnv reads the push state cache and the project whose last
+// push is the base. With mirrors, the first (primary) project is used.
+func parseLastPushEnv() (string, string, error) {
+	cacheDir := strings.TrimSpace(os.Getenv("LOKALISE_CACHE_DIR"))
+	if cacheDir == "" {
+		return "", "", fmt.Errorf("SINCE_LAST_PUSH requires the push state cache (LOKALISE_CACHE_DIR)")
+	}
+
+	raw, err := envconf.EnvOrFile("LOKALISE_PROJECT_ID")
+	if err != nil {
+		return "", "", err
+	}
+	projectID, _ := envconf.SplitProjectIDs(raw)
+	if projectID == "" {
+		return "", "", fmt.Errorf("SINCE_LAST_PUSH requires the project ID (LOKALISE_PROJECT_ID)")
+	}
+	if _, err := pushstate.Dir(cacheDir, projectID); err != nil {
+		return "", "", err
+	}
+	return cacheDir, projectID, nil
+}
+
+// parseRevEnv reads an optional git revision. Values starting with "-" are
+// rejected so they cannot be mistaken for git options.
+func parseRevEnv(key string) (string, error) {
+	rev := strings.TrimSpace(os.Getenv(key))
+	if strings.HasPrefix(rev, "-") || strings.ContainsAny(rev, " \t\r\n") {
+		return "", fmt.Errorf("invalid %s: %q is not a git revision", key, rev)
+	}
+	return rev, nil
+}
@@ -0,0 +1,151 @@
+package detectchanges
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func setBaseEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("PATHS_FILE", "")
+	t.Setenv("PATHS_IGNORE_FILE", "")
+	t.Setenv("WATCH_PATTERNS", "")
+	t.Setenv("BASE_SHA", "")
+	t.Setenv("SHA", "")
+	t.Setenv("GITHUB_EVENT_NAME", "")
+	t.Setenv("GITHUB_BASE_REF", "")
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	t.Setenv("SINCE_LAST_PUSH", "")
+	t.Setenv("LOKALISE_CACHE_DIR", "")
+	t.Setenv("LOKALISE_PROJECT_ID", "")
+	t.Setenv("LOKALISE_PROJECT_ID_FILE", "")
+}
+
+func TestValidateEnvironment_Defaults(t *testing.T) {
+	setBaseEnv(t)
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := config{PathsFile: ".git/lokalise-action/paths.txt", SHA: "HEAD", Remote: "origin"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestValidateEnvironment_FromEnv(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("PATHS_FILE", " custom/paths.txt ")
+	t.Setenv("PATHS_IGNORE_FILE", " custom//paths.ignore.txt ")
+	t.Setenv("WATCH_PATTERNS", "lokalise.yml\n\n ./config/i18n.*.ts \n, docs/{a\\,b}.md")
+	t.Setenv("BASE_SHA", " abc123 ")
+	t.Setenv("SHA", "def456")
+	t.Setenv("GITHUB_EVENT_NAME", "pull_request")
+	t.Setenv("GITHUB_BASE_REF", "main")
+	t.Setenv("GITHUB_EVENT_PATH", "/tmp/event.json")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := config{
+		PathsFile:  "custom/paths.txt",
+		IgnoreFile: "custom/paths.ignore.txt",
+		Watch:      []string{"lokalise.yml", "config/i18n.*.ts", "docs/{a,b}.md"},
+		BaseSHA:    "abc123",
+		SHA:        "def456",
+		EventName:  "pull_request",
+		BaseRef:    "main",
+		EventPath:  "/tmp/event.json",
+		Remote:     "origin",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestValidateEnvironment_InvalidRevisions(t *testing.T) {
+	for _, key := range []string{"BASE_SHA", "SHA", "GITHUB_BASE_REF"} {
+		for _, value := range []string{"--upload-pack=evil", "main feature"} {
+			setBaseEnv(t)
+			t.Setenv(key, value)
+			if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "invalid "+key) {
+				t.Fatalf("%s=%q: expected error, got %v", key, value, err)
+			}
+		}
+	}
+}
+
+func TestValidateEnvironment_InvalidWatchPattern(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("WATCH_PATTERNS", "config/[i18n.ts")
+	if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "invalid WATCH_PATTERNS") {
+		t.Fatalf("expected error, got %v", err)
+	}
+}
+
+func TestValidateEnvironment_ReportsAllErrors(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("BASE_SHA", "--output=x")
+	t.Setenv("SHA", "a b")
+
+	_, err := validateEnvironment()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"2 configuration problems", "invalid BASE_SHA", "invalid SHA"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %q", want, err.Error())
+		}
+	}
+}
+
+func TestValidateEnvironment_SinceLastPush(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("SINCE_LAST_PUSH", "true")
+	t.Setenv("LOKALISE_CACHE_DIR", " /tmp/lokalise-cache ")
+	t.Setenv("LOKALISE_PROJECT_ID", "\n111.abc, 222.def")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.SinceLastPush || got.CacheDir != "/tmp/lokalise-cache" || got.ProjectID != "111.abc" {
+		t.Fatalf("unexpected last push settings %+v", got)
+	}
+
+	// The cache and project are only read in this mode.
+	t.Setenv("SINCE_LAST_PUSH", "false")
+	if got, err := validateEnvironment(); err != nil || got.CacheDir != "" || got.ProjectID != "" {
+		t.Fatalf("expected no last push settings, got %+v (%v)", got, err)
+	}
+}
+
+func TestValidateEnvironment_SinceLastPushErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		since   string
+		cache   string
+		project string
+		wantErr string
+	}{
+		{"invalid SINCE_LAST_PUSH", "sometimes", "", "", "invalid SINCE_LAST_PUSH"},
+		{"missing cache", "true", "", "111.abc", "requires the push state cache (LOKALISE_CACHE_DIR)"},
+		{"missing project", "true", "/cache", " , ", "requires the project ID (LOKALISE_PROJECT_ID)"},
+		{"invalid project", "true", "/cache", "../x", `invalid project ID "../x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("SINCE_LAST_PUSH", tt.since)
+			t.Setenv("LOKALISE_CACHE_DIR", tt.cache)
+			t.Setenv("LOKALISE_PROJECT_ID", tt.project)
+			if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -1,11 +0,0 @@
-module find_all_files
-
-go 1.26
-
-toolchain go1.26.4
-
-require github.com/bodrovis/lokalise-actions-common/v2 v2.15.0
-
-require github.com/bmatcuk/doublestar/v4 v4.10.0
-
-require go.yaml.in/yaml/v4 v4.0.0-rc.6 // indirect
@@ -0,0 +1,64 @@
+package findallfiles
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// changedFilesFunc lists files under roots that differ from ref, relative to the
+// current directory.
+type changedFilesFunc func(ref string, roots []string) ([]string, error)
+
+// listChangedFiles runs "git diff" against ref in the current directory.
+func listChangedFiles(ref string, roots []string) ([]string, error) {
+	return gitDiffNames("", ref, roots)
+}
+
+// gitDiffNames returns the files added or modified in the working tree since ref.
+// Renames are reported as their new path and deleted files are left out, since
+// there is nothing to upload for them.
+func gitDiffNames(dir, ref string, roots []string) ([]string, error) {
+	args := append([]string{"diff", "--name-only", "-z", "--relative", "--no-renames", "--diff-filter=d", ref, "--"}, roots...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff against %q failed: %w: %s", ref, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var files []string
+	for entry := range strings.SplitSeq(string(out), "\x00") {
+		if entry != "" {
+			files = append(files, entry)
+		}
+	}
+	return files, nil
+}
+
+// keepChanged returns the files that git reports as changed since ref,
+// preserving their order.
+func keepChanged(files []string, ref string, roots []string, list changedFilesFunc) ([]string, error) {
+	changed, err := list(ref, roots)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]struct{}, len(changed))
+	for _, f := range changed {
+		set[f] = struct{}{}
+	}
+
+	kept := files[:0:0]
+	for _, f := range files {
+		if _, ok := set[f]; ok {
+			kept = append(kept, f)
+		}
+	}
+	return kept, nil
+}
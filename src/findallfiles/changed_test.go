@@ -0,0 +1,92 @@
+package findallfiles
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGitDiffNames(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		full := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("locales/en/app.json", "{}")
+	writeFile("locales/en/old.json", "{}")
+	writeFile("locales/en/gone.json", "{}")
+	writeFile("docs/readme.md", "# docs")
+	runGit("init", "-q")
+	runGit("add", ".")
+	runGit("commit", "-q", "-m", "base")
+
+	writeFile("locales/en/app.json", `{"a":"b"}`)
+	writeFile("docs/readme.md", "# changed")
+	runGit("mv", "locales/en/old.json", "locales/en/new.json")
+	runGit("rm", "-q", "locales/en/gone.json")
+	runGit("commit", "-q", "-m", "change")
+	writeFile("locales/en/app.json", `{"a":"c"}`) // uncommitted edits count too
+
+	got, err := gitDiffNames(dir, "HEAD~1", []string{"locales"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"locales/en/app.json", "locales/en/new.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if _, err := gitDiffNames(dir, "no-such-ref", []string{"locales"}); err == nil || !strings.Contains(err.Error(), `git diff against "no-such-ref" failed`) {
+		t.Fatalf("expected unknown ref error, got %v", err)
+	}
+}
+
+func TestKeepChanged(t *testing.T) {
+	t.Parallel()
+
+	files := []string{"locales/en/a.json", "locales/en/b.json", "locales/en/c.json"}
+	list := func(ref string, roots []string) ([]string, error) {
+		if ref != "main" || !reflect.DeepEqual(roots, []string{"locales"}) {
+			t.Fatalf("unexpected call: ref=%q roots=%v", ref, roots)
+		}
+		return []string{"locales/en/c.json", "locales/en/a.json", "locales/fr/a.json"}, nil
+	}
+
+	got, err := keepChanged(files, "main", []string{"locales"}, list)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"locales/en/a.json", "locales/en/c.json"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	_, err = keepChanged(files, "main", nil, func(string, []string) ([]string, error) {
+		return nil, errors.New("boom")
+	})
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("expected lister error, got %v", err)
+	}
+}
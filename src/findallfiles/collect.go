@@ -0,0 +1,322 @@
+package findallfiles
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"lokalise-push-action/internal/namepattern"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+// fileCollector accumulates unique file paths and normalizes them to forward slashes
+// to keep output deterministic across operating systems. It also remembers which
+// root first produced each file so overlapping roots can be reported.
+type fileCollector struct {
+	seen     map[string]string // path -> root that added it first
+	files    []string
+	root     string
+	overlaps map[rootPair][]string
+}
+
+func newFileCollector() *fileCollector {
+	return &fileCollector{
+		seen:     make(map[string]string),
+		overlaps: make(map[rootPair][]string),
+	}
+}
+
+// forRoot attributes subsequently added files to root.
+func (c *fileCollector) forRoot(root string) {
+	c.root = root
+}
+
+func (c *fileCollector) add(path string) {
+	path = filepath.ToSlash(path)
+	if first, ok := c.seen[path]; ok {
+		if first != c.root {
+			pair := rootPair{first, c.root}
+			c.overlaps[pair] = append(c.overlaps[pair], path)
+		}
+		return
+	}
+	c.seen[path] = c.root
+	c.files = append(c.files, path)
+}
+
+func (c *fileCollector) sorted() []string {
+	out := append([]string(nil), c.files...)
+	sort.Strings(out)
+	return out
+}
+
+// rootedRule is a NAME_PATTERN rule whose globs are anchored at a translations
+// root, so they match repo-relative paths.
+type rootedRule namepattern.Rule
+
+// rootRule joins the rule's globs with root and checks their syntax.
+func rootRule(r namepattern.Rule, root string) (rootedRule, error) {
+	out := rootedRule{Pattern: joinRootPattern(root, r.Pattern)}
+	if !doublestar.ValidatePattern(out.Pattern) {
+		return rootedRule{}, fmt.Errorf("apply name pattern %q: %w", out.Pattern, doublestar.ErrBadPattern)
+	}
+
+	for _, p := range r.Excludes {
+		exclude := joinRootPattern(root, p)
+		if !doublestar.ValidatePattern(exclude) {
+			return rootedRule{}, fmt.Errorf("apply name pattern exclusion %q: %w", exclude, doublestar.ErrBadPattern)
+		}
+		out.Excludes = append(out.Excludes, exclude)
+	}
+	return out, nil
+}
+
+// match reports whether the slash-separated path matches the pattern and none of the exclusions.
+func (r rootedRule) match(path string) bool {
+	// Roots are in NFC, so the path is too before matching.
+	path = pathnorm.ComposeNFC(path)
+	// Patterns are validated by rootRule, so Match cannot fail here.
+	ok, _ := doublestar.Match(r.Pattern, path)
+	return ok && !matchesAnyPattern(path, r.Excludes)
+}
+
+// joinRootPattern anchors a NAME_PATTERN glob at root as a repo-relative slash path.
+func joinRootPattern(root, pattern string) string {
+	return strings.TrimPrefix(filepath.ToSlash(filepath.Join(root, pattern)), "./")
+}
+
+// collectFilesByPattern applies NAME_PATTERN relative to the given root.
+// The root is walked according to the symlink policy and every file whose
+// repo-relative path matches the pattern, and none of its exclusions, is collected.
+func collectFilesByPattern(root string, r namepattern.Rule, opts walkOptions, add func(string)) error {
+	rule, err := rootRule(r, root)
+	if err != nil {
+		return err
+	}
+
+	// MAX_DEPTH and vendor directory skipping only apply to nested layouts.
+	opts.MaxDepth = 0
+	opts.SkipVendorDirs = false
+
+	return walkFiles(root, opts, func(fp string) {
+		if rule.match(filepath.ToSlash(fp)) {
+			add(fp)
+		}
+	})
+}
+
+// collectFilesByRegex walks root and collects files whose path relative to root
+// matches NAME_REGEX and whose captured language is wanted.
+func collectFilesByRegex(root string, cfg config, skipLangs map[string]struct{}, opts walkOptions, add func(string)) error {
+	// MAX_DEPTH and vendor directory skipping only apply to nested layouts.
+	opts.MaxDepth = 0
+	opts.SkipVendorDirs = false
+
+	return walkFiles(root, opts, func(fp string) {
+		// Visited paths are joined onto root, so Rel cannot fail here.
+		rel, _ := filepath.Rel(root, fp)
+		if lang, ok := regexLang(cfg.NameRegex, pathnorm.ComposeNFC(filepath.ToSlash(rel))); ok && langWanted(lang, cfg, skipLangs) {
+			add(fp)
+		}
+	})
+}
+
+// regexLang matches rel against NAME_REGEX and returns the captured language.
+func regexLang(re *regexp.Regexp, rel string) (string, bool) {
+	m := re.FindStringSubmatch(rel)
+	if m == nil {
+		return "", false
+	}
+	lang := m[re.SubexpIndex("lang")]
+	return lang, lang != ""
+}
+
+// collectFlatFiles checks for exact flat-layout file names:
+//
+//	<root>/<baseLang>.<ext>
+//
+// Missing files are ignored. Unexpected stat errors are returned.
+// Symlinked files are only collected when following symlinks.
+func collectFlatFiles(root, baseLang string, fileExts []string, opts walkOptions, add func(string)) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading directory %q: %w", root, err)
+	}
+
+	for _, entry := range entries {
+		mode, ok, err := resolveEntry(filepath.Join(root, entry.Name()), entry, opts)
+		if err != nil {
+			return err
+		}
+		if !ok || !mode.IsRegular() {
+			continue
+		}
+
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if ext == "" {
+			continue
+		}
+
+		base := strings.TrimSuffix(name, ext)
+		if base != baseLang {
+			continue
+		}
+
+		if hasMatchingExtension(name, fileExts) {
+			add(filepath.Join(root, name))
+		}
+	}
+
+	return nil
+}
+
+// collectNestedFiles walks the nested layout directory:
+//
+//	<root>/<baseLang>/...
+//
+// Missing language directories are treated as "no files found", not as errors.
+// A symlinked language directory is only entered when following symlinks.
+func collectNestedFiles(root, baseLang string, fileExts []string, opts walkOptions, add func(string)) error {
+	targetDir := filepath.Join(root, baseLang)
+
+	info, err := os.Lstat(targetDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error accessing directory %q: %w", targetDir, err)
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 && !opts.FollowSymlinks {
+		return nil
+	}
+
+	return walkFiles(targetDir, opts, func(fp string) {
+		if hasMatchingExtension(filepath.Base(fp), fileExts) {
+			add(fp)
+		}
+	})
+}
+
+// collectFlatFilesAllLangs collects every flat-layout file directly under root:
+//
+//	<root>/<lang>.<ext>
+//
+// Languages listed in skipLangs are ignored. Missing roots are ignored.
+func collectFlatFilesAllLangs(root string, fileExts []string, skipLangs map[string]struct{}, opts walkOptions, add func(string)) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading directory %q: %w", root, err)
+	}
+
+	for _, entry := range entries {
+		mode, ok, err := resolveEntry(filepath.Join(root, entry.Name()), entry, opts)
+		if err != nil {
+			return err
+		}
+		if !ok || !mode.IsRegular() {
+			continue
+		}
+
+		name := entry.Name()
+		if _, skip := skipLangs[strings.TrimSuffix(name, filepath.Ext(name))]; skip {
+			continue
+		}
+		if hasMatchingExtension(name, fileExts) {
+			add(filepath.Join(root, name))
+		}
+	}
+
+	return nil
+}
+
+// collectNestedFilesAllLangs walks every language directory under root:
+//
+//	<root>/<lang>/...
+//
+// Languages listed in skipLangs are ignored, and so are vendor and
+// dot-directories unless opts allow them. Missing roots are ignored.
+func collectNestedFilesAllLangs(root string, fileExts []string, skipLangs map[string]struct{}, opts walkOptions, add func(string)) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading directory %q: %w", root, err)
+	}
+
+	for _, entry := range entries {
+		mode, ok, err := resolveEntry(filepath.Join(root, entry.Name()), entry, opts)
+		if err != nil {
+			return err
+		}
+		if !ok || !mode.IsDir() || opts.skipDir(entry.Name()) {
+			continue
+		}
+		if _, skip := skipLangs[entry.Name()]; skip {
+			continue
+		}
+		if err := collectNestedFiles(root, entry.Name(), fileExts, opts, add); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// excludeFiles drops files matching any of the doublestar patterns and
+// reports the removed ones. Paths are matched in slash form.
+func excludeFiles(files, patterns []string) ([]string, []skippedFile) {
+	kept := files[:0:0]
+	var excluded []skippedFile
+	for _, f := range files {
+		if p, ok := firstMatchingPattern(f, patterns); ok {
+			excluded = append(excluded, skippedFile{
+				File:   f,
+				Reason: fmt.Sprintf("Not pushed: excluded by exclude_patterns entry %q", p),
+			})
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept, excluded
+}
+
+func matchesAnyPattern(path string, patterns []string) bool {
+	_, ok := firstMatchingPattern(path, patterns)
+	return ok
+}
+
+// firstMatchingPattern returns the first pattern matching path.
+func firstMatchingPattern(path string, patterns []string) (string, bool) {
+	for _, p := range patterns {
+		// Patterns are validated up front, so Match cannot fail here.
+		if ok, _ := doublestar.Match(p, path); ok {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// hasMatchingExtension reports whether the file name ends with one of the allowed extensions.
+// Comparison is case-insensitive.
+func hasMatchingExtension(name string, fileExts []string) bool {
+	for _, ext := range fileExts {
+		if strings.EqualFold(filepath.Ext(name), "."+ext) {
+			return true
+		}
+	}
+	return false
+}
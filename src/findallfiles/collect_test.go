@@ -0,0 +1,154 @@
+package findallfiles
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/namepattern"
+)
+
+func TestHasMatchingExtension(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		fileExts []string
+		want     bool
+	}{
+		{
+			name:     "matches lowercase extension",
+			filename: "file.json",
+			fileExts: []string{"json"},
+			want:     true,
+		},
+		{
+			name:     "matches uppercase extension case-insensitively",
+			filename: "file.JSON",
+			fileExts: []string{"json"},
+			want:     true,
+		},
+		{
+			name:     "matches one of multiple extensions",
+			filename: "file.yaml",
+			fileExts: []string{"json", "yaml"},
+			want:     true,
+		},
+		{
+			name:     "no extension does not match",
+			filename: "file",
+			fileExts: []string{"json"},
+			want:     false,
+		},
+		{
+			name:     "different extension does not match",
+			filename: "file.txt",
+			fileExts: []string{"json"},
+			want:     false,
+		},
+		{
+			name:     "matches extension in multi-dot filename",
+			filename: "archive.tar.gz",
+			fileExts: []string{"gz"},
+			want:     true,
+		},
+		{
+			name:     "does not match non-last extension in multi-dot filename",
+			filename: "archive.tar.gz",
+			fileExts: []string{"tar"},
+			want:     false,
+		},
+		{
+			name:     "extension list without dot still matches",
+			filename: "file.json",
+			fileExts: []string{"json"},
+			want:     true,
+		},
+		{
+			name:     "empty extension list does not match anything",
+			filename: "file.json",
+			fileExts: []string{},
+			want:     false,
+		},
+		{
+			name:     "extension list with dot does not match (invalid input)",
+			filename: "file.json",
+			fileExts: []string{".json"},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := hasMatchingExtension(tt.filename, tt.fileExts)
+			if got != tt.want {
+				t.Fatalf("hasMatchingExtension(%q, %v) = %v, want %v", tt.filename, tt.fileExts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludeFiles(t *testing.T) {
+	files := []string{
+		"locales/en.json",
+		"locales/fixtures/en.json",
+		"src/test/fixtures/deep/en.json",
+		"vendor/lib/locales/en.json",
+	}
+
+	got, excluded := excludeFiles(files, []string{"**/fixtures/**", "vendor/**"})
+	if want := []string{"locales/en.json"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if len(excluded) != 3 {
+		t.Fatalf("expected 3 excluded files, got %v", excluded)
+	}
+	want := skippedFile{File: "locales/fixtures/en.json", Reason: `Not pushed: excluded by exclude_patterns entry "**/fixtures/**"`}
+	if excluded[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, excluded[0])
+	}
+	if len(files) != 4 || files[1] != "locales/fixtures/en.json" {
+		t.Fatalf("input slice must not be modified: %v", files)
+	}
+
+	got, excluded = excludeFiles(files, []string{"*.json"})
+	if !reflect.DeepEqual(got, files) || len(excluded) != 0 {
+		t.Fatalf("single-segment pattern must not match nested paths, got %v (%v excluded)", got, excluded)
+	}
+}
+
+func TestRootRule(t *testing.T) {
+	rule, err := rootRule(namepattern.Rule{Pattern: "**/*.yaml", Excludes: []string{"**/*.generated.yaml"}}, "./config")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := rootedRule{Pattern: "config/**/*.yaml", Excludes: []string{"config/**/*.generated.yaml"}}
+	if !reflect.DeepEqual(rule, want) {
+		t.Fatalf("expected %v, got %v", want, rule)
+	}
+
+	for path, want := range map[string]bool{
+		"config/en.yaml":               true,
+		"config/sub/fr.yaml":           true,
+		"config/en.generated.yaml":     false,
+		"config/sub/fr.generated.yaml": false,
+		"other/en.yaml":                false,
+	} {
+		if got := rule.match(path); got != want {
+			t.Fatalf("%s: expected %v, got %v", path, want, got)
+		}
+	}
+
+	decomposed, err := rootRule(namepattern.Rule{Pattern: "*.yaml"}, "caf\u00e9")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decomposed.match("cafe\u0301/en.yaml") {
+		t.Fatal("expected a decomposed path to match its composed root")
+	}
+
+	if _, err := rootRule(namepattern.Rule{Pattern: "*.yaml", Excludes: []string{"[bad"}}, "config"); err == nil || !strings.Contains(err.Error(), "apply name pattern exclusion") {
+		t.Fatalf("expected exclusion syntax error, got %v", err)
+	}
+}
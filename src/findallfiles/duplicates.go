@@ -0,0 +1,76 @@
+package findallfiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"lokalise-push-action/internal/logging"
+)
+
+// emptySHA256 is the digest of empty content. Empty stub files are not reported
+// as duplicates of each other.
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// duplicateGroup lists files sharing identical content.
+type duplicateGroup struct {
+	SHA256 string   `json:"sha256"`
+	Files  []string `json:"files"`
+}
+
+// writeDuplicates reports groups of collected files with identical content when
+// DETECT_DUPLICATES is enabled: one warning per group, and DUPLICATE_FILES as
+// a JSON array of groups. Nothing is written without duplicates.
+func writeDuplicates(cfg config, files []string, writeOutput func(key, value string) bool) error {
+	if !cfg.DetectDuplicates || len(files) < 2 {
+		return nil
+	}
+
+	hashes, err := hashFiles(files)
+	if err != nil {
+		return err
+	}
+	groups := findDuplicates(files, hashes)
+	if len(groups) == 0 {
+		return nil
+	}
+
+	for _, g := range groups {
+		logs.WarnAt(logging.Location{Title: "Duplicate translation files"},
+			"%d files have identical content: %s", len(g.Files), strings.Join(g.Files, ", "))
+	}
+
+	groupsJSON, err := json.Marshal(groups)
+	if err != nil {
+		return fmt.Errorf("cannot encode DUPLICATE_FILES: %w", err)
+	}
+	if !writeOutput("DUPLICATE_FILES", string(groupsJSON)) {
+		return fmt.Errorf("cannot write DUPLICATE_FILES to GITHUB_OUTPUT")
+	}
+	return nil
+}
+
+// findDuplicates groups files by digest, keeping groups with more than one file.
+// Groups are ordered by their first file and list files in input order.
+func findDuplicates(files []string, hashes map[string]string) []duplicateGroup {
+	byHash := make(map[string][]string)
+	var order []string
+	for _, f := range files {
+		sum := hashes[f]
+		if sum == emptySHA256 {
+			continue
+		}
+		if _, ok := byHash[sum]; !ok {
+			order = append(order, sum)
+		}
+		byHash[sum] = append(byHash[sum], f)
+	}
+
+	var groups []duplicateGroup
+	for _, sum := range order {
+		if len(byHash[sum]) > 1 {
+			groups = append(groups, duplicateGroup{SHA256: sum, Files: byHash[sum]})
+		}
+	}
+	return groups
+}
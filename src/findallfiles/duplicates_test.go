@@ -0,0 +1,88 @@
+package findallfiles
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	files := []string{"a.json", "b.json", "c.json", "d.json", "e.json", "f.json"}
+	hashes := map[string]string{
+		"a.json": "h1",
+		"b.json": "h2",
+		"c.json": "h1",
+		"d.json": emptySHA256,
+		"e.json": emptySHA256,
+		"f.json": "h2",
+	}
+
+	want := []duplicateGroup{
+		{SHA256: "h1", Files: []string{"a.json", "c.json"}},
+		{SHA256: "h2", Files: []string{"b.json", "f.json"}},
+	}
+	if got := findDuplicates(files, hashes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got := findDuplicates(files[:2], hashes); got != nil {
+		t.Fatalf("expected no duplicates, got %v", got)
+	}
+}
+
+func TestWriteDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for name, content := range map[string]string{
+		"pkg-a/en.json": `{"hello":"Hello"}`,
+		"pkg-b/en.json": `{"hello":"Hello"}`,
+		"pkg-c/en.json": `{"bye":"Bye"}`,
+	} {
+		fp := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fp), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fp, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"pkg-a/en.json", "pkg-b/en.json", "pkg-c/en.json"} {
+		files = append(files, filepath.ToSlash(filepath.Join(dir, name)))
+	}
+
+	writes := make(map[string]string)
+	write := func(key, value string) bool {
+		writes[key] = value
+		return true
+	}
+
+	out := captureLogs(t)
+	if err := writeDuplicates(config{}, files, write); err != nil || out.Len() != 0 || len(writes) != 0 {
+		t.Fatalf("expected nothing when disabled, got %q %v (%v)", out.String(), writes, err)
+	}
+
+	cfg := config{DetectDuplicates: true}
+	if err := writeDuplicates(cfg, files, write); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantWarning := "::warning title=Duplicate translation files::2 files have identical content: " + files[0] + ", " + files[1] + "\n"
+	if out.String() != wantWarning {
+		t.Fatalf("expected %q, got %q", wantWarning, out.String())
+	}
+	if !strings.HasPrefix(writes["DUPLICATE_FILES"], `[{"sha256":"`) || !strings.HasSuffix(writes["DUPLICATE_FILES"], `","files":["`+files[0]+`","`+files[1]+`"]}]`) {
+		t.Fatalf("unexpected DUPLICATE_FILES: %s", writes["DUPLICATE_FILES"])
+	}
+
+	out.Reset()
+	clear(writes)
+	if err := writeDuplicates(cfg, files[1:], write); err != nil || out.Len() != 0 || len(writes) != 0 {
+		t.Fatalf("expected nothing without duplicates, got %q %v (%v)", out.String(), writes, err)
+	}
+
+	if err := writeDuplicates(cfg, []string{files[0], filepath.Join(dir, "missing.json")}, write); err == nil || !strings.Contains(err.Error(), "cannot hash") {
+		t.Fatalf("expected hash error, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package findallfiles
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"lokalise-push-action/internal/logging"
+	"lokalise-push-action/internal/textenc"
+)
+
+// encodingIssue describes why a file is not plain UTF-8.
+type encodingIssue struct {
+	File    string
+	Line    int // 1-based line of the first invalid byte, 0 for the whole file
+	Message string
+}
+
+// writeEncodingWarnings logs a warning for every collected file that is not
+// valid UTF-8 or starts with a byte order mark, when CHECK_ENCODING is enabled. Such files routinely fail to import on Lokalise with unclear errors.
+func writeEncodingWarnings(cfg config, files []string) error {
+	if !cfg.CheckEncoding {
+		return nil
+	}
+
+	for _, f := range files {
+		issue, err := checkFileEncoding(f)
+		if err != nil {
+			return err
+		}
+		if issue == nil {
+			continue
+		}
+
+		logs.WarnAt(logging.Location{File: issue.File, Line: issue.Line, Title: "Invalid file encoding"}, "%s", issue.Message)
+	}
+	return nil
+}
+
+// checkFileEncoding reads f and reports its encoding problem, if any.
+func checkFileEncoding(f string) (*encodingIssue, error) {
+	data, err := os.ReadFile(filepath.FromSlash(f))
+	if err != nil {
+		return nil, fmt.Errorf("cannot check encoding of %q: %w", f, err)
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return &encodingIssue{File: f, Line: 1, Message: f + " starts with a UTF-8 byte order mark (BOM); save it as UTF-8 without BOM"}, nil
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return &encodingIssue{File: f, Message: f + " appears to be UTF-16 encoded; convert it to UTF-8"}, nil
+	}
+
+	if offset := textenc.InvalidUTF8Offset(data); offset >= 0 {
+		return &encodingIssue{File: f, Line: textenc.LineAt(data, offset), Message: fmt.Sprintf("%s is not valid UTF-8 (invalid byte at offset %d); convert it to UTF-8", f, offset)}, nil
+	}
+	return nil, nil
+}
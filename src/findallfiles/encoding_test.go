@@ -0,0 +1,81 @@
+package findallfiles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckFileEncoding(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		t.Helper()
+		fp := filepath.Join(dir, name)
+		if err := os.WriteFile(fp, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return filepath.ToSlash(fp)
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		wantLine int
+		wantMsg  string
+	}{
+		{name: "valid.json", data: []byte(`{"hello":"Привет, 世界"}`)},
+		{name: "empty.json", data: nil},
+		{name: "bom.json", data: []byte("\xEF\xBB\xBF{}"), wantLine: 1, wantMsg: "byte order mark"},
+		{name: "utf16.json", data: []byte("\xFF\xFE{\x00}\x00"), wantMsg: "UTF-16"},
+		{name: "latin1.json", data: []byte("{\n  \"cafe\": \"caf\xE9\"\n}"), wantLine: 2, wantMsg: "invalid byte at offset 16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := write(tt.name, tt.data)
+			issue, err := checkFileEncoding(f)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantMsg == "" {
+				if issue != nil {
+					t.Fatalf("expected no issue, got %+v", issue)
+				}
+				return
+			}
+			if issue == nil || issue.File != f || issue.Line != tt.wantLine || !strings.Contains(issue.Message, tt.wantMsg) {
+				t.Fatalf("expected issue at line %d containing %q, got %+v", tt.wantLine, tt.wantMsg, issue)
+			}
+		})
+	}
+
+	if _, err := checkFileEncoding(filepath.Join(dir, "missing.json")); err == nil || !strings.Contains(err.Error(), "cannot check encoding") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestWriteEncodingWarnings(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.ToSlash(filepath.Join(dir, "en.json"))
+	bad := filepath.ToSlash(filepath.Join(dir, "fr.json"))
+	if err := os.WriteFile(good, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte("\xEF\xBB\xBF{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureLogs(t)
+	if err := writeEncodingWarnings(config{}, []string{good, bad}); err != nil || out.Len() != 0 {
+		t.Fatalf("expected no output when disabled, got %q (%v)", out.String(), err)
+	}
+
+	if err := writeEncodingWarnings(config{CheckEncoding: true}, []string{good, bad}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "::warning file=" + strings.NewReplacer(":", "%3A", ",", "%2C").Replace(bad) + ",line=1,title=Invalid file encoding::" + bad + " starts with a UTF-8 byte order mark (BOM); save it as UTF-8 without BOM\n"
+	if out.String() != want {
+		t.Fatalf("expected %q, got %q", want, out.String())
+	}
+}
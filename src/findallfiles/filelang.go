@@ -0,0 +1,201 @@
+package findallfiles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fileLang infers the language of a collected file from the layout of the most
+// specific translations root containing it:
+//
+//	NAME_REGEX: the "lang" capture group
+//	flat:       <root>/<lang>.<ext>
+//	nested:     <root>/<lang>/...
+//
+// Files collected via NAME_PATTERN have no inferable language.
+func fileLang(cfg config, file string) (string, bool) {
+	root, rel, ok := fileRoot(file, cfg.Paths)
+	if !ok {
+		return "", false
+	}
+
+	switch {
+	case cfg.NameRegex != nil:
+		return regexLang(cfg.NameRegex, rel)
+	case cfg.nameRuleFor(root).Pattern != "":
+		return "", false
+	case cfg.flatNamingFor(root):
+		if strings.Contains(rel, "/") {
+			return "", false
+		}
+		lang := strings.TrimSuffix(rel, path.Ext(rel))
+		return lang, lang != ""
+	default:
+		lang, rest, ok := strings.Cut(rel, "/")
+		if !ok || lang == "" || rest == "" {
+			return "", false
+		}
+		return lang, true
+	}
+}
+
+// fileLangMapSuffix is appended to FILES_LIST_PATH to name the language map.
+const fileLangMapSuffix = ".langs.json"
+
+// writeFileLangMap writes the JSON object mapping each file whose language can
+// be inferred to that language, so the uploader can set lang_iso per file, and
+// emits its path as FILE_LANG_MAP_PATH. The map of a large repository would
+// not fit in the uploader's environment, so it is passed as a file: next to
+// FILES_LIST_PATH or, without a list, a new file under RUNNER_TEMP. Without a
+// list the map is emitted as FILE_LANG_MAP too. Nothing is written when no
+// language can be inferred.
+func writeFileLangMap(cfg config, files []string, writeOutput func(key, value string) bool) error {
+	langs := fileLangMap(cfg, files)
+	if len(langs) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(langs)
+	if err != nil {
+		return fmt.Errorf("cannot encode FILE_LANG_MAP: %w", err)
+	}
+
+	var path string
+	if cfg.FilesListPath != "" {
+		path = cfg.FilesListPath + fileLangMapSuffix
+		err = os.WriteFile(path, data, 0o644)
+	} else {
+		if !writeOutput("FILE_LANG_MAP", string(data)) {
+			return fmt.Errorf("cannot write FILE_LANG_MAP to GITHUB_OUTPUT")
+		}
+		path, err = writeTempFile(cfg.TempDir, "lokalise-file-langs-*.json", data)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write file language map: %w", err)
+	}
+
+	if !writeOutput("FILE_LANG_MAP_PATH", path) {
+		return fmt.Errorf("cannot write FILE_LANG_MAP_PATH to GITHUB_OUTPUT")
+	}
+	return nil
+}
+
+// writeTempFile writes data to a new file in dir (see os.CreateTemp) and
+// returns its path.
+func writeTempFile(dir, pattern string, data []byte) (string, error) {
+	f, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	return f.Name(), errors.Join(err, f.Close())
+}
+
+// fileLangMap maps every file whose language can be inferred to that language.
+func fileLangMap(cfg config, files []string) map[string]string {
+	langs := make(map[string]string)
+	for _, f := range files {
+		if lang, ok := fileLang(cfg, f); ok {
+			langs[f] = lang
+		}
+	}
+	return langs
+}
+
+// fileRoot returns the most specific root containing the slash-separated file
+// and the file path relative to it.
+func fileRoot(file string, roots []string) (string, string, bool) {
+	var root, rel string
+	found := false
+	for _, r := range roots {
+		if r == "" {
+			continue
+		}
+		r = filepath.ToSlash(r)
+		if fileRel, ok := relativeToRoot(r, file); ok && (!found || len(r) > len(root)) {
+			root, rel, found = r, fileRel, true
+		}
+	}
+	return root, rel, found
+}
+
+// detectLangs lists the languages present under the translation roots, whether
+// or not PUSH_ALL_LANGS or SKIP_LANGS would push them: language folders holding
+// translation files (nested layout), translation file names (flat layout), or
+// NAME_REGEX captures. Roots using NAME_PATTERN have no languages to detect.
+func detectLangs(cfg config) ([]string, error) {
+	found := make(map[string]struct{})
+	opts := walkOptions{
+		MaxDepth:       cfg.MaxDepth,
+		FollowSymlinks: cfg.FollowSymlinks,
+		SkipVendorDirs: !cfg.IncludeVendorDirs,
+	}
+
+	for _, root := range cfg.Paths {
+		if root == "" || (cfg.NameRegex == nil && cfg.nameRuleFor(root).Pattern != "") {
+			continue
+		}
+
+		if cfg.NameRegex != nil {
+			regexOpts := walkOptions{FollowSymlinks: cfg.FollowSymlinks}
+			err := walkFiles(root, regexOpts, func(fp string) {
+				rel, _ := filepath.Rel(root, fp)
+				if lang, ok := regexLang(cfg.NameRegex, filepath.ToSlash(rel)); ok {
+					found[lang] = struct{}{}
+				}
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("error reading directory %q: %w", root, err)
+		}
+
+		flat, fileExts := cfg.flatNamingFor(root), cfg.fileExtsFor(root)
+		for _, entry := range entries {
+			name := entry.Name()
+			mode, ok, err := resolveEntry(filepath.Join(root, name), entry, opts)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+
+			switch {
+			case flat:
+				if mode.IsRegular() && hasMatchingExtension(name, fileExts) {
+					found[strings.TrimSuffix(name, filepath.Ext(name))] = struct{}{}
+				}
+			case mode.IsDir() && !opts.skipDir(name):
+				hasFiles := false
+				if err := collectNestedFiles(root, name, fileExts, opts, func(string) { hasFiles = true }); err != nil {
+					return nil, err
+				}
+				if hasFiles {
+					found[name] = struct{}{}
+				}
+			}
+		}
+	}
+
+	langs := make([]string, 0, len(found))
+	for lang := range found {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs, nil
+}
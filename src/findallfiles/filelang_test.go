@@ -0,0 +1,116 @@
+package findallfiles
+
+import (
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"lokalise-push-action/internal/namepattern"
+)
+
+func TestFileLang(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config
+		file string
+		want string
+		ok   bool
+	}{
+		{name: "nested", cfg: config{Paths: []string{"locales"}}, file: "locales/fr/pages/home.json", want: "fr", ok: true},
+		{name: "flat", cfg: config{Paths: []string{"locales"}, FlatNaming: true}, file: "locales/pt_BR.json", want: "pt_BR", ok: true},
+		{name: "flat file in a subfolder", cfg: config{Paths: []string{"locales"}, FlatNaming: true}, file: "locales/sub/en.json", ok: false},
+		{name: "repo root", cfg: config{Paths: []string{"."}}, file: "de/app.json", want: "de", ok: true},
+		{
+			name: "most specific root wins",
+			cfg:  config{Paths: []string{"app", "app/i18n"}, FlatNamingByRoot: map[string]bool{"app/i18n": true}},
+			file: "app/i18n/es.json",
+			want: "es",
+			ok:   true,
+		},
+		{
+			name: "regex capture",
+			cfg:  config{Paths: []string{"res"}, NameRegex: regexp.MustCompile(`^(?:messages_(?P<lang>[a-z]{2})\.properties)$`)},
+			file: "res/messages_it.properties",
+			want: "it",
+			ok:   true,
+		},
+		{name: "name pattern", cfg: config{Paths: []string{"locales"}, NamePattern: "**/*.json"}, file: "locales/fr/app.json", ok: false},
+		{name: "outside roots", cfg: config{Paths: []string{"locales"}}, file: "docs/en/readme.json", ok: false},
+		{name: "file directly in nested root", cfg: config{Paths: []string{"locales"}}, file: "locales/app.json", ok: false},
+		{name: "decomposed file path", cfg: config{Paths: []string{"caf\u00e9/locales"}}, file: "cafe\u0301/locales/fr/app.json", want: "fr", ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := fileLang(tt.cfg, tt.file)
+			if ok != tt.ok || got != tt.want {
+				t.Fatalf("expected (%q, %v), got (%q, %v)", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestFileLangMap(t *testing.T) {
+	cfg := config{Paths: []string{"locales", "custom"}, NamePatternByRoot: map[string]namepattern.Rule{"custom": {Pattern: "*.json"}}}
+	got := fileLangMap(cfg, []string{"locales/en/app.json", "locales/fr/app.json", "custom/strings.json"})
+	want := map[string]string{"locales/en/app.json": "en", "locales/fr/app.json": "fr"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestDetectLangs(t *testing.T) {
+	t.Parallel()
+
+	flatRoot := filepath.Join(baseTestDir, "flat/translations")
+	nestedRoot := filepath.Join(baseTestDir, "nested")
+
+	tests := []struct {
+		name string
+		cfg  config
+		want []string
+	}{
+		{
+			name: "nested and flat roots",
+			cfg: config{
+				Paths:            []string{nestedRoot, flatRoot, filepath.Join(baseTestDir, "missing")},
+				FileExts:         []string{"json"},
+				FlatNamingByRoot: map[string]bool{filepath.ToSlash(flatRoot): true},
+			},
+			want: []string{"en", "en-US", "es", "fr"},
+		},
+		{
+			name: "folders without translation files are ignored",
+			cfg:  config{Paths: []string{nestedRoot}, FileExts: []string{"yaml"}},
+			want: []string{"en"},
+		},
+		{
+			name: "name pattern roots are skipped",
+			cfg:  config{Paths: []string{nestedRoot}, FileExts: []string{"json"}, NamePattern: "**/*.json"},
+			want: []string{},
+		},
+		{
+			name: "regex captures",
+			cfg: config{
+				Paths:     []string{flatRoot},
+				NameRegex: regexp.MustCompile(`^(?:(?P<lang>[a-z]{2})\.(?:json|yaml))$`),
+			},
+			want: []string{"en", "fr"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := detectLangs(tt.cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package findallfiles
+
+import "fmt"
+
+// findAllTranslationFiles scans each configured root using the chosen strategy.
+// Rules:
+//   - NAME_PATTERN (if provided) overrides layout rules and is treated as a glob under the root.
+//   - Flat:   collect "<root>/<baseLang>.<ext>" if present.
+//   - Nested: walk "<root>/<baseLang>" and collect files ending with ".<ext>",
+//     descending at most MAX_DEPTH levels when set. Dot-directories, node_modules,
+//     and vendor are skipped unless INCLUDE_VENDOR_DIRS is enabled.
+//
+// FLAT_NAMING may choose the layout per root. Symlinked files and directories
+// are skipped unless FOLLOW_SYMLINKS is enabled.
+//
+// With PUSH_ALL_LANGS, layout rules match every language instead of the base one:
+// flat collects "<root>/*.<ext>" and nested walks every "<root>/<lang>" directory,
+// except for languages listed in SKIP_LANGS.
+//
+// With DISCOVERY_MODE=git, the same rules are applied to the files tracked by git
+// instead of walking the working tree. INCLUDE_SUBMODULES adds the files tracked
+// by initialized submodules; the filesystem walk sees their checkouts anyway.
+//
+// With CHANGED_SINCE, only files that "git diff" reports as added or modified
+// since that ref are kept.
+//
+// Files matching any EXCLUDE_PATTERNS glob or EXCLUDE_PATHS entry are dropped from the result, and so are
+// files smaller than MIN_FILE_BYTES or larger than MAX_FILE_BYTES. Files
+// matched by several roots are kept once and reported in a ::warning annotation.
+//
+// With ANNOTATE_SKIPPED, every dropped file and every near miss of the
+// base-language layout (see findNearMisses) gets a ::warning annotation too.
+func findAllTranslationFiles(cfg config) ([]string, error) {
+	var (
+		files   []string
+		skipped []skippedFile
+		err     error
+	)
+	if cfg.AnnotateSkipped {
+		if skipped, err = findNearMisses(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.DiscoveryMode == discoveryGit {
+		list := listTrackedFiles
+		if cfg.IncludeSubmodules {
+			list = listTrackedFilesWithSubmodules
+		}
+		files, err = findTrackedTranslationFiles(cfg, list)
+	} else {
+		files, err = walkTranslationFiles(cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ChangedSince != "" {
+		if files, err = keepChanged(files, cfg.ChangedSince, cfg.Paths, listChangedFiles); err != nil {
+			return nil, err
+		}
+		logs.Infof("Kept %d files changed since %s", len(files), cfg.ChangedSince)
+	}
+
+	if len(cfg.ExcludePatterns) > 0 {
+		var excluded []skippedFile
+		files, excluded = excludeFiles(files, cfg.ExcludePatterns)
+		logs.Infof("Excluded %d files matching EXCLUDE_PATTERNS or EXCLUDE_PATHS", len(excluded))
+		skipped = append(skipped, excluded...)
+	}
+
+	if cfg.MinFileBytes > 0 || cfg.MaxFileBytes > 0 {
+		var outOfRange []skippedFile
+		files, outOfRange, err = filterBySize(files, cfg.MinFileBytes, cfg.MaxFileBytes)
+		if err != nil {
+			return nil, err
+		}
+		logs.Infof("Skipped %d files outside MIN_FILE_BYTES/MAX_FILE_BYTES", len(outOfRange))
+		skipped = append(skipped, outOfRange...)
+	}
+	logs.Infof("Found %d unique files", len(files))
+
+	if cfg.AnnotateSkipped {
+		warnSkipped(skipped)
+	}
+
+	return files, nil
+}
+
+// walkTranslationFiles collects matching files by reading the working tree.
+func walkTranslationFiles(cfg config) ([]string, error) {
+	collector := newFileCollector()
+	skipLangs := langSet(cfg.SkipLangs)
+	opts := walkOptions{
+		MaxDepth:       cfg.MaxDepth,
+		FollowSymlinks: cfg.FollowSymlinks,
+		SkipVendorDirs: !cfg.IncludeVendorDirs,
+	}
+
+	for _, root := range cfg.Paths {
+		if root == "" {
+			continue
+		}
+
+		collector.forRoot(root)
+		var err error
+		switch rule := cfg.nameRuleFor(root); {
+		case cfg.NameRegex != nil:
+			err = collectFilesByRegex(root, cfg, skipLangs, opts, collector.add)
+		case rule.Pattern != "":
+			err = collectFilesByPattern(root, rule, opts, collector.add)
+		case cfg.flatNamingFor(root) && cfg.AllLangs:
+			err = collectFlatFilesAllLangs(root, cfg.fileExtsFor(root), skipLangs, opts, collector.add)
+		case cfg.flatNamingFor(root):
+			err = collectFlatFiles(root, cfg.BaseLang, cfg.fileExtsFor(root), opts, collector.add)
+		case cfg.AllLangs:
+			err = collectNestedFilesAllLangs(root, cfg.fileExtsFor(root), skipLangs, opts, collector.add)
+		default:
+			err = collectNestedFiles(root, cfg.BaseLang, cfg.fileExtsFor(root), opts, collector.add)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot collect translation files under %q: %w", root, err)
+		}
+	}
+
+	warnOverlaps(collector.overlapping())
+	return collector.sorted(), nil
+}
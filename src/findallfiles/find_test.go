@@ -0,0 +1,863 @@
+package findallfiles
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/namepattern"
+)
+
+func TestFindAllTranslationFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		paths       []string
+		flatNaming  bool
+		baseLang    string
+		fileExt     []string
+		namePattern string
+		expected    []string
+		shouldError bool
+	}{
+		{
+			name:       "Flat naming with valid files",
+			paths:      []string{filepath.Join(baseTestDir, "flat/translations")},
+			flatNaming: true,
+			baseLang:   "en",
+			fileExt:    []string{"json"},
+			expected: []string{
+				filepath.Join(baseTestDir, "flat/translations/en.json"),
+			},
+		},
+		{
+			name:       "Flat naming with valid files and multiple exts",
+			paths:      []string{filepath.Join(baseTestDir, "flat/translations")},
+			flatNaming: true,
+			baseLang:   "en",
+			fileExt:    []string{"json", "yaml"},
+			expected: []string{
+				filepath.Join(baseTestDir, "flat/translations/en.json"),
+				filepath.Join(baseTestDir, "flat/translations/en.yaml"),
+			},
+		},
+		{
+			name:        "Custom pattern works with empty file extensions",
+			paths:       []string{filepath.Join(baseTestDir, "pattern-only")},
+			flatNaming:  true,
+			baseLang:    "zz",
+			fileExt:     nil,
+			namePattern: "**/custom_name.json",
+			expected: []string{
+				filepath.Join(baseTestDir, "pattern-only/sub/custom_name.json"),
+			},
+		},
+		{
+			name:       "Flat naming missing files is not an error",
+			paths:      []string{filepath.Join(baseTestDir, "flat/translations")},
+			flatNaming: true,
+			baseLang:   "de",
+			fileExt:    []string{"json"},
+			expected:   []string{},
+		},
+		{
+			name:       "Nested naming finds files recursively",
+			paths:      []string{filepath.Join(baseTestDir, "nested")},
+			flatNaming: false,
+			baseLang:   "en",
+			fileExt:    []string{"json"},
+			expected: []string{
+				filepath.Join(baseTestDir, "nested/en/file1.json"),
+				filepath.Join(baseTestDir, "nested/en/file2.json"),
+				filepath.Join(baseTestDir, "nested/en/deeper/file4.json"),
+			},
+		},
+		{
+			name:       "Nested naming matches extensions case-insensitively",
+			paths:      []string{filepath.Join(baseTestDir, "nested")},
+			flatNaming: false,
+			baseLang:   "en",
+			fileExt:    []string{"yaml"},
+			expected: []string{
+				filepath.Join(baseTestDir, "nested/en/file3.YAML"),
+			},
+		},
+		{
+			name:       "Nested naming missing language directory is not an error",
+			paths:      []string{filepath.Join(baseTestDir, "empty")},
+			flatNaming: false,
+			baseLang:   "en",
+			fileExt:    []string{"json"},
+			expected:   []string{},
+		},
+		{
+			name:       "Mixed flat roots only return matching flat files",
+			paths:      []string{filepath.Join(baseTestDir, "flat/translations"), filepath.Join(baseTestDir, "nested")},
+			flatNaming: true,
+			baseLang:   "en",
+			fileExt:    []string{"json"},
+			expected: []string{
+				filepath.Join(baseTestDir, "flat/translations/en.json"),
+			},
+		},
+		{
+			name:        "Custom name pattern with wildcard",
+			paths:       []string{filepath.Join(baseTestDir, "flat/translations"), filepath.Join(baseTestDir, "flat/translations")},
+			flatNaming:  false,
+			baseLang:    "",
+			fileExt:     []string{""},
+			namePattern: "**/*.json",
+			expected: []string{
+				filepath.Join(baseTestDir, "flat/translations/en.json"),
+				filepath.Join(baseTestDir, "flat/translations/en-US.json"),
+				filepath.Join(baseTestDir, "flat/translations/fr.json"),
+			},
+		},
+		{
+			name:        "Custom pattern overrides other inputs",
+			paths:       []string{filepath.Join(baseTestDir, "pattern-only")},
+			flatNaming:  true,
+			baseLang:    "zz",
+			fileExt:     []string{"xml"},
+			namePattern: "**/custom_name.json",
+			expected: []string{
+				filepath.Join(baseTestDir, "pattern-only/sub/custom_name.json"),
+			},
+		},
+		{
+			name:        "Invalid name pattern",
+			paths:       []string{filepath.Join(baseTestDir, "flat/translations")},
+			flatNaming:  false,
+			baseLang:    "",
+			fileExt:     []string{""},
+			namePattern: "[invalid pattern",
+			shouldError: true,
+		},
+		{
+			name:        "Case-sensitive pattern with no matches",
+			paths:       []string{filepath.Join(baseTestDir, "flat/translations")},
+			flatNaming:  false,
+			baseLang:    "",
+			fileExt:     []string{""},
+			namePattern: "**/*.JSON",
+			expected:    []string{},
+		},
+		{
+			name: "Multiple valid roots with custom pattern",
+			paths: []string{
+				filepath.Join(baseTestDir, "locales"),
+				filepath.Join(baseTestDir, "i18n"),
+			},
+			flatNaming:  false,
+			baseLang:    "",
+			fileExt:     []string{""},
+			namePattern: "en/**/custom_*.json",
+			expected: []string{
+				filepath.Join(baseTestDir, "locales/en/sub1/custom_abc.json"),
+				filepath.Join(baseTestDir, "i18n/en/sub2/custom_xyz.json"),
+			},
+		},
+		{
+			name:        "Custom pattern with no matches",
+			paths:       []string{filepath.Join(baseTestDir, "locales")},
+			flatNaming:  false,
+			baseLang:    "",
+			fileExt:     []string{""},
+			namePattern: "es/**/custom_*.json",
+			expected:    []string{},
+		},
+		{
+			name:       "Root directory translations with flat naming",
+			paths:      []string{filepath.Join(baseTestDir)},
+			flatNaming: true,
+			baseLang:   "en",
+			fileExt:    []string{"json"},
+			expected: []string{
+				filepath.Join(baseTestDir, "en.json"),
+			},
+		},
+		{
+			name:       "Duplicate roots and duplicate extensions are deduped",
+			paths:      []string{filepath.Join(baseTestDir, "flat/translations"), filepath.Join(baseTestDir, "flat/translations")},
+			flatNaming: true,
+			baseLang:   "en",
+			fileExt:    []string{"json", "json"},
+			expected: []string{
+				filepath.Join(baseTestDir, "flat/translations/en.json"),
+			},
+		},
+		{
+			name:       "Empty root entries are skipped",
+			paths:      []string{"", filepath.Join(baseTestDir, "flat/translations")},
+			flatNaming: true,
+			baseLang:   "en",
+			fileExt:    []string{"json"},
+			expected: []string{
+				filepath.Join(baseTestDir, "flat/translations/en.json"),
+			},
+		},
+		{
+			name: "Nested naming across multiple roots",
+			paths: []string{
+				filepath.Join(baseTestDir, "multiple/dir1"),
+				filepath.Join(baseTestDir, "multiple/dir2"),
+				filepath.Join(baseTestDir, "multiple/dir3"),
+			},
+			flatNaming: false,
+			baseLang:   "en",
+			fileExt:    []string{"json"},
+			expected: []string{
+				filepath.Join(baseTestDir, "multiple/dir1/en/file1.json"),
+				filepath.Join(baseTestDir, "multiple/dir2/en/file2.json"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := findAllTranslationFiles(config{
+				Paths:       tt.paths,
+				FlatNaming:  tt.flatNaming,
+				BaseLang:    tt.baseLang,
+				FileExts:    tt.fileExt,
+				NamePattern: tt.namePattern,
+			})
+
+			if tt.shouldError {
+				if err == nil {
+					t.Fatal("expected an error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			actualNormalized := normalizePaths(actual)
+			expectedNormalized := normalizePaths(tt.expected)
+
+			slices.Sort(actualNormalized)
+			slices.Sort(expectedNormalized)
+
+			if !reflect.DeepEqual(actualNormalized, expectedNormalized) {
+				t.Fatalf("expected files %v, got %v", expectedNormalized, actualNormalized)
+			}
+		})
+	}
+}
+
+func TestFindAllTranslationFiles_ReturnsSortedOutput(t *testing.T) {
+	t.Parallel()
+
+	paths := []string{filepath.Join(baseTestDir, "flat/translations")}
+
+	got, err := findAllTranslationFiles(config{
+		Paths:      paths,
+		FlatNaming: true,
+		BaseLang:   "en",
+		FileExts:   []string{"yaml", "json"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got = normalizePaths(got)
+	want := normalizePaths([]string{
+		filepath.Join(baseTestDir, "flat/translations/en.json"),
+		filepath.Join(baseTestDir, "flat/translations/en.yaml"),
+	})
+
+	slices.Sort(want)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected sorted files %v, got %v", want, got)
+	}
+}
+
+func TestFindAllTranslationFiles_AllLangs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		paths      []string
+		flatNaming bool
+		fileExt    []string
+		expected   []string
+	}{
+		{
+			name:       "flat layout collects every language file",
+			paths:      []string{filepath.Join(baseTestDir, "flat/translations")},
+			flatNaming: true,
+			fileExt:    []string{"json"},
+			expected: []string{
+				filepath.Join(baseTestDir, "flat/translations/en-US.json"),
+				filepath.Join(baseTestDir, "flat/translations/en.json"),
+				filepath.Join(baseTestDir, "flat/translations/fr.json"),
+			},
+		},
+		{
+			name:    "nested layout walks every language directory",
+			paths:   []string{filepath.Join(baseTestDir, "nested")},
+			fileExt: []string{"json"},
+			expected: []string{
+				filepath.Join(baseTestDir, "nested/en/deeper/file4.json"),
+				filepath.Join(baseTestDir, "nested/en/file1.json"),
+				filepath.Join(baseTestDir, "nested/en/file2.json"),
+				filepath.Join(baseTestDir, "nested/es/file1.json"),
+			},
+		},
+		{
+			name:     "missing root is not an error",
+			paths:    []string{filepath.Join(baseTestDir, "does-not-exist")},
+			fileExt:  []string{"json"},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := findAllTranslationFiles(config{
+				Paths:      tt.paths,
+				FlatNaming: tt.flatNaming,
+				BaseLang:   "en",
+				FileExts:   tt.fileExt,
+				AllLangs:   true,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got = normalizePaths(got)
+			want := normalizePaths(tt.expected)
+			slices.Sort(want)
+
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected files %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFindAllTranslationFiles_AllLangsSkipLangs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		paths      []string
+		flatNaming bool
+		expected   []string
+	}{
+		{
+			name:       "flat layout",
+			paths:      []string{filepath.Join(baseTestDir, "flat/translations")},
+			flatNaming: true,
+			expected: []string{
+				filepath.Join(baseTestDir, "flat/translations/en-US.json"),
+				filepath.Join(baseTestDir, "flat/translations/en.json"),
+			},
+		},
+		{
+			name:  "nested layout",
+			paths: []string{filepath.Join(baseTestDir, "nested")},
+			expected: []string{
+				filepath.Join(baseTestDir, "nested/en/deeper/file4.json"),
+				filepath.Join(baseTestDir, "nested/en/file1.json"),
+				filepath.Join(baseTestDir, "nested/en/file2.json"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := findAllTranslationFiles(config{
+				Paths:      tt.paths,
+				FlatNaming: tt.flatNaming,
+				BaseLang:   "en",
+				FileExts:   []string{"json"},
+				AllLangs:   true,
+				SkipLangs:  []string{"fr", "es"},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got = normalizePaths(got)
+			want := normalizePaths(tt.expected)
+			slices.Sort(want)
+
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected files %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFindAllTranslationFiles_ExcludePatterns(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.ToSlash(filepath.Join(baseTestDir, "nested"))
+
+	got, err := findAllTranslationFiles(config{
+		Paths:           []string{root},
+		BaseLang:        "en",
+		FileExts:        []string{"json"},
+		AllLangs:        true,
+		ExcludePatterns: []string{"**/deeper/**", root + "/es/*.json"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got = normalizePaths(got)
+	want := normalizePaths([]string{
+		filepath.Join(baseTestDir, "nested/en/file1.json"),
+		filepath.Join(baseTestDir, "nested/en/file2.json"),
+	})
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected files %v, got %v", want, got)
+	}
+}
+
+func TestFindAllTranslationFiles_FileSizeLimits(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"en/empty.json": "",
+		"en/app.json":   `{"hello":"Hello"}`,
+		"en/blob.json":  strings.Repeat("x", 4096),
+	} {
+		fp := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fp), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fp, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := findAllTranslationFiles(config{
+		Paths:        []string{filepath.ToSlash(dir)},
+		BaseLang:     "en",
+		FileExts:     []string{"json"},
+		MinFileBytes: 1,
+		MaxFileBytes: 1024,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{filepath.ToSlash(filepath.Join(dir, "en/app.json"))}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected files %v, got %v", want, got)
+	}
+}
+
+func TestFindAllTranslationFiles_MaxDepth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		maxDepth int
+		expected []string
+	}{
+		{
+			name:     "depth 1 keeps files directly in the language directory",
+			maxDepth: 1,
+			expected: []string{
+				filepath.Join(baseTestDir, "nested/en/file1.json"),
+				filepath.Join(baseTestDir, "nested/en/file2.json"),
+			},
+		},
+		{
+			name:     "depth 2 includes one nested level",
+			maxDepth: 2,
+			expected: []string{
+				filepath.Join(baseTestDir, "nested/en/deeper/file4.json"),
+				filepath.Join(baseTestDir, "nested/en/file1.json"),
+				filepath.Join(baseTestDir, "nested/en/file2.json"),
+			},
+		},
+		{
+			name:     "zero means unlimited",
+			maxDepth: 0,
+			expected: []string{
+				filepath.Join(baseTestDir, "nested/en/deeper/file4.json"),
+				filepath.Join(baseTestDir, "nested/en/file1.json"),
+				filepath.Join(baseTestDir, "nested/en/file2.json"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := findAllTranslationFiles(config{
+				Paths:    []string{filepath.Join(baseTestDir, "nested")},
+				BaseLang: "en",
+				FileExts: []string{"json"},
+				MaxDepth: tt.maxDepth,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got = normalizePaths(got)
+			want := normalizePaths(tt.expected)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected files %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFindAllTranslationFiles_Symlinks(t *testing.T) {
+	dir := setupSymlinkTree(t)
+	// Flat layout: "fr.json" is a symlink to a regular file, "de" a symlinked language directory.
+	if err := os.Symlink(filepath.Join("en", "a.json"), filepath.Join(dir, "fr.json")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+	if err := os.Symlink("en", filepath.Join(dir, "de")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config
+		expected []string
+	}{
+		{
+			name:     "flat layout skips symlinked files by default",
+			cfg:      config{FlatNaming: true, AllLangs: true},
+			expected: nil,
+		},
+		{
+			name:     "flat layout follows symlinked files",
+			cfg:      config{FlatNaming: true, AllLangs: true, FollowSymlinks: true},
+			expected: []string{"fr.json"},
+		},
+		{
+			name:     "nested layout skips symlinked language directories by default",
+			cfg:      config{AllLangs: true, SkipLangs: []string{"shared"}},
+			expected: []string{"en/a.json"},
+		},
+		{
+			name:     "nested layout follows symlinks with loop protection",
+			cfg:      config{AllLangs: true, FollowSymlinks: true, SkipLangs: []string{"shared"}},
+			expected: []string{"de/a.json", "de/link.json", "de/shared/b.json", "en/a.json", "en/link.json", "en/shared/b.json"},
+		},
+		{
+			name:     "name pattern skips symlinks by default",
+			cfg:      config{NamePattern: "**/*.json"},
+			expected: []string{"en/a.json", "shared/b.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.Paths = []string{dir}
+			cfg.BaseLang = "en"
+			cfg.FileExts = []string{"json"}
+
+			got, err := findAllTranslationFiles(cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var want []string
+			for _, f := range tt.expected {
+				want = append(want, filepath.ToSlash(filepath.Join(dir, f)))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected files %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFindAllTranslationFiles_VendorDirs(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"en/app.json",
+		"en/pages/home.json",
+		"en/node_modules/pkg/en.json",
+		"en/vendor/lib.json",
+		"en/.cache/tmp.json",
+		".git/en.json",
+		"node_modules/en.json",
+	} {
+		full := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		include  bool
+		expected []string
+	}{
+		{
+			name:     "skipped by default",
+			expected: []string{"en/app.json", "en/pages/home.json"},
+		},
+		{
+			name:    "included on opt-out",
+			include: true,
+			expected: []string{
+				".git/en.json",
+				"en/.cache/tmp.json",
+				"en/app.json",
+				"en/node_modules/pkg/en.json",
+				"en/pages/home.json",
+				"en/vendor/lib.json",
+				"node_modules/en.json",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findAllTranslationFiles(config{
+				Paths:             []string{dir},
+				BaseLang:          "en",
+				FileExts:          []string{"json"},
+				AllLangs:          true,
+				IncludeVendorDirs: tt.include,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var want []string
+			for _, f := range tt.expected {
+				want = append(want, filepath.ToSlash(filepath.Join(dir, f)))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected files %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFindAllTranslationFiles_FlatNamingPerRoot(t *testing.T) {
+	t.Parallel()
+
+	flatRoot := filepath.ToSlash(filepath.Join(baseTestDir, "flat/translations"))
+	nestedRoot := filepath.ToSlash(filepath.Join(baseTestDir, "nested"))
+
+	got, err := findAllTranslationFiles(config{
+		Paths:            []string{flatRoot, nestedRoot},
+		BaseLang:         "en",
+		FileExts:         []string{"json"},
+		FlatNamingByRoot: map[string]bool{flatRoot: true, nestedRoot: false},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got = normalizePaths(got)
+	want := normalizePaths([]string{
+		filepath.Join(baseTestDir, "flat/translations/en.json"),
+		filepath.Join(baseTestDir, "nested/en/deeper/file4.json"),
+		filepath.Join(baseTestDir, "nested/en/file1.json"),
+		filepath.Join(baseTestDir, "nested/en/file2.json"),
+	})
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected files %v, got %v", want, got)
+	}
+}
+
+func TestFindAllTranslationFiles_NamePatternPerRoot(t *testing.T) {
+	t.Parallel()
+
+	patternRoot := filepath.ToSlash(filepath.Join(baseTestDir, "pattern-only"))
+	nestedRoot := filepath.ToSlash(filepath.Join(baseTestDir, "nested"))
+
+	got, err := findAllTranslationFiles(config{
+		Paths:             []string{patternRoot, nestedRoot},
+		BaseLang:          "en",
+		FileExts:          []string{"json"},
+		NamePatternByRoot: map[string]namepattern.Rule{patternRoot: {Pattern: "**/custom_*.json"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got = normalizePaths(got)
+	want := normalizePaths([]string{
+		filepath.Join(baseTestDir, "nested/en/deeper/file4.json"),
+		filepath.Join(baseTestDir, "nested/en/file1.json"),
+		filepath.Join(baseTestDir, "nested/en/file2.json"),
+		filepath.Join(baseTestDir, "pattern-only/sub/custom_name.json"),
+	})
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected files %v, got %v", want, got)
+	}
+}
+
+func TestFindAllTranslationFiles_NamePatternExclusions(t *testing.T) {
+	t.Parallel()
+
+	got, err := findAllTranslationFiles(config{
+		Paths:        []string{filepath.Join(baseTestDir, "nested")},
+		BaseLang:     "en",
+		NamePattern:  "**/*.json",
+		NameExcludes: []string{"**/deeper/**", "es/*"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got = normalizePaths(got)
+	want := normalizePaths([]string{
+		filepath.Join(baseTestDir, "nested/en/file1.json"),
+		filepath.Join(baseTestDir, "nested/en/file2.json"),
+	})
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected files %v, got %v", want, got)
+	}
+}
+
+func TestFindAllTranslationFiles_NameRegex(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"messages_en.properties",
+		"messages_fr.properties",
+		"messages_de.properties",
+		"messages.properties",
+		"admin/messages_en.properties",
+		"other_en.properties",
+	} {
+		full := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("key=value"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	re := regexp.MustCompile(`^(?:(?:[^/]+/)*messages_(?P<lang>[a-z]{2})\.properties)$`)
+
+	tests := []struct {
+		name     string
+		cfg      config
+		expected []string
+	}{
+		{
+			name:     "base language only",
+			cfg:      config{BaseLang: "en"},
+			expected: []string{"admin/messages_en.properties", "messages_en.properties"},
+		},
+		{
+			name:     "all languages except skipped",
+			cfg:      config{BaseLang: "en", AllLangs: true, SkipLangs: []string{"de"}},
+			expected: []string{"admin/messages_en.properties", "messages_en.properties", "messages_fr.properties"},
+		},
+		{
+			name:     "git discovery",
+			cfg:      config{BaseLang: "fr", DiscoveryMode: discoveryGit},
+			expected: []string{"messages_fr.properties"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.Paths = []string{dir}
+			cfg.NameRegex = re
+
+			var got []string
+			var err error
+			if cfg.DiscoveryMode == discoveryGit {
+				got, err = findTrackedTranslationFiles(cfg, func([]string) ([]string, error) {
+					var tracked []string
+					for _, f := range []string{"messages_en.properties", "messages_fr.properties", "nested/messages_fr.txt"} {
+						tracked = append(tracked, filepath.ToSlash(filepath.Join(dir, f)))
+					}
+					return tracked, nil
+				})
+			} else {
+				got, err = findAllTranslationFiles(cfg)
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var want []string
+			for _, f := range tt.expected {
+				want = append(want, filepath.Join(dir, f))
+			}
+			if !reflect.DeepEqual(normalizePaths(got), normalizePaths(want)) {
+				t.Fatalf("expected files %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func normalizePaths(paths []string) []string {
+	normalized := make([]string, len(paths))
+	for i, p := range paths {
+		normalized[i] = filepath.ToSlash(filepath.Clean(p))
+	}
+	return normalized
+}
+
+func TestFindAllTranslationFiles_FileExtsPerRoot(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"web/en/app.json",
+		"web/en/app.strings",
+		"ios/en.strings",
+		"ios/en.stringsdict",
+		"ios/en.json",
+	} {
+		full := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	web := filepath.ToSlash(filepath.Join(dir, "web"))
+	ios := filepath.ToSlash(filepath.Join(dir, "ios"))
+	got, err := findAllTranslationFiles(config{
+		Paths:            []string{web, ios},
+		BaseLang:         "en",
+		FileExts:         []string{"json", "strings", "stringsdict"},
+		FileExtsByRoot:   map[string][]string{web: {"json"}, ios: {"strings", "stringsdict"}},
+		FlatNamingByRoot: map[string]bool{web: false, ios: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got = normalizePaths(got)
+	want := normalizePaths([]string{
+		filepath.Join(dir, "ios/en.strings"),
+		filepath.Join(dir, "ios/en.stringsdict"),
+		filepath.Join(dir, "web/en/app.json"),
+	})
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected files %v, got %v", want, got)
+	}
+}
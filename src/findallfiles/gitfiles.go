@@ -0,0 +1,216 @@
+package findallfiles
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"lokalise-push-action/internal/pathnorm"
+)
+
+// Discovery modes accepted by DISCOVERY_MODE.
+const (
+	discoveryFilesystem = "filesystem" // Walk the working tree (default).
+	discoveryGit        = "git"        // Enumerate files tracked by git.
+)
+
+// trackedFilesFunc lists tracked files under the given roots, relative to the repo root.
+type trackedFilesFunc func(roots []string) ([]string, error)
+
+// listTrackedFiles runs "git ls-files" for roots in the current directory.
+// Output is NUL-delimited so paths with spaces, quotes, or newlines survive intact.
+func listTrackedFiles(roots []string) ([]string, error) {
+	return gitLsFiles("", roots, false)
+}
+
+// listTrackedFilesWithSubmodules also lists the files tracked by initialized
+// submodules; without it a submodule shows up as a single gitlink entry.
+func listTrackedFilesWithSubmodules(roots []string) ([]string, error) {
+	return gitLsFiles("", roots, true)
+}
+
+func gitLsFiles(dir string, roots []string, recurseSubmodules bool) ([]string, error) {
+	args := []string{"ls-files", "-z", "--cached"}
+	if recurseSubmodules {
+		args = append(args, "--recurse-submodules")
+	}
+	args = append(append(args, "--"), roots...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var files []string
+	for entry := range strings.SplitSeq(string(out), "\x00") {
+		if entry != "" {
+			files = append(files, entry)
+		}
+	}
+	return files, nil
+}
+
+// findTrackedTranslationFiles applies the same layout rules as the filesystem
+// walk to the list of tracked files, so no directories are traversed.
+// Tracked files deleted from the working tree are skipped, and so are
+// symlinks unless FOLLOW_SYMLINKS is enabled.
+func findTrackedTranslationFiles(cfg config, list trackedFilesFunc) ([]string, error) {
+	var roots []string
+	for _, root := range cfg.Paths {
+		if root != "" {
+			roots = append(roots, root)
+		}
+	}
+	if len(roots) == 0 {
+		return nil, nil
+	}
+
+	tracked, err := list(roots)
+	if err != nil {
+		return nil, err
+	}
+
+	collector := newFileCollector()
+	skipLangs := langSet(cfg.SkipLangs)
+
+	for _, root := range roots {
+		collector.forRoot(root)
+		if err := collectTrackedFiles(root, cfg, skipLangs, tracked, collector.add); err != nil {
+			return nil, fmt.Errorf("cannot collect translation files under %q: %w", root, err)
+		}
+	}
+
+	warnOverlaps(collector.overlapping())
+	opts := walkOptions{FollowSymlinks: cfg.FollowSymlinks}
+
+	var files []string
+	for _, f := range collector.sorted() {
+		ok, err := isTrackedRegularFile(f, opts)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// isTrackedRegularFile reports whether the tracked file still exists in the working
+// tree as a regular file. Git stores symlinks as links, so symlinked files are
+// only kept (and resolved) when following symlinks.
+func isTrackedRegularFile(f string, opts walkOptions) (bool, error) {
+	fp := filepath.FromSlash(f)
+
+	info, err := os.Lstat(fp)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("cannot access tracked file %q: %w", f, err)
+	}
+	mode, ok, err := resolveEntry(fp, fs.FileInfoToDirEntry(info), opts)
+	if err != nil || !ok {
+		return false, err
+	}
+	return mode.IsRegular(), nil
+}
+
+// collectTrackedFiles adds the tracked files under root that match the configured layout.
+func collectTrackedFiles(root string, cfg config, skipLangs map[string]struct{}, tracked []string, add func(string)) error {
+	root = filepath.ToSlash(root)
+
+	var rule rootedRule
+	if r := cfg.nameRuleFor(root); r.Pattern != "" {
+		var err error
+		if rule, err = rootRule(r, root); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range tracked {
+		rel, ok := relativeToRoot(root, file)
+		if !ok {
+			continue
+		}
+
+		var match bool
+		switch {
+		case cfg.NameRegex != nil:
+			lang, ok := regexLang(cfg.NameRegex, rel)
+			match = ok && langWanted(lang, cfg, skipLangs)
+		case rule.Pattern != "":
+			match = rule.match(file)
+		case cfg.flatNamingFor(root):
+			match = matchTrackedFlat(rel, cfg.fileExtsFor(root), cfg, skipLangs)
+		default:
+			match = matchTrackedNested(rel, cfg.fileExtsFor(root), cfg, skipLangs)
+		}
+
+		if match {
+			add(file)
+		}
+	}
+
+	return nil
+}
+
+// relativeToRoot returns file relative to root, reporting whether it lies under
+// root. Roots are in NFC, so file is composed before the comparison: git and
+// macOS file systems may list decomposed names.
+func relativeToRoot(root, file string) (string, bool) {
+	file = pathnorm.ComposeNFC(file)
+	if root == "." {
+		return file, true
+	}
+	return strings.CutPrefix(file, root+"/")
+}
+
+// matchTrackedFlat mirrors collectFlatFiles and collectFlatFilesAllLangs.
+func matchTrackedFlat(rel string, fileExts []string, cfg config, skipLangs map[string]struct{}) bool {
+	if strings.Contains(rel, "/") || !hasMatchingExtension(rel, fileExts) {
+		return false
+	}
+
+	lang := strings.TrimSuffix(rel, path.Ext(rel))
+	if cfg.AllLangs {
+		_, skip := skipLangs[lang]
+		return !skip
+	}
+	return lang == cfg.BaseLang
+}
+
+// matchTrackedNested mirrors collectNestedFiles and collectNestedFilesAllLangs.
+func matchTrackedNested(rel string, fileExts []string, cfg config, skipLangs map[string]struct{}) bool {
+	lang, rest, ok := strings.Cut(rel, "/")
+	if !ok || !hasMatchingExtension(path.Base(rest), fileExts) {
+		return false
+	}
+	opts := walkOptions{MaxDepth: cfg.MaxDepth, SkipVendorDirs: !cfg.IncludeVendorDirs}
+	if opts.tooDeep(pathDepth(rest)) || opts.skipDir(lang) {
+		return false
+	}
+	if dir := path.Dir(rest); dir != "." {
+		for seg := range strings.SplitSeq(dir, "/") {
+			if opts.skipDir(seg) {
+				return false
+			}
+		}
+	}
+
+	if cfg.AllLangs {
+		_, skip := skipLangs[lang]
+		return !skip
+	}
+	return lang == cfg.BaseLang
+}
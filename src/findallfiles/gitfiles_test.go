@@ -0,0 +1,300 @@
+package findallfiles
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/namepattern"
+)
+
+func TestGitLsFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	files := map[string]string{
+		"locales/en/app.json":         "{}",
+		"locales/en/with, comma.json": "{}",
+		"locales/fr/app.json":         "{}",
+		"docs/readme.md":              "# docs",
+	}
+	for name, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runGit("init", "-q")
+	runGit("add", "locales/en", "docs")
+	// Untracked files must not be listed.
+	if err := os.WriteFile(filepath.Join(dir, "locales/en/untracked.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := gitLsFiles(dir, []string{"locales"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"locales/en/app.json", "locales/en/with, comma.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if _, err := gitLsFiles(t.TempDir(), []string{"."}, false); err == nil || !strings.Contains(err.Error(), "git ls-files failed") {
+		t.Fatalf("expected error outside a repository, got %v", err)
+	}
+}
+
+func TestGitLsFiles_Submodules(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	runGit := func(dir string, args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "protocol.file.allow=always"}, args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	writeFile := func(path string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lib := t.TempDir()
+	writeFile(filepath.Join(lib, "locales", "en", "lib.json"))
+	runGit(lib, "init", "-q")
+	runGit(lib, "add", ".")
+	runGit(lib, "commit", "-q", "-m", "lib")
+
+	dir := t.TempDir()
+	writeFile(filepath.Join(dir, "locales", "en", "app.json"))
+	runGit(dir, "init", "-q")
+	runGit(dir, "add", ".")
+	runGit(dir, "submodule", "add", "-q", lib, "packages/lib")
+
+	got, err := gitLsFiles(dir, []string{"locales", "packages"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"locales/en/app.json", "packages/lib"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	got, err = gitLsFiles(dir, []string{"locales", "packages"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"locales/en/app.json", "packages/lib/locales/en/lib.json"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestFindTrackedTranslationFiles(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.ToSlash(baseTestDir)
+	tracked := []string{
+		base + "/flat/translations/en.json",
+		base + "/flat/translations/fr.json",
+		base + "/flat/translations/unrelated.txt",
+		base + "/nested/en/file1.json",
+		base + "/nested/en/deeper/file4.json",
+		base + "/nested/en/file3.YAML",
+		base + "/nested/es/file1.json",
+		base + "/nested/en/deleted.json", // tracked but removed from the working tree
+		base + "/pattern-only/sub/custom_name.json",
+		base + "/nested/en/node_modules/pkg/en.json",
+		base + "/nested/.git/en.json",
+	}
+	list := func(roots []string) ([]string, error) {
+		return tracked, nil
+	}
+
+	tests := []struct {
+		name string
+		cfg  config
+		want []string
+	}{
+		{
+			name: "flat layout",
+			cfg:  config{Paths: []string{base + "/flat/translations"}, FlatNaming: true, BaseLang: "en", FileExts: []string{"json"}},
+			want: []string{"flat/translations/en.json"},
+		},
+		{
+			name: "flat layout with all languages",
+			cfg:  config{Paths: []string{base + "/flat/translations"}, FlatNaming: true, AllLangs: true, BaseLang: "en", FileExts: []string{"json"}},
+			want: []string{"flat/translations/en.json", "flat/translations/fr.json"},
+		},
+		{
+			name: "nested layout",
+			cfg:  config{Paths: []string{base + "/nested"}, BaseLang: "en", FileExts: []string{"json", "yaml"}},
+			want: []string{"nested/en/deeper/file4.json", "nested/en/file1.json", "nested/en/file3.YAML"},
+		},
+		{
+			name: "nested layout with all languages and skipped ones",
+			cfg:  config{Paths: []string{base + "/nested"}, AllLangs: true, SkipLangs: []string{"en"}, BaseLang: "fr", FileExts: []string{"json"}},
+			want: []string{"nested/es/file1.json"},
+		},
+		{
+			name: "name pattern",
+			cfg:  config{Paths: []string{base}, NamePattern: "**/custom_*.json", BaseLang: "en"},
+			want: []string{"pattern-only/sub/custom_name.json"},
+		},
+		{
+			name: "name pattern with exclusions",
+			cfg:  config{Paths: []string{base + "/nested"}, NamePattern: "**/*.json", NameExcludes: []string{"**/deeper/**", "**/node_modules/**", ".git/**"}, BaseLang: "en"},
+			want: []string{"nested/en/file1.json", "nested/es/file1.json"},
+		},
+		{
+			name: "per-root name pattern",
+			cfg: config{
+				Paths:             []string{base + "/pattern-only", base + "/nested"},
+				NamePatternByRoot: map[string]namepattern.Rule{base + "/pattern-only": {Pattern: "**/custom_*.json"}},
+				BaseLang:          "en",
+				FileExts:          []string{"json"},
+			},
+			want: []string{"nested/en/deeper/file4.json", "nested/en/file1.json", "pattern-only/sub/custom_name.json"},
+		},
+		{
+			name: "nested layout with max depth",
+			cfg:  config{Paths: []string{base + "/nested"}, BaseLang: "en", FileExts: []string{"json"}, MaxDepth: 1},
+			want: []string{"nested/en/file1.json"},
+		},
+		{
+			name: "root outside tracked files",
+			cfg:  config{Paths: []string{base + "/i18n"}, BaseLang: "en", FileExts: []string{"json"}},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := findTrackedTranslationFiles(tt.cfg, list)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var want []string
+			for _, f := range tt.want {
+				want = append(want, path.Join(base, f))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFindTrackedTranslationFiles_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := findTrackedTranslationFiles(config{Paths: []string{"locales"}}, func([]string) ([]string, error) {
+		return nil, errors.New("boom")
+	})
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("expected lister error, got %v", err)
+	}
+
+	_, err = findTrackedTranslationFiles(config{Paths: []string{"locales"}, NamePattern: "[en.json"}, func([]string) ([]string, error) {
+		return []string{"locales/en.json"}, nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "apply name pattern") {
+		t.Fatalf("expected pattern error, got %v", err)
+	}
+}
+
+func TestIsTrackedRegularFile(t *testing.T) {
+	dir := setupSymlinkTree(t)
+
+	tests := []struct {
+		file   string
+		follow bool
+		want   bool
+	}{
+		{"en/a.json", false, true},
+		{"en/link.json", false, false},
+		{"en/link.json", true, true},
+		{"en/dangling.json", true, false},
+		{"en/shared", true, false},
+		{"en/deleted.json", false, false},
+	}
+
+	for _, tt := range tests {
+		got, err := isTrackedRegularFile(filepath.ToSlash(filepath.Join(dir, tt.file)), walkOptions{FollowSymlinks: tt.follow})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.file, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%s (follow=%v): expected %v, got %v", tt.file, tt.follow, tt.want, got)
+		}
+	}
+}
+
+func TestRelativeToRoot(t *testing.T) {
+	for _, tt := range []struct {
+		root, file, want string
+		ok               bool
+	}{
+		{"locales", "locales/en/app.json", "en/app.json", true},
+		{".", "locales/en/app.json", "locales/en/app.json", true},
+		{"locales", "other/en/app.json", "", false},
+		{"caf\u00e9", "cafe\u0301/en.json", "en.json", true},
+	} {
+		got, ok := relativeToRoot(tt.root, tt.file)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Fatalf("%s in %s: expected (%q, %v), got (%q, %v)", tt.file, tt.root, tt.want, tt.ok, got, ok)
+		}
+	}
+}
+
+func TestMatchTrackedNested_VendorDirs(t *testing.T) {
+	cfg := config{BaseLang: "en", FileExts: []string{"json"}, AllLangs: true}
+
+	for rel, want := range map[string]bool{
+		"en/app.json":                 true,
+		"en/pages/home.json":          true,
+		"en/node_modules/pkg/en.json": false,
+		"en/a/vendor/lib.json":        false,
+		".git/en.json":                false,
+	} {
+		if got := matchTrackedNested(rel, cfg.FileExts, cfg, nil); got != want {
+			t.Fatalf("%s: expected %v, got %v", rel, want, got)
+		}
+	}
+
+	cfg.IncludeVendorDirs = true
+	if !matchTrackedNested("en/node_modules/pkg/en.json", cfg.FileExts, cfg, nil) {
+		t.Fatal("expected vendor directories to be matched on opt-out")
+	}
+}
@@ -0,0 +1,92 @@
+package findallfiles
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hashManifestSuffix is appended to FILES_LIST_PATH to name the hash manifest.
+const hashManifestSuffix = ".sha256"
+
+// writeFileHashes emits the SHA-256 of every collected file when HASH_FILES is
+// enabled: FILE_HASHES as a JSON object of paths to hex digests or, when
+// FILES_LIST_PATH is set, a sha256sum-compatible manifest next to the list
+// (FILE_HASHES_PATH). FILES_DIGEST hashes the whole manifest, giving a single
+// cache key that changes whenever any file is added, removed, or modified.
+func writeFileHashes(cfg config, files []string, writeOutput func(key, value string) bool) error {
+	if !cfg.HashFiles || len(files) == 0 {
+		return nil
+	}
+
+	hashes, err := hashFiles(files)
+	if err != nil {
+		return err
+	}
+	manifest := hashManifest(files, hashes)
+
+	if cfg.FilesListPath != "" {
+		manifestPath := cfg.FilesListPath + hashManifestSuffix
+		if err := os.WriteFile(manifestPath, []byte(manifest), 0o644); err != nil {
+			return fmt.Errorf("cannot write hash manifest: %w", err)
+		}
+		if !writeOutput("FILE_HASHES_PATH", manifestPath) {
+			return fmt.Errorf("cannot write FILE_HASHES_PATH to GITHUB_OUTPUT")
+		}
+	} else {
+		hashesJSON, err := json.Marshal(hashes)
+		if err != nil {
+			return fmt.Errorf("cannot encode FILE_HASHES: %w", err)
+		}
+		if !writeOutput("FILE_HASHES", string(hashesJSON)) {
+			return fmt.Errorf("cannot write FILE_HASHES to GITHUB_OUTPUT")
+		}
+	}
+
+	digest := sha256.Sum256([]byte(manifest))
+	if !writeOutput("FILES_DIGEST", hex.EncodeToString(digest[:])) {
+		return fmt.Errorf("cannot write FILES_DIGEST to GITHUB_OUTPUT")
+	}
+	return nil
+}
+
+// hashFiles returns the hex-encoded SHA-256 of every file, keyed by path.
+func hashFiles(files []string) (map[string]string, error) {
+	hashes := make(map[string]string, len(files))
+	for _, f := range files {
+		sum, err := hashFile(filepath.FromSlash(f))
+		if err != nil {
+			return nil, err
+		}
+		hashes[f] = sum
+	}
+	return hashes, nil
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot hash %q: %w", path, err)
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("cannot hash %q: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashManifest renders hashes in sha256sum format ("<digest>  <path>"), in file order.
+func hashManifest(files []string, hashes map[string]string) string {
+	var b strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&b, "%s  %s\n", hashes[f], f)
+	}
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package findallfiles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Digests of "{}" and "hello" respectively.
+const (
+	emptyObjectSHA = "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"
+	helloSHA       = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+)
+
+func writeHashFixtures(t *testing.T) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := []string{filepath.ToSlash(filepath.Join(dir, "en.json")), filepath.ToSlash(filepath.Join(dir, "fr.json"))}
+	for f, content := range map[string]string{files[0]: "{}", files[1]: "hello"} {
+		if err := os.WriteFile(filepath.FromSlash(f), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return files
+}
+
+func TestWriteFileHashes(t *testing.T) {
+	files := writeHashFixtures(t)
+
+	t.Run("disabled", func(t *testing.T) {
+		err := writeFileHashes(config{}, files, func(key, _ string) bool {
+			t.Fatalf("unexpected write of %s", key)
+			return true
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		writes := make(map[string]string)
+		err := writeFileHashes(config{HashFiles: true}, files, func(key, value string) bool {
+			writes[key] = value
+			return true
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		wantJSON := `{"` + files[0] + `":"` + emptyObjectSHA + `","` + files[1] + `":"` + helloSHA + `"}`
+		if writes["FILE_HASHES"] != wantJSON {
+			t.Fatalf("expected FILE_HASHES %s, got %s", wantJSON, writes["FILE_HASHES"])
+		}
+		if len(writes["FILES_DIGEST"]) != 64 {
+			t.Fatalf("expected a SHA-256 FILES_DIGEST, got %q", writes["FILES_DIGEST"])
+		}
+	})
+
+	t.Run("manifest next to the files list", func(t *testing.T) {
+		listPath := filepath.Join(t.TempDir(), "files.txt")
+		writes := make(map[string]string)
+		err := writeFileHashes(config{HashFiles: true, FilesListPath: listPath}, files, func(key, value string) bool {
+			writes[key] = value
+			return true
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if writes["FILE_HASHES_PATH"] != listPath+".sha256" {
+			t.Fatalf("unexpected FILE_HASHES_PATH %q", writes["FILE_HASHES_PATH"])
+		}
+		data, err := os.ReadFile(listPath + ".sha256")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := emptyObjectSHA + "  " + files[0] + "\n" + helloSHA + "  " + files[1] + "\n"
+		if string(data) != want {
+			t.Fatalf("expected manifest %q, got %q", want, data)
+		}
+		if _, ok := writes["FILE_HASHES"]; ok {
+			t.Fatal("FILE_HASHES must not be written with FILES_LIST_PATH")
+		}
+	})
+
+	t.Run("digest tracks content changes", func(t *testing.T) {
+		digest := func() string {
+			var got string
+			if err := writeFileHashes(config{HashFiles: true}, files, func(key, value string) bool {
+				if key == "FILES_DIGEST" {
+					got = value
+				}
+				return true
+			}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return got
+		}
+
+		before := digest()
+		if err := os.WriteFile(filepath.FromSlash(files[1]), []byte("changed"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if after := digest(); after == before {
+			t.Fatal("expected FILES_DIGEST to change with file contents")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := writeFileHashes(config{HashFiles: true}, []string{"does/not/exist.json"}, func(string, string) bool { return true })
+		if err == nil || !strings.Contains(err.Error(), "cannot hash") {
+			t.Fatalf("expected hashing error, got %v", err)
+		}
+	})
+
+	t.Run("output failure", func(t *testing.T) {
+		err := writeFileHashes(config{HashFiles: true}, files, func(key, _ string) bool { return key != "FILES_DIGEST" })
+		if err == nil || !strings.Contains(err.Error(), "cannot write FILES_DIGEST to GITHUB_OUTPUT") {
+			t.Fatalf("expected output error, got %v", err)
+		}
+	})
+}
+
+func TestHashManifest(t *testing.T) {
+	got := hashManifest([]string{"b.json", "a.json"}, map[string]string{"a.json": "aa", "b.json": "bb"})
+	if want := "bb  b.json\naa  a.json\n"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
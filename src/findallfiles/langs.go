@@ -0,0 +1,20 @@
+package findallfiles
+
+// langSet builds a lookup set from a list of languages.
+func langSet(langs []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(langs))
+	for _, l := range langs {
+		set[l] = struct{}{}
+	}
+	return set
+}
+
+// langWanted reports whether files in lang are collected: every language not
+// in skipLangs when pushing all languages, otherwise only the base language.
+func langWanted(lang string, cfg config, skipLangs map[string]struct{}) bool {
+	if cfg.AllLangs {
+		_, skip := skipLangs[lang]
+		return !skip
+	}
+	return lang == cfg.BaseLang
+}
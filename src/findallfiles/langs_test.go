@@ -0,0 +1,27 @@
+package findallfiles
+
+import "testing"
+
+func TestLangWanted(t *testing.T) {
+	skip := langSet([]string{"de"})
+
+	tests := []struct {
+		name string
+		lang string
+		cfg  config
+		want bool
+	}{
+		{name: "base language", lang: "en", cfg: config{BaseLang: "en"}, want: true},
+		{name: "other language", lang: "fr", cfg: config{BaseLang: "en"}, want: false},
+		{name: "all languages", lang: "fr", cfg: config{BaseLang: "en", AllLangs: true}, want: true},
+		{name: "skipped language", lang: "de", cfg: config{BaseLang: "en", AllLangs: true}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := langWanted(tt.lang, tt.cfg, skip); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
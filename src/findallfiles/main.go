@@ -0,0 +1,102 @@
+package findallfiles
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/ghoutput"
+	"lokalise-push-action/internal/logging"
+)
+
+// exitFunc is a function variable that defaults to os.Exit.
+// Overridable in tests to assert exit behavior without terminating the process.
+var exitFunc = os.Exit
+
+// logs is the logger of the command; Main configures it from the environment.
+var logs = logging.New(os.Stderr, os.Stderr)
+
+// Main collects every translation file to push, configured through environment variables.
+// It exits the process on failure.
+func Main() {
+	if err := logs.Configure(); err != nil {
+		returnWithError(err.Error())
+	}
+	if err := run(); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// Check reads and validates the configuration of Main without running it.
+func Check() error {
+	_, err := validateEnvironment()
+	return envconf.Join([]error{logs.Configure(), err})
+}
+
+func run() error {
+	return runWith(
+		validateEnvironment,
+		findAllTranslationFiles,
+		processAllFiles,
+		ghoutput.Writer(logs),
+	)
+}
+
+type findFunc func(config) ([]string, error)
+
+func runWith(
+	validate func() (config, error),
+	find findFunc,
+	process func(config, []string, func(string, string) bool) error,
+	write func(string, string) bool,
+) error {
+	// Read and validate required env variables.
+	cfg, err := validate()
+	if err != nil {
+		return err
+	}
+
+	// Discover files according to the selected strategy.
+	allFiles, err := find(cfg)
+	if err != nil {
+		return fmt.Errorf("unable to find translation files: %w", err)
+	}
+
+	// In strict mode an empty result means a misconfiguration, not "nothing to push".
+	// With CHANGED_SINCE an empty result just means nothing changed.
+	if len(allFiles) == 0 && cfg.FailIfEmpty && cfg.ChangedSince == "" {
+		return fmt.Errorf(
+			"no translation files found in %s for base language %q; check translations_path, base_lang, file_ext and name_pattern (fail_if_empty is enabled)",
+			strings.Join(cfg.Paths, ", "), cfg.BaseLang,
+		)
+	}
+
+	// Guard against overly broad patterns uploading thousands of unrelated files.
+	if cfg.MaxFiles > 0 && len(allFiles) > cfg.MaxFiles {
+		return fmt.Errorf(
+			"found %d translation files, more than max_files (%d); this usually means translations_path or name_pattern matches non-locale files (e.g. \"**/*.json\" at the repository root). Narrow them down or raise max_files",
+			len(allFiles), cfg.MaxFiles,
+		)
+	}
+
+	// Keep only this job's share of the files when the push is split across jobs.
+	if cfg.ShardCount > 1 {
+		total := len(allFiles)
+		allFiles = shardFiles(allFiles, cfg.ShardCount, cfg.ShardIndex)
+		logs.Infof("Shard %d of %d: %d of %d files", cfg.ShardIndex+1, cfg.ShardCount, len(allFiles), total)
+	}
+
+	// Write outputs for downstream workflow steps.
+	if err := process(cfg, allFiles, write); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// returnWithError prints an error and exits with a non-zero code.
+func returnWithError(message string) {
+	logs.Errorf("%s", message)
+	exitFunc(1)
+}
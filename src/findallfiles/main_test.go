@@ -1,4 +1,4 @@
-package main
+package findallfiles
 
 import (
 	"errors"
@@ -135,29 +135,17 @@ func TestRunWith(t *testing.T) {
 			return wantCfg, nil
 		}
 
-		find := func(paths []string, flatNaming bool, baseLang string, fileExts []string, namePattern string) ([]string, error) {
+		find := func(cfg config) ([]string, error) {
 			findCalled = true
 
-			if !reflect.DeepEqual(paths, wantCfg.Paths) {
-				t.Fatalf("paths mismatch. want=%v got=%v", wantCfg.Paths, paths)
-			}
-			if flatNaming != wantCfg.FlatNaming {
-				t.Fatalf("flatNaming mismatch. want=%v got=%v", wantCfg.FlatNaming, flatNaming)
-			}
-			if baseLang != wantCfg.BaseLang {
-				t.Fatalf("baseLang mismatch. want=%q got=%q", wantCfg.BaseLang, baseLang)
-			}
-			if !reflect.DeepEqual(fileExts, wantCfg.FileExts) {
-				t.Fatalf("fileExts mismatch. want=%v got=%v", wantCfg.FileExts, fileExts)
-			}
-			if namePattern != wantCfg.NamePattern {
-				t.Fatalf("namePattern mismatch. want=%q got=%q", wantCfg.NamePattern, namePattern)
+			if !reflect.DeepEqual(cfg, wantCfg) {
+				t.Fatalf("config mismatch. want=%#v got=%#v", wantCfg, cfg)
 			}
 
 			return wantFiles, nil
 		}
 
-		process := func(allFiles []string, writeOutput func(string, string) bool) error {
+		process := func(_ config, allFiles []string, writeOutput func(string, string) bool) error {
 			processCalled = true
 
 			if !reflect.DeepEqual(allFiles, wantFiles) {
@@ -202,12 +190,12 @@ func TestRunWith(t *testing.T) {
 			return config{}, errors.New("bad env")
 		}
 
-		find := func([]string, bool, string, []string, string) ([]string, error) {
+		find := func(config) ([]string, error) {
 			t.Fatal("find should not be called")
 			return nil, nil
 		}
 
-		process := func([]string, func(string, string) bool) error {
+		process := func(config, []string, func(string, string) bool) error {
 			t.Fatal("process should not be called")
 			return nil
 		}
@@ -239,11 +227,11 @@ func TestRunWith(t *testing.T) {
 			}, nil
 		}
 
-		find := func([]string, bool, string, []string, string) ([]string, error) {
+		find := func(config) ([]string, error) {
 			return nil, errors.New("glob exploded")
 		}
 
-		process := func([]string, func(string, string) bool) error {
+		process := func(config, []string, func(string, string) bool) error {
 			t.Fatal("process should not be called")
 			return nil
 		}
@@ -280,11 +268,11 @@ func TestRunWith(t *testing.T) {
 			}, nil
 		}
 
-		find := func([]string, bool, string, []string, string) ([]string, error) {
+		find := func(config) ([]string, error) {
 			return wantFiles, nil
 		}
 
-		process := func(allFiles []string, writeOutput func(string, string) bool) error {
+		process := func(_ config, allFiles []string, writeOutput func(string, string) bool) error {
 			if !reflect.DeepEqual(allFiles, wantFiles) {
 				t.Fatalf("allFiles mismatch. want=%v got=%v", wantFiles, allFiles)
 			}
@@ -304,4 +292,147 @@ func TestRunWith(t *testing.T) {
 			t.Fatalf("expected error containing %q, got %q", "cannot write ALL_FILES to GITHUB_OUTPUT", err.Error())
 		}
 	})
+
+	t.Run("fails on empty result when FAIL_IF_EMPTY is set", func(t *testing.T) {
+		t.Parallel()
+
+		validate := func() (config, error) {
+			return config{Paths: []string{"locales", "i18n"}, BaseLang: "en", FailIfEmpty: true}, nil
+		}
+
+		find := func(config) ([]string, error) {
+			return nil, nil
+		}
+
+		process := func(config, []string, func(string, string) bool) error {
+			t.Fatal("process should not be called")
+			return nil
+		}
+
+		write := func(string, string) bool {
+			t.Fatal("write should not be called")
+			return true
+		}
+
+		err := runWith(validate, find, process, write)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		for _, want := range []string{"no translation files found", "locales, i18n", `"en"`} {
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("expected error containing %q, got %q", want, err.Error())
+			}
+		}
+	})
+
+	t.Run("empty result is not an error by default or with CHANGED_SINCE", func(t *testing.T) {
+		t.Parallel()
+
+		for _, cfg := range []config{
+			{Paths: []string{"locales"}, BaseLang: "en"},
+			{Paths: []string{"locales"}, BaseLang: "en", FailIfEmpty: true, ChangedSince: "origin/main"},
+		} {
+			processCalled := false
+			validate := func() (config, error) {
+				return cfg, nil
+			}
+			find := func(config) ([]string, error) {
+				return nil, nil
+			}
+			process := func(config, []string, func(string, string) bool) error {
+				processCalled = true
+				return nil
+			}
+
+			if err := runWith(validate, find, process, func(string, string) bool { return true }); err != nil {
+				t.Fatalf("%+v: unexpected error: %v", cfg, err)
+			}
+			if !processCalled {
+				t.Fatalf("%+v: process was not called", cfg)
+			}
+		}
+	})
+
+	t.Run("enforces MAX_FILES", func(t *testing.T) {
+		t.Parallel()
+
+		files := []string{"a.json", "b.json", "c.json"}
+		find := func(config) ([]string, error) {
+			return files, nil
+		}
+
+		for _, tt := range []struct {
+			max     int
+			wantErr bool
+		}{
+			{max: 2, wantErr: true},
+			{max: 3},
+			{max: 0},
+		} {
+			validate := func() (config, error) {
+				return config{Paths: []string{"."}, BaseLang: "en", MaxFiles: tt.max}, nil
+			}
+			processCalled := false
+			process := func(config, []string, func(string, string) bool) error {
+				processCalled = true
+				return nil
+			}
+
+			err := runWith(validate, find, process, func(string, string) bool { return true })
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "found 3 translation files, more than max_files (2)") {
+					t.Fatalf("max=%d: expected MAX_FILES error, got %v", tt.max, err)
+				}
+				if processCalled {
+					t.Fatalf("max=%d: process should not be called", tt.max)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("max=%d: unexpected error: %v", tt.max, err)
+			}
+			if !processCalled {
+				t.Fatalf("max=%d: process was not called", tt.max)
+			}
+		}
+	})
+
+	t.Run("passes only the selected shard to process", func(t *testing.T) {
+		t.Parallel()
+
+		validate := func() (config, error) {
+			return config{Paths: []string{"."}, BaseLang: "en", MaxFiles: 3, ShardCount: 2, ShardIndex: 1}, nil
+		}
+		find := func(config) ([]string, error) {
+			return []string{"a.json", "b.json", "c.json"}, nil
+		}
+
+		var got []string
+		process := func(_ config, files []string, _ func(string, string) bool) error {
+			got = files
+			return nil
+		}
+
+		if err := runWith(validate, find, process, func(string, string) bool { return true }); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"c.json"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	})
+}
+
+func TestCheck(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("TRANSLATIONS_PATH", "")
+
+	err := Check()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"invalid LOG_LEVEL", "TRANSLATIONS_PATH"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %q", want, err.Error())
+		}
+	}
 }
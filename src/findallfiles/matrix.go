@@ -0,0 +1,91 @@
+package findallfiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxMatrixJobs is the most jobs GitHub allows a single matrix to generate.
+const maxMatrixJobs = 256
+
+// matrixEntry is one job of the MATRIX output.
+type matrixEntry struct {
+	Index int    `json:"index"`
+	Files string `json:"files"` // comma-joined, encoded like ALL_FILES
+	Count int    `json:"count"`
+	Bytes int64  `json:"bytes"`
+}
+
+// writeMatrix emits MATRIX, a strategy.matrix value ({"include":[...]}) that splits
+// the collected files into chunks of at most MATRIX_CHUNK_FILES files and
+// MATRIX_CHUNK_BYTES bytes. Nothing is written unless a limit is set or no files
+// were collected.
+func writeMatrix(cfg config, files []string, writeOutput func(key, value string) bool) error {
+	if (cfg.MatrixChunkFiles == 0 && cfg.MatrixChunkBytes == 0) || len(files) == 0 {
+		return nil
+	}
+
+	chunks, err := chunkFiles(files, cfg.MatrixChunkFiles, cfg.MatrixChunkBytes)
+	if err != nil {
+		return err
+	}
+	if len(chunks) > maxMatrixJobs {
+		return fmt.Errorf("cannot build MATRIX: %d chunks exceed GitHub's limit of %d jobs; raise matrix_chunk_files or matrix_chunk_bytes", len(chunks), maxMatrixJobs)
+	}
+
+	encoded := encodeEntries(files, cfg.FilesEncoding)
+	start := 0
+	for i := range chunks {
+		chunks[i].Index = i
+		chunks[i].Files = strings.Join(encoded[start:start+chunks[i].Count], ",")
+		start += chunks[i].Count
+	}
+
+	matrix := struct {
+		Include []matrixEntry `json:"include"`
+	}{Include: chunks}
+
+	matrixJSON, err := json.Marshal(matrix)
+	if err != nil {
+		return fmt.Errorf("cannot encode MATRIX: %w", err)
+	}
+	if !writeOutput("MATRIX", string(matrixJSON)) {
+		return fmt.Errorf("cannot write MATRIX to GITHUB_OUTPUT")
+	}
+	return nil
+}
+
+// chunkFiles groups consecutive files so that no chunk holds more than maxFiles
+// files or maxBytes bytes; a zero limit is not enforced. A file larger than
+// maxBytes gets a chunk of its own. Only Count and Bytes of the result are set.
+func chunkFiles(files []string, maxFiles int, maxBytes int64) ([]matrixEntry, error) {
+	var chunks []matrixEntry
+	var cur matrixEntry
+
+	for _, f := range files {
+		var size int64
+		if maxBytes > 0 {
+			info, err := os.Stat(filepath.FromSlash(f))
+			if err != nil {
+				return nil, fmt.Errorf("cannot stat %q: %w", f, err)
+			}
+			size = info.Size()
+		}
+
+		full := maxFiles > 0 && cur.Count >= maxFiles
+		overflow := maxBytes > 0 && cur.Bytes+size > maxBytes
+		if cur.Count > 0 && (full || overflow) {
+			chunks = append(chunks, cur)
+			cur = matrixEntry{}
+		}
+		cur.Count++
+		cur.Bytes += size
+	}
+	if cur.Count > 0 {
+		chunks = append(chunks, cur)
+	}
+	return chunks, nil
+}
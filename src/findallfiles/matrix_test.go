@@ -0,0 +1,124 @@
+package findallfiles
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeSizedFiles creates files of the given sizes in a temp dir and returns their paths.
+func writeSizedFiles(t *testing.T, sizes ...int) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var files []string
+	for i, size := range sizes {
+		fp := filepath.Join(dir, string(rune('a'+i))+".json")
+		if err := os.WriteFile(fp, []byte(strings.Repeat("x", size)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, filepath.ToSlash(fp))
+	}
+	return files
+}
+
+func TestChunkFiles(t *testing.T) {
+	files := writeSizedFiles(t, 10, 20, 30, 100, 5)
+
+	tests := []struct {
+		name     string
+		maxFiles int
+		maxBytes int64
+		want     []matrixEntry
+	}{
+		{
+			name:     "by count",
+			maxFiles: 2,
+			want:     []matrixEntry{{Count: 2}, {Count: 2}, {Count: 1}},
+		},
+		{
+			name:     "by size, oversized file alone",
+			maxBytes: 60,
+			want:     []matrixEntry{{Count: 3, Bytes: 60}, {Count: 1, Bytes: 100}, {Count: 1, Bytes: 5}},
+		},
+		{
+			name:     "both limits",
+			maxFiles: 2,
+			maxBytes: 200,
+			want:     []matrixEntry{{Count: 2, Bytes: 30}, {Count: 2, Bytes: 130}, {Count: 1, Bytes: 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := chunkFiles(files, tt.maxFiles, tt.maxBytes)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := chunkFiles([]string{"missing.json"}, 0, 10); err == nil || !strings.Contains(err.Error(), "cannot stat") {
+		t.Fatalf("expected stat error, got %v", err)
+	}
+}
+
+func TestWriteMatrix(t *testing.T) {
+	files := writeSizedFiles(t, 10, 20, 30)
+
+	writes := make(map[string]string)
+	write := func(key, value string) bool {
+		writes[key] = value
+		return true
+	}
+
+	if err := writeMatrix(config{}, files, write); err != nil || len(writes) != 0 {
+		t.Fatalf("expected no output without limits, got %v (%v)", writes, err)
+	}
+	if err := writeMatrix(config{MatrixChunkFiles: 2}, nil, write); err != nil || len(writes) != 0 {
+		t.Fatalf("expected no output without files, got %v (%v)", writes, err)
+	}
+
+	if err := writeMatrix(config{MatrixChunkFiles: 2}, files, write); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got struct {
+		Include []matrixEntry `json:"include"`
+	}
+	if err := json.Unmarshal([]byte(writes["MATRIX"]), &got); err != nil {
+		t.Fatalf("invalid MATRIX %q: %v", writes["MATRIX"], err)
+	}
+	want := []matrixEntry{
+		{Index: 0, Files: files[0] + "," + files[1], Count: 2},
+		{Index: 1, Files: files[2], Count: 1},
+	}
+	if !reflect.DeepEqual(got.Include, want) {
+		t.Fatalf("expected %+v, got %+v", want, got.Include)
+	}
+
+	// Entries follow ALL_FILES_ENCODING.
+	if err := writeMatrix(config{MatrixChunkFiles: 1, FilesEncoding: encodingURL}, []string{"a b.json"}, write); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(writes["MATRIX"], `"files":"a%20b.json"`) {
+		t.Fatalf("expected encoded entry, got %s", writes["MATRIX"])
+	}
+
+	many := make([]string, maxMatrixJobs+1)
+	for i := range many {
+		many[i] = "f.json"
+	}
+	if err := writeMatrix(config{MatrixChunkFiles: 1}, many, write); err == nil || !strings.Contains(err.Error(), "exceed GitHub's limit of 256 jobs") {
+		t.Fatalf("expected job limit error, got %v", err)
+	}
+
+	if err := writeMatrix(config{MatrixChunkFiles: 5}, files, func(string, string) bool { return false }); err == nil {
+		t.Fatal("expected write error")
+	}
+}
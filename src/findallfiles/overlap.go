@@ -0,0 +1,58 @@
+package findallfiles
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"lokalise-push-action/internal/logging"
+)
+
+// maxOverlapExamples caps how many duplicate files are listed per warning.
+const maxOverlapExamples = 10
+
+// rootPair identifies two TRANSLATIONS_PATH entries, in configuration order.
+type rootPair struct {
+	First, Second string
+}
+
+// rootOverlap lists the files matched by both roots of a pair.
+type rootOverlap struct {
+	Roots rootPair
+	Files []string
+}
+
+// overlapping returns the files matched by more than one root, grouped by
+// root pair and sorted for deterministic output.
+func (c *fileCollector) overlapping() []rootOverlap {
+	out := make([]rootOverlap, 0, len(c.overlaps))
+	for pair, files := range c.overlaps {
+		files = append([]string(nil), files...)
+		sort.Strings(files)
+		out = append(out, rootOverlap{Roots: pair, Files: files})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Roots.First != out[j].Roots.First {
+			return out[i].Roots.First < out[j].Roots.First
+		}
+		return out[i].Roots.Second < out[j].Roots.Second
+	})
+	return out
+}
+
+// warnOverlaps logs a warning for every pair of roots that matched the same
+// files. Duplicates are dropped from the result either way, but overlapping
+// roots usually point at a configuration mistake.
+func warnOverlaps(overlaps []rootOverlap) {
+	for _, o := range overlaps {
+		listed := o.Files
+		more := ""
+		if len(listed) > maxOverlapExamples {
+			more = fmt.Sprintf(" (and %d more)", len(listed)-maxOverlapExamples)
+			listed = listed[:maxOverlapExamples]
+		}
+		logs.WarnAt(logging.Location{Title: "Overlapping translation paths"},
+			"TRANSLATIONS_PATH entries %q and %q both match %d file(s): %s%s",
+			o.Roots.First, o.Roots.Second, len(o.Files), strings.Join(listed, ", "), more)
+	}
+}
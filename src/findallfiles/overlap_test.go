@@ -0,0 +1,67 @@
+package findallfiles
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFileCollector_Overlapping(t *testing.T) {
+	c := newFileCollector()
+
+	c.forRoot("locales")
+	c.add("locales/en/b.json")
+	c.add("locales/en/a.json")
+	c.add("locales/en/a.json") // repeated within the same root is not an overlap
+
+	c.forRoot("locales/en")
+	c.add("locales/en/a.json")
+	c.add("locales/en/b.json")
+	c.add("locales/en/c.json")
+
+	c.forRoot("i18n")
+	c.add("locales/en/c.json")
+
+	if got, want := c.sorted(), []string{"locales/en/a.json", "locales/en/b.json", "locales/en/c.json"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	want := []rootOverlap{
+		{Roots: rootPair{"locales", "locales/en"}, Files: []string{"locales/en/a.json", "locales/en/b.json"}},
+		{Roots: rootPair{"locales/en", "i18n"}, Files: []string{"locales/en/c.json"}},
+	}
+	if got := c.overlapping(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestWarnOverlaps(t *testing.T) {
+	buf := captureLogs(t)
+	warnOverlaps(nil)
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output, got %q", buf.String())
+	}
+
+	var many []string
+	for i := range maxOverlapExamples + 2 {
+		many = append(many, fmt.Sprintf("a/%02d.json", i))
+	}
+	warnOverlaps([]rootOverlap{
+		{Roots: rootPair{"a", "a/b"}, Files: []string{"a/b/100%.json", "a/b/x\ny.json"}},
+		{Roots: rootPair{"a", "."}, Files: many},
+	})
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 annotations, got %q", buf.String())
+	}
+
+	wantFirst := `::warning title=Overlapping translation paths::TRANSLATIONS_PATH entries "a" and "a/b" both match 2 file(s): a/b/100%25.json, a/b/x%0Ay.json`
+	if lines[0] != wantFirst {
+		t.Fatalf("expected %q, got %q", wantFirst, lines[0])
+	}
+	if !strings.Contains(lines[1], "both match 12 file(s)") || !strings.HasSuffix(lines[1], "a/09.json (and 2 more)") {
+		t.Fatalf("unexpected truncated annotation: %q", lines[1])
+	}
+}
@@ -0,0 +1,198 @@
+package findallfiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"lokalise-push-action/internal/ghoutput"
+)
+
+// Entry encodings accepted by ALL_FILES_ENCODING.
+const (
+	encodingPlain = "plain" // Entries are written as is (default).
+	encodingURL   = "url"   // Entries are percent-encoded, so delimiters never appear inside them.
+	encodingNUL   = "nul"   // List file entries are NUL-terminated; requires FILES_LIST_PATH.
+)
+
+// processAllFiles emits GitHub Action outputs. ALL_FILES is comma-joined for the
+// upload step; ALL_FILES_JSON carries the same list as a JSON array so paths with
+// commas or spaces can be consumed safely (e.g. via fromJSON in workflows).
+//
+// When FILES_LIST_PATH is set, the list is written to that file instead and only
+// its path and the file count are emitted, keeping large lists out of GITHUB_OUTPUT.
+//
+// LANGS_FOUND, a JSON array of the languages detected under the translation roots,
+// is emitted in both modes, and so is FILE_LANG_MAP_PATH, the file mapping each
+// file whose language can be inferred to that language (see writeFileLangMap).
+//
+// FILES_COUNT, TOTAL_BYTES, and LARGEST_FILE summarize the collected files (see writeFileStats).
+// With HASH_FILES, per-file SHA-256 digests are emitted too (see writeFileHashes),
+// DETECT_DUPLICATES reports files with identical content (see writeDuplicates),
+// CHECK_ENCODING flags files that are not plain UTF-8 (see writeEncodingWarnings),
+// and MATRIX_CHUNK_FILES or MATRIX_CHUNK_BYTES add a ready-made job matrix (see writeMatrix).
+//
+// ALL_FILES_ENCODING=url percent-encodes every entry of ALL_FILES and the list file;
+// ALL_FILES_JSON, FILE_LANG_MAP, and hashes always carry the raw paths.
+func processAllFiles(cfg config, allFiles []string, writeOutput func(key, value string) bool) error {
+	if err := writeLangsFound(cfg, writeOutput); err != nil {
+		return err
+	}
+
+	if cfg.FilesListPath != "" {
+		if err := writeFilesList(cfg.FilesListPath, allFiles, cfg.FilesEncoding, writeOutput); err != nil {
+			return err
+		}
+		if err := writeFileLangMap(cfg, allFiles, writeOutput); err != nil {
+			return err
+		}
+		if err := writeFileStats(allFiles, writeOutput); err != nil {
+			return err
+		}
+		if err := writeFileHashes(cfg, allFiles, writeOutput); err != nil {
+			return err
+		}
+		if err := writeDuplicates(cfg, allFiles, writeOutput); err != nil {
+			return err
+		}
+		if err := writeEncodingWarnings(cfg, allFiles); err != nil {
+			return err
+		}
+		return writeMatrix(cfg, allFiles, writeOutput)
+	}
+
+	if len(allFiles) == 0 {
+		if !writeOutput("has_files", "false") {
+			return fmt.Errorf("cannot write has_files to GITHUB_OUTPUT")
+		}
+		return nil
+	}
+
+	if !writeOutput("ALL_FILES", strings.Join(encodeEntries(allFiles, cfg.FilesEncoding), ",")) {
+		return fmt.Errorf("cannot write ALL_FILES to GITHUB_OUTPUT")
+	}
+
+	filesJSON, err := json.Marshal(allFiles)
+	if err != nil {
+		return fmt.Errorf("cannot encode ALL_FILES_JSON: %w", err)
+	}
+	if !writeOutput("ALL_FILES_JSON", string(filesJSON)) {
+		return fmt.Errorf("cannot write ALL_FILES_JSON to GITHUB_OUTPUT")
+	}
+
+	if err := writeFileLangMap(cfg, allFiles, writeOutput); err != nil {
+		return err
+	}
+
+	if err := writeFileStats(allFiles, writeOutput); err != nil {
+		return err
+	}
+
+	if err := writeFileHashes(cfg, allFiles, writeOutput); err != nil {
+		return err
+	}
+
+	if err := writeDuplicates(cfg, allFiles, writeOutput); err != nil {
+		return err
+	}
+
+	if err := writeEncodingWarnings(cfg, allFiles); err != nil {
+		return err
+	}
+
+	if err := writeMatrix(cfg, allFiles, writeOutput); err != nil {
+		return err
+	}
+
+	if !writeOutput("has_files", "true") {
+		return fmt.Errorf("cannot write has_files to GITHUB_OUTPUT")
+	}
+
+	return nil
+}
+
+// writeLangsFound emits LANGS_FOUND, a JSON array of the languages detected under
+// the translation roots. Nothing is written when no language was detected.
+func writeLangsFound(cfg config, writeOutput func(key, value string) bool) error {
+	langs, err := detectLangs(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot detect languages: %w", err)
+	}
+	if len(langs) == 0 {
+		return nil
+	}
+
+	langsJSON, err := json.Marshal(langs)
+	if err != nil {
+		return fmt.Errorf("cannot encode LANGS_FOUND: %w", err)
+	}
+	if !writeOutput("LANGS_FOUND", string(langsJSON)) {
+		return fmt.Errorf("cannot write LANGS_FOUND to GITHUB_OUTPUT")
+	}
+	return nil
+}
+
+// writeFilesList writes allFiles to path, one per line (or NUL-terminated with the
+// nul encoding), emits ALL_FILES_PATH, ALL_FILES_COUNT, and has_files, and
+// exports the path as LOKALISE_FILES_LIST.
+// The file is written even when no files were found.
+func writeFilesList(path string, allFiles []string, encoding string, writeOutput func(key, value string) bool) error {
+	sep := "\n"
+	if encoding == encodingNUL {
+		sep = "\x00"
+	}
+
+	var b strings.Builder
+	for _, f := range encodeEntries(allFiles, encoding) {
+		if sep == "\n" && strings.ContainsAny(f, "\r\n") {
+			return fmt.Errorf("cannot write %q to FILES_LIST_PATH: newline-delimited entries cannot contain line breaks", f)
+		}
+		b.WriteString(f)
+		b.WriteString(sep)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("cannot create directory for FILES_LIST_PATH: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("cannot write FILES_LIST_PATH: %w", err)
+	}
+
+	outputs := [][2]string{
+		{"ALL_FILES_PATH", path},
+		{"ALL_FILES_COUNT", strconv.Itoa(len(allFiles))},
+		{"has_files", strconv.FormatBool(len(allFiles) > 0)},
+	}
+	for _, o := range outputs {
+		if !writeOutput(o[0], o[1]) {
+			return fmt.Errorf("cannot write %s to GITHUB_OUTPUT", o[0])
+		}
+	}
+
+	// Later steps of the job, including the user's own, can read the list
+	// without threading the output through.
+	if err := ghoutput.ExportEnv("LOKALISE_FILES_LIST", path); err != nil {
+		return fmt.Errorf("cannot export LOKALISE_FILES_LIST: %w", err)
+	}
+
+	return nil
+}
+
+// encodeEntries applies the url encoding; other encodings keep entries as is.
+func encodeEntries(files []string, encoding string) []string {
+	if encoding != encodingURL {
+		return files
+	}
+
+	out := make([]string, len(files))
+	for i, f := range files {
+		out[i] = url.PathEscape(f)
+	}
+	return out
+}
@@ -0,0 +1,443 @@
+package findallfiles
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/ghoutput"
+)
+
+func TestProcessAllFiles(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          []string
+		failOnKey      string
+		wantWrites     map[string]string
+		wantWriteOrder []string
+		wantErr        string
+	}{
+		{
+			name:  "Files found",
+			input: []string{"file1", "file2"},
+			wantWrites: map[string]string{
+				"ALL_FILES":      "file1,file2",
+				"ALL_FILES_JSON": `["file1","file2"]`,
+				"FILES_COUNT":    "2",
+				"TOTAL_BYTES":    "0",
+				"has_files":      "true",
+			},
+			wantWriteOrder: []string{"ALL_FILES", "ALL_FILES_JSON", "FILES_COUNT", "TOTAL_BYTES", "has_files"},
+		},
+		{
+			name:  "No files found",
+			input: []string{},
+			wantWrites: map[string]string{
+				"has_files": "false",
+			},
+			wantWriteOrder: []string{"has_files"},
+		},
+		{
+			name:           "WriteOutput fails on ALL_FILES",
+			input:          []string{"file1", "file2"},
+			failOnKey:      "ALL_FILES",
+			wantErr:        "cannot write ALL_FILES to GITHUB_OUTPUT",
+			wantWriteOrder: []string{"ALL_FILES"},
+		},
+		{
+			name:           "WriteOutput fails on has_files true",
+			input:          []string{"file1", "file2"},
+			failOnKey:      "has_files",
+			wantErr:        "cannot write has_files to GITHUB_OUTPUT",
+			wantWriteOrder: []string{"ALL_FILES", "ALL_FILES_JSON", "FILES_COUNT", "TOTAL_BYTES", "has_files"},
+			wantWrites: map[string]string{
+				"ALL_FILES":      "file1,file2",
+				"ALL_FILES_JSON": `["file1","file2"]`,
+				"FILES_COUNT":    "2",
+				"TOTAL_BYTES":    "0",
+			},
+		},
+		{
+			name:           "WriteOutput fails on has_files false",
+			input:          []string{},
+			failOnKey:      "has_files",
+			wantErr:        "cannot write has_files to GITHUB_OUTPUT",
+			wantWriteOrder: []string{"has_files"},
+		},
+		{
+			name:  "Nil input behaves like no files",
+			input: nil,
+			wantWrites: map[string]string{
+				"has_files": "false",
+			},
+			wantWriteOrder: []string{"has_files"},
+		},
+		{
+			name:  "Preserves input order in ALL_FILES",
+			input: []string{"b.json", "a.json", "c.json"},
+			wantWrites: map[string]string{
+				"ALL_FILES":      "b.json,a.json,c.json",
+				"ALL_FILES_JSON": `["b.json","a.json","c.json"]`,
+				"FILES_COUNT":    "3",
+				"TOTAL_BYTES":    "0",
+				"has_files":      "true",
+			},
+			wantWriteOrder: []string{"ALL_FILES", "ALL_FILES_JSON", "FILES_COUNT", "TOTAL_BYTES", "has_files"},
+		},
+		{
+			name:           "WriteOutput fails on ALL_FILES_JSON",
+			input:          []string{"file1"},
+			failOnKey:      "ALL_FILES_JSON",
+			wantErr:        "cannot write ALL_FILES_JSON to GITHUB_OUTPUT",
+			wantWriteOrder: []string{"ALL_FILES", "ALL_FILES_JSON"},
+			wantWrites: map[string]string{
+				"ALL_FILES": "file1",
+			},
+		},
+		{
+			name:  "JSON output keeps commas, spaces, and newlines intact",
+			input: []string{"locales/a, b.json", "locales/new\nline.json"},
+			wantWrites: map[string]string{
+				"ALL_FILES":      "locales/a, b.json,locales/new\nline.json",
+				"ALL_FILES_JSON": `["locales/a, b.json","locales/new\nline.json"]`,
+				"FILES_COUNT":    "2",
+				"TOTAL_BYTES":    "0",
+				"has_files":      "true",
+			},
+			wantWriteOrder: []string{"ALL_FILES", "ALL_FILES_JSON", "FILES_COUNT", "TOTAL_BYTES", "has_files"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			writes := make(map[string]string)
+			var order []string
+
+			mockWrite := func(key, value string) bool {
+				order = append(order, key)
+				if tt.failOnKey == key {
+					return false
+				}
+				writes[key] = value
+				return true
+			}
+
+			err := processAllFiles(config{}, tt.input, mockWrite)
+
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error mismatch. want substring=%q got=%q", tt.wantErr, err.Error())
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			wantWrites := tt.wantWrites
+			if wantWrites == nil {
+				wantWrites = map[string]string{}
+			}
+
+			if !reflect.DeepEqual(writes, wantWrites) {
+				t.Fatalf("writes mismatch. want=%v got=%v", wantWrites, writes)
+			}
+			if !reflect.DeepEqual(order, tt.wantWriteOrder) {
+				t.Fatalf("write order mismatch. want=%v got=%v", tt.wantWriteOrder, order)
+			}
+		})
+	}
+}
+
+func TestProcessAllFiles_FileLangMap(t *testing.T) {
+	files := []string{"locales/en/app.json", "locales/fr/app.json", "locales/README.json"}
+	const want = `{"locales/en/app.json":"en","locales/fr/app.json":"fr"}`
+
+	readMap := func(t *testing.T, path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("cannot read the language map: %v", err)
+		}
+		return string(data)
+	}
+
+	t.Run("temp file and output without a files list", func(t *testing.T) {
+		dir := t.TempDir()
+		cfg := config{Paths: []string{"locales"}, TempDir: dir}
+
+		writes := make(map[string]string)
+		var order []string
+		err := processAllFiles(cfg, files, func(key, value string) bool {
+			order = append(order, key)
+			writes[key] = value
+			return true
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if writes["FILE_LANG_MAP"] != want {
+			t.Fatalf("expected FILE_LANG_MAP %s, got %s", want, writes["FILE_LANG_MAP"])
+		}
+		path := writes["FILE_LANG_MAP_PATH"]
+		if filepath.Dir(path) != dir {
+			t.Fatalf("expected the map under %s, got %q", dir, path)
+		}
+		if got := readMap(t, path); got != want {
+			t.Fatalf("expected map %s, got %s", want, got)
+		}
+		if want := []string{"ALL_FILES", "ALL_FILES_JSON", "FILE_LANG_MAP", "FILE_LANG_MAP_PATH", "FILES_COUNT", "TOTAL_BYTES", "has_files"}; !reflect.DeepEqual(order, want) {
+			t.Fatalf("write order mismatch. want=%v got=%v", want, order)
+		}
+
+		for _, key := range []string{"FILE_LANG_MAP", "FILE_LANG_MAP_PATH"} {
+			err = processAllFiles(cfg, files, func(k, _ string) bool { return k != key })
+			if err == nil || !strings.Contains(err.Error(), "cannot write "+key+" to GITHUB_OUTPUT") {
+				t.Fatalf("expected %s write error, got %v", key, err)
+			}
+		}
+	})
+
+	t.Run("next to the files list", func(t *testing.T) {
+		listPath := filepath.Join(t.TempDir(), "files.txt")
+		cfg := config{Paths: []string{"locales"}, FilesListPath: listPath}
+
+		writes := make(map[string]string)
+		if err := processAllFiles(cfg, files, func(key, value string) bool {
+			writes[key] = value
+			return true
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if path := writes["FILE_LANG_MAP_PATH"]; path != listPath+fileLangMapSuffix {
+			t.Fatalf("expected the map next to the list, got %q", path)
+		}
+		if got := readMap(t, listPath+fileLangMapSuffix); got != want {
+			t.Fatalf("expected map %s, got %s", want, got)
+		}
+		if _, ok := writes["FILE_LANG_MAP"]; ok {
+			t.Fatal("FILE_LANG_MAP must not be written with a files list")
+		}
+	})
+
+	t.Run("nothing without inferred languages", func(t *testing.T) {
+		writes := make(map[string]string)
+		if err := processAllFiles(config{Paths: []string{"locales"}, TempDir: t.TempDir()}, []string{"locales/README.json"}, func(key, value string) bool {
+			writes[key] = value
+			return true
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := writes["FILE_LANG_MAP_PATH"]; ok {
+			t.Fatalf("unexpected FILE_LANG_MAP_PATH: %v", writes)
+		}
+	})
+}
+
+func TestProcessAllFiles_LangsFound(t *testing.T) {
+	cfg := config{Paths: []string{filepath.Join(baseTestDir, "nested")}, BaseLang: "en", FileExts: []string{"json"}}
+
+	writes := make(map[string]string)
+	var order []string
+	err := processAllFiles(cfg, nil, func(key, value string) bool {
+		order = append(order, key)
+		writes[key] = value
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if writes["LANGS_FOUND"] != `["en","es"]` {
+		t.Fatalf("expected LANGS_FOUND [\"en\",\"es\"], got %s", writes["LANGS_FOUND"])
+	}
+	if want := []string{"LANGS_FOUND", "has_files"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("write order mismatch. want=%v got=%v", want, order)
+	}
+
+	err = processAllFiles(cfg, nil, func(key, value string) bool { return key != "LANGS_FOUND" })
+	if err == nil || !strings.Contains(err.Error(), "cannot write LANGS_FOUND to GITHUB_OUTPUT") {
+		t.Fatalf("expected LANGS_FOUND write error, got %v", err)
+	}
+}
+
+func TestProcessAllFiles_Matrix(t *testing.T) {
+	files := []string{"locales/en/a.json", "locales/en/b.json", "locales/en/c.json"}
+
+	for _, listPath := range []string{"", filepath.Join(t.TempDir(), "files.txt")} {
+		writes := make(map[string]string)
+		var order []string
+		err := processAllFiles(config{MatrixChunkFiles: 2, FilesListPath: listPath}, files, func(key, value string) bool {
+			order = append(order, key)
+			writes[key] = value
+			return true
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := `{"include":[{"index":0,"files":"locales/en/a.json,locales/en/b.json","count":2,"bytes":0},{"index":1,"files":"locales/en/c.json","count":1,"bytes":0}]}`
+		if writes["MATRIX"] != want {
+			t.Fatalf("expected MATRIX %s, got %s", want, writes["MATRIX"])
+		}
+		if listPath == "" && order[len(order)-1] != "has_files" {
+			t.Fatalf("expected has_files to be written last, got %v", order)
+		}
+	}
+}
+
+func TestProcessAllFiles_FilesListPath(t *testing.T) {
+	t.Run("writes list and emits path and count", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "files.txt")
+		writes := make(map[string]string)
+		var order []string
+
+		err := processAllFiles(config{FilesListPath: path}, []string{"locales/a, b.json", "locales/en.json"}, func(key, value string) bool {
+			order = append(order, key)
+			writes[key] = value
+			return true
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("cannot read list: %v", err)
+		}
+		if got, want := string(data), "locales/a, b.json\nlocales/en.json\n"; got != want {
+			t.Fatalf("list mismatch. want=%q got=%q", want, got)
+		}
+
+		wantWrites := map[string]string{"FILES_COUNT": "2", "TOTAL_BYTES": "0", "ALL_FILES_PATH": path, "ALL_FILES_COUNT": "2", "has_files": "true"}
+		if !reflect.DeepEqual(writes, wantWrites) {
+			t.Fatalf("writes mismatch. want=%v got=%v", wantWrites, writes)
+		}
+		if want := []string{"ALL_FILES_PATH", "ALL_FILES_COUNT", "has_files", "FILES_COUNT", "TOTAL_BYTES"}; !reflect.DeepEqual(order, want) {
+			t.Fatalf("write order mismatch. want=%v got=%v", want, order)
+		}
+	})
+
+	t.Run("exports the list path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "files.txt")
+		env := filepath.Join(t.TempDir(), "env")
+		if err := os.WriteFile(env, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("GITHUB_ENV", env)
+
+		if err := processAllFiles(config{FilesListPath: path}, []string{"locales/en.json"}, func(string, string) bool { return true }); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := os.ReadFile(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := ghoutput.Parse(string(data)); err != nil || got["LOKALISE_FILES_LIST"] != path {
+			t.Fatalf("expected LOKALISE_FILES_LIST=%q, got %v (%v)", path, got, err)
+		}
+	})
+
+	t.Run("no files writes an empty list", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "files.txt")
+		writes := make(map[string]string)
+
+		err := processAllFiles(config{FilesListPath: path}, nil, func(key, value string) bool {
+			writes[key] = value
+			return true
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if data, err := os.ReadFile(path); err != nil || len(data) != 0 {
+			t.Fatalf("expected empty list, got %q (err=%v)", data, err)
+		}
+		if writes["ALL_FILES_COUNT"] != "0" || writes["has_files"] != "false" {
+			t.Fatalf("unexpected writes: %v", writes)
+		}
+	})
+
+	t.Run("rejects paths with line breaks", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "files.txt")
+
+		err := processAllFiles(config{FilesListPath: path}, []string{"bad\nname.json"}, func(string, string) bool {
+			t.Fatal("write should not be called")
+			return true
+		})
+		if err == nil || !strings.Contains(err.Error(), "cannot contain line breaks") {
+			t.Fatalf("expected line break error, got %v", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("list must not be written, stat err=%v", err)
+		}
+	})
+
+	t.Run("output failure", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "files.txt")
+
+		err := processAllFiles(config{FilesListPath: path}, []string{"a.json"}, func(key, _ string) bool {
+			return key != "ALL_FILES_COUNT"
+		})
+		if err == nil || !strings.Contains(err.Error(), "cannot write ALL_FILES_COUNT to GITHUB_OUTPUT") {
+			t.Fatalf("expected output error, got %v", err)
+		}
+	})
+}
+
+func TestProcessAllFiles_Encoding(t *testing.T) {
+	files := []string{"locales/a, b.json", "locales/it's\nnew.json"}
+
+	t.Run("url encodes ALL_FILES but not ALL_FILES_JSON", func(t *testing.T) {
+		writes := make(map[string]string)
+
+		err := processAllFiles(config{FilesEncoding: encodingURL}, files, func(key, value string) bool {
+			writes[key] = value
+			return true
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := "locales%2Fa%2C%20b.json,locales%2Fit%27s%0Anew.json"; writes["ALL_FILES"] != want {
+			t.Fatalf("ALL_FILES mismatch. want=%q got=%q", want, writes["ALL_FILES"])
+		}
+		if want := `["locales/a, b.json","locales/it's\nnew.json"]`; writes["ALL_FILES_JSON"] != want {
+			t.Fatalf("ALL_FILES_JSON mismatch. want=%q got=%q", want, writes["ALL_FILES_JSON"])
+		}
+	})
+
+	t.Run("url encodes list file entries", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "files.txt")
+
+		err := processAllFiles(config{FilesListPath: path, FilesEncoding: encodingURL}, files, func(string, string) bool { return true })
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, _ := os.ReadFile(path)
+		if want := "locales%2Fa%2C%20b.json\nlocales%2Fit%27s%0Anew.json\n"; string(data) != want {
+			t.Fatalf("list mismatch. want=%q got=%q", want, data)
+		}
+	})
+
+	t.Run("nul terminates raw list file entries", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "files.txt")
+
+		err := processAllFiles(config{FilesListPath: path, FilesEncoding: encodingNUL}, files, func(string, string) bool { return true })
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, _ := os.ReadFile(path)
+		if want := "locales/a, b.json\x00locales/it's\nnew.json\x00"; string(data) != want {
+			t.Fatalf("list mismatch. want=%q got=%q", want, data)
+		}
+	})
+}
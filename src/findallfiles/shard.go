@@ -0,0 +1,18 @@
+package findallfiles
+
+// shardFiles splits files into count contiguous shards of near-equal size and
+// returns the one at index. Earlier shards get the extra file when the split is
+// uneven, so every file lands in exactly one shard for a given count.
+func shardFiles(files []string, count, index int) []string {
+	if count <= 1 {
+		return files
+	}
+
+	size, extra := len(files)/count, len(files)%count
+	start := index*size + min(index, extra)
+	end := start + size
+	if index < extra {
+		end++
+	}
+	return files[start:end]
+}
@@ -0,0 +1,42 @@
+package findallfiles
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestShardFiles(t *testing.T) {
+	files := []string{"a", "b", "c", "d", "e", "f", "g"}
+
+	tests := []struct {
+		count int
+		want  [][]string
+	}{
+		{count: 1, want: [][]string{files}},
+		{count: 2, want: [][]string{{"a", "b", "c", "d"}, {"e", "f", "g"}}},
+		{count: 3, want: [][]string{{"a", "b", "c"}, {"d", "e"}, {"f", "g"}}},
+		{count: 7, want: [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}, {"f"}, {"g"}}},
+		{count: 9, want: [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}, {"f"}, {"g"}, {}, {}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d shards", tt.count), func(t *testing.T) {
+			var all []string
+			for i, want := range tt.want {
+				got := shardFiles(files, tt.count, i)
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("shard %d: expected %v, got %v", i, want, got)
+				}
+				all = append(all, got...)
+			}
+			if !reflect.DeepEqual(all, files) {
+				t.Fatalf("shards do not cover every file once: %v", all)
+			}
+		})
+	}
+
+	if got := shardFiles(nil, 3, 2); len(got) != 0 {
+		t.Fatalf("expected empty shard, got %v", got)
+	}
+}
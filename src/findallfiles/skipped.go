@@ -0,0 +1,143 @@
+package findallfiles
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lokalise-push-action/internal/logging"
+)
+
+// maxSkipAnnotations caps the warnings for skipped files; the rest are
+// summarized in a final one.
+const maxSkipAnnotations = 50
+
+// skippedFile is a file left out of the result along with the reason shown to
+// users when ANNOTATE_SKIPPED is enabled.
+type skippedFile struct {
+	File   string
+	Reason string
+}
+
+// warnSkipped logs a warning, shown as a GitHub annotation, for every skipped
+// file, so a file that "wasn't pushed" explains itself in the workflow run.
+func warnSkipped(skipped []skippedFile) {
+	for i, s := range skipped {
+		if i == maxSkipAnnotations {
+			logs.WarnAt(logging.Location{Title: "Skipped translation files"},
+				"%d more file(s) were skipped without an annotation", len(skipped)-i)
+			return
+		}
+		logs.WarnAt(logging.Location{File: s.File, Title: "Skipped translation file"}, "%s", s.Reason)
+	}
+}
+
+// findNearMisses reports entries that look like base-language translation files
+// but don't match the configured layout: a <baseLang> file or, in nested roots,
+// a file under <baseLang>/ with an extension missing from FILE_EXT, and names
+// matching BASE_LANG only up to letter case. Roots using NAME_PATTERN or
+// NAME_REGEX, and PUSH_ALL_LANGS, are not inspected.
+func findNearMisses(cfg config) ([]skippedFile, error) {
+	if cfg.NameRegex != nil || cfg.AllLangs {
+		return nil, nil
+	}
+
+	opts := walkOptions{
+		MaxDepth:       cfg.MaxDepth,
+		FollowSymlinks: cfg.FollowSymlinks,
+		SkipVendorDirs: !cfg.IncludeVendorDirs,
+	}
+
+	var out []skippedFile
+	for _, root := range cfg.Paths {
+		if cfg.nameRuleFor(root).Pattern != "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("error reading directory %q: %w", root, err)
+		}
+
+		flat, fileExts := cfg.flatNamingFor(root), cfg.fileExtsFor(root)
+		for _, entry := range entries {
+			fp := filepath.Join(root, entry.Name())
+			mode, ok, err := resolveEntry(fp, entry, opts)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+
+			reason := nearMissReason(entry.Name(), mode.IsDir(), flat, fileExts, cfg.BaseLang)
+			if reason == "" || (mode.IsDir() && sameFile(fp, filepath.Join(root, cfg.BaseLang))) {
+				// On case-insensitive filesystems the folder is collected anyway.
+				continue
+			}
+			out = append(out, skippedFile{File: filepath.ToSlash(fp), Reason: reason})
+		}
+
+		if !flat {
+			misses, err := unlistedExtensions(filepath.Join(root, cfg.BaseLang), fileExts, opts)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, misses...)
+		}
+	}
+	return out, nil
+}
+
+// nearMissReason explains why a root entry that resembles the base-language
+// layout is not collected, or returns "" when it doesn't resemble it.
+func nearMissReason(name string, isDir, flat bool, fileExts []string, baseLang string) string {
+	switch {
+	case flat && !isDir:
+		ext := filepath.Ext(name)
+		stem := strings.TrimSuffix(name, ext)
+		if stem == baseLang && ext != "" && !hasMatchingExtension(name, fileExts) {
+			return fmt.Sprintf("Not pushed: extension %q is not listed in file_ext (%s)", ext, strings.Join(fileExts, ", "))
+		}
+		if stem != baseLang && strings.EqualFold(stem, baseLang) && hasMatchingExtension(name, fileExts) {
+			return fmt.Sprintf("Not pushed: file name %q matches base_lang %q only when ignoring letter case", name, baseLang)
+		}
+	case !flat && isDir:
+		if name != baseLang && strings.EqualFold(name, baseLang) {
+			return fmt.Sprintf("Not pushed: folder name %q matches base_lang %q only when ignoring letter case", name, baseLang)
+		}
+	}
+	return ""
+}
+
+// sameFile reports whether both paths exist and point to the same file.
+func sameFile(a, b string) bool {
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	ib, err := os.Stat(b)
+	return err == nil && os.SameFile(ia, ib)
+}
+
+// unlistedExtensions reports files under the nested language directory whose
+// extension is missing from FILE_EXT. Dotfiles and files without an extension
+// are ignored.
+func unlistedExtensions(langDir string, fileExts []string, opts walkOptions) ([]skippedFile, error) {
+	var out []skippedFile
+	err := walkFiles(langDir, opts, func(fp string) {
+		ext := filepath.Ext(fp)
+		if ext == "" || strings.HasPrefix(filepath.Base(fp), ".") || hasMatchingExtension(fp, fileExts) {
+			return
+		}
+		out = append(out, skippedFile{
+			File:   filepath.ToSlash(fp),
+			Reason: fmt.Sprintf("Not pushed: extension %q is not listed in file_ext (%s)", ext, strings.Join(fileExts, ", ")),
+		})
+	})
+	return out, err
+}
@@ -0,0 +1,139 @@
+package findallfiles
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/logging"
+	"lokalise-push-action/internal/namepattern"
+)
+
+// captureLogs redirects the package logger to a buffer for the duration of the
+// test, rendering warnings and errors as GitHub annotations.
+func captureLogs(t *testing.T) *strings.Builder {
+	t.Helper()
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+
+	var buf strings.Builder
+	orig := logs
+	t.Cleanup(func() { logs = orig })
+	logs = logging.New(&buf, &buf)
+	if err := logs.Configure(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestWarnSkipped(t *testing.T) {
+	out := captureLogs(t)
+	warnSkipped([]skippedFile{
+		{File: "locales/en,1.yml", Reason: "Not pushed: extension \".yml\" is not listed in file_ext (json)"},
+	})
+
+	want := "::warning file=locales/en%2C1.yml,title=Skipped translation file::Not pushed: extension \".yml\" is not listed in file_ext (json)\n"
+	if out.String() != want {
+		t.Fatalf("expected %q, got %q", want, out.String())
+	}
+}
+
+func TestWarnSkipped_Cap(t *testing.T) {
+	var skipped []skippedFile
+	for i := range maxSkipAnnotations + 3 {
+		skipped = append(skipped, skippedFile{File: fmt.Sprintf("locales/%d.json", i), Reason: "Not pushed"})
+	}
+
+	out := captureLogs(t)
+	warnSkipped(skipped)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != maxSkipAnnotations+1 {
+		t.Fatalf("expected %d lines, got %d", maxSkipAnnotations+1, len(lines))
+	}
+	if want := "::warning title=Skipped translation files::3 more file(s) were skipped without an annotation"; lines[len(lines)-1] != want {
+		t.Fatalf("expected %q, got %q", want, lines[len(lines)-1])
+	}
+}
+
+func TestFindNearMisses(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, f := range []string{
+		"flat/en.json",
+		"flat/en.yml",
+		"flat/EN.json",
+		"flat/fr.yml",
+		"nested/en/app.json",
+		"nested/en/app.yaml",
+		"nested/en/.DS_Store",
+		"nested/en/LICENSE",
+		"nested/En/extra.json",
+		"pattern/en.yml",
+	} {
+		if err := os.MkdirAll(filepath.Dir(f), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config{
+		Paths:             []string{"flat", "nested", "pattern", "missing"},
+		BaseLang:          "en",
+		FileExts:          []string{"json"},
+		FlatNamingByRoot:  map[string]bool{"flat": true},
+		NamePatternByRoot: map[string]namepattern.Rule{"pattern": {Pattern: "*.yml"}},
+	}
+
+	got, err := findNearMisses(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var files []string
+	for _, s := range got {
+		files = append(files, s.File)
+	}
+	want := []string{"flat/EN.json", "flat/en.yml", "nested/En", "nested/en/app.yaml"}
+	if sameFile("nested/En", "nested/en") {
+		// Case-insensitive filesystem: both names refer to one folder.
+		want = []string{"flat/EN.json", "flat/en.yml", "nested/en/app.yaml"}
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("expected %v, got %v", want, files)
+	}
+	if !strings.Contains(got[1].Reason, `extension ".yml" is not listed in file_ext (json)`) {
+		t.Fatalf("unexpected reason %q", got[1].Reason)
+	}
+	if !strings.Contains(got[0].Reason, `"EN.json" matches base_lang "en" only when ignoring letter case`) {
+		t.Fatalf("unexpected reason %q", got[0].Reason)
+	}
+}
+
+func TestFindNearMisses_NotInspected(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.MkdirAll("locales", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("locales/en.yml", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	base := config{Paths: []string{"locales"}, BaseLang: "en", FileExts: []string{"json"}, FlatNaming: true}
+	for name, cfg := range map[string]config{
+		"all langs": func() config { c := base; c.AllLangs = true; return c }(),
+		"pattern":   func() config { c := base; c.NamePattern = "*.json"; return c }(),
+	} {
+		got, err := findNearMisses(cfg)
+		if err != nil || len(got) != 0 {
+			t.Fatalf("%s: expected no near misses, got %v (%v)", name, got, err)
+		}
+	}
+}
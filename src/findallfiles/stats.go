@@ -0,0 +1,88 @@
+package findallfiles
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// writeFileStats emits FILES_COUNT, TOTAL_BYTES, and LARGEST_FILE for the collected
+// files so workflows can react to large pushes. Files that disappeared since
+// discovery are counted but contribute no bytes. Nothing is written without files.
+func writeFileStats(files []string, writeOutput func(key, value string) bool) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	total, largest, err := fileStats(files)
+	if err != nil {
+		return err
+	}
+
+	outputs := [][2]string{
+		{"FILES_COUNT", strconv.Itoa(len(files))},
+		{"TOTAL_BYTES", strconv.FormatInt(total, 10)},
+	}
+	if largest != "" {
+		outputs = append(outputs, [2]string{"LARGEST_FILE", largest})
+	}
+	for _, o := range outputs {
+		if !writeOutput(o[0], o[1]) {
+			return fmt.Errorf("cannot write %s to GITHUB_OUTPUT", o[0])
+		}
+	}
+	return nil
+}
+
+// fileStats returns the combined size of files and the largest one; ties keep
+// the first file in list order.
+func fileStats(files []string) (int64, string, error) {
+	var total, largestSize int64
+	largest := ""
+
+	for _, f := range files {
+		info, err := os.Stat(filepath.FromSlash(f))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return 0, "", fmt.Errorf("cannot stat %q: %w", f, err)
+		}
+
+		total += info.Size()
+		if largest == "" || info.Size() > largestSize {
+			largest, largestSize = f, info.Size()
+		}
+	}
+	return total, largest, nil
+}
+
+// filterBySize drops files smaller than minBytes or larger than maxBytes; a zero
+// limit is not enforced. It returns the kept files and the dropped ones.
+func filterBySize(files []string, minBytes, maxBytes int64) ([]string, []skippedFile, error) {
+	kept := files[:0:0]
+	var skipped []skippedFile
+	for _, f := range files {
+		info, err := os.Stat(filepath.FromSlash(f))
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot stat %q: %w", f, err)
+		}
+
+		switch size := info.Size(); {
+		case size < minBytes:
+			skipped = append(skipped, skippedFile{
+				File:   f,
+				Reason: fmt.Sprintf("Not pushed: %d bytes is below min_file_bytes (%d)", size, minBytes),
+			})
+		case maxBytes > 0 && size > maxBytes:
+			skipped = append(skipped, skippedFile{
+				File:   f,
+				Reason: fmt.Sprintf("Not pushed: %d bytes exceeds max_file_bytes (%d)", size, maxBytes),
+			})
+		default:
+			kept = append(kept, f)
+		}
+	}
+	return kept, skipped, nil
+}
@@ -0,0 +1,102 @@
+package findallfiles
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteFileStats(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for name, size := range map[string]int{"en.json": 10, "fr.json": 25, "de.json": 25} {
+		fp := filepath.Join(dir, name)
+		if err := os.WriteFile(fp, []byte(strings.Repeat("x", size)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"en.json", "fr.json", "gone.json", "de.json"} {
+		files = append(files, filepath.ToSlash(filepath.Join(dir, name)))
+	}
+
+	writes := make(map[string]string)
+	if err := writeFileStats(files, func(key, value string) bool {
+		writes[key] = value
+		return true
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{"FILES_COUNT": "4", "TOTAL_BYTES": "60", "LARGEST_FILE": files[1]}
+	if !reflect.DeepEqual(writes, want) {
+		t.Fatalf("expected %v, got %v", want, writes)
+	}
+
+	if err := writeFileStats(nil, func(key, _ string) bool {
+		t.Fatalf("unexpected write of %s", key)
+		return true
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := writeFileStats(files, func(key, _ string) bool { return key != "LARGEST_FILE" })
+	if err == nil || !strings.Contains(err.Error(), "cannot write LARGEST_FILE to GITHUB_OUTPUT") {
+		t.Fatalf("expected output error, got %v", err)
+	}
+}
+
+func TestFilterBySize(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for _, f := range []struct {
+		name string
+		size int
+	}{{"empty.json", 0}, {"stub.json", 2}, {"en.json", 40}, {"blob.json", 500}} {
+		fp := filepath.Join(dir, f.name)
+		if err := os.WriteFile(fp, []byte(strings.Repeat("x", f.size)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, filepath.ToSlash(fp))
+	}
+
+	tests := []struct {
+		name        string
+		min, max    int64
+		wantKept    []string
+		wantSkipped int
+	}{
+		{name: "no limits", wantKept: files},
+		{name: "min only", min: 3, wantKept: files[2:], wantSkipped: 2},
+		{name: "max only", max: 100, wantKept: files[:3], wantSkipped: 1},
+		{name: "both limits are inclusive", min: 2, max: 40, wantKept: files[1:3], wantSkipped: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, skipped, err := filterBySize(files, tt.min, tt.max)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(kept, tt.wantKept) || len(skipped) != tt.wantSkipped {
+				t.Fatalf("expected %v (%d skipped), got %v (%v skipped)", tt.wantKept, tt.wantSkipped, kept, skipped)
+			}
+		})
+	}
+
+	_, skipped, err := filterBySize(files, 2, 40)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []skippedFile{
+		{File: files[0], Reason: "Not pushed: 0 bytes is below min_file_bytes (2)"},
+		{File: files[3], Reason: "Not pushed: 500 bytes exceeds max_file_bytes (40)"},
+	}
+	if !reflect.DeepEqual(skipped, want) {
+		t.Fatalf("expected %v, got %v", want, skipped)
+	}
+
+	if _, _, err := filterBySize([]string{filepath.ToSlash(filepath.Join(dir, "gone.json"))}, 1, 0); err == nil || !strings.Contains(err.Error(), "cannot stat") {
+		t.Fatalf("expected stat error, got %v", err)
+	}
+}
@@ -0,0 +1,524 @@
+package findallfiles
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/bodrovis/lokalise-actions-common/v2/normalizers"
+	"github.com/bodrovis/lokalise-actions-common/v2/parsers"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/manifest"
+	"lokalise-push-action/internal/namepattern"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+type config struct {
+	Paths             []string
+	BaseLang          string
+	FileExts          []string
+	FileExtsByRoot    map[string][]string
+	NamePattern       string
+	NameExcludes      []string
+	NamePatternByRoot map[string]namepattern.Rule
+	NameRegex         *regexp.Regexp
+	FlatNaming        bool
+	FlatNamingByRoot  map[string]bool
+	AllLangs          bool
+	SkipLangs         []string
+	ExcludePatterns   []string
+	DiscoveryMode     string
+	FilesListPath     string
+	FilesEncoding     string
+	TempDir           string
+	MaxDepth          int
+	FollowSymlinks    bool
+	IncludeVendorDirs bool
+	HashFiles         bool
+	FailIfEmpty       bool
+	MaxFiles          int
+	MinFileBytes      int64
+	MaxFileBytes      int64
+	ChangedSince      string
+	ShardCount        int
+	ShardIndex        int
+	MatrixChunkFiles  int
+	MatrixChunkBytes  int64
+	DetectDuplicates  bool
+	CheckEncoding     bool
+	IncludeSubmodules bool
+	AnnotateSkipped   bool
+}
+
+// flatNamingFor reports whether root uses the flat layout, honoring per-root
+// FLAT_NAMING values when given.
+func (c config) flatNamingFor(root string) bool {
+	if flat, ok := c.FlatNamingByRoot[filepath.ToSlash(root)]; ok {
+		return flat
+	}
+	return c.FlatNaming
+}
+
+// fileExtsFor returns the FILE_EXT extensions of root, honoring the per-root
+// mapping when given.
+func (c config) fileExtsFor(root string) []string {
+	if c.FileExtsByRoot != nil {
+		return c.FileExtsByRoot[filepath.ToSlash(root)]
+	}
+	return c.FileExts
+}
+
+// nameRuleFor returns the NAME_PATTERN rule for root, honoring per-root
+// patterns when given. An empty pattern means the default layout applies.
+func (c config) nameRuleFor(root string) namepattern.Rule {
+	if c.NamePatternByRoot != nil {
+		return c.NamePatternByRoot[filepath.ToSlash(root)]
+	}
+	return namepattern.Rule{Pattern: c.NamePattern, Excludes: c.NameExcludes}
+}
+
+// validateEnvironment enforces presence of required inputs and normalizes them.
+// Every invalid variable is reported, not just the first one, so a workflow
+// can be fixed in one pass; checks that depend on an invalid value are skipped.
+func validateEnvironment() (config, error) {
+	// A manifest replaces the root and layout env variables.
+	m, err := manifest.ParseFile()
+	if err != nil {
+		return config{}, err
+	}
+
+	var errs []error
+
+	var paths []string
+	if m != nil {
+		paths = m.Paths
+	} else if paths, err = parseTranslationsPaths(); err != nil {
+		errs = append(errs, err)
+	}
+	pathsOK := err == nil
+
+	baseLang, err := parsers.ParseLangEnv("BASE_LANG")
+	errs = append(errs, err)
+	baseLangOK := err == nil
+
+	var (
+		fileExts       []string
+		fileExtsByRoot map[string][]string
+	)
+	if m != nil && len(m.FileExts) > 0 {
+		fileExts = m.FileExts
+	} else if pathsOK {
+		fileExts, fileExtsByRoot, err = parseFileExtensions(paths)
+		errs = append(errs, err)
+	}
+
+	var (
+		defaultRule       namepattern.Rule
+		namePatternByRoot map[string]namepattern.Rule
+		namePatternOK     = true
+	)
+	if m != nil {
+		namePatternByRoot = m.NamePatternByRoot
+	} else if pathsOK {
+		defaultRule, namePatternByRoot, err = namepattern.ParseEnv(paths, pathnorm.EnsureRepoRelativePath)
+		errs = append(errs, err)
+		namePatternOK = err == nil
+	}
+
+	nameRegex, err := namepattern.ParseRegexEnv()
+	errs = append(errs, err)
+	if nameRegex != nil && namePatternOK && (defaultRule.Pattern != "" || namePatternByRoot != nil) {
+		errs = append(errs, fmt.Errorf("NAME_REGEX and NAME_PATTERN cannot be used together"))
+	}
+
+	var (
+		flatNaming       bool
+		flatNamingByRoot map[string]bool
+	)
+	if m != nil {
+		flatNamingByRoot = m.FlatNamingByRoot
+	} else {
+		flatNaming, flatNamingByRoot, err = envconf.ParseFlatNaming(pathnorm.EnsureRepoRelativePath)
+		errs = append(errs, err)
+	}
+
+	allLangs, err := envconf.ParseBoolEnv("PUSH_ALL_LANGS")
+	errs = append(errs, err)
+
+	var skipLangs []string
+	if baseLangOK {
+		skipLangs, err = parseSkipLangs(baseLang)
+		errs = append(errs, err)
+	}
+
+	excludePatterns, err := parseExcludePatterns()
+	errs = append(errs, err)
+
+	discoveryMode, err := parseDiscoveryMode()
+	errs = append(errs, err)
+
+	filesListPath := parseFilesListPath()
+
+	filesEncoding, err := parseFilesEncoding(filesListPath)
+	errs = append(errs, err)
+
+	maxDepth, err := parseMaxDepth()
+	errs = append(errs, err)
+
+	followSymlinks, err := envconf.ParseBoolEnv("FOLLOW_SYMLINKS")
+	errs = append(errs, err)
+
+	includeVendorDirs, err := envconf.ParseBoolEnv("INCLUDE_VENDOR_DIRS")
+	errs = append(errs, err)
+
+	hashFiles, err := envconf.ParseBoolEnv("HASH_FILES")
+	errs = append(errs, err)
+
+	detectDuplicates, err := envconf.ParseBoolEnv("DETECT_DUPLICATES")
+	errs = append(errs, err)
+
+	checkEncoding, err := envconf.ParseBoolEnv("CHECK_ENCODING")
+	errs = append(errs, err)
+
+	includeSubmodules, err := envconf.ParseBoolEnv("INCLUDE_SUBMODULES")
+	errs = append(errs, err)
+
+	annotateSkipped, err := envconf.ParseBoolEnv("ANNOTATE_SKIPPED")
+	errs = append(errs, err)
+
+	failIfEmpty, err := envconf.ParseBoolEnv("FAIL_IF_EMPTY")
+	errs = append(errs, err)
+
+	maxFiles, err := parseMaxFiles()
+	errs = append(errs, err)
+
+	minFileBytes, minErr := parseSizeEnv("MIN_FILE_BYTES")
+	maxFileBytes, maxErr := parseSizeEnv("MAX_FILE_BYTES")
+	errs = append(errs, minErr, maxErr)
+	if minErr == nil && maxErr == nil && maxFileBytes > 0 && minFileBytes > maxFileBytes {
+		errs = append(errs, fmt.Errorf("invalid file size limits: MIN_FILE_BYTES (%d) exceeds MAX_FILE_BYTES (%d)", minFileBytes, maxFileBytes))
+	}
+
+	changedSince, err := parseChangedSince()
+	errs = append(errs, err)
+
+	shardCount, shardIndex, err := parseShard()
+	errs = append(errs, err)
+
+	matrixChunkFiles, err := parseLimit("MATRIX_CHUNK_FILES", "files")
+	errs = append(errs, err)
+	matrixChunkBytes, err := parseSizeEnv("MATRIX_CHUNK_BYTES")
+	errs = append(errs, err)
+
+	if err := envconf.Join(errs); err != nil {
+		return config{}, err
+	}
+
+	return config{
+		Paths:             paths,
+		BaseLang:          baseLang,
+		FileExts:          fileExts,
+		FileExtsByRoot:    fileExtsByRoot,
+		NamePattern:       defaultRule.Pattern,
+		NameExcludes:      defaultRule.Excludes,
+		NamePatternByRoot: namePatternByRoot,
+		NameRegex:         nameRegex,
+		FlatNaming:        flatNaming,
+		FlatNamingByRoot:  flatNamingByRoot,
+		AllLangs:          allLangs,
+		SkipLangs:         skipLangs,
+		ExcludePatterns:   excludePatterns,
+		DiscoveryMode:     discoveryMode,
+		FilesListPath:     filesListPath,
+		FilesEncoding:     filesEncoding,
+		TempDir:           strings.TrimSpace(os.Getenv("RUNNER_TEMP")),
+		MaxDepth:          maxDepth,
+		FollowSymlinks:    followSymlinks,
+		IncludeVendorDirs: includeVendorDirs,
+		HashFiles:         hashFiles,
+		FailIfEmpty:       failIfEmpty,
+		MaxFiles:          maxFiles,
+		MinFileBytes:      minFileBytes,
+		MaxFileBytes:      maxFileBytes,
+		ChangedSince:      changedSince,
+		ShardCount:        shardCount,
+		ShardIndex:        shardIndex,
+		MatrixChunkFiles:  int(matrixChunkFiles),
+		MatrixChunkBytes:  matrixChunkBytes,
+		DetectDuplicates:  detectDuplicates,
+		CheckEncoding:     checkEncoding,
+		IncludeSubmodules: includeSubmodules,
+		AnnotateSkipped:   annotateSkipped,
+	}, nil
+}
+
+// parseExcludePatterns reads newline-separated EXCLUDE_PATTERNS globs.
+// Patterns are matched against repo-relative paths, so they are cleaned
+// the same way as translation roots and checked for doublestar syntax.
+// EXCLUDE_PATHS entries exclude a file or a directory with everything below it.
+func parseExcludePatterns() ([]string, error) {
+	raw := parsers.ParseStringArrayEnv("EXCLUDE_PATTERNS")
+	paths := envconf.SplitListEnv("EXCLUDE_PATHS")
+	if len(raw) == 0 && len(paths) == 0 {
+		return nil, nil
+	}
+
+	patterns := make([]string, 0, len(raw)+2*len(paths))
+	for _, p := range raw {
+		clean, err := pathnorm.EnsureRepoRelativePattern(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EXCLUDE_PATTERNS: %w", err)
+		}
+		clean = filepath.ToSlash(clean)
+		if !doublestar.ValidatePattern(clean) {
+			return nil, fmt.Errorf("invalid EXCLUDE_PATTERNS: malformed pattern %q", p)
+		}
+		patterns = append(patterns, clean)
+	}
+
+	for _, p := range paths {
+		clean, err := pathnorm.EnsureRepoRelativePath(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EXCLUDE_PATHS: %w", err)
+		}
+		if clean == "." {
+			return nil, fmt.Errorf("invalid EXCLUDE_PATHS: %q would exclude the whole repository", p)
+		}
+		path := filepath.ToSlash(clean)
+		patterns = append(patterns, path, path+"/**")
+	}
+	return patterns, nil
+}
+
+// parseDiscoveryMode reads DISCOVERY_MODE, defaulting to the filesystem walk.
+func parseDiscoveryMode() (string, error) {
+	return envconf.ParseEnumEnv("DISCOVERY_MODE", []string{discoveryFilesystem, discoveryGit}, discoveryFilesystem)
+}
+
+// parseChangedSince reads the optional CHANGED_SINCE git ref. Refs starting with
+// "-" are rejected so they cannot be mistaken for git options.
+func parseChangedSince() (string, error) {
+	ref := strings.TrimSpace(os.Getenv("CHANGED_SINCE"))
+	if strings.HasPrefix(ref, "-") || strings.ContainsAny(ref, " \t\r\n") {
+		return "", fmt.Errorf("invalid CHANGED_SINCE: %q is not a git ref", ref)
+	}
+	return ref, nil
+}
+
+// parseShard reads SHARD_COUNT and the zero-based SHARD_INDEX. Without
+// SHARD_COUNT every file belongs to the single shard 0.
+func parseShard() (int, int, error) {
+	count, index := 1, 0
+
+	if raw := strings.TrimSpace(os.Getenv("SHARD_COUNT")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return 0, 0, fmt.Errorf("invalid SHARD_COUNT: expected a positive integer, got %q", raw)
+		}
+		count = n
+	}
+
+	if raw := strings.TrimSpace(os.Getenv("SHARD_INDEX")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 || n >= count {
+			return 0, 0, fmt.Errorf("invalid SHARD_INDEX: expected an integer from 0 to %d, got %q", count-1, raw)
+		}
+		index = n
+	}
+
+	return count, index, nil
+}
+
+// parseFilesListPath reads the optional FILES_LIST_PATH. Absolute paths are allowed
+// so the list can live outside the repository (e.g. in the runner's temp directory).
+func parseFilesListPath() string {
+	p := strings.TrimSpace(os.Getenv("FILES_LIST_PATH"))
+	if p == "" {
+		return ""
+	}
+	return filepath.Clean(p)
+}
+
+// parseFilesEncoding reads ALL_FILES_ENCODING. NUL-delimited entries cannot be
+// stored in step outputs, so the nul encoding requires FILES_LIST_PATH.
+func parseFilesEncoding(filesListPath string) (string, error) {
+	encoding, err := envconf.ParseEnumEnv("ALL_FILES_ENCODING", []string{encodingPlain, encodingURL, encodingNUL}, encodingPlain)
+	if err != nil {
+		return "", err
+	}
+	if encoding == encodingNUL && filesListPath == "" {
+		return "", fmt.Errorf("invalid ALL_FILES_ENCODING: %q requires FILES_LIST_PATH", encodingNUL)
+	}
+	return encoding, nil
+}
+
+// parseMaxDepth reads MAX_DEPTH for nested walks; empty or 0 means unlimited.
+func parseMaxDepth() (int, error) {
+	raw := strings.TrimSpace(os.Getenv("MAX_DEPTH"))
+	if raw == "" {
+		return 0, nil
+	}
+	depth, err := strconv.Atoi(raw)
+	if err != nil || depth < 0 {
+		return 0, fmt.Errorf("invalid MAX_DEPTH: expected a non-negative integer, got %q", raw)
+	}
+	return depth, nil
+}
+
+// defaultMaxFiles is the MAX_FILES cap applied when the variable is unset.
+const defaultMaxFiles = 10000
+
+// parseMaxFiles reads MAX_FILES, the most files discovery may match before
+// aborting; empty means defaultMaxFiles and 0 disables the cap.
+func parseMaxFiles() (int, error) {
+	raw := strings.TrimSpace(os.Getenv("MAX_FILES"))
+	if raw == "" {
+		return defaultMaxFiles, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid MAX_FILES: expected a non-negative integer, got %q", raw)
+	}
+	return limit, nil
+}
+
+// parseLimit reads an optional non-negative limit counting unit (e.g. "files");
+// empty or 0 means no limit.
+func parseLimit(key, unit string) (int64, error) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return 0, nil
+	}
+	limit, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid %s: expected a non-negative number of %s, got %q", key, unit, raw)
+	}
+	return limit, nil
+}
+
+// sizeUnits maps the suffixes parseSizeEnv accepts (lowercased) to their
+// multipliers: KB/MB/GB are decimal, KiB/MiB/GiB binary.
+var sizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"kb":  1000,
+	"mb":  1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+}
+
+// parseSizeEnv reads an optional non-negative byte size from key, either a
+// plain number of bytes or a whole number with a unit such as "512KiB" or
+// "10MB" (case-insensitive, optional space); empty or 0 means no limit.
+func parseSizeEnv(key string) (int64, error) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return 0, nil
+	}
+
+	digits := strings.TrimLeft(raw, "0123456789")
+	number, unit := raw[:len(raw)-len(digits)], strings.ToLower(strings.TrimSpace(digits))
+	multiplier, ok := sizeUnits[unit]
+	if number == "" || !ok {
+		return 0, fmt.Errorf("invalid %s: expected a non-negative size such as 1048576, 512KiB, or 10MB, got %q", key, raw)
+	}
+
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("invalid %s: size %q is too large", key, raw)
+	}
+	return n * multiplier, nil
+}
+
+// parseSkipLangs reads SKIP_LANGS and ensures the base language is not skipped.
+func parseSkipLangs(baseLang string) ([]string, error) {
+	skipLangs, err := envconf.ParseLangListEnv("SKIP_LANGS")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := langSet(skipLangs)[baseLang]; ok {
+		return nil, fmt.Errorf("invalid SKIP_LANGS: base language %q cannot be skipped", baseLang)
+	}
+	return skipLangs, nil
+}
+
+// parseFileExtensions reads FILE_EXT: either extensions applied to every root,
+// or a JSON/YAML mapping of roots to an extension or a list of extensions.
+// Every root must be listed in the mapping, since no extensions apply otherwise.
+func parseFileExtensions(roots []string) ([]string, map[string][]string, error) {
+	raw := os.Getenv("FILE_EXT")
+	if !envconf.IsPatternMapping(raw) {
+		fileExts, err := normalizers.NormalizeFileExtensions(envconf.SplitListEnv("FILE_EXT"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: %w", err)
+		}
+		return fileExts, nil, nil
+	}
+
+	obj, err := envconf.ParseMap(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid FILE_EXT: %w", err)
+	}
+
+	known := make(map[string]struct{}, len(roots))
+	for _, r := range roots {
+		known[filepath.ToSlash(r)] = struct{}{}
+	}
+
+	byRoot := make(map[string][]string, len(obj))
+	for key, value := range obj {
+		clean, err := pathnorm.EnsureRepoRelativePath(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: %w", err)
+		}
+		root := filepath.ToSlash(clean)
+		if _, ok := known[root]; !ok {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: %q is not listed in TRANSLATIONS_PATH", key)
+		}
+
+		lines, ok := envconf.StringList(value)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: extensions for %q must be a string or a list of strings", key)
+		}
+		exts, err := normalizers.NormalizeFileExtensions(lines)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT for %q: %w", key, err)
+		}
+		if prev, ok := byRoot[root]; ok && !slices.Equal(prev, exts) {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: conflicting extensions for %q", root)
+		}
+		byRoot[root] = exts
+	}
+
+	var all []string
+	for _, r := range roots {
+		exts, ok := byRoot[filepath.ToSlash(r)]
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: no extensions for %q", r)
+		}
+		for _, ext := range exts {
+			if !slices.Contains(all, ext) {
+				all = append(all, ext)
+			}
+		}
+	}
+	return all, byRoot, nil
+}
+
+// parseTranslationsPaths parses and validates repo-relative translation roots.
+func parseTranslationsPaths() ([]string, error) {
+	paths, err := envconf.ParseRepoRelativePathsEnv("TRANSLATIONS_PATH", pathnorm.EnsureRepoRelativePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRANSLATIONS_PATH: %w", err)
+	}
+	return paths, nil
+}
@@ -0,0 +1,1240 @@
+package findallfiles
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/namepattern"
+)
+
+func TestValidateEnvironment(t *testing.T) {
+	tests := []struct {
+		name            string
+		env             map[string]string
+		wantPaths       []string
+		wantBaseLang    string
+		wantFileExt     []string
+		wantNamePattern string
+		wantFlatNaming  bool
+		wantErr         string
+	}{
+		{
+			name: "Valid environment variables",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "\npath1\npath2\n\n",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "custom_name.json",
+				"FLAT_NAMING":       "true",
+			},
+			wantPaths:       []string{"path1", "path2"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "custom_name.json",
+			wantFlatNaming:  true,
+		},
+		{
+			name: "Missing environment variables",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "",
+				"BASE_LANG":         "",
+				"FILE_EXT":          "",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "false",
+			},
+			wantErr: "invalid TRANSLATIONS_PATH: environment variable TRANSLATIONS_PATH is required",
+		},
+		{
+			name: "Roots are cleaned and remain relative",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": ".\n./locales\nlocales/../locales/en/..",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "false",
+			},
+			wantPaths:       []string{".", "locales"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "",
+			wantFlatNaming:  false,
+		},
+		{
+			name: "Absolute translations path fails",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "/etc/locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "false",
+			},
+			wantErr: "invalid TRANSLATIONS_PATH",
+		},
+		{
+			name: "Parent escape translations path fails",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "../locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "false",
+			},
+			wantErr: "invalid TRANSLATIONS_PATH",
+		},
+		{
+			name: "Name pattern glob variants are allowed",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "translations",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "en/**/custom_*.json",
+				"FLAT_NAMING":       "false",
+			},
+			wantPaths:       []string{"translations"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "en/**/custom_*.json",
+			wantFlatNaming:  false,
+		},
+		{
+			name: "Absolute name pattern fails",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "translations",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "/tmp/**/*.json",
+				"FLAT_NAMING":       "false",
+			},
+			wantErr: "must be relative",
+		},
+		{
+			name: "File extensions are normalized and deduplicated",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "translations",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          " JSON \n.yaml\njson\n YML \n .xml ",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "true",
+			},
+			wantPaths:       []string{"translations"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json", "yaml", "yml", "xml"},
+			wantNamePattern: "",
+			wantFlatNaming:  true,
+		},
+		{
+			name: "Empty file extensions after normalization fail",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "translations",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          ".\n \n",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "true",
+			},
+			wantErr: "invalid FILE_EXT",
+		},
+		{
+			name: "Leading dots and casing in file extensions normalize correctly",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "translations",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          ".json\n.JSON\n json ",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "true",
+			},
+			wantPaths:       []string{"translations"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "",
+			wantFlatNaming:  true,
+		},
+		{
+			name: "Whitespace name pattern is treated as empty",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "translations",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "   ",
+				"FLAT_NAMING":       "true",
+			},
+			wantPaths:       []string{"translations"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "",
+			wantFlatNaming:  true,
+		},
+		{
+			name: "Invalid FLAT_NAMING fails",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "translations",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "wat",
+			},
+			wantErr: "invalid FLAT_NAMING",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{
+				"TRANSLATIONS_PATH",
+				"BASE_LANG",
+				"FILE_EXT",
+				"NAME_PATTERN",
+				"FLAT_NAMING",
+			} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			got, err := validateEnvironment()
+
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %q", tt.wantErr, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got.Paths, tt.wantPaths) {
+				t.Fatalf("paths mismatch. want=%v got=%v", tt.wantPaths, got.Paths)
+			}
+			if got.BaseLang != tt.wantBaseLang {
+				t.Fatalf("baseLang mismatch. want=%q got=%q", tt.wantBaseLang, got.BaseLang)
+			}
+			if !reflect.DeepEqual(got.FileExts, tt.wantFileExt) {
+				t.Fatalf("fileExt mismatch. want=%v got=%v", tt.wantFileExt, got.FileExts)
+			}
+			if filepath.ToSlash(got.NamePattern) != filepath.ToSlash(tt.wantNamePattern) {
+				t.Fatalf("namePattern mismatch. want=%q got=%q", tt.wantNamePattern, got.NamePattern)
+			}
+			if got.FlatNaming != tt.wantFlatNaming {
+				t.Fatalf("flatNaming mismatch. want=%v got=%v", tt.wantFlatNaming, got.FlatNaming)
+			}
+		})
+	}
+}
+
+// setBaseEnv sets a minimal valid environment for validateEnvironment.
+func setBaseEnv(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("TRANSLATIONS_PATH", "translations")
+	t.Setenv("BASE_LANG", "en")
+	t.Setenv("FILE_EXT", "json")
+	t.Setenv("NAME_PATTERN", "")
+	t.Setenv("NAME_REGEX", "")
+	t.Setenv("FLAT_NAMING", "false")
+	t.Setenv("EXCLUDE_PATTERNS", "")
+	t.Setenv("EXCLUDE_PATHS", "")
+	t.Setenv("DISCOVERY_MODE", "")
+	t.Setenv("FILES_LIST_PATH", "")
+	t.Setenv("ALL_FILES_ENCODING", "")
+	t.Setenv("MAX_DEPTH", "")
+	t.Setenv("FOLLOW_SYMLINKS", "")
+	t.Setenv("INCLUDE_VENDOR_DIRS", "")
+	t.Setenv("HASH_FILES", "")
+	t.Setenv("FAIL_IF_EMPTY", "")
+	t.Setenv("MAX_FILES", "")
+	t.Setenv("MIN_FILE_BYTES", "")
+	t.Setenv("MAX_FILE_BYTES", "")
+	t.Setenv("CHANGED_SINCE", "")
+	t.Setenv("SHARD_COUNT", "")
+	t.Setenv("SHARD_INDEX", "")
+	t.Setenv("MATRIX_CHUNK_FILES", "")
+	t.Setenv("MATRIX_CHUNK_BYTES", "")
+	t.Setenv("DETECT_DUPLICATES", "")
+	t.Setenv("CHECK_ENCODING", "")
+	t.Setenv("INCLUDE_SUBMODULES", "")
+	t.Setenv("MANIFEST_FILE", "")
+	t.Setenv("ANNOTATE_SKIPPED", "")
+}
+
+func TestValidateEnvironment_PushAllLangs(t *testing.T) {
+	t.Run("defaults to false", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("PUSH_ALL_LANGS", "")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.AllLangs {
+			t.Fatal("expected AllLangs=false")
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("PUSH_ALL_LANGS", "true")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !got.AllLangs {
+			t.Fatal("expected AllLangs=true")
+		}
+	})
+
+	t.Run("invalid value fails", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("PUSH_ALL_LANGS", "maybe")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "invalid PUSH_ALL_LANGS") {
+			t.Fatalf("expected PUSH_ALL_LANGS error, got %v", err)
+		}
+	})
+}
+
+func TestValidateEnvironment_SkipLangs(t *testing.T) {
+	t.Run("parsed list", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("SKIP_LANGS", `["de", "pt_BR"]`)
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got.SkipLangs, []string{"de", "pt_BR"}) {
+			t.Fatalf("unexpected SkipLangs: %v", got.SkipLangs)
+		}
+	})
+
+	t.Run("base language cannot be skipped", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("SKIP_LANGS", "de\nen")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), `base language "en" cannot be skipped`) {
+			t.Fatalf("expected base language error, got %v", err)
+		}
+	})
+}
+
+func TestValidateEnvironment_ExcludePatterns(t *testing.T) {
+	t.Run("defaults to none", func(t *testing.T) {
+		setBaseEnv(t)
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.ExcludePatterns != nil {
+			t.Fatalf("expected no ExcludePatterns, got %v", got.ExcludePatterns)
+		}
+	})
+
+	t.Run("parsed and cleaned", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("EXCLUDE_PATTERNS", "\n**/fixtures/**\r\n ./locales/vendor/** \n\n")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"**/fixtures/**", "locales/vendor/**"}
+		if !reflect.DeepEqual(got.ExcludePatterns, want) {
+			t.Fatalf("expected %v, got %v", want, got.ExcludePatterns)
+		}
+	})
+
+	t.Run("paths", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("EXCLUDE_PATTERNS", "**/*.bak")
+		t.Setenv("EXCLUDE_PATHS", "locales/vendor/\n./locales/en/draft.json")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"**/*.bak", "locales/vendor", "locales/vendor/**", "locales/en/draft.json", "locales/en/draft.json/**"}
+		if !reflect.DeepEqual(got.ExcludePatterns, want) {
+			t.Fatalf("expected %v, got %v", want, got.ExcludePatterns)
+		}
+	})
+
+	for _, tt := range []struct{ name, key, value, wantErr string }{
+		{"path escape fails", "EXCLUDE_PATHS", "../vendor", "invalid EXCLUDE_PATHS"},
+		{"repository root fails", "EXCLUDE_PATHS", ".", "would exclude the whole repository"},
+		{"glob in path fails", "EXCLUDE_PATHS", "locales/*/vendor", "invalid EXCLUDE_PATHS"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv(tt.key, tt.value)
+
+			_, err := validateEnvironment()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	for _, tt := range []struct{ name, value, wantErr string }{
+		{"absolute pattern fails", "/etc/**", "invalid EXCLUDE_PATTERNS"},
+		{"parent escape fails", "../**/*.json", "path escapes repo root"},
+		{"malformed pattern fails", "locales/[en.json", "malformed pattern"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("EXCLUDE_PATTERNS", tt.value)
+
+			_, err := validateEnvironment()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_DiscoveryMode(t *testing.T) {
+	for _, tt := range []struct{ value, want string }{
+		{"", discoveryFilesystem},
+		{"filesystem", discoveryFilesystem},
+		{" Git ", discoveryGit},
+	} {
+		t.Run("value "+tt.value, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("DISCOVERY_MODE", tt.value)
+
+			got, err := validateEnvironment()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.DiscoveryMode != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got.DiscoveryMode)
+			}
+		})
+	}
+
+	t.Run("unknown mode fails", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("DISCOVERY_MODE", "svn")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "invalid DISCOVERY_MODE") {
+			t.Fatalf("expected DISCOVERY_MODE error, got %v", err)
+		}
+	})
+}
+
+func TestValidateEnvironment_FilesListPath(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("FILES_LIST_PATH", " ./tmp//files.txt ")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join("tmp", "files.txt"); got.FilesListPath != want {
+		t.Fatalf("expected %q, got %q", want, got.FilesListPath)
+	}
+}
+
+func TestValidateEnvironment_FilesEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		listPath string
+		want     string
+		wantErr  string
+	}{
+		{name: "defaults to plain", want: encodingPlain},
+		{name: "url", value: " URL ", want: encodingURL},
+		{name: "nul with list file", value: "nul", listPath: "files.txt", want: encodingNUL},
+		{name: "nul without list file fails", value: "nul", wantErr: "requires FILES_LIST_PATH"},
+		{name: "unknown fails", value: "base64", wantErr: "invalid ALL_FILES_ENCODING"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("ALL_FILES_ENCODING", tt.value)
+			t.Setenv("FILES_LIST_PATH", tt.listPath)
+
+			got, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.FilesEncoding != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got.FilesEncoding)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_MaxDepth(t *testing.T) {
+	for _, tt := range []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "0", want: 0},
+		{value: " 3 ", want: 3},
+		{value: "-1", wantErr: true},
+		{value: "deep", wantErr: true},
+	} {
+		t.Run("value "+tt.value, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("MAX_DEPTH", tt.value)
+
+			got, err := validateEnvironment()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "invalid MAX_DEPTH") {
+					t.Fatalf("expected MAX_DEPTH error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.MaxDepth != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got.MaxDepth)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_FollowSymlinks(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("FOLLOW_SYMLINKS", "true")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.FollowSymlinks {
+		t.Fatal("expected FollowSymlinks=true")
+	}
+
+	t.Setenv("FOLLOW_SYMLINKS", "sometimes")
+	if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "invalid FOLLOW_SYMLINKS") {
+		t.Fatalf("expected FOLLOW_SYMLINKS error, got %v", err)
+	}
+}
+
+func TestValidateEnvironment_IncludeVendorDirs(t *testing.T) {
+	setBaseEnv(t)
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.IncludeVendorDirs {
+		t.Fatal("expected vendor directories to be skipped by default")
+	}
+
+	t.Setenv("INCLUDE_VENDOR_DIRS", "true")
+	got, err = validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.IncludeVendorDirs {
+		t.Fatal("expected IncludeVendorDirs=true")
+	}
+}
+
+func TestValidateEnvironment_FlatNamingPerRoot(t *testing.T) {
+	t.Run("list aligned with roots", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("TRANSLATIONS_PATH", "packages/web/locales\n./packages/app/i18n/")
+		t.Setenv("FLAT_NAMING", "true\nfalse")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]bool{"packages/web/locales": true, "packages/app/i18n": false}
+		if !reflect.DeepEqual(got.FlatNamingByRoot, want) {
+			t.Fatalf("expected %v, got %v", want, got.FlatNamingByRoot)
+		}
+		if !got.flatNamingFor("packages/web/locales") || got.flatNamingFor("packages/app/i18n") {
+			t.Fatal("unexpected per-root layout")
+		}
+	})
+
+	t.Run("single value applies to every root", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("TRANSLATIONS_PATH", "a\nb")
+		t.Setenv("FLAT_NAMING", "true")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.FlatNamingByRoot != nil || !got.flatNamingFor("a") || !got.flatNamingFor("b") {
+			t.Fatalf("unexpected config: %#v", got)
+		}
+	})
+
+	for _, tt := range []struct{ name, paths, flat, wantErr string }{
+		{"count mismatch", "a\nb\nc", "true,false", "got 2 values for 3 TRANSLATIONS_PATH entries"},
+		{"invalid value", "a\nb", "true,maybe", `expected true or false, got "maybe"`},
+		{"conflicting duplicate roots", "a\n./a", "true,false", `conflicting values for "a"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("TRANSLATIONS_PATH", tt.paths)
+			t.Setenv("FLAT_NAMING", tt.flat)
+
+			_, err := validateEnvironment()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_FileExtsPerRoot(t *testing.T) {
+	t.Run("mapping of roots", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("TRANSLATIONS_PATH", "apps/web/locales\n./apps/ios/i18n/")
+		t.Setenv("FILE_EXT", "apps/web/locales: json\napps/ios/i18n: [strings, stringsdict]")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string][]string{"apps/web/locales": {"json"}, "apps/ios/i18n": {"strings", "stringsdict"}}
+		if !reflect.DeepEqual(got.FileExtsByRoot, want) {
+			t.Fatalf("expected %v, got %v", want, got.FileExtsByRoot)
+		}
+		if !reflect.DeepEqual(got.FileExts, []string{"json", "strings", "stringsdict"}) {
+			t.Fatalf("unexpected extensions %v", got.FileExts)
+		}
+		if !reflect.DeepEqual(got.fileExtsFor("apps/ios/i18n"), []string{"strings", "stringsdict"}) {
+			t.Fatalf("unexpected per-root extensions %v", got.fileExtsFor("apps/ios/i18n"))
+		}
+	})
+
+	for _, tt := range []struct{ name, exts, wantErr string }{
+		{"missing root", "apps/web/locales: json", `no extensions for "apps/ios/i18n"`},
+		{"unknown root", "apps/web/locales: json\napps/ios/i18n: strings\napps/api: json", `"apps/api" is not listed in TRANSLATIONS_PATH`},
+		{"invalid extension", "apps/web/locales: json\napps/ios/i18n: a/b", `invalid FILE_EXT for "apps/ios/i18n"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("TRANSLATIONS_PATH", "apps/web/locales\napps/ios/i18n")
+			t.Setenv("FILE_EXT", tt.exts)
+
+			_, err := validateEnvironment()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_NamePatternPerRoot(t *testing.T) {
+	t.Run("list aligned with roots", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("TRANSLATIONS_PATH", "packages/web/locales\n./packages/app/i18n/")
+		t.Setenv("NAME_PATTERN", "**/*.json\n ./strings/*.yaml ")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]namepattern.Rule{"packages/web/locales": {Pattern: "**/*.json"}, "packages/app/i18n": {Pattern: "strings/*.yaml"}}
+		if !reflect.DeepEqual(got.NamePatternByRoot, want) {
+			t.Fatalf("expected %v, got %v", want, got.NamePatternByRoot)
+		}
+		if got.NamePattern != "" {
+			t.Fatalf("expected no global pattern, got %q", got.NamePattern)
+		}
+	})
+
+	t.Run("yaml mapping leaves other roots on the default layout", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("TRANSLATIONS_PATH", "web\nmobile")
+		t.Setenv("NAME_PATTERN", "./web/: \"**/*.json\"")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.nameRuleFor("web").Pattern != "**/*.json" || got.nameRuleFor("mobile").Pattern != "" {
+			t.Fatalf("unexpected per-root patterns: %v", got.NamePatternByRoot)
+		}
+	})
+
+	t.Run("json mapping", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("TRANSLATIONS_PATH", "web\nmobile")
+		t.Setenv("NAME_PATTERN", `{"mobile":"*.strings"}`)
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.nameRuleFor("mobile").Pattern != "*.strings" || got.nameRuleFor("web").Pattern != "" {
+			t.Fatalf("unexpected per-root patterns: %v", got.NamePatternByRoot)
+		}
+	})
+
+	t.Run("brace glob stays a single pattern", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("TRANSLATIONS_PATH", "web\nmobile")
+		t.Setenv("NAME_PATTERN", "{en,fr}.json")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.NamePatternByRoot != nil || got.nameRuleFor("mobile").Pattern != "{en,fr}.json" {
+			t.Fatalf("unexpected config: %#v", got)
+		}
+	})
+
+	for _, tt := range []struct{ name, paths, pattern, wantErr string }{
+		{"count mismatch", "a\nb\nc", "*.json\n*.yaml", "got 2 patterns for 3 TRANSLATIONS_PATH entries"},
+		{"conflicting duplicate roots", "a\n./a", "*.json\n*.yaml", `conflicting patterns for "a"`},
+		{"unknown root in mapping", "a\nb", "c: '*.json'", `"c" is not listed in TRANSLATIONS_PATH`},
+		{"non-string mapping value", "a", "a: 1", `pattern for "a" must be a string`},
+		{"escaping pattern in list", "a\nb", "*.json\n../*.json", "invalid NAME_PATTERN"},
+		{"malformed mapping", "a", "{a: ", "invalid NAME_PATTERN"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("TRANSLATIONS_PATH", tt.paths)
+			t.Setenv("NAME_PATTERN", tt.pattern)
+
+			_, err := validateEnvironment()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_NamePatternExclusions(t *testing.T) {
+	t.Run("single pattern with exclusions", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("NAME_PATTERN", "**/*.yaml\n!**/*.generated.yaml\n ! ./tmp/** ")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.NamePattern != "**/*.yaml" {
+			t.Fatalf("unexpected pattern %q", got.NamePattern)
+		}
+		if want := []string{"**/*.generated.yaml", "tmp/**"}; !reflect.DeepEqual(got.NameExcludes, want) {
+			t.Fatalf("expected exclusions %v, got %v", want, got.NameExcludes)
+		}
+	})
+
+	t.Run("exclusions apply to every aligned root", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("TRANSLATIONS_PATH", "web\nmobile")
+		t.Setenv("NAME_PATTERN", "**/*.json\n!**/fixtures/**\n*.yaml")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]namepattern.Rule{
+			"web":    {Pattern: "**/*.json", Excludes: []string{"**/fixtures/**"}},
+			"mobile": {Pattern: "*.yaml", Excludes: []string{"**/fixtures/**"}},
+		}
+		if !reflect.DeepEqual(got.NamePatternByRoot, want) {
+			t.Fatalf("expected %v, got %v", want, got.NamePatternByRoot)
+		}
+	})
+
+	t.Run("mapping with per-root exclusions", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("TRANSLATIONS_PATH", "web\nmobile")
+		t.Setenv("NAME_PATTERN", "web:\n  - '**/*.yaml'\n  - '!**/*.generated.yaml'\nmobile: '*.strings'")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]namepattern.Rule{
+			"web":    {Pattern: "**/*.yaml", Excludes: []string{"**/*.generated.yaml"}},
+			"mobile": {Pattern: "*.strings"},
+		}
+		if !reflect.DeepEqual(got.NamePatternByRoot, want) {
+			t.Fatalf("expected %v, got %v", want, got.NamePatternByRoot)
+		}
+	})
+
+	for _, tt := range []struct{ name, paths, pattern, wantErr string }{
+		{"exclusion without pattern", "a", "!*.json", "exclusions require a pattern"},
+		{"empty exclusion", "a", "*.json\n!", "empty exclusion"},
+		{"escaping exclusion", "a", "*.json\n!../*.json", "invalid NAME_PATTERN"},
+		{"several patterns for one mapped root", "a", "a: ['*.json', '*.yaml']", `got 2 patterns for "a"`},
+		{"non-string list item", "a", "a: ['*.json', 1]", "must be a string or a list of strings"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("TRANSLATIONS_PATH", tt.paths)
+			t.Setenv("NAME_PATTERN", tt.pattern)
+
+			_, err := validateEnvironment()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_NameRegex(t *testing.T) {
+	t.Run("anchored and capturing the language", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("NAME_REGEX", ` messages_(?P<lang>[a-z]{2})\.properties `)
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if lang, ok := regexLang(got.NameRegex, "messages_de.properties"); !ok || lang != "de" {
+			t.Fatalf("expected language de, got %q (%v)", lang, ok)
+		}
+		if _, ok := regexLang(got.NameRegex, "old/messages_de.properties.bak"); ok {
+			t.Fatal("expected the expression to match whole paths only")
+		}
+	})
+
+	t.Run("empty disables regex mode", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("NAME_REGEX", "  ")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.NameRegex != nil {
+			t.Fatalf("expected no regex, got %v", got.NameRegex)
+		}
+	})
+
+	for _, tt := range []struct{ name, regex, pattern, wantErr string }{
+		{"missing lang group", `messages_([a-z]{2})\.properties`, "", "missing (?P<lang>...) capture group"},
+		{"invalid syntax", `messages_(?P<lang>[a-z`, "", "invalid NAME_REGEX"},
+		{"combined with name pattern", `(?P<lang>[a-z]{2})\.json`, "*.json", "NAME_REGEX and NAME_PATTERN cannot be used together"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("NAME_REGEX", tt.regex)
+			t.Setenv("NAME_PATTERN", tt.pattern)
+
+			_, err := validateEnvironment()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_HashFiles(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("HASH_FILES", "true")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.HashFiles {
+		t.Fatal("expected HashFiles=true")
+	}
+
+	t.Setenv("HASH_FILES", "sometimes")
+	if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "invalid HASH_FILES") {
+		t.Fatalf("expected HASH_FILES error, got %v", err)
+	}
+}
+
+func TestValidateEnvironment_FailIfEmpty(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("FAIL_IF_EMPTY", "true")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.FailIfEmpty {
+		t.Fatal("expected FailIfEmpty=true")
+	}
+
+	t.Setenv("FAIL_IF_EMPTY", "maybe")
+	if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "invalid FAIL_IF_EMPTY") {
+		t.Fatalf("expected FAIL_IF_EMPTY error, got %v", err)
+	}
+}
+
+func TestValidateEnvironment_MaxFiles(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{raw: "", want: defaultMaxFiles},
+		{raw: "0", want: 0},
+		{raw: " 250 ", want: 250},
+		{raw: "-1", wantErr: true},
+		{raw: "lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("MAX_FILES", tt.raw)
+
+			got, err := validateEnvironment()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "invalid MAX_FILES") {
+					t.Fatalf("expected MAX_FILES error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.MaxFiles != tt.want {
+				t.Fatalf("expected MaxFiles=%d, got %d", tt.want, got.MaxFiles)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_FileSizeLimits(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("MIN_FILE_BYTES", "3")
+	t.Setenv("MAX_FILE_BYTES", " 1048576 ")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.MinFileBytes != 3 || got.MaxFileBytes != 1048576 {
+		t.Fatalf("expected limits 3..1048576, got %d..%d", got.MinFileBytes, got.MaxFileBytes)
+	}
+
+	for _, tt := range []struct {
+		min, max, wantErr string
+	}{
+		{min: "-1", wantErr: "invalid MIN_FILE_BYTES"},
+		{max: "1.5MB", wantErr: "invalid MAX_FILE_BYTES"},
+		{max: "10 parsecs", wantErr: "invalid MAX_FILE_BYTES"},
+		{min: "10", max: "5", wantErr: "MIN_FILE_BYTES (10) exceeds MAX_FILE_BYTES (5)"},
+	} {
+		t.Setenv("MIN_FILE_BYTES", tt.min)
+		t.Setenv("MAX_FILE_BYTES", tt.max)
+		if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+		}
+	}
+}
+
+func TestValidateEnvironment_FileSizeLimitUnits(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("MIN_FILE_BYTES", "1KB")
+	t.Setenv("MAX_FILE_BYTES", "10 MiB")
+	t.Setenv("MATRIX_CHUNK_BYTES", "512kib")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.MinFileBytes != 1000 || got.MaxFileBytes != 10<<20 || got.MatrixChunkBytes != 512<<10 {
+		t.Fatalf("unexpected limits: min=%d max=%d chunk=%d", got.MinFileBytes, got.MaxFileBytes, got.MatrixChunkBytes)
+	}
+}
+
+func TestParseSizeEnv(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int64
+		wantErr string
+	}{
+		{"", 0, ""},
+		{"0", 0, ""},
+		{"2048", 2048, ""},
+		{"2048B", 2048, ""},
+		{"10MB", 10_000_000, ""},
+		{"3GB", 3_000_000_000, ""},
+		{"512KiB", 512 << 10, ""},
+		{" 2 GiB ", 2 << 30, ""},
+		{"MB", 0, "expected a non-negative size"},
+		{"-1KB", 0, "expected a non-negative size"},
+		{"1.5MiB", 0, "expected a non-negative size"},
+		{"5TB", 0, "expected a non-negative size"},
+		{"9223372036854775807GiB", 0, "too large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			t.Setenv("SOME_BYTES", tt.raw)
+
+			got, err := parseSizeEnv("SOME_BYTES")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_ChangedSince(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("CHANGED_SINCE", " origin/main ")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ChangedSince != "origin/main" {
+		t.Fatalf("expected ChangedSince=origin/main, got %q", got.ChangedSince)
+	}
+
+	for _, ref := range []string{"--output=/tmp/x", "main feature"} {
+		t.Setenv("CHANGED_SINCE", ref)
+		if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "invalid CHANGED_SINCE") {
+			t.Fatalf("%q: expected CHANGED_SINCE error, got %v", ref, err)
+		}
+	}
+}
+
+func TestValidateEnvironment_Shard(t *testing.T) {
+	tests := []struct {
+		count, index string
+		wantCount    int
+		wantIndex    int
+		wantErr      string
+	}{
+		{wantCount: 1, wantIndex: 0},
+		{count: "4", wantCount: 4, wantIndex: 0},
+		{count: " 4 ", index: "3", wantCount: 4, wantIndex: 3},
+		{count: "0", wantErr: "invalid SHARD_COUNT"},
+		{count: "two", wantErr: "invalid SHARD_COUNT"},
+		{count: "4", index: "4", wantErr: "expected an integer from 0 to 3"},
+		{index: "1", wantErr: "invalid SHARD_INDEX"},
+		{count: "2", index: "-1", wantErr: "invalid SHARD_INDEX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.count+"/"+tt.index, func(t *testing.T) {
+			setBaseEnv(t)
+			t.Setenv("SHARD_COUNT", tt.count)
+			t.Setenv("SHARD_INDEX", tt.index)
+
+			got, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.ShardCount != tt.wantCount || got.ShardIndex != tt.wantIndex {
+				t.Fatalf("expected shard %d/%d, got %d/%d", tt.wantIndex, tt.wantCount, got.ShardIndex, got.ShardCount)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_MatrixChunks(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("MATRIX_CHUNK_FILES", "50")
+	t.Setenv("MATRIX_CHUNK_BYTES", "1048576")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.MatrixChunkFiles != 50 || got.MatrixChunkBytes != 1048576 {
+		t.Fatalf("unexpected chunk limits: %d files, %d bytes", got.MatrixChunkFiles, got.MatrixChunkBytes)
+	}
+
+	t.Setenv("MATRIX_CHUNK_FILES", "many")
+	if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "invalid MATRIX_CHUNK_FILES: expected a non-negative number of files") {
+		t.Fatalf("expected MATRIX_CHUNK_FILES error, got %v", err)
+	}
+}
+
+func TestValidateEnvironment_DetectDuplicates(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("DETECT_DUPLICATES", "true")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.DetectDuplicates {
+		t.Fatal("expected DetectDuplicates=true")
+	}
+
+	t.Setenv("DETECT_DUPLICATES", "often")
+	if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "invalid DETECT_DUPLICATES") {
+		t.Fatalf("expected DETECT_DUPLICATES error, got %v", err)
+	}
+}
+
+func TestValidateEnvironment_CheckEncoding(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("CHECK_ENCODING", "true")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.CheckEncoding {
+		t.Fatal("expected CheckEncoding=true")
+	}
+
+	t.Setenv("CHECK_ENCODING", "utf8")
+	if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "invalid CHECK_ENCODING") {
+		t.Fatalf("expected CHECK_ENCODING error, got %v", err)
+	}
+}
+
+func TestValidateEnvironment_IncludeSubmodules(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("INCLUDE_SUBMODULES", "true")
+
+	got, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.IncludeSubmodules {
+		t.Fatal("expected IncludeSubmodules=true")
+	}
+
+	t.Setenv("INCLUDE_SUBMODULES", "recursive")
+	if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "invalid INCLUDE_SUBMODULES") {
+		t.Fatalf("expected INCLUDE_SUBMODULES error, got %v", err)
+	}
+}
+
+func TestValidateEnvironment_ManifestFile(t *testing.T) {
+	t.Run("replaces roots and layouts", func(t *testing.T) {
+		setBaseEnv(t)
+		dir := t.TempDir()
+		t.Chdir(dir)
+		manifest := `
+file_ext: [json, yaml]
+roots:
+  - apps/web/locales
+  - path: apps/mobile/i18n
+    flat_naming: true
+  - path: packages/ui/i18n
+    name_pattern: ["**/*.yaml", "!**/*.generated.yaml"]
+`
+		if err := os.WriteFile(filepath.Join(dir, "lokalise.yml"), []byte(manifest), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("MANIFEST_FILE", "lokalise.yml")
+		t.Setenv("NAME_PATTERN", "ignored.json")
+		t.Setenv("FLAT_NAMING", "true")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := []string{"apps/web/locales", "apps/mobile/i18n", "packages/ui/i18n"}; !reflect.DeepEqual(got.Paths, want) {
+			t.Fatalf("paths mismatch. want=%v got=%v", want, got.Paths)
+		}
+		if want := []string{"json", "yaml"}; !reflect.DeepEqual(got.FileExts, want) {
+			t.Fatalf("file exts mismatch. want=%v got=%v", want, got.FileExts)
+		}
+		if got.flatNamingFor("apps/web/locales") || !got.flatNamingFor("apps/mobile/i18n") {
+			t.Fatalf("unexpected layouts: %v", got.FlatNamingByRoot)
+		}
+		if rule := got.nameRuleFor("apps/web/locales"); rule.Pattern != "" {
+			t.Fatalf("expected default layout for apps/web/locales, got %+v", rule)
+		}
+		want := namepattern.Rule{Pattern: "**/*.yaml", Excludes: []string{"**/*.generated.yaml"}}
+		if rule := got.nameRuleFor("packages/ui/i18n"); !reflect.DeepEqual(rule, want) {
+			t.Fatalf("name rule mismatch. want=%+v got=%+v", want, rule)
+		}
+	})
+
+	t.Run("falls back to FILE_EXT", func(t *testing.T) {
+		setBaseEnv(t)
+		dir := t.TempDir()
+		t.Chdir(dir)
+		if err := os.WriteFile(filepath.Join(dir, "lokalise.yml"), []byte("roots: [locales]\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("MANIFEST_FILE", "lokalise.yml")
+		t.Setenv("FILE_EXT", "yml")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got.FileExts, []string{"yml"}) || !reflect.DeepEqual(got.Paths, []string{"locales"}) {
+			t.Fatalf("unexpected config: %+v", got)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Chdir(t.TempDir())
+		t.Setenv("MANIFEST_FILE", "lokalise.yml")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "cannot read manifest") {
+			t.Fatalf("expected read error, got %v", err)
+		}
+	})
+
+	t.Run("rejects paths outside the repo", func(t *testing.T) {
+		setBaseEnv(t)
+		t.Setenv("MANIFEST_FILE", "../lokalise.yml")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "invalid MANIFEST_FILE") {
+			t.Fatalf("expected invalid path error, got %v", err)
+		}
+	})
+}
+
+func TestValidateEnvironment_ReportsAllErrors(t *testing.T) {
+	setBaseEnv(t)
+	t.Setenv("PUSH_ALL_LANGS", "maybe")
+	t.Setenv("DISCOVERY_MODE", "ftp")
+	t.Setenv("MAX_FILE_BYTES", "lots")
+
+	_, err := validateEnvironment()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"3 configuration problems", "invalid PUSH_ALL_LANGS", "invalid DISCOVERY_MODE", "invalid MAX_FILE_BYTES"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %q", want, err.Error())
+		}
+	}
+}
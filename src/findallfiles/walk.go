@@ -0,0 +1,153 @@
+package findallfiles
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// walkOptions controls how translation directories are traversed.
+type walkOptions struct {
+	// MaxDepth limits how many path levels below the language directory are
+	// collected (1 = files directly inside it); 0 means unlimited.
+	MaxDepth int
+	// FollowSymlinks makes the walk resolve symlinked files and directories
+	// instead of skipping them.
+	FollowSymlinks bool
+	// SkipVendorDirs skips dot-directories (such as .git), node_modules, and vendor.
+	SkipVendorDirs bool
+}
+
+// vendorDirs regularly contain locale-like files that don't belong to the project,
+// so nested walks skip them (along with every dot-directory) by default.
+var vendorDirs = map[string]struct{}{
+	"node_modules": {},
+	"vendor":       {},
+}
+
+// skipDir reports whether a directory named name must not be entered.
+func (o walkOptions) skipDir(name string) bool {
+	if !o.SkipVendorDirs {
+		return false
+	}
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	_, ok := vendorDirs[name]
+	return ok
+}
+
+// tooDeep reports whether a path depth levels below the walked directory
+// exceeds the configured limit.
+func (o walkOptions) tooDeep(depth int) bool {
+	return o.MaxDepth > 0 && depth > o.MaxDepth
+}
+
+// pathDepth returns the number of segments in a relative path.
+func pathDepth(rel string) int {
+	return strings.Count(filepath.ToSlash(rel), "/") + 1
+}
+
+// resolveEntry returns the type of the entry at fp according to the symlink policy.
+// ok is false for entries that must be skipped: symlinks when not following them
+// and dangling symlinks.
+func resolveEntry(fp string, entry fs.DirEntry, opts walkOptions) (fs.FileMode, bool, error) {
+	if entry.Type()&fs.ModeSymlink == 0 {
+		return entry.Type(), true, nil
+	}
+	if !opts.FollowSymlinks {
+		return 0, false, nil
+	}
+
+	info, err := os.Stat(fp)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("error resolving symlink %q: %w", fp, err)
+	}
+	return info.Mode().Type(), true, nil
+}
+
+// walkFiles calls visit for every regular file below dir, which is always resolved.
+// Symlinks found during the walk follow opts.FollowSymlinks; a followed directory
+// that resolves to one of its own ancestors is skipped to avoid infinite loops.
+// Directories beyond opts.MaxDepth and, with opts.SkipVendorDirs, vendor and
+// dot-directories are not entered. Missing directories are ignored.
+func walkFiles(dir string, opts walkOptions, visit func(string)) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error accessing directory %q: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil
+	}
+
+	return walkDir(dir, 1, []os.FileInfo{info}, opts, visit)
+}
+
+// walkDir visits the entries of dir; depth is the depth of its files and
+// ancestors holds the directories on the current path (only tracked when following symlinks).
+func walkDir(dir string, depth int, ancestors []os.FileInfo, opts walkOptions, visit func(string)) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error walking through directory %q: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		fp := filepath.Join(dir, entry.Name())
+
+		mode, ok, err := resolveEntry(fp, entry, opts)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		switch {
+		case mode.IsDir():
+			// Files inside this directory sit one level deeper.
+			if opts.tooDeep(depth+1) || opts.skipDir(entry.Name()) {
+				continue
+			}
+
+			var next []os.FileInfo
+			if opts.FollowSymlinks {
+				info, err := os.Stat(fp)
+				if err != nil {
+					return fmt.Errorf("error accessing directory %q: %w", fp, err)
+				}
+				if isAncestor(info, ancestors) {
+					logs.Infof("Skipping symlink loop at %s", fp)
+					continue
+				}
+				next = append(ancestors[:len(ancestors):len(ancestors)], info)
+			}
+
+			if err := walkDir(fp, depth+1, next, opts, visit); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			if !opts.tooDeep(depth) {
+				visit(fp)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isAncestor(info os.FileInfo, ancestors []os.FileInfo) bool {
+	for _, a := range ancestors {
+		if os.SameFile(info, a) {
+			return true
+		}
+	}
+	return false
+}
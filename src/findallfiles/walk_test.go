@@ -0,0 +1,118 @@
+package findallfiles
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// setupSymlinkTree creates:
+//
+//	en/a.json
+//	en/link.json    -> a.json
+//	en/dangling.json -> missing.json
+//	en/shared       -> ../shared (contains b.json)
+//	en/loop         -> ../en
+//
+// The test is skipped when symlinks cannot be created (e.g. on Windows without privileges).
+func setupSymlinkTree(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, d := range []string{"en", "shared"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"en/a.json", "shared/b.json"} {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	links := [][2]string{
+		{"a.json", "en/link.json"},
+		{"missing.json", "en/dangling.json"},
+		{filepath.Join("..", "shared"), "en/shared"},
+		{filepath.Join("..", "en"), "en/loop"},
+	}
+	for _, l := range links {
+		if err := os.Symlink(l[0], filepath.Join(dir, l[1])); err != nil {
+			t.Skipf("symlinks are not supported: %v", err)
+		}
+	}
+
+	return dir
+}
+
+func TestWalkFiles_Symlinks(t *testing.T) {
+	dir := setupSymlinkTree(t)
+	root := filepath.Join(dir, "en")
+
+	collect := func(opts walkOptions) []string {
+		var got []string
+		if err := walkFiles(root, opts, func(fp string) {
+			rel, _ := filepath.Rel(root, fp)
+			got = append(got, filepath.ToSlash(rel))
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		slices.Sort(got)
+		return got
+	}
+
+	if got, want := collect(walkOptions{}), []string{"a.json"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("default policy: expected %v, got %v", want, got)
+	}
+
+	want := []string{"a.json", "link.json", "shared/b.json"}
+	if got := collect(walkOptions{FollowSymlinks: true}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("following symlinks: expected %v, got %v", want, got)
+	}
+
+	want = []string{"a.json", "link.json"}
+	if got := collect(walkOptions{FollowSymlinks: true, MaxDepth: 1}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("following symlinks with max depth: expected %v, got %v", want, got)
+	}
+}
+
+func TestWalkFiles_MissingDir(t *testing.T) {
+	err := walkFiles(filepath.Join(t.TempDir(), "nope"), walkOptions{}, func(string) {
+		t.Fatal("visit should not be called")
+	})
+	if err != nil {
+		t.Fatalf("expected missing directory to be ignored, got %v", err)
+	}
+}
+
+func TestWalkOptions_TooDeep(t *testing.T) {
+	if (walkOptions{}).tooDeep(100) {
+		t.Fatal("zero MaxDepth must be unlimited")
+	}
+	opts := walkOptions{MaxDepth: 2}
+	if opts.tooDeep(2) || !opts.tooDeep(3) {
+		t.Fatal("unexpected depth check for MaxDepth=2")
+	}
+	if pathDepth("a.json") != 1 || pathDepth("sub/dir/a.json") != 3 {
+		t.Fatal("unexpected path depth")
+	}
+}
+
+func TestWalkOptions_SkipDir(t *testing.T) {
+	opts := walkOptions{SkipVendorDirs: true}
+	for _, name := range []string{".git", ".cache", "node_modules", "vendor"} {
+		if !opts.skipDir(name) {
+			t.Fatalf("expected %q to be skipped", name)
+		}
+	}
+	for _, name := range []string{"en", "vendors", "pages"} {
+		if opts.skipDir(name) {
+			t.Fatalf("expected %q to be walked", name)
+		}
+	}
+	if (walkOptions{}).skipDir(".git") {
+		t.Fatal("nothing must be skipped when SkipVendorDirs is disabled")
+	}
+}
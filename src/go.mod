@@ -0,0 +1,14 @@
+module lokalise-push-action
+
+go 1.26
+
+toolchain go1.26.4
+
+require (
+	github.com/bmatcuk/doublestar/v4 v4.10.0
+	github.com/bodrovis/lokalise-actions-common/v2 v2.15.0
+	github.com/bodrovis/lokex/v2 v2.3.1
+	go.yaml.in/yaml/v4 v4.0.0-rc.6
+)
+
+require golang.org/x/sync v0.21.0 // indirect
@@ -0,0 +1,35 @@
+// Package apiclient builds the Lokalise API clients used by the commands.
+package apiclient
+
+import (
+	"time"
+
+	"github.com/bodrovis/lokex/v2/client"
+)
+
+// userAgent identifies the action in Lokalise API logs.
+const userAgent = "lokalise-push-action/lokex"
+
+// Settings are the retry, timeout, and polling options of a client. Zero poll
+// waits keep the client defaults.
+type Settings struct {
+	MaxRetries      int
+	HTTPTimeout     time.Duration
+	InitialBackoff  time.Duration
+	MaxBackoff      time.Duration
+	PollInitialWait time.Duration
+	PollMaxWait     time.Duration
+}
+
+// New wires a lokex client for projectID with the given settings.
+func New(token, projectID string, s Settings) (*client.Client, error) {
+	return client.NewClient(
+		token,
+		projectID,
+		client.WithMaxRetries(s.MaxRetries),
+		client.WithHTTPTimeout(s.HTTPTimeout),
+		client.WithBackoff(s.InitialBackoff, s.MaxBackoff),
+		client.WithPollWait(s.PollInitialWait, s.PollMaxWait),
+		client.WithUserAgent(userAgent),
+	)
+}
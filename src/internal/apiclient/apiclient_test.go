@@ -0,0 +1,37 @@
+package apiclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	c, err := New("token", "123.abc", Settings{
+		MaxRetries:      4,
+		HTTPTimeout:     7 * time.Second,
+		InitialBackoff:  2 * time.Second,
+		MaxBackoff:      30 * time.Second,
+		PollInitialWait: 3 * time.Second,
+		PollMaxWait:     90 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if c.ProjectID != "123.abc" || c.UserAgent != userAgent {
+		t.Fatalf("unexpected client %q/%q", c.ProjectID, c.UserAgent)
+	}
+	if c.MaxRetries != 4 || c.HTTPClient.Timeout != 7*time.Second {
+		t.Fatalf("unexpected retries/timeout %d/%v", c.MaxRetries, c.HTTPClient.Timeout)
+	}
+	if c.InitialBackoff != 2*time.Second || c.MaxBackoff != 30*time.Second {
+		t.Fatalf("unexpected backoff %v/%v", c.InitialBackoff, c.MaxBackoff)
+	}
+	if c.PollInitialWait != 3*time.Second || c.PollMaxWait != 90*time.Second {
+		t.Fatalf("unexpected poll waits %v/%v", c.PollInitialWait, c.PollMaxWait)
+	}
+
+	if _, err := New("", "123.abc", Settings{}); err == nil {
+		t.Fatal("expected an error without a token")
+	}
+}
@@ -0,0 +1,64 @@
+package envconf
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bodrovis/lokalise-actions-common/v2/parsers"
+)
+
+// ParseBoolEnv reads a boolean from key; an empty value is false.
+func ParseBoolEnv(key string) (bool, error) {
+	value, err := parsers.ParseBoolEnv(key)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: expected true or false: %w", key, err)
+	}
+	return value, nil
+}
+
+// EnvOrFile returns the contents of the file named by key+"_FILE" when that
+// variable is set and the value of key otherwise, so secrets can be read from
+// a mounted file instead of being passed through the environment.
+func EnvOrFile(key string) (string, error) {
+	path := strings.TrimSpace(os.Getenv(key + "_FILE"))
+	if path == "" {
+		return os.Getenv(key), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read %s_FILE: %w", key, err)
+	}
+	return string(data), nil
+}
+
+// ParseDurationEnv reads a positive duration from key, accepting Go duration
+// syntax ("30s", "5m", "1h30m") or plain integer seconds. Empty means def.
+func ParseDurationEnv(key string, def time.Duration) (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return def, nil
+	}
+
+	var d time.Duration
+	if secs, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		if secs > math.MaxInt64/int64(time.Second) {
+			return 0, fmt.Errorf("invalid %s: %q is too large", key, raw)
+		}
+		d = time.Duration(secs) * time.Second
+	} else {
+		d, err = time.ParseDuration(raw)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s: expected a duration like 30s, 5m, or integer seconds, got %q", key, raw)
+		}
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid %s: duration must be positive, got %q", key, raw)
+	}
+	return d, nil
+}
@@ -0,0 +1,85 @@
+package envconf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBoolEnv(t *testing.T) {
+	for raw, want := range map[string]bool{"": false, "true": true, " FALSE ": false} {
+		t.Setenv("SOME_FLAG", raw)
+		if got, err := ParseBoolEnv("SOME_FLAG"); err != nil || got != want {
+			t.Fatalf("ParseBoolEnv(%q): expected %v, got %v, %v", raw, want, got, err)
+		}
+	}
+
+	t.Setenv("SOME_FLAG", "maybe")
+	if _, err := ParseBoolEnv("SOME_FLAG"); err == nil || !strings.Contains(err.Error(), "invalid SOME_FLAG: expected true or false") {
+		t.Fatalf("expected invalid SOME_FLAG error, got %v", err)
+	}
+}
+
+func TestEnvOrFile(t *testing.T) {
+	t.Setenv("SOME_SECRET", "from-env")
+	t.Setenv("SOME_SECRET_FILE", "")
+
+	if got, err := EnvOrFile("SOME_SECRET"); err != nil || got != "from-env" {
+		t.Fatalf("expected env value, got %q, %v", got, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SOME_SECRET_FILE", path)
+
+	if got, err := EnvOrFile("SOME_SECRET"); err != nil || got != "from-file\n" {
+		t.Fatalf("expected file contents, got %q, %v", got, err)
+	}
+}
+
+func TestParseDurationEnv(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    time.Duration
+		wantErr string
+	}{
+		{"", 7 * time.Second, ""},
+		{"  ", 7 * time.Second, ""},
+		{"30", 30 * time.Second, ""},
+		{" 30s ", 30 * time.Second, ""},
+		{"5m", 5 * time.Minute, ""},
+		{"1h30m", 90 * time.Minute, ""},
+		{"0", 0, "must be positive"},
+		{"-5", 0, "must be positive"},
+		{"-1s", 0, "must be positive"},
+		{"10 seconds", 0, "expected a duration"},
+		{"99999999999999999", 0, "too large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			t.Setenv("SOME_TIMEOUT", tt.raw)
+
+			got, err := ParseDurationEnv("SOME_TIMEOUT", 7*time.Second)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				if !strings.Contains(err.Error(), "invalid SOME_TIMEOUT") {
+					t.Fatalf("expected error to name the variable, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Package envconf reads and validates the environment variables configuring
+// the commands.
+package envconf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Errors reports several configuration problems at once.
+type Errors []error
+
+func (e Errors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d configuration problems:", len(e))
+	for _, err := range e {
+		b.WriteString("\n  - ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+func (e Errors) Unwrap() []error { return e }
+
+// Join drops nil entries from errs and returns nil, the only
+// remaining error, or all of them as Errors. Nested Errors are
+// flattened into the list.
+func Join(errs []error) error {
+	var flat []error
+	for _, err := range errs {
+		if nested, ok := err.(Errors); ok {
+			flat = append(flat, nested...)
+		} else if err != nil {
+			flat = append(flat, err)
+		}
+	}
+	errs = flat
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return Errors(errs)
+	}
+}
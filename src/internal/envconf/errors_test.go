@@ -0,0 +1,35 @@
+package envconf
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	if err := Join([]error{nil, nil}); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	single := fmt.Errorf("invalid A: bad")
+	if err := Join([]error{nil, single}); err != single {
+		t.Fatalf("expected the single error as is, got %v", err)
+	}
+
+	second := fmt.Errorf("invalid B: worse")
+	err := Join([]error{single, nil, second})
+	want := "2 configuration problems:\n  - invalid A: bad\n  - invalid B: worse"
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+	if !errors.Is(err, second) {
+		t.Fatalf("expected joined error to wrap %v", second)
+	}
+
+	third := fmt.Errorf("invalid C: worst")
+	err = Join([]error{err, third})
+	want = "3 configuration problems:\n  - invalid A: bad\n  - invalid B: worse\n  - invalid C: worst"
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected nested problems to be flattened into %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,64 @@
+package envconf
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeyValue is one "<key>=<value>" entry; Line is where it appeared (1-based).
+type KeyValue struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// ParseKeyValueLinesEnv parses the "<key>=<value>" entries of key; see
+// ParseKeyValueLines.
+func ParseKeyValueLinesEnv(key string, normalizeKey func(string) (string, error)) ([]KeyValue, error) {
+	return ParseKeyValueLines(os.Getenv(key), normalizeKey)
+}
+
+// ParseKeyValueLines parses "<key>=<value>" entries separated by newlines or
+// commas, e.g. "packages/app=123.abc". A comma inside a key or value is
+// written as "\,". Keys and values are trimmed, blank entries are skipped, and
+// entries are returned in input order. When normalizeKey is set, it cleans
+// each key before duplicates are detected, so "app" and "./app" count as the
+// same key.
+func ParseKeyValueLines(raw string, normalizeKey func(string) (string, error)) ([]KeyValue, error) {
+	var out []KeyValue
+	seen := make(map[string]int)
+
+	for i, line := range strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n") {
+		lineNo := i + 1
+		for _, entry := range splitEntries(line) {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+
+			k, value, ok := strings.Cut(entry, "=")
+			k = strings.TrimSpace(k)
+			if !ok || k == "" {
+				return nil, fmt.Errorf("line %d: expected <key>=<value>, got %q", lineNo, entry)
+			}
+
+			if normalizeKey != nil {
+				clean, err := normalizeKey(k)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNo, err)
+				}
+				k = clean
+			}
+
+			if prev, dup := seen[k]; dup {
+				return nil, fmt.Errorf("line %d: %q is already set on line %d", lineNo, k, prev)
+			}
+			seen[k] = lineNo
+
+			out = append(out, KeyValue{Key: k, Value: strings.TrimSpace(value), Line: lineNo})
+		}
+	}
+
+	return out, nil
+}
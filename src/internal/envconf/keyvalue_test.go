@@ -0,0 +1,76 @@
+package envconf
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseKeyValueLinesEnv(t *testing.T) {
+	t.Setenv("SOME_PAIRS", "b=2\r\n\n a = 1 , c=x=y\nd=")
+	got, err := ParseKeyValueLinesEnv("SOME_PAIRS", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []KeyValue{
+		{Key: "b", Value: "2", Line: 1},
+		{Key: "a", Value: "1", Line: 3},
+		{Key: "c", Value: "x=y", Line: 3},
+		{Key: "d", Value: "", Line: 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+
+	t.Setenv("SOME_PAIRS", " \n ")
+	if got, err := ParseKeyValueLinesEnv("SOME_PAIRS", nil); err != nil || len(got) != 0 {
+		t.Fatalf("expected no entries for blank input, got %#v, %v", got, err)
+	}
+}
+
+func TestParseKeyValueLinesEnv_EscapedComma(t *testing.T) {
+	t.Setenv("SOME_PAIRS", `apps/a\,b=1.a:tok\,en, c=2`)
+	got, err := ParseKeyValueLinesEnv("SOME_PAIRS", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []KeyValue{
+		{Key: "apps/a,b", Value: "1.a:tok,en", Line: 1},
+		{Key: "c", Value: "2", Line: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestParseKeyValueLinesEnv_Errors(t *testing.T) {
+	upper := func(key string) (string, error) {
+		if strings.HasPrefix(key, "-") {
+			return "", fmt.Errorf("bad key %q", key)
+		}
+		return strings.ToUpper(key), nil
+	}
+
+	tests := []struct {
+		name, raw, wantErr string
+	}{
+		{name: "missing separator", raw: "a=1\nb", wantErr: `line 2: expected <key>=<value>, got "b"`},
+		{name: "empty key", raw: " =1", wantErr: `line 1: expected <key>=<value>, got "=1"`},
+		{name: "duplicate key", raw: "a=1\nb=2\na=3", wantErr: `line 3: "A" is already set on line 1`},
+		{name: "duplicate after normalization", raw: "a=1, A=2", wantErr: `line 1: "A" is already set on line 1`},
+		{name: "normalizer error", raw: "a=1\n-b=2", wantErr: `line 2: bad key "-b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SOME_PAIRS", tt.raw)
+			_, err := ParseKeyValueLinesEnv("SOME_PAIRS", upper)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package envconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bodrovis/lokalise-actions-common/v2/parsers"
+)
+
+// ParseLangListEnv reads a language list from envVar. Accepted forms:
+//   - JSON array: ["de", "pt_BR"]
+//   - newline- and/or comma-separated values
+//
+// Each language is validated; duplicates are dropped (order-preserving).
+func ParseLangListEnv(envVar string) ([]string, error) {
+	raw := strings.TrimSpace(os.Getenv(envVar))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var items []string
+	if strings.HasPrefix(raw, "[") {
+		if err := json.Unmarshal([]byte(raw), &items); err != nil {
+			return nil, fmt.Errorf("invalid %s: expected a JSON array of strings: %w", envVar, err)
+		}
+	} else {
+		items = strings.FieldsFunc(raw, func(r rune) bool {
+			return r == ',' || r == '\n' || r == '\r'
+		})
+	}
+
+	seen := make(map[string]struct{}, len(items))
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		lang, err := parsers.ParseLang(envVar, item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", envVar, err)
+		}
+		if _, dup := seen[lang]; dup {
+			continue
+		}
+		seen[lang] = struct{}{}
+		out = append(out, lang)
+	}
+
+	return out, nil
+}
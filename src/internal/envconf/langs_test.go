@@ -0,0 +1,42 @@
+package envconf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLangListEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr string
+	}{
+		{name: "empty", raw: "  ", want: nil},
+		{name: "JSON array", raw: `["de", "pt_BR", "de"]`, want: []string{"de", "pt_BR"}},
+		{name: "newline and comma separated", raw: "de, fr\n\npt_BR ", want: []string{"de", "fr", "pt_BR"}},
+		{name: "invalid JSON", raw: `["de",`, wantErr: "expected a JSON array of strings"},
+		{name: "path separator rejected", raw: "de/x", wantErr: "must not contain path separators"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_LANGS", tt.raw)
+
+			got, err := ParseLangListEnv("TEST_LANGS")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
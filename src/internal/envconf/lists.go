@@ -0,0 +1,191 @@
+package envconf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// SplitListEnv splits a comma- and/or newline-separated env value, dropping
+// blanks. An escaped comma ("\,") is kept as part of the value.
+func SplitListEnv(key string) []string {
+	var out []string
+	lines := strings.FieldsFunc(os.Getenv(key), func(r rune) bool { return r == '\n' || r == '\r' })
+	for _, line := range lines {
+		for _, v := range splitEntries(line) {
+			if v = strings.TrimSpace(v); v != "" {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// splitEntries splits line on commas, turning an escaped comma ("\,") into a
+// literal one. Entries are returned untrimmed.
+func splitEntries(line string) []string {
+	var (
+		out []string
+		cur strings.Builder
+	)
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line) && line[i+1] == ',':
+			cur.WriteByte(',')
+			i++
+		case c == ',':
+			out = append(out, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	return append(out, cur.String())
+}
+
+// ProjectIDs splits a comma- or newline-separated LOKALISE_PROJECT_ID value.
+// Entries are trimmed; empty values and duplicates are dropped (order-preserving).
+func ProjectIDs(raw string) []string {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+
+	seen := make(map[string]struct{}, len(fields))
+	var ids []string
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if _, dup := seen[f]; dup {
+			continue
+		}
+		seen[f] = struct{}{}
+		ids = append(ids, f)
+	}
+	return ids
+}
+
+// SplitProjectIDs splits LOKALISE_PROJECT_ID (see ProjectIDs) into the primary
+// project and the mirrors receiving the same files, which are nil without any.
+func SplitProjectIDs(raw string) (string, []string) {
+	ids := ProjectIDs(raw)
+	switch len(ids) {
+	case 0:
+		return "", nil
+	case 1:
+		return ids[0], nil
+	default:
+		return ids[0], ids[1:]
+	}
+}
+
+// ParseRepoRelativePathsEnv reads a list of repo-relative paths from key (see
+// SplitListEnv), cleaned by normalize (such as pathnorm.EnsureRepoRelativePath),
+// converted to forward slashes, and deduplicated in order.
+func ParseRepoRelativePathsEnv(key string, normalize func(string) (string, error)) ([]string, error) {
+	raw := SplitListEnv(key)
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("environment variable %s is required", key)
+	}
+
+	seen := make(map[string]struct{}, len(raw))
+	out := make([]string, 0, len(raw))
+	for _, p := range raw {
+		clean, err := normalize(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q in %s: %w", p, key, err)
+		}
+		norm := filepath.ToSlash(clean)
+		if _, dup := seen[norm]; dup {
+			continue
+		}
+		seen[norm] = struct{}{}
+		out = append(out, norm)
+	}
+	return out, nil
+}
+
+// ParseWatchPatternsEnv reads WATCH_PATTERNS, extra repo-relative files or
+// globs (e.g. lokalise.yml) whose changes trigger a push without being
+// uploaded. Patterns are cleaned by normalize (such as
+// pathnorm.EnsureRepoRelativePattern) and converted to forward slashes.
+func ParseWatchPatternsEnv(normalize func(string) (string, error)) ([]string, error) {
+	var patterns []string
+	for _, p := range SplitListEnv("WATCH_PATTERNS") {
+		if strings.HasPrefix(p, "!") {
+			return nil, fmt.Errorf("invalid WATCH_PATTERNS: %q must not start with \"!\"; use exclude_patterns instead", p)
+		}
+		clean, err := normalize(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WATCH_PATTERNS: %w", err)
+		}
+		if clean == "." {
+			return nil, fmt.Errorf("invalid WATCH_PATTERNS: %q would watch the whole repository", p)
+		}
+		pattern := filepath.ToSlash(clean)
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("invalid WATCH_PATTERNS: malformed pattern %q", p)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// ParseFlatNaming reads FLAT_NAMING: either a single boolean applied to every
+// root, or a comma- or newline-separated list with one value per TRANSLATIONS_PATH
+// entry (in order), returned as a per-root map keyed by the root cleaned with
+// normalize.
+func ParseFlatNaming(normalize func(string) (string, error)) (bool, map[string]bool, error) {
+	values := SplitListEnv("FLAT_NAMING")
+	if len(values) <= 1 {
+		flatNaming, err := ParseBoolEnv("FLAT_NAMING")
+		return flatNaming, nil, err
+	}
+
+	rawRoots := SplitListEnv("TRANSLATIONS_PATH")
+	if len(values) != len(rawRoots) {
+		return false, nil, fmt.Errorf("invalid FLAT_NAMING: got %d values for %d TRANSLATIONS_PATH entries", len(values), len(rawRoots))
+	}
+
+	byRoot := make(map[string]bool, len(values))
+	for i, v := range values {
+		flat, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, nil, fmt.Errorf("invalid FLAT_NAMING: expected true or false, got %q", v)
+		}
+		clean, err := normalize(rawRoots[i])
+		if err != nil {
+			return false, nil, fmt.Errorf("invalid TRANSLATIONS_PATH: %w", err)
+		}
+		root := filepath.ToSlash(clean)
+		if prev, ok := byRoot[root]; ok && prev != flat {
+			return false, nil, fmt.Errorf("invalid FLAT_NAMING: conflicting values for %q", root)
+		}
+		byRoot[root] = flat
+	}
+	return false, byRoot, nil
+}
+
+// ParseEnumEnv reads key case-insensitively and returns one of allowed, or def
+// when the variable is empty.
+func ParseEnumEnv(key string, allowed []string, def string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(key)))
+	if value == "" {
+		return def, nil
+	}
+	if slices.Contains(allowed, value) {
+		return value, nil
+	}
+
+	quoted := make([]string, len(allowed))
+	for i, a := range allowed {
+		quoted[i] = strconv.Quote(a)
+	}
+	return "", fmt.Errorf("invalid %s: allowed values are %s; got %q", key, strings.Join(quoted, ", "), value)
+}
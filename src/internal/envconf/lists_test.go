@@ -0,0 +1,167 @@
+package envconf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/pathnorm"
+)
+
+func TestSplitListEnv(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{"", nil},
+		{"a, b ,c", []string{"a", "b", "c"}},
+		{"a\r\nb\n\n, c", []string{"a", "b", "c"}},
+		{`apps/a\,b, c`, []string{"apps/a,b", "c"}},
+		{`a\b, \,`, []string{`a\b`, ","}},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("SOME_LIST", tt.raw)
+		if got := SplitListEnv("SOME_LIST"); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%q: expected %q, got %q", tt.raw, tt.want, got)
+		}
+	}
+}
+
+func TestSplitProjectIDs(t *testing.T) {
+	tests := []struct {
+		raw     string
+		primary string
+		mirrors []string
+	}{
+		{raw: ""},
+		{raw: " , \n"},
+		{raw: " proj ", primary: "proj"},
+		{raw: "p1,p2", primary: "p1", mirrors: []string{"p2"}},
+		{raw: "\n  p1 \r\n p2\n, p3", primary: "p1", mirrors: []string{"p2", "p3"}},
+		{raw: " , ,p3,p3", primary: "p3"},
+	}
+	for _, tt := range tests {
+		primary, mirrors := SplitProjectIDs(tt.raw)
+		if primary != tt.primary || !reflect.DeepEqual(mirrors, tt.mirrors) {
+			t.Fatalf("SplitProjectIDs(%q) = %q, %q; want %q, %q", tt.raw, primary, mirrors, tt.primary, tt.mirrors)
+		}
+	}
+	if got, want := ProjectIDs("p1, p2\np1"), []string{"p1", "p2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestParseRepoRelativePathsEnv(t *testing.T) {
+	t.Setenv("SOME_PATHS", "locales, ./packages/app/i18n/\nlocales")
+	got, err := ParseRepoRelativePathsEnv("SOME_PATHS", pathnorm.EnsureRepoRelativePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"locales", "packages/app/i18n"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	for raw, wantErr := range map[string]string{
+		" , ":           "environment variable SOME_PATHS is required",
+		"locales, ../x": `invalid path "../x" in SOME_PATHS`,
+	} {
+		t.Setenv("SOME_PATHS", raw)
+		if _, err := ParseRepoRelativePathsEnv("SOME_PATHS", pathnorm.EnsureRepoRelativePath); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%q: expected error containing %q, got %v", raw, wantErr, err)
+		}
+	}
+}
+
+func TestParseWatchPatternsEnv(t *testing.T) {
+	t.Setenv("WATCH_PATTERNS", "lokalise.yml\n ./config//i18n.*.ts , docs/{a\\,b}.md")
+	got, err := ParseWatchPatternsEnv(pathnorm.EnsureRepoRelativePattern)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"lokalise.yml", "config/i18n.*.ts", "docs/{a,b}.md"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	for raw, wantErr := range map[string]string{
+		"!lokalise.yml":   `"!lokalise.yml" must not start with "!"`,
+		"../lokalise.yml": "invalid WATCH_PATTERNS",
+		"./":              "would watch the whole repository",
+		"config/[i18n.ts": `malformed pattern "config/[i18n.ts"`,
+	} {
+		t.Setenv("WATCH_PATTERNS", raw)
+		if _, err := ParseWatchPatternsEnv(pathnorm.EnsureRepoRelativePattern); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%q: expected error containing %q, got %v", raw, wantErr, err)
+		}
+	}
+}
+
+func TestParseFlatNaming(t *testing.T) {
+	tests := []struct {
+		name       string
+		flat       string
+		roots      string
+		want       bool
+		wantByRoot map[string]bool
+		wantErr    string
+	}{
+		{name: "empty", flat: "", roots: "locales"},
+		{name: "single value", flat: "true", roots: "web/locales\napp/i18n", want: true},
+		{name: "per root", flat: "true\nfalse", roots: "./web//locales\napp/i18n", wantByRoot: map[string]bool{"web/locales": true, "app/i18n": false}},
+		{name: "invalid single value", flat: "wat", roots: "locales", wantErr: "invalid FLAT_NAMING: expected true or false"},
+		{name: "invalid per-root value", flat: "true,wat", roots: "a,b", wantErr: `invalid FLAT_NAMING: expected true or false, got "wat"`},
+		{name: "count mismatch", flat: "true,false,true", roots: "a,b", wantErr: "got 3 values for 2 TRANSLATIONS_PATH entries"},
+		{name: "conflicting values", flat: "true,false", roots: "a,./a", wantErr: `conflicting values for "a"`},
+		{name: "invalid root", flat: "true,false", roots: "a,../b", wantErr: "invalid TRANSLATIONS_PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FLAT_NAMING", tt.flat)
+			t.Setenv("TRANSLATIONS_PATH", tt.roots)
+
+			got, byRoot, err := ParseFlatNaming(pathnorm.EnsureRepoRelativePath)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || !reflect.DeepEqual(byRoot, tt.wantByRoot) {
+				t.Fatalf("expected %v %v, got %v %v", tt.want, tt.wantByRoot, got, byRoot)
+			}
+		})
+	}
+}
+
+func TestParseEnumEnv(t *testing.T) {
+	allowed := []string{"debug", "info", "warn"}
+
+	for _, tt := range []struct {
+		raw, want string
+	}{
+		{"", "info"},
+		{"  ", "info"},
+		{"warn", "warn"},
+		{" DEBUG ", "debug"},
+	} {
+		t.Setenv("LOG_LEVEL", tt.raw)
+		got, err := ParseEnumEnv("LOG_LEVEL", allowed, "info")
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.raw, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%q: expected %q, got %q", tt.raw, tt.want, got)
+		}
+	}
+
+	t.Setenv("LOG_LEVEL", "Verbose")
+	_, err := ParseEnumEnv("LOG_LEVEL", allowed, "info")
+	want := `invalid LOG_LEVEL: allowed values are "debug", "info", "warn"; got "verbose"`
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,150 @@
+package envconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// maxMappingBytes caps JSON/YAML mapping inputs. Environment values can't grow
+// much beyond this anyway, so anything larger is a pasted file, not a config.
+const maxMappingBytes = 64 << 10
+
+// IsPatternMapping reports whether NAME_PATTERN or FILE_EXT is a JSON or YAML
+// mapping of roots to values. Colons never appear in repo-relative globs or
+// extensions, while brace globs such as "{en,fr}.json" start with "{" too, so
+// the colon decides.
+func IsPatternMapping(raw string) bool {
+	return strings.Contains(raw, ":")
+}
+
+// ParseMap decodes raw as a JSON object when it starts with "{" and as a
+// YAML mapping otherwise. An empty value yields an empty map.
+func ParseMap(raw string) (map[string]any, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return map[string]any{}, nil
+	}
+	if strings.HasPrefix(raw, "{") {
+		return parseJSONMap(raw)
+	}
+	return parseYAMLMap(raw)
+}
+
+// MergeAdditionalParams decodes ADDITIONAL_PARAMS (see ParseMap) into the
+// request payload, overriding the keys it sets.
+func MergeAdditionalParams(params map[string]any, raw string) error {
+	add, err := ParseMap(raw)
+	if err != nil {
+		return fmt.Errorf("invalid additional_params (must be JSON object or YAML mapping): %w", err)
+	}
+	maps.Copy(params, add)
+	return nil
+}
+
+// StringList accepts a string or a list of strings decoded from JSON/YAML.
+func StringList(value any) ([]string, bool) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, true
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, s)
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+// parseJSONMap decodes a JSON object; arrays, scalars, and null are rejected.
+func parseJSONMap(raw string) (map[string]any, error) {
+	if len(raw) > maxMappingBytes {
+		return nil, fmt.Errorf("value is %d bytes, over the %d-byte limit", len(raw), maxMappingBytes)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal([]byte(raw), &m); err != nil {
+		return nil, fmt.Errorf("expected a JSON object: %w", err)
+	}
+	if m == nil {
+		return nil, fmt.Errorf("expected a JSON object, got null")
+	}
+	return m, nil
+}
+
+// parseYAMLMap decodes a YAML mapping; sequences, scalars, and null are rejected.
+// Nested mappings are converted to map[string]any so the result encodes to
+// JSON; their keys must be scalars.
+func parseYAMLMap(raw string) (map[string]any, error) {
+	if len(raw) > maxMappingBytes {
+		return nil, fmt.Errorf("value is %d bytes, over the %d-byte limit", len(raw), maxMappingBytes)
+	}
+
+	var m map[string]any
+	if err := yaml.Unmarshal([]byte(raw), &m); err != nil {
+		return nil, fmt.Errorf("expected a YAML mapping: %w", err)
+	}
+	if m == nil {
+		return nil, fmt.Errorf("expected a YAML mapping (key: value)")
+	}
+
+	for key, value := range m {
+		v, err := stringKeys(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", key, err)
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// stringKeys recursively converts map[any]any values, which YAML produces for
+// mappings with non-string keys such as {1: a}, to map[string]any.
+func stringKeys(value any) (any, error) {
+	switch v := value.(type) {
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			switch key.(type) {
+			case string, int, int64, uint64, float64, bool:
+			default:
+				return nil, fmt.Errorf("mapping key %v must be a scalar", key)
+			}
+			converted, err := stringKeys(item)
+			if err != nil {
+				return nil, err
+			}
+			out[fmt.Sprint(key)] = converted
+		}
+		return out, nil
+	case map[string]any:
+		for key, item := range v {
+			converted, err := stringKeys(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			converted, err := stringKeys(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
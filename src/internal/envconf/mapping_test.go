@@ -0,0 +1,124 @@
+package envconf
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsPatternMapping(t *testing.T) {
+	for raw, want := range map[string]bool{
+		"json":                     false,
+		"{en,fr}.json":             false,
+		"**/*.%LANG%.json":         false,
+		`{"locales": "json"}`:      true,
+		"locales: json\napp: yaml": true,
+	} {
+		if got := IsPatternMapping(raw); got != want {
+			t.Fatalf("%q: expected %v, got %v", raw, want, got)
+		}
+	}
+}
+
+func TestParseMap(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want map[string]any
+	}{
+		{name: "empty", raw: "  ", want: map[string]any{}},
+		{name: "json", raw: `{"a": "x", "n": 2}`, want: map[string]any{"a": "x", "n": float64(2)}},
+		{name: "yaml", raw: "a: x\nn: 2", want: map[string]any{"a": "x", "n": 2}},
+		{
+			name: "yaml nested non-string keys",
+			raw:  "a:\n  1: one\n  true: yes\nlist:\n  - {2: two}",
+			want: map[string]any{
+				"a":    map[string]any{"1": "one", "true": "yes"},
+				"list": []any{map[string]any{"2": "two"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMap(tt.raw)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %#v, got %#v", tt.want, got)
+			}
+			if _, err := json.Marshal(got); err != nil {
+				t.Fatalf("result does not encode to JSON: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseMap_Invalid(t *testing.T) {
+	tests := []struct {
+		name, raw, wantErr string
+	}{
+		{name: "json unterminated", raw: `{"a": 1`, wantErr: "expected a JSON object"},
+		{name: "json trailing data", raw: `{"a": 1} {}`, wantErr: "expected a JSON object"},
+		{name: "yaml sequence", raw: "- a\n- b", wantErr: "expected a YAML mapping"},
+		{name: "yaml scalar", raw: "just text", wantErr: "expected a YAML mapping"},
+		{name: "yaml null", raw: "~", wantErr: "expected a YAML mapping"},
+		{name: "yaml duplicate key", raw: "a: 1\na: 2", wantErr: "already defined"},
+		{name: "too large", raw: "a: " + strings.Repeat("x", maxMappingBytes), wantErr: "-byte limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMap(tt.raw)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseJSONMap_RejectsNull(t *testing.T) {
+	if _, err := parseJSONMap("null"); err == nil || !strings.Contains(err.Error(), "got null") {
+		t.Fatalf("expected null error, got %v", err)
+	}
+}
+
+func TestMergeAdditionalParams(t *testing.T) {
+	params := map[string]any{"format": "json", "original_filenames": true}
+	if err := MergeAdditionalParams(params, "original_filenames: false\nindentation: 2sp"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]any{"format": "json", "original_filenames": false, "indentation": "2sp"}
+	if !reflect.DeepEqual(params, want) {
+		t.Fatalf("expected %v, got %v", want, params)
+	}
+
+	if err := MergeAdditionalParams(params, "{not json"); err == nil || !strings.Contains(err.Error(), "invalid additional_params") {
+		t.Fatalf("expected additional_params error, got %v", err)
+	}
+}
+
+func TestStringList(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		value  any
+		want   []string
+		wantOK bool
+	}{
+		{"string", "json", []string{"json"}, true},
+		{"list", []any{"json", "yaml"}, []string{"json", "yaml"}, true},
+		{"empty list", []any{}, []string{}, true},
+		{"non-string item", []any{"json", 1}, nil, false},
+		{"number", 1, nil, false},
+		{"mapping", map[string]any{"a": "b"}, nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := StringList(tt.value)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v (%v), got %v (%v)", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Package ghcontext reads the metadata of the GitHub Actions workflow run from
+// the GITHUB_* variables.
+package ghcontext
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+)
+
+// Context is the metadata of the workflow run that triggered the action.
+// Values that aren't available (e.g. PRNumber outside pull requests) are empty.
+type Context struct {
+	// RefName is the branch keys are tagged with: the head branch on pull
+	// requests, the pushed branch or tag otherwise.
+	RefName string
+	SHA     string
+	// HeadSHA is the commit checks are attached to: the head of the pull
+	// request, whose GITHUB_SHA is a merge commit, or SHA otherwise.
+	HeadSHA    string
+	PRNumber   string
+	RunID      string
+	Repository string
+	ServerURL  string
+	// APIURL is the base URL of the GitHub REST API.
+	APIURL string
+	// EventPath is the file holding the webhook payload of the event.
+	EventPath string
+}
+
+var (
+	shaRe        = regexp.MustCompile(`^[0-9a-fA-F]{1,64}$`)
+	repositoryRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+	pullRefRe    = regexp.MustCompile(`^refs/pull/(\d+)/`)
+)
+
+// Read reads the run metadata from getenv. Every variable is optional, since
+// the action may run outside GitHub Actions, but values that are set must be
+// well-formed; all problems are reported together. The pull request number
+// comes from GITHUB_REF, or from the event payload for events such as
+// pull_request_target whose ref is the base branch.
+func Read(getenv func(string) string) (Context, error) {
+	env := func(key string) string { return strings.TrimSpace(getenv(key)) }
+
+	c := Context{
+		RefName:    env("GITHUB_HEAD_REF"),
+		SHA:        env("GITHUB_SHA"),
+		PRNumber:   pullRequestNumber(env("GITHUB_REF")),
+		RunID:      env("GITHUB_RUN_ID"),
+		Repository: env("GITHUB_REPOSITORY"),
+		ServerURL:  env("GITHUB_SERVER_URL"),
+		APIURL:     env("GITHUB_API_URL"),
+		EventPath:  env("GITHUB_EVENT_PATH"),
+	}
+	if c.RefName == "" {
+		c.RefName = env("GITHUB_REF_NAME")
+	}
+
+	var errs []error
+	if c.SHA != "" && !shaRe.MatchString(c.SHA) {
+		errs = append(errs, fmt.Errorf("invalid GITHUB_SHA: expected a hexadecimal commit SHA, got %q", c.SHA))
+	}
+	if c.RunID != "" {
+		if id, err := strconv.ParseUint(c.RunID, 10, 64); err != nil || id == 0 {
+			errs = append(errs, fmt.Errorf("invalid GITHUB_RUN_ID: expected a positive integer, got %q", c.RunID))
+		}
+	}
+	if c.Repository != "" && !repositoryRe.MatchString(c.Repository) {
+		errs = append(errs, fmt.Errorf("invalid GITHUB_REPOSITORY: expected <owner>/<name>, got %q", c.Repository))
+	}
+	for _, v := range []struct{ key, value string }{{"GITHUB_SERVER_URL", c.ServerURL}, {"GITHUB_API_URL", c.APIURL}} {
+		if v.value == "" {
+			continue
+		}
+		if u, err := url.Parse(v.value); err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+			errs = append(errs, fmt.Errorf("invalid %s: expected an absolute http(s) URL, got %q", v.key, v.value))
+		}
+	}
+	if c.EventPath != "" {
+		number, headSHA, err := eventPullRequest(c.EventPath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid GITHUB_EVENT_PATH: %w", err))
+		}
+		if c.PRNumber == "" {
+			c.PRNumber = number
+		}
+		if shaRe.MatchString(headSHA) {
+			c.HeadSHA = headSHA
+		}
+	}
+	if c.HeadSHA == "" {
+		c.HeadSHA = c.SHA
+	}
+
+	return c, envconf.Join(errs)
+}
+
+// eventPullRequest returns the pull request number and head commit from the
+// event payload at path, or empty strings when the event isn't about a pull
+// request.
+func eventPullRequest(path string) (string, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot read event payload: %w", err)
+	}
+
+	var payload struct {
+		PullRequest *struct {
+			Number uint64 `json:"number"`
+			Head   struct {
+				SHA string `json:"sha"`
+			} `json:"head"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", "", fmt.Errorf("cannot parse event payload: %w", err)
+	}
+	if payload.PullRequest == nil || payload.PullRequest.Number == 0 {
+		return "", "", nil
+	}
+	return strconv.FormatUint(payload.PullRequest.Number, 10), payload.PullRequest.Head.SHA, nil
+}
+
+// pullRequestNumber extracts the PR number from a GITHUB_REF like "refs/pull/42/merge".
+func pullRequestNumber(ref string) string {
+	if m := pullRefRe.FindStringSubmatch(ref); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package ghcontext
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func mapEnv(m map[string]string) func(string) string {
+	return func(key string) string { return m[key] }
+}
+
+func TestRead(t *testing.T) {
+	gh, err := Read(mapEnv(map[string]string{
+		"GITHUB_HEAD_REF":   "",
+		"GITHUB_REF_NAME":   " main ",
+		"GITHUB_REF":        "refs/pull/17/merge",
+		"GITHUB_SHA":        "0123456789abcdef0123456789abcdef01234567",
+		"GITHUB_RUN_ID":     "42",
+		"GITHUB_REPOSITORY": "acme/app",
+		"GITHUB_SERVER_URL": "https://github.com",
+		"GITHUB_API_URL":    "https://api.github.com",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := []string{gh.RefName, gh.SHA, gh.HeadSHA, gh.PRNumber, gh.RunID, gh.Repository, gh.ServerURL, gh.APIURL, gh.EventPath}
+	want := []string{"main", "0123456789abcdef0123456789abcdef01234567", "0123456789abcdef0123456789abcdef01234567", "17", "42", "acme/app", "https://github.com", "https://api.github.com", ""}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+
+	gh, err = Read(mapEnv(map[string]string{"GITHUB_HEAD_REF": "feature/x", "GITHUB_REF_NAME": "17/merge"}))
+	if err != nil || gh.RefName != "feature/x" {
+		t.Fatalf("expected head ref to win, got %q (%v)", gh.RefName, err)
+	}
+
+	gh, err = Read(mapEnv(nil))
+	if err != nil || gh.RefName != "" || gh.PRNumber != "" {
+		t.Fatalf("expected an empty context outside GitHub Actions, got %+v (%v)", gh, err)
+	}
+}
+
+func TestRead_EventPayload(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// pull_request_target runs on the base branch ref.
+	gh, err := Read(mapEnv(map[string]string{
+		"GITHUB_REF":        "refs/heads/main",
+		"GITHUB_EVENT_PATH": write("pr.json", `{"action":"opened","pull_request":{"number":7}}`),
+	}))
+	if err != nil || gh.PRNumber != "7" {
+		t.Fatalf("expected PR number from the payload, got %q (%v)", gh.PRNumber, err)
+	}
+
+	gh, err = Read(mapEnv(map[string]string{
+		"GITHUB_EVENT_PATH": write("push.json", `{"ref":"refs/heads/main"}`),
+		"GITHUB_SHA":        "abc123",
+	}))
+	if err != nil || gh.PRNumber != "" || gh.HeadSHA != "abc123" {
+		t.Fatalf("expected no PR number and the pushed head for a push, got %q, %q (%v)", gh.PRNumber, gh.HeadSHA, err)
+	}
+
+	// Checks belong to the head of a pull request, not to its merge commit.
+	gh, err = Read(mapEnv(map[string]string{
+		"GITHUB_REF":        "refs/pull/7/merge",
+		"GITHUB_SHA":        "abc123",
+		"GITHUB_EVENT_PATH": write("pr-head.json", `{"pull_request":{"number":7,"head":{"sha":"def456"}}}`),
+	}))
+	if err != nil || gh.PRNumber != "7" || gh.HeadSHA != "def456" {
+		t.Fatalf("expected the pull request head, got %q, %q (%v)", gh.PRNumber, gh.HeadSHA, err)
+	}
+
+	for name, path := range map[string]string{
+		"cannot parse event payload": write("bad.json", "{"),
+		"cannot read event payload":  filepath.Join(dir, "missing.json"),
+	} {
+		if _, err := Read(mapEnv(map[string]string{"GITHUB_EVENT_PATH": path})); err == nil || !strings.Contains(err.Error(), name) {
+			t.Fatalf("expected error containing %q, got %v", name, err)
+		}
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	_, err := Read(mapEnv(map[string]string{
+		"GITHUB_SHA":        "not-a-sha",
+		"GITHUB_RUN_ID":     "0",
+		"GITHUB_REPOSITORY": "acme",
+		"GITHUB_SERVER_URL": "github.com",
+		"GITHUB_API_URL":    "ftp://api.github.com",
+	}))
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"5 configuration problems", "invalid GITHUB_SHA", "invalid GITHUB_RUN_ID", "invalid GITHUB_REPOSITORY", "invalid GITHUB_SERVER_URL", "invalid GITHUB_API_URL"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %q", want, err.Error())
+		}
+	}
+}
+
+func TestPullRequestNumber(t *testing.T) {
+	tests := map[string]string{
+		"refs/pull/42/merge": "42",
+		"refs/pull/7/head":   "7",
+		"refs/heads/main":    "",
+		"":                   "",
+	}
+	for ref, want := range tests {
+		if got := pullRequestNumber(ref); got != want {
+			t.Fatalf("pullRequestNumber(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Package ghoutput writes the step outputs of the commands to GITHUB_OUTPUT,
+// or to a fallback sink when they run outside GitHub Actions.
+package ghoutput
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"lokalise-push-action/internal/logging"
+)
+
+// outputName matches the names GitHub Actions accepts for outputs, environment
+// variables, and state: a letter or "_" followed by letters, digits, "-", or "_".
+var outputName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// FallbackEnv names the file receiving outputs when GITHUB_OUTPUT is unset.
+const FallbackEnv = "LOKALISE_OUTPUT_FILE"
+
+// Write appends an output to GITHUB_OUTPUT using the delimiter syntax, so
+// unlike githuboutput.WriteToGitHubOutput it accepts multiline values.
+// Outside GitHub Actions it falls back to writeFallback.
+func Write(name, value string) error {
+	if os.Getenv("GITHUB_OUTPUT") != "" {
+		return appendFile("GITHUB_OUTPUT", name, value)
+	}
+	return writeFallback(os.Stdout, name, value)
+}
+
+// Writer returns the output function the commands hand to their steps: it
+// writes with Write and logs failures to logs, reporting whether the output
+// was written.
+func Writer(logs *logging.Logger) func(name, value string) bool {
+	return func(name, value string) bool {
+		if err := Write(name, value); err != nil {
+			logs.Errorf("Failed to write output %q: %v", name, err)
+			return false
+		}
+		return true
+	}
+}
+
+// ExportEnv exports an environment variable to the following steps of the job
+// through GITHUB_ENV. Outside GitHub Actions, where GITHUB_ENV is unset, only
+// the name is checked.
+func ExportEnv(name, value string) error {
+	return appendIfSet("GITHUB_ENV", name, value)
+}
+
+// SaveState saves a value through GITHUB_STATE; the runner exposes it to the
+// post step of the action as STATE_<name>. Outside GitHub Actions, where
+// GITHUB_STATE is unset, only the name is checked.
+func SaveState(name, value string) error {
+	return appendIfSet("GITHUB_STATE", name, value)
+}
+
+// Parse reads outputs in the format Write produces, which is also the format of
+// GITHUB_OUTPUT: name=value lines and name<<delimiter blocks ending with a line
+// holding only the delimiter.
+func Parse(raw string) (map[string]string, error) {
+	outputs := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" {
+			continue
+		}
+
+		if name, delimiter, ok := strings.Cut(line, "<<"); ok && !strings.Contains(name, "=") {
+			end := slices.Index(lines[i+1:], delimiter)
+			if end < 0 {
+				return nil, fmt.Errorf("output %q is missing its closing delimiter", name)
+			}
+			outputs[name] = strings.Join(lines[i+1:i+1+end], "\n")
+			i += end + 1
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid output line %q", line)
+		}
+		outputs[name] = value
+	}
+	return outputs, nil
+}
+
+// writeFallback writes an output for local runs and other CI systems: to the
+// LOKALISE_OUTPUT_FILE file, created if needed, or to stdout when that is
+// unset too. Single-line values are written as name=value.
+func writeFallback(stdout io.Writer, name, value string) error {
+	entry, err := formatEntry(name, value, true)
+	if err != nil {
+		return err
+	}
+
+	path := strings.TrimSpace(os.Getenv(FallbackEnv))
+	if path == "" {
+		_, err = io.WriteString(stdout, entry)
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("cannot open %s file: %w", FallbackEnv, err)
+	}
+
+	_, err = io.WriteString(file, entry)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write %s file: %w", FallbackEnv, err)
+	}
+	return nil
+}
+
+// appendIfSet appends name and value to the file named by envVar when that
+// variable is set, and otherwise only validates name.
+func appendIfSet(envVar, name, value string) error {
+	if os.Getenv(envVar) == "" {
+		_, err := formatEntry(name, value, false)
+		return err
+	}
+	return appendFile(envVar, name, value)
+}
+
+// appendFile appends name and value to the file named by envVar.
+func appendFile(envVar, name, value string) error {
+	path := os.Getenv(envVar)
+	if path == "" {
+		return fmt.Errorf("%s is not set", envVar)
+	}
+
+	entry, err := formatEntry(name, value, false)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("cannot open %s file: %w", envVar, err)
+	}
+
+	_, err = io.WriteString(file, entry)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write %s file: %w", envVar, err)
+	}
+	return nil
+}
+
+// formatEntry validates name and renders an environment file entry. Values are
+// written between random delimiters, with CRLF line endings normalized, so they
+// can't inject further entries; with plain set, single-line values are written
+// as name=value instead.
+func formatEntry(name, value string, plain bool) (string, error) {
+	if !outputName.MatchString(name) {
+		return "", fmt.Errorf("invalid name %q: expected letters, digits, \"-\", or \"_\", starting with a letter or \"_\"", name)
+	}
+
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	if plain && !strings.ContainsAny(value, "\r\n") {
+		return name + "=" + value + "\n", nil
+	}
+
+	delimiter := "ghadelimiter_" + rand.Text()
+	if strings.Contains(value, delimiter) {
+		return "", fmt.Errorf("value contains the delimiter %q", delimiter)
+	}
+	return fmt.Sprintf("%s<<%s\n%s\n%s\n", name, delimiter, value, delimiter), nil
+}
+
+// maxStepSummaryBytes is the largest job summary GitHub accepts for a step;
+// larger summaries make the upload fail, so they are not appended.
+const maxStepSummaryBytes = 1024 * 1024
+
+// AppendStepSummary appends markdown to the job summary, the file named by
+// GITHUB_STEP_SUMMARY. It does nothing when the variable is unset, as it is
+// outside GitHub Actions, or markdown is empty.
+func AppendStepSummary(markdown string) error {
+	path := strings.TrimSpace(os.Getenv("GITHUB_STEP_SUMMARY"))
+	if path == "" || markdown == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("cannot open step summary: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("cannot open step summary: %w", err)
+	}
+	if size := info.Size() + int64(len(markdown)) + 1; size > maxStepSummaryBytes {
+		file.Close()
+		return fmt.Errorf("step summary would grow to %d bytes, over the %d bytes GitHub accepts; skipping it", size, maxStepSummaryBytes)
+	}
+
+	_, writeErr := file.WriteString(markdown + "\n")
+	closeErr := file.Close()
+	if writeErr != nil {
+		return fmt.Errorf("cannot write step summary: %w", writeErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("cannot close step summary: %w", closeErr)
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package ghoutput
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/logging"
+)
+
+func TestWrite(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "output")
+	if err := os.WriteFile(out, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_OUTPUT", out)
+
+	if err := Write("files", "locales/en.json\nlocales/fr.json"); err != nil {
+		t.Fatalf("expected multiline write to succeed: %v", err)
+	}
+	if err := Write("count", "2"); err != nil {
+		t.Fatalf("expected single-line write to succeed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("expected 7 lines, got %d: %q", len(lines), data)
+	}
+
+	name, delimiter, ok := strings.Cut(lines[0], "<<")
+	if !ok || name != "files" || delimiter == "" {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	if lines[1] != "locales/en.json" || lines[2] != "locales/fr.json" || lines[3] != delimiter {
+		t.Fatalf("unexpected multiline block %q", lines[:4])
+	}
+	if !strings.HasPrefix(lines[4], "count<<") || lines[5] != "2" || lines[6] == delimiter {
+		t.Fatalf("unexpected single-line block %q", lines[4:])
+	}
+}
+
+func TestWrite_Invalid(t *testing.T) {
+	t.Setenv("GITHUB_OUTPUT", filepath.Join(t.TempDir(), "missing", "output"))
+	if err := Write("name", "value"); err == nil {
+		t.Fatal("expected failure for an unwritable GITHUB_OUTPUT")
+	}
+
+	for _, name := range []string{"", "a=b", "a\nb", "a<<b", "1st"} {
+		if err := Write(name, "value"); err == nil {
+			t.Fatalf("expected failure for name %q", name)
+		}
+	}
+}
+
+func TestWrite_Fallback(t *testing.T) {
+	fallback := filepath.Join(t.TempDir(), "outputs.env")
+	t.Setenv("GITHUB_OUTPUT", "")
+	t.Setenv("LOKALISE_OUTPUT_FILE", fallback)
+
+	if Write("count", "2") != nil || Write("files", "a.json\nb.json") != nil {
+		t.Fatal("expected fallback writes to succeed")
+	}
+	if err := Write("1st", "value"); err == nil {
+		t.Fatal("expected invalid names to fail")
+	}
+
+	data, err := os.ReadFile(fallback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 5 || lines[0] != "count=2" || !strings.HasPrefix(lines[1], "files<<") || lines[2] != "a.json" || lines[3] != "b.json" {
+		t.Fatalf("unexpected fallback file %q", data)
+	}
+	got, err := Parse(string(data))
+	if want := map[string]string{"count": "2", "files": "a.json\nb.json"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the fallback file to parse back to %v, got %v (%v)", want, got, err)
+	}
+
+	t.Setenv("LOKALISE_OUTPUT_FILE", filepath.Join(fallback, "nested"))
+	if err := Write("count", "2"); err == nil {
+		t.Fatal("expected failure for an unwritable LOKALISE_OUTPUT_FILE")
+	}
+}
+
+func TestWriter(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "output")
+	if err := os.WriteFile(out, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_OUTPUT", out)
+
+	var log strings.Builder
+	write := Writer(logging.New(&log, &log))
+	if !write("count", "2") {
+		t.Fatal("expected write to succeed")
+	}
+	if write("1st", "value") {
+		t.Fatal("expected invalid name to fail")
+	}
+	if !strings.Contains(log.String(), `Failed to write output "1st": invalid name "1st"`) {
+		t.Fatalf("expected failure to be logged, got %q", log.String())
+	}
+}
+
+func TestExportEnvAndSaveState(t *testing.T) {
+	dir := t.TempDir()
+	env, state := filepath.Join(dir, "env"), filepath.Join(dir, "state")
+	for _, f := range []string{env, state} {
+		if err := os.WriteFile(f, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("GITHUB_ENV", env)
+	t.Setenv("GITHUB_STATE", state)
+
+	if err := ExportEnv("LOKALISE_FILES_LIST", "/tmp/files.txt"); err != nil {
+		t.Fatalf("unexpected env error: %v", err)
+	}
+	if err := SaveState("paths_file", "/tmp/paths.txt\n"); err != nil {
+		t.Fatalf("unexpected state error: %v", err)
+	}
+
+	for file, want := range map[string]map[string]string{
+		env:   {"LOKALISE_FILES_LIST": "/tmp/files.txt"},
+		state: {"paths_file": "/tmp/paths.txt\n"},
+	} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "<<ghadelimiter_") {
+			t.Fatalf("expected the delimiter syntax in %s, got %q", filepath.Base(file), data)
+		}
+		got, err := Parse(string(data))
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected %s to parse back to %q, got %q (%v)", filepath.Base(file), want, got, err)
+		}
+	}
+
+	if ExportEnv("1BAD", "value") == nil || SaveState("a=b", "value") == nil {
+		t.Fatal("expected invalid names to fail")
+	}
+
+	t.Setenv("GITHUB_ENV", "")
+	t.Setenv("GITHUB_STATE", "")
+	if err := errors.Join(ExportEnv("NAME", "value"), SaveState("name", "value")); err != nil {
+		t.Fatalf("expected no-op outside GitHub Actions, got %v", err)
+	}
+	if ExportEnv("1BAD", "value") == nil {
+		t.Fatal("expected invalid names to fail outside GitHub Actions too")
+	}
+}
+
+func TestParse(t *testing.T) {
+	raw := "any_changed=true\n" +
+		"all_changed_files<<ghadelimiter_abc\r\nen.json,fr.json\r\nghadelimiter_abc\n" +
+		"translations_path<<ghadelimiter_def\nlocales\napps/web/i18n\nghadelimiter_def\n" +
+		"empty=\n" +
+		"expr=a<<b\n"
+
+	got, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"any_changed":       "true",
+		"all_changed_files": "en.json,fr.json",
+		"translations_path": "locales\napps/web/i18n",
+		"empty":             "",
+		"expr":              "a<<b",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatch.\nwant=%v\ngot=%v", want, got)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for raw, wantErr := range map[string]string{
+		"files<<EOF\nen.json\n": `output "files" is missing its closing delimiter`,
+		"no value":              `invalid output line "no value"`,
+	} {
+		if _, err := Parse(raw); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%q: expected error containing %q, got %v", raw, wantErr, err)
+		}
+	}
+}
+
+func TestWriteFallback_Stdout(t *testing.T) {
+	t.Setenv("LOKALISE_OUTPUT_FILE", "")
+
+	var b strings.Builder
+	if err := writeFallback(&b, "any_changed", "true"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.String() != "any_changed=true\n" {
+		t.Fatalf("unexpected stdout %q", b.String())
+	}
+}
+
+func TestAppendFile(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "output")
+	if err := os.WriteFile(out, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_OUTPUT", out)
+
+	if err := appendFile("GITHUB_OUTPUT", "_files-list", "a\r\nb"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(string(data), "\n"); len(lines) != 5 || lines[1] != "a" || lines[2] != "b" {
+		t.Fatalf("expected CRLF to be normalized, got %q", data)
+	}
+
+	for name, wantErr := range map[string]string{
+		"1st":  `invalid name "1st"`,
+		"a b":  `invalid name "a b"`,
+		"a=b":  `invalid name "a=b"`,
+		"":     `invalid name ""`,
+		"ok_1": "",
+	} {
+		err := appendFile("GITHUB_OUTPUT", name, "value")
+		if wantErr == "" {
+			if err != nil {
+				t.Fatalf("%q: unexpected error: %v", name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%q: expected error containing %q, got %v", name, wantErr, err)
+		}
+	}
+
+	t.Setenv("GITHUB_OUTPUT", "")
+	if err := appendFile("GITHUB_OUTPUT", "name", "value"); err == nil || !strings.Contains(err.Error(), "GITHUB_OUTPUT is not set") {
+		t.Fatalf("expected missing variable error, got %v", err)
+	}
+
+	t.Setenv("GITHUB_OUTPUT", filepath.Join(t.TempDir(), "missing", "output"))
+	if err := appendFile("GITHUB_OUTPUT", "name", "value"); err == nil || !strings.Contains(err.Error(), "cannot open GITHUB_OUTPUT file") {
+		t.Fatalf("expected open error, got %v", err)
+	}
+}
+
+func TestAppendStepSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	if err := os.WriteFile(path, []byte("# Before\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+
+	if err := AppendStepSummary("### Lokalise push"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := AppendStepSummary(""); err != nil {
+		t.Fatalf("unexpected error for an empty summary: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# Before\n### Lokalise push\n"; string(data) != want {
+		t.Fatalf("unexpected summary file %q, want %q", data, want)
+	}
+
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+	if err := AppendStepSummary("ignored"); err != nil {
+		t.Fatalf("unexpected error outside GitHub Actions: %v", err)
+	}
+
+	t.Setenv("GITHUB_STEP_SUMMARY", filepath.Join(path, "nested"))
+	if err := AppendStepSummary("x"); err == nil || !strings.Contains(err.Error(), "cannot open step summary") {
+		t.Fatalf("expected open error, got %v", err)
+	}
+}
+
+func TestAppendStepSummary_SizeGuard(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	existing := strings.Repeat("x", maxStepSummaryBytes-10)
+	if err := os.WriteFile(path, []byte(existing), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+
+	if err := AppendStepSummary("fits"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := AppendStepSummary("too large"); err == nil || !strings.Contains(err.Error(), "over the 1048576 bytes GitHub accepts") {
+		t.Fatalf("expected size error, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != existing+"fits\n" {
+		t.Fatalf("expected the oversized summary to be skipped, got %d bytes", len(data))
+	}
+}
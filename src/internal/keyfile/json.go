@@ -0,0 +1,116 @@
+package keyfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// parseJSON walks a JSON object. In ARB files, "@" keys hold metadata rather
+// than translations and are skipped.
+func parseJSON(data []byte, arb bool) ([]Entry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	lines := newLineIndex(data)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	var entries []Entry
+	var walk func(key string, skip bool) error
+	walk = func(key string, skip bool) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case json.Delim:
+			for i := 0; dec.More(); i++ {
+				child := strconv.Itoa(i)
+				if t == '{' {
+					name, err := dec.Token()
+					if err != nil {
+						return err
+					}
+					child = name.(string)
+				}
+				if err := walk(joinKey(key, child), skip); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token() // closing delimiter
+			return err
+		case string:
+			if !skip {
+				entries = append(entries, Entry{Key: key, Value: t, Line: lines.line(int(dec.InputOffset()) - 1)})
+			}
+		case json.Number:
+			if !skip {
+				entries = append(entries, Entry{Key: key, Value: t.String(), Line: lines.line(int(dec.InputOffset()) - 1)})
+			}
+		}
+		return nil
+	}
+
+	for dec.More() {
+		name, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := name.(string)
+		if err := walk(key, arb && strings.HasPrefix(key, "@")); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// parseYAML walks a YAML mapping. Rails-style files keep their root locale
+// key, e.g. "en.greeting".
+func parseYAML(data []byte) ([]Entry, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a YAML mapping")
+	}
+
+	var entries []Entry
+	var walk func(key string, n *yaml.Node)
+	walk = func(key string, n *yaml.Node) {
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				walk(joinKey(key, n.Content[i].Value), n.Content[i+1])
+			}
+		case yaml.SequenceNode:
+			for i, item := range n.Content {
+				walk(joinKey(key, strconv.Itoa(i)), item)
+			}
+		case yaml.AliasNode:
+			if n.Alias != nil {
+				walk(key, n.Alias)
+			}
+		case yaml.ScalarNode:
+			if n.Tag != "!!null" {
+				entries = append(entries, Entry{Key: key, Value: n.Value, Line: n.Line})
+			}
+		}
+	}
+	walk("", root)
+	return entries, nil
+}
@@ -0,0 +1,124 @@
+// Package keyfile reads the keys and values of translation files for the
+// checks run before upload. It understands the formats most repositories
+// push: JSON (and Flutter ARB), YAML, Java properties, Apple strings, and
+// Android XML resources. Nested keys are joined with dots.
+package keyfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Format is a translation file format understood by Parse.
+type Format string
+
+const (
+	JSON       Format = "json"
+	ARB        Format = "arb"
+	YAML       Format = "yaml"
+	Properties Format = "properties"
+	Strings    Format = "strings"
+	AndroidXML Format = "xml"
+)
+
+// ErrUnsupported is returned for files in a format Parse does not read.
+var ErrUnsupported = errors.New("unsupported file format")
+
+// Entry is one translation: its key, its value, and the 1-based line the
+// value is on. Items of Android plurals and string arrays are keyed by
+// their quantity or index below the Parent resource, which is the key
+// name Lokalise knows them by.
+type Entry struct {
+	Key    string
+	Value  string
+	Line   int
+	Parent string
+}
+
+// DetectFormat returns the format of path from its extension.
+func DetectFormat(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JSON, true
+	case ".arb":
+		return ARB, true
+	case ".yml", ".yaml":
+		return YAML, true
+	case ".properties":
+		return Properties, true
+	case ".strings":
+		return Strings, true
+	case ".xml":
+		return AndroidXML, true
+	}
+	return "", false
+}
+
+// Parse reads the entries of the file at path, in the order they appear.
+// Files in a format DetectFormat doesn't know yield ErrUnsupported.
+func Parse(path string) (Format, []Entry, error) {
+	format, ok := DetectFormat(path)
+	if !ok {
+		return "", nil, fmt.Errorf("%s: %w", path, ErrUnsupported)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	entries, err := ParseBytes(format, data)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	return format, entries, nil
+}
+
+// ParseBytes reads the entries of data in the given format.
+func ParseBytes(format Format, data []byte) ([]Entry, error) {
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	switch format {
+	case JSON:
+		return parseJSON(data, false)
+	case ARB:
+		return parseJSON(data, true)
+	case YAML:
+		return parseYAML(data)
+	case Properties:
+		return parseProperties(data), nil
+	case Strings:
+		return parseStrings(data)
+	case AndroidXML:
+		return parseAndroidXML(data)
+	}
+	return nil, ErrUnsupported
+}
+
+// joinKey appends a nested key to its parent.
+func joinKey(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// lineIndex maps byte offsets to 1-based line numbers.
+type lineIndex []int
+
+func newLineIndex(data []byte) lineIndex {
+	var starts lineIndex
+	for i, b := range data {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// line returns the line of the byte at offset.
+func (idx lineIndex) line(offset int) int {
+	return sort.SearchInts(idx, offset+1) + 1
+}
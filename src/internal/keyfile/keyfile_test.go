@@ -0,0 +1,170 @@
+package keyfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	for path, want := range map[string]Format{
+		"locales/en.json":              JSON,
+		"lib/l10n/app_en.arb":          ARB,
+		"config/locales/en.YML":        YAML,
+		"messages_en.properties":       Properties,
+		"en.lproj/Localizable.strings": Strings,
+		"res/values/strings.xml":       AndroidXML,
+	} {
+		if got, ok := DetectFormat(path); !ok || got != want {
+			t.Errorf("%s: expected %q, got %q", path, want, got)
+		}
+	}
+	if _, ok := DetectFormat("en.po"); ok {
+		t.Errorf("expected .po to be unsupported")
+	}
+}
+
+func TestParse(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "en.json")
+	if err := os.WriteFile(path, []byte("\xEF\xBB\xBF{\"a\": \"b\"}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	format, entries, err := Parse(path)
+	if err != nil || format != JSON || !reflect.DeepEqual(entries, []Entry{{Key: "a", Value: "b", Line: 1}}) {
+		t.Fatalf("unexpected result %q %+v (%v)", format, entries, err)
+	}
+
+	if _, _, err := Parse(filepath.Join(dir, "en.po")); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`["a"]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Parse(bad); err == nil {
+		t.Fatalf("expected an error for a JSON array")
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+		want   []Entry
+	}{
+		{
+			name:   "nested JSON",
+			format: JSON,
+			data:   "{\n  \"home\": {\n    \"title\": \"Hi \\\"you\\\"\",\n    \"items\": [\"one\", \"two\"]\n  },\n  \"count\": 3,\n  \"flag\": true,\n  \"none\": null\n}",
+			want: []Entry{
+				{Key: "home.title", Value: `Hi "you"`, Line: 3},
+				{Key: "home.items.0", Value: "one", Line: 4},
+				{Key: "home.items.1", Value: "two", Line: 4},
+				{Key: "count", Value: "3", Line: 6},
+			},
+		},
+		{
+			name:   "ARB metadata",
+			format: ARB,
+			data:   "{\n  \"@@locale\": \"en\",\n  \"hello\": \"Hello {name}\",\n  \"@hello\": {\"description\": \"Greeting\", \"placeholders\": {\"name\": {}}}\n}",
+			want:   []Entry{{Key: "hello", Value: "Hello {name}", Line: 3}},
+		},
+		{
+			name:   "YAML",
+			format: YAML,
+			data:   "en:\n  greeting: Hello\n  list:\n    - a\n  empty: ~\n  multi: |\n    line\n",
+			want: []Entry{
+				{Key: "en.greeting", Value: "Hello", Line: 2},
+				{Key: "en.list.0", Value: "a", Line: 4},
+				{Key: "en.multi", Value: "line\n", Line: 6},
+			},
+		},
+		{
+			name:   "properties",
+			format: Properties,
+			data:   "# comment\n! other\nkey1=value 1\nkey2 : value 2\nkey3 value3\nkey\\ 4=multi \\\n    line\nunicode=caf\\u00e9\n\nempty\n",
+			want: []Entry{
+				{Key: "key1", Value: "value 1", Line: 3},
+				{Key: "key2", Value: "value 2", Line: 4},
+				{Key: "key3", Value: "value3", Line: 5},
+				{Key: "key 4", Value: "multi line", Line: 6},
+				{Key: "unicode", Value: "café", Line: 8},
+				{Key: "empty", Value: "", Line: 10},
+			},
+		},
+		{
+			name:   "Apple strings",
+			format: Strings,
+			data:   "/* Title */\n\"title\" = \"Hello \\\"%@\\\"\";\n// note\nbare = \"Line\\nbreak\";\n\"uni\" = \"\\U00e9\";\n",
+			want: []Entry{
+				{Key: "title", Value: `Hello "%@"`, Line: 2},
+				{Key: "bare", Value: "Line\nbreak", Line: 4},
+				{Key: "uni", Value: "é", Line: 5},
+			},
+		},
+		{
+			name:   "Android XML",
+			format: AndroidXML,
+			data: `<?xml version="1.0" encoding="utf-8"?>
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string name="app_name">My App</string>
+    <string name="welcome">Hi <xliff:g id="name">%1$s</xliff:g>!</string>
+    <plurals name="files">
+        <item quantity="one">%d file</item>
+        <item quantity="other">%d files</item>
+    </plurals>
+    <string-array name="days">
+        <item>Mon</item>
+    </string-array>
+</resources>`,
+			want: []Entry{
+				{Key: "app_name", Value: "My App", Line: 3},
+				{Key: "welcome", Value: "Hi %1$s!", Line: 4},
+				{Key: "files.one", Value: "%d file", Line: 6, Parent: "files"},
+				{Key: "files.other", Value: "%d files", Line: 7, Parent: "files"},
+				{Key: "days.0", Value: "Mon", Line: 10, Parent: "days"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBytes(tt.format, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseBytes_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+	}{
+		{"invalid JSON", JSON, `{"a": }`},
+		{"YAML list", YAML, "- a\n- b\n"},
+		{"missing semicolon", Strings, `"a" = "b"`},
+		{"unterminated string", Strings, `"a" = "b`},
+		{"UTF-16 strings", Strings, "\xFF\xFE\"\x00"},
+		{"broken XML", AndroidXML, `<resources><string name="a">x</resources>`},
+		{"unknown format", Format("po"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseBytes(tt.format, []byte(tt.data)); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package keyfile
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseProperties reads Java properties: "key=value", "key: value", or
+// "key value" entries, with "#" and "!" comments, backslash escapes, and
+// lines continued by a trailing backslash.
+func parseProperties(data []byte) []Entry {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	var entries []Entry
+	for i := 0; i < len(lines); i++ {
+		start := i + 1
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		for continued(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+
+		key, value := splitProperty(line)
+		entries = append(entries, Entry{Key: unescapeProperty(key), Value: unescapeProperty(value), Line: start})
+	}
+	return entries
+}
+
+// continued reports whether line ends with an odd number of backslashes.
+func continued(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, `\`))
+	return n%2 == 1
+}
+
+// splitProperty splits a logical line at the first unescaped separator.
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':':
+			return line[:i], strings.TrimLeft(line[i+1:], " \t\f")
+		case ' ', '\t', '\f':
+			rest := strings.TrimLeft(line[i:], " \t\f")
+			if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+				rest = strings.TrimLeft(rest[1:], " \t\f")
+			}
+			return line[:i], rest
+		}
+	}
+	return line, ""
+}
+
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if r, err := strconv.ParseUint(s[i+1:min(i+5, len(s))], 16, 32); err == nil && i+5 <= len(s) {
+				b.WriteRune(rune(r))
+				i += 4
+				continue
+			}
+			b.WriteByte('u')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// parseStrings reads Apple .strings files: "key" = "value"; entries with
+// C-style comments in between.
+func parseStrings(data []byte) ([]Entry, error) {
+	if bytes.HasPrefix(data, []byte{0xFF, 0xFE}) || bytes.HasPrefix(data, []byte{0xFE, 0xFF}) {
+		return nil, fmt.Errorf("UTF-16 encoded .strings files are not supported; convert the file to UTF-8")
+	}
+	s := &stringsScanner{data: string(data), lines: newLineIndex(data)}
+
+	var entries []Entry
+	for {
+		s.skipSpace()
+		if s.pos >= len(s.data) {
+			return entries, nil
+		}
+		line := s.lines.line(s.pos)
+		key, err := s.token()
+		if err != nil {
+			return nil, err
+		}
+		if err := s.expect('='); err != nil {
+			return nil, err
+		}
+		value, err := s.token()
+		if err != nil {
+			return nil, err
+		}
+		if err := s.expect(';'); err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{Key: key, Value: value, Line: line})
+	}
+}
+
+type stringsScanner struct {
+	data  string
+	pos   int
+	lines lineIndex
+}
+
+// skipSpace skips whitespace and comments.
+func (s *stringsScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		rest := s.data[s.pos:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n' || rest[0] == '\r':
+			s.pos++
+		case strings.HasPrefix(rest, "//"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			s.pos += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				s.pos = len(s.data)
+				return
+			}
+			s.pos += end + 4
+		default:
+			return
+		}
+	}
+}
+
+func (s *stringsScanner) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", s.lines.line(s.pos), fmt.Sprintf(format, args...))
+}
+
+func (s *stringsScanner) expect(c byte) error {
+	s.skipSpace()
+	if s.pos >= len(s.data) || s.data[s.pos] != c {
+		return s.errorf("expected %q", c)
+	}
+	s.pos++
+	return nil
+}
+
+// token reads a quoted string or a bare word.
+func (s *stringsScanner) token() (string, error) {
+	s.skipSpace()
+	if s.pos >= len(s.data) {
+		return "", s.errorf("unexpected end of file")
+	}
+	if s.data[s.pos] != '"' {
+		start := s.pos
+		for s.pos < len(s.data) && strings.IndexByte(" \t\r\n=;\"", s.data[s.pos]) < 0 {
+			s.pos++
+		}
+		if s.pos == start {
+			return "", s.errorf("expected a string")
+		}
+		return s.data[start:s.pos], nil
+	}
+
+	var b strings.Builder
+	for s.pos++; s.pos < len(s.data); s.pos++ {
+		c := s.data[s.pos]
+		switch {
+		case c == '"':
+			s.pos++
+			return b.String(), nil
+		case c == '\\' && s.pos+1 < len(s.data):
+			s.pos++
+			switch e := s.data[s.pos]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'U', 'u':
+				if r, err := strconv.ParseUint(s.data[s.pos+1:min(s.pos+5, len(s.data))], 16, 32); err == nil && s.pos+5 <= len(s.data) {
+					b.WriteRune(rune(r))
+					s.pos += 4
+					continue
+				}
+				b.WriteByte(e)
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", s.errorf("unterminated string")
+}
+
+// parseAndroidXML reads Android string resources: <string>, <plurals> items
+// keyed by quantity, and <string-array> items keyed by index. Markup inside a
+// string, such as <xliff:g>, is kept as text.
+func parseAndroidXML(data []byte) ([]Entry, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("not valid UTF-8")
+	}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var entries []Entry
+	var parent string // name of the enclosing <plurals> or <string-array>
+	var items int
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			if end, ok := tok.(xml.EndElement); ok && (end.Name.Local == "plurals" || end.Name.Local == "string-array") {
+				parent = ""
+			}
+			continue
+		}
+
+		switch start.Name.Local {
+		case "plurals", "string-array":
+			parent, items = attr(start, "name"), 0
+		case "string", "item":
+			key := attr(start, "name")
+			if start.Name.Local == "item" {
+				if parent == "" {
+					continue
+				}
+				sub := attr(start, "quantity")
+				if sub == "" {
+					sub = strconv.Itoa(items)
+				}
+				items++
+				key = joinKey(parent, sub)
+			}
+			line, _ := dec.InputPos()
+			value, err := innerText(dec)
+			if err != nil {
+				return nil, err
+			}
+			if key != "" {
+				entries = append(entries, Entry{Key: key, Value: value, Line: line, Parent: parent})
+			}
+		}
+	}
+}
+
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// innerText reads up to the end of the current element and returns its text.
+func innerText(dec *xml.Decoder) (string, error) {
+	var b strings.Builder
+	for depth := 1; depth > 0; {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			b.Write(t)
+		}
+	}
+	return b.String(), nil
+}
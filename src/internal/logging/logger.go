@@ -0,0 +1,189 @@
+// Package logging implements the leveled logger shared by the commands.
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// logLevel orders log messages from the most to the least verbose.
+type logLevel int
+
+const (
+	logDebug logLevel = iota
+	logInfo
+	logWarning
+	logError
+)
+
+// logLevelNames are the LOG_LEVEL values and the names used in JSON output.
+var logLevelNames = []string{"debug", "info", "warning", "error"}
+
+// Logger writes the diagnostics of a command. Debug and info messages go to
+// out, warnings and errors to errOut. Registered secrets are replaced with
+// "***" in every message. In JSON mode each message is a single JSON object;
+// otherwise, with annotate set, warnings and errors become workflow
+// annotations and debug messages are shown only when the runner's debug
+// logging is on.
+type Logger struct {
+	mu       sync.Mutex
+	out      io.Writer
+	errOut   io.Writer
+	level    logLevel
+	json     bool
+	annotate bool
+	secrets  []string
+}
+
+// New returns a plain-text logger printing info messages and above.
+func New(out, errOut io.Writer) *Logger {
+	return &Logger{out: out, errOut: errOut, level: logInfo}
+}
+
+// Configure applies LOG_LEVEL and LOG_FORMAT ("text" or "json"). Inside GitHub
+// Actions, text messages are bridged to workflow commands, and LOG_LEVEL
+// defaults to debug when the runner's debug logging (RUNNER_DEBUG) is on.
+func (l *Logger) Configure() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.annotate = os.Getenv("GITHUB_ACTIONS") == "true"
+
+	level := strings.ToLower(strings.TrimSpace(os.Getenv("LOG_LEVEL")))
+	switch {
+	case level == "" && os.Getenv("RUNNER_DEBUG") == "1":
+		l.level = logDebug
+	case level == "":
+		l.level = logInfo
+	default:
+		i := slices.Index(logLevelNames, level)
+		if i < 0 {
+			return fmt.Errorf(`invalid LOG_LEVEL: allowed values are "debug", "info", "warning", "error"; got %q`, level)
+		}
+		l.level = logLevel(i)
+	}
+
+	switch format := strings.ToLower(strings.TrimSpace(os.Getenv("LOG_FORMAT"))); format {
+	case "", "text":
+		l.json = false
+	case "json":
+		l.json = true
+	default:
+		return fmt.Errorf(`invalid LOG_FORMAT: allowed values are "text", "json"; got %q`, format)
+	}
+	return nil
+}
+
+// AddSecret makes the logger redact value from later messages.
+func (l *Logger) AddSecret(value string) {
+	if value = strings.TrimSpace(value); value == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.secrets = append(l.secrets, value)
+}
+
+func (l *Logger) Debugf(format string, args ...any) { l.log(logDebug, format, args...) }
+func (l *Logger) Infof(format string, args ...any)  { l.log(logInfo, format, args...) }
+func (l *Logger) Warnf(format string, args ...any)  { l.log(logWarning, format, args...) }
+func (l *Logger) Errorf(format string, args ...any) { l.log(logError, format, args...) }
+
+// Location points a warning or error at a line of a file. In GitHub Actions
+// the message becomes an annotation shown next to that line in pull requests.
+type Location struct {
+	File  string
+	Line  int // 1-based; 0 for the whole file
+	Title string
+}
+
+// WarnAt logs a warning about loc.
+func (l *Logger) WarnAt(loc Location, format string, args ...any) {
+	l.logAt(logWarning, loc, format, args...)
+}
+
+// ErrorAt logs an error about loc.
+func (l *Logger) ErrorAt(loc Location, format string, args ...any) {
+	l.logAt(logError, loc, format, args...)
+}
+
+func (l *Logger) log(level logLevel, format string, args ...any) {
+	l.logAt(level, Location{}, format, args...)
+}
+
+func (l *Logger) logAt(level logLevel, loc Location, format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Annotated debug messages are filtered by the runner instead.
+	if level < l.level && !(level == logDebug && l.annotate && !l.json) {
+		return
+	}
+
+	msg := l.redact(fmt.Sprintf(format, args...))
+	loc.File, loc.Title = l.redact(loc.File), l.redact(loc.Title)
+
+	w := l.out
+	if level >= logWarning {
+		w = l.errOut
+	}
+
+	switch {
+	case l.json:
+		line, _ := json.Marshal(struct {
+			Level   string `json:"level"`
+			Message string `json:"msg"`
+			File    string `json:"file,omitempty"`
+			Line    int    `json:"line,omitempty"`
+		}{logLevelNames[level], msg, loc.File, loc.Line})
+		fmt.Fprintf(w, "%s\n", line)
+	case l.annotate && level != logInfo:
+		fmt.Fprintf(w, "::%s%s::%s\n", logLevelNames[level], loc.properties(), commandEscaper.Replace(msg))
+	case loc.File != "" && loc.Line > 0:
+		fmt.Fprintf(w, "%s%s:%d: %s\n", logPrefixes[level], loc.File, loc.Line, msg)
+	case loc.File != "":
+		fmt.Fprintf(w, "%s%s: %s\n", logPrefixes[level], loc.File, msg)
+	default:
+		fmt.Fprintf(w, "%s%s\n", logPrefixes[level], msg)
+	}
+}
+
+// redact replaces the registered secrets in s with "***".
+func (l *Logger) redact(s string) string {
+	for _, secret := range l.secrets {
+		s = strings.ReplaceAll(s, secret, "***")
+	}
+	return s
+}
+
+// properties renders loc as the properties of a workflow command.
+func (loc Location) properties() string {
+	var props []string
+	if loc.File != "" {
+		props = append(props, "file="+propertyEscaper.Replace(loc.File))
+	}
+	if loc.Line > 0 {
+		props = append(props, fmt.Sprintf("line=%d", loc.Line))
+	}
+	if loc.Title != "" {
+		props = append(props, "title="+propertyEscaper.Replace(loc.Title))
+	}
+	if len(props) == 0 {
+		return ""
+	}
+	return " " + strings.Join(props, ",")
+}
+
+// logPrefixes start plain-text messages of each level.
+var logPrefixes = []string{"Debug: ", "", "Warning: ", "Error: "}
+
+// commandEscaper escapes workflow command values, which are line-based.
+var commandEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+
+// propertyEscaper escapes workflow command properties such as file=.
+var propertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
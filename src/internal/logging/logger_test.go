@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogger_Text(t *testing.T) {
+	var out, errOut strings.Builder
+	l := New(&out, &errOut)
+
+	l.Debugf("hidden %d", 1)
+	l.Infof("Found %d files", 2)
+	l.Warnf("slow %s", "upload")
+	l.Errorf("boom")
+
+	if got := out.String(); got != "Found 2 files\n" {
+		t.Fatalf("unexpected out %q", got)
+	}
+	if got := errOut.String(); got != "Warning: slow upload\nError: boom\n" {
+		t.Fatalf("unexpected errOut %q", got)
+	}
+}
+
+func TestLogger_JSONAndRedaction(t *testing.T) {
+	var out strings.Builder
+	l := New(&out, &out)
+	l.json = true
+	l.AddSecret(" s3cret ")
+	l.AddSecret("")
+
+	l.Infof("token is %s", "s3cret")
+	l.Errorf("line1\nline2")
+
+	want := `{"level":"info","msg":"token is ***"}` + "\n" + `{"level":"error","msg":"line1\nline2"}` + "\n"
+	if got := out.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestLogger_LocationRedaction(t *testing.T) {
+	var out strings.Builder
+	l := New(&out, &out)
+	l.AddSecret("s3cret")
+
+	l.WarnAt(Location{File: "apps/s3cret/en.json", Line: 2}, "bad")
+	l.annotate = true
+	l.ErrorAt(Location{File: "s3cret.json", Title: "Token s3cret"}, "bad")
+
+	want := "Warning: apps/***/en.json:2: bad\n::error file=***.json,title=Token ***::bad\n"
+	if got := out.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestLogger_Annotations(t *testing.T) {
+	var out strings.Builder
+	l := New(&out, &out)
+	l.annotate = true
+
+	l.Debugf("details")
+	l.Infof("plain")
+	l.Warnf("50%% done\nnext")
+	l.Errorf("failed")
+
+	want := "::debug::details\nplain\n::warning::50%25 done%0Anext\n::error::failed\n"
+	if got := out.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestLogger_Locations(t *testing.T) {
+	loc := Location{File: "locales/a,b.json", Line: 3, Title: "Bad: key"}
+
+	t.Run("text", func(t *testing.T) {
+		var out strings.Builder
+		l := New(&out, &out)
+		l.WarnAt(loc, "problem")
+		l.ErrorAt(Location{File: "en.json"}, "whole file")
+		if want := "Warning: locales/a,b.json:3: problem\nError: en.json: whole file\n"; out.String() != want {
+			t.Fatalf("expected %q, got %q", want, out.String())
+		}
+	})
+
+	t.Run("annotations", func(t *testing.T) {
+		var out strings.Builder
+		l := New(&out, &out)
+		l.annotate = true
+		l.WarnAt(loc, "100%% wrong")
+		l.ErrorAt(Location{}, "plain")
+		want := "::warning file=locales/a%2Cb.json,line=3,title=Bad%3A key::100%25 wrong\n::error::plain\n"
+		if out.String() != want {
+			t.Fatalf("expected %q, got %q", want, out.String())
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var out strings.Builder
+		l := New(&out, &out)
+		l.json = true
+		l.WarnAt(loc, "problem")
+		if want := `{"level":"warning","msg":"problem","file":"locales/a,b.json","line":3}` + "\n"; out.String() != want {
+			t.Fatalf("expected %q, got %q", want, out.String())
+		}
+	})
+}
+
+func TestLogger_Configure(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "")
+	t.Setenv("RUNNER_DEBUG", "")
+	t.Setenv("LOG_LEVEL", " Warning ")
+	t.Setenv("LOG_FORMAT", "JSON")
+
+	l := New(nil, nil)
+	if err := l.Configure(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l.level != logWarning || !l.json || l.annotate {
+		t.Fatalf("unexpected logger %+v", l)
+	}
+
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+	t.Setenv("RUNNER_DEBUG", "1")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	if err := l.Configure(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l.level != logDebug || l.json || !l.annotate {
+		t.Fatalf("unexpected logger %+v", l)
+	}
+
+	for key, value := range map[string]string{"LOG_LEVEL": "verbose", "LOG_FORMAT": "xml"} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			if err := New(nil, nil).Configure(); err == nil || !strings.Contains(err.Error(), "invalid "+key) {
+				t.Fatalf("expected invalid %s error, got %v", key, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Mask emits ::add-mask:: on w so the runner hides value in all later log
+// output. Values that don't come from secrets (e.g. tokens handed over by a
+// credential helper) are not masked otherwise. Each line is masked on its own,
+// since the runner matches masks per line.
+func Mask(w io.Writer, value string) {
+	for line := range strings.SplitSeq(strings.ReplaceAll(value, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			fmt.Fprintf(w, "::add-mask::%s\n", commandEscaper.Replace(line))
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	var b strings.Builder
+	Mask(&b, "")
+	Mask(&b, "tok%en")
+	Mask(&b, "-----BEGIN KEY-----\r\nabc\n\n-----END KEY-----\n")
+
+	want := "::add-mask::tok%25en\n" +
+		"::add-mask::-----BEGIN KEY-----\n" +
+		"::add-mask::abc\n" +
+		"::add-mask::-----END KEY-----\n"
+	if b.String() != want {
+		t.Fatalf("expected %q, got %q", want, b.String())
+	}
+}
@@ -0,0 +1,193 @@
+// Package manifest reads MANIFEST_FILE (e.g. lokalise.yml), a checked-in file
+// describing the translation roots and their layout.
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bodrovis/lokalise-actions-common/v2/normalizers"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/namepattern"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+// Manifest is the translation layout read from MANIFEST_FILE.
+// It replaces TRANSLATIONS_PATH, FLAT_NAMING, and NAME_PATTERN; FileExts is
+// empty when the manifest leaves FILE_EXT in charge.
+//
+//	file_ext: [json, yaml]
+//	flat_naming: false
+//	roots:
+//	  - apps/web/locales
+//	  - path: apps/mobile/i18n
+//	    flat_naming: true
+//	  - path: packages/ui/i18n
+//	    name_pattern: ["**/*.yaml", "!**/*.generated.yaml"]
+type Manifest struct {
+	File              string
+	Paths             []string
+	FileExts          []string
+	FlatNamingByRoot  map[string]bool
+	NamePatternByRoot map[string]namepattern.Rule
+}
+
+var topKeys = map[string]struct{}{
+	"file_ext":    {},
+	"flat_naming": {},
+	"roots":       {},
+}
+
+var rootKeys = map[string]struct{}{
+	"path":         {},
+	"flat_naming":  {},
+	"name_pattern": {},
+}
+
+// ParseFile reads the optional MANIFEST_FILE. It returns nil when unset.
+func ParseFile() (*Manifest, error) {
+	raw := strings.TrimSpace(os.Getenv("MANIFEST_FILE"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	path, err := pathnorm.EnsureRepoRelativePath(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MANIFEST_FILE: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read manifest: %w", err)
+	}
+
+	m, err := Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", filepath.ToSlash(path), err)
+	}
+	m.File = filepath.ToSlash(path)
+	return m, nil
+}
+
+// Parse decodes a JSON or YAML manifest. Unknown keys are rejected so that
+// typos don't silently fall back to defaults.
+func Parse(raw string) (*Manifest, error) {
+	obj, err := envconf.ParseMap(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKeys(obj, topKeys); err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{FlatNamingByRoot: map[string]bool{}}
+
+	if value, ok := obj["file_ext"]; ok {
+		exts, ok := envconf.StringList(value)
+		if !ok {
+			return nil, fmt.Errorf("file_ext must be a string or a list of strings")
+		}
+		m.FileExts, err = normalizers.NormalizeFileExtensions(exts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file_ext: %w", err)
+		}
+	}
+
+	defaultFlat := false
+	if value, ok := obj["flat_naming"]; ok {
+		if defaultFlat, ok = value.(bool); !ok {
+			return nil, fmt.Errorf("flat_naming must be true or false")
+		}
+	}
+
+	roots, ok := obj["roots"].([]any)
+	if !ok || len(roots) == 0 {
+		return nil, fmt.Errorf("roots must be a non-empty list")
+	}
+
+	patterns := map[string]namepattern.Rule{}
+	for i, item := range roots {
+		root, flat, rule, err := parseRoot(item, defaultFlat)
+		if err != nil {
+			return nil, fmt.Errorf("roots[%d]: %w", i, err)
+		}
+		if _, dup := m.FlatNamingByRoot[root]; dup {
+			return nil, fmt.Errorf("roots[%d]: %q is listed more than once", i, root)
+		}
+
+		m.Paths = append(m.Paths, root)
+		m.FlatNamingByRoot[root] = flat
+		if rule.Pattern != "" {
+			patterns[root] = rule
+		}
+	}
+
+	// A nil map keeps the default layout for every root and lets NAME_REGEX apply.
+	if len(patterns) > 0 {
+		m.NamePatternByRoot = patterns
+	}
+	return m, nil
+}
+
+// parseRoot decodes a roots entry: either a path or a mapping with a
+// path and optional flat_naming and name_pattern overrides.
+func parseRoot(item any, defaultFlat bool) (string, bool, namepattern.Rule, error) {
+	var (
+		rawPath = item
+		flat    = defaultFlat
+		rule    namepattern.Rule
+	)
+
+	if obj, ok := item.(map[string]any); ok {
+		if err := checkKeys(obj, rootKeys); err != nil {
+			return "", false, namepattern.Rule{}, err
+		}
+		rawPath = obj["path"]
+
+		if value, ok := obj["flat_naming"]; ok {
+			if flat, ok = value.(bool); !ok {
+				return "", false, namepattern.Rule{}, fmt.Errorf("flat_naming must be true or false")
+			}
+		}
+
+		if value, ok := obj["name_pattern"]; ok {
+			lines, ok := envconf.StringList(value)
+			if !ok {
+				return "", false, namepattern.Rule{}, fmt.Errorf("name_pattern must be a string or a list of strings")
+			}
+			patterns, excludes, err := namepattern.Split(lines)
+			if err != nil {
+				return "", false, namepattern.Rule{}, err
+			}
+			if len(patterns) > 1 {
+				return "", false, namepattern.Rule{}, fmt.Errorf("got %d name patterns, expected one", len(patterns))
+			}
+			if len(patterns) == 1 {
+				rule = namepattern.Rule{Pattern: patterns[0], Excludes: excludes}
+			}
+		}
+	}
+
+	path, ok := rawPath.(string)
+	if !ok || strings.TrimSpace(path) == "" {
+		return "", false, namepattern.Rule{}, fmt.Errorf("path must be a non-empty string")
+	}
+	clean, err := pathnorm.EnsureRepoRelativePath(path)
+	if err != nil {
+		return "", false, namepattern.Rule{}, fmt.Errorf("invalid path: %w", err)
+	}
+	return filepath.ToSlash(clean), flat, rule, nil
+}
+
+// checkKeys rejects keys of obj missing from allowed.
+func checkKeys(obj map[string]any, allowed map[string]struct{}) error {
+	for key := range obj {
+		if _, ok := allowed[key]; !ok {
+			return fmt.Errorf("unknown key %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/namepattern"
+)
+
+func TestParse(t *testing.T) {
+	m, err := Parse(`
+flat_naming: true
+roots:
+  - locales
+  - path: ./apps/web/i18n/
+    flat_naming: false
+  - path: packages/ui
+    name_pattern: "**/*.yaml"
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"locales", "apps/web/i18n", "packages/ui"}; !reflect.DeepEqual(m.Paths, want) {
+		t.Fatalf("paths mismatch. want=%v got=%v", want, m.Paths)
+	}
+	if len(m.FileExts) != 0 {
+		t.Fatalf("expected no file exts, got %v", m.FileExts)
+	}
+	wantFlat := map[string]bool{"locales": true, "apps/web/i18n": false, "packages/ui": true}
+	if !reflect.DeepEqual(m.FlatNamingByRoot, wantFlat) {
+		t.Fatalf("flat naming mismatch. want=%v got=%v", wantFlat, m.FlatNamingByRoot)
+	}
+	wantPatterns := map[string]namepattern.Rule{"packages/ui": {Pattern: "**/*.yaml"}}
+	if !reflect.DeepEqual(m.NamePatternByRoot, wantPatterns) {
+		t.Fatalf("patterns mismatch. want=%v got=%v", wantPatterns, m.NamePatternByRoot)
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	m, err := Parse(`{"file_ext": "strings", "roots": ["ios/Resources"]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(m.Paths, []string{"ios/Resources"}) || !reflect.DeepEqual(m.FileExts, []string{"strings"}) {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+	if m.NamePatternByRoot != nil {
+		t.Fatalf("expected no name patterns, got %v", m.NamePatternByRoot)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr string
+	}{
+		{"not a mapping", "- locales", "cannot construct"},
+		{"unknown key", "roots: [locales]\ntranslations_path: locales", `unknown key "translations_path"`},
+		{"missing roots", "file_ext: json", "roots must be a non-empty list"},
+		{"empty roots", "roots: []", "roots must be a non-empty list"},
+		{"bad file_ext", "file_ext: 42\nroots: [locales]", "file_ext must be a string or a list of strings"},
+		{"bad flat_naming", "flat_naming: yes please\nroots: [locales]", "flat_naming must be true or false"},
+		{"unknown root key", "roots:\n  - path: locales\n    flat: true", `roots[0]: unknown key "flat"`},
+		{"missing path", "roots:\n  - flat_naming: true", "roots[0]: path must be a non-empty string"},
+		{"escaping path", "roots: [../locales]", "roots[0]: invalid path"},
+		{"glob path", "roots: ['locales/*']", "glob characters are not allowed"},
+		{"duplicate root", "roots: [locales, ./locales]", `roots[1]: "locales" is listed more than once`},
+		{"bad root flat_naming", "roots:\n  - path: locales\n    flat_naming: 1", "roots[0]: flat_naming must be true or false"},
+		{"bad name_pattern", "roots:\n  - path: locales\n    name_pattern: {a: b}", "name_pattern must be a string or a list of strings"},
+		{"two name patterns", "roots:\n  - path: locales\n    name_pattern: ['*.json', '*.yaml']", "got 2 name patterns, expected one"},
+		{"orphan exclusion", "roots:\n  - path: locales\n    name_pattern: ['!*.json']", "exclusions require a pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.raw)
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile(filepath.Join(dir, "lokalise.yml"), []byte("roots: [locales]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MANIFEST_FILE", "")
+	if m, err := ParseFile(); m != nil || err != nil {
+		t.Fatalf("expected no manifest, got %+v (%v)", m, err)
+	}
+
+	t.Setenv("MANIFEST_FILE", " ./lokalise.yml ")
+	m, err := ParseFile()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.File != "lokalise.yml" || !reflect.DeepEqual(m.Paths, []string{"locales"}) {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+
+	for raw, wantErr := range map[string]string{
+		"../lokalise.yml": "invalid MANIFEST_FILE",
+		"missing.yml":     "cannot read manifest",
+	} {
+		t.Setenv("MANIFEST_FILE", raw)
+		if _, err := ParseFile(); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected error containing %q, got %v", raw, wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package namepattern parses NAME_PATTERN and NAME_REGEX, the custom file
+// layouts shared by the commands that look for translation files.
+package namepattern
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bodrovis/lokalise-actions-common/v2/normalizers"
+	"github.com/bodrovis/lokalise-actions-common/v2/parsers"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+// Rule is a NAME_PATTERN glob plus the "!"-prefixed globs that exclude files
+// it would otherwise match.
+type Rule struct {
+	Pattern  string
+	Excludes []string
+}
+
+// ParseEnv reads NAME_PATTERN: either a single pattern applied to every root,
+// one pattern per line aligned with TRANSLATIONS_PATH entries, or a JSON/YAML
+// mapping of roots to patterns. Roots missing from the mapping use the default
+// layout. Lines starting with "!" exclude matching files (gitignore-style); in
+// the line forms they apply to every root and don't count towards the
+// alignment. normalizeRoot cleans the TRANSLATIONS_PATH entries the lines are
+// aligned with.
+//
+// The single form returns the default rule; the other forms return per-root
+// rules keyed by slash-separated roots.
+func ParseEnv(roots []string, normalizeRoot func(string) (string, error)) (Rule, map[string]Rule, error) {
+	raw := os.Getenv("NAME_PATTERN")
+	if envconf.IsPatternMapping(raw) {
+		byRoot, err := ParseMap(raw, roots)
+		return Rule{}, byRoot, err
+	}
+
+	patterns, excludes, err := Split(parsers.ParseStringArrayEnv("NAME_PATTERN"))
+	if err != nil {
+		return Rule{}, nil, err
+	}
+	if len(patterns) <= 1 {
+		rule := Rule{Excludes: excludes}
+		if len(patterns) == 1 {
+			rule.Pattern = patterns[0]
+		}
+		return rule, nil, nil
+	}
+
+	rawRoots := envconf.SplitListEnv("TRANSLATIONS_PATH")
+	if len(patterns) != len(rawRoots) {
+		return Rule{}, nil, fmt.Errorf("invalid NAME_PATTERN: got %d patterns for %d TRANSLATIONS_PATH entries", len(patterns), len(rawRoots))
+	}
+
+	byRoot := make(map[string]Rule, len(patterns))
+	for i, pattern := range patterns {
+		clean, err := normalizeRoot(rawRoots[i])
+		if err != nil {
+			return Rule{}, nil, fmt.Errorf("invalid TRANSLATIONS_PATH: %w", err)
+		}
+		root := filepath.ToSlash(clean)
+		if prev, ok := byRoot[root]; ok && prev.Pattern != pattern {
+			return Rule{}, nil, fmt.Errorf("invalid NAME_PATTERN: conflicting patterns for %q", root)
+		}
+		byRoot[root] = Rule{Pattern: pattern, Excludes: excludes}
+	}
+	return Rule{}, byRoot, nil
+}
+
+// ParseMap parses the mapping form of NAME_PATTERN. Keys must name configured
+// translation roots; values are a pattern or a list holding one pattern and
+// any number of "!" exclusions.
+func ParseMap(raw string, roots []string) (map[string]Rule, error) {
+	obj, err := envconf.ParseMap(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NAME_PATTERN: %w", err)
+	}
+
+	known := make(map[string]struct{}, len(roots))
+	for _, r := range roots {
+		known[filepath.ToSlash(r)] = struct{}{}
+	}
+
+	byRoot := make(map[string]Rule, len(obj))
+	for key, value := range obj {
+		clean, err := pathnorm.EnsureRepoRelativePath(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NAME_PATTERN: %w", err)
+		}
+		root := filepath.ToSlash(clean)
+		if _, ok := known[root]; !ok {
+			return nil, fmt.Errorf("invalid NAME_PATTERN: %q is not listed in TRANSLATIONS_PATH", key)
+		}
+
+		lines, ok := envconf.StringList(value)
+		if !ok {
+			return nil, fmt.Errorf("invalid NAME_PATTERN: pattern for %q must be a string or a list of strings", key)
+		}
+		patterns, excludes, err := Split(lines)
+		if err != nil {
+			return nil, err
+		}
+		if len(patterns) > 1 {
+			return nil, fmt.Errorf("invalid NAME_PATTERN: got %d patterns for %q, expected one", len(patterns), key)
+		}
+
+		var rule Rule
+		if len(patterns) == 1 {
+			rule = Rule{Pattern: patterns[0], Excludes: excludes}
+		}
+		if prev, ok := byRoot[root]; ok && (prev.Pattern != rule.Pattern || !slices.Equal(prev.Excludes, rule.Excludes)) {
+			return nil, fmt.Errorf("invalid NAME_PATTERN: conflicting patterns for %q", root)
+		}
+		byRoot[root] = rule
+	}
+	return byRoot, nil
+}
+
+// Split normalizes NAME_PATTERN entries, separating "!" exclusions from the
+// patterns. Exclusions are only meaningful next to a pattern.
+func Split(lines []string) (patterns, excludes []string, err error) {
+	for _, line := range lines {
+		rest, negated := strings.CutPrefix(strings.TrimSpace(line), "!")
+
+		pattern, err := normalizers.NormalizeOptionalNamePattern(rest)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid NAME_PATTERN: %w", err)
+		}
+
+		switch {
+		case !negated:
+			patterns = append(patterns, pattern)
+		case pattern == "":
+			return nil, nil, fmt.Errorf("invalid NAME_PATTERN: empty exclusion %q", line)
+		default:
+			excludes = append(excludes, pattern)
+		}
+	}
+
+	if len(excludes) > 0 && len(patterns) == 0 {
+		return nil, nil, fmt.Errorf("invalid NAME_PATTERN: exclusions require a pattern to exclude from")
+	}
+	return patterns, excludes, nil
+}
+
+// ParseRegexEnv reads the optional NAME_REGEX. The expression must match the
+// whole file path relative to a translations root and capture the language in
+// a group named "lang", e.g. messages_(?P<lang>[a-z]{2})\.properties.
+func ParseRegexEnv() (*regexp.Regexp, error) {
+	raw := strings.TrimSpace(os.Getenv("NAME_REGEX"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	re, err := regexp.Compile(`^(?:` + raw + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NAME_REGEX: %w", err)
+	}
+	if re.SubexpIndex("lang") < 0 {
+		return nil, fmt.Errorf("invalid NAME_REGEX: missing (?P<lang>...) capture group")
+	}
+	return re, nil
+}
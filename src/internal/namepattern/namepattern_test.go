@@ -0,0 +1,118 @@
+package namepattern
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/pathnorm"
+)
+
+func TestParseEnv(t *testing.T) {
+	t.Run("single pattern with exclusions", func(t *testing.T) {
+		t.Setenv("NAME_PATTERN", "**/*.yaml\n!**/*.generated.yaml\n ! ./tmp/** ")
+
+		rule, byRoot, err := ParseEnv([]string{"locales"}, pathnorm.EnsureRepoRelativePath)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := Rule{Pattern: "**/*.yaml", Excludes: []string{"**/*.generated.yaml", "tmp/**"}}
+		if !reflect.DeepEqual(rule, want) || byRoot != nil {
+			t.Fatalf("expected %v and no per-root rules, got %v and %v", want, rule, byRoot)
+		}
+	})
+
+	t.Run("one pattern per line", func(t *testing.T) {
+		t.Setenv("TRANSLATIONS_PATH", "./web\nmobile")
+		t.Setenv("NAME_PATTERN", "**/*.json\n!**/fixtures/**\n*.yaml")
+
+		_, byRoot, err := ParseEnv([]string{"web", "mobile"}, pathnorm.EnsureRepoRelativePath)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]Rule{
+			"web":    {Pattern: "**/*.json", Excludes: []string{"**/fixtures/**"}},
+			"mobile": {Pattern: "*.yaml", Excludes: []string{"**/fixtures/**"}},
+		}
+		if !reflect.DeepEqual(byRoot, want) {
+			t.Fatalf("expected %v, got %v", want, byRoot)
+		}
+	})
+
+	t.Run("mapping", func(t *testing.T) {
+		t.Setenv("NAME_PATTERN", "web:\n  - '**/*.yaml'\n  - '!**/*.generated.yaml'\nmobile: '*.strings'")
+
+		_, byRoot, err := ParseEnv([]string{"web", "mobile"}, pathnorm.EnsureRepoRelativePath)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]Rule{
+			"web":    {Pattern: "**/*.yaml", Excludes: []string{"**/*.generated.yaml"}},
+			"mobile": {Pattern: "*.strings"},
+		}
+		if !reflect.DeepEqual(byRoot, want) {
+			t.Fatalf("expected %v, got %v", want, byRoot)
+		}
+	})
+
+	t.Run("root normalizer errors are reported", func(t *testing.T) {
+		t.Setenv("TRANSLATIONS_PATH", "a\nb")
+		t.Setenv("NAME_PATTERN", "*.json\n*.yaml")
+
+		normalize := func(string) (string, error) { return "", errors.New("no globs here") }
+		_, _, err := ParseEnv([]string{"a", "b"}, normalize)
+		if err == nil || !strings.Contains(err.Error(), "invalid TRANSLATIONS_PATH: no globs here") {
+			t.Fatalf("expected normalizer error, got %v", err)
+		}
+	})
+
+	for _, tt := range []struct{ name, paths, pattern, wantErr string }{
+		{"count mismatch", "a\nb\nc", "*.json\n*.yaml", "got 2 patterns for 3 TRANSLATIONS_PATH entries"},
+		{"conflicting duplicate roots", "a\n./a", "*.json\n*.yaml", `conflicting patterns for "a"`},
+		{"unknown root in mapping", "a\nb", "c: '*.json'", `"c" is not listed in TRANSLATIONS_PATH`},
+		{"non-string mapping value", "a", "a: 1", `pattern for "a" must be a string`},
+		{"several patterns for one mapped root", "a", "a: ['*.json', '*.yaml']", `got 2 patterns for "a"`},
+		{"exclusion without pattern", "a", "!*.json", "exclusions require a pattern"},
+		{"empty exclusion", "a", "*.json\n!", "empty exclusion"},
+		{"escaping pattern", "a\nb", "*.json\n../*.json", "invalid NAME_PATTERN"},
+		{"malformed mapping", "a", "{a: ", "invalid NAME_PATTERN"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", tt.paths)
+			t.Setenv("NAME_PATTERN", tt.pattern)
+
+			roots := strings.Split(tt.paths, "\n")
+			_, _, err := ParseEnv(roots, pathnorm.EnsureRepoRelativePath)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseRegexEnv(t *testing.T) {
+	t.Setenv("NAME_REGEX", ` messages_(?P<lang>[a-z]{2})\.properties `)
+	re, err := ParseRegexEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !re.MatchString("messages_de.properties") || re.MatchString("old/messages_de.properties.bak") {
+		t.Fatalf("expected %v to match whole paths only", re)
+	}
+
+	t.Setenv("NAME_REGEX", "  ")
+	if re, err := ParseRegexEnv(); re != nil || err != nil {
+		t.Fatalf("expected no regex, got %v (%v)", re, err)
+	}
+
+	for regex, wantErr := range map[string]string{
+		`messages_([a-z]{2})\.json`: "missing (?P<lang>...) capture group",
+		`(?P<lang>[a-z`:             "invalid NAME_REGEX",
+	} {
+		t.Setenv("NAME_REGEX", regex)
+		if _, err := ParseRegexEnv(); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected error containing %q, got %v", regex, wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package pathnorm
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestComposeNFC(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"locales", "locales"},
+		{"caf\u00e9", "caf\u00e9"},
+		{"cafe\u0301", "caf\u00e9"},
+		{"u\u0308\u0304", "\u01d6"},      // two marks, composed in turn
+		{"\u0438\u0306", "\u0439"},       // Cyrillic short i
+		{"\u1100\u1161\u11a8", "\uac01"}, // Hangul L+V+T
+		{"x\u0301", "x\u0301"},           // no precomposed form
+		{"\u0915\u093c", "\u0915\u093c"}, // composition exclusion
+		{"e\u0301/n\u0303o\u0303", "\u00e9/\u00f1\u00f5"},
+	}
+
+	for _, tt := range tests {
+		if got := ComposeNFC(tt.in); got != tt.want {
+			t.Fatalf("ComposeNFC(%+q): expected %+q, got %+q", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestEnsureRepoRelativePath_Normalizes(t *testing.T) {
+	want := "apps/caf\u00e9/locales"
+	for _, raw := range []string{
+		"apps/caf\u00e9/locales",
+		"apps/cafe\u0301/locales",
+		"./apps//cafe\u0301/locales/",
+	} {
+		got, err := EnsureRepoRelativePath(raw)
+		if err != nil {
+			t.Fatalf("%+q: unexpected error: %v", raw, err)
+		}
+		if filepath.ToSlash(got) != want {
+			t.Fatalf("%+q: expected %+q, got %+q", raw, want, got)
+		}
+	}
+
+	if _, err := EnsureRepoRelativePath("../cafe\u0301"); err == nil {
+		t.Fatal("expected escaping path to be rejected")
+	}
+
+	got, err := EnsureRepoRelativePattern("cafe\u0301//**/*.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.ToSlash(got) != "caf\u00e9/**/*.json" {
+		t.Fatalf("unexpected pattern %+q", got)
+	}
+}
+
+func TestRepoFilePathAndWithinRoot(t *testing.T) {
+	path := RepoFilePath("./apps/café//locales/en.json")
+	if path != "apps/café/locales/en.json" {
+		t.Fatalf("unexpected path %+q", path)
+	}
+
+	for root, want := range map[string]bool{
+		".":               true,
+		"apps":            true,
+		"apps/café":       true,
+		"apps/ca":         false,
+		"apps/café/other": false,
+		path:              true,
+	} {
+		if got := WithinRoot(path, root); got != want {
+			t.Fatalf("WithinRoot(%q, %q): expected %v, got %v", path, root, want, got)
+		}
+	}
+}
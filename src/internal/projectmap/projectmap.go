@@ -0,0 +1,111 @@
+// Package projectmap parses PROJECT_MAPPINGS, which routes the files under
+// each translations root to a dedicated Lokalise project and, optionally, token.
+package projectmap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+// Mapping routes files under Root to a dedicated Lokalise project.
+// Token is optional; when empty, the default LOKALISE_API_TOKEN is used.
+type Mapping struct {
+	Root      string
+	ProjectID string
+	Token     string
+}
+
+// Parse parses the PROJECT_MAPPINGS variable; see ParseValue.
+func Parse() ([]Mapping, error) {
+	return ParseValue(os.Getenv("PROJECT_MAPPINGS"))
+}
+
+// ParseValue parses project mappings separated by newlines or commas (see
+// envconf.ParseKeyValueLines):
+//
+//	<root>=<project_id>[:<api_token>]
+//
+// Roots must be repo-relative paths and may be configured only once.
+func ParseValue(raw string) ([]Mapping, error) {
+	entries, err := envconf.ParseKeyValueLines(raw, func(rawRoot string) (string, error) {
+		root, err := pathnorm.EnsureRepoRelativePath(rawRoot)
+		if err != nil {
+			return "", fmt.Errorf("invalid root %q: %w", rawRoot, err)
+		}
+		return filepath.ToSlash(root), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid project_mappings: %w", err)
+	}
+
+	out := make([]Mapping, 0, len(entries))
+	for _, e := range entries {
+		projectID, token, _ := strings.Cut(e.Value, ":")
+		projectID = strings.TrimSpace(projectID)
+		token = strings.TrimSpace(token)
+		if projectID == "" {
+			return nil, fmt.Errorf("invalid project_mappings: line %d: project ID for %q is empty", e.Line, e.Key)
+		}
+
+		out = append(out, Mapping{Root: e.Key, ProjectID: projectID, Token: token})
+	}
+
+	return out, nil
+}
+
+// Tokens returns the tokens of all PROJECT_MAPPINGS entries. Entries are not
+// validated, so the tokens can be masked before a malformed value is reported.
+func Tokens() []string {
+	var tokens []string
+	for _, entry := range envconf.SplitListEnv("PROJECT_MAPPINGS") {
+		if _, value, ok := strings.Cut(entry, "="); ok {
+			entry = value
+		}
+		if _, token, ok := strings.Cut(entry, ":"); ok && strings.TrimSpace(token) != "" {
+			tokens = append(tokens, strings.TrimSpace(token))
+		}
+	}
+	return tokens
+}
+
+// Match returns the mapping for the most specific root containing filePath.
+func Match(mappings []Mapping, filePath string) (Mapping, bool) {
+	path := pathnorm.RepoFilePath(filePath)
+
+	var best Mapping
+	found := false
+
+	for _, m := range mappings {
+		if !pathnorm.WithinRoot(path, m.Root) {
+			continue
+		}
+		if !found || len(m.Root) > len(best.Root) {
+			best = m
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// TokensByProject maps each project ID to the token configured for it. When a
+// project is listed under several roots, the first entry with a token wins.
+// Projects without a dedicated token are left out, and the map is nil when
+// no project has one.
+func TokensByProject(mappings []Mapping) map[string]string {
+	var tokens map[string]string
+	for _, m := range mappings {
+		if _, ok := tokens[m.ProjectID]; !ok && m.Token != "" {
+			if tokens == nil {
+				tokens = make(map[string]string)
+			}
+			tokens[m.ProjectID] = m.Token
+		}
+	}
+	return tokens
+}
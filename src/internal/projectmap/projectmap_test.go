@@ -0,0 +1,114 @@
+package projectmap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []Mapping
+		wantErr string
+	}{
+		{
+			name: "empty input",
+			raw:  " \n ",
+		},
+		{
+			name: "comma and newline separated entries",
+			raw:  "packages/app=111.abc, ./packages/site/=222.def:tok\n\nlocales = 333.ghi ",
+			want: []Mapping{
+				{Root: "packages/app", ProjectID: "111.abc"},
+				{Root: "packages/site", ProjectID: "222.def", Token: "tok"},
+				{Root: "locales", ProjectID: "333.ghi"},
+			},
+		},
+		{
+			name:    "missing separator",
+			raw:     "packages/app",
+			wantErr: `line 1: expected <key>=<value>, got "packages/app"`,
+		},
+		{
+			name:    "empty project id",
+			raw:     "packages/app= :tok",
+			wantErr: `line 1: project ID for "packages/app" is empty`,
+		},
+		{
+			name:    "invalid root",
+			raw:     "../app=111.abc",
+			wantErr: `invalid project_mappings: line 1: invalid root "../app"`,
+		},
+		{
+			name:    "duplicate root",
+			raw:     "app=1.a, ./app=2.b",
+			wantErr: `"app" is already set on line 1`,
+		},
+		{
+			name:    "duplicate root on another line",
+			raw:     "app=1.a\nlocales=3.c\n./app/=2.b",
+			wantErr: `line 3: "app" is already set on line 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PROJECT_MAPPINGS", tt.raw)
+			got, err := Parse()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Fatalf("expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTokens(t *testing.T) {
+	t.Setenv("PROJECT_MAPPINGS", "packages/app=111.abc: app-token , packages/site=222.def\nbroken 333.ghi:broken-token\nlib=444.jkl:, x=5.m:to\\,ken")
+	got := Tokens()
+	want := []string{"app-token", "broken-token", "to,ken"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	mappings := []Mapping{
+		{Root: "packages", ProjectID: "100.aaa"},
+		{Root: "packages/app", ProjectID: "111.abc"},
+	}
+
+	for path, want := range map[string]string{
+		"packages/app/en.json":   "111.abc",
+		"./packages/lib/en.json": "100.aaa",
+		"packages/application":   "100.aaa",
+		"locales/en.json":        "",
+	} {
+		got, ok := Match(mappings, path)
+		if ok != (want != "") || got.ProjectID != want {
+			t.Fatalf("%s: expected %q, got %q (%v)", path, want, got.ProjectID, ok)
+		}
+	}
+}
+
+func TestTokensByProject(t *testing.T) {
+	got := TokensByProject([]Mapping{
+		{Root: "a", ProjectID: "1.a"},
+		{Root: "b", ProjectID: "1.a", Token: "tok-1"},
+		{Root: "c", ProjectID: "1.a", Token: "tok-other"},
+		{Root: "d", ProjectID: "2.b", Token: "tok-2"},
+	})
+	if want := map[string]string{"1.a": "tok-1", "2.b": "tok-2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
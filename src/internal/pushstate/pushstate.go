@@ -0,0 +1,282 @@
+// Package pushstate keeps what was pushed to each Lokalise project in a cache
+// directory meant to be restored and saved with actions/cache: the fingerprint
+// of every uploaded file, the last pushed commit, and the upload processes.
+//
+// Uploads run in parallel processes, so they never rewrite the state file:
+// each one adds a record under pending/, and Load merges the records into the
+// state. Save, run by a single process, folds them into state.json.
+package pushstate
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	stateFile  = "state.json"
+	pendingDir = "pending"
+
+	// version is bumped when the layout changes; older states are discarded.
+	version = 1
+
+	// maxProcesses caps the process history kept per project.
+	maxProcesses = 200
+)
+
+// projectIDPattern limits project IDs to characters that are safe as a
+// directory name. Lokalise IDs look like 123456789abcdef.12345678.
+var projectIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// FileRecord is the last successful upload of a file.
+type FileRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	LangISO     string `json:"lang_iso,omitempty"`
+	PushedAt    int64  `json:"pushed_at"`
+}
+
+// Process is an upload process started on Lokalise.
+type Process struct {
+	ID        string `json:"id"`
+	File      string `json:"file"`
+	StartedAt int64  `json:"started_at"`
+}
+
+// Upload is the record an upload adds for a successfully uploaded file.
+type Upload struct {
+	File        string `json:"file"`
+	Fingerprint string `json:"fingerprint"`
+	LangISO     string `json:"lang_iso,omitempty"`
+	ProcessID   string `json:"process_id,omitempty"`
+	PushedAt    int64  `json:"pushed_at"`
+}
+
+// State is the push state of one project.
+type State struct {
+	Version       int                   `json:"version"`
+	ProjectID     string                `json:"project_id"`
+	LastPushedSHA string                `json:"last_pushed_sha,omitempty"`
+	UpdatedAt     int64                 `json:"updated_at,omitempty"`
+	Files         map[string]FileRecord `json:"files"`
+	Processes     []Process             `json:"processes,omitempty"`
+
+	// pending lists the records merged by Load, removed by Save.
+	pending []string
+}
+
+// Dir returns the directory holding the state of projectID in cacheDir.
+func Dir(cacheDir, projectID string) (string, error) {
+	if !projectIDPattern.MatchString(projectID) {
+		return "", fmt.Errorf("invalid project ID %q for the push state: expected letters, digits, \".\", \"_\", or \"-\"", projectID)
+	}
+	return filepath.Join(cacheDir, projectID), nil
+}
+
+// Load reads the state of projectID with the pending upload records merged in.
+// A missing or outdated state yields an empty one.
+func Load(cacheDir, projectID string) (*State, error) {
+	dir, err := Dir(cacheDir, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &State{Version: version, ProjectID: projectID, Files: map[string]FileRecord{}}
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("cannot read push state: %w", err)
+	default:
+		var stored State
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return nil, fmt.Errorf("invalid push state %s: %w", filepath.Join(dir, stateFile), err)
+		}
+		if stored.Version == version {
+			stored.ProjectID = projectID
+			if stored.Files == nil {
+				stored.Files = map[string]FileRecord{}
+			}
+			s = &stored
+		}
+	}
+
+	if err := s.mergePending(filepath.Join(dir, pendingDir)); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// mergePending applies the upload records in dir in the order they were made.
+func (s *State) mergePending(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read pending push records: %w", err)
+	}
+
+	var uploads []Upload
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("cannot read pending push record: %w", err)
+		}
+		var u Upload
+		if err := json.Unmarshal(data, &u); err != nil {
+			return fmt.Errorf("invalid pending push record %s: %w", path, err)
+		}
+		uploads = append(uploads, u)
+		s.pending = append(s.pending, path)
+	}
+
+	slices.SortStableFunc(uploads, func(a, b Upload) int { return int(a.PushedAt - b.PushedAt) })
+	for _, u := range uploads {
+		s.Files[u.File] = FileRecord{Fingerprint: u.Fingerprint, LangISO: u.LangISO, PushedAt: u.PushedAt}
+		if u.ProcessID != "" {
+			s.Processes = append(s.Processes, Process{ID: u.ProcessID, File: u.File, StartedAt: u.PushedAt})
+		}
+	}
+	return nil
+}
+
+// Pending reports how many upload records Load merged that are not saved yet.
+func (s *State) Pending() int {
+	return len(s.pending)
+}
+
+// Unchanged reports whether file was last pushed with the given fingerprint,
+// so uploading it again would change nothing.
+func (s *State) Unchanged(file, fingerprint string) bool {
+	rec, ok := s.Files[file]
+	return ok && rec.Fingerprint == fingerprint
+}
+
+// Save writes the state to cacheDir and removes the pending records it
+// merged. The state file is replaced atomically.
+func (s *State) Save(cacheDir string) error {
+	dir, err := Dir(cacheDir, s.ProjectID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("cannot create push state directory: %w", err)
+	}
+
+	if len(s.Processes) > maxProcesses {
+		s.Processes = slices.Clone(s.Processes[len(s.Processes)-maxProcesses:])
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode push state: %w", err)
+	}
+	if err := writeAtomic(dir, stateFile, append(data, '\n')); err != nil {
+		return fmt.Errorf("cannot write push state: %w", err)
+	}
+
+	for _, path := range s.pending {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("cannot remove pending push record: %w", err)
+		}
+	}
+	s.pending = nil
+	return nil
+}
+
+// RecordUpload adds the record of a successful upload to projectID. Records
+// are written under a unique name and renamed into place, so concurrent
+// uploads and readers never see a partial record.
+func RecordUpload(cacheDir, projectID string, u Upload) error {
+	dir, err := Dir(cacheDir, projectID)
+	if err != nil {
+		return err
+	}
+	dir = filepath.Join(dir, pendingDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("cannot create pending push records directory: %w", err)
+	}
+
+	data, err := json.Marshal(u)
+	if err != nil {
+		return fmt.Errorf("cannot encode push record: %w", err)
+	}
+	if err := writeAtomic(dir, "upload-"+rand.Text()+".json", data); err != nil {
+		return fmt.Errorf("cannot write push record: %w", err)
+	}
+	return nil
+}
+
+// Projects lists the projects with a state in cacheDir.
+func Projects(cacheDir string) ([]string, error) {
+	entries, err := os.ReadDir(cacheDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read push state cache: %w", err)
+	}
+
+	var projects []string
+	for _, e := range entries {
+		if e.IsDir() && projectIDPattern.MatchString(e.Name()) {
+			projects = append(projects, e.Name())
+		}
+	}
+	return projects, nil
+}
+
+// Fingerprint returns the hex SHA-256 of the contents of the file at path
+// followed by params encoded as JSON. Uploads of the same contents with other
+// parameters, such as a new branch tag, get a different fingerprint.
+func Fingerprint(path string, params any) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	if err := json.NewEncoder(h).Encode(params); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeAtomic writes data to dir/name through a temporary file in dir.
+func writeAtomic(dir, name string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
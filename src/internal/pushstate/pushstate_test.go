@@ -0,0 +1,257 @@
+package pushstate
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	dir, err := Dir("/cache", "123abc.456")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join("/cache", "123abc.456"); dir != want {
+		t.Fatalf("expected %q, got %q", want, dir)
+	}
+
+	for _, id := range []string{"", "..", "../x", "a/b", ".hidden", "a b"} {
+		if _, err := Dir("/cache", id); err == nil || !strings.Contains(err.Error(), "invalid project ID") {
+			t.Errorf("project %q: expected invalid project ID error, got %v", id, err)
+		}
+	}
+}
+
+func TestLoad_Empty(t *testing.T) {
+	s, err := Load(t.TempDir(), "proj")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.ProjectID != "proj" || s.Version != version || len(s.Files) != 0 || s.Pending() != 0 {
+		t.Fatalf("expected an empty state, got %+v", s)
+	}
+	if s.Unchanged("en.json", "abc") {
+		t.Fatalf("expected no file to be unchanged in an empty state")
+	}
+}
+
+func TestRecordUpload_LoadSave(t *testing.T) {
+	cache := t.TempDir()
+
+	uploads := []Upload{
+		{File: "locales/en.json", Fingerprint: "old", LangISO: "en", ProcessID: "p1", PushedAt: 10},
+		{File: "locales/fr.json", Fingerprint: "fr1", LangISO: "fr", ProcessID: "p2", PushedAt: 20},
+		{File: "locales/en.json", Fingerprint: "new", LangISO: "en", ProcessID: "p3", PushedAt: 30},
+	}
+	// Records are written in reverse: merging must follow PushedAt, not file names.
+	for i := len(uploads) - 1; i >= 0; i-- {
+		if err := RecordUpload(cache, "proj", uploads[i]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	s, err := Load(cache, "proj")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Pending() != 3 {
+		t.Fatalf("expected 3 pending records, got %d", s.Pending())
+	}
+	if !s.Unchanged("locales/en.json", "new") || s.Unchanged("locales/en.json", "old") {
+		t.Fatalf("expected the latest upload of en.json to win, got %+v", s.Files["locales/en.json"])
+	}
+	if !s.Unchanged("locales/fr.json", "fr1") {
+		t.Fatalf("expected fr.json to be unchanged, got %+v", s.Files["locales/fr.json"])
+	}
+	if got := len(s.Processes); got != 3 || s.Processes[0].ID != "p1" || s.Processes[2].ID != "p3" {
+		t.Fatalf("expected processes p1..p3 in order, got %+v", s.Processes)
+	}
+
+	s.LastPushedSHA = "deadbeef"
+	if err := s.Save(cache); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Pending() != 0 {
+		t.Fatalf("expected no pending records after Save, got %d", s.Pending())
+	}
+	entries, err := os.ReadDir(filepath.Join(cache, "proj", pendingDir))
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected the pending records to be removed, got %v (%v)", entries, err)
+	}
+
+	reloaded, err := Load(cache, "proj")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reloaded.LastPushedSHA != "deadbeef" || !reloaded.Unchanged("locales/en.json", "new") || len(reloaded.Processes) != 3 {
+		t.Fatalf("expected the saved state back, got %+v", reloaded)
+	}
+	if reloaded.Pending() != 0 {
+		t.Fatalf("expected no pending records, got %d", reloaded.Pending())
+	}
+}
+
+func TestSave_CapsProcesses(t *testing.T) {
+	cache := t.TempDir()
+	s, err := Load(cache, "proj")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range maxProcesses + 5 {
+		s.Processes = append(s.Processes, Process{ID: strconv.Itoa(i)})
+	}
+	if err := s.Save(cache); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reloaded, err := Load(cache, "proj")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reloaded.Processes) != maxProcesses || reloaded.Processes[0].ID != "5" {
+		t.Fatalf("expected the latest %d processes, got %d starting at %q", maxProcesses, len(reloaded.Processes), reloaded.Processes[0].ID)
+	}
+}
+
+func TestLoad_OutdatedVersion(t *testing.T) {
+	cache := t.TempDir()
+	dir := filepath.Join(cache, "proj")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := `{"version":0,"last_pushed_sha":"abc","files":{"en.json":{"fingerprint":"x"}}}`
+	if err := os.WriteFile(filepath.Join(dir, stateFile), []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(cache, "proj")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.LastPushedSHA != "" || len(s.Files) != 0 || s.Version != version {
+		t.Fatalf("expected an outdated state to be discarded, got %+v", s)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Run("invalid state", func(t *testing.T) {
+		cache := t.TempDir()
+		dir := filepath.Join(cache, "proj")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, stateFile), []byte("{"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(cache, "proj"); err == nil || !strings.Contains(err.Error(), "invalid push state") {
+			t.Fatalf("expected invalid push state error, got %v", err)
+		}
+	})
+
+	t.Run("invalid pending record", func(t *testing.T) {
+		cache := t.TempDir()
+		dir := filepath.Join(cache, "proj", pendingDir)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "upload-x.json"), []byte("nope"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(cache, "proj"); err == nil || !strings.Contains(err.Error(), "invalid pending push record") {
+			t.Fatalf("expected invalid pending push record error, got %v", err)
+		}
+	})
+
+	t.Run("invalid project", func(t *testing.T) {
+		if _, err := Load(t.TempDir(), "../escape"); err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+}
+
+func TestLoad_IgnoresTemporaryFiles(t *testing.T) {
+	cache := t.TempDir()
+	dir := filepath.Join(cache, "proj", pendingDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A record still being written has not been renamed to *.json yet.
+	if err := os.WriteFile(filepath.Join(dir, ".upload-x.json.tmp-123"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(cache, "proj")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Pending() != 0 {
+		t.Fatalf("expected temporary files to be ignored, got %d pending", s.Pending())
+	}
+}
+
+func TestProjects(t *testing.T) {
+	cache := t.TempDir()
+	if got, err := Projects(filepath.Join(cache, "missing")); err != nil || got != nil {
+		t.Fatalf("expected no projects for a missing cache, got %v (%v)", got, err)
+	}
+
+	for _, id := range []string{"b.2", "a.1"} {
+		if err := RecordUpload(cache, id, Upload{File: "en.json"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(cache, "stray.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(cache, ".tmp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Projects(cache)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(got, ",") != "a.1,b.2" {
+		t.Fatalf("expected [a.1 b.2], got %v", got)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "en.json")
+	if err := os.WriteFile(path, []byte(`{"a":"b"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	params := map[string]any{"lang_iso": "en", "tags": []string{"main"}}
+	first, err := Fingerprint(path, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := Fingerprint(path, map[string]any{"tags": []string{"main"}, "lang_iso": "en"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != again || len(first) != 64 {
+		t.Fatalf("expected a stable SHA-256 fingerprint, got %q and %q", first, again)
+	}
+
+	other, _ := Fingerprint(path, map[string]any{"lang_iso": "en", "tags": []string{"dev"}})
+	if other == first {
+		t.Fatalf("expected other params to change the fingerprint")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"a":"c"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	changed, _ := Fingerprint(path, params)
+	if changed == first {
+		t.Fatalf("expected other contents to change the fingerprint")
+	}
+
+	if _, err := Fingerprint(filepath.Join(dir, "missing.json"), params); err == nil {
+		t.Fatalf("expected an error for a missing file")
+	}
+}
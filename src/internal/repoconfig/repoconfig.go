@@ -0,0 +1,244 @@
+// Package repoconfig loads the repository configuration file, a checked-in
+// lokalise-push.yml holding the settings otherwise passed as action inputs.
+package repoconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+// DefaultFile is read from the repository root when CONFIG_FILE is unset.
+const DefaultFile = "lokalise-push.yml"
+
+// keys maps each setting of the file to the environment variable it fills.
+// Setting names are the action inputs they replace.
+//
+//	translations_path: [apps/web/locales, apps/mobile/i18n]
+//	file_format: json
+//	flat_naming: [false, true]
+//	additional_params:
+//	  convert_placeholders: true
+//	max_retries: 5
+var keys = map[string]string{
+	// Roots.
+	"translations_path":       "TRANSLATIONS_PATH",
+	"translations_path_globs": "TRANSLATIONS_PATH_GLOBS",
+	"auto_discover_paths":     "AUTO_DISCOVER_PATHS",
+	"manifest_file":           "MANIFEST_FILE",
+	"exclude_paths":           "EXCLUDE_PATHS",
+	"exclude_patterns":        "EXCLUDE_PATTERNS",
+
+	// Formats and languages.
+	"project_id":            "LOKALISE_PROJECT_ID",
+	"file_format":           "FILE_FORMAT",
+	"file_ext":              "FILE_EXT",
+	"collapse_extensions":   "COLLAPSE_EXTENSIONS",
+	"use_format_preset":     "USE_FORMAT_PRESET",
+	"map_pot_to_po":         "MAP_POT_TO_PO",
+	"base_lang":             "BASE_LANG",
+	"additional_base_langs": "ADDITIONAL_BASE_LANGS",
+	"language_mappings":     "LANGUAGE_MAPPINGS",
+	"push_all_langs":        "PUSH_ALL_LANGS",
+	"skip_langs":            "SKIP_LANGS",
+
+	// Layouts.
+	"flat_naming":  "FLAT_NAMING",
+	"name_pattern": "NAME_PATTERN",
+	"name_regex":   "NAME_REGEX",
+
+	// Upload parameters.
+	"additional_params":   "ADDITIONAL_PARAMS",
+	"root_flag_overrides": "ROOT_FLAG_OVERRIDES",
+	"project_mappings":    "PROJECT_MAPPINGS",
+	"skip_tagging":        "SKIP_TAGGING",
+	"skip_default_flags":  "SKIP_DEFAULT_FLAGS",
+
+	// Retries and timeouts.
+	"max_retries":       "MAX_RETRIES",
+	"sleep_on_retry":    "SLEEP_TIME",
+	"http_timeout":      "HTTP_TIMEOUT",
+	"upload_timeout":    "UPLOAD_TIMEOUT",
+	"poll_initial_wait": "POLL_INITIAL_WAIT",
+	"poll_max_wait":     "POLL_MAX_WAIT",
+}
+
+// defaults fill the variables still empty after the file was applied. They
+// used to be action input defaults, which would have overridden the file.
+var defaults = []struct {
+	env, value string
+	applies    func() bool
+}{
+	{"BASE_LANG", "en", nil},
+	{"FILE_EXT", "json", nil},
+	{"TRANSLATIONS_PATH", "locales", func() bool {
+		return strings.TrimSpace(os.Getenv("AUTO_DISCOVER_PATHS")) != "true"
+	}},
+}
+
+// Apply loads the file named by CONFIG_FILE, or DefaultFile when it exists,
+// and sets every variable it configures that is empty in the environment, so
+// explicit variables override the file. It returns the file loaded, or ""
+// when there is none.
+func Apply() (string, error) {
+	path, err := locate()
+	if err != nil {
+		return "", err
+	}
+
+	if path != "" {
+		values, err := Load(path)
+		if err != nil {
+			return "", err
+		}
+		for env, value := range values {
+			if strings.TrimSpace(os.Getenv(env)) == "" {
+				os.Setenv(env, value)
+			}
+		}
+	}
+
+	for _, d := range defaults {
+		if strings.TrimSpace(os.Getenv(d.env)) == "" && (d.applies == nil || d.applies()) {
+			os.Setenv(d.env, d.value)
+		}
+	}
+	return path, nil
+}
+
+// locate resolves the configuration file. An explicit CONFIG_FILE must exist;
+// a missing DefaultFile just means the repository has none.
+func locate() (string, error) {
+	raw := strings.TrimSpace(os.Getenv("CONFIG_FILE"))
+	if raw == "" {
+		if _, err := os.Stat(DefaultFile); errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return DefaultFile, nil
+	}
+
+	path, err := pathnorm.EnsureRepoRelativePath(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid CONFIG_FILE: %w", err)
+	}
+	return path, nil
+}
+
+// Load reads a JSON or YAML configuration file and returns the value of each
+// environment variable it sets. Unknown settings are rejected so that typos
+// don't silently fall back to defaults.
+func Load(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config file: %w", err)
+	}
+
+	values, err := parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func parse(raw string) (map[string]string, error) {
+	obj, err := envconf.ParseMap(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	values := make(map[string]string, len(obj))
+	var errs []error
+	for _, name := range names {
+		env, ok := keys[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown key %q", name))
+			continue
+		}
+		if obj[name] == nil {
+			continue
+		}
+		value, err := envValue(obj[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		values[env] = value
+	}
+	if err := envconf.Join(errs); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// envValue renders a setting the way its variable is written by hand: scalars
+// as text, lists one item per line, and mappings and lists of mappings (such
+// as language_mappings) as JSON.
+func envValue(value any) (string, error) {
+	switch v := value.(type) {
+	case []any:
+		if slices.ContainsFunc(v, func(item any) bool { _, ok := item.(map[string]any); return ok }) {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		}
+		lines := make([]string, 0, len(v))
+		for i, item := range v {
+			line, ok := scalar(item)
+			if !ok {
+				return "", fmt.Errorf("item %d must be a string, number, or boolean", i)
+			}
+			if strings.ContainsAny(line, "\r\n") {
+				return "", fmt.Errorf("item %d must be a single line", i)
+			}
+			lines = append(lines, line)
+		}
+		return strings.Join(lines, "\n"), nil
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		s, ok := scalar(v)
+		if !ok {
+			return "", fmt.Errorf("unsupported value %v", v)
+		}
+		return s, nil
+	}
+}
+
+func scalar(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,197 @@
+package repoconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// envKeys are cleared before each test so the runner's environment can't leak in.
+var envKeys = []string{"CONFIG_FILE", "BASE_LANG", "FILE_EXT", "FILE_FORMAT", "TRANSLATIONS_PATH", "AUTO_DISCOVER_PATHS", "MAX_RETRIES", "ADDITIONAL_PARAMS", "FLAT_NAMING"}
+
+func setup(t *testing.T, config string) {
+	t.Helper()
+	for _, key := range envKeys {
+		t.Setenv(key, "")
+	}
+	t.Chdir(t.TempDir())
+	if config != "" {
+		if err := os.WriteFile(DefaultFile, []byte(config), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	got, err := parse(`
+translations_path:
+  - apps/web/locales
+  - apps/mobile/i18n
+flat_naming: [false, true]
+file_format: json
+base_lang: fr
+max_retries: 5
+sleep_on_retry: 1.5
+skip_tagging: true
+name_regex: ~
+language_mappings:
+  - original_language_iso: en_US
+    custom_language_iso: en-US
+additional_params:
+  convert_placeholders: true
+  tags: [release]
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"TRANSLATIONS_PATH": "apps/web/locales\napps/mobile/i18n",
+		"FLAT_NAMING":       "false\ntrue",
+		"FILE_FORMAT":       "json",
+		"BASE_LANG":         "fr",
+		"MAX_RETRIES":       "5",
+		"SLEEP_TIME":        "1.5",
+		"SKIP_TAGGING":      "true",
+		"LANGUAGE_MAPPINGS": `[{"custom_language_iso":"en-US","original_language_iso":"en_US"}]`,
+		"ADDITIONAL_PARAMS": `{"convert_placeholders":true,"tags":["release"]}`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatch.\nwant=%v\ngot=%v", want, got)
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	got, err := parse(`{"file_ext": ["json", "yml"], "upload_timeout": 900}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"FILE_EXT": "json\nyml", "UPLOAD_TIMEOUT": "900"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatch. want=%v got=%v", want, got)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr string
+	}{
+		{"not a mapping", "- locales", "cannot construct"},
+		{"unknown key", "translations_paths: locales", `unknown key "translations_paths"`},
+		{"secret", "api_token: abc", `unknown key "api_token"`},
+		{"nested list", "translations_path: [[locales]]", "translations_path: item 0 must be a string"},
+		{"multi-line item", "translations_path: [\"a\\nb\"]", "item 0 must be a single line"},
+		{"every problem", "a: 1\nb: 2", "2 configuration problems"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(tt.raw)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	seen := make(map[string]string)
+	for name, env := range keys {
+		if other, dup := seen[env]; dup {
+			t.Fatalf("%s and %s both set %s", name, other, env)
+		}
+		seen[env] = name
+		if strings.Contains(env, "TOKEN") {
+			t.Fatalf("%s must not be read from a checked-in file", env)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	setup(t, "file_format: json\nbase_lang: fr\nmax_retries: 5\n")
+	t.Setenv("MAX_RETRIES", "7")
+
+	file, err := Apply()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if file != DefaultFile {
+		t.Fatalf("expected %s to be loaded, got %q", DefaultFile, file)
+	}
+
+	for key, want := range map[string]string{
+		"FILE_FORMAT":       "json",
+		"BASE_LANG":         "fr",
+		"MAX_RETRIES":       "7",
+		"FILE_EXT":          "json",
+		"TRANSLATIONS_PATH": "locales",
+	} {
+		if got := os.Getenv(key); got != want {
+			t.Fatalf("%s: expected %q, got %q", key, want, got)
+		}
+	}
+}
+
+func TestApply_NoFile(t *testing.T) {
+	setup(t, "")
+	t.Setenv("AUTO_DISCOVER_PATHS", "true")
+	t.Setenv("BASE_LANG", "de")
+
+	file, err := Apply()
+	if err != nil || file != "" {
+		t.Fatalf("expected no file, got %q, %v", file, err)
+	}
+	if got := os.Getenv("BASE_LANG"); got != "de" {
+		t.Fatalf("expected BASE_LANG to be kept, got %q", got)
+	}
+	if got := os.Getenv("TRANSLATIONS_PATH"); got != "" {
+		t.Fatalf("expected no default TRANSLATIONS_PATH with AUTO_DISCOVER_PATHS, got %q", got)
+	}
+}
+
+func TestApply_ConfigFile(t *testing.T) {
+	setup(t, "base_lang: fr\n")
+	if err := os.MkdirAll("ci", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("ci", "push.yml"), []byte("base_lang: es\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", "./ci/push.yml")
+
+	file, err := Apply()
+	if err != nil || file != "ci/push.yml" {
+		t.Fatalf("expected ci/push.yml, got %q, %v", file, err)
+	}
+	if got := os.Getenv("BASE_LANG"); got != "es" {
+		t.Fatalf("expected BASE_LANG from CONFIG_FILE, got %q", got)
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     string
+		configFile string
+		wantErr    string
+	}{
+		{"missing CONFIG_FILE", "", "push.yml", "cannot read config file"},
+		{"CONFIG_FILE outside the repository", "", "../push.yml", "invalid CONFIG_FILE"},
+		{"invalid file", "retries: 3\n", "", "invalid config file lokalise-push.yml: unknown key \"retries\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup(t, tt.config)
+			t.Setenv("CONFIG_FILE", tt.configFile)
+
+			if _, err := Apply(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
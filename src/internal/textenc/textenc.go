@@ -0,0 +1,25 @@
+// Package textenc holds the byte-level checks shared by the commands that
+// inspect the encoding of translation files.
+package textenc
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// InvalidUTF8Offset returns the offset of the first invalid UTF-8 sequence, or -1.
+func InvalidUTF8Offset(data []byte) int {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
+
+// LineAt returns the 1-based line of the byte at offset.
+func LineAt(data []byte, offset int) int {
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
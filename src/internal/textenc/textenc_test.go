@@ -0,0 +1,27 @@
+package textenc
+
+import "testing"
+
+func TestInvalidUTF8Offset(t *testing.T) {
+	for data, want := range map[string]int{
+		"":                -1,
+		"plain ascii":     -1,
+		"café \U0001F600": -1,
+		"ok\n\xff":        3,
+		"caf\xe9":         3,
+		"\xe2\x82":        0,
+	} {
+		if got := InvalidUTF8Offset([]byte(data)); got != want {
+			t.Errorf("%q: expected %d, got %d", data, want, got)
+		}
+	}
+}
+
+func TestLineAt(t *testing.T) {
+	data := []byte("a\nb\n\nc")
+	for offset, want := range map[int]int{0: 1, 1: 1, 2: 2, 4: 3, 5: 4} {
+		if got := LineAt(data, offset); got != want {
+			t.Errorf("offset %d: expected line %d, got %d", offset, want, got)
+		}
+	}
+}
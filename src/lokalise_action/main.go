@@ -0,0 +1,144 @@
+// Command lokalise_action bundles the commands run by the action into a single
+// binary. The first argument names the command; the remaining arguments and
+// the environment are handed to it, with the repository configuration file
+// filling in the variables left empty.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"lokalise-push-action/detectchanges"
+	"lokalise-push-action/findallfiles"
+	"lokalise-push-action/lokalisedownload"
+	"lokalise-push-action/lokaliseupload"
+	"lokalise-push-action/postpush"
+	"lokalise-push-action/pushunits"
+	"lokalise-push-action/recordpush"
+	"lokalise-push-action/storetranslationpaths"
+
+	"lokalise-push-action/internal/repoconfig"
+)
+
+// exitFunc is a function variable that defaults to os.Exit.
+// Overridable in tests to assert exit behavior without terminating the process.
+var exitFunc = os.Exit
+
+// applyConfig loads the repository configuration file into the environment.
+// Overridable in tests.
+var applyConfig = repoconfig.Apply
+
+// command is a subcommand of the binary. run exits the process on failure;
+// check validates the configuration of run without running it. Commands with
+// unitConfig load a configuration file per unit themselves, so the repository
+// configuration file is not applied before they run.
+type command struct {
+	name       string
+	summary    string
+	run        func()
+	check      func() error
+	unitConfig bool
+}
+
+var commands = []command{
+	{"paths", "write the translation pathspecs", storetranslationpaths.Main, storetranslationpaths.Check, false},
+	{"changes", "list the changed translation files", detectchanges.Main, detectchanges.Check, false},
+	{"discover", "collect every translation file to push", findallfiles.Main, findallfiles.Check, false},
+	{"upload", "upload one translation file: upload <file>", lokaliseupload.Main, lokaliseupload.Check, false},
+	{"post-push", "run the post-push integrations", postpush.Main, postpush.Check, false},
+	{"wait", "wait until Lokalise has processed the uploads", postpush.Wait, postpush.Check, false},
+	{"check-run", "report the push as a GitHub check run", postpush.CheckRun, postpush.ValidateCheckRun, false},
+	{"status", "set the commit status of the push", postpush.CommitStatus, postpush.ValidateCommitStatus, false},
+	{"notify", "send the result of the push to Slack", postpush.Notify, postpush.ValidateNotify, false},
+	{"pull", "download translated files into the repository", lokalisedownload.Main, lokalisedownload.Check, false},
+	{"units", "push every unit of a monorepo, each with its own config file", pushunits.Main, pushunits.Check, true},
+	{"record", "record a successful push in the push state cache", recordpush.Main, recordpush.Check, false},
+}
+
+func main() {
+	exitFunc(dispatch(os.Args, commands, os.Stdout, os.Stderr))
+}
+
+// dispatch runs the command named by args[1] and returns the exit code.
+// The command sees os.Args without the binary name, so args[1] becomes its
+// program name.
+func dispatch(args []string, cmds []command, stdout, stderr io.Writer) int {
+	if len(args) < 2 {
+		printUsage(stderr, cmds)
+		return 2
+	}
+
+	name := args[1]
+	switch name {
+	case "help", "-h", "--help":
+		printUsage(stdout, cmds)
+		return 0
+	case "doctor":
+		file, err := applyConfig()
+		if err != nil {
+			fmt.Fprintf(stdout, "config: %v\n", err)
+			return 1
+		}
+		if file != "" {
+			fmt.Fprintf(stdout, "config: %s\n", file)
+		}
+		return doctor(args[2:], cmds, stdout)
+	}
+
+	i := slices.IndexFunc(cmds, func(c command) bool { return c.name == name })
+	if i < 0 {
+		fmt.Fprintf(stderr, "Error: unknown command %q\n", name)
+		printUsage(stderr, cmds)
+		return 2
+	}
+
+	if !cmds[i].unitConfig {
+		if _, err := applyConfig(); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	os.Args = args[1:]
+	cmds[i].run()
+	return 0
+}
+
+// doctor validates the configuration of the named commands, or of every
+// command when none is named, without running them. Every problem is printed;
+// the exit code is 1 if any command is misconfigured.
+func doctor(names []string, cmds []command, out io.Writer) int {
+	selected := cmds
+	if len(names) > 0 {
+		selected = nil
+		for _, name := range names {
+			i := slices.IndexFunc(cmds, func(c command) bool { return c.name == name })
+			if i < 0 {
+				fmt.Fprintf(out, "Error: unknown command %q\n", name)
+				return 2
+			}
+			selected = append(selected, cmds[i])
+		}
+	}
+
+	code := 0
+	for _, c := range selected {
+		if err := c.check(); err != nil {
+			fmt.Fprintf(out, "%s: %v\n", c.name, err)
+			code = 1
+			continue
+		}
+		fmt.Fprintf(out, "%s: ok\n", c.name)
+	}
+	return code
+}
+
+func printUsage(w io.Writer, cmds []command) {
+	fmt.Fprintf(w, "usage: lokalise_action <command> [arguments]\n\nCommands:\n")
+	for _, c := range cmds {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "  %-10s %s\n", "doctor", "check the configuration of the commands: doctor [command...]")
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testCommands(ran *[]string, gotArgs *[]string) []command {
+	cmd := func(name string, checkErr error) command {
+		return command{
+			name:    name,
+			summary: name + " things",
+			run: func() {
+				*ran = append(*ran, name)
+				*gotArgs = append([]string(nil), os.Args...)
+			},
+			check: func() error { return checkErr },
+		}
+	}
+	return []command{cmd("paths", nil), cmd("upload", errors.New("API token is required"))}
+}
+
+func TestDispatch(t *testing.T) {
+	args := os.Args
+	t.Cleanup(func() { os.Args = args })
+	stubConfig(t, "", nil)
+
+	t.Run("runs the named command with its arguments", func(t *testing.T) {
+		var ran, gotArgs []string
+		var stdout, stderr strings.Builder
+
+		code := dispatch([]string{"lokalise_action", "upload", "en.json"}, testCommands(&ran, &gotArgs), &stdout, &stderr)
+		if code != 0 {
+			t.Fatalf("expected exit code 0, got %d", code)
+		}
+		if !reflect.DeepEqual(ran, []string{"upload"}) {
+			t.Fatalf("unexpected commands run: %v", ran)
+		}
+		if !reflect.DeepEqual(gotArgs, []string{"upload", "en.json"}) {
+			t.Fatalf("unexpected os.Args: %v", gotArgs)
+		}
+	})
+
+	t.Run("missing or unknown command", func(t *testing.T) {
+		for _, args := range [][]string{{"lokalise_action"}, {"lokalise_action", "push"}} {
+			var ran, gotArgs []string
+			var stdout, stderr strings.Builder
+
+			if code := dispatch(args, testCommands(&ran, &gotArgs), &stdout, &stderr); code != 2 {
+				t.Fatalf("%v: expected exit code 2, got %d", args, code)
+			}
+			if len(ran) != 0 {
+				t.Fatalf("%v: expected no command to run, got %v", args, ran)
+			}
+			if !strings.Contains(stderr.String(), "usage: lokalise_action <command>") {
+				t.Fatalf("%v: expected usage, got %q", args, stderr.String())
+			}
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		var ran, gotArgs []string
+		var stdout, stderr strings.Builder
+
+		if code := dispatch([]string{"lokalise_action", "help"}, testCommands(&ran, &gotArgs), &stdout, &stderr); code != 0 {
+			t.Fatalf("expected exit code 0, got %d", code)
+		}
+		for _, want := range []string{"paths", "paths things", "upload things", "doctor"} {
+			if !strings.Contains(stdout.String(), want) {
+				t.Fatalf("expected usage to mention %q, got %q", want, stdout.String())
+			}
+		}
+	})
+}
+
+func TestDispatch_Config(t *testing.T) {
+	args := os.Args
+	t.Cleanup(func() { os.Args = args })
+
+	t.Run("config error stops the command", func(t *testing.T) {
+		stubConfig(t, "", errors.New(`invalid config file lokalise-push.yml: unknown key "retries"`))
+		var ran, gotArgs []string
+		var stdout, stderr strings.Builder
+
+		if code := dispatch([]string{"lokalise_action", "paths"}, testCommands(&ran, &gotArgs), &stdout, &stderr); code != 1 {
+			t.Fatalf("expected exit code 1, got %d", code)
+		}
+		if len(ran) != 0 {
+			t.Fatalf("expected no command to run, got %v", ran)
+		}
+		if !strings.Contains(stderr.String(), `Error: invalid config file lokalise-push.yml: unknown key "retries"`) {
+			t.Fatalf("unexpected stderr %q", stderr.String())
+		}
+	})
+
+	t.Run("commands with unit config skip the repository config", func(t *testing.T) {
+		stubConfig(t, "", errors.New("invalid config file lokalise-push.yml"))
+		var ran, gotArgs []string
+		var stdout, stderr strings.Builder
+		cmds := append(testCommands(&ran, &gotArgs), command{
+			name:       "units",
+			summary:    "units things",
+			run:        func() { ran = append(ran, "units") },
+			check:      func() error { return nil },
+			unitConfig: true,
+		})
+
+		if code := dispatch([]string{"lokalise_action", "units"}, cmds, &stdout, &stderr); code != 0 {
+			t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+		}
+		if !reflect.DeepEqual(ran, []string{"units"}) {
+			t.Fatalf("unexpected commands run: %v", ran)
+		}
+	})
+
+	t.Run("doctor names the config file", func(t *testing.T) {
+		stubConfig(t, "lokalise-push.yml", nil)
+		var ran, gotArgs []string
+		var stdout, stderr strings.Builder
+
+		if code := dispatch([]string{"lokalise_action", "doctor", "paths"}, testCommands(&ran, &gotArgs), &stdout, &stderr); code != 0 {
+			t.Fatalf("expected exit code 0, got %d", code)
+		}
+		if want := "config: lokalise-push.yml\npaths: ok\n"; stdout.String() != want {
+			t.Fatalf("expected %q, got %q", want, stdout.String())
+		}
+	})
+
+	t.Run("doctor reports a config error", func(t *testing.T) {
+		stubConfig(t, "", errors.New("invalid CONFIG_FILE: path escapes repo"))
+		var ran, gotArgs []string
+		var stdout, stderr strings.Builder
+
+		if code := dispatch([]string{"lokalise_action", "doctor"}, testCommands(&ran, &gotArgs), &stdout, &stderr); code != 1 {
+			t.Fatalf("expected exit code 1, got %d", code)
+		}
+		if want := "config: invalid CONFIG_FILE: path escapes repo\n"; stdout.String() != want {
+			t.Fatalf("expected %q, got %q", want, stdout.String())
+		}
+	})
+}
+
+// stubConfig replaces the configuration loader for the duration of the test.
+func stubConfig(t *testing.T, file string, err error) {
+	t.Helper()
+	orig := applyConfig
+	t.Cleanup(func() { applyConfig = orig })
+	applyConfig = func() (string, error) { return file, err }
+}
+
+func TestDoctor(t *testing.T) {
+	var ran, gotArgs []string
+	cmds := testCommands(&ran, &gotArgs)
+
+	t.Run("all commands", func(t *testing.T) {
+		var out strings.Builder
+		if code := doctor(nil, cmds, &out); code != 1 {
+			t.Fatalf("expected exit code 1, got %d", code)
+		}
+		want := "paths: ok\nupload: API token is required\n"
+		if out.String() != want {
+			t.Fatalf("expected %q, got %q", want, out.String())
+		}
+		if len(ran) != 0 {
+			t.Fatalf("doctor must not run commands, ran %v", ran)
+		}
+	})
+
+	t.Run("selected commands", func(t *testing.T) {
+		var out strings.Builder
+		if code := doctor([]string{"paths"}, cmds, &out); code != 0 || out.String() != "paths: ok\n" {
+			t.Fatalf("unexpected result %d, %q", code, out.String())
+		}
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		var out strings.Builder
+		if code := doctor([]string{"nope"}, cmds, &out); code != 2 || !strings.Contains(out.String(), `unknown command "nope"`) {
+			t.Fatalf("unexpected result %d, %q", code, out.String())
+		}
+	})
+}
+
+func TestCommands(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, c := range commands {
+		if seen[c.name] || c.name == "doctor" || c.name == "help" {
+			t.Fatalf("duplicate or reserved command name %q", c.name)
+		}
+		seen[c.name] = true
+		if c.run == nil || c.check == nil || c.summary == "" {
+			t.Fatalf("command %q is incomplete", c.name)
+		}
+	}
+	for _, name := range []string{"discover", "paths", "upload", "wait", "pull", "units", "record", "check-run", "status", "notify"} {
+		if !seen[name] {
+			t.Fatalf("missing command %q", name)
+		}
+	}
+}
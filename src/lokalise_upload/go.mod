@@ -1,14 +0,0 @@
-module lokalise_upload
-
-go 1.26
-
-toolchain go1.26.4
-
-require github.com/bodrovis/lokalise-actions-common/v2 v2.15.0
-
-require github.com/bodrovis/lokex/v2 v2.3.1
-
-require (
-	go.yaml.in/yaml/v4 v4.0.0-rc.6 // indirect
-	golang.org/x/sync v0.21.0 // indirect
-)
@@ -0,0 +1,162 @@
+package lokalisedownload
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bodrovis/lokalise-actions-common/v2/parsers"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+const (
+	defaultMaxRetries       = 3   // Default number of retries on rate limits.
+	defaultInitialSleepTime = 1   // Initial backoff in seconds; client applies exponential backoff.
+	maxSleepTime            = 60  // Maximum backoff in seconds.
+	defaultDownloadTimeout  = 600 // Total timeout for the download in seconds.
+	defaultHTTPTimeout      = 120 // Per-request HTTP timeout in seconds.
+	defaultPollInitialWait  = 1   // Initial wait before the first poll in seconds.
+	defaultPollMaxWait      = 120 // Total polling timeout in seconds.
+)
+
+// DownloadConfig aggregates all inputs required to download a translation bundle.
+type DownloadConfig struct {
+	ProjectID string
+	Token     string
+	// DestDir is the repo-relative directory the bundle is unzipped into.
+	DestDir          string
+	FileFormat       string
+	AdditionalParams string
+
+	// FilterLangs limits the bundle to these languages; empty means all.
+	FilterLangs []string
+
+	OriginalFilenames bool
+	AsyncMode         bool
+
+	MaxRetries       int
+	InitialSleepTime time.Duration
+	MaxSleepTime     time.Duration
+	DownloadTimeout  time.Duration
+	HTTPTimeout      time.Duration
+	PollInitialWait  time.Duration
+	PollMaxWait      time.Duration
+}
+
+// prepareConfig reads env vars, validates booleans, durations, and paths, and
+// assembles a DownloadConfig. All invalid variables are reported together
+// rather than stopping at the first one.
+func prepareConfig() (DownloadConfig, error) {
+	var errs []error
+
+	projectID, err := envconf.EnvOrFile("LOKALISE_PROJECT_ID")
+	errs = append(errs, err)
+
+	token, err := envconf.EnvOrFile("LOKALISE_API_TOKEN")
+	errs = append(errs, err)
+
+	additionalParams, err := envconf.EnvOrFile("ADDITIONAL_PARAMS")
+	errs = append(errs, err)
+
+	destDir, err := parseDestDir()
+	errs = append(errs, err)
+
+	filterLangs, err := parseFilterLangs()
+	errs = append(errs, err)
+
+	originalFilenames, err := parseBoolEnvDefault("ORIGINAL_FILENAMES", true)
+	errs = append(errs, err)
+
+	asyncMode, err := envconf.ParseBoolEnv("ASYNC_MODE")
+	errs = append(errs, err)
+
+	initialSleepTime, err := envconf.ParseDurationEnv("SLEEP_TIME", defaultInitialSleepTime*time.Second)
+	errs = append(errs, err)
+
+	downloadTimeout, err := envconf.ParseDurationEnv("DOWNLOAD_TIMEOUT", defaultDownloadTimeout*time.Second)
+	errs = append(errs, err)
+
+	httpTimeout, err := envconf.ParseDurationEnv("HTTP_TIMEOUT", defaultHTTPTimeout*time.Second)
+	errs = append(errs, err)
+
+	pollInitialWait, err := envconf.ParseDurationEnv("POLL_INITIAL_WAIT", defaultPollInitialWait*time.Second)
+	errs = append(errs, err)
+
+	pollMaxWait, err := envconf.ParseDurationEnv("POLL_MAX_WAIT", defaultPollMaxWait*time.Second)
+	errs = append(errs, err)
+
+	if err := envconf.Join(errs); err != nil {
+		return DownloadConfig{}, err
+	}
+
+	return DownloadConfig{
+		ProjectID:        strings.TrimSpace(projectID),
+		Token:            strings.TrimSpace(token),
+		DestDir:          destDir,
+		FileFormat:       strings.ToLower(strings.TrimSpace(os.Getenv("FILE_FORMAT"))),
+		AdditionalParams: strings.TrimSpace(additionalParams),
+
+		FilterLangs: filterLangs,
+
+		OriginalFilenames: originalFilenames,
+		AsyncMode:         asyncMode,
+
+		MaxRetries:       parsers.ParseUintEnv("MAX_RETRIES", defaultMaxRetries),
+		InitialSleepTime: initialSleepTime,
+		MaxSleepTime:     time.Duration(maxSleepTime) * time.Second,
+		DownloadTimeout:  downloadTimeout,
+		HTTPTimeout:      httpTimeout,
+		PollInitialWait:  pollInitialWait,
+		PollMaxWait:      pollMaxWait,
+	}, nil
+}
+
+// parseDestDir reads DEST_DIR, which must stay inside the repository so a
+// bundle can't be unpacked over unrelated files. Defaults to the repository root.
+func parseDestDir() (string, error) {
+	raw := strings.TrimSpace(os.Getenv("DEST_DIR"))
+	if raw == "" {
+		return ".", nil
+	}
+	clean, err := pathnorm.EnsureRepoRelativePath(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid DEST_DIR: %w", err)
+	}
+	return filepath.ToSlash(clean), nil
+}
+
+// parseFilterLangs reads FILTER_LANGS, a comma- or newline-separated list of
+// language ISO codes, dropping duplicates.
+func parseFilterLangs() ([]string, error) {
+	fields := strings.FieldsFunc(os.Getenv("FILTER_LANGS"), func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+
+	var langs []string
+	for _, f := range fields {
+		if strings.TrimSpace(f) == "" {
+			continue
+		}
+		lang, err := parsers.ParseLang("FILTER_LANGS", f)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	return langs, nil
+}
+
+// parseBoolEnvDefault is envconf.ParseBoolEnv returning def when key is empty.
+func parseBoolEnvDefault(key string, def bool) (bool, error) {
+	if strings.TrimSpace(os.Getenv(key)) == "" {
+		return def, nil
+	}
+	return envconf.ParseBoolEnv(key)
+}
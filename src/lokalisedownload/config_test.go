@@ -0,0 +1,213 @@
+package lokalisedownload
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var configEnvKeys = []string{
+	"LOKALISE_PROJECT_ID",
+	"LOKALISE_PROJECT_ID_FILE",
+	"LOKALISE_API_TOKEN",
+	"LOKALISE_API_TOKEN_FILE",
+	"ADDITIONAL_PARAMS",
+	"ADDITIONAL_PARAMS_FILE",
+	"DEST_DIR",
+	"FILE_FORMAT",
+	"FILTER_LANGS",
+	"ORIGINAL_FILENAMES",
+	"ASYNC_MODE",
+	"MAX_RETRIES",
+	"SLEEP_TIME",
+	"DOWNLOAD_TIMEOUT",
+	"HTTP_TIMEOUT",
+	"POLL_INITIAL_WAIT",
+	"POLL_MAX_WAIT",
+}
+
+func TestPrepareConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+		assert  func(t *testing.T, cfg DownloadConfig)
+	}{
+		{
+			name: "defaults are applied",
+			assert: func(t *testing.T, cfg DownloadConfig) {
+				t.Helper()
+
+				if cfg.DestDir != "." {
+					t.Fatalf("expected DestDir=., got %q", cfg.DestDir)
+				}
+				if !cfg.OriginalFilenames {
+					t.Fatalf("expected OriginalFilenames=true by default")
+				}
+				if cfg.AsyncMode {
+					t.Fatalf("expected AsyncMode=false, got true")
+				}
+				if cfg.FilterLangs != nil {
+					t.Fatalf("expected no FilterLangs, got %v", cfg.FilterLangs)
+				}
+				if cfg.MaxRetries != defaultMaxRetries {
+					t.Fatalf("expected MaxRetries=%d, got %d", defaultMaxRetries, cfg.MaxRetries)
+				}
+				if cfg.InitialSleepTime != defaultInitialSleepTime*time.Second {
+					t.Fatalf("unexpected InitialSleepTime %v", cfg.InitialSleepTime)
+				}
+				if cfg.MaxSleepTime != maxSleepTime*time.Second {
+					t.Fatalf("unexpected MaxSleepTime %v", cfg.MaxSleepTime)
+				}
+				if cfg.DownloadTimeout != defaultDownloadTimeout*time.Second {
+					t.Fatalf("unexpected DownloadTimeout %v", cfg.DownloadTimeout)
+				}
+				if cfg.HTTPTimeout != defaultHTTPTimeout*time.Second {
+					t.Fatalf("unexpected HTTPTimeout %v", cfg.HTTPTimeout)
+				}
+				if cfg.PollInitialWait != defaultPollInitialWait*time.Second || cfg.PollMaxWait != defaultPollMaxWait*time.Second {
+					t.Fatalf("unexpected poll waits %v/%v", cfg.PollInitialWait, cfg.PollMaxWait)
+				}
+			},
+		},
+		{
+			name: "values are read and trimmed",
+			env: map[string]string{
+				"LOKALISE_PROJECT_ID": " 123.abc ",
+				"LOKALISE_API_TOKEN":  " secret ",
+				"DEST_DIR":            "./locales/",
+				"FILE_FORMAT":         " JSON ",
+				"FILTER_LANGS":        "en, fr\nen",
+				"ORIGINAL_FILENAMES":  "false",
+				"ASYNC_MODE":          "true",
+				"MAX_RETRIES":         "5",
+				"DOWNLOAD_TIMEOUT":    "2m",
+			},
+			assert: func(t *testing.T, cfg DownloadConfig) {
+				t.Helper()
+
+				if cfg.ProjectID != "123.abc" || cfg.Token != "secret" {
+					t.Fatalf("unexpected credentials %q/%q", cfg.ProjectID, cfg.Token)
+				}
+				if cfg.DestDir != "locales" {
+					t.Fatalf("expected DestDir=locales, got %q", cfg.DestDir)
+				}
+				if cfg.FileFormat != "json" {
+					t.Fatalf("expected FileFormat=json, got %q", cfg.FileFormat)
+				}
+				if !reflect.DeepEqual(cfg.FilterLangs, []string{"en", "fr"}) {
+					t.Fatalf("unexpected FilterLangs %v", cfg.FilterLangs)
+				}
+				if cfg.OriginalFilenames || !cfg.AsyncMode {
+					t.Fatalf("unexpected flags original=%v async=%v", cfg.OriginalFilenames, cfg.AsyncMode)
+				}
+				if cfg.MaxRetries != 5 || cfg.DownloadTimeout != 2*time.Minute {
+					t.Fatalf("unexpected retries/timeout %d/%v", cfg.MaxRetries, cfg.DownloadTimeout)
+				}
+			},
+		},
+		{
+			name:    "dest dir escaping the repository",
+			env:     map[string]string{"DEST_DIR": "../outside"},
+			wantErr: "invalid DEST_DIR",
+		},
+		{
+			name:    "absolute dest dir",
+			env:     map[string]string{"DEST_DIR": "/etc"},
+			wantErr: "invalid DEST_DIR",
+		},
+		{
+			name:    "malformed filter language",
+			env:     map[string]string{"FILTER_LANGS": "en,fr/ca"},
+			wantErr: "FILTER_LANGS",
+		},
+		{
+			name:    "invalid boolean",
+			env:     map[string]string{"ORIGINAL_FILENAMES": "maybe"},
+			wantErr: "invalid ORIGINAL_FILENAMES",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range configEnvKeys {
+				t.Setenv(key, "")
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := prepareConfig()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.assert != nil {
+				tt.assert(t, cfg)
+			}
+		})
+	}
+}
+
+func TestPrepareConfig_FileIndirection(t *testing.T) {
+	for _, key := range configEnvKeys {
+		t.Setenv(key, "")
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Setenv("LOKALISE_PROJECT_ID_FILE", write("project_id", "111.abc\n"))
+	t.Setenv("LOKALISE_API_TOKEN_FILE", write("token", "secret-token\n"))
+	t.Setenv("ADDITIONAL_PARAMS_FILE", write("params.yml", "export_empty_as: skip\n"))
+
+	cfg, err := prepareConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ProjectID != "111.abc" || cfg.Token != "secret-token" {
+		t.Fatalf("unexpected credentials %q/%q", cfg.ProjectID, cfg.Token)
+	}
+	if cfg.AdditionalParams != "export_empty_as: skip" {
+		t.Fatalf("expected additional params from file, got %q", cfg.AdditionalParams)
+	}
+
+	t.Setenv("LOKALISE_API_TOKEN_FILE", filepath.Join(dir, "missing"))
+	if _, err := prepareConfig(); err == nil || !strings.Contains(err.Error(), "cannot read LOKALISE_API_TOKEN_FILE") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestPrepareConfig_ReportsAllErrors(t *testing.T) {
+	for _, key := range configEnvKeys {
+		t.Setenv(key, "")
+	}
+	t.Setenv("ASYNC_MODE", "maybe")
+	t.Setenv("DOWNLOAD_TIMEOUT", "forever")
+	t.Setenv("DEST_DIR", "../up")
+
+	_, err := prepareConfig()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"3 configuration problems", "invalid ASYNC_MODE", "invalid DOWNLOAD_TIMEOUT", "invalid DEST_DIR"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %q", want, err.Error())
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package lokalisedownload
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bodrovis/lokex/v2/client/download"
+
+	"lokalise-push-action/internal/apiclient"
+)
+
+// Downloader abstracts the download client for testability.
+type Downloader interface {
+	Download(ctx context.Context, unzipTo string, params download.DownloadParams) (string, error)
+	DownloadAsync(ctx context.Context, unzipTo string, params download.DownloadParams) (string, error)
+}
+
+// ClientFactory allows injecting a fake client in tests.
+type ClientFactory interface {
+	NewDownloader(cfg DownloadConfig) (Downloader, error)
+}
+
+type LokaliseFactory struct{}
+
+// NewDownloader wires lokex client with our retry, timeout, and polling settings.
+func (f *LokaliseFactory) NewDownloader(cfg DownloadConfig) (Downloader, error) {
+	lokaliseClient, err := apiclient.New(cfg.Token, cfg.ProjectID, apiclient.Settings{
+		MaxRetries:      cfg.MaxRetries,
+		HTTPTimeout:     cfg.HTTPTimeout,
+		InitialBackoff:  cfg.InitialSleepTime,
+		MaxBackoff:      cfg.MaxSleepTime,
+		PollInitialWait: cfg.PollInitialWait,
+		PollMaxWait:     cfg.PollMaxWait,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return download.NewDownloader(lokaliseClient), nil
+}
+
+// downloadFiles exports the project bundle and unzips it into cfg.DestDir.
+// The client validates the archive and refuses entries escaping DestDir.
+// With AsyncMode the export runs as a background process that is polled.
+func downloadFiles(ctx context.Context, cfg DownloadConfig, factory ClientFactory) error {
+	params, err := buildDownloadParams(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(cfg.DestDir, 0o755); err != nil {
+		return fmt.Errorf("cannot create destination directory %q: %w", cfg.DestDir, err)
+	}
+
+	downloader, err := factory.NewDownloader(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot create Lokalise API client: %w", err)
+	}
+
+	logs.Infof("Downloading %s files from project %s into %q", cfg.FileFormat, cfg.ProjectID, cfg.DestDir)
+
+	fetch := downloader.Download
+	if cfg.AsyncMode {
+		fetch = downloader.DownloadAsync
+	}
+	if _, err := fetch(ctx, cfg.DestDir, params); err != nil {
+		return fmt.Errorf("failed to download files: %w", err)
+	}
+
+	logs.Infof("Downloaded translation files into %q", cfg.DestDir)
+	return nil
+}
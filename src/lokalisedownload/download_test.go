@@ -0,0 +1,167 @@
+package lokalisedownload
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bodrovis/lokex/v2/client/download"
+)
+
+func TestDownloadFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     DownloadConfig
+		factory *fakeDownloadFactory
+		wantErr string
+		assert  func(t *testing.T, fd *fakeDownloader)
+	}{
+		{
+			name:    "sync download",
+			cfg:     DownloadConfig{ProjectID: "p", Token: "t", FileFormat: "json", OriginalFilenames: true},
+			factory: &fakeDownloadFactory{downloader: &fakeDownloader{}},
+			assert: func(t *testing.T, fd *fakeDownloader) {
+				t.Helper()
+				if !fd.syncCalled || fd.asyncCalled {
+					t.Fatalf("expected sync download only, got sync=%v async=%v", fd.syncCalled, fd.asyncCalled)
+				}
+				if fd.gotParams["format"] != "json" || fd.gotParams["original_filenames"] != true {
+					t.Fatalf("unexpected params %v", fd.gotParams)
+				}
+			},
+		},
+		{
+			name:    "async download",
+			cfg:     DownloadConfig{ProjectID: "p", Token: "t", FileFormat: "json", AsyncMode: true},
+			factory: &fakeDownloadFactory{downloader: &fakeDownloader{}},
+			assert: func(t *testing.T, fd *fakeDownloader) {
+				t.Helper()
+				if fd.syncCalled || !fd.asyncCalled {
+					t.Fatalf("expected async download only, got sync=%v async=%v", fd.syncCalled, fd.asyncCalled)
+				}
+			},
+		},
+		{
+			name:    "factory error",
+			cfg:     DownloadConfig{FileFormat: "json"},
+			factory: &fakeDownloadFactory{wantErr: errors.New("bad token")},
+			wantErr: "cannot create Lokalise API client: bad token",
+		},
+		{
+			name:    "download error",
+			cfg:     DownloadConfig{FileFormat: "json"},
+			factory: &fakeDownloadFactory{downloader: &fakeDownloader{returnErr: errors.New("network down")}},
+			wantErr: "failed to download files: network down",
+		},
+		{
+			name:    "invalid additional params",
+			cfg:     DownloadConfig{FileFormat: "json", AdditionalParams: "not: [valid"},
+			factory: &fakeDownloadFactory{downloader: &fakeDownloader{}},
+			wantErr: "invalid additional_params",
+			assert: func(t *testing.T, fd *fakeDownloader) {
+				t.Helper()
+				if fd.syncCalled {
+					t.Fatalf("expected no download on invalid params")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "locales", "nested")
+			tt.cfg.DestDir = dest
+
+			err := downloadFiles(context.Background(), tt.cfg, tt.factory)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if info, err := os.Stat(dest); err != nil || !info.IsDir() {
+					t.Fatalf("expected destination directory to be created: %v", err)
+				}
+				fd := tt.factory.downloader.(*fakeDownloader)
+				if fd.gotDest != dest {
+					t.Fatalf("expected unzip into %q, got %q", dest, fd.gotDest)
+				}
+			}
+			if tt.assert != nil {
+				tt.assert(t, tt.factory.downloader.(*fakeDownloader))
+			}
+		})
+	}
+}
+
+func TestLokaliseFactory_NewDownloader(t *testing.T) {
+	cfg := DownloadConfig{
+		ProjectID:        "123.abc",
+		Token:            "token",
+		MaxRetries:       2,
+		InitialSleepTime: time.Second,
+		MaxSleepTime:     10 * time.Second,
+		HTTPTimeout:      5 * time.Second,
+		PollInitialWait:  time.Second,
+		PollMaxWait:      5 * time.Second,
+	}
+
+	d, err := (&LokaliseFactory{}).NewDownloader(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d == nil {
+		t.Fatal("expected a downloader")
+	}
+}
+
+type fakeDownloader struct {
+	syncCalled  bool
+	asyncCalled bool
+	gotCtx      context.Context
+	gotDest     string
+	gotParams   download.DownloadParams
+
+	returnErr error
+}
+
+func (f *fakeDownloader) Download(ctx context.Context, unzipTo string, params download.DownloadParams) (string, error) {
+	f.syncCalled = true
+	return f.record(ctx, unzipTo, params)
+}
+
+func (f *fakeDownloader) DownloadAsync(ctx context.Context, unzipTo string, params download.DownloadParams) (string, error) {
+	f.asyncCalled = true
+	return f.record(ctx, unzipTo, params)
+}
+
+func (f *fakeDownloader) record(ctx context.Context, unzipTo string, params download.DownloadParams) (string, error) {
+	f.gotCtx = ctx
+	f.gotDest = unzipTo
+	f.gotParams = params
+	if f.returnErr != nil {
+		return "", f.returnErr
+	}
+	return "https://example.com/bundle.zip", nil
+}
+
+type fakeDownloadFactory struct {
+	wantErr error
+	gotCfg  DownloadConfig
+
+	downloader Downloader
+}
+
+func (f *fakeDownloadFactory) NewDownloader(cfg DownloadConfig) (Downloader, error) {
+	f.gotCfg = cfg
+	if f.wantErr != nil {
+		return nil, f.wantErr
+	}
+	return f.downloader, nil
+}
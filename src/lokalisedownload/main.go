@@ -0,0 +1,86 @@
+package lokalisedownload
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/logging"
+)
+
+// exitFunc is a function variable that defaults to os.Exit.
+// Overridable in tests to assert exit behavior without terminating the process.
+var exitFunc = os.Exit
+
+// logs is the logger of the command; Main configures it from the environment.
+var logs = logging.New(os.Stdout, os.Stderr)
+
+type downloaderFunc func(context.Context, DownloadConfig, ClientFactory) error
+
+// Main downloads a translation bundle into the repository, configured through environment variables.
+// It exits the process on failure.
+func Main() {
+	if err := logs.Configure(); err != nil {
+		returnWithError(err.Error())
+	}
+	if err := run(); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// Check reads and validates the configuration of Main without running it.
+func Check() error {
+	errs := []error{logs.Configure()}
+	cfg, err := prepareConfig()
+	if err == nil {
+		err = validate(cfg)
+	}
+	return envconf.Join(append(errs, err))
+}
+
+func run() error {
+	return runWith(
+		os.Args,
+		prepareConfig,
+		validate,
+		downloadFiles,
+		&LokaliseFactory{},
+	)
+}
+
+func runWith(
+	args []string,
+	prepare func() (DownloadConfig, error),
+	validate func(DownloadConfig) error,
+	download downloaderFunc,
+	factory ClientFactory,
+) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: lokalise_action pull (configured through environment variables)")
+	}
+
+	cfg, err := prepare()
+	if err != nil {
+		return err
+	}
+
+	// The token may come from an earlier step rather than a secret; keep it out of the log.
+	logging.Mask(os.Stdout, cfg.Token)
+	logs.AddSecret(cfg.Token)
+
+	if err := validate(cfg); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DownloadTimeout)
+	defer cancel()
+
+	return download(ctx, cfg, factory)
+}
+
+// returnWithError prints an error message to stderr and exits the program with a non-zero status code.
+func returnWithError(message string) {
+	logs.Errorf("%s", message)
+	exitFunc(1)
+}
@@ -0,0 +1,109 @@
+package lokalisedownload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// Hijack os.Exit so tests can assert hard exits.
+	exitFunc = func(code int) { panic(fmt.Sprintf("Exit called with code %d", code)) }
+
+	code := m.Run()
+
+	// Restore.
+	exitFunc = os.Exit
+	os.Exit(code)
+}
+
+func TestRunWith(t *testing.T) {
+	cfg := DownloadConfig{ProjectID: "p", Token: "t", FileFormat: "json", DownloadTimeout: 5 * time.Second}
+	prepare := func() (DownloadConfig, error) { return cfg, nil }
+	noValidate := func(DownloadConfig) error { return nil }
+
+	t.Run("happy path", func(t *testing.T) {
+		factory := &LokaliseFactory{}
+		called := false
+		download := func(ctx context.Context, got DownloadConfig, f ClientFactory) error {
+			called = true
+			if got.ProjectID != "p" || f != factory {
+				t.Fatalf("unexpected args %#v, %v", got, f)
+			}
+			if _, ok := ctx.Deadline(); !ok {
+				t.Fatalf("expected a deadline on the download context")
+			}
+			return nil
+		}
+
+		if err := runWith([]string{"lokalisedownload"}, prepare, noValidate, download, factory); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !called {
+			t.Fatal("expected download to be called")
+		}
+	})
+
+	t.Run("unexpected arguments", func(t *testing.T) {
+		err := runWith([]string{"lokalisedownload", "extra"}, prepare, noValidate, nil, nil)
+		if err == nil || !strings.Contains(err.Error(), "usage") {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+
+	t.Run("prepare error stops the run", func(t *testing.T) {
+		failing := func() (DownloadConfig, error) { return DownloadConfig{}, errors.New("bad env") }
+		err := runWith([]string{"lokalisedownload"}, failing, noValidate, nil, nil)
+		if err == nil || err.Error() != "bad env" {
+			t.Fatalf("expected prepare error, got %v", err)
+		}
+	})
+
+}
+
+func TestRunWith_ValidationFailure(t *testing.T) {
+	prepare := func() (DownloadConfig, error) { return DownloadConfig{}, nil }
+	download := func(context.Context, DownloadConfig, ClientFactory) error {
+		t.Fatal("download must not run")
+		return nil
+	}
+
+	err := runWith([]string{"lokalisedownload"}, prepare, validate, download, nil)
+	if err == nil || !strings.Contains(err.Error(), "project ID is required") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestReturnWithError(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "Exit called with code 1" {
+			t.Fatalf("expected exit with code 1, got %v", r)
+		}
+	}()
+	returnWithError("boom")
+}
+
+func TestCheck(t *testing.T) {
+	for _, key := range configEnvKeys {
+		t.Setenv(key, "")
+	}
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOKALISE_PROJECT_ID", "123.abc")
+	t.Setenv("LOKALISE_API_TOKEN", "token")
+	t.Setenv("FILE_FORMAT", "json")
+	if err := Check(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Setenv("FILE_FORMAT", "")
+	t.Setenv("LOG_LEVEL", "verbose")
+	err := Check()
+	if err == nil || !strings.Contains(err.Error(), "invalid LOG_LEVEL") || !strings.Contains(err.Error(), "FILE_FORMAT") {
+		t.Fatalf("expected LOG_LEVEL and FILE_FORMAT errors, got %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package lokalisedownload
+
+import (
+	"github.com/bodrovis/lokex/v2/client/download"
+
+	"lokalise-push-action/internal/envconf"
+)
+
+// buildDownloadParams constructs the request body for the files download
+// endpoint. ADDITIONAL_PARAMS is merged last, so it can override any default.
+func buildDownloadParams(cfg DownloadConfig) (download.DownloadParams, error) {
+	params := download.DownloadParams{
+		"format":             cfg.FileFormat,
+		"original_filenames": cfg.OriginalFilenames,
+	}
+	if len(cfg.FilterLangs) > 0 {
+		params["filter_langs"] = cfg.FilterLangs
+	}
+
+	if cfg.AdditionalParams != "" {
+		if err := envconf.MergeAdditionalParams(params, cfg.AdditionalParams); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
@@ -0,0 +1,55 @@
+package lokalisedownload
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bodrovis/lokex/v2/client/download"
+)
+
+func TestBuildDownloadParams(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		got, err := buildDownloadParams(DownloadConfig{FileFormat: "json", OriginalFilenames: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := download.DownloadParams{"format": "json", "original_filenames": true}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("filter langs", func(t *testing.T) {
+		got, err := buildDownloadParams(DownloadConfig{FileFormat: "po", FilterLangs: []string{"en", "fr"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got["filter_langs"], []string{"en", "fr"}) {
+			t.Fatalf("unexpected filter_langs %v", got["filter_langs"])
+		}
+		if got["original_filenames"] != false {
+			t.Fatalf("expected original_filenames=false, got %v", got["original_filenames"])
+		}
+	})
+
+	t.Run("additional params override defaults", func(t *testing.T) {
+		got, err := buildDownloadParams(DownloadConfig{
+			FileFormat:       "json",
+			AdditionalParams: "original_filenames: false\nexport_empty_as: skip\n",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got["original_filenames"] != false || got["export_empty_as"] != "skip" {
+			t.Fatalf("unexpected params %v", got)
+		}
+	})
+
+	t.Run("invalid additional params", func(t *testing.T) {
+		_, err := buildDownloadParams(DownloadConfig{FileFormat: "json", AdditionalParams: "[1, 2]"})
+		if err == nil || !strings.Contains(err.Error(), "invalid additional_params") {
+			t.Fatalf("expected additional_params error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package lokalisedownload
+
+import (
+	"fmt"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+)
+
+// validate performs input sanity checks before any network calls.
+// Every failed check is reported, with actionable messages for CI logs.
+func validate(cfg DownloadConfig) error {
+	return envconf.Join([]error{
+		validateRequiredFields(cfg),
+		validateProjectID(cfg.ProjectID),
+	})
+}
+
+// validateRequiredFields checks the minimum required Lokalise settings.
+func validateRequiredFields(cfg DownloadConfig) error {
+	var errs []error
+	if cfg.ProjectID == "" {
+		errs = append(errs, fmt.Errorf("project ID is required and cannot be empty"))
+	}
+	if cfg.Token == "" {
+		errs = append(errs, fmt.Errorf("API token is required and cannot be empty"))
+	}
+	if cfg.FileFormat == "" {
+		errs = append(errs, fmt.Errorf("file format (FILE_FORMAT) is required and cannot be empty"))
+	}
+	return envconf.Join(errs)
+}
+
+// validateProjectID rejects project lists: a bundle comes from a single project.
+func validateProjectID(projectID string) error {
+	if strings.ContainsAny(projectID, ",\r\n") {
+		return fmt.Errorf("project ID must name a single project, got %q", projectID)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package lokalisedownload
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := DownloadConfig{ProjectID: "123.abc", Token: "tok", FileFormat: "json"}
+
+	tests := []struct {
+		name    string
+		mutate  func(*DownloadConfig)
+		wantErr []string
+	}{
+		{name: "valid config"},
+		{
+			name:    "missing project ID",
+			mutate:  func(c *DownloadConfig) { c.ProjectID = "" },
+			wantErr: []string{"project ID is required"},
+		},
+		{
+			name:    "missing format",
+			mutate:  func(c *DownloadConfig) { c.FileFormat = "" },
+			wantErr: []string{"FILE_FORMAT"},
+		},
+		{
+			name:    "several projects",
+			mutate:  func(c *DownloadConfig) { c.ProjectID = "1.a,2.b" },
+			wantErr: []string{"single project"},
+		},
+		{
+			name: "all problems reported",
+			mutate: func(c *DownloadConfig) {
+				*c = DownloadConfig{}
+			},
+			wantErr: []string{"3 configuration problems", "project ID", "API token", "file format"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			if tt.mutate != nil {
+				tt.mutate(&cfg)
+			}
+
+			err := validate(cfg)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("expected error containing %q, got %q", want, err.Error())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package lokaliseupload
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"lokalise-push-action/internal/keyfile"
+)
+
+// Modes of the checks run on a file before it is uploaded.
+const (
+	checkOff  = "off"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// parseCheckMode reads the mode of a pre-upload check from envVar; empty
+// means off.
+func parseCheckMode(envVar string) (string, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv(envVar))); mode {
+	case "", checkOff, "false":
+		return checkOff, nil
+	case checkWarn, checkFail:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid %s %q: expected off, warn, or fail", envVar, os.Getenv(envVar))
+	}
+}
+
+// checkEnabled reports whether a check in mode runs.
+func checkEnabled(mode string) bool {
+	return mode == checkWarn || mode == checkFail
+}
+
+// runChecks runs the pre-upload checks enabled in cfg. A check in fail mode
+// that finds problems stops the upload; the file is then reported as failed
+// to every project it was meant for.
+func runChecks(cfg UploadConfig) error {
+	err := errors.Join(checkPlaceholders(cfg), checkKeyNames(cfg))
+	if err != nil {
+		reportStoppedUpload(cfg, err)
+	}
+	return err
+}
+
+// reportStoppedUpload reports a file stopped before upload as failed to every
+// project it was meant for.
+func reportStoppedUpload(cfg UploadConfig, err error) {
+	for _, projectID := range append([]string{cfg.ProjectID}, cfg.MirrorProjectIDs...) {
+		projectCfg := cfg
+		projectCfg.ProjectID = projectID
+		reportUploadResult(projectCfg, time.Now(), "", err)
+	}
+}
+
+// parseKeyFile reads the entries of the file to upload, from its converted
+// copy when there is one. Lines are dropped when transforms moved the keys,
+// so that problems are reported on the whole file instead.
+func parseKeyFile(cfg UploadConfig) (keyfile.Format, []keyfile.Entry, error) {
+	format, ok := keyfile.DetectFormat(cfg.FilePath)
+	if !ok {
+		return "", nil, fmt.Errorf("%s: %w", cfg.FilePath, keyfile.ErrUnsupported)
+	}
+	path := cfg.FilePath
+	if cfg.SourcePath != "" {
+		path = cfg.SourcePath
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	entries, err := keyfile.ParseBytes(format, data)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot parse %s: %w", cfg.FilePath, err)
+	}
+	if cfg.SourceReordered {
+		for i := range entries {
+			entries[i].Line = 0
+		}
+	}
+	return format, entries, nil
+}
@@ -0,0 +1,113 @@
+package lokaliseupload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseCheckMode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "", want: checkOff},
+		{value: "false", want: checkOff},
+		{value: " OFF ", want: checkOff},
+		{value: "warn", want: checkWarn},
+		{value: "Fail", want: checkFail},
+		{value: "true", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("SOME_CHECK", tt.value)
+		got, err := parseCheckMode("SOME_CHECK")
+		if tt.wantErr {
+			if err == nil || !strings.Contains(err.Error(), "invalid SOME_CHECK") {
+				t.Fatalf("%q: expected invalid SOME_CHECK error, got %v", tt.value, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("%q: expected %q, got %q (%v)", tt.value, tt.want, got, err)
+		}
+	}
+}
+
+func TestRunChecks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "en.json")
+	if err := os.WriteFile(path, []byte(`{"greeting": "Hello, {name"}`), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	t.Run("failed check is reported to every project without uploading", func(t *testing.T) {
+		captureLogs(t)
+		reportDir := t.TempDir()
+		ff := &recordingUploadFactory{}
+		cfg := UploadConfig{
+			FilePath:         path,
+			ProjectID:        "111.abc",
+			MirrorProjectIDs: []string{"222.def"},
+			Token:            "tok",
+			LangISO:          "en",
+			ReportDir:        reportDir,
+			PlaceholderCheck: checkFail,
+		}
+
+		err := uploadFile(context.Background(), cfg, ff)
+		if err == nil || !strings.Contains(err.Error(), "placeholder check found 1 problem(s)") {
+			t.Fatalf("expected placeholder check error, got %v", err)
+		}
+		if len(ff.projects) != 0 {
+			t.Fatalf("expected no uploads, got %v", ff.projects)
+		}
+
+		results := readUploadResults(t, reportDir)
+		var projects []string
+		for _, res := range results {
+			if res.Status != resultStatusFailed || !strings.Contains(res.Error, "placeholder check") {
+				t.Fatalf("expected failed result, got %#v", res)
+			}
+			projects = append(projects, res.ProjectID)
+		}
+		sort.Strings(projects)
+		if strings.Join(projects, ",") != "111.abc,222.def" {
+			t.Fatalf("expected results for both projects, got %v", projects)
+		}
+	})
+
+	t.Run("every check runs", func(t *testing.T) {
+		captureLogs(t)
+		rules, err := parseKeyNamingRules("max_length: 3")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cfg := UploadConfig{
+			FilePath:         path,
+			ProjectID:        "111.abc",
+			LangISO:          "en",
+			BaseLangs:        []string{"en"},
+			PlaceholderCheck: checkFail,
+			KeyNamingCheck:   checkFail,
+			KeyNamingRules:   rules,
+		}
+
+		err = runChecks(cfg)
+		if err == nil || !strings.Contains(err.Error(), "placeholder check") || !strings.Contains(err.Error(), "key naming check") {
+			t.Fatalf("expected errors from both checks, got %v", err)
+		}
+	})
+
+	t.Run("warnings do not stop the upload", func(t *testing.T) {
+		captureLogs(t)
+		cfg := UploadConfig{FilePath: path, ProjectID: "111.abc", PlaceholderCheck: checkWarn}
+		if err := runChecks(cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
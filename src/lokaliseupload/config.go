@@ -0,0 +1,210 @@
+package lokaliseupload
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bodrovis/lokalise-actions-common/v2/parsers"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/ghcontext"
+)
+
+const (
+	defaultMaxRetries       = 3   // Default number of retries on rate limits.
+	defaultInitialSleepTime = 1   // Initial backoff in seconds; client applies exponential backoff.
+	maxSleepTime            = 60  // Maximum backoff in seconds.
+	defaultUploadTimeout    = 600 // Total timeout for a single upload in seconds.
+	defaultHTTPTimeout      = 120 // Per-request HTTP timeout in seconds.
+	defaultPollInitialWait  = 1   // Initial wait before the first poll in seconds.
+	defaultPollMaxWait      = 120 // Total polling timeout in seconds.
+	defaultHookTimeout      = 300 // Timeout for a single hook command in seconds.
+)
+
+// UploadConfig aggregates all inputs required to upload a single file.
+type UploadConfig struct {
+	FilePath          string
+	ProjectID         string
+	Token             string
+	LangISO           string
+	GitHubRefName     string
+	AdditionalParams  string
+	RootFlagOverrides string
+	FileFormat        string
+	LanguageMappings  string
+	ReportDir         string
+	// CacheDir holds the push state; files unchanged since their last push are skipped.
+	CacheDir string
+	// SourcePath, when set, is a converted copy of FilePath uploaded in its place.
+	SourcePath string
+	// SourceReordered reports that keys may be on other lines in SourcePath than in FilePath.
+	SourceReordered bool
+	// Transforms lists the transforms configured for the file's root (TRANSFORMS).
+	Transforms []string
+
+	// MirrorProjectIDs lists extra projects receiving the same file.
+	MirrorProjectIDs []string
+	// SkipLangs lists languages that must never be uploaded.
+	SkipLangs []string
+	// SkipReason, when set, explains why the file is skipped instead of uploaded.
+	SkipReason string
+
+	SkipTagging      bool
+	SkipPolling      bool
+	SkipDefaultFlags bool
+	MapPotToPo       bool
+	UseFormatPreset  bool
+	PushAllLangs     bool
+	// ForceUpload uploads files even when the push state says they are unchanged.
+	ForceUpload bool
+
+	// PlaceholderCheck is the mode of the placeholder check: off, warn, or fail.
+	PlaceholderCheck string
+	// NormalizeEncoding is off, transcode, or fail; see normalizeEncoding.
+	NormalizeEncoding string
+	// KeyNamingCheck is the mode of the key naming check against KeyNamingRules.
+	KeyNamingCheck string
+	KeyNamingRules *keyNamingRules
+	// BaseLangs are BASE_LANG and ADDITIONAL_BASE_LANGS, the source languages.
+	BaseLangs []string
+	// PreUploadCommand and PostUploadCommand are shell commands run for the file; see runHook.
+	PreUploadCommand  string
+	PostUploadCommand string
+	HookTimeout       time.Duration
+
+	MaxRetries       int
+	InitialSleepTime time.Duration
+	MaxSleepTime     time.Duration
+	UploadTimeout    time.Duration
+	HTTPTimeout      time.Duration
+	PollInitialWait  time.Duration
+	PollMaxWait      time.Duration
+}
+
+// prepareConfig reads env vars, validates booleans, trims strings,
+// and assembles an UploadConfig for the provided file path.
+// PROJECT_MAPPINGS may redirect the file to a root-specific project and token,
+// and PUSH_ALL_LANGS derives the upload language from the file location
+// (or FILE_LANG_MAP_PATH when findallfiles provided one). All invalid variables
+// are reported together rather than stopping at the first one.
+func prepareConfig(filePath string) (UploadConfig, error) {
+	var errs []error
+
+	skipTagging, err := envconf.ParseBoolEnv("SKIP_TAGGING")
+	errs = append(errs, err)
+
+	skipPolling, err := envconf.ParseBoolEnv("SKIP_POLLING")
+	errs = append(errs, err)
+
+	skipDefaultFlags, err := envconf.ParseBoolEnv("SKIP_DEFAULT_FLAGS")
+	errs = append(errs, err)
+
+	mapPotToPo, err := envconf.ParseBoolEnv("MAP_POT_TO_PO")
+	errs = append(errs, err)
+
+	useFormatPreset, err := envconf.ParseBoolEnv("USE_FORMAT_PRESET")
+	errs = append(errs, err)
+
+	gh, err := ghcontext.Read(os.Getenv)
+	errs = append(errs, err)
+
+	pushAllLangs, err := envconf.ParseBoolEnv("PUSH_ALL_LANGS")
+	errs = append(errs, err)
+
+	forceUpload, err := envconf.ParseBoolEnv("FORCE_UPLOAD")
+	errs = append(errs, err)
+
+	skipLangs, err := envconf.ParseLangListEnv("SKIP_LANGS")
+	errs = append(errs, err)
+
+	placeholderCheck, err := parseCheckMode("PLACEHOLDER_CHECK")
+	errs = append(errs, err)
+
+	keyNamingCheck, keyNamingRules, err := parseKeyNamingEnv()
+	errs = append(errs, err)
+
+	normalizeEncoding, err := parseEncodingMode()
+	errs = append(errs, err)
+
+	initialSleepTime, err := envconf.ParseDurationEnv("SLEEP_TIME", defaultInitialSleepTime*time.Second)
+	errs = append(errs, err)
+
+	uploadTimeout, err := envconf.ParseDurationEnv("UPLOAD_TIMEOUT", defaultUploadTimeout*time.Second)
+	errs = append(errs, err)
+
+	httpTimeout, err := envconf.ParseDurationEnv("HTTP_TIMEOUT", defaultHTTPTimeout*time.Second)
+	errs = append(errs, err)
+
+	pollInitialWait, err := envconf.ParseDurationEnv("POLL_INITIAL_WAIT", defaultPollInitialWait*time.Second)
+	errs = append(errs, err)
+
+	pollMaxWait, err := envconf.ParseDurationEnv("POLL_MAX_WAIT", defaultPollMaxWait*time.Second)
+	errs = append(errs, err)
+
+	hookTimeout, err := envconf.ParseDurationEnv("HOOK_TIMEOUT", defaultHookTimeout*time.Second)
+	errs = append(errs, err)
+
+	rawProjectIDs, err := envconf.EnvOrFile("LOKALISE_PROJECT_ID")
+	errs = append(errs, err)
+	projectID, mirrorProjectIDs := envconf.SplitProjectIDs(rawProjectIDs)
+
+	token, err := envconf.EnvOrFile("LOKALISE_API_TOKEN")
+	errs = append(errs, err)
+
+	additionalParams, err := envconf.EnvOrFile("ADDITIONAL_PARAMS")
+	errs = append(errs, err)
+
+	cfg := UploadConfig{
+		FilePath:          filePath,
+		ProjectID:         projectID,
+		MirrorProjectIDs:  mirrorProjectIDs,
+		SkipLangs:         skipLangs,
+		Token:             strings.TrimSpace(token),
+		LangISO:           strings.TrimSpace(os.Getenv("BASE_LANG")),
+		GitHubRefName:     gh.RefName,
+		AdditionalParams:  strings.TrimSpace(additionalParams),
+		RootFlagOverrides: strings.TrimSpace(os.Getenv("ROOT_FLAG_OVERRIDES")),
+		FileFormat:        strings.ToLower(strings.TrimSpace(os.Getenv("FILE_FORMAT"))),
+		LanguageMappings:  strings.TrimSpace(os.Getenv("LANGUAGE_MAPPINGS")),
+		ReportDir:         strings.TrimSpace(os.Getenv("REPORT_DIR")),
+		CacheDir:          strings.TrimSpace(os.Getenv("LOKALISE_CACHE_DIR")),
+
+		SkipTagging:      skipTagging,
+		SkipPolling:      skipPolling,
+		SkipDefaultFlags: skipDefaultFlags,
+		MapPotToPo:       mapPotToPo,
+		UseFormatPreset:  useFormatPreset,
+		PushAllLangs:     pushAllLangs,
+		ForceUpload:      forceUpload,
+
+		PlaceholderCheck:  placeholderCheck,
+		NormalizeEncoding: normalizeEncoding,
+		KeyNamingCheck:    keyNamingCheck,
+		KeyNamingRules:    keyNamingRules,
+		BaseLangs:         baseLangs(),
+		PreUploadCommand:  strings.TrimSpace(os.Getenv("PRE_UPLOAD_COMMAND")),
+		PostUploadCommand: strings.TrimSpace(os.Getenv("POST_UPLOAD_COMMAND")),
+		HookTimeout:       hookTimeout,
+
+		MaxRetries:       parsers.ParseUintEnv("MAX_RETRIES", defaultMaxRetries),
+		InitialSleepTime: initialSleepTime,
+		MaxSleepTime:     time.Duration(maxSleepTime) * time.Second,
+		UploadTimeout:    uploadTimeout,
+		HTTPTimeout:      httpTimeout,
+		PollInitialWait:  pollInitialWait,
+		PollMaxWait:      pollMaxWait,
+	}
+
+	errs = append(errs,
+		applyProjectMapping(&cfg),
+		applyTransformsConfig(&cfg, os.Getenv("TRANSFORMS")),
+		applyFileLang(&cfg),
+		applyFileLangMap(&cfg, os.Getenv("FILE_LANG_MAP_PATH")),
+	)
+	if err := envconf.Join(errs); err != nil {
+		return UploadConfig{}, err
+	}
+
+	return cfg, nil
+}
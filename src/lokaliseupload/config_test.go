@@ -0,0 +1,745 @@
+package lokaliseupload
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var configEnvKeys = []string{
+	"LOKALISE_PROJECT_ID",
+	"LOKALISE_PROJECT_ID_FILE",
+	"LOKALISE_API_TOKEN",
+	"LOKALISE_API_TOKEN_FILE",
+	"BASE_LANG",
+	"GITHUB_REF_NAME",
+	"ADDITIONAL_PARAMS",
+	"ADDITIONAL_PARAMS_FILE",
+	"ROOT_FLAG_OVERRIDES",
+	"FILE_FORMAT",
+	"MAP_POT_TO_PO",
+	"USE_FORMAT_PRESET",
+	"LANGUAGE_MAPPINGS",
+	"PROJECT_MAPPINGS",
+	"PUSH_ALL_LANGS",
+	"TRANSLATIONS_PATH",
+	"FLAT_NAMING",
+	"NAME_REGEX",
+	"FILE_LANG_MAP_PATH",
+	"SKIP_LANGS",
+	"REPORT_DIR",
+	"LOKALISE_CACHE_DIR",
+	"FORCE_UPLOAD",
+	"PLACEHOLDER_CHECK",
+	"NORMALIZE_ENCODING",
+	"TRANSFORMS",
+	"PRE_UPLOAD_COMMAND",
+	"POST_UPLOAD_COMMAND",
+	"HOOK_TIMEOUT",
+	"KEY_NAMING_CHECK",
+	"KEY_NAMING_RULES",
+	"KEY_NAMING_RULES_FILE",
+	"ADDITIONAL_BASE_LANGS",
+	"SKIP_TAGGING",
+	"SKIP_POLLING",
+	"SKIP_DEFAULT_FLAGS",
+	"MAX_RETRIES",
+	"SLEEP_TIME",
+	"UPLOAD_TIMEOUT",
+	"HTTP_TIMEOUT",
+	"POLL_INITIAL_WAIT",
+	"POLL_MAX_WAIT",
+}
+
+func TestPrepareConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		filePath string
+		wantErr  string
+		assert   func(t *testing.T, cfg UploadConfig)
+	}{
+		{
+			name: "defaults are applied",
+			env: map[string]string{
+				"LOKALISE_PROJECT_ID": "",
+				"LOKALISE_API_TOKEN":  "",
+				"BASE_LANG":           "",
+				"GITHUB_REF_NAME":     "",
+				"ADDITIONAL_PARAMS":   "",
+				"SKIP_TAGGING":        "",
+				"SKIP_POLLING":        "",
+				"SKIP_DEFAULT_FLAGS":  "",
+				"MAX_RETRIES":         "",
+				"SLEEP_TIME":          "",
+				"UPLOAD_TIMEOUT":      "",
+				"HTTP_TIMEOUT":        "",
+				"POLL_INITIAL_WAIT":   "",
+				"POLL_MAX_WAIT":       "",
+			},
+			filePath: "test.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.FilePath != "test.json" {
+					t.Fatalf("expected FilePath=test.json, got %s", cfg.FilePath)
+				}
+
+				if cfg.ProjectID != "" {
+					t.Fatalf("expected empty ProjectID, got %q", cfg.ProjectID)
+				}
+				if cfg.Token != "" {
+					t.Fatalf("expected empty Token, got %q", cfg.Token)
+				}
+				if cfg.LangISO != "" {
+					t.Fatalf("expected empty LangISO, got %q", cfg.LangISO)
+				}
+				if cfg.GitHubRefName != "" {
+					t.Fatalf("expected empty GitHubRefName, got %q", cfg.GitHubRefName)
+				}
+				if cfg.AdditionalParams != "" {
+					t.Fatalf("expected empty AdditionalParams, got %q", cfg.AdditionalParams)
+				}
+
+				if cfg.SkipTagging {
+					t.Fatalf("expected SkipTagging=false, got true")
+				}
+				if cfg.SkipPolling {
+					t.Fatalf("expected SkipPolling=false, got true")
+				}
+				if cfg.SkipDefaultFlags {
+					t.Fatalf("expected SkipDefaultFlags=false, got true")
+				}
+				if cfg.PlaceholderCheck != checkOff {
+					t.Fatalf("expected PlaceholderCheck=off, got %q", cfg.PlaceholderCheck)
+				}
+				if cfg.NormalizeEncoding != encodingOff {
+					t.Fatalf("expected NormalizeEncoding=off, got %q", cfg.NormalizeEncoding)
+				}
+
+				if cfg.MaxRetries != defaultMaxRetries {
+					t.Fatalf("expected MaxRetries=%d, got %d", defaultMaxRetries, cfg.MaxRetries)
+				}
+				if cfg.InitialSleepTime != time.Duration(defaultInitialSleepTime)*time.Second {
+					t.Fatalf("expected InitialSleepTime=%v, got %v", time.Duration(defaultInitialSleepTime)*time.Second, cfg.InitialSleepTime)
+				}
+				if cfg.MaxSleepTime != time.Duration(maxSleepTime)*time.Second {
+					t.Fatalf("expected MaxSleepTime=%v, got %v", time.Duration(maxSleepTime)*time.Second, cfg.MaxSleepTime)
+				}
+				if cfg.UploadTimeout != time.Duration(defaultUploadTimeout)*time.Second {
+					t.Fatalf("expected UploadTimeout=%v, got %v", time.Duration(defaultUploadTimeout)*time.Second, cfg.UploadTimeout)
+				}
+				if cfg.HTTPTimeout != time.Duration(defaultHTTPTimeout)*time.Second {
+					t.Fatalf("expected HTTPTimeout=%v, got %v", time.Duration(defaultHTTPTimeout)*time.Second, cfg.HTTPTimeout)
+				}
+				if cfg.PollInitialWait != time.Duration(defaultPollInitialWait)*time.Second {
+					t.Fatalf("expected PollInitialWait=%v, got %v", time.Duration(defaultPollInitialWait)*time.Second, cfg.PollInitialWait)
+				}
+				if cfg.PollMaxWait != time.Duration(defaultPollMaxWait)*time.Second {
+					t.Fatalf("expected PollMaxWait=%v, got %v", time.Duration(defaultPollMaxWait)*time.Second, cfg.PollMaxWait)
+				}
+				if cfg.HookTimeout != time.Duration(defaultHookTimeout)*time.Second {
+					t.Fatalf("expected HookTimeout=%v, got %v", time.Duration(defaultHookTimeout)*time.Second, cfg.HookTimeout)
+				}
+			},
+		},
+		{
+			name: "env overrides are applied and trimmed",
+			env: map[string]string{
+				"LOKALISE_PROJECT_ID": "  proj123  ",
+				"LOKALISE_API_TOKEN":  "  token123  ",
+				"BASE_LANG":           "  en  ",
+				"GITHUB_REF_NAME":     "  refs/heads/main  ",
+				"ADDITIONAL_PARAMS":   "  {\"custom\": true}  ",
+				"ROOT_FLAG_OVERRIDES": "  {\"pkg\": {\"include_path\": false}}  ",
+				"FILE_FORMAT":         "  PO  ",
+				"MAP_POT_TO_PO":       "true",
+				"USE_FORMAT_PRESET":   "true",
+				"LANGUAGE_MAPPINGS":   "  - {original_language_iso: en, custom_language_iso: en-US}  ",
+				"SKIP_TAGGING":        "true",
+				"SKIP_POLLING":        "true",
+				"SKIP_DEFAULT_FLAGS":  "true",
+				"MAX_RETRIES":         "10",
+				"SLEEP_TIME":          "5",
+				"UPLOAD_TIMEOUT":      "42",
+				"HTTP_TIMEOUT":        "11",
+				"POLL_INITIAL_WAIT":   "7",
+				"POLL_MAX_WAIT":       "8",
+			},
+			filePath: "file.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.FilePath != "file.json" {
+					t.Fatalf("expected FilePath=file.json, got %s", cfg.FilePath)
+				}
+				if cfg.ProjectID != "proj123" {
+					t.Fatalf("expected ProjectID=proj123, got %s", cfg.ProjectID)
+				}
+				if cfg.Token != "token123" {
+					t.Fatalf("expected Token=token123, got %s", cfg.Token)
+				}
+				if cfg.LangISO != "en" {
+					t.Fatalf("expected LangISO=en, got %s", cfg.LangISO)
+				}
+				if cfg.GitHubRefName != "refs/heads/main" {
+					t.Fatalf("expected GitHubRefName=refs/heads/main, got %s", cfg.GitHubRefName)
+				}
+				if cfg.AdditionalParams != "{\"custom\": true}" {
+					t.Fatalf("expected trimmed AdditionalParams, got %q", cfg.AdditionalParams)
+				}
+				if cfg.RootFlagOverrides != "{\"pkg\": {\"include_path\": false}}" {
+					t.Fatalf("expected trimmed RootFlagOverrides, got %q", cfg.RootFlagOverrides)
+				}
+				if cfg.FileFormat != "po" {
+					t.Fatalf("expected normalized FileFormat=po, got %q", cfg.FileFormat)
+				}
+				if !cfg.MapPotToPo {
+					t.Fatalf("expected MapPotToPo=true, got false")
+				}
+				if !cfg.UseFormatPreset {
+					t.Fatalf("expected UseFormatPreset=true, got false")
+				}
+				if cfg.LanguageMappings != "- {original_language_iso: en, custom_language_iso: en-US}" {
+					t.Fatalf("expected trimmed LanguageMappings, got %q", cfg.LanguageMappings)
+				}
+				if !cfg.SkipTagging {
+					t.Fatalf("expected SkipTagging=true, got false")
+				}
+				if !cfg.SkipPolling {
+					t.Fatalf("expected SkipPolling=true, got false")
+				}
+				if !cfg.SkipDefaultFlags {
+					t.Fatalf("expected SkipDefaultFlags=true, got false")
+				}
+				if cfg.MaxRetries != 10 {
+					t.Fatalf("expected MaxRetries=10, got %d", cfg.MaxRetries)
+				}
+				if cfg.InitialSleepTime != 5*time.Second {
+					t.Fatalf("expected InitialSleepTime=5s, got %v", cfg.InitialSleepTime)
+				}
+				if cfg.MaxSleepTime != time.Duration(maxSleepTime)*time.Second {
+					t.Fatalf("expected MaxSleepTime=%v, got %v", time.Duration(maxSleepTime)*time.Second, cfg.MaxSleepTime)
+				}
+				if cfg.UploadTimeout != 42*time.Second {
+					t.Fatalf("expected UploadTimeout=42s, got %v", cfg.UploadTimeout)
+				}
+				if cfg.HTTPTimeout != 11*time.Second {
+					t.Fatalf("expected HTTPTimeout=11s, got %v", cfg.HTTPTimeout)
+				}
+				if cfg.PollInitialWait != 7*time.Second {
+					t.Fatalf("expected PollInitialWait=7s, got %v", cfg.PollInitialWait)
+				}
+				if cfg.PollMaxWait != 8*time.Second {
+					t.Fatalf("expected PollMaxWait=8s, got %v", cfg.PollMaxWait)
+				}
+			},
+		},
+		{
+			name: "explicit false bool envs are applied",
+			env: map[string]string{
+				"SKIP_TAGGING":       "false",
+				"SKIP_POLLING":       "false",
+				"SKIP_DEFAULT_FLAGS": "false",
+			},
+			filePath: "file.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.SkipTagging {
+					t.Fatalf("expected SkipTagging=false, got true")
+				}
+				if cfg.SkipPolling {
+					t.Fatalf("expected SkipPolling=false, got true")
+				}
+				if cfg.SkipDefaultFlags {
+					t.Fatalf("expected SkipDefaultFlags=false, got true")
+				}
+			},
+		},
+		{
+			name: "invalid SKIP_TAGGING returns error",
+			env: map[string]string{
+				"SKIP_TAGGING": "not-a-bool",
+			},
+			filePath: "file.json",
+			wantErr:  "invalid SKIP_TAGGING",
+		},
+		{
+			name: "invalid SKIP_POLLING returns error",
+			env: map[string]string{
+				"SKIP_POLLING": "not-a-bool",
+			},
+			filePath: "file.json",
+			wantErr:  "invalid SKIP_POLLING",
+		},
+		{
+			name: "invalid SKIP_DEFAULT_FLAGS returns error",
+			env: map[string]string{
+				"SKIP_DEFAULT_FLAGS": "not-a-bool",
+			},
+			filePath: "file.json",
+			wantErr:  "invalid SKIP_DEFAULT_FLAGS",
+		},
+		{
+			name: "invalid MAP_POT_TO_PO returns error",
+			env: map[string]string{
+				"MAP_POT_TO_PO": "not-a-bool",
+			},
+			filePath: "file.pot",
+			wantErr:  "invalid MAP_POT_TO_PO",
+		},
+		{
+			name: "project mapping overrides project and token for matching root",
+			env: map[string]string{
+				"LOKALISE_PROJECT_ID": "default.proj",
+				"LOKALISE_API_TOKEN":  "default-token",
+				"PROJECT_MAPPINGS":    "packages/app=111.abc:app-token, packages/site=222.def",
+			},
+			filePath: "packages/app/locales/en.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.ProjectID != "111.abc" {
+					t.Fatalf("expected ProjectID=111.abc, got %q", cfg.ProjectID)
+				}
+				if cfg.Token != "app-token" {
+					t.Fatalf("expected mapped token, got %q", cfg.Token)
+				}
+			},
+		},
+		{
+			name: "project mapping without token keeps default token",
+			env: map[string]string{
+				"LOKALISE_PROJECT_ID": "default.proj",
+				"LOKALISE_API_TOKEN":  "default-token",
+				"PROJECT_MAPPINGS":    "packages/app=111.abc:app-token\npackages/site=222.def",
+			},
+			filePath: "packages/site/en.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.ProjectID != "222.def" || cfg.Token != "default-token" {
+					t.Fatalf("unexpected project/token: %q / %q", cfg.ProjectID, cfg.Token)
+				}
+			},
+		},
+		{
+			name: "project ID list configures mirrors",
+			env: map[string]string{
+				"LOKALISE_PROJECT_ID": " 111.abc,\n222.def , 111.abc,333.ghi ",
+			},
+			filePath: "locales/en.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.ProjectID != "111.abc" {
+					t.Fatalf("expected primary ProjectID=111.abc, got %q", cfg.ProjectID)
+				}
+				want := []string{"222.def", "333.ghi"}
+				if !reflect.DeepEqual(cfg.MirrorProjectIDs, want) {
+					t.Fatalf("expected mirrors %v, got %v", want, cfg.MirrorProjectIDs)
+				}
+			},
+		},
+		{
+			name: "project mapping disables mirrors for mapped files",
+			env: map[string]string{
+				"LOKALISE_PROJECT_ID": "111.abc,222.def",
+				"PROJECT_MAPPINGS":    "packages/app=999.zzz",
+			},
+			filePath: "packages/app/en.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.ProjectID != "999.zzz" || cfg.MirrorProjectIDs != nil {
+					t.Fatalf("unexpected project config: %q %v", cfg.ProjectID, cfg.MirrorProjectIDs)
+				}
+			},
+		},
+		{
+			name: "push all langs derives language from nested layout",
+			env: map[string]string{
+				"BASE_LANG":         "en",
+				"PUSH_ALL_LANGS":    "true",
+				"TRANSLATIONS_PATH": "locales\npackages/app/locales",
+			},
+			filePath: "packages/app/locales/pt_BR/common.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if !cfg.PushAllLangs {
+					t.Fatalf("expected PushAllLangs=true")
+				}
+				if cfg.LangISO != "pt_BR" {
+					t.Fatalf("expected LangISO=pt_BR, got %q", cfg.LangISO)
+				}
+			},
+		},
+		{
+			name: "push all langs derives language from flat layout",
+			env: map[string]string{
+				"BASE_LANG":         "en",
+				"PUSH_ALL_LANGS":    "true",
+				"TRANSLATIONS_PATH": "locales",
+				"FLAT_NAMING":       "true",
+			},
+			filePath: "locales/de.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.LangISO != "de" {
+					t.Fatalf("expected LangISO=de, got %q", cfg.LangISO)
+				}
+			},
+		},
+		{
+			name: "push all langs requires translations path",
+			env: map[string]string{
+				"BASE_LANG":      "en",
+				"PUSH_ALL_LANGS": "true",
+			},
+			filePath: "locales/de.json",
+			wantErr:  "invalid TRANSLATIONS_PATH",
+		},
+		{
+			name: "skip langs are parsed",
+			env: map[string]string{
+				"SKIP_LANGS": `["de", "pt_BR"]`,
+			},
+			filePath: "locales/en.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if !reflect.DeepEqual(cfg.SkipLangs, []string{"de", "pt_BR"}) {
+					t.Fatalf("unexpected SkipLangs: %v", cfg.SkipLangs)
+				}
+			},
+		},
+		{
+			name: "invalid SKIP_LANGS returns error",
+			env: map[string]string{
+				"SKIP_LANGS": `["de",`,
+			},
+			filePath: "locales/en.json",
+			wantErr:  "invalid SKIP_LANGS",
+		},
+		{
+			name: "invalid PROJECT_MAPPINGS returns error",
+			env: map[string]string{
+				"PROJECT_MAPPINGS": "packages/app",
+			},
+			filePath: "packages/app/en.json",
+			wantErr:  "invalid project_mappings: line 1",
+		},
+		{
+			name: "invalid USE_FORMAT_PRESET returns error",
+			env: map[string]string{
+				"USE_FORMAT_PRESET": "not-a-bool",
+			},
+			filePath: "file.json",
+			wantErr:  "invalid USE_FORMAT_PRESET",
+		},
+		{
+			name: "timeouts accept duration syntax",
+			env: map[string]string{
+				"SLEEP_TIME":        "500ms",
+				"UPLOAD_TIMEOUT":    "15m",
+				"HTTP_TIMEOUT":      "90",
+				"POLL_INITIAL_WAIT": "2s",
+				"POLL_MAX_WAIT":     "1h",
+				"HOOK_TIMEOUT":      "45s",
+			},
+			filePath: "file.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.InitialSleepTime != 500*time.Millisecond {
+					t.Fatalf("expected InitialSleepTime=500ms, got %v", cfg.InitialSleepTime)
+				}
+				if cfg.UploadTimeout != 15*time.Minute {
+					t.Fatalf("expected UploadTimeout=15m, got %v", cfg.UploadTimeout)
+				}
+				if cfg.HTTPTimeout != 90*time.Second {
+					t.Fatalf("expected HTTPTimeout=90s, got %v", cfg.HTTPTimeout)
+				}
+				if cfg.PollInitialWait != 2*time.Second {
+					t.Fatalf("expected PollInitialWait=2s, got %v", cfg.PollInitialWait)
+				}
+				if cfg.PollMaxWait != time.Hour {
+					t.Fatalf("expected PollMaxWait=1h, got %v", cfg.PollMaxWait)
+				}
+				if cfg.HookTimeout != 45*time.Second {
+					t.Fatalf("expected HookTimeout=45s, got %v", cfg.HookTimeout)
+				}
+			},
+		},
+		{
+			name: "push state cache is read",
+			env: map[string]string{
+				"LOKALISE_CACHE_DIR": " /tmp/lokalise-cache ",
+				"FORCE_UPLOAD":       "true",
+			},
+			filePath: "file.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.CacheDir != "/tmp/lokalise-cache" {
+					t.Fatalf("expected CacheDir=/tmp/lokalise-cache, got %q", cfg.CacheDir)
+				}
+				if !cfg.ForceUpload {
+					t.Fatalf("expected ForceUpload=true, got false")
+				}
+			},
+		},
+		{
+			name: "invalid FORCE_UPLOAD returns error",
+			env: map[string]string{
+				"FORCE_UPLOAD": "always",
+			},
+			filePath: "file.json",
+			wantErr:  "invalid FORCE_UPLOAD",
+		},
+		{
+			name: "placeholder check mode is read",
+			env: map[string]string{
+				"PLACEHOLDER_CHECK": " Fail ",
+			},
+			filePath: "file.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.PlaceholderCheck != checkFail {
+					t.Fatalf("expected PlaceholderCheck=fail, got %q", cfg.PlaceholderCheck)
+				}
+			},
+		},
+		{
+			name: "invalid PLACEHOLDER_CHECK returns error",
+			env: map[string]string{
+				"PLACEHOLDER_CHECK": "strict",
+			},
+			filePath: "file.json",
+			wantErr:  "invalid PLACEHOLDER_CHECK",
+		},
+		{
+			name: "transforms of the most specific root are used",
+			env: map[string]string{
+				"TRANSFORMS": "packages: [sort_keys]\npackages/app: [strip_comments, flatten]\n",
+			},
+			filePath: "packages/app/locales/en.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if !reflect.DeepEqual(cfg.Transforms, []string{transformStripComments, transformFlatten}) {
+					t.Fatalf("unexpected Transforms %v", cfg.Transforms)
+				}
+			},
+		},
+		{
+			name: "invalid TRANSFORMS returns error",
+			env: map[string]string{
+				"TRANSFORMS": `{"locales": ["minify"]}`,
+			},
+			filePath: "locales/en.json",
+			wantErr:  `unknown transform "minify"`,
+		},
+		{
+			name: "hook commands are read",
+			env: map[string]string{
+				"PRE_UPLOAD_COMMAND":  " ./convert.sh \"$LOKALISE_FILE\"\n",
+				"POST_UPLOAD_COMMAND": "rm -f \"$LOKALISE_FILE.bak\"",
+			},
+			filePath: "file.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.PreUploadCommand != `./convert.sh "$LOKALISE_FILE"` {
+					t.Fatalf("unexpected PreUploadCommand %q", cfg.PreUploadCommand)
+				}
+				if cfg.PostUploadCommand != `rm -f "$LOKALISE_FILE.bak"` {
+					t.Fatalf("unexpected PostUploadCommand %q", cfg.PostUploadCommand)
+				}
+			},
+		},
+		{
+			name: "invalid HOOK_TIMEOUT returns error",
+			env: map[string]string{
+				"HOOK_TIMEOUT": "soon",
+			},
+			filePath: "file.json",
+			wantErr:  "invalid HOOK_TIMEOUT",
+		},
+		{
+			name: "invalid NORMALIZE_ENCODING returns error",
+			env: map[string]string{
+				"NORMALIZE_ENCODING": "latin1",
+			},
+			filePath: "file.json",
+			wantErr:  "invalid NORMALIZE_ENCODING",
+		},
+		{
+			name: "key naming rules are read",
+			env: map[string]string{
+				"KEY_NAMING_CHECK":      "warn",
+				"KEY_NAMING_RULES":      "max_length: 40",
+				"BASE_LANG":             "en",
+				"ADDITIONAL_BASE_LANGS": "en_GB",
+				"TRANSLATIONS_PATH":     "locales",
+			},
+			filePath: "file.json",
+			assert: func(t *testing.T, cfg UploadConfig) {
+				t.Helper()
+
+				if cfg.KeyNamingCheck != checkWarn {
+					t.Fatalf("expected KeyNamingCheck=warn, got %q", cfg.KeyNamingCheck)
+				}
+				if cfg.KeyNamingRules == nil || cfg.KeyNamingRules.MaxLength != 40 {
+					t.Fatalf("expected rules with max_length 40, got %#v", cfg.KeyNamingRules)
+				}
+				if !reflect.DeepEqual(cfg.BaseLangs, []string{"en", "en_GB"}) {
+					t.Fatalf("expected BaseLangs=[en en_GB], got %v", cfg.BaseLangs)
+				}
+			},
+		},
+		{
+			name: "key naming check requires rules",
+			env: map[string]string{
+				"KEY_NAMING_CHECK": "fail",
+			},
+			filePath: "file.json",
+			wantErr:  "KEY_NAMING_RULES or KEY_NAMING_RULES_FILE is required",
+		},
+		{
+			name: "invalid KEY_NAMING_RULES returns error",
+			env: map[string]string{
+				"KEY_NAMING_RULES": "pattern: '[a-z'",
+			},
+			filePath: "file.json",
+			wantErr:  "invalid KEY_NAMING_RULES",
+		},
+		{
+			name: "invalid UPLOAD_TIMEOUT returns error",
+			env: map[string]string{
+				"UPLOAD_TIMEOUT": "ten minutes",
+			},
+			filePath: "file.json",
+			wantErr:  "invalid UPLOAD_TIMEOUT",
+		},
+		{
+			name: "non-positive POLL_MAX_WAIT returns error",
+			env: map[string]string{
+				"POLL_MAX_WAIT": "0",
+			},
+			filePath: "file.json",
+			wantErr:  "invalid POLL_MAX_WAIT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range configEnvKeys {
+				t.Setenv(key, tt.env[key])
+			}
+
+			cfg, err := prepareConfig(tt.filePath)
+
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %q", tt.wantErr, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.assert != nil {
+				tt.assert(t, cfg)
+			}
+		})
+	}
+}
+
+func TestPrepareConfig_FileIndirection(t *testing.T) {
+	for _, key := range configEnvKeys {
+		t.Setenv(key, "")
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Setenv("LOKALISE_PROJECT_ID", "ignored.id")
+	t.Setenv("LOKALISE_PROJECT_ID_FILE", write("project_id", "111.abc\n222.def\n"))
+	t.Setenv("LOKALISE_API_TOKEN", "ignored-token")
+	t.Setenv("LOKALISE_API_TOKEN_FILE", write("token", "secret-token\n"))
+	t.Setenv("ADDITIONAL_PARAMS_FILE", write("params.yml", "convert_placeholders: true\n"))
+	t.Setenv("BASE_LANG", "en")
+
+	cfg, err := prepareConfig("file.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ProjectID != "111.abc" || !reflect.DeepEqual(cfg.MirrorProjectIDs, []string{"222.def"}) {
+		t.Fatalf("unexpected projects: %q, %v", cfg.ProjectID, cfg.MirrorProjectIDs)
+	}
+	if cfg.Token != "secret-token" {
+		t.Fatalf("expected token from file, got %q", cfg.Token)
+	}
+	if cfg.AdditionalParams != "convert_placeholders: true" {
+		t.Fatalf("expected additional params from file, got %q", cfg.AdditionalParams)
+	}
+
+	t.Setenv("FILE_LANG_MAP_PATH", write("langs.json", `{"res/messages_it.properties":"it"}`))
+	cfg, err = prepareConfig("res/messages_it.properties")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.LangISO != "it" {
+		t.Fatalf("expected LangISO=it from the file language map, got %q", cfg.LangISO)
+	}
+
+	t.Setenv("FILE_LANG_MAP_PATH", write("bad-langs.json", `{"a.json":`))
+	if _, err := prepareConfig("a.json"); err == nil || !strings.Contains(err.Error(), "invalid FILE_LANG_MAP_PATH") {
+		t.Fatalf("expected language map error, got %v", err)
+	}
+	t.Setenv("FILE_LANG_MAP_PATH", "")
+
+	t.Setenv("LOKALISE_API_TOKEN_FILE", filepath.Join(dir, "missing"))
+	if _, err := prepareConfig("file.json"); err == nil || !strings.Contains(err.Error(), "cannot read LOKALISE_API_TOKEN_FILE") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestPrepareConfig_ReportsAllErrors(t *testing.T) {
+	for _, key := range configEnvKeys {
+		t.Setenv(key, "")
+	}
+	t.Setenv("SKIP_TAGGING", "maybe")
+	t.Setenv("UPLOAD_TIMEOUT", "forever")
+	t.Setenv("PROJECT_MAPPINGS", "packages/app")
+
+	_, err := prepareConfig("file.json")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"3 configuration problems", "invalid SKIP_TAGGING", "invalid UPLOAD_TIMEOUT", "invalid project_mappings"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %q", want, err.Error())
+		}
+	}
+}
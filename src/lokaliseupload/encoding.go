@@ -0,0 +1,127 @@
+package lokaliseupload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"lokalise-push-action/internal/logging"
+	"lokalise-push-action/internal/textenc"
+)
+
+// Modes of NORMALIZE_ENCODING.
+const (
+	encodingOff       = "off"
+	encodingTranscode = "transcode"
+	encodingFail      = "fail"
+)
+
+// parseEncodingMode reads NORMALIZE_ENCODING; empty means off.
+func parseEncodingMode() (string, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("NORMALIZE_ENCODING"))); mode {
+	case "", encodingOff, "false":
+		return encodingOff, nil
+	case encodingTranscode, encodingFail:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid NORMALIZE_ENCODING %q: expected off, transcode, or fail", os.Getenv("NORMALIZE_ENCODING"))
+	}
+}
+
+// fileEncoding is what detectEncoding found out about a file that is not
+// plain UTF-8.
+type fileEncoding struct {
+	Name string // the detected encoding, such as "UTF-16LE"
+	Line int    // 1-based line of the first byte that gave it away; 0 for the whole file
+	// Reason explains how the encoding was detected.
+	Reason string
+	// decode converts the contents to UTF-8; nil when they can't be converted.
+	decode func([]byte) []byte
+}
+
+// detectEncoding inspects data. It returns nil for UTF-8 without a byte
+// order mark. UTF-16 is recognized by its byte order mark, and other files
+// that are not valid UTF-8 are taken to be Latin-1 (Windows-1252). NUL bytes
+// without a byte order mark point to UTF-16 too, but which byte order is
+// unknown, so such files can't be converted.
+func detectEncoding(data []byte) *fileEncoding {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return &fileEncoding{Name: "UTF-8 with BOM", Line: 1, Reason: "starts with a UTF-8 byte order mark", decode: func(b []byte) []byte { return b[3:] }}
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return &fileEncoding{Name: "UTF-16LE", Reason: "starts with a UTF-16 little-endian byte order mark", decode: func(b []byte) []byte { return decodeUTF16(b[2:], binary.LittleEndian) }}
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return &fileEncoding{Name: "UTF-16BE", Reason: "starts with a UTF-16 big-endian byte order mark", decode: func(b []byte) []byte { return decodeUTF16(b[2:], binary.BigEndian) }}
+	}
+
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return &fileEncoding{Name: "UTF-16 without BOM", Line: textenc.LineAt(data, i), Reason: fmt.Sprintf("contains a NUL byte at offset %d, typical of UTF-16 without a byte order mark", i)}
+	}
+	if i := textenc.InvalidUTF8Offset(data); i >= 0 {
+		return &fileEncoding{Name: "Latin-1", Line: textenc.LineAt(data, i), Reason: fmt.Sprintf("is not valid UTF-8 (byte 0x%02X at offset %d)", data[i], i), decode: decodeWindows1252}
+	}
+	return nil
+}
+
+// encodingEnabled reports whether NORMALIZE_ENCODING is on.
+func encodingEnabled(cfg UploadConfig) bool {
+	return cfg.NormalizeEncoding == encodingTranscode || cfg.NormalizeEncoding == encodingFail
+}
+
+// normalizeEncoding checks the encoding of data, the contents of the file.
+// In transcode mode a file that is not plain UTF-8 is converted, and the
+// UTF-8 contents are returned; nil means data is fine as is. In fail mode, or
+// when the file can't be converted, the upload is stopped with a diagnosis.
+func normalizeEncoding(cfg UploadConfig, data []byte) ([]byte, error) {
+	enc := detectEncoding(data)
+	if enc == nil {
+		return nil, nil
+	}
+
+	loc := logging.Location{File: cfg.FilePath, Line: enc.Line, Title: "File encoding"}
+	if cfg.NormalizeEncoding == encodingFail || enc.decode == nil {
+		logs.ErrorAt(loc, "File %s (detected %s); save it as UTF-8 without BOM", enc.Reason, enc.Name)
+		return nil, fmt.Errorf("file %q is not plain UTF-8: it %s (detected %s)", cfg.FilePath, enc.Reason, enc.Name)
+	}
+
+	logs.WarnAt(loc, "File %s (detected %s); a UTF-8 copy is uploaded instead, save it as UTF-8 without BOM to skip the conversion", enc.Reason, enc.Name)
+	return enc.decode(data), nil
+}
+
+// decodeUTF16 converts UTF-16 in the given byte order to UTF-8. A trailing
+// odd byte is dropped and unpaired surrogates become U+FFFD.
+func decodeUTF16(data []byte, order binary.ByteOrder) []byte {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+// windows1252 maps the bytes 0x80-0x9F of Windows-1252 to runes; the other
+// bytes are the same as in Latin-1. Unassigned bytes keep their Latin-1 value.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// decodeWindows1252 converts Windows-1252, a superset of the printable
+// Latin-1 characters, to UTF-8.
+func decodeWindows1252(data []byte) []byte {
+	out := make([]byte, 0, len(data)+len(data)/8)
+	for _, b := range data {
+		switch {
+		case b < 0x80:
+			out = append(out, b)
+		case b < 0xA0:
+			out = utf8.AppendRune(out, windows1252[b-0x80])
+		default:
+			out = utf8.AppendRune(out, rune(b))
+		}
+	}
+	return out
+}
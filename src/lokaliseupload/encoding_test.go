@@ -0,0 +1,154 @@
+package lokaliseupload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bodrovis/lokex/v2/client/upload"
+)
+
+func TestParseEncodingMode(t *testing.T) {
+	for value, want := range map[string]string{"": encodingOff, "false": encodingOff, " Transcode ": encodingTranscode, "fail": encodingFail} {
+		t.Setenv("NORMALIZE_ENCODING", value)
+		if got, err := parseEncodingMode(); err != nil || got != want {
+			t.Fatalf("%q: expected %q, got %q (%v)", value, want, got, err)
+		}
+	}
+
+	t.Setenv("NORMALIZE_ENCODING", "utf8")
+	if _, err := parseEncodingMode(); err == nil || !strings.Contains(err.Error(), "invalid NORMALIZE_ENCODING") {
+		t.Fatalf("expected invalid NORMALIZE_ENCODING error, got %v", err)
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		wantName string
+		wantLine int
+		want     string // decoded contents; empty when not convertible
+	}{
+		{name: "plain UTF-8", data: []byte("{\"a\": \"Größe\"}")},
+		{name: "UTF-8 with BOM", data: []byte("\xEF\xBB\xBF{\"a\": \"b\"}"), wantName: "UTF-8 with BOM", wantLine: 1, want: `{"a": "b"}`},
+		{name: "UTF-16LE", data: []byte("\xFF\xFE{\x00\"\x00\xE9\x00\"\x00}\x00"), wantName: "UTF-16LE", want: `{"é"}`},
+		{name: "UTF-16BE", data: []byte("\xFE\xFF\x00{\x00\"\x20\xAC\x00\"\x00}"), wantName: "UTF-16BE", want: `{"€"}`},
+		{name: "Latin-1", data: []byte("a=1\nb=Gr\xF6\xDFe \x80\n"), wantName: "Latin-1", wantLine: 2, want: "a=1\nb=Größe €\n"},
+		{name: "UTF-16 without BOM", data: []byte("a\nb\x00c"), wantName: "UTF-16 without BOM", wantLine: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := detectEncoding(tt.data)
+			if tt.wantName == "" {
+				if enc != nil {
+					t.Fatalf("expected plain UTF-8, got %#v", enc)
+				}
+				return
+			}
+			if enc == nil || enc.Name != tt.wantName || enc.Line != tt.wantLine {
+				t.Fatalf("expected %s on line %d, got %#v", tt.wantName, tt.wantLine, enc)
+			}
+			if tt.want == "" {
+				if enc.decode != nil {
+					t.Fatalf("expected %s not to be convertible", tt.wantName)
+				}
+				return
+			}
+			if got := string(enc.decode(tt.data)); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// readingUploader records the contents of the file it is asked to upload.
+type readingUploader struct {
+	params   upload.UploadParams
+	contents string
+}
+
+func (u *readingUploader) Upload(_ context.Context, params upload.UploadParams, srcPath string, _ bool) (string, error) {
+	u.params = params
+	path := srcPath
+	if path == "" {
+		path = params["filename"].(string)
+	}
+	data, err := os.ReadFile(path)
+	u.contents = string(data)
+	return "pid", err
+}
+
+func TestUploadFile_NormalizeEncoding(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "en.properties")
+	latin1 := "title=Gr\xF6\xDFe\n"
+	if err := os.WriteFile(path, []byte(latin1), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	base := UploadConfig{FilePath: path, ProjectID: "111.abc", Token: "tok", LangISO: "en", SkipTagging: true}
+
+	t.Run("transcode uploads a UTF-8 copy under the original name", func(t *testing.T) {
+		buf := captureLogs(t)
+		uploader := &readingUploader{}
+		cfg := base
+		cfg.NormalizeEncoding = encodingTranscode
+
+		if err := uploadFile(context.Background(), cfg, &fakeUploadFactory{uploader: uploader}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if uploader.contents != "title=Größe\n" {
+			t.Fatalf("expected UTF-8 contents, got %q", uploader.contents)
+		}
+		if uploader.params["filename"] != path {
+			t.Fatalf("expected filename %q, got %v", path, uploader.params["filename"])
+		}
+		if !strings.Contains(buf.String(), path+":1: File is not valid UTF-8 (byte 0xF6 at offset 8) (detected Latin-1); a UTF-8 copy is uploaded instead") {
+			t.Fatalf("unexpected output %q", buf.String())
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != latin1 {
+			t.Fatalf("expected the original file to be left as is, got %q (%v)", data, err)
+		}
+		if matches, _ := filepath.Glob(filepath.Join(os.TempDir(), "lokalise-upload-*.properties")); len(matches) != 0 {
+			t.Fatalf("expected the copy to be removed, found %v", matches)
+		}
+	})
+
+	t.Run("fail stops the upload with a diagnosis", func(t *testing.T) {
+		buf := captureLogs(t)
+		reportDir := t.TempDir()
+		ff := &recordingUploadFactory{}
+		cfg := base
+		cfg.NormalizeEncoding = encodingFail
+		cfg.ReportDir = reportDir
+
+		err := uploadFile(context.Background(), cfg, ff)
+		if err == nil || !strings.Contains(err.Error(), "it is not valid UTF-8 (byte 0xF6 at offset 8) (detected Latin-1)") {
+			t.Fatalf("expected encoding error, got %v", err)
+		}
+		if len(ff.projects) != 0 {
+			t.Fatalf("expected no uploads, got %v", ff.projects)
+		}
+		if !strings.Contains(buf.String(), "Error: "+path+":1: File is not valid UTF-8") {
+			t.Fatalf("unexpected output %q", buf.String())
+		}
+		if results := readUploadResults(t, reportDir); len(results) != 1 || results[0].Status != resultStatusFailed {
+			t.Fatalf("expected a failed result, got %#v", results)
+		}
+	})
+
+	t.Run("off uploads the file as is", func(t *testing.T) {
+		uploader := &readingUploader{}
+		if err := uploadFile(context.Background(), base, &fakeUploadFactory{uploader: uploader}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if uploader.contents != latin1 {
+			t.Fatalf("expected the original contents, got %q", uploader.contents)
+		}
+	})
+}
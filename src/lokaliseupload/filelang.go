@@ -0,0 +1,167 @@
+package lokaliseupload
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/namepattern"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+// detectFileLang infers the language of filePath from the translations layout:
+//
+//	flat:   <root>/<lang>.<ext>
+//	nested: <root>/<lang>/...
+//
+// The most specific root containing the file is used. Returns false when the
+// file does not follow the layout of any root.
+func detectFileLang(filePath string, roots []string, flatNaming bool) (string, bool) {
+	_, rel, found := fileRoot(filePath, roots)
+	if !found {
+		return "", false
+	}
+
+	if flatNaming {
+		if strings.Contains(rel, "/") {
+			return "", false
+		}
+		lang := strings.TrimSuffix(rel, filepath.Ext(rel))
+		return lang, lang != ""
+	}
+
+	lang, rest, ok := strings.Cut(rel, "/")
+	if !ok || lang == "" || rest == "" {
+		return "", false
+	}
+	return lang, true
+}
+
+// fileRoot returns the most specific root containing filePath and the file
+// path relative to it.
+func fileRoot(filePath string, roots []string) (string, string, bool) {
+	path := pathnorm.RepoFilePath(filePath)
+
+	root := ""
+	found := false
+	for _, r := range roots {
+		if !pathnorm.WithinRoot(path, r) {
+			continue
+		}
+		if !found || len(r) > len(root) {
+			root = r
+			found = true
+		}
+	}
+	if !found {
+		return "", "", false
+	}
+
+	rel := path
+	if root != "." {
+		rel = strings.TrimPrefix(path, root+"/")
+	}
+	return root, rel, true
+}
+
+// applyFileLang sets LangISO from the file location when PUSH_ALL_LANGS is enabled,
+// or when the file is in one of the ADDITIONAL_BASE_LANGS. Files whose language
+// cannot be inferred keep BASE_LANG. With NAME_REGEX the language is always taken
+// from the expression; see applyRegexLang.
+func applyFileLang(cfg *UploadConfig) error {
+	nameRegex, err := namepattern.ParseRegexEnv()
+	if err != nil {
+		return err
+	}
+	extraLangs := envconf.SplitListEnv("ADDITIONAL_BASE_LANGS")
+	if nameRegex == nil && !cfg.PushAllLangs && len(extraLangs) == 0 {
+		return nil
+	}
+
+	roots, err := envconf.ParseRepoRelativePathsEnv("TRANSLATIONS_PATH", pathnorm.EnsureRepoRelativePath)
+	if err != nil {
+		return fmt.Errorf("invalid TRANSLATIONS_PATH (required when PUSH_ALL_LANGS or NAME_REGEX is set): %w", err)
+	}
+
+	if nameRegex != nil {
+		applyRegexLang(cfg, roots, nameRegex, extraLangs)
+		return nil
+	}
+
+	flatNaming, flatNamingByRoot, err := envconf.ParseFlatNaming(pathnorm.EnsureRepoRelativePath)
+	if err != nil {
+		return err
+	}
+	if root, _, ok := fileRoot(cfg.FilePath, roots); ok {
+		if flat, ok := flatNamingByRoot[root]; ok {
+			flatNaming = flat
+		}
+	}
+
+	if lang, ok := detectFileLang(cfg.FilePath, roots, flatNaming); ok && (cfg.PushAllLangs || slices.Contains(extraLangs, lang)) {
+		cfg.LangISO = lang
+	}
+
+	return nil
+}
+
+// applyFileLangMap sets LangISO from the file at FILE_LANG_MAP_PATH, the JSON
+// object of file paths to languages written by findallfiles for full uploads.
+// Files missing from the map keep the language detected so far.
+func applyFileLangMap(cfg *UploadConfig, path string) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read FILE_LANG_MAP_PATH: %w", err)
+	}
+	// The map lists every file of a full upload, so it isn't subject to the
+	// size limit of mappings given in the environment.
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("invalid FILE_LANG_MAP_PATH %s: expected a JSON object of file paths to languages: %w", path, err)
+	}
+	langs, err := mappingValues[string](obj)
+	if err != nil {
+		return fmt.Errorf("invalid FILE_LANG_MAP_PATH %s: expected a JSON object of file paths to languages: %w", path, err)
+	}
+
+	key := strings.TrimPrefix(filepath.ToSlash(filepath.Clean(cfg.FilePath)), "./")
+	if lang := strings.TrimSpace(langs[key]); lang != "" {
+		cfg.LangISO = lang
+	}
+	return nil
+}
+
+// applyRegexLang takes the language from the "lang" group of NAME_REGEX matched
+// against the file path relative to its root. Changed-file detection can't apply
+// the expression, so files that don't match it, or that are in a language other
+// than BASE_LANG or extraLangs without PUSH_ALL_LANGS, are marked as skipped.
+func applyRegexLang(cfg *UploadConfig, roots []string, re *regexp.Regexp, extraLangs []string) {
+	_, rel, ok := fileRoot(cfg.FilePath, roots)
+	if !ok {
+		cfg.SkipReason = "file is outside translations_path"
+		return
+	}
+
+	m := re.FindStringSubmatch(rel)
+	if m == nil || m[re.SubexpIndex("lang")] == "" {
+		cfg.SkipReason = "file name does not match name_regex"
+		return
+	}
+
+	lang := m[re.SubexpIndex("lang")]
+	if !cfg.PushAllLangs && lang != cfg.LangISO && !slices.Contains(extraLangs, lang) {
+		cfg.SkipReason = fmt.Sprintf("language %q is not the base language", lang)
+		return
+	}
+	cfg.LangISO = lang
+}
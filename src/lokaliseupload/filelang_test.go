@@ -0,0 +1,239 @@
+package lokaliseupload
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectFileLang(t *testing.T) {
+	tests := []struct {
+		name       string
+		filePath   string
+		roots      []string
+		flatNaming bool
+		wantLang   string
+		wantOK     bool
+	}{
+		{name: "nested layout", filePath: "locales/fr/common.json", roots: []string{"locales"}, wantLang: "fr", wantOK: true},
+		{name: "nested layout deep file", filePath: "locales/fr/a/b.json", roots: []string{"locales"}, wantLang: "fr", wantOK: true},
+		{name: "nested file directly under root", filePath: "locales/fr.json", roots: []string{"locales"}, wantOK: false},
+		{name: "flat layout", filePath: "./locales/pt_BR.json", roots: []string{"locales"}, flatNaming: true, wantLang: "pt_BR", wantOK: true},
+		{name: "flat layout rejects nested file", filePath: "locales/fr/x.json", roots: []string{"locales"}, flatNaming: true, wantOK: false},
+		{name: "most specific root wins", filePath: "app/locales/de/x.json", roots: []string{"app", "app/locales"}, wantLang: "de", wantOK: true},
+		{name: "repo root", filePath: "es/x.json", roots: []string{"."}, wantLang: "es", wantOK: true},
+		{name: "outside roots", filePath: "other/fr/x.json", roots: []string{"locales"}, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lang, ok := detectFileLang(tt.filePath, tt.roots, tt.flatNaming)
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v (lang=%q)", tt.wantOK, ok, lang)
+			}
+			if ok && lang != tt.wantLang {
+				t.Fatalf("expected lang %q, got %q", tt.wantLang, lang)
+			}
+		})
+	}
+}
+
+func TestApplyFileLang(t *testing.T) {
+	t.Run("disabled keeps base language", func(t *testing.T) {
+		cfg := UploadConfig{FilePath: "locales/fr/x.json", LangISO: "en"}
+		if err := applyFileLang(&cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.LangISO != "en" {
+			t.Fatalf("expected en, got %q", cfg.LangISO)
+		}
+	})
+
+	t.Run("undetectable language keeps base language", func(t *testing.T) {
+		t.Setenv("TRANSLATIONS_PATH", "locales")
+		t.Setenv("FLAT_NAMING", "false")
+
+		cfg := UploadConfig{FilePath: "elsewhere/x.json", LangISO: "en", PushAllLangs: true}
+		if err := applyFileLang(&cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.LangISO != "en" {
+			t.Fatalf("expected en, got %q", cfg.LangISO)
+		}
+	})
+
+	t.Run("per-root FLAT_NAMING selects the layout of the file's root", func(t *testing.T) {
+		t.Setenv("TRANSLATIONS_PATH", "web/locales\napp/i18n")
+		t.Setenv("FLAT_NAMING", "true\nfalse")
+
+		for path, want := range map[string]string{
+			"web/locales/fr.json":     "fr",
+			"app/i18n/de/common.json": "de",
+		} {
+			cfg := UploadConfig{FilePath: path, LangISO: "en", PushAllLangs: true}
+			if err := applyFileLang(&cfg); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.LangISO != want {
+				t.Fatalf("%s: expected %q, got %q", path, want, cfg.LangISO)
+			}
+		}
+	})
+
+	t.Run("additional base languages", func(t *testing.T) {
+		t.Setenv("TRANSLATIONS_PATH", "locales")
+		t.Setenv("FLAT_NAMING", "true")
+		t.Setenv("ADDITIONAL_BASE_LANGS", "en_US\nen_GB")
+
+		for path, want := range map[string]string{
+			"locales/en_US.json": "en_US",
+			"locales/en.json":    "en",
+			"locales/fr.json":    "en",
+		} {
+			cfg := UploadConfig{FilePath: path, LangISO: "en"}
+			if err := applyFileLang(&cfg); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.LangISO != want {
+				t.Fatalf("%s: expected %q, got %q", path, want, cfg.LangISO)
+			}
+		}
+	})
+
+	t.Run("per-root FLAT_NAMING count mismatch returns error", func(t *testing.T) {
+		t.Setenv("TRANSLATIONS_PATH", "web/locales\napp/i18n")
+		t.Setenv("FLAT_NAMING", "true,false,true")
+
+		cfg := UploadConfig{FilePath: "web/locales/fr.json", PushAllLangs: true}
+		err := applyFileLang(&cfg)
+		if err == nil || !strings.Contains(err.Error(), "got 3 values for 2 TRANSLATIONS_PATH entries") {
+			t.Fatalf("expected count mismatch error, got %v", err)
+		}
+	})
+
+	t.Run("invalid FLAT_NAMING returns error", func(t *testing.T) {
+		t.Setenv("TRANSLATIONS_PATH", "locales")
+		t.Setenv("FLAT_NAMING", "wat")
+
+		cfg := UploadConfig{FilePath: "locales/fr/x.json", PushAllLangs: true}
+		if err := applyFileLang(&cfg); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestApplyFileLang_NameRegex(t *testing.T) {
+	tests := []struct {
+		name         string
+		filePath     string
+		pushAllLangs bool
+		extraLangs   string
+		wantLang     string
+		wantSkip     string
+	}{
+		{name: "base language", filePath: "src/main/resources/messages_en.properties", wantLang: "en"},
+		{name: "nested file", filePath: "src/main/resources/admin/messages_en.properties", wantLang: "en"},
+		{name: "other language needs push_all_langs", filePath: "src/main/resources/messages_fr.properties", wantLang: "en", wantSkip: `language "fr" is not the base language`},
+		{name: "additional base language", filePath: "src/main/resources/messages_de.properties", extraLangs: "de", wantLang: "de"},
+		{name: "other language with push_all_langs", filePath: "src/main/resources/messages_fr.properties", pushAllLangs: true, wantLang: "fr"},
+		{name: "unmatched file name", filePath: "src/main/resources/application.properties", pushAllLangs: true, wantLang: "en", wantSkip: "does not match name_regex"},
+		{name: "outside roots", filePath: "docs/messages_en.properties", wantLang: "en", wantSkip: "outside translations_path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "src/main/resources")
+			t.Setenv("FLAT_NAMING", "")
+			t.Setenv("NAME_REGEX", `(?:[^/]+/)*messages_(?P<lang>[a-z]{2})\.properties`)
+			t.Setenv("ADDITIONAL_BASE_LANGS", tt.extraLangs)
+
+			cfg := UploadConfig{FilePath: tt.filePath, LangISO: "en", PushAllLangs: tt.pushAllLangs}
+			if err := applyFileLang(&cfg); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.LangISO != tt.wantLang {
+				t.Fatalf("expected language %q, got %q", tt.wantLang, cfg.LangISO)
+			}
+			if (tt.wantSkip == "") != (cfg.SkipReason == "") || !strings.Contains(cfg.SkipReason, tt.wantSkip) {
+				t.Fatalf("expected skip reason containing %q, got %q", tt.wantSkip, cfg.SkipReason)
+			}
+		})
+	}
+
+	t.Run("missing lang group returns error", func(t *testing.T) {
+		t.Setenv("TRANSLATIONS_PATH", "src/main/resources")
+		t.Setenv("NAME_REGEX", `messages_[a-z]{2}\.properties`)
+
+		cfg := UploadConfig{FilePath: "src/main/resources/messages_en.properties", LangISO: "en"}
+		err := applyFileLang(&cfg)
+		if err == nil || !strings.Contains(err.Error(), "missing (?P<lang>...) capture group") {
+			t.Fatalf("expected capture group error, got %v", err)
+		}
+	})
+}
+
+func TestApplyFileLangMap(t *testing.T) {
+	langs := `{"locales/fr/app.json": "fr", "res/messages_it.properties": "it"}`
+
+	tests := []struct {
+		name     string
+		filePath string
+		raw      string
+		want     string
+		wantErr  string
+	}{
+		{name: "mapped file", filePath: "locales/fr/app.json", raw: langs, want: "fr"},
+		{name: "path is normalized", filePath: "./res//messages_it.properties", raw: langs, want: "it"},
+		{name: "unmapped file keeps language", filePath: "locales/en/app.json", raw: langs, want: "en"},
+		{name: "invalid JSON", filePath: "locales/fr/app.json", raw: `["fr"]`, wantErr: "invalid FILE_LANG_MAP_PATH"},
+		{name: "non-string language", filePath: "locales/fr/app.json", raw: `{"locales/fr/app.json": 7}`, wantErr: "expected string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "langs.json")
+			if err := os.WriteFile(path, []byte(tt.raw), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := UploadConfig{FilePath: tt.filePath, LangISO: "en"}
+			err := applyFileLangMap(&cfg, path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.LangISO != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, cfg.LangISO)
+			}
+		})
+	}
+
+	t.Run("no path keeps language", func(t *testing.T) {
+		cfg := UploadConfig{FilePath: "locales/fr/app.json", LangISO: "en"}
+		if err := applyFileLangMap(&cfg, " "); err != nil || cfg.LangISO != "en" {
+			t.Fatalf("expected en without error, got %q (%v)", cfg.LangISO, err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		cfg := UploadConfig{FilePath: "locales/fr/app.json"}
+		err := applyFileLangMap(&cfg, filepath.Join(t.TempDir(), "missing.json"))
+		if err == nil || !strings.Contains(err.Error(), "cannot read FILE_LANG_MAP_PATH") {
+			t.Fatalf("expected read error, got %v", err)
+		}
+	})
+}
+
+func TestFileRoot_DecomposedPath(t *testing.T) {
+	roots := []string{"apps/caf\u00e9/locales"}
+	root, rel, ok := fileRoot("./apps/cafe\u0301/locales/en.json", roots)
+	if !ok || root != roots[0] || rel != "en.json" {
+		t.Fatalf("expected decomposed path to match %q, got %q %q %v", roots[0], root, rel, ok)
+	}
+}
@@ -0,0 +1,124 @@
+package lokaliseupload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"lokalise-push-action/internal/logging"
+)
+
+// hookWaitDelay is how long a killed hook may keep its output open.
+const hookWaitDelay = 5 * time.Second
+
+// Results passed to the post-upload hook in LOKALISE_UPLOAD_RESULT.
+const (
+	hookResultSuccess = "success"
+	hookResultFailure = "failure"
+)
+
+// hookShell runs hook commands, like run steps with shell: bash.
+var hookShell = []string{"bash", "--noprofile", "--norc", "-eo", "pipefail", "-c"}
+
+// hookCredentialEnv lists the variables of the upload step that carry
+// credentials; hook commands don't get them. PROJECT_MAPPINGS may hold a
+// token for every mapped root.
+var hookCredentialEnv = []string{"LOKALISE_API_TOKEN", "LOKALISE_API_TOKEN_FILE", "PROJECT_MAPPINGS"}
+
+// runPreUploadHook runs PRE_UPLOAD_COMMAND for the file before anything is
+// read from it, so the command may rewrite the file (a custom converter, for
+// example). A failing command stops the upload of the file.
+func runPreUploadHook(ctx context.Context, cfg UploadConfig) error {
+	if cfg.PreUploadCommand == "" {
+		return nil
+	}
+	if err := runHook(ctx, cfg, "pre-upload", cfg.PreUploadCommand, nil); err != nil {
+		logs.ErrorAt(hookLocation(cfg), "%v", err)
+		return err
+	}
+	return nil
+}
+
+// runPostUploadHook runs POST_UPLOAD_COMMAND once the file was uploaded to
+// every project, or failed to be, with the outcome in LOKALISE_UPLOAD_RESULT
+// and LOKALISE_UPLOAD_ERROR. A failing command fails the step, but doesn't
+// change the recorded upload results.
+func runPostUploadHook(ctx context.Context, cfg UploadConfig, uploadErr error) error {
+	if cfg.PostUploadCommand == "" {
+		return nil
+	}
+
+	env := map[string]string{"LOKALISE_UPLOAD_RESULT": hookResultSuccess, "LOKALISE_UPLOAD_ERROR": ""}
+	if uploadErr != nil {
+		env["LOKALISE_UPLOAD_RESULT"] = hookResultFailure
+		env["LOKALISE_UPLOAD_ERROR"] = uploadErr.Error()
+	}
+
+	// The upload may have used up the context; the hook still gets its own time.
+	if err := runHook(context.WithoutCancel(ctx), cfg, "post-upload", cfg.PostUploadCommand, env); err != nil {
+		logs.ErrorAt(hookLocation(cfg), "%v", err)
+		return err
+	}
+	return nil
+}
+
+// runHook runs command with bash in the working directory, streaming its
+// output to the log. The command is killed once HOOK_TIMEOUT passes or ctx is
+// done. The file and its upload settings are passed as LOKALISE_* variables
+// on top of the environment of the action, without its credentials.
+func runHook(ctx context.Context, cfg UploadConfig, name, command string, extra map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.HookTimeout)
+	defer cancel()
+
+	logs.Infof("Running %s command for %q", name, cfg.FilePath)
+
+	cmd := exec.CommandContext(ctx, hookShell[0], append(hookShell[1:], command)...)
+	cmd.Env = hookEnv(os.Environ(), cfg, extra)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.WaitDelay = hookWaitDelay
+
+	err := cmd.Run()
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%s command for %q timed out after %s", name, cfg.FilePath, cfg.HookTimeout)
+	default:
+		return fmt.Errorf("%s command for %q failed: %w", name, cfg.FilePath, err)
+	}
+}
+
+// hookLocation annotates hook failures on the file they ran for.
+func hookLocation(cfg UploadConfig) logging.Location {
+	return logging.Location{File: cfg.FilePath, Title: "Upload hook"}
+}
+
+// hookEnv returns environ with the LOKALISE_* variables of the hook set, and
+// the variables in hookCredentialEnv removed.
+func hookEnv(environ []string, cfg UploadConfig, extra map[string]string) []string {
+	vars := map[string]string{
+		"LOKALISE_FILE":       cfg.FilePath,
+		"LOKALISE_LANG":       cfg.LangISO,
+		"LOKALISE_PROJECT_ID": strings.Join(append([]string{cfg.ProjectID}, cfg.MirrorProjectIDs...), ","),
+	}
+	for k, v := range extra {
+		vars[k] = v
+	}
+
+	out := slices.DeleteFunc(slices.Clone(environ), func(kv string) bool {
+		name, _, _ := strings.Cut(kv, "=")
+		_, replaced := vars[name]
+		return replaced || slices.Contains(hookCredentialEnv, name)
+	})
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		out = append(out, k+"="+vars[k])
+	}
+	return out
+}
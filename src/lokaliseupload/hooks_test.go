@@ -0,0 +1,182 @@
+package lokaliseupload
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHookEnv(t *testing.T) {
+	environ := []string{
+		"PATH=/bin",
+		"LOKALISE_API_TOKEN=secret",
+		"LOKALISE_API_TOKEN_FILE=/tmp/token",
+		"PROJECT_MAPPINGS=packages/app=111.abc:app-token",
+		"LOKALISE_PROJECT_ID=raw",
+		"LOKALISE_FILE=stale",
+	}
+	cfg := UploadConfig{FilePath: "locales/en.json", LangISO: "en", ProjectID: "111.abc", MirrorProjectIDs: []string{"222.def"}}
+
+	got := hookEnv(environ, cfg, map[string]string{"LOKALISE_UPLOAD_RESULT": hookResultSuccess})
+
+	want := []string{
+		"PATH=/bin",
+		"LOKALISE_FILE=locales/en.json",
+		"LOKALISE_LANG=en",
+		"LOKALISE_PROJECT_ID=111.abc,222.def",
+		"LOKALISE_UPLOAD_RESULT=success",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRunHook(t *testing.T) {
+	cfg := UploadConfig{FilePath: "en.json", HookTimeout: time.Minute}
+
+	t.Run("success", func(t *testing.T) {
+		if err := runHook(context.Background(), cfg, "pre-upload", `test "$LOKALISE_FILE" = en.json`, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("credentials are not passed on", func(t *testing.T) {
+		t.Setenv("PROJECT_MAPPINGS", "packages/app=111.abc:app-token")
+		t.Setenv("LOKALISE_API_TOKEN", "secret")
+		if err := runHook(context.Background(), cfg, "pre-upload", `test -z "${PROJECT_MAPPINGS+set}${LOKALISE_API_TOKEN+set}"`, nil); err != nil {
+			t.Fatalf("expected the credentials to be unset: %v", err)
+		}
+	})
+
+	t.Run("failing pipeline", func(t *testing.T) {
+		err := runHook(context.Background(), cfg, "pre-upload", "false | cat", nil)
+		if err == nil || !strings.Contains(err.Error(), `pre-upload command for "en.json" failed: exit status 1`) {
+			t.Fatalf("expected failure, got %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		cfg := cfg
+		cfg.HookTimeout = 100 * time.Millisecond
+		start := time.Now()
+		err := runHook(context.Background(), cfg, "post-upload", "sleep 30", nil)
+		if err == nil || !strings.Contains(err.Error(), "timed out after 100ms") {
+			t.Fatalf("expected timeout, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 10*time.Second {
+			t.Fatalf("expected the command to be killed, took %v", elapsed)
+		}
+	})
+}
+
+func TestUploadFile_Hooks(t *testing.T) {
+	newFile := func(t *testing.T) (string, UploadConfig) {
+		t.Helper()
+		dir := t.TempDir()
+		path := filepath.Join(dir, "en.json")
+		if err := os.WriteFile(path, []byte(`{"a": "b"}`), 0o644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+		return dir, UploadConfig{FilePath: path, ProjectID: "111.abc", Token: "tok", LangISO: "en", SkipTagging: true, HookTimeout: time.Minute}
+	}
+	readResult := func(t *testing.T, path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("expected the post-upload command to run: %v", err)
+		}
+		return string(data)
+	}
+
+	t.Run("pre-upload command may rewrite the file", func(t *testing.T) {
+		dir, cfg := newFile(t)
+		result := filepath.Join(dir, "result")
+		uploader := &readingUploader{}
+		cfg.PreUploadCommand = `printf '{"a": "converted"}' > "$LOKALISE_FILE"`
+		cfg.PostUploadCommand = `echo "$LOKALISE_UPLOAD_RESULT:$LOKALISE_UPLOAD_ERROR" > ` + result
+
+		if err := uploadFile(context.Background(), cfg, &fakeUploadFactory{uploader: uploader}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if uploader.contents != `{"a": "converted"}` {
+			t.Fatalf("expected the converted contents, got %q", uploader.contents)
+		}
+		if got := readResult(t, result); got != "success:\n" {
+			t.Fatalf("unexpected post-upload result %q", got)
+		}
+	})
+
+	t.Run("failing pre-upload command stops the upload", func(t *testing.T) {
+		dir, cfg := newFile(t)
+		result := filepath.Join(dir, "result")
+		buf := captureLogs(t)
+		ff := &recordingUploadFactory{}
+		cfg.ReportDir = t.TempDir()
+		cfg.PreUploadCommand = "exit 3"
+		cfg.PostUploadCommand = `echo "$LOKALISE_UPLOAD_RESULT" > ` + result
+
+		err := uploadFile(context.Background(), cfg, ff)
+		if err == nil || !strings.Contains(err.Error(), "pre-upload command") {
+			t.Fatalf("expected pre-upload error, got %v", err)
+		}
+		if len(ff.projects) != 0 {
+			t.Fatalf("expected no uploads, got %v", ff.projects)
+		}
+		if !strings.Contains(buf.String(), "Error: "+cfg.FilePath+": pre-upload command") {
+			t.Fatalf("unexpected output %q", buf.String())
+		}
+		if results := readUploadResults(t, cfg.ReportDir); len(results) != 1 || results[0].Status != resultStatusFailed {
+			t.Fatalf("expected a failed result, got %#v", results)
+		}
+		if got := readResult(t, result); got != "failure\n" {
+			t.Fatalf("unexpected post-upload result %q", got)
+		}
+	})
+
+	t.Run("post-upload command sees a failed upload", func(t *testing.T) {
+		dir, cfg := newFile(t)
+		result := filepath.Join(dir, "result")
+		cfg.PostUploadCommand = `echo "$LOKALISE_UPLOAD_RESULT:$LOKALISE_UPLOAD_ERROR" > ` + result
+		uploader := &fakeUploader{returnErr: errors.New("boom")}
+
+		if err := uploadFile(context.Background(), cfg, &fakeUploadFactory{uploader: uploader}); err == nil {
+			t.Fatal("expected upload error")
+		}
+		if got := readResult(t, result); !strings.HasPrefix(got, "failure:") || !strings.Contains(got, "boom") {
+			t.Fatalf("unexpected post-upload result %q", got)
+		}
+	})
+
+	t.Run("failing post-upload command fails the file", func(t *testing.T) {
+		_, cfg := newFile(t)
+		cfg.ReportDir = t.TempDir()
+		cfg.PostUploadCommand = "exit 1"
+
+		err := uploadFile(context.Background(), cfg, &fakeUploadFactory{uploader: &readingUploader{}})
+		if err == nil || !strings.Contains(err.Error(), "post-upload command") {
+			t.Fatalf("expected post-upload error, got %v", err)
+		}
+		if results := readUploadResults(t, cfg.ReportDir); len(results) != 1 || results[0].Status != resultStatusUploaded {
+			t.Fatalf("expected the upload to stay recorded, got %#v", results)
+		}
+	})
+
+	t.Run("skipped files run no command", func(t *testing.T) {
+		dir, cfg := newFile(t)
+		result := filepath.Join(dir, "result")
+		cfg.SkipReason = "file name does not match name_regex"
+		cfg.PreUploadCommand = "touch " + result
+
+		if err := uploadFile(context.Background(), cfg, &fakeUploadFactory{uploader: &readingUploader{}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(result); !os.IsNotExist(err) {
+			t.Fatalf("expected the command not to run, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package lokaliseupload
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	yaml "go.yaml.in/yaml/v4"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/keyfile"
+	"lokalise-push-action/internal/logging"
+)
+
+// keyNamingRule constrains key names. Empty fields are not checked.
+type keyNamingRule struct {
+	Pattern        string `yaml:"pattern"`
+	MaxLength      int    `yaml:"max_length"`
+	ForbiddenChars string `yaml:"forbidden_chars"`
+
+	re *regexp.Regexp
+}
+
+// keyNamingRules is the key naming policy from KEY_NAMING_RULES. The
+// top-level rule applies to every key; a namespace rule applies to the keys
+// below that namespace and overrides the fields it sets.
+type keyNamingRules struct {
+	keyNamingRule `yaml:",inline"`
+	Namespaces    map[string]keyNamingRule `yaml:"namespaces"`
+}
+
+// parseKeyNamingRules parses a YAML (or JSON) key naming policy:
+//
+//	max_length: 100
+//	forbidden_chars: " :"
+//	pattern: '^[a-z0-9_.]+$'
+//	namespaces:
+//	  checkout:
+//	    pattern: '^checkout\.[a-z_.]+$'
+func parseKeyNamingRules(data string) (*keyNamingRules, error) {
+	dec := yaml.NewDecoder(strings.NewReader(data))
+	dec.KnownFields(true)
+
+	var rules keyNamingRules
+	if err := dec.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("invalid KEY_NAMING_RULES (must set pattern, max_length, forbidden_chars, or namespaces): %w", err)
+	}
+
+	if err := compileKeyNamingRule(&rules.keyNamingRule); err != nil {
+		return nil, fmt.Errorf("invalid KEY_NAMING_RULES: %w", err)
+	}
+	for name, rule := range rules.Namespaces {
+		if strings.TrimSpace(name) == "" || name != strings.Trim(name, ".") {
+			return nil, fmt.Errorf("invalid KEY_NAMING_RULES: namespace %q must be a key prefix without leading or trailing dots", name)
+		}
+		if err := compileKeyNamingRule(&rule); err != nil {
+			return nil, fmt.Errorf("invalid KEY_NAMING_RULES for namespace %q: %w", name, err)
+		}
+		rules.Namespaces[name] = rule
+	}
+
+	return &rules, nil
+}
+
+// parseKeyNamingEnv reads KEY_NAMING_CHECK and the rules it checks, given in
+// KEY_NAMING_RULES or the file named by KEY_NAMING_RULES_FILE.
+func parseKeyNamingEnv() (string, *keyNamingRules, error) {
+	mode, err := parseCheckMode("KEY_NAMING_CHECK")
+	if err != nil {
+		return "", nil, err
+	}
+
+	raw, err := envconf.EnvOrFile("KEY_NAMING_RULES")
+	if err != nil {
+		return "", nil, err
+	}
+	if strings.TrimSpace(raw) == "" {
+		if checkEnabled(mode) {
+			return "", nil, fmt.Errorf("KEY_NAMING_RULES or KEY_NAMING_RULES_FILE is required when KEY_NAMING_CHECK is %s", mode)
+		}
+		return mode, nil, nil
+	}
+
+	rules, err := parseKeyNamingRules(raw)
+	if err != nil {
+		return "", nil, err
+	}
+	return mode, rules, nil
+}
+
+// compileKeyNamingRule validates rule and compiles its pattern.
+func compileKeyNamingRule(rule *keyNamingRule) error {
+	if rule.MaxLength < 0 {
+		return fmt.Errorf("max_length cannot be negative")
+	}
+	if rule.Pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return fmt.Errorf("pattern: %w", err)
+	}
+	rule.re = re
+	return nil
+}
+
+// ruleFor returns the rule for key: the top-level rule overridden by the
+// longest namespace the key belongs to, and the name of that namespace.
+func (r *keyNamingRules) ruleFor(key string) (keyNamingRule, string) {
+	rule, namespace := r.keyNamingRule, ""
+	for name := range r.Namespaces {
+		if (key == name || strings.HasPrefix(key, name+".")) && len(name) > len(namespace) {
+			namespace = name
+		}
+	}
+	if namespace == "" {
+		return rule, ""
+	}
+
+	ns := r.Namespaces[namespace]
+	if ns.re != nil {
+		rule.Pattern, rule.re = ns.Pattern, ns.re
+	}
+	if ns.MaxLength > 0 {
+		rule.MaxLength = ns.MaxLength
+	}
+	if ns.ForbiddenChars != "" {
+		rule.ForbiddenChars = ns.ForbiddenChars
+	}
+	return rule, namespace
+}
+
+// problems describes how key breaks the rules.
+func (r *keyNamingRules) problems(key string) []string {
+	rule, namespace := r.ruleFor(key)
+
+	var problems []string
+	if n := utf8.RuneCountInString(key); rule.MaxLength > 0 && n > rule.MaxLength {
+		problems = append(problems, fmt.Sprintf("is %d characters long, more than the limit of %d", n, rule.MaxLength))
+	}
+	if i := strings.IndexAny(key, rule.ForbiddenChars); rule.ForbiddenChars != "" && i >= 0 {
+		c, _ := utf8.DecodeRuneInString(key[i:])
+		problems = append(problems, fmt.Sprintf("contains the forbidden character %q", c))
+	}
+	if rule.re != nil && !rule.re.MatchString(key) {
+		if namespace != "" {
+			problems = append(problems, fmt.Sprintf("does not match the pattern of namespace %q: %s", namespace, rule.Pattern))
+		} else {
+			problems = append(problems, fmt.Sprintf("does not match the pattern %s", rule.Pattern))
+		}
+	}
+	return problems
+}
+
+// checkKeyNames validates the key names of a base-language file against
+// KEY_NAMING_RULES when KEY_NAMING_CHECK is enabled. Files in other
+// languages carry the same keys and are not checked again. Every offending
+// key is annotated on its line; in fail mode they stop the upload.
+func checkKeyNames(cfg UploadConfig) error {
+	if !checkEnabled(cfg.KeyNamingCheck) || cfg.KeyNamingRules == nil {
+		return nil
+	}
+	if !slices.Contains(cfg.BaseLangs, cfg.LangISO) {
+		logs.Debugf("Key naming check skipped for %q: %q is not a base language", cfg.FilePath, cfg.LangISO)
+		return nil
+	}
+
+	_, entries, err := parseKeyFile(cfg)
+	if errors.Is(err, keyfile.ErrUnsupported) {
+		logs.Debugf("Key naming check skipped for %q: unsupported file format", cfg.FilePath)
+		return nil
+	}
+
+	report := logs.WarnAt
+	if cfg.KeyNamingCheck == checkFail {
+		report = logs.ErrorAt
+	}
+	loc := logging.Location{File: cfg.FilePath, Title: "Key naming"}
+
+	if err != nil {
+		report(loc, "cannot check key names: %v", err)
+		return keyNamingCheckError(cfg, 1)
+	}
+
+	problems := 0
+	seen := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		key := e.Key
+		if e.Parent != "" {
+			key = e.Parent
+		}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		for _, msg := range cfg.KeyNamingRules.problems(key) {
+			loc.Line = e.Line
+			report(loc, "key %q %s", key, msg)
+			problems++
+		}
+	}
+	return keyNamingCheckError(cfg, problems)
+}
+
+// keyNamingCheckError fails the upload in fail mode when problems were found.
+func keyNamingCheckError(cfg UploadConfig, problems int) error {
+	if problems == 0 || cfg.KeyNamingCheck != checkFail {
+		return nil
+	}
+	return fmt.Errorf("key naming check found %d problem(s) in %q", problems, cfg.FilePath)
+}
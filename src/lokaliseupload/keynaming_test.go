@@ -0,0 +1,164 @@
+package lokaliseupload
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseKeyNamingRules(t *testing.T) {
+	t.Run("top-level rule and namespaces", func(t *testing.T) {
+		rules, err := parseKeyNamingRules(`
+max_length: 20
+forbidden_chars: " "
+pattern: '^[a-z_.]+$'
+namespaces:
+  checkout:
+    pattern: '^checkout\.[a-z_]+$'
+`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rules.MaxLength != 20 || rules.ForbiddenChars != " " || rules.re == nil {
+			t.Fatalf("unexpected top-level rule %#v", rules.keyNamingRule)
+		}
+		if rules.Namespaces["checkout"].re == nil {
+			t.Fatalf("expected compiled namespace pattern, got %#v", rules.Namespaces)
+		}
+	})
+
+	t.Run("JSON is accepted", func(t *testing.T) {
+		rules, err := parseKeyNamingRules(`{"max_length": 10}`)
+		if err != nil || rules.MaxLength != 10 {
+			t.Fatalf("unexpected result %#v, %v", rules, err)
+		}
+	})
+
+	for _, tt := range []struct{ data, wantErr string }{
+		{data: "max_len: 10", wantErr: "invalid KEY_NAMING_RULES"},
+		{data: "pattern: '(['", wantErr: "invalid KEY_NAMING_RULES: pattern"},
+		{data: "max_length: -1", wantErr: "max_length cannot be negative"},
+		{data: "namespaces:\n  checkout.:\n    max_length: 5", wantErr: `namespace "checkout."`},
+		{data: "namespaces:\n  auth:\n    pattern: '+'", wantErr: `for namespace "auth"`},
+	} {
+		if _, err := parseKeyNamingRules(tt.data); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Fatalf("%q: expected error containing %q, got %v", tt.data, tt.wantErr, err)
+		}
+	}
+}
+
+func TestKeyNamingRules_Problems(t *testing.T) {
+	rules, err := parseKeyNamingRules(`
+max_length: 20
+forbidden_chars: " :"
+pattern: '^[a-z_.]+$'
+namespaces:
+  checkout:
+    pattern: '^checkout\.[a-z]+$'
+  checkout.legacy:
+    pattern: '^checkout\.legacy\.[A-Za-z]+$'
+    max_length: 30
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{key: "home.title"},
+		{key: "checkout.total"},
+		{key: "checkout.legacy.OldTotalAmount"},
+		{key: "home.welcome_message_title", want: []string{"is 26 characters long, more than the limit of 20"}},
+		{key: "Home.Title", want: []string{"does not match the pattern ^[a-z_.]+$"}},
+		{key: "home title", want: []string{
+			`contains the forbidden character ' '`,
+			"does not match the pattern ^[a-z_.]+$",
+		}},
+		{key: "checkout.sub_total", want: []string{`does not match the pattern of namespace "checkout": ^checkout\.[a-z]+$`}},
+		{key: "checkoutpage.title"},
+	}
+
+	for _, tt := range tests {
+		if got := rules.problems(tt.key); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%q: expected %q, got %q", tt.key, tt.want, got)
+		}
+	}
+}
+
+func TestCheckKeyNames(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		return path
+	}
+
+	rules, err := parseKeyNamingRules(`pattern: '^[a-z_.]+$'`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	jsonFile := write("en.json", "{\n  \"home\": {\n    \"Title\": \"Home\"\n  },\n  \"ok\": \"Fine\"\n}\n")
+	xmlFile := write("strings.xml", `<resources>
+    <plurals name="fileCount">
+        <item quantity="one">%d file</item>
+        <item quantity="other">%d files</item>
+    </plurals>
+</resources>`)
+
+	base := UploadConfig{KeyNamingRules: rules, LangISO: "en", BaseLangs: []string{"en"}}
+
+	t.Run("warn reports keys on their lines", func(t *testing.T) {
+		buf := captureLogs(t)
+		cfg := base
+		cfg.FilePath, cfg.KeyNamingCheck = jsonFile, checkWarn
+		if err := checkKeyNames(cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "Warning: " + jsonFile + `:3: key "home.Title" does not match the pattern ^[a-z_.]+$` + "\n"
+		if buf.String() != want {
+			t.Fatalf("expected %q, got %q", want, buf.String())
+		}
+	})
+
+	t.Run("fail stops the upload and checks resource names once", func(t *testing.T) {
+		buf := captureLogs(t)
+		cfg := base
+		cfg.FilePath, cfg.KeyNamingCheck = xmlFile, checkFail
+		err := checkKeyNames(cfg)
+		if err == nil || !strings.Contains(err.Error(), "key naming check found 1 problem(s)") {
+			t.Fatalf("expected key naming error, got %v", err)
+		}
+		if !strings.Contains(buf.String(), `key "fileCount" does not match`) {
+			t.Fatalf("expected the plurals name to be reported, got %q", buf.String())
+		}
+	})
+
+	t.Run("other languages are not checked", func(t *testing.T) {
+		buf := captureLogs(t)
+		cfg := base
+		cfg.FilePath, cfg.KeyNamingCheck, cfg.LangISO = jsonFile, checkFail, "fr"
+		if err := checkKeyNames(cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Fatalf("expected no output, got %q", buf.String())
+		}
+	})
+
+	t.Run("off does nothing", func(t *testing.T) {
+		captureLogs(t)
+		cfg := base
+		cfg.FilePath, cfg.KeyNamingCheck = jsonFile, checkOff
+		if err := checkKeyNames(cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
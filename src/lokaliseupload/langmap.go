@@ -0,0 +1,79 @@
+package lokaliseupload
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bodrovis/lokex/v2/client/upload"
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// languageMapping maps a Lokalise language ISO to the custom ISO used in the repo.
+type languageMapping struct {
+	OriginalLanguageISO string `yaml:"original_language_iso"`
+	CustomLanguageISO   string `yaml:"custom_language_iso"`
+}
+
+// parseLanguageMappings parses LANGUAGE_MAPPINGS: a YAML sequence or JSON array of
+// {original_language_iso, custom_language_iso} pairs. Both sides must be non-empty
+// and each ISO may appear on each side only once.
+func parseLanguageMappings(raw string) ([]languageMapping, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	dec := yaml.NewDecoder(strings.NewReader(raw))
+	dec.KnownFields(true)
+
+	var mappings []languageMapping
+	if err := dec.Decode(&mappings); err != nil {
+		return nil, fmt.Errorf("invalid language_mappings (must be a list of original_language_iso/custom_language_iso pairs): %w", err)
+	}
+
+	seenOriginal := make(map[string]struct{}, len(mappings))
+	seenCustom := make(map[string]struct{}, len(mappings))
+
+	for i := range mappings {
+		m := &mappings[i]
+		m.OriginalLanguageISO = strings.TrimSpace(m.OriginalLanguageISO)
+		m.CustomLanguageISO = strings.TrimSpace(m.CustomLanguageISO)
+
+		if m.OriginalLanguageISO == "" || m.CustomLanguageISO == "" {
+			return nil, fmt.Errorf("invalid language_mappings entry #%d: original_language_iso and custom_language_iso are required", i+1)
+		}
+		if _, dup := seenOriginal[m.OriginalLanguageISO]; dup {
+			return nil, fmt.Errorf("invalid language_mappings: original_language_iso %q is mapped more than once", m.OriginalLanguageISO)
+		}
+		if _, dup := seenCustom[m.CustomLanguageISO]; dup {
+			return nil, fmt.Errorf("invalid language_mappings: custom_language_iso %q is mapped more than once", m.CustomLanguageISO)
+		}
+
+		seenOriginal[m.OriginalLanguageISO] = struct{}{}
+		seenCustom[m.CustomLanguageISO] = struct{}{}
+	}
+
+	return mappings, nil
+}
+
+// applyLanguageMappings injects the "language_mapping" upload param.
+func applyLanguageMappings(params upload.UploadParams, cfg UploadConfig) error {
+	mappings, err := parseLanguageMappings(cfg.LanguageMappings)
+	if err != nil {
+		return err
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	out := make([]map[string]string, 0, len(mappings))
+	for _, m := range mappings {
+		out = append(out, map[string]string{
+			"original_language_iso": m.OriginalLanguageISO,
+			"custom_language_iso":   m.CustomLanguageISO,
+		})
+	}
+
+	params["language_mapping"] = out
+	return nil
+}
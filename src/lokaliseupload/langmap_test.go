@@ -0,0 +1,119 @@
+package lokaliseupload
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bodrovis/lokex/v2/client/upload"
+)
+
+func TestParseLanguageMappings(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []languageMapping
+		wantErr string
+	}{
+		{
+			name: "empty input",
+			raw:  "  ",
+		},
+		{
+			name: "YAML list is parsed and trimmed",
+			raw: `
+- original_language_iso: " en "
+  custom_language_iso: en-US
+- original_language_iso: pt_BR
+  custom_language_iso: pt-br
+`,
+			want: []languageMapping{
+				{OriginalLanguageISO: "en", CustomLanguageISO: "en-US"},
+				{OriginalLanguageISO: "pt_BR", CustomLanguageISO: "pt-br"},
+			},
+		},
+		{
+			name: "JSON array is parsed",
+			raw:  `[{"original_language_iso": "de", "custom_language_iso": "de-DE"}]`,
+			want: []languageMapping{
+				{OriginalLanguageISO: "de", CustomLanguageISO: "de-DE"},
+			},
+		},
+		{
+			name:    "mapping instead of list is rejected",
+			raw:     `{"original_language_iso": "de", "custom_language_iso": "de-DE"}`,
+			wantErr: "invalid language_mappings",
+		},
+		{
+			name:    "unknown field is rejected",
+			raw:     `[{"original_language_iso": "de", "custom_iso": "de-DE"}]`,
+			wantErr: "invalid language_mappings",
+		},
+		{
+			name:    "missing custom iso",
+			raw:     `[{"original_language_iso": "de"}]`,
+			wantErr: "entry #1: original_language_iso and custom_language_iso are required",
+		},
+		{
+			name:    "duplicate original iso",
+			raw:     "- {original_language_iso: de, custom_language_iso: a}\n- {original_language_iso: de, custom_language_iso: b}\n",
+			wantErr: `original_language_iso "de" is mapped more than once`,
+		},
+		{
+			name:    "duplicate custom iso",
+			raw:     "- {original_language_iso: de, custom_language_iso: x}\n- {original_language_iso: fr, custom_language_iso: x}\n",
+			wantErr: `custom_language_iso "x" is mapped more than once`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLanguageMappings(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Fatalf("expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyLanguageMappings(t *testing.T) {
+	t.Run("param is injected", func(t *testing.T) {
+		params := upload.UploadParams{}
+		cfg := UploadConfig{LanguageMappings: "- {original_language_iso: en, custom_language_iso: en-US}"}
+
+		if err := applyLanguageMappings(params, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []map[string]string{{"original_language_iso": "en", "custom_language_iso": "en-US"}}
+		if !reflect.DeepEqual(params["language_mapping"], want) {
+			t.Fatalf("expected %#v, got %#v", want, params["language_mapping"])
+		}
+	})
+
+	t.Run("empty mappings leave params untouched", func(t *testing.T) {
+		params := upload.UploadParams{}
+		if err := applyLanguageMappings(params, UploadConfig{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := params["language_mapping"]; ok {
+			t.Fatalf("language_mapping should be absent")
+		}
+	})
+
+	t.Run("invalid mappings fail param building", func(t *testing.T) {
+		_, err := buildUploadParams(UploadConfig{FilePath: "en.json", LangISO: "en", SkipTagging: true, LanguageMappings: "[{}]"})
+		if err == nil || !strings.Contains(err.Error(), "invalid language_mappings") {
+			t.Fatalf("expected language_mappings error, got %v", err)
+		}
+	})
+}
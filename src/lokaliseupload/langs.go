@@ -0,0 +1,32 @@
+package lokaliseupload
+
+import (
+	"os"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+)
+
+// langSet builds a lookup set from a list of languages.
+func langSet(langs []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(langs))
+	for _, l := range langs {
+		set[l] = struct{}{}
+	}
+	return set
+}
+
+// baseLangs returns BASE_LANG followed by the ADDITIONAL_BASE_LANGS.
+func baseLangs() []string {
+	var langs []string
+	if lang := strings.TrimSpace(os.Getenv("BASE_LANG")); lang != "" {
+		langs = append(langs, lang)
+	}
+	return append(langs, envconf.SplitListEnv("ADDITIONAL_BASE_LANGS")...)
+}
+
+// isLangSkipped reports whether the file's language is listed in SKIP_LANGS.
+func isLangSkipped(cfg UploadConfig) bool {
+	_, skip := langSet(cfg.SkipLangs)[cfg.LangISO]
+	return skip
+}
@@ -0,0 +1,27 @@
+package lokaliseupload
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBaseLangs(t *testing.T) {
+	t.Setenv("BASE_LANG", " en ")
+	t.Setenv("ADDITIONAL_BASE_LANGS", "en_US\nen_GB")
+
+	want := []string{"en", "en_US", "en_GB"}
+	if got := baseLangs(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestIsLangSkipped(t *testing.T) {
+	cfg := UploadConfig{LangISO: "de", SkipLangs: []string{"fr", "de"}}
+	if !isLangSkipped(cfg) {
+		t.Fatal("expected de to be skipped")
+	}
+	cfg.LangISO = "en"
+	if isLangSkipped(cfg) {
+		t.Fatal("expected en not to be skipped")
+	}
+}
@@ -0,0 +1,150 @@
+package lokaliseupload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/logging"
+	"lokalise-push-action/internal/projectmap"
+)
+
+// exitFunc is a function variable that defaults to os.Exit.
+// Overridable in tests to assert exit behavior without terminating the process.
+var exitFunc = os.Exit
+
+// logs is the logger of the command; Main configures it from the environment.
+var logs = logging.New(os.Stdout, os.Stderr)
+
+type uploaderFunc func(context.Context, UploadConfig, ClientFactory) error
+
+// Main uploads the file named on the command line, configured through environment variables.
+// It exits the process on failure.
+func Main() {
+	if err := logs.Configure(); err != nil {
+		returnWithError(err.Error())
+	}
+	if err := run(); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// Check reads and validates the configuration shared by every uploaded file
+// without uploading anything. The base language may be inferred from each
+// file, so only the credentials are required here.
+func Check() error {
+	errs := []error{logs.Configure()}
+	cfg, err := prepareConfig("")
+	if err != nil {
+		return envconf.Join(append(errs, err))
+	}
+	if cfg.ProjectID == "" || cfg.Token == "" {
+		errs = append(errs, validateRequiredFields(cfg))
+	}
+	return envconf.Join(append(errs, validateTaggingInputs(cfg), validateFormatPreset(cfg)))
+}
+
+func run() error {
+	return runWith(
+		os.Args,
+		prepareConfig,
+		validate,
+		uploadFile,
+		&LokaliseFactory{},
+	)
+}
+
+func runWith(
+	args []string,
+	prepare func(string) (UploadConfig, error),
+	validate func(UploadConfig) error,
+	upload uploaderFunc,
+	factory ClientFactory,
+) error {
+	filePath, err := parseCLIArgs(args)
+	if err != nil {
+		return err
+	}
+
+	filePath, err = decodeFilePath(filePath, os.Getenv("FILE_PATH_ENCODING"))
+	if err != nil {
+		return err
+	}
+
+	maskTokens(os.Stdout)
+
+	cfg, err := prepare(filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := validate(cfg); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.UploadTimeout)
+	defer cancel()
+
+	return upload(ctx, cfg, factory)
+}
+
+// maskTokens keeps every API token out of the log, before the config is read:
+// LOKALISE_API_TOKEN and the tokens of all PROJECT_MAPPINGS entries, not only
+// the one the file is uploaded with. Tokens may come from an earlier step
+// rather than a secret, so the runner doesn't mask them otherwise.
+func maskTokens(w io.Writer) {
+	tokens := projectmap.Tokens()
+	if token, err := envconf.EnvOrFile("LOKALISE_API_TOKEN"); err == nil {
+		tokens = append(tokens, token)
+	}
+	for _, token := range tokens {
+		logging.Mask(w, token)
+		logs.AddSecret(token)
+	}
+}
+
+// parseCLIArgs validates the CLI input and returns the target file path.
+func parseCLIArgs(args []string) (string, error) {
+	if len(args) != 2 {
+		return "", fmt.Errorf("usage: lokalise_action upload <file>")
+	}
+
+	// File names may start or end with spaces, so the path is taken as given.
+	filePath := args[1]
+	if filePath == "" {
+		return "", fmt.Errorf("file path is empty")
+	}
+
+	return filePath, nil
+}
+
+// decodeFilePath reverses the encoding applied by findallfiles so that paths
+// with commas, quotes, or line breaks survive the shell loop. Supported
+// encodings: "" or "plain" (as is) and "url" (percent-encoded).
+func decodeFilePath(filePath, encoding string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "plain":
+		return filePath, nil
+	case "url":
+		decoded, err := url.PathUnescape(filePath)
+		if err != nil {
+			return "", fmt.Errorf("cannot decode file path %q: %w", filePath, err)
+		}
+		if strings.TrimSpace(decoded) == "" {
+			return "", fmt.Errorf("file path is empty")
+		}
+		return decoded, nil
+	default:
+		return "", fmt.Errorf("invalid FILE_PATH_ENCODING: expected plain or url, got %q", encoding)
+	}
+}
+
+// returnWithError prints an error message to stderr and exits the program with a non-zero status code.
+func returnWithError(message string) {
+	logs.Errorf("%s", message)
+	exitFunc(1)
+}
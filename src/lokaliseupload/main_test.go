@@ -1,10 +1,11 @@
-package main
+package lokaliseupload
 
 import (
 	"context"
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -25,7 +26,7 @@ func TestRunWith(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
 		t.Parallel()
 
-		args := []string{"lokalise_upload", "  file.json  "}
+		args := []string{"lokaliseupload", "file.json"}
 		wantCfg := UploadConfig{
 			FilePath:      "file.json",
 			ProjectID:     "proj",
@@ -51,7 +52,7 @@ func TestRunWith(t *testing.T) {
 
 		validateFn := func(cfg UploadConfig) error {
 			validateCalled = true
-			if cfg != wantCfg {
+			if !reflect.DeepEqual(cfg, wantCfg) {
 				t.Fatalf("validate got cfg=%#v, want %#v", cfg, wantCfg)
 			}
 			return nil
@@ -60,7 +61,7 @@ func TestRunWith(t *testing.T) {
 		upload := func(ctx context.Context, cfg UploadConfig, gotFactory ClientFactory) error {
 			uploadCalled = true
 
-			if cfg != wantCfg {
+			if !reflect.DeepEqual(cfg, wantCfg) {
 				t.Fatalf("upload got cfg=%#v, want %#v", cfg, wantCfg)
 			}
 			if gotFactory != factory {
@@ -92,7 +93,7 @@ func TestRunWith(t *testing.T) {
 	t.Run("returns parse args error and stops", func(t *testing.T) {
 		t.Parallel()
 
-		args := []string{"lokalise_upload"}
+		args := []string{"lokaliseupload"}
 
 		prepare := func(string) (UploadConfig, error) {
 			t.Fatal("prepare should not be called")
@@ -113,7 +114,7 @@ func TestRunWith(t *testing.T) {
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
-		if !strings.Contains(err.Error(), "usage: lokalise_upload <file>") {
+		if !strings.Contains(err.Error(), "usage: lokalise_action upload <file>") {
 			t.Fatalf("unexpected error: %v", err)
 		}
 	})
@@ -121,7 +122,7 @@ func TestRunWith(t *testing.T) {
 	t.Run("returns prepare error and stops", func(t *testing.T) {
 		t.Parallel()
 
-		args := []string{"lokalise_upload", "file.json"}
+		args := []string{"lokaliseupload", "file.json"}
 
 		prepare := func(filePath string) (UploadConfig, error) {
 			if filePath != "file.json" {
@@ -152,7 +153,7 @@ func TestRunWith(t *testing.T) {
 	t.Run("returns validate error and stops", func(t *testing.T) {
 		t.Parallel()
 
-		args := []string{"lokalise_upload", "file.json"}
+		args := []string{"lokaliseupload", "file.json"}
 
 		wantCfg := UploadConfig{
 			FilePath:      "file.json",
@@ -164,7 +165,7 @@ func TestRunWith(t *testing.T) {
 		}
 
 		validateFn := func(cfg UploadConfig) error {
-			if cfg != wantCfg {
+			if !reflect.DeepEqual(cfg, wantCfg) {
 				t.Fatalf("validate got cfg=%#v, want %#v", cfg, wantCfg)
 			}
 			return errors.New("invalid upload config")
@@ -187,7 +188,7 @@ func TestRunWith(t *testing.T) {
 	t.Run("returns upload error", func(t *testing.T) {
 		t.Parallel()
 
-		args := []string{"lokalise_upload", "file.json"}
+		args := []string{"lokaliseupload", "file.json"}
 
 		wantCfg := UploadConfig{
 			FilePath:      "file.json",
@@ -205,14 +206,14 @@ func TestRunWith(t *testing.T) {
 		}
 
 		validateFn := func(cfg UploadConfig) error {
-			if cfg != wantCfg {
+			if !reflect.DeepEqual(cfg, wantCfg) {
 				t.Fatalf("validate got cfg=%#v, want %#v", cfg, wantCfg)
 			}
 			return nil
 		}
 
 		upload := func(ctx context.Context, cfg UploadConfig, gotFactory ClientFactory) error {
-			if cfg != wantCfg {
+			if !reflect.DeepEqual(cfg, wantCfg) {
 				t.Fatalf("upload got cfg=%#v, want %#v", cfg, wantCfg)
 			}
 			if gotFactory != factory {
@@ -234,6 +235,27 @@ func TestRunWith(t *testing.T) {
 	})
 }
 
+func TestMaskTokens(t *testing.T) {
+	buf := captureLogs(t)
+	t.Setenv("LOKALISE_API_TOKEN", "default-token")
+	t.Setenv("LOKALISE_API_TOKEN_FILE", "")
+	t.Setenv("PROJECT_MAPPINGS", "packages/app=111.abc:app-token\npackages/site=222.def:site-token")
+
+	var out strings.Builder
+	maskTokens(&out)
+
+	for _, token := range []string{"default-token", "app-token", "site-token"} {
+		if !strings.Contains(out.String(), "::add-mask::"+token+"\n") {
+			t.Fatalf("expected %s to be masked, got %q", token, out.String())
+		}
+	}
+
+	logs.Infof("uploading with site-token and default-token")
+	if got := buf.String(); strings.Contains(got, "site-token") || strings.Contains(got, "default-token") {
+		t.Fatalf("expected the tokens to be redacted, got %q", got)
+	}
+}
+
 func TestParseCLIArgs(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -243,23 +265,23 @@ func TestParseCLIArgs(t *testing.T) {
 	}{
 		{
 			name:    "missing CLI arg returns error",
-			args:    []string{"lokalise_upload"},
-			wantErr: "usage: lokalise_upload <file>",
+			args:    []string{"lokaliseupload"},
+			wantErr: "usage: lokalise_action upload <file>",
 		},
 		{
 			name:    "empty CLI arg returns error",
-			args:    []string{"lokalise_upload", "   "},
+			args:    []string{"lokaliseupload", ""},
 			wantErr: "file path is empty",
 		},
 		{
-			name: "valid CLI arg is trimmed",
-			args: []string{"lokalise_upload", "  file.json  "},
-			want: "file.json",
+			name: "CLI arg is taken as given",
+			args: []string{"lokaliseupload", " file, v2.json "},
+			want: " file, v2.json ",
 		},
 		{
 			name:    "too many CLI args returns error",
-			args:    []string{"lokalise_upload", "file.json", "extra"},
-			wantErr: "usage: lokalise_upload <file>",
+			args:    []string{"lokaliseupload", "file.json", "extra"},
+			wantErr: "usage: lokalise_action upload <file>",
 		},
 	}
 
@@ -288,3 +310,63 @@ func TestParseCLIArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeFilePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		encoding string
+		want     string
+		wantErr  string
+	}{
+		{name: "plain by default", path: "a%2Cb.json", want: "a%2Cb.json"},
+		{name: "explicit plain", path: "a b.json", encoding: "plain", want: "a b.json"},
+		{name: "url decoded", path: "locales%2Fen%2Fa%2C%20b%27s%0A.json", encoding: " URL ", want: "locales/en/a, b's\n.json"},
+		{name: "malformed escape", path: "bad%zz.json", encoding: "url", wantErr: "cannot decode file path"},
+		{name: "decodes to blank", path: "%20%20", encoding: "url", wantErr: "file path is empty"},
+		{name: "unknown encoding", path: "a.json", encoding: "base64", wantErr: "invalid FILE_PATH_ENCODING"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := decodeFilePath(tt.path, tt.encoding)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("decodeFilePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for _, key := range configEnvKeys {
+		t.Setenv(key, "")
+	}
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("GITHUB_HEAD_REF", "")
+	t.Setenv("LOKALISE_PROJECT_ID", "123.abc")
+	t.Setenv("LOKALISE_API_TOKEN", "token")
+	t.Setenv("GITHUB_REF_NAME", "main")
+
+	// BASE_LANG may be inferred per file, so it isn't required here.
+	if err := Check(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Setenv("LOKALISE_API_TOKEN", "")
+	t.Setenv("GITHUB_REF_NAME", "")
+	err := Check()
+	if err == nil || !strings.Contains(err.Error(), "API token is required") || !strings.Contains(err.Error(), "GitHub reference name") {
+		t.Fatalf("expected token and ref name errors, got %v", err)
+	}
+}
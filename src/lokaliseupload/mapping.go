@@ -0,0 +1,26 @@
+package lokaliseupload
+
+import (
+	"fmt"
+	"slices"
+)
+
+// mappingValues checks that every value of m is a T, reporting the first
+// offending key in sorted order.
+func mappingValues[T any](m map[string]any) (map[string]T, error) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	out := make(map[string]T, len(m))
+	for _, key := range keys {
+		v, ok := m[key].(T)
+		if !ok {
+			return nil, fmt.Errorf("value for %q is %T, expected %T", key, m[key], v)
+		}
+		out[key] = v
+	}
+	return out, nil
+}
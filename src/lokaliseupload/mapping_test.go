@@ -0,0 +1,22 @@
+package lokaliseupload
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMappingValues(t *testing.T) {
+	got, err := mappingValues[string](map[string]any{"a.json": "en", "b.json": "fr"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string]string{"a.json": "en", "b.json": "fr"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	_, err = mappingValues[string](map[string]any{"a.json": "en", "b.json": 1.0, "c.json": true})
+	want := `value for "b.json" is float64, expected string`
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+}
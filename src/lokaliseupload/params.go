@@ -1,13 +1,13 @@
-package main
+package lokaliseupload
 
 import (
-	"fmt"
-
-	"github.com/bodrovis/lokalise-actions-common/v2/parsers"
 	"github.com/bodrovis/lokex/v2/client/upload"
+
+	"lokalise-push-action/internal/envconf"
 )
 
 // buildUploadParams assembles the payload for the Lokalise upload endpoint.
+// Per-root flag overrides replace the defaults for files under the matching root.
 // AdditionalParams are merged last and may override defaults intentionally.
 func buildUploadParams(cfg UploadConfig) (upload.UploadParams, error) {
 	params := upload.UploadParams{
@@ -16,9 +16,17 @@ func buildUploadParams(cfg UploadConfig) (upload.UploadParams, error) {
 	}
 
 	applyDefaultFlags(params, cfg)
+	if err := applyRootFlagOverrides(params, cfg); err != nil {
+		return nil, err
+	}
+	applyPotParams(params, cfg)
+	applyFormatPreset(params, cfg)
+	if err := applyLanguageMappings(params, cfg); err != nil {
+		return nil, err
+	}
 	applyTagging(params, cfg)
 
-	if err := mergeAdditionalParams(params, cfg.AdditionalParams); err != nil {
+	if err := envconf.MergeAdditionalParams(params, cfg.AdditionalParams); err != nil {
 		return nil, err
 	}
 
@@ -47,11 +55,3 @@ func applyTagging(params upload.UploadParams, cfg UploadConfig) {
 	params["tag_updated_keys"] = true
 	params["tags"] = []string{cfg.GitHubRefName}
 }
-
-// mergeAdditionalParams validates and merges user-provided params into the upload payload.
-func mergeAdditionalParams(params upload.UploadParams, raw string) error {
-	if err := parsers.ParseAdditionalParamsAndMerge(params, raw); err != nil {
-		return fmt.Errorf("invalid additional_params (must be JSON object or YAML mapping): %w", err)
-	}
-	return nil
-}
@@ -1,4 +1,4 @@
-package main
+package lokaliseupload
 
 import (
 	"reflect"
@@ -168,6 +168,23 @@ filename: /tmp/override.json
 				"tag_updated_keys":    true,
 			},
 		},
+		{
+			name: "per-root overrides replace defaults and additional params win",
+			cfg: UploadConfig{
+				FilePath:          "packages/shared/en.json",
+				LangISO:           "en",
+				SkipTagging:       true,
+				RootFlagOverrides: "packages/shared:\n  distinguish_by_file: false\n  include_path: false\n",
+				AdditionalParams:  `{"include_path": true}`,
+			},
+			want: upload.UploadParams{
+				"filename":            "packages/shared/en.json",
+				"lang_iso":            "en",
+				"replace_modified":    true,
+				"include_path":        true,
+				"distinguish_by_file": false,
+			},
+		},
 		{
 			name: "invalid additional params return error",
 			cfg: UploadConfig{
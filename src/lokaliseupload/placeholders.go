@@ -0,0 +1,146 @@
+package lokaliseupload
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"lokalise-push-action/internal/keyfile"
+	"lokalise-push-action/internal/logging"
+)
+
+// printfPlaceholder matches a printf conversion at the start of a string:
+// an optional position, flags, width, precision, length, and the conversion.
+var printfPlaceholder = regexp.MustCompile(`^%(?:(\d+)\$)?[-+ 0#,']*(?:\d+|\*)?(?:\.(?:\d+|\*))?(?:hh|h|ll|l|L|q|j|z|t)?([a-zA-Z@%])`)
+
+// printfConversions are the conversions each printf-style format accepts:
+// Apple strings follow the Foundation format specifiers, Android resources
+// the Java Formatter.
+var printfConversions = map[keyfile.Format]string{
+	keyfile.Strings:    "diouxXDOUeEfFgGaAcCsSpn@%",
+	keyfile.AndroidXML: "bBhHsScCdoxXeEfgGaAtTn%",
+}
+
+// checkPlaceholders looks for suspicious placeholders in the file when
+// PLACEHOLDER_CHECK is enabled. Apple strings and Android resources are
+// checked for printf conversions; the other formats for {var} braces.
+// Every problem is annotated on its line; in fail mode they stop the upload.
+func checkPlaceholders(cfg UploadConfig) error {
+	if !checkEnabled(cfg.PlaceholderCheck) {
+		return nil
+	}
+
+	format, entries, err := parseKeyFile(cfg)
+	if errors.Is(err, keyfile.ErrUnsupported) {
+		logs.Debugf("Placeholder check skipped for %q: unsupported file format", cfg.FilePath)
+		return nil
+	}
+
+	report := logs.WarnAt
+	if cfg.PlaceholderCheck == checkFail {
+		report = logs.ErrorAt
+	}
+	loc := logging.Location{File: cfg.FilePath, Title: "Suspicious placeholder"}
+
+	if err != nil {
+		report(loc, "cannot check placeholders: %v", err)
+		return placeholderCheckError(cfg, 1)
+	}
+
+	problems := 0
+	for _, e := range entries {
+		for _, msg := range placeholderProblems(format, e.Value) {
+			loc.Line = e.Line
+			report(loc, "key %q: %s", e.Key, msg)
+			problems++
+		}
+	}
+	return placeholderCheckError(cfg, problems)
+}
+
+// placeholderCheckError fails the upload in fail mode when problems were found.
+func placeholderCheckError(cfg UploadConfig, problems int) error {
+	if problems == 0 || cfg.PlaceholderCheck != checkFail {
+		return nil
+	}
+	return fmt.Errorf("placeholder check found %d problem(s) in %q", problems, cfg.FilePath)
+}
+
+// placeholderProblems describes what is wrong with the placeholders of value.
+func placeholderProblems(format keyfile.Format, value string) []string {
+	if conversions, ok := printfConversions[format]; ok {
+		return printfProblems(value, conversions)
+	}
+	return braceProblems(value)
+}
+
+// printfProblems reports unknown conversions, a trailing "%", and a mix of
+// positional (%1$s) and sequential (%s) placeholders, which most printf
+// implementations reject.
+func printfProblems(value, conversions string) []string {
+	var problems []string
+	positional, sequential := "", ""
+
+	for i := 0; i < len(value); i++ {
+		if value[i] != '%' {
+			continue
+		}
+		if i == len(value)-1 {
+			problems = append(problems, `ends with a lone "%"; write "%%" for a literal percent sign`)
+			break
+		}
+
+		m := printfPlaceholder.FindStringSubmatch(value[i:])
+		if m == nil || !strings.Contains(conversions, m[2]) {
+			spec := value[i:]
+			if m != nil {
+				spec = m[0]
+			} else if end := strings.IndexAny(spec[1:], " \t\n"); end >= 0 {
+				spec = spec[:end+1]
+			}
+			problems = append(problems, fmt.Sprintf("unknown placeholder %q; write \"%%%%\" for a literal percent sign", spec))
+			if m == nil {
+				continue
+			}
+		}
+
+		i += len(m[0]) - 1
+		switch {
+		case m[2] == "%" || m[2] == "n":
+		case m[1] != "":
+			positional = m[0]
+		default:
+			sequential = m[0]
+		}
+	}
+
+	if positional != "" && sequential != "" {
+		problems = append(problems, fmt.Sprintf("mixes positional (%s) and sequential (%s) placeholders", positional, sequential))
+	}
+	return problems
+}
+
+// braceProblems reports unbalanced braces. Nested braces, as in ICU plurals
+// ({count, plural, one {# file} other {# files}}), are balanced; a quoted
+// brace ('{') is literal text.
+func braceProblems(value string) []string {
+	depth := 0
+	for i := 0; i < len(value); i++ {
+		switch {
+		case strings.HasPrefix(value[i:], "'{'"), strings.HasPrefix(value[i:], "'}'"):
+			i += 2
+		case value[i] == '{':
+			depth++
+		case value[i] == '}':
+			if depth == 0 {
+				return []string{fmt.Sprintf(`unbalanced braces: "}" at offset %d has no matching "{"`, i)}
+			}
+			depth--
+		}
+	}
+	if depth > 0 {
+		return []string{fmt.Sprintf(`unbalanced braces: %d "{" not closed`, depth)}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package lokaliseupload
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/keyfile"
+	"lokalise-push-action/internal/logging"
+)
+
+// captureLogs redirects the package logger to a buffer for the duration of the test.
+func captureLogs(t *testing.T) *strings.Builder {
+	t.Helper()
+	var buf strings.Builder
+	orig := logs
+	t.Cleanup(func() { logs = orig })
+	logs = logging.New(&buf, &buf)
+	return &buf
+}
+
+func TestPrintfProblems(t *testing.T) {
+	conversions := printfConversions[keyfile.Strings]
+
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "valid placeholders", value: "%@ has %d new messages (100%%)"},
+		{name: "width, precision and length", value: "%-5.2f and %lld"},
+		{name: "positional only", value: "%1$@ sent %2$d files"},
+		{name: "no placeholders", value: "Hello"},
+		{
+			name:  "trailing percent",
+			value: "Done: 100%",
+			want:  []string{`ends with a lone "%"; write "%%" for a literal percent sign`},
+		},
+		{
+			name:  "unknown conversion",
+			value: "Saved %k items",
+			want:  []string{`unknown placeholder "%k"; write "%%" for a literal percent sign`},
+		},
+		{
+			name:  "mixed positional and sequential",
+			value: "%1$@ sent %d files",
+			want:  []string{"mixes positional (%1$@) and sequential (%d) placeholders"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := printfProblems(tt.value, conversions)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("conversions differ by format", func(t *testing.T) {
+		if got := printfProblems("%@", printfConversions[keyfile.AndroidXML]); len(got) != 1 {
+			t.Fatalf("expected %%@ to be unknown in Android resources, got %q", got)
+		}
+		if got := printfProblems("%b", printfConversions[keyfile.AndroidXML]); got != nil {
+			t.Fatalf("expected %%b to be valid in Android resources, got %q", got)
+		}
+	})
+}
+
+func TestBraceProblems(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "Hello, {name}!"},
+		{value: "{count, plural, one {# file} other {# files}}"},
+		{value: "Use '{' to open"},
+		{value: "Hello, {name!", want: []string{`unbalanced braces: 1 "{" not closed`}},
+		{value: "Hello, name}!", want: []string{`unbalanced braces: "}" at offset 11 has no matching "{"`}},
+	}
+
+	for _, tt := range tests {
+		if got := braceProblems(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%q: expected %q, got %q", tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestCheckPlaceholders(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		return path
+	}
+
+	jsonFile := write("en.json", "{\n  \"greeting\": \"Hello, {name\",\n  \"ok\": \"Fine\"\n}\n")
+	stringsFile := write("Localizable.strings", "\"saved\" = \"Saved 100%\";\n\"sent\" = \"%1$@ sent %d\";\n")
+	cleanFile := write("fr.json", `{"greeting": "Bonjour, {name}"}`)
+	brokenFile := write("de.json", `{"greeting": `)
+	otherFile := write("en.po", "msgid \"hello\"\nmsgstr \"{\"\n")
+
+	t.Run("off does nothing", func(t *testing.T) {
+		buf := captureLogs(t)
+		if err := checkPlaceholders(UploadConfig{FilePath: jsonFile, PlaceholderCheck: checkOff}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Fatalf("expected no output, got %q", buf.String())
+		}
+	})
+
+	t.Run("warn reports problems on their lines", func(t *testing.T) {
+		buf := captureLogs(t)
+		if err := checkPlaceholders(UploadConfig{FilePath: jsonFile, PlaceholderCheck: checkWarn}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "Warning: " + jsonFile + `:2: key "greeting": unbalanced braces: 1 "{" not closed` + "\n"
+		if buf.String() != want {
+			t.Fatalf("expected %q, got %q", want, buf.String())
+		}
+	})
+
+	t.Run("fail stops the upload", func(t *testing.T) {
+		buf := captureLogs(t)
+		err := checkPlaceholders(UploadConfig{FilePath: stringsFile, PlaceholderCheck: checkFail})
+		if err == nil || !strings.Contains(err.Error(), "placeholder check found 2 problem(s)") {
+			t.Fatalf("expected placeholder check error, got %v", err)
+		}
+		for _, want := range []string{
+			stringsFile + `:1: key "saved": ends with a lone "%"`,
+			stringsFile + `:2: key "sent": mixes positional`,
+		} {
+			if !strings.Contains(buf.String(), want) {
+				t.Fatalf("expected output to contain %q, got %q", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("clean file passes", func(t *testing.T) {
+		captureLogs(t)
+		if err := checkPlaceholders(UploadConfig{FilePath: cleanFile, PlaceholderCheck: checkFail}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("unparsable file is a problem", func(t *testing.T) {
+		buf := captureLogs(t)
+		err := checkPlaceholders(UploadConfig{FilePath: brokenFile, PlaceholderCheck: checkFail})
+		if err == nil || !strings.Contains(err.Error(), "1 problem(s)") {
+			t.Fatalf("expected placeholder check error, got %v", err)
+		}
+		if !strings.Contains(buf.String(), "cannot check placeholders") {
+			t.Fatalf("expected parse error in output, got %q", buf.String())
+		}
+	})
+
+	t.Run("unsupported format is skipped", func(t *testing.T) {
+		captureLogs(t)
+		if err := checkPlaceholders(UploadConfig{FilePath: otherFile, PlaceholderCheck: checkFail}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package lokaliseupload
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/bodrovis/lokex/v2/client/upload"
+)
+
+// isPotSource reports whether the file is a gettext template uploaded in the PO format.
+func isPotSource(cfg UploadConfig) bool {
+	return strings.EqualFold(cfg.FileFormat, "po") &&
+		strings.EqualFold(filepath.Ext(cfg.FilePath), ".pot")
+}
+
+// applyPotParams adjusts params for .pot base files when FILE_FORMAT is "po".
+// Templates carry no language in their name, so language detection is disabled
+// and lang_iso is always taken from BASE_LANG. With MapPotToPo the file is
+// registered on Lokalise under the ".po" extension.
+func applyPotParams(params upload.UploadParams, cfg UploadConfig) {
+	if !isPotSource(cfg) {
+		return
+	}
+
+	params["skip_detect_lang_iso"] = true
+
+	if cfg.MapPotToPo {
+		params["filename"] = potToPoFilename(cfg.FilePath)
+	}
+}
+
+// potToPoFilename replaces the ".pot" extension with ".po".
+func potToPoFilename(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".po"
+}
+
+// uploadSourcePath returns the path to read file contents from when it differs
+// from the "filename" param: a converted copy, or the template itself when
+// MapPotToPo renames it. An empty string means "read from filename".
+func uploadSourcePath(cfg UploadConfig) string {
+	if cfg.SourcePath != "" {
+		return cfg.SourcePath
+	}
+	if isPotSource(cfg) && cfg.MapPotToPo {
+		return cfg.FilePath
+	}
+	return ""
+}
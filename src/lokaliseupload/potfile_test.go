@@ -0,0 +1,75 @@
+package lokaliseupload
+
+import (
+	"testing"
+
+	"github.com/bodrovis/lokex/v2/client/upload"
+)
+
+func TestApplyPotParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          UploadConfig
+		wantFilename string
+		wantSkipLang bool
+		wantSrcPath  string
+	}{
+		{
+			name:         "pot with po format disables language detection",
+			cfg:          UploadConfig{FilePath: "locales/messages.pot", FileFormat: "po"},
+			wantFilename: "locales/messages.pot",
+			wantSkipLang: true,
+		},
+		{
+			name:         "pot mapped to po extension",
+			cfg:          UploadConfig{FilePath: "locales/messages.pot", FileFormat: "po", MapPotToPo: true},
+			wantFilename: "locales/messages.po",
+			wantSkipLang: true,
+			wantSrcPath:  "locales/messages.pot",
+		},
+		{
+			name:         "extension comparison is case-insensitive",
+			cfg:          UploadConfig{FilePath: "locales/messages.POT", FileFormat: "PO", MapPotToPo: true},
+			wantFilename: "locales/messages.po",
+			wantSkipLang: true,
+			wantSrcPath:  "locales/messages.POT",
+		},
+		{
+			name:         "regular po file is untouched",
+			cfg:          UploadConfig{FilePath: "locales/en.po", FileFormat: "po", MapPotToPo: true},
+			wantFilename: "locales/en.po",
+		},
+		{
+			name:         "converted copy is read instead of the template",
+			cfg:          UploadConfig{FilePath: "locales/messages.pot", SourcePath: "/tmp/lokalise-upload-1.pot", FileFormat: "po", MapPotToPo: true},
+			wantFilename: "locales/messages.po",
+			wantSkipLang: true,
+			wantSrcPath:  "/tmp/lokalise-upload-1.pot",
+		},
+		{
+			name:         "pot without po format is untouched",
+			cfg:          UploadConfig{FilePath: "locales/messages.pot", MapPotToPo: true},
+			wantFilename: "locales/messages.pot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := upload.UploadParams{"filename": tt.cfg.FilePath}
+			applyPotParams(params, tt.cfg)
+
+			if params["filename"] != tt.wantFilename {
+				t.Fatalf("expected filename %q, got %#v", tt.wantFilename, params["filename"])
+			}
+
+			_, hasSkip := params["skip_detect_lang_iso"]
+			if hasSkip != tt.wantSkipLang {
+				t.Fatalf("expected skip_detect_lang_iso presence=%v, got %v", tt.wantSkipLang, hasSkip)
+			}
+
+			if got := uploadSourcePath(tt.cfg); got != tt.wantSrcPath {
+				t.Fatalf("expected source path %q, got %q", tt.wantSrcPath, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package lokaliseupload
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bodrovis/lokex/v2/client/upload"
+)
+
+// formatPresets holds curated upload params per file format.
+// Values follow Lokalise recommendations for each format and are applied
+// before additional_params, so users can still override any of them.
+var formatPresets = map[string]upload.UploadParams{
+	"json": {
+		"convert_placeholders": true,
+		"detect_icu_plurals":   true,
+	},
+	"po": {
+		"convert_placeholders": true,
+	},
+	"xliff": {
+		"convert_placeholders": true,
+	},
+	"strings": {
+		"convert_placeholders": true,
+	},
+	"properties": {
+		"convert_placeholders": true,
+		"detect_icu_plurals":   true,
+	},
+}
+
+// presetFormatByExt maps file extensions to preset names when FILE_FORMAT is not set.
+var presetFormatByExt = map[string]string{
+	".json":       "json",
+	".po":         "po",
+	".pot":        "po",
+	".xlf":        "xliff",
+	".xliff":      "xliff",
+	".strings":    "strings",
+	".properties": "properties",
+}
+
+// resolvePresetFormat returns the preset name for the upload.
+// FILE_FORMAT wins; otherwise the format is inferred from the file extension.
+func resolvePresetFormat(cfg UploadConfig) string {
+	if cfg.FileFormat != "" {
+		return cfg.FileFormat
+	}
+	return presetFormatByExt[strings.ToLower(filepath.Ext(cfg.FilePath))]
+}
+
+// applyFormatPreset copies the preset for the resolved format into params.
+// Files without a known format are uploaded without preset params.
+func applyFormatPreset(params upload.UploadParams, cfg UploadConfig) {
+	if !cfg.UseFormatPreset {
+		return
+	}
+
+	preset, ok := formatPresets[resolvePresetFormat(cfg)]
+	if !ok {
+		return
+	}
+
+	for k, v := range preset {
+		params[k] = v
+	}
+}
+
+// validateFormatPreset ensures an explicitly configured format has a preset.
+func validateFormatPreset(cfg UploadConfig) error {
+	if !cfg.UseFormatPreset || cfg.FileFormat == "" {
+		return nil
+	}
+	if _, ok := formatPresets[cfg.FileFormat]; !ok {
+		return fmt.Errorf("no parameter preset available for file format %q (supported: json, po, properties, strings, xliff)", cfg.FileFormat)
+	}
+	return nil
+}
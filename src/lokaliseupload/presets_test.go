@@ -0,0 +1,99 @@
+package lokaliseupload
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bodrovis/lokex/v2/client/upload"
+)
+
+func TestResolvePresetFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  UploadConfig
+		want string
+	}{
+		{name: "explicit format wins", cfg: UploadConfig{FilePath: "en.json", FileFormat: "po"}, want: "po"},
+		{name: "json inferred", cfg: UploadConfig{FilePath: "locales/en.json"}, want: "json"},
+		{name: "xlf inferred as xliff", cfg: UploadConfig{FilePath: "locales/en.XLF"}, want: "xliff"},
+		{name: "pot inferred as po", cfg: UploadConfig{FilePath: "messages.pot"}, want: "po"},
+		{name: "unknown extension", cfg: UploadConfig{FilePath: "en.yml"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolvePresetFormat(tt.cfg); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyFormatPreset(t *testing.T) {
+	t.Run("disabled preset leaves params untouched", func(t *testing.T) {
+		params := upload.UploadParams{}
+		applyFormatPreset(params, UploadConfig{FilePath: "en.json"})
+		if len(params) != 0 {
+			t.Fatalf("expected no params, got %#v", params)
+		}
+	})
+
+	t.Run("json preset is applied", func(t *testing.T) {
+		params := upload.UploadParams{}
+		applyFormatPreset(params, UploadConfig{FilePath: "en.json", UseFormatPreset: true})
+		if params["convert_placeholders"] != true || params["detect_icu_plurals"] != true {
+			t.Fatalf("expected json preset, got %#v", params)
+		}
+	})
+
+	t.Run("unknown format applies nothing", func(t *testing.T) {
+		params := upload.UploadParams{}
+		applyFormatPreset(params, UploadConfig{FilePath: "en.yml", UseFormatPreset: true})
+		if len(params) != 0 {
+			t.Fatalf("expected no params, got %#v", params)
+		}
+	})
+
+	t.Run("preset does not leak mutations between calls", func(t *testing.T) {
+		params := upload.UploadParams{}
+		applyFormatPreset(params, UploadConfig{FilePath: "en.po", UseFormatPreset: true})
+		params["convert_placeholders"] = false
+
+		if formatPresets["po"]["convert_placeholders"] != true {
+			t.Fatalf("preset was mutated: %#v", formatPresets["po"])
+		}
+	})
+
+	t.Run("additional params override preset", func(t *testing.T) {
+		params, err := buildUploadParams(UploadConfig{
+			FilePath:         "en.json",
+			LangISO:          "en",
+			SkipTagging:      true,
+			UseFormatPreset:  true,
+			AdditionalParams: `{"detect_icu_plurals": false}`,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if params["detect_icu_plurals"] != false || params["convert_placeholders"] != true {
+			t.Fatalf("unexpected params: %#v", params)
+		}
+	})
+}
+
+func TestValidateFormatPreset(t *testing.T) {
+	if err := validateFormatPreset(UploadConfig{FileFormat: "yaml"}); err != nil {
+		t.Fatalf("disabled preset must not validate format, got %v", err)
+	}
+	if err := validateFormatPreset(UploadConfig{UseFormatPreset: true}); err != nil {
+		t.Fatalf("inferred format must not fail validation, got %v", err)
+	}
+	if err := validateFormatPreset(UploadConfig{UseFormatPreset: true, FileFormat: "xliff"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := validateFormatPreset(UploadConfig{UseFormatPreset: true, FileFormat: "yaml"})
+	if err == nil || !strings.Contains(err.Error(), "no parameter preset") {
+		t.Fatalf("expected preset error, got %v", err)
+	}
+}
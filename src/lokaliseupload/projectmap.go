@@ -0,0 +1,26 @@
+package lokaliseupload
+
+import "lokalise-push-action/internal/projectmap"
+
+// applyProjectMapping points cfg at the project configured for the file's root.
+// Mapped files are uploaded to that single project only (no mirrors).
+// Files outside every mapped root keep the default project and token.
+func applyProjectMapping(cfg *UploadConfig) error {
+	mappings, err := projectmap.Parse()
+	if err != nil {
+		return err
+	}
+
+	m, ok := projectmap.Match(mappings, cfg.FilePath)
+	if !ok {
+		return nil
+	}
+
+	cfg.ProjectID = m.ProjectID
+	cfg.MirrorProjectIDs = nil
+	if m.Token != "" {
+		cfg.Token = m.Token
+	}
+
+	return nil
+}
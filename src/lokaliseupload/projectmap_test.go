@@ -0,0 +1,30 @@
+package lokaliseupload
+
+import "testing"
+
+func TestApplyProjectMapping(t *testing.T) {
+	t.Setenv("PROJECT_MAPPINGS", "packages=100.aaa, packages/app=111.abc:app-token")
+
+	tests := []struct {
+		name      string
+		filePath  string
+		wantID    string
+		wantToken string
+	}{
+		{name: "most specific root wins", filePath: "packages/app/en.json", wantID: "111.abc", wantToken: "app-token"},
+		{name: "parent root matches", filePath: "packages/lib/en.json", wantID: "100.aaa", wantToken: "default-token"},
+		{name: "unmapped file keeps defaults", filePath: "locales/en.json", wantID: "default.proj", wantToken: "default-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := UploadConfig{FilePath: tt.filePath, ProjectID: "default.proj", Token: "default-token"}
+			if err := applyProjectMapping(&cfg); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.ProjectID != tt.wantID || cfg.Token != tt.wantToken {
+				t.Fatalf("expected %q/%q, got %q/%q", tt.wantID, tt.wantToken, cfg.ProjectID, cfg.Token)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package lokaliseupload
+
+import (
+	"time"
+
+	"github.com/bodrovis/lokex/v2/client/upload"
+
+	"lokalise-push-action/internal/pushstate"
+)
+
+// The push state is an optimization: when it cannot be read or written, the
+// file is uploaded as if there were no cache, and the problem is only logged.
+
+// pushFingerprint returns the fingerprint of uploading the file with params,
+// or "" when there is no push state cache.
+func pushFingerprint(cfg UploadConfig, params upload.UploadParams) string {
+	if cfg.CacheDir == "" {
+		return ""
+	}
+	fingerprint, err := pushstate.Fingerprint(cfg.FilePath, params)
+	if err != nil {
+		logs.Warnf("Cannot fingerprint %q for the push state: %v", cfg.FilePath, err)
+		return ""
+	}
+	return fingerprint
+}
+
+// pushedUnchanged reports whether the push state of cfg.ProjectID shows the
+// file was last pushed with fingerprint.
+func pushedUnchanged(cfg UploadConfig, fingerprint string) bool {
+	if fingerprint == "" {
+		return false
+	}
+	state, err := pushstate.Load(cfg.CacheDir, cfg.ProjectID)
+	if err != nil {
+		logs.Warnf("Cannot read the push state of project %s: %v", cfg.ProjectID, err)
+		return false
+	}
+	return state.Unchanged(cfg.FilePath, fingerprint)
+}
+
+// recordPush adds a successful upload to the push state of cfg.ProjectID.
+func recordPush(cfg UploadConfig, fingerprint string, startedAt time.Time, processID string) {
+	if fingerprint == "" {
+		return
+	}
+	err := pushstate.RecordUpload(cfg.CacheDir, cfg.ProjectID, pushstate.Upload{
+		File:        cfg.FilePath,
+		Fingerprint: fingerprint,
+		LangISO:     cfg.LangISO,
+		ProcessID:   processID,
+		PushedAt:    startedAt.Unix(),
+	})
+	if err != nil {
+		logs.Warnf("Cannot record the upload of %q in the push state: %v", cfg.FilePath, err)
+	}
+}
@@ -0,0 +1,123 @@
+package lokaliseupload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lokalise-push-action/internal/pushstate"
+)
+
+func TestUploadFile_PushState(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "en.json")
+	if err := os.WriteFile(file, []byte(`{"hello":"world"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cache := filepath.Join(dir, "cache")
+
+	cfg := UploadConfig{
+		FilePath:      file,
+		ProjectID:     "111.abc",
+		Token:         "tok",
+		LangISO:       "en",
+		GitHubRefName: "main",
+		CacheDir:      cache,
+	}
+
+	upload := func(cfg UploadConfig) *fakeUploader {
+		t.Helper()
+		fu := &fakeUploader{returnPID: "upl_1"}
+		if err := uploadFile(context.Background(), cfg, &fakeUploadFactory{uploader: fu}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return fu
+	}
+
+	if fu := upload(cfg); !fu.called {
+		t.Fatalf("expected the first push to upload")
+	}
+	state, err := pushstate.Load(cache, "111.abc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Pending() != 1 || state.Files[file].LangISO != "en" || len(state.Processes) != 1 || state.Processes[0].ID != "upl_1" {
+		t.Fatalf("expected the upload to be recorded, got %+v", state)
+	}
+
+	if fu := upload(cfg); fu.called {
+		t.Fatalf("expected an unchanged file to be skipped")
+	}
+
+	forced := cfg
+	forced.ForceUpload = true
+	if fu := upload(forced); !fu.called {
+		t.Fatalf("expected FORCE_UPLOAD to upload an unchanged file")
+	}
+
+	otherBranch := cfg
+	otherBranch.GitHubRefName = "release"
+	if fu := upload(otherBranch); !fu.called {
+		t.Fatalf("expected other upload params to upload the file again")
+	}
+
+	if err := os.WriteFile(file, []byte(`{"hello":"there"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if fu := upload(cfg); !fu.called {
+		t.Fatalf("expected changed contents to upload the file again")
+	}
+
+	mirror := cfg
+	mirror.ProjectID = "222.def"
+	if fu := upload(mirror); !fu.called {
+		t.Fatalf("expected each project to keep its own state")
+	}
+}
+
+func TestUploadFile_PushStateFailures(t *testing.T) {
+	t.Run("failed uploads are not recorded", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "en.json")
+		if err := os.WriteFile(file, []byte(`{}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg := UploadConfig{FilePath: file, ProjectID: "111.abc", Token: "tok", LangISO: "en", CacheDir: dir}
+
+		ff := &recordingUploadFactory{failProject: "111.abc"}
+		if err := uploadFile(context.Background(), cfg, ff); err == nil {
+			t.Fatalf("expected the upload to fail")
+		}
+		state, err := pushstate.Load(dir, "111.abc")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if state.Pending() != 0 {
+			t.Fatalf("expected no record of a failed upload, got %d", state.Pending())
+		}
+	})
+
+	t.Run("unreadable state does not block uploads", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "en.json")
+		if err := os.WriteFile(file, []byte(`{}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(dir, "111.abc"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "111.abc", "state.json"), []byte("{"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg := UploadConfig{FilePath: file, ProjectID: "111.abc", Token: "tok", LangISO: "en", CacheDir: dir}
+
+		fu := &fakeUploader{returnPID: "upl_1"}
+		if err := uploadFile(context.Background(), cfg, &fakeUploadFactory{uploader: fu}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !fu.called {
+			t.Fatalf("expected the file to be uploaded")
+		}
+	})
+}
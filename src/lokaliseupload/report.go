@@ -0,0 +1,82 @@
+package lokaliseupload
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	resultStatusUploaded = "uploaded"
+	resultStatusFailed   = "failed"
+)
+
+// uploadResult describes the outcome of uploading one file to one project.
+// Results are consumed by the postpush step to build run reports.
+type uploadResult struct {
+	File      string `json:"file"`
+	ProjectID string `json:"project_id"`
+	LangISO   string `json:"lang_iso"`
+	ProcessID string `json:"process_id,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	// StartedAt is the Unix time the upload began; keys created since then are new.
+	StartedAt int64 `json:"started_at,omitempty"`
+}
+
+// newUploadResult builds a result record for cfg; err==nil marks a successful upload.
+func newUploadResult(cfg UploadConfig, startedAt time.Time, processID string, err error) uploadResult {
+	res := uploadResult{
+		File:      cfg.FilePath,
+		ProjectID: cfg.ProjectID,
+		LangISO:   cfg.LangISO,
+		ProcessID: processID,
+		Status:    resultStatusUploaded,
+		StartedAt: startedAt.Unix(),
+	}
+	if err != nil {
+		res.Status = resultStatusFailed
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// writeUploadResult stores res as a uniquely named JSON file inside dir.
+// Each upload runs in its own process, so one file per result avoids
+// concurrent writers. An empty dir disables reporting.
+func writeUploadResult(dir string, res uploadResult) error {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("cannot create report directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, "result-*.json")
+	if err != nil {
+		return fmt.Errorf("cannot create report file: %w", err)
+	}
+
+	encErr := json.NewEncoder(file).Encode(res)
+	closeErr := file.Close()
+	if encErr != nil {
+		return fmt.Errorf("cannot write report file: %w", encErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("cannot close report file: %w", closeErr)
+	}
+
+	return nil
+}
+
+// reportUploadResult records the result and only warns on failure:
+// reporting problems must never fail an otherwise successful upload.
+func reportUploadResult(cfg UploadConfig, startedAt time.Time, processID string, uploadErr error) {
+	if err := writeUploadResult(cfg.ReportDir, newUploadResult(cfg, startedAt, processID, uploadErr)); err != nil {
+		logs.Warnf("%v", err)
+	}
+}
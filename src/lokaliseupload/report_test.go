@@ -0,0 +1,121 @@
+package lokaliseupload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readUploadResults(t *testing.T, dir string) []uploadResult {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read report dir: %v", err)
+	}
+
+	var out []uploadResult
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatalf("read report file: %v", err)
+		}
+		var res uploadResult
+		if err := json.Unmarshal(data, &res); err != nil {
+			t.Fatalf("decode report file %s: %v", e.Name(), err)
+		}
+		out = append(out, res)
+	}
+	return out
+}
+
+func TestNewUploadResult(t *testing.T) {
+	cfg := UploadConfig{FilePath: "locales/en.json", ProjectID: "proj_1", LangISO: "en"}
+
+	startedAt := time.Unix(1700000000, 0)
+
+	ok := newUploadResult(cfg, startedAt, "pid_1", nil)
+	want := uploadResult{File: "locales/en.json", ProjectID: "proj_1", LangISO: "en", ProcessID: "pid_1", Status: resultStatusUploaded, StartedAt: 1700000000}
+	if ok != want {
+		t.Fatalf("expected %#v, got %#v", want, ok)
+	}
+
+	failed := newUploadResult(cfg, startedAt, "", errors.New("boom"))
+	if failed.Status != resultStatusFailed || failed.Error != "boom" {
+		t.Fatalf("expected failed result with error, got %#v", failed)
+	}
+}
+
+func TestWriteUploadResult(t *testing.T) {
+	t.Run("empty dir disables reporting", func(t *testing.T) {
+		if err := writeUploadResult("  ", uploadResult{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("writes one file per result and creates dir", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "report")
+
+		first := uploadResult{File: "en.json", ProjectID: "p1", Status: resultStatusUploaded}
+		second := uploadResult{File: "fr.json", ProjectID: "p1", Status: resultStatusFailed, Error: "nope"}
+		for _, r := range []uploadResult{first, second} {
+			if err := writeUploadResult(dir, r); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		got := readUploadResults(t, dir)
+		if len(got) != 2 {
+			t.Fatalf("expected 2 results, got %d", len(got))
+		}
+		seen := map[string]uploadResult{}
+		for _, r := range got {
+			seen[r.File] = r
+		}
+		if seen["en.json"] != first || seen["fr.json"] != second {
+			t.Fatalf("unexpected results: %#v", got)
+		}
+	})
+
+	t.Run("unusable dir returns error", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(file, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeUploadResult(file, uploadResult{}); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestUploadFile_WritesReport(t *testing.T) {
+	dir := t.TempDir()
+	cfg := UploadConfig{
+		FilePath:  "locales/en.json",
+		ProjectID: "proj_1",
+		Token:     "tok",
+		LangISO:   "en",
+		ReportDir: dir,
+	}
+
+	ff := &fakeUploadFactory{uploader: &fakeUploader{returnPID: "pid_42"}}
+	before := time.Now().Unix()
+	if err := uploadFile(context.Background(), cfg, ff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := readUploadResults(t, dir)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(got))
+	}
+	if got[0].ProcessID != "pid_42" || got[0].Status != resultStatusUploaded || got[0].ProjectID != "proj_1" {
+		t.Fatalf("unexpected result: %#v", got[0])
+	}
+	if got[0].StartedAt < before || got[0].StartedAt > time.Now().Unix() {
+		t.Fatalf("unexpected started_at: %d", got[0].StartedAt)
+	}
+}
@@ -0,0 +1,114 @@
+package lokaliseupload
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bodrovis/lokex/v2/client/upload"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+// overridableRootFlags lists the default flags that may be configured per root.
+var overridableRootFlags = map[string]struct{}{
+	"distinguish_by_file": {},
+	"include_path":        {},
+}
+
+// rootFlagOverride holds flag values configured for a single translations root.
+type rootFlagOverride struct {
+	Root  string
+	Flags map[string]bool
+}
+
+// parseRootFlagOverrides parses ROOT_FLAG_OVERRIDES (JSON object or YAML mapping)
+// in the form "<root>: {distinguish_by_file: bool, include_path: bool}".
+// Roots must be repo-relative paths; only the flags listed in overridableRootFlags are accepted.
+func parseRootFlagOverrides(raw string) ([]rootFlagOverride, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	obj, err := envconf.ParseMap(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root_flag_overrides (must be JSON object or YAML mapping): %w", err)
+	}
+
+	seen := make(map[string]struct{}, len(obj))
+	out := make([]rootFlagOverride, 0, len(obj))
+	for rawRoot, rawFlags := range obj {
+		root, err := pathnorm.EnsureRepoRelativePath(rawRoot)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root_flag_overrides root %q: %w", rawRoot, err)
+		}
+		root = filepath.ToSlash(root)
+		if _, dup := seen[root]; dup {
+			return nil, fmt.Errorf("invalid root_flag_overrides: root %q is configured more than once", root)
+		}
+		seen[root] = struct{}{}
+
+		flagsObj, ok := rawFlags.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid root_flag_overrides for %q: expected a mapping of flags", rawRoot)
+		}
+
+		flags := make(map[string]bool, len(flagsObj))
+		for name, value := range flagsObj {
+			if _, ok := overridableRootFlags[name]; !ok {
+				return nil, fmt.Errorf("invalid root_flag_overrides for %q: unsupported flag %q", rawRoot, name)
+			}
+			b, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("invalid root_flag_overrides for %q: flag %q must be true or false", rawRoot, name)
+			}
+			flags[name] = b
+		}
+
+		out = append(out, rootFlagOverride{Root: root, Flags: flags})
+	}
+
+	return out, nil
+}
+
+// matchRootFlagOverride returns the override whose root contains filePath.
+// When several roots match, the most specific (longest) one wins.
+func matchRootFlagOverride(overrides []rootFlagOverride, filePath string) (rootFlagOverride, bool) {
+	path := pathnorm.RepoFilePath(filePath)
+
+	var best rootFlagOverride
+	found := false
+
+	for _, o := range overrides {
+		if !pathnorm.WithinRoot(path, o.Root) {
+			continue
+		}
+		if !found || len(o.Root) > len(best.Root) {
+			best = o
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// applyRootFlagOverrides replaces default flags with the values configured for the file's root.
+func applyRootFlagOverrides(params upload.UploadParams, cfg UploadConfig) error {
+	overrides, err := parseRootFlagOverrides(cfg.RootFlagOverrides)
+	if err != nil {
+		return err
+	}
+
+	override, ok := matchRootFlagOverride(overrides, cfg.FilePath)
+	if !ok {
+		return nil
+	}
+
+	for name, value := range override.Flags {
+		params[name] = value
+	}
+
+	return nil
+}
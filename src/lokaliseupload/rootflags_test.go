@@ -0,0 +1,192 @@
+package lokaliseupload
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bodrovis/lokex/v2/client/upload"
+)
+
+func TestParseRootFlagOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    map[string]map[string]bool
+		wantErr string
+	}{
+		{
+			name: "empty input yields no overrides",
+			raw:  "   ",
+			want: map[string]map[string]bool{},
+		},
+		{
+			name: "YAML mapping is parsed and roots are normalized",
+			raw: `
+./apps/web:
+  distinguish_by_file: true
+packages/shared/:
+  distinguish_by_file: false
+  include_path: false
+`,
+			want: map[string]map[string]bool{
+				"apps/web":        {"distinguish_by_file": true},
+				"packages/shared": {"distinguish_by_file": false, "include_path": false},
+			},
+		},
+		{
+			name: "JSON object is parsed",
+			raw:  `{"locales": {"include_path": false}}`,
+			want: map[string]map[string]bool{
+				"locales": {"include_path": false},
+			},
+		},
+		{
+			name:    "invalid syntax",
+			raw:     `{"locales": `,
+			wantErr: "invalid root_flag_overrides",
+		},
+		{
+			name:    "root escaping repo is rejected",
+			raw:     `{"../outside": {"include_path": false}}`,
+			wantErr: "invalid root_flag_overrides root",
+		},
+		{
+			name:    "duplicate normalized roots are rejected",
+			raw:     `{"locales": {"include_path": false}, "./locales": {"include_path": true}}`,
+			wantErr: "configured more than once",
+		},
+		{
+			name:    "flags must be a mapping",
+			raw:     `{"locales": true}`,
+			wantErr: "expected a mapping of flags",
+		},
+		{
+			name:    "unsupported flag is rejected",
+			raw:     `{"locales": {"replace_modified": false}}`,
+			wantErr: `unsupported flag "replace_modified"`,
+		},
+		{
+			name:    "non-bool flag value is rejected",
+			raw:     `{"locales": {"include_path": "no"}}`,
+			wantErr: "must be true or false",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRootFlagOverrides(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %q", tt.wantErr, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d overrides, got %d: %#v", len(tt.want), len(got), got)
+			}
+			for _, o := range got {
+				wantFlags, ok := tt.want[o.Root]
+				if !ok {
+					t.Fatalf("unexpected root %q", o.Root)
+				}
+				if len(o.Flags) != len(wantFlags) {
+					t.Fatalf("root %q: expected flags %#v, got %#v", o.Root, wantFlags, o.Flags)
+				}
+				for k, v := range wantFlags {
+					if o.Flags[k] != v {
+						t.Fatalf("root %q: flag %q expected %v, got %v", o.Root, k, v, o.Flags[k])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMatchRootFlagOverride(t *testing.T) {
+	overrides := []rootFlagOverride{
+		{Root: "packages", Flags: map[string]bool{"include_path": true}},
+		{Root: "packages/shared", Flags: map[string]bool{"distinguish_by_file": false}},
+		{Root: "apps/web", Flags: map[string]bool{"distinguish_by_file": true}},
+	}
+
+	tests := []struct {
+		name     string
+		filePath string
+		wantRoot string
+		wantOK   bool
+	}{
+		{name: "most specific root wins", filePath: "packages/shared/locales/en.json", wantRoot: "packages/shared", wantOK: true},
+		{name: "parent root matches", filePath: "packages/app/en.json", wantRoot: "packages", wantOK: true},
+		{name: "leading ./ is ignored", filePath: "./apps/web/en.json", wantRoot: "apps/web", wantOK: true},
+		{name: "sibling prefix does not match", filePath: "apps/website/en.json", wantOK: false},
+		{name: "unrelated path", filePath: "locales/en.json", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := matchRootFlagOverride(overrides, tt.filePath)
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tt.wantOK, ok)
+			}
+			if ok && got.Root != tt.wantRoot {
+				t.Fatalf("expected root %q, got %q", tt.wantRoot, got.Root)
+			}
+		})
+	}
+
+	t.Run("repo root matches everything", func(t *testing.T) {
+		got, ok := matchRootFlagOverride([]rootFlagOverride{{Root: "."}}, "any/where/en.json")
+		if !ok || got.Root != "." {
+			t.Fatalf("expected repo root match, got %#v ok=%v", got, ok)
+		}
+	})
+}
+
+func TestApplyRootFlagOverrides(t *testing.T) {
+	t.Run("overrides defaults for matching root", func(t *testing.T) {
+		params := upload.UploadParams{"include_path": true, "distinguish_by_file": true}
+		cfg := UploadConfig{
+			FilePath:          "packages/shared/en.json",
+			RootFlagOverrides: "packages/shared:\n  distinguish_by_file: false\n",
+		}
+
+		if err := applyRootFlagOverrides(params, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if params["distinguish_by_file"] != false {
+			t.Fatalf("expected distinguish_by_file=false, got %#v", params["distinguish_by_file"])
+		}
+		if params["include_path"] != true {
+			t.Fatalf("expected include_path untouched, got %#v", params["include_path"])
+		}
+	})
+
+	t.Run("non-matching root leaves params untouched", func(t *testing.T) {
+		params := upload.UploadParams{"include_path": true}
+		cfg := UploadConfig{
+			FilePath:          "apps/web/en.json",
+			RootFlagOverrides: `{"packages/shared": {"include_path": false}}`,
+		}
+
+		if err := applyRootFlagOverrides(params, cfg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if params["include_path"] != true {
+			t.Fatalf("expected include_path=true, got %#v", params["include_path"])
+		}
+	})
+
+	t.Run("invalid overrides return error", func(t *testing.T) {
+		err := applyRootFlagOverrides(upload.UploadParams{}, UploadConfig{RootFlagOverrides: "{"})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,395 @@
+package lokaliseupload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+// Transforms applied to JSON files before upload.
+const (
+	transformStripComments = "strip_comments"
+	transformFlatten       = "flatten"
+	transformUnflatten     = "unflatten"
+	transformSortKeys      = "sort_keys"
+)
+
+var knownTransforms = []string{transformStripComments, transformFlatten, transformUnflatten, transformSortKeys}
+
+// transformExts lists the extensions of the files transforms apply to.
+var transformExts = []string{".json", ".jsonc"}
+
+// rootTransforms holds the transforms configured for a single translations root.
+type rootTransforms struct {
+	Root       string
+	Transforms []string
+}
+
+// parseTransforms parses TRANSFORMS (JSON object or YAML mapping) in the form
+// "<root>: [transform, ...]"; a comma-separated string is accepted as well.
+func parseTransforms(raw string) ([]rootTransforms, error) {
+	obj, err := envconf.ParseMap(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transforms (must be JSON object or YAML mapping): %w", err)
+	}
+
+	seen := make(map[string]struct{}, len(obj))
+	out := make([]rootTransforms, 0, len(obj))
+	for rawRoot, rawList := range obj {
+		root, err := pathnorm.EnsureRepoRelativePath(rawRoot)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transforms root %q: %w", rawRoot, err)
+		}
+		root = filepath.ToSlash(root)
+		if _, dup := seen[root]; dup {
+			return nil, fmt.Errorf("invalid transforms: root %q is configured more than once", root)
+		}
+		seen[root] = struct{}{}
+
+		var names []string
+		switch v := rawList.(type) {
+		case string:
+			names = strings.Split(v, ",")
+		case []any:
+			for _, item := range v {
+				name, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid transforms for %q: expected a list of names", rawRoot)
+				}
+				names = append(names, name)
+			}
+		default:
+			return nil, fmt.Errorf("invalid transforms for %q: expected a list of names", rawRoot)
+		}
+
+		var transforms []string
+		for _, name := range names {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if !slices.Contains(knownTransforms, name) {
+				return nil, fmt.Errorf("invalid transforms for %q: unknown transform %q (expected one of %s)", rawRoot, name, strings.Join(knownTransforms, ", "))
+			}
+			transforms = append(transforms, name)
+		}
+		if slices.Contains(transforms, transformFlatten) && slices.Contains(transforms, transformUnflatten) {
+			return nil, fmt.Errorf("invalid transforms for %q: flatten and unflatten can't be combined", rawRoot)
+		}
+
+		out = append(out, rootTransforms{Root: root, Transforms: transforms})
+	}
+
+	return out, nil
+}
+
+// applyTransformsConfig sets cfg.Transforms to the transforms configured for
+// the file's root. When several roots match, the most specific one wins.
+func applyTransformsConfig(cfg *UploadConfig, raw string) error {
+	configured, err := parseTransforms(raw)
+	if err != nil {
+		return err
+	}
+
+	path := pathnorm.RepoFilePath(cfg.FilePath)
+	best := -1
+	for i, rt := range configured {
+		if pathnorm.WithinRoot(path, rt.Root) && (best < 0 || len(rt.Root) > len(configured[best].Root)) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		cfg.Transforms = configured[best].Transforms
+	}
+	return nil
+}
+
+// applyTransforms runs cfg.Transforms on the contents to upload, in order,
+// and returns the result. It reports whether keys may have moved to other
+// lines; stripping comments keeps every key on its line.
+func applyTransforms(cfg UploadConfig, data []byte) ([]byte, bool, error) {
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	stripOnly := true
+	for _, name := range cfg.Transforms {
+		if name != transformStripComments {
+			stripOnly = false
+		}
+	}
+	if slices.Contains(cfg.Transforms, transformStripComments) {
+		data = stripJSONComments(data)
+	}
+	if stripOnly {
+		return data, false, nil
+	}
+
+	obj, err := decodeOrderedObject(data)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, name := range cfg.Transforms {
+		switch name {
+		case transformFlatten:
+			obj, err = flattenObject(obj)
+		case transformUnflatten:
+			obj, err = unflattenObject(obj)
+		case transformSortKeys:
+			sortObject(obj)
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	out, err := obj.encode()
+	return out, true, err
+}
+
+// transformsApply reports whether the file is one transforms can rewrite.
+func transformsApply(cfg UploadConfig) bool {
+	return len(cfg.Transforms) > 0 && slices.Contains(transformExts, strings.ToLower(filepath.Ext(cfg.FilePath)))
+}
+
+// stripJSONComments blanks out // and /* */ comments and trailing commas, as
+// allowed by JSONC, outside strings. Line breaks are kept, so every value
+// stays on its line.
+func stripJSONComments(data []byte) []byte {
+	out := bytes.Clone(data)
+	blank := func(from, to int) {
+		for i := from; i < to; i++ {
+			if out[i] != '\n' && out[i] != '\r' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	lastComma := -1 // a comma that is trailing if the next token closes a container
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case c == '"':
+			lastComma = -1
+			for i++; i < len(out) && out[i] != '"'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			end := bytes.IndexByte(out[i:], '\n')
+			if end < 0 {
+				end = len(out) - i
+			}
+			blank(i, i+end)
+			i += end - 1
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := bytes.Index(out[i+2:], []byte("*/"))
+			if end < 0 {
+				blank(i, len(out))
+				return out
+			}
+			blank(i, i+2+end+2)
+			i += 2 + end + 1
+		case c == ',':
+			lastComma = i
+		case c == '}' || c == ']':
+			if lastComma >= 0 {
+				out[lastComma] = ' '
+			}
+			lastComma = -1
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		default:
+			lastComma = -1
+		}
+	}
+	return out
+}
+
+// orderedField is a member of a JSON object: Value is an orderedObject for
+// nested objects and the raw JSON of any other value.
+type orderedField struct {
+	Key   string
+	Value any
+}
+
+// orderedObject is a JSON object that keeps the order of its members.
+type orderedObject []orderedField
+
+// decodeOrderedObject decodes a JSON object, keeping the order of its members.
+func decodeOrderedObject(data []byte) (orderedObject, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("invalid JSON: expected an object at the top level")
+	}
+
+	var obj orderedObject
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		key := tok.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		var value any = raw
+		if bytes.HasPrefix(raw, []byte("{")) {
+			if value, err = decodeOrderedObject(raw); err != nil {
+				return nil, err
+			}
+		}
+		obj = append(obj, orderedField{Key: key, Value: value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return obj, nil
+}
+
+// encode renders obj as indented JSON with a trailing newline.
+func (obj orderedObject) encode() ([]byte, error) {
+	var compact bytes.Buffer
+	if err := obj.writeTo(&compact); err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+func (obj orderedObject) writeTo(buf *bytes.Buffer) error {
+	buf.WriteByte('{')
+	for i, f := range obj {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(f.Key); err != nil {
+			return err
+		}
+		buf.Truncate(buf.Len() - 1) // Encode appends a newline
+		buf.WriteByte(':')
+		switch v := f.Value.(type) {
+		case orderedObject:
+			if err := v.writeTo(buf); err != nil {
+				return err
+			}
+		case json.RawMessage:
+			buf.Write(v)
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// flattenObject joins nested keys with dots: {"a": {"b": "x"}} becomes
+// {"a.b": "x"}. Empty objects are dropped.
+func flattenObject(obj orderedObject) (orderedObject, error) {
+	var out orderedObject
+	seen := make(map[string]struct{})
+	var walk func(prefix string, obj orderedObject) error
+	walk = func(prefix string, obj orderedObject) error {
+		for _, f := range obj {
+			key := f.Key
+			if prefix != "" {
+				key = prefix + "." + f.Key
+			}
+			if nested, ok := f.Value.(orderedObject); ok {
+				if err := walk(key, nested); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, dup := seen[key]; dup {
+				return fmt.Errorf("key %q appears more than once", key)
+			}
+			seen[key] = struct{}{}
+			out = append(out, orderedField{Key: key, Value: f.Value})
+		}
+		return nil
+	}
+	return out, walk("", obj)
+}
+
+// unflattenObject splits keys on dots into nested objects: {"a.b": "x"}
+// becomes {"a": {"b": "x"}}. Objects appear where their first key did.
+func unflattenObject(obj orderedObject) (orderedObject, error) {
+	var out orderedObject
+	for _, f := range obj {
+		value := f.Value
+		if nested, ok := value.(orderedObject); ok {
+			var err error
+			if value, err = unflattenObject(nested); err != nil {
+				return nil, err
+			}
+		}
+		if err := out.insert(strings.Split(f.Key, "."), value, f.Key); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// insert sets the value at path, creating or merging nested objects.
+func (obj *orderedObject) insert(path []string, value any, fullKey string) error {
+	for i := range *obj {
+		f := &(*obj)[i]
+		if f.Key != path[0] {
+			continue
+		}
+		existing, isObj := f.Value.(orderedObject)
+		incoming, incomingObj := value.(orderedObject)
+		switch {
+		case len(path) > 1 && isObj:
+			if err := existing.insert(path[1:], value, fullKey); err != nil {
+				return err
+			}
+			f.Value = existing
+			return nil
+		case len(path) == 1 && isObj && incomingObj:
+			for _, g := range incoming {
+				if err := existing.insert([]string{g.Key}, g.Value, fullKey+"."+g.Key); err != nil {
+					return err
+				}
+			}
+			f.Value = existing
+			return nil
+		default:
+			return fmt.Errorf("key %q conflicts with another key", fullKey)
+		}
+	}
+
+	if len(path) > 1 {
+		var nested orderedObject
+		if err := nested.insert(path[1:], value, fullKey); err != nil {
+			return err
+		}
+		value = nested
+	}
+	*obj = append(*obj, orderedField{Key: path[0], Value: value})
+	return nil
+}
+
+// sortObject sorts the members of obj and of every nested object by key.
+func sortObject(obj orderedObject) {
+	sort.SliceStable(obj, func(i, j int) bool { return obj[i].Key < obj[j].Key })
+	for _, f := range obj {
+		if nested, ok := f.Value.(orderedObject); ok {
+			sortObject(nested)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package lokaliseupload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTransforms(t *testing.T) {
+	t.Run("lists and comma-separated strings", func(t *testing.T) {
+		got, err := parseTransforms(`{"./locales/": ["Strip_Comments", "sort_keys"], "apps/web": "flatten, sort_keys"}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string][]string{
+			"locales":  {transformStripComments, transformSortKeys},
+			"apps/web": {transformFlatten, transformSortKeys},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("expected %d roots, got %#v", len(want), got)
+		}
+		for _, rt := range got {
+			if !reflect.DeepEqual(rt.Transforms, want[rt.Root]) {
+				t.Fatalf("root %q: expected %v, got %v", rt.Root, want[rt.Root], rt.Transforms)
+			}
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if got, err := parseTransforms(""); err != nil || len(got) != 0 {
+			t.Fatalf("expected no transforms, got %#v (%v)", got, err)
+		}
+	})
+
+	for name, raw := range map[string]string{
+		"unknown transform":     "locales: [minify]",
+		"not a list":            "locales: {flatten: true}",
+		"flatten and unflatten": "locales: [flatten, unflatten]",
+		"duplicate root":        `{"locales": "flatten", "./locales": "sort_keys"}`,
+		"root outside the repo": "../locales: [flatten]",
+		"not a mapping":         "- flatten",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseTransforms(raw); err == nil || !strings.Contains(err.Error(), "invalid transforms") {
+				t.Fatalf("expected invalid transforms error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestStripJSONComments(t *testing.T) {
+	in := "{\n  // header\n  \"url\": \"http://example.com\", /* inline */\n  \"list\": [1, 2,],\n  \"quote\": \"\\\"// not a comment\",\n  \"last\": \"x\", // trailing\n}\n"
+	got := string(stripJSONComments([]byte(in)))
+
+	if strings.Count(got, "\n") != strings.Count(in, "\n") {
+		t.Fatalf("expected line breaks to be kept, got %q", got)
+	}
+	for _, gone := range []string{"header", "inline", "trailing", "2,]", `"x",`} {
+		if strings.Contains(got, gone) {
+			t.Fatalf("expected %q to be stripped, got %q", gone, got)
+		}
+	}
+	for _, kept := range []string{`"http://example.com",`, `"\"// not a comment",`} {
+		if !strings.Contains(got, kept) {
+			t.Fatalf("expected %q to be kept, got %q", kept, got)
+		}
+	}
+	if _, err := decodeOrderedObject([]byte(got)); err != nil {
+		t.Fatalf("expected valid JSON, got %v for %q", err, got)
+	}
+}
+
+func TestApplyTransforms(t *testing.T) {
+	tests := []struct {
+		name          string
+		transforms    []string
+		in            string
+		want          string
+		wantReordered bool
+		wantErr       string
+	}{
+		{
+			name:       "strip comments keeps lines",
+			transforms: []string{transformStripComments},
+			in:         "{\n  \"a\": \"x\", // note\n}\n",
+			want:       "{\n  \"a\": \"x\"         \n}\n",
+		},
+		{
+			name:          "flatten",
+			transforms:    []string{transformFlatten},
+			in:            `{"menu": {"open": "Open", "sub": {"close": "<Close>"}}, "empty": {}, "n": 1}`,
+			want:          "{\n  \"menu.open\": \"Open\",\n  \"menu.sub.close\": \"<Close>\",\n  \"n\": 1\n}\n",
+			wantReordered: true,
+		},
+		{
+			name:          "unflatten merges objects where they first appear",
+			transforms:    []string{transformUnflatten},
+			in:            `{"menu.open": "Open", "title": "T", "menu.close": "Close", "menu": {"sub.item": "I"}}`,
+			want:          "{\n  \"menu\": {\n    \"open\": \"Open\",\n    \"close\": \"Close\",\n    \"sub\": {\n      \"item\": \"I\"\n    }\n  },\n  \"title\": \"T\"\n}\n",
+			wantReordered: true,
+		},
+		{
+			name:          "comments are stripped before sorting",
+			transforms:    []string{transformStripComments, transformSortKeys},
+			in:            "\xEF\xBB\xBF{\n  // c\n  \"b\": {\"y\": [2, 1], \"x\": null},\n  \"a\": true,\n}",
+			want:          "{\n  \"a\": true,\n  \"b\": {\n    \"x\": null,\n    \"y\": [\n      2,\n      1\n    ]\n  }\n}\n",
+			wantReordered: true,
+		},
+		{
+			name:       "flatten reports clashing keys",
+			transforms: []string{transformFlatten},
+			in:         `{"a.b": "x", "a": {"b": "y"}}`,
+			wantErr:    `flatten: key "a.b" appears more than once`,
+		},
+		{
+			name:       "unflatten reports a key that is also an object",
+			transforms: []string{transformUnflatten},
+			in:         `{"a": "x", "a.b": "y"}`,
+			wantErr:    `unflatten: key "a.b" conflicts with another key`,
+		},
+		{
+			name:       "comments without strip_comments",
+			transforms: []string{transformSortKeys},
+			in:         "{\n  // c\n  \"a\": \"x\"\n}",
+			wantErr:    "invalid JSON",
+		},
+		{
+			name:       "top level array",
+			transforms: []string{transformSortKeys},
+			in:         `["a"]`,
+			wantErr:    "expected an object at the top level",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reordered, err := applyTransforms(UploadConfig{Transforms: tt.transforms}, []byte(tt.in))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+			if reordered != tt.wantReordered {
+				t.Fatalf("expected reordered=%v, got %v", tt.wantReordered, reordered)
+			}
+		})
+	}
+}
+
+func TestTransformsApply(t *testing.T) {
+	tests := []struct {
+		path       string
+		transforms []string
+		want       bool
+	}{
+		{"en.json", []string{transformFlatten}, true},
+		{"en.JSONC", []string{transformStripComments}, true},
+		{"en.yml", []string{transformFlatten}, false},
+		{"en.json", nil, false},
+	}
+	for _, tt := range tests {
+		if got := transformsApply(UploadConfig{FilePath: tt.path, Transforms: tt.transforms}); got != tt.want {
+			t.Fatalf("%s %v: expected %v, got %v", tt.path, tt.transforms, tt.want, got)
+		}
+	}
+}
+
+func TestUploadFile_Transforms(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "en.json")
+	original := "{\n  // header\n  \"menu\": {\"open\": \"Open {name\"},\n}\n"
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	base := UploadConfig{FilePath: path, ProjectID: "111.abc", Token: "tok", LangISO: "en", SkipTagging: true}
+
+	t.Run("uploads the transformed copy under the original name", func(t *testing.T) {
+		uploader := &readingUploader{}
+		cfg := base
+		cfg.Transforms = []string{transformStripComments, transformFlatten}
+
+		if err := uploadFile(context.Background(), cfg, &fakeUploadFactory{uploader: uploader}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if uploader.contents != "{\n  \"menu.open\": \"Open {name\"\n}\n" {
+			t.Fatalf("unexpected contents %q", uploader.contents)
+		}
+		if uploader.params["filename"] != path {
+			t.Fatalf("expected filename %q, got %v", path, uploader.params["filename"])
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != original {
+			t.Fatalf("expected the original file to be left as is, got %q (%v)", data, err)
+		}
+		if matches, _ := filepath.Glob(filepath.Join(os.TempDir(), "lokalise-upload-*.json")); len(matches) != 0 {
+			t.Fatalf("expected the copy to be removed, found %v", matches)
+		}
+	})
+
+	t.Run("checks read the transformed copy without lines", func(t *testing.T) {
+		buf := captureLogs(t)
+		cfg := base
+		cfg.Transforms = []string{transformStripComments, transformFlatten}
+		cfg.PlaceholderCheck = checkWarn
+
+		if err := uploadFile(context.Background(), cfg, &fakeUploadFactory{uploader: &readingUploader{}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(buf.String(), path+": ") || strings.Contains(buf.String(), path+":3") {
+			t.Fatalf("expected a file-level annotation, got %q", buf.String())
+		}
+	})
+
+	t.Run("failing transform stops the upload", func(t *testing.T) {
+		reportDir := t.TempDir()
+		ff := &recordingUploadFactory{}
+		cfg := base
+		cfg.Transforms = []string{transformFlatten}
+		cfg.ReportDir = reportDir
+
+		err := uploadFile(context.Background(), cfg, ff)
+		if err == nil || !strings.Contains(err.Error(), "cannot transform") {
+			t.Fatalf("expected transform error, got %v", err)
+		}
+		if len(ff.projects) != 0 {
+			t.Fatalf("expected no uploads, got %v", ff.projects)
+		}
+		if results := readUploadResults(t, reportDir); len(results) != 1 || results[0].Status != resultStatusFailed {
+			t.Fatalf("expected a failed result, got %#v", results)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package lokaliseupload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bodrovis/lokex/v2/client/upload"
+
+	"lokalise-push-action/internal/apiclient"
+)
+
+// Uploader abstracts the upload client for testability.
+type Uploader interface {
+	Upload(ctx context.Context, params upload.UploadParams, srcPath string, poll bool) (string, error)
+}
+
+// ClientFactory allows injecting a fake client in tests.
+type ClientFactory interface {
+	NewUploader(cfg UploadConfig) (Uploader, error)
+}
+
+type LokaliseFactory struct{}
+
+// NewUploader wires lokex client with our retry, timeout, and polling settings.
+func (f *LokaliseFactory) NewUploader(cfg UploadConfig) (Uploader, error) {
+	lokaliseClient, err := apiclient.New(cfg.Token, cfg.ProjectID, apiclient.Settings{
+		MaxRetries:      cfg.MaxRetries,
+		HTTPTimeout:     cfg.HTTPTimeout,
+		InitialBackoff:  cfg.InitialSleepTime,
+		MaxBackoff:      cfg.MaxSleepTime,
+		PollInitialWait: cfg.PollInitialWait,
+		PollMaxWait:     cfg.PollMaxWait,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return upload.NewUploader(lokaliseClient), nil
+}
+
+// uploadFile builds upload params, creates a client, and performs the upload.
+// Polling is enabled unless SkipPolling is true.
+// When mirror projects are configured, the file is pushed to each of them as well.
+// Files in a language listed in SKIP_LANGS, or with a SkipReason, are skipped without error.
+// The pre-upload hook runs first, then the contents are normalized and
+// transformed and the pre-upload checks run; each of them may stop the
+// upload. The post-upload hook runs last, whatever the outcome.
+func uploadFile(ctx context.Context, cfg UploadConfig, factory ClientFactory) error {
+	if cfg.SkipReason != "" {
+		logs.Infof("Skipping file %q: %s", cfg.FilePath, cfg.SkipReason)
+		return nil
+	}
+	if isLangSkipped(cfg) {
+		logs.Infof("Skipping file %q: language %q is listed in skip_langs", cfg.FilePath, cfg.LangISO)
+		return nil
+	}
+
+	if err := runPreUploadHook(ctx, cfg); err != nil {
+		reportStoppedUpload(cfg, err)
+		return errors.Join(err, runPostUploadHook(ctx, cfg, err))
+	}
+
+	err := uploadPreparedFile(ctx, cfg, factory)
+	return errors.Join(err, runPostUploadHook(ctx, cfg, err))
+}
+
+// uploadPreparedFile prepares the contents to upload and uploads them to
+// every project, once the pre-upload hook ran.
+func uploadPreparedFile(ctx context.Context, cfg UploadConfig, factory ClientFactory) error {
+	cfg, cleanup, err := prepareUploadSource(cfg)
+	defer cleanup()
+	if err != nil {
+		reportStoppedUpload(cfg, err)
+		return err
+	}
+
+	if err := runChecks(cfg); err != nil {
+		return err
+	}
+
+	params, err := buildUploadParams(cfg)
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.MirrorProjectIDs) == 0 {
+		return uploadToProject(ctx, cfg, params, factory)
+	}
+
+	return uploadToAllProjects(ctx, cfg, params, factory)
+}
+
+// prepareUploadSource normalizes the encoding of the file and applies its
+// transforms. When that changes the contents, they are written to a
+// temporary copy, set as cfg.SourcePath, and uploaded under the original
+// name; the file in the repository is left as is. cleanup removes the copy.
+func prepareUploadSource(cfg UploadConfig) (UploadConfig, func(), error) {
+	cleanup := func() {}
+	if !encodingEnabled(cfg) && !transformsApply(cfg) {
+		return cfg, cleanup, nil
+	}
+
+	data, err := os.ReadFile(cfg.FilePath)
+	if err != nil {
+		return cfg, cleanup, fmt.Errorf("cannot read %q: %w", cfg.FilePath, err)
+	}
+
+	changed := false
+	if encodingEnabled(cfg) {
+		converted, err := normalizeEncoding(cfg, data)
+		if err != nil {
+			return cfg, cleanup, err
+		}
+		if converted != nil {
+			data, changed = converted, true
+		}
+	}
+	if transformsApply(cfg) {
+		logs.Infof("Applying transforms to %q: %s", cfg.FilePath, strings.Join(cfg.Transforms, ", "))
+		data, cfg.SourceReordered, err = applyTransforms(cfg, data)
+		if err != nil {
+			return cfg, cleanup, fmt.Errorf("cannot transform %q: %w", cfg.FilePath, err)
+		}
+		changed = true
+	}
+	if !changed {
+		return cfg, cleanup, nil
+	}
+
+	tmp, err := os.CreateTemp("", "lokalise-upload-*"+filepath.Ext(cfg.FilePath))
+	if err != nil {
+		return cfg, cleanup, fmt.Errorf("cannot create a copy of %q: %w", cfg.FilePath, err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return cfg, cleanup, fmt.Errorf("cannot write a copy of %q: %w", cfg.FilePath, err)
+	}
+
+	cfg.SourcePath = tmp.Name()
+	return cfg, func() { os.Remove(tmp.Name()) }, nil
+}
+
+// uploadToProject uploads the file to cfg.ProjectID and records the result in REPORT_DIR.
+// With a push state cache, a file already pushed with the same contents and
+// params is skipped, and a successful upload is recorded in the cache.
+func uploadToProject(ctx context.Context, cfg UploadConfig, params upload.UploadParams, factory ClientFactory) error {
+	fingerprint := pushFingerprint(cfg, params)
+	if !cfg.ForceUpload && pushedUnchanged(cfg, fingerprint) {
+		logs.Infof("Skipping file %q: unchanged since the last push to project %s", cfg.FilePath, cfg.ProjectID)
+		return nil
+	}
+
+	uploader, err := factory.NewUploader(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot create Lokalise API client: %w", err)
+	}
+
+	logs.Infof("Starting to upload file %q", cfg.FilePath)
+
+	startedAt := time.Now()
+	processID, err := uploader.Upload(ctx, params, uploadSourcePath(cfg), !cfg.SkipPolling)
+	reportUploadResult(cfg, startedAt, processID, err)
+	if err != nil {
+		return fmt.Errorf("failed to upload file %q: %w", cfg.FilePath, err)
+	}
+
+	recordPush(cfg, fingerprint, startedAt, processID)
+	return nil
+}
+
+// uploadToAllProjects uploads the file to the primary project and every mirror.
+// A failure in one project does not stop uploads to the others; each project's
+// result is reported and all failures are returned together.
+func uploadToAllProjects(ctx context.Context, cfg UploadConfig, params upload.UploadParams, factory ClientFactory) error {
+	projectIDs := append([]string{cfg.ProjectID}, cfg.MirrorProjectIDs...)
+
+	var errs []error
+	for _, projectID := range projectIDs {
+		projectCfg := cfg
+		projectCfg.ProjectID = projectID
+
+		if err := uploadToProject(ctx, projectCfg, params, factory); err != nil {
+			logs.Warnf("Project %s: upload of %q failed", projectID, cfg.FilePath)
+			errs = append(errs, fmt.Errorf("project %s: %w", projectID, err))
+			continue
+		}
+
+		logs.Infof("Project %s: uploaded %q", projectID, cfg.FilePath)
+	}
+
+	return errors.Join(errs...)
+}
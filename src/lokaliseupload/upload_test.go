@@ -1,8 +1,9 @@
-package main
+package lokaliseupload
 
 import (
 	"context"
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -156,6 +157,68 @@ func TestUploadFile(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "pot template mapped to po reads from original path",
+			cfg: UploadConfig{
+				FilePath:    "locales/messages.pot",
+				ProjectID:   "proj_123",
+				Token:       "tok_abc",
+				LangISO:     "en",
+				SkipTagging: true,
+				FileFormat:  "po",
+				MapPotToPo:  true,
+			},
+			factory: &fakeUploadFactory{
+				uploader: &fakeUploader{returnPID: "upl_777"},
+			},
+			assert: func(t *testing.T, fu *fakeUploader, ff *fakeUploadFactory) {
+				t.Helper()
+				if fu.gotParams["filename"] != "locales/messages.po" {
+					t.Fatalf("expected mapped filename, got %#v", fu.gotParams["filename"])
+				}
+				if fu.gotSrcPath != "locales/messages.pot" {
+					t.Fatalf("expected srcPath to point at the .pot file, got %q", fu.gotSrcPath)
+				}
+			},
+		},
+		{
+			name: "skipped language is not uploaded",
+			cfg: UploadConfig{
+				FilePath:  "locales/de/common.json",
+				ProjectID: "proj_123",
+				Token:     "tok_abc",
+				LangISO:   "de",
+				SkipLangs: []string{"de", "pt_BR"},
+			},
+			factory: &fakeUploadFactory{
+				uploader: &fakeUploader{},
+			},
+			assert: func(t *testing.T, fu *fakeUploader, ff *fakeUploadFactory) {
+				t.Helper()
+				if ff.called || fu.called {
+					t.Fatalf("upload must be skipped for languages in SkipLangs")
+				}
+			},
+		},
+		{
+			name: "file with a skip reason is skipped",
+			cfg: UploadConfig{
+				FilePath:   "/tmp/readme.properties",
+				ProjectID:  "proj_123",
+				Token:      "tok_abc",
+				LangISO:    "en",
+				SkipReason: "file name does not match name_regex",
+			},
+			factory: &fakeUploadFactory{
+				uploader: &fakeUploader{},
+			},
+			assert: func(t *testing.T, fu *fakeUploader, ff *fakeUploadFactory) {
+				t.Helper()
+				if ff.called || fu.called {
+					t.Fatalf("upload must be skipped for files with a SkipReason")
+				}
+			},
+		},
 		{
 			name: "upload error is wrapped",
 			cfg: UploadConfig{
@@ -219,6 +282,54 @@ func TestUploadFile(t *testing.T) {
 	}
 }
 
+func TestUploadFile_MirrorProjects(t *testing.T) {
+	t.Run("uploads to every project", func(t *testing.T) {
+		ff := &recordingUploadFactory{}
+		cfg := UploadConfig{
+			FilePath:         "/tmp/en.json",
+			ProjectID:        "111.abc",
+			MirrorProjectIDs: []string{"222.def", "333.ghi"},
+			Token:            "tok",
+			LangISO:          "en",
+			SkipTagging:      true,
+		}
+
+		if err := uploadFile(context.Background(), cfg, ff); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{"111.abc", "222.def", "333.ghi"}
+		if !reflect.DeepEqual(ff.projects, want) {
+			t.Fatalf("expected uploads to %v, got %v", want, ff.projects)
+		}
+	})
+
+	t.Run("failure in one project does not stop others", func(t *testing.T) {
+		ff := &recordingUploadFactory{failProject: "222.def"}
+		cfg := UploadConfig{
+			FilePath:         "/tmp/en.json",
+			ProjectID:        "111.abc",
+			MirrorProjectIDs: []string{"222.def", "333.ghi"},
+			Token:            "tok",
+			LangISO:          "en",
+			SkipTagging:      true,
+		}
+
+		err := uploadFile(context.Background(), cfg, ff)
+		if err == nil || !strings.Contains(err.Error(), "project 222.def") {
+			t.Fatalf("expected error mentioning failed project, got %v", err)
+		}
+		if strings.Contains(err.Error(), "project 111.abc") || strings.Contains(err.Error(), "project 333.ghi") {
+			t.Fatalf("error should only mention the failed project, got %v", err)
+		}
+
+		want := []string{"111.abc", "222.def", "333.ghi"}
+		if !reflect.DeepEqual(ff.projects, want) {
+			t.Fatalf("expected uploads to %v, got %v", want, ff.projects)
+		}
+	})
+}
+
 func TestUploadFile_PassesContextToUploader(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -262,6 +373,20 @@ func (f *fakeUploader) Upload(ctx context.Context, params upload.UploadParams, s
 	return f.returnPID, f.returnErr
 }
 
+// recordingUploadFactory records the project of every uploader it creates.
+type recordingUploadFactory struct {
+	projects    []string
+	failProject string
+}
+
+func (f *recordingUploadFactory) NewUploader(cfg UploadConfig) (Uploader, error) {
+	f.projects = append(f.projects, cfg.ProjectID)
+	if cfg.ProjectID == f.failProject {
+		return &fakeUploader{returnErr: errors.New("boom")}, nil
+	}
+	return &fakeUploader{returnPID: "upl_" + cfg.ProjectID}, nil
+}
+
 type fakeUploadFactory struct {
 	wantErr error
 	called  bool
@@ -1,23 +1,21 @@
-package main
+package lokaliseupload
 
 import (
 	"fmt"
 	"os"
+
+	"lokalise-push-action/internal/envconf"
 )
 
 // validate performs input sanity checks before any network calls.
-// It fails fast with actionable messages for CI logs.
+// Every failed check is reported, with actionable messages for CI logs.
 func validate(cfg UploadConfig) error {
-	if err := validateFile(cfg.FilePath); err != nil {
-		return err
-	}
-	if err := validateRequiredFields(cfg); err != nil {
-		return err
-	}
-	if err := validateTaggingInputs(cfg); err != nil {
-		return err
-	}
-	return nil
+	return envconf.Join([]error{
+		validateFile(cfg.FilePath),
+		validateRequiredFields(cfg),
+		validateTaggingInputs(cfg),
+		validateFormatPreset(cfg),
+	})
 }
 
 // validateRequiredFields checks the minimum required Lokalise settings.
@@ -1,4 +1,4 @@
-package main
+package lokaliseupload
 
 import (
 	"os"
@@ -141,6 +141,19 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: "GitHub reference name (GITHUB_HEAD_REF or GITHUB_REF_NAME) is required when tagging is enabled",
 		},
+		{
+			name: "unknown format preset returns error",
+			cfg: UploadConfig{
+				FilePath:        validFile,
+				ProjectID:       "p",
+				Token:           "t",
+				LangISO:         "en",
+				GitHubRefName:   "ref",
+				FileFormat:      "yaml",
+				UseFormatPreset: true,
+			},
+			wantErr: `no parameter preset available for file format "yaml"`,
+		},
 		{
 			name: "missing file path returns error",
 			cfg: UploadConfig{
@@ -0,0 +1,316 @@
+package postpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bodrovis/lokex/v2/client"
+
+	"lokalise-push-action/internal/apiclient"
+)
+
+// LokaliseAPI abstracts the Lokalise endpoints used after a push for testability.
+type LokaliseAPI interface {
+	FetchProcess(ctx context.Context, processID string) (processResponse, error)
+	FetchProject(ctx context.Context) (projectResponse, error)
+	ListKeysByTag(ctx context.Context, tag string) ([]lokaliseKey, error)
+	CreateTask(ctx context.Context, req taskRequest) (taskResponse, error)
+	AddKeyComment(ctx context.Context, keyID int64, comment string) error
+	ListKeysByName(ctx context.Context, names []string) ([]lokaliseKey, error)
+	UpdateKeys(ctx context.Context, updates []keyUpdate) error
+	ListScreenshots(ctx context.Context) ([]lokaliseScreenshot, error)
+	CreateScreenshot(ctx context.Context, s newScreenshot) error
+	UpdateScreenshotKeys(ctx context.Context, screenshotID int64, keyIDs []int64) error
+	DeleteScreenshot(ctx context.Context, screenshotID int64) error
+}
+
+// lokaliseKey is the subset of a key object we use.
+type lokaliseKey struct {
+	KeyID              int64    `json:"key_id"`
+	KeyName            keyName  `json:"key_name"`
+	Tags               []string `json:"tags"`
+	CreatedAtTimestamp int64    `json:"created_at_timestamp"`
+}
+
+// keyName holds per-platform key names; projects without per-platform names
+// repeat the same value for every platform.
+type keyName struct {
+	IOS     string `json:"ios"`
+	Android string `json:"android"`
+	Web     string `json:"web"`
+	Other   string `json:"other"`
+}
+
+// names returns the distinct non-empty platform names.
+func (n keyName) names() []string {
+	var out []string
+	for _, v := range []string{n.Web, n.Other, n.IOS, n.Android} {
+		if v != "" && !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// keyUpdate is one entry of a bulk key update. Nil fields are left unchanged.
+type keyUpdate struct {
+	KeyID       int64    `json:"key_id"`
+	Description *string  `json:"description,omitempty"`
+	CharLimit   *int     `json:"char_limit,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	MergeTags   bool     `json:"merge_tags,omitempty"`
+}
+
+// ClientFactory allows injecting a fake client in tests.
+type ClientFactory interface {
+	NewAPI(cfg postPushConfig, projectID string) (LokaliseAPI, error)
+}
+
+type LokaliseFactory struct{}
+
+type lokaliseAPI struct {
+	client *client.Client
+}
+
+// NewAPI wires a lokex client for projectID with our retry and timeout
+// settings, authenticated with the token configured for that project.
+func (f *LokaliseFactory) NewAPI(cfg postPushConfig, projectID string) (LokaliseAPI, error) {
+	lokaliseClient, err := apiclient.New(cfg.tokenFor(projectID), projectID, apiclient.Settings{
+		MaxRetries:     cfg.MaxRetries,
+		HTTPTimeout:    cfg.HTTPTimeout,
+		InitialBackoff: cfg.InitialSleepTime,
+		MaxBackoff:     cfg.MaxSleepTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lokaliseAPI{client: lokaliseClient}, nil
+}
+
+func (a *lokaliseAPI) FetchProcess(ctx context.Context, processID string) (processResponse, error) {
+	var resp processResponse
+	err := a.client.DoJSONWithRetry(ctx, "GET", a.projectPath("processes/"+url.PathEscape(processID)), nil, &resp)
+	return resp, err
+}
+
+func (a *lokaliseAPI) FetchProject(ctx context.Context) (projectResponse, error) {
+	var resp projectResponse
+	err := a.client.DoJSONWithRetry(ctx, "GET", a.projectPath(""), nil, &resp)
+	return resp, err
+}
+
+const (
+	keysPageLimit   = 5000 // Page size used when listing keys (API maximum).
+	keyNamesPerPage = 100  // Key names per filter_keys request, keeping URLs short.
+	keysPerUpdate   = 500  // Keys per bulk update request.
+
+	screenshotsPageLimit = 500 // Page size used when listing screenshots.
+)
+
+// ListKeysByTag returns all keys carrying tag.
+func (a *lokaliseAPI) ListKeysByTag(ctx context.Context, tag string) ([]lokaliseKey, error) {
+	return a.listKeys(ctx, url.Values{"filter_tags": {tag}})
+}
+
+// ListKeysByName returns the keys matching any of names, querying in batches.
+func (a *lokaliseAPI) ListKeysByName(ctx context.Context, names []string) ([]lokaliseKey, error) {
+	var keys []lokaliseKey
+	for batch := range slices.Chunk(names, keyNamesPerPage) {
+		found, err := a.listKeys(ctx, url.Values{"filter_keys": {strings.Join(batch, ",")}})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, found...)
+	}
+	return keys, nil
+}
+
+// UpdateKeys applies updates using the bulk update endpoint, in batches.
+func (a *lokaliseAPI) UpdateKeys(ctx context.Context, updates []keyUpdate) error {
+	for batch := range slices.Chunk(updates, keysPerUpdate) {
+		if err := a.sendJSON(ctx, "PUT", a.projectPath("keys"), map[string]any{"keys": batch}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listKeys returns all keys matching filters, following pagination
+// until a short page is returned.
+func (a *lokaliseAPI) listKeys(ctx context.Context, filters url.Values) ([]lokaliseKey, error) {
+	var keys []lokaliseKey
+
+	for page := 1; ; page++ {
+		q := url.Values{}
+		for k, v := range filters {
+			q[k] = v
+		}
+		q.Set("limit", strconv.Itoa(keysPageLimit))
+		q.Set("page", strconv.Itoa(page))
+
+		var resp struct {
+			Keys []lokaliseKey `json:"keys"`
+		}
+		if err := a.getJSONWithQuery(ctx, a.projectPath("keys"), q, &resp); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, resp.Keys...)
+		if len(resp.Keys) < keysPageLimit {
+			return keys, nil
+		}
+	}
+}
+
+func (a *lokaliseAPI) CreateTask(ctx context.Context, req taskRequest) (taskResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return taskResponse{}, fmt.Errorf("cannot encode task: %w", err)
+	}
+
+	var resp taskResponse
+	err = a.client.DoJSONWithRetry(ctx, "POST", a.projectPath("tasks"), bytes.NewReader(body), &resp)
+	return resp, err
+}
+
+// AddKeyComment posts a comment on a single key.
+func (a *lokaliseAPI) AddKeyComment(ctx context.Context, keyID int64, comment string) error {
+	return a.sendJSON(ctx, "POST", a.projectPath(fmt.Sprintf("keys/%d/comments", keyID)), map[string]any{
+		"comments": []map[string]string{{"comment": comment}},
+	})
+}
+
+// ListScreenshots returns all screenshots in the project.
+func (a *lokaliseAPI) ListScreenshots(ctx context.Context) ([]lokaliseScreenshot, error) {
+	var out []lokaliseScreenshot
+
+	for page := 1; ; page++ {
+		q := url.Values{}
+		q.Set("limit", strconv.Itoa(screenshotsPageLimit))
+		q.Set("page", strconv.Itoa(page))
+
+		var resp struct {
+			Screenshots []lokaliseScreenshot `json:"screenshots"`
+		}
+		if err := a.getJSONWithQuery(ctx, a.projectPath("screenshots"), q, &resp); err != nil {
+			return nil, err
+		}
+
+		out = append(out, resp.Screenshots...)
+		if len(resp.Screenshots) < screenshotsPageLimit {
+			return out, nil
+		}
+	}
+}
+
+func (a *lokaliseAPI) CreateScreenshot(ctx context.Context, s newScreenshot) error {
+	return a.sendJSON(ctx, "POST", a.projectPath("screenshots"), map[string]any{
+		"screenshots": []newScreenshot{s},
+	})
+}
+
+func (a *lokaliseAPI) UpdateScreenshotKeys(ctx context.Context, screenshotID int64, keyIDs []int64) error {
+	return a.sendJSON(ctx, "PUT", a.projectPath(fmt.Sprintf("screenshots/%d", screenshotID)), map[string]any{
+		"key_ids": keyIDs,
+	})
+}
+
+func (a *lokaliseAPI) DeleteScreenshot(ctx context.Context, screenshotID int64) error {
+	var resp json.RawMessage
+	return a.client.DoJSONWithRetry(ctx, "DELETE", a.projectPath(fmt.Sprintf("screenshots/%d", screenshotID)), nil, &resp)
+}
+
+// sendJSON encodes payload and sends it with the client's retry policy, discarding the response.
+func (a *lokaliseAPI) sendJSON(ctx context.Context, method, path string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("cannot encode request: %w", err)
+	}
+
+	var resp json.RawMessage
+	return a.client.DoJSONWithRetry(ctx, method, path, bytes.NewReader(body), &resp)
+}
+
+// statusError is returned by getJSONWithQuery for non-2xx responses.
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("API responded with status %d: %s", e.StatusCode, e.Body)
+}
+
+// isRetryableError retries rate limits, server errors, and network failures.
+func isRetryableError(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= 500
+	}
+	var ne net.Error
+	return errors.As(err, &ne)
+}
+
+// getJSONWithQuery performs a GET with query parameters using the client's
+// credentials and retry policy. lokex request helpers escape "?" in paths,
+// so query strings cannot be passed through DoJSONWithRetry.
+func (a *lokaliseAPI) getJSONWithQuery(ctx context.Context, path string, query url.Values, v any) error {
+	fullURL := strings.TrimSuffix(a.client.BaseURL, "/") + "/" + path + "?" + query.Encode()
+
+	return a.client.WithExpBackoff(ctx, "request", func(int) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("X-Api-Token", a.client.Token)
+		req.Header.Set("User-Agent", a.client.UserAgent)
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := a.client.HTTPClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<12))
+			return &statusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+		}
+
+		return json.NewDecoder(resp.Body).Decode(v)
+	}, isRetryableError)
+}
+
+// projectPath builds "projects/<id>[/<suffix>]" for the client's project.
+func (a *lokaliseAPI) projectPath(suffix string) string {
+	path := "projects/" + url.PathEscape(a.client.ProjectID)
+	if suffix != "" {
+		path += "/" + suffix
+	}
+	return path
+}
+
+// cachedAPI returns the client for projectID, creating and caching it on first use.
+func cachedAPI(clients map[string]LokaliseAPI, factory ClientFactory, cfg postPushConfig, projectID string) (LokaliseAPI, error) {
+	if api, ok := clients[projectID]; ok {
+		return api, nil
+	}
+
+	api, err := factory.NewAPI(cfg, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create Lokalise API client for project %s: %w", projectID, err)
+	}
+	clients[projectID] = api
+	return api, nil
+}
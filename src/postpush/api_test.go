@@ -0,0 +1,492 @@
+package postpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bodrovis/lokex/v2/client"
+)
+
+type fakeAPI struct {
+	processes    map[string]processResponse
+	project      projectResponse
+	keys         []lokaliseKey
+	err          error
+	processCalls []string
+	projectCalls int
+	tags         []string
+	tasks        []taskRequest
+	comments     map[int64]string
+	commentErr   error
+	names        []string
+	updates      []keyUpdate
+	updateErr    error
+	screenshots  []lokaliseScreenshot
+	created      []newScreenshot
+	relinked     map[int64][]int64
+	deleted      []int64
+}
+
+func (f *fakeAPI) ListScreenshots(context.Context) ([]lokaliseScreenshot, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.screenshots, nil
+}
+
+func (f *fakeAPI) CreateScreenshot(_ context.Context, s newScreenshot) error {
+	f.created = append(f.created, s)
+	return nil
+}
+
+func (f *fakeAPI) UpdateScreenshotKeys(_ context.Context, screenshotID int64, keyIDs []int64) error {
+	if f.relinked == nil {
+		f.relinked = make(map[int64][]int64)
+	}
+	f.relinked[screenshotID] = keyIDs
+	return nil
+}
+
+func (f *fakeAPI) DeleteScreenshot(_ context.Context, screenshotID int64) error {
+	f.deleted = append(f.deleted, screenshotID)
+	return nil
+}
+
+func (f *fakeAPI) ListKeysByName(_ context.Context, names []string) ([]lokaliseKey, error) {
+	f.names = append(f.names, names...)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.keys, nil
+}
+
+func (f *fakeAPI) UpdateKeys(_ context.Context, updates []keyUpdate) error {
+	if f.updateErr != nil {
+		return f.updateErr
+	}
+	f.updates = append(f.updates, updates...)
+	return nil
+}
+
+func (f *fakeAPI) FetchProcess(_ context.Context, processID string) (processResponse, error) {
+	f.processCalls = append(f.processCalls, processID)
+	if f.err != nil {
+		return processResponse{}, f.err
+	}
+	return f.processes[processID], nil
+}
+
+func (f *fakeAPI) FetchProject(_ context.Context) (projectResponse, error) {
+	f.projectCalls++
+	if f.err != nil {
+		return projectResponse{}, f.err
+	}
+	return f.project, nil
+}
+
+func (f *fakeAPI) ListKeysByTag(_ context.Context, tag string) ([]lokaliseKey, error) {
+	f.tags = append(f.tags, tag)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.keys, nil
+}
+
+func (f *fakeAPI) AddKeyComment(_ context.Context, keyID int64, comment string) error {
+	if f.commentErr != nil {
+		return f.commentErr
+	}
+	if f.comments == nil {
+		f.comments = make(map[int64]string)
+	}
+	f.comments[keyID] = comment
+	return nil
+}
+
+func (f *fakeAPI) CreateTask(_ context.Context, req taskRequest) (taskResponse, error) {
+	f.tasks = append(f.tasks, req)
+	if f.err != nil {
+		return taskResponse{}, f.err
+	}
+	var resp taskResponse
+	resp.Task.TaskID = int64(len(f.tasks))
+	resp.Task.Title = req.Title
+	return resp, nil
+}
+
+type fakeFactory struct {
+	api      *fakeAPI
+	err      error
+	projects []string
+}
+
+func (f *fakeFactory) NewAPI(_ postPushConfig, projectID string) (LokaliseAPI, error) {
+	f.projects = append(f.projects, projectID)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.api, nil
+}
+
+func noopWrite(string, string) bool { return true }
+
+func TestLokaliseAPI_ProjectPath(t *testing.T) {
+	c, err := client.NewClient("tok", "123.abc/x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	api := &lokaliseAPI{client: c}
+
+	if got := api.projectPath(""); got != "projects/123.abc%2Fx" {
+		t.Fatalf("unexpected project path: %q", got)
+	}
+	if got := api.projectPath("processes/p1"); got != "projects/123.abc%2Fx/processes/p1" {
+		t.Fatalf("unexpected process path: %q", got)
+	}
+}
+
+func TestCachedAPI(t *testing.T) {
+	factory := &fakeFactory{api: &fakeAPI{}}
+	clients := make(map[string]LokaliseAPI)
+
+	for range 2 {
+		if _, err := cachedAPI(clients, factory, postPushConfig{}, "p1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(factory.projects) != 1 {
+		t.Fatalf("expected client to be created once, got %v", factory.projects)
+	}
+
+	_, err := cachedAPI(clients, &fakeFactory{err: errors.New("bad token")}, postPushConfig{}, "p2")
+	if err == nil || !strings.Contains(err.Error(), "project p2") {
+		t.Fatalf("expected client error for p2, got %v", err)
+	}
+}
+
+func TestLokaliseFactory_ProjectTokens(t *testing.T) {
+	t.Setenv("PROJECT_MAPPINGS", "apps/web=1.web:web-token\napps/mobile=2.mobile:mobile-token\nlibs=3.libs")
+	t.Setenv("LOKALISE_API_TOKEN", "default-token")
+	cfg, err := prepareConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for projectID, want := range map[string]string{
+		"1.web":    "web-token",
+		"2.mobile": "mobile-token",
+		"3.libs":   "default-token",
+		"4.other":  "default-token",
+	} {
+		api, err := (&LokaliseFactory{}).NewAPI(cfg, projectID)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", projectID, err)
+		}
+		if got := api.(*lokaliseAPI).client.Token; got != want {
+			t.Fatalf("%s: expected token %q, got %q", projectID, want, got)
+		}
+	}
+}
+
+func newTestAPI(t *testing.T, handler http.HandlerFunc) *lokaliseAPI {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := client.NewClient("tok", "p1",
+		client.WithBaseURL(srv.URL),
+		client.WithMaxRetries(1),
+		client.WithBackoff(time.Millisecond, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return &lokaliseAPI{client: c}
+}
+
+func TestLokaliseAPI_ListKeysByTag(t *testing.T) {
+	var pages []string
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/p1/keys" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if r.Header.Get("X-Api-Token") != "tok" {
+			t.Errorf("missing token header")
+		}
+		if got := r.URL.Query().Get("filter_tags"); got != "feature/x" {
+			t.Errorf("unexpected filter_tags %q", got)
+		}
+
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+
+		count := 2
+		if page == "1" {
+			count = keysPageLimit
+		}
+		keys := make([]map[string]int64, 0, count)
+		for i := range count {
+			keys = append(keys, map[string]int64{"key_id": int64(i + 1)})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	})
+
+	keys, err := api.ListKeysByTag(context.Background(), "feature/x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != keysPageLimit+2 {
+		t.Fatalf("expected %d keys, got %d", keysPageLimit+2, len(keys))
+	}
+	if strings.Join(pages, ",") != "1,2" {
+		t.Fatalf("unexpected pages requested: %v", pages)
+	}
+}
+
+func TestLokaliseAPI_ListKeysByTag_Errors(t *testing.T) {
+	t.Run("client errors are not retried", func(t *testing.T) {
+		calls := 0
+		api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"bad"}}`))
+		})
+
+		_, err := api.ListKeysByTag(context.Background(), "main")
+		if err == nil || !strings.Contains(err.Error(), "status 400") {
+			t.Fatalf("expected status error, got %v", err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected a single attempt, got %d", calls)
+		}
+	})
+
+	t.Run("rate limits are retried", func(t *testing.T) {
+		calls := 0
+		api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_, _ = w.Write([]byte(`{"keys":[{"key_id":7,"created_at_timestamp":1700000000}]}`))
+		})
+
+		keys, err := api.ListKeysByTag(context.Background(), "main")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []lokaliseKey{{KeyID: 7, CreatedAtTimestamp: 1700000000}}
+		if !reflect.DeepEqual(keys, want) || calls != 2 {
+			t.Fatalf("unexpected result keys=%v calls=%d", keys, calls)
+		}
+	})
+}
+
+func TestLokaliseAPI_CreateTask(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/projects/p1/tasks" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var got taskRequest
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		if got.Title != "T" || len(got.Keys) != 2 || got.Languages[0].Groups[0] != 9 {
+			t.Errorf("unexpected task body: %#v", got)
+		}
+		_, _ = w.Write([]byte(`{"project_id":"p1","task":{"task_id":55,"title":"T"}}`))
+	})
+
+	resp, err := api.CreateTask(context.Background(), taskRequest{
+		Title:     "T",
+		TaskType:  "translation",
+		Keys:      []int64{1, 2},
+		Languages: []taskLanguage{{LanguageISO: "fr", Groups: []int64{9}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Task.TaskID != 55 {
+		t.Fatalf("unexpected response: %#v", resp)
+	}
+}
+
+func TestLokaliseAPI_AddKeyComment(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/projects/p1/keys/42/comments" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var got struct {
+			Comments []struct {
+				Comment string `json:"comment"`
+			} `json:"comments"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		if len(got.Comments) != 1 || got.Comments[0].Comment != "hello" {
+			t.Errorf("unexpected comment body: %#v", got)
+		}
+		_, _ = w.Write([]byte(`{"project_id":"p1","key_id":42,"comments":[{"comment_id":1}]}`))
+	})
+
+	if err := api.AddKeyComment(context.Background(), 42, "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestKeyNameNames(t *testing.T) {
+	n := keyName{IOS: "a", Android: "a", Web: "a", Other: "a"}
+	if got := n.names(); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Fatalf("expected single name, got %v", got)
+	}
+
+	n = keyName{IOS: "ios_name", Web: "web.name"}
+	if got := n.names(); !reflect.DeepEqual(got, []string{"web.name", "ios_name"}) {
+		t.Fatalf("unexpected names: %v", got)
+	}
+}
+
+func TestLokaliseAPI_ListKeysByName(t *testing.T) {
+	var filters []string
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("filter_keys")
+		filters = append(filters, filter)
+		first := strings.Split(filter, ",")[0]
+		_, _ = fmt.Fprintf(w, `{"keys":[{"key_id":%d,"key_name":{"web":%q}}]}`, len(filters), first)
+	})
+
+	names := make([]string, keyNamesPerPage+1)
+	for i := range names {
+		names[i] = fmt.Sprintf("key.%d", i)
+	}
+
+	keys, err := api.ListKeysByName(context.Background(), names)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(filters) != 2 || len(strings.Split(filters[0], ",")) != keyNamesPerPage || filters[1] != "key.100" {
+		t.Fatalf("unexpected batches: %d requests", len(filters))
+	}
+	if len(keys) != 2 || keys[1].KeyName.Web != "key.100" {
+		t.Fatalf("unexpected keys: %#v", keys)
+	}
+}
+
+func TestLokaliseAPI_UpdateKeys(t *testing.T) {
+	var batches []int
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/projects/p1/keys" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Keys []map[string]any `json:"keys"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		batches = append(batches, len(body.Keys))
+		if _, ok := body.Keys[0]["char_limit"]; ok {
+			t.Errorf("nil fields must be omitted: %v", body.Keys[0])
+		}
+		_, _ = w.Write([]byte(`{"keys":[]}`))
+	})
+
+	desc := "hello"
+	updates := make([]keyUpdate, keysPerUpdate+3)
+	for i := range updates {
+		updates[i] = keyUpdate{KeyID: int64(i + 1), Description: &desc}
+	}
+
+	if err := api.UpdateKeys(context.Background(), updates); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(batches, []int{keysPerUpdate, 3}) {
+		t.Fatalf("unexpected batches: %v", batches)
+	}
+}
+
+func TestLokaliseAPI_Screenshots(t *testing.T) {
+	var requests []string
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"screenshots":[{"screenshot_id":3,"title":"a.png","description":"sha256:x","key_ids":[1,2]}]}`))
+		case r.Method == http.MethodPost:
+			var body struct {
+				Screenshots []newScreenshot `json:"screenshots"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Screenshots) != 1 || body.Screenshots[0].Title != "b.png" {
+				t.Errorf("unexpected create body: %#v err=%v", body, err)
+			}
+			_, _ = w.Write([]byte(`{"screenshots":[]}`))
+		case r.Method == http.MethodPut:
+			var body map[string][]int64
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !reflect.DeepEqual(body["key_ids"], []int64{4}) {
+				t.Errorf("unexpected update body: %#v err=%v", body, err)
+			}
+			_, _ = w.Write([]byte(`{"screenshot":{}}`))
+		default:
+			_, _ = w.Write([]byte(`{"screenshot_deleted":true}`))
+		}
+	})
+
+	got, err := api.ListScreenshots(context.Background())
+	if err != nil || len(got) != 1 || got[0].ScreenshotID != 3 || !reflect.DeepEqual(got[0].KeyIDs, []int64{1, 2}) {
+		t.Fatalf("unexpected screenshots %#v err=%v", got, err)
+	}
+	if err := api.CreateScreenshot(context.Background(), newScreenshot{Title: "b.png"}); err != nil {
+		t.Fatalf("unexpected create error: %v", err)
+	}
+	if err := api.UpdateScreenshotKeys(context.Background(), 3, []int64{4}); err != nil {
+		t.Fatalf("unexpected update error: %v", err)
+	}
+	if err := api.DeleteScreenshot(context.Background(), 3); err != nil {
+		t.Fatalf("unexpected delete error: %v", err)
+	}
+
+	want := []string{
+		"GET /projects/p1/screenshots",
+		"POST /projects/p1/screenshots",
+		"PUT /projects/p1/screenshots/3",
+		"DELETE /projects/p1/screenshots/3",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+}
+
+func TestLokaliseAPI_FetchProjectAndProcess(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/projects/p1":
+			_, _ = w.Write([]byte(`{"project_id":"p1","name":"App","statistics":{"keys_total":3}}`))
+		case "/projects/p1/processes/pid":
+			_, _ = w.Write([]byte(`{"process":{"status":"finished","details":{"files":[{"key_count_inserted":2}]}}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	project, err := api.FetchProject(context.Background())
+	if err != nil || project.Statistics.KeysTotal != 3 {
+		t.Fatalf("unexpected project %#v err=%v", project, err)
+	}
+
+	proc, err := api.FetchProcess(context.Background(), "pid")
+	if err != nil || proc.Process.Details.Files[0].KeyCountInserted != 2 {
+		t.Fatalf("unexpected process %#v err=%v", proc, err)
+	}
+}
@@ -0,0 +1,236 @@
+package postpush
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/logging"
+)
+
+const (
+	defaultCheckRunName = "Lokalise push"
+
+	// maxCheckAnnotations is how many annotations the Checks API accepts per
+	// request; more are sent by updating the check run.
+	maxCheckAnnotations = 50
+	// maxCheckText is the limit of the summary and text of a check run.
+	maxCheckText = 65535
+)
+
+// checkRun is the body of a check run create or update request.
+type checkRun struct {
+	Name       string          `json:"name,omitempty"`
+	HeadSHA    string          `json:"head_sha,omitempty"`
+	Status     string          `json:"status,omitempty"`
+	Conclusion string          `json:"conclusion,omitempty"`
+	DetailsURL string          `json:"details_url,omitempty"`
+	Output     *checkRunOutput `json:"output,omitempty"`
+}
+
+type checkRunOutput struct {
+	Title       string            `json:"title"`
+	Summary     string            `json:"summary"`
+	Text        string            `json:"text,omitempty"`
+	Annotations []checkAnnotation `json:"annotations,omitempty"`
+}
+
+// checkAnnotation points at a file that could not be pushed.
+type checkAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title"`
+	Message         string `json:"message"`
+}
+
+// CheckRun reports the uploads recorded in REPORT_DIR as a check run on the
+// pushed commit, so the result shows on the checks tab of pull requests
+// separately from the workflow job. It exits the process on failure.
+func CheckRun() {
+	if err := runCheckRun(prepareConfig, loadUploadResults, newGitHubAPI); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// ValidateCheckRun reads and validates the configuration of CheckRun without running it.
+func ValidateCheckRun() error {
+	cfg, err := prepareConfig()
+	if err != nil {
+		return err
+	}
+	return validateCheckRun(cfg)
+}
+
+// validateCheckRun ensures the check run can be created.
+func validateCheckRun(cfg postPushConfig) error {
+	var errs []error
+	if cfg.ReportDir == "" {
+		errs = append(errs, fmt.Errorf("report directory (REPORT_DIR) is required and cannot be empty"))
+	}
+	if cfg.GitHubToken == "" {
+		errs = append(errs, fmt.Errorf("GitHub token (GITHUB_TOKEN) is required to create a check run"))
+	}
+	if cfg.Repository == "" {
+		errs = append(errs, fmt.Errorf("GitHub repository (GITHUB_REPOSITORY) is required to create a check run"))
+	}
+	if cfg.HeadSHA == "" {
+		errs = append(errs, fmt.Errorf("commit SHA (GITHUB_SHA) is required to create a check run"))
+	}
+	return envconf.Join(errs)
+}
+
+// runCheckRun creates the check run, completed at once since the push is over.
+// Unlike the other integrations it also reports runs that uploaded nothing or
+// failed, which is when the check matters most.
+func runCheckRun(
+	prepare func() (postPushConfig, error),
+	load func(string) ([]uploadResult, error),
+	newAPI func(postPushConfig) GitHubAPI,
+) error {
+	cfg, err := prepare()
+	if err != nil {
+		return err
+	}
+	for _, secret := range []string{cfg.Token, cfg.GitHubToken} {
+		logging.Mask(os.Stdout, secret)
+	}
+	if err := validateCheckRun(cfg); err != nil {
+		return err
+	}
+
+	results, err := load(cfg.ReportDir)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	run := buildCheckRun(cfg, results)
+	annotations := run.Output.Annotations
+	run.Output.Annotations = annotations[:min(len(annotations), maxCheckAnnotations)]
+
+	api := newAPI(cfg)
+	id, err := api.CreateCheckRun(ctx, run)
+	if err != nil {
+		return fmt.Errorf("cannot create check run: %w", err)
+	}
+
+	for i := maxCheckAnnotations; i < len(annotations); i += maxCheckAnnotations {
+		output := *run.Output
+		output.Annotations = annotations[i:min(i+maxCheckAnnotations, len(annotations))]
+		if err := api.UpdateCheckRun(ctx, id, checkRun{Output: &output}); err != nil {
+			return fmt.Errorf("cannot add annotations to check run: %w", err)
+		}
+	}
+
+	logs.Infof("Check run %q created: %s", cfg.CheckRunName, run.Conclusion)
+	return nil
+}
+
+// buildCheckRun describes the push: its conclusion, a table with the result of
+// every file, and a failure annotation on every file that wasn't pushed.
+func buildCheckRun(cfg postPushConfig, results []uploadResult) checkRun {
+	failed := 0
+	var annotations []checkAnnotation
+	for _, res := range results {
+		if res.Status != resultStatusFailed {
+			continue
+		}
+		failed++
+		annotations = append(annotations, checkAnnotation{
+			Path:            res.File,
+			StartLine:       1,
+			EndLine:         1,
+			AnnotationLevel: "failure",
+			Title:           "Lokalise upload failed",
+			Message:         fmt.Sprintf("Upload to project %s failed: %s", res.ProjectID, res.Error),
+		})
+	}
+
+	conclusion, title := checkConclusion(cfg.PushOutcome, len(results), failed)
+
+	var summary strings.Builder
+	summary.WriteString(title + ".")
+	if url := runURL(cfg); url != "" {
+		fmt.Fprintf(&summary, " See the [workflow run](%s) for the logs.", url)
+	}
+
+	return checkRun{
+		Name:       cfg.CheckRunName,
+		HeadSHA:    cfg.HeadSHA,
+		Status:     "completed",
+		Conclusion: conclusion,
+		DetailsURL: runURL(cfg),
+		Output: &checkRunOutput{
+			Title:       title,
+			Summary:     summary.String(),
+			Text:        renderCheckRunText(results),
+			Annotations: annotations,
+		},
+	}
+}
+
+// checkConclusion picks the conclusion and title of the check run. A failed
+// push step fails the check even when no upload was recorded, for example
+// when the configuration was invalid.
+func checkConclusion(outcome string, total, failed int) (string, string) {
+	switch {
+	case failed > 0:
+		return "failure", fmt.Sprintf("%d of %d files failed to push", failed, total)
+	case outcome == "failure":
+		return "failure", "The push failed"
+	case outcome == "cancelled":
+		return "cancelled", "The push was cancelled"
+	case total == 0:
+		return "success", "No translation files to push"
+	case total == 1:
+		return "success", "1 file pushed to Lokalise"
+	default:
+		return "success", fmt.Sprintf("%d files pushed to Lokalise", total)
+	}
+}
+
+// renderCheckRunText renders the result of every upload as a Markdown table,
+// cut short at the size the Checks API accepts.
+func renderCheckRunText(results []uploadResult) string {
+	if len(results) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("| File | Project | Language | Result |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for i, res := range results {
+		result := "✅ pushed"
+		if res.Status == resultStatusFailed {
+			result = "❌ " + escapeTableCell(res.Error)
+		}
+		row := fmt.Sprintf("| `%s` | %s | %s | %s |\n", escapeTableCell(res.File), res.ProjectID, res.LangISO, result)
+
+		more := fmt.Sprintf("\n_%d more files not shown._\n", len(results)-i)
+		if b.Len()+len(row)+len(more) > maxCheckText {
+			b.WriteString(more)
+			break
+		}
+		b.WriteString(row)
+	}
+	return b.String()
+}
+
+// escapeTableCell keeps s on one line of a Markdown table cell.
+func escapeTableCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ", "\r", " ", "`", "'").Replace(s)
+}
+
+// runURL links to the workflow run, or is empty outside GitHub Actions.
+func runURL(cfg postPushConfig) string {
+	if cfg.ServerURL == "" || cfg.Repository == "" || cfg.RunID == "" {
+		return ""
+	}
+	return strings.TrimSuffix(cfg.ServerURL, "/") + "/" + cfg.Repository + "/actions/runs/" + cfg.RunID
+}
@@ -0,0 +1,196 @@
+package postpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeGitHubAPI struct {
+	created  []checkRun
+	updates  []checkRun
+	statuses []commitStatus
+	sha      string
+	err      error
+}
+
+func (f *fakeGitHubAPI) CreateCheckRun(_ context.Context, run checkRun) (int64, error) {
+	f.created = append(f.created, run)
+	return 7, f.err
+}
+
+func (f *fakeGitHubAPI) UpdateCheckRun(_ context.Context, id int64, run checkRun) error {
+	if id != 7 {
+		return fmt.Errorf("unexpected check run %d", id)
+	}
+	f.updates = append(f.updates, run)
+	return f.err
+}
+
+func (f *fakeGitHubAPI) CreateStatus(_ context.Context, sha string, status commitStatus) error {
+	f.sha = sha
+	f.statuses = append(f.statuses, status)
+	return f.err
+}
+
+func checkRunConfig() postPushConfig {
+	return postPushConfig{
+		ReportDir:    "/tmp/report",
+		Repository:   "acme/app",
+		ServerURL:    "https://github.com",
+		RunID:        "42",
+		HeadSHA:      "abc",
+		GitHubToken:  "ghs_x",
+		CheckRunName: defaultCheckRunName,
+		Timeout:      time.Minute,
+	}
+}
+
+func TestBuildCheckRun(t *testing.T) {
+	results := []uploadResult{
+		{File: "locales/en.json", ProjectID: "p1", LangISO: "en", Status: resultStatusUploaded},
+		{File: "locales/fr.json", ProjectID: "p1", LangISO: "fr", Status: resultStatusFailed, Error: "invalid | file\nformat"},
+	}
+
+	run := buildCheckRun(checkRunConfig(), results)
+
+	if run.Name != defaultCheckRunName || run.HeadSHA != "abc" || run.Status != "completed" || run.Conclusion != "failure" {
+		t.Fatalf("unexpected check run %+v", run)
+	}
+	if run.DetailsURL != "https://github.com/acme/app/actions/runs/42" {
+		t.Fatalf("unexpected details URL %q", run.DetailsURL)
+	}
+	if run.Output.Title != "1 of 2 files failed to push" || !strings.Contains(run.Output.Summary, "[workflow run](https://github.com/acme/app/actions/runs/42)") {
+		t.Fatalf("unexpected output %+v", run.Output)
+	}
+	for _, want := range []string{"| `locales/en.json` | p1 | en | ✅ pushed |", `| ❌ invalid \| file format |`} {
+		if !strings.Contains(run.Output.Text, want) {
+			t.Fatalf("expected text to contain %q, got %q", want, run.Output.Text)
+		}
+	}
+	want := checkAnnotation{
+		Path:            "locales/fr.json",
+		StartLine:       1,
+		EndLine:         1,
+		AnnotationLevel: "failure",
+		Title:           "Lokalise upload failed",
+		Message:         "Upload to project p1 failed: invalid | file\nformat",
+	}
+	if len(run.Output.Annotations) != 1 || run.Output.Annotations[0] != want {
+		t.Fatalf("unexpected annotations %+v", run.Output.Annotations)
+	}
+}
+
+func TestCheckConclusion(t *testing.T) {
+	tests := []struct {
+		outcome         string
+		total, failed   int
+		conclusion, msg string
+	}{
+		{"failure", 3, 2, "failure", "2 of 3 files failed to push"},
+		{"failure", 0, 0, "failure", "The push failed"},
+		{"cancelled", 1, 0, "cancelled", "The push was cancelled"},
+		{"success", 0, 0, "success", "No translation files to push"},
+		{"", 1, 0, "success", "1 file pushed to Lokalise"},
+		{"success", 4, 0, "success", "4 files pushed to Lokalise"},
+	}
+	for _, tt := range tests {
+		conclusion, msg := checkConclusion(tt.outcome, tt.total, tt.failed)
+		if conclusion != tt.conclusion || msg != tt.msg {
+			t.Errorf("%q %d/%d: expected %q %q, got %q %q", tt.outcome, tt.failed, tt.total, tt.conclusion, tt.msg, conclusion, msg)
+		}
+	}
+}
+
+func TestRenderCheckRunText_Truncates(t *testing.T) {
+	if got := renderCheckRunText(nil); got != "" {
+		t.Fatalf("expected no text without results, got %q", got)
+	}
+
+	results := make([]uploadResult, 2000)
+	for i := range results {
+		results[i] = uploadResult{File: fmt.Sprintf("locales/%s/%04d.json", strings.Repeat("x", 40), i), ProjectID: "p1", LangISO: "en", Status: resultStatusUploaded}
+	}
+
+	text := renderCheckRunText(results)
+	if len(text) > maxCheckText {
+		t.Fatalf("expected at most %d bytes, got %d", maxCheckText, len(text))
+	}
+	if !strings.Contains(text, "more files not shown") {
+		t.Fatalf("expected a truncation note, got %q", text[len(text)-100:])
+	}
+}
+
+func TestRunCheckRun(t *testing.T) {
+	load := func(results []uploadResult) func(string) ([]uploadResult, error) {
+		return func(string) ([]uploadResult, error) { return results, nil }
+	}
+
+	t.Run("annotations are sent in batches", func(t *testing.T) {
+		var results []uploadResult
+		for i := range 120 {
+			results = append(results, uploadResult{File: fmt.Sprintf("%d.json", i), ProjectID: "p1", Status: resultStatusFailed, Error: "boom"})
+		}
+		api := &fakeGitHubAPI{}
+
+		err := runCheckRun(func() (postPushConfig, error) { return checkRunConfig(), nil }, load(results), func(postPushConfig) GitHubAPI { return api })
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.created) != 1 || len(api.created[0].Output.Annotations) != maxCheckAnnotations {
+			t.Fatalf("expected one check run with %d annotations, got %+v", maxCheckAnnotations, api.created)
+		}
+		if len(api.updates) != 2 || len(api.updates[0].Output.Annotations) != 50 || len(api.updates[1].Output.Annotations) != 20 {
+			t.Fatalf("expected updates with 50 and 20 annotations, got %d updates", len(api.updates))
+		}
+		if api.updates[1].Output.Annotations[0].Path != "100.json" || api.updates[1].Output.Title != api.created[0].Output.Title {
+			t.Fatalf("unexpected last update %+v", api.updates[1].Output)
+		}
+	})
+
+	t.Run("no results are reported too", func(t *testing.T) {
+		api := &fakeGitHubAPI{}
+		err := runCheckRun(func() (postPushConfig, error) { return checkRunConfig(), nil }, load(nil), func(postPushConfig) GitHubAPI { return api })
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.created) != 1 || api.created[0].Conclusion != "success" || len(api.updates) != 0 {
+			t.Fatalf("unexpected check runs %+v", api.created)
+		}
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		api := &fakeGitHubAPI{err: &statusError{StatusCode: 403, Body: "forbidden"}}
+		err := runCheckRun(func() (postPushConfig, error) { return checkRunConfig(), nil }, load(nil), func(postPushConfig) GitHubAPI { return api })
+		if err == nil || !strings.Contains(err.Error(), "cannot create check run") || !strings.Contains(err.Error(), "403") {
+			t.Fatalf("expected a check run error, got %v", err)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		cfg := checkRunConfig()
+		cfg.GitHubToken = ""
+		cfg.HeadSHA = ""
+		err := runCheckRun(func() (postPushConfig, error) { return cfg, nil }, load(nil), func(postPushConfig) GitHubAPI {
+			t.Fatal("the API must not be used with an invalid config")
+			return nil
+		})
+		if err == nil || !strings.Contains(err.Error(), "GITHUB_TOKEN") || !strings.Contains(err.Error(), "GITHUB_SHA") {
+			t.Fatalf("expected a validation error, got %v", err)
+		}
+	})
+
+	t.Run("load error", func(t *testing.T) {
+		err := runCheckRun(
+			func() (postPushConfig, error) { return checkRunConfig(), nil },
+			func(string) ([]uploadResult, error) { return nil, errors.New("bad report") },
+			func(postPushConfig) GitHubAPI { return &fakeGitHubAPI{} },
+		)
+		if err == nil || err.Error() != "bad report" {
+			t.Fatalf("expected the load error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package postpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxKeyComments caps comments per project so large initial pushes
+// don't flood translators (and the API rate limit) with notes.
+const maxKeyComments = 300
+
+// pushStartedAt returns the earliest upload start time for projectID, or 0 when unknown.
+func pushStartedAt(results []uploadResult, projectID string) int64 {
+	var earliest int64
+	for _, res := range results {
+		if res.ProjectID != projectID || res.StartedAt == 0 {
+			continue
+		}
+		if earliest == 0 || res.StartedAt < earliest {
+			earliest = res.StartedAt
+		}
+	}
+	return earliest
+}
+
+// newKeyIDs returns the IDs of keys created at or after since.
+func newKeyIDs(keys []lokaliseKey, since int64) []int64 {
+	var ids []int64
+	for _, k := range keys {
+		if k.CreatedAtTimestamp >= since {
+			ids = append(ids, k.KeyID)
+		}
+	}
+	return ids
+}
+
+// ciContextComment describes where new keys came from: repository, PR or commit link, and run.
+func ciContextComment(cfg postPushConfig) string {
+	var b strings.Builder
+	b.WriteString("Added by lokalise-push-action")
+	if cfg.Repository != "" {
+		fmt.Fprintf(&b, " from %s", cfg.Repository)
+	}
+	if cfg.Branch != "" {
+		fmt.Fprintf(&b, " (branch %s)", cfg.Branch)
+	}
+	b.WriteString(".")
+
+	repoURL := ""
+	if cfg.ServerURL != "" && cfg.Repository != "" {
+		repoURL = strings.TrimSuffix(cfg.ServerURL, "/") + "/" + cfg.Repository
+	}
+
+	if cfg.PRNumber != "" {
+		if repoURL != "" {
+			fmt.Fprintf(&b, "\nPull request: %s/pull/%s", repoURL, cfg.PRNumber)
+		} else {
+			fmt.Fprintf(&b, "\nPull request: #%s", cfg.PRNumber)
+		}
+	}
+	if cfg.SHA != "" {
+		if repoURL != "" {
+			fmt.Fprintf(&b, "\nCommit: %s/commit/%s", repoURL, cfg.SHA)
+		} else {
+			fmt.Fprintf(&b, "\nCommit: %s", cfg.SHA)
+		}
+	}
+	if repoURL != "" && cfg.RunID != "" {
+		fmt.Fprintf(&b, "\nWorkflow run: %s/actions/runs/%s", repoURL, cfg.RunID)
+	}
+
+	return b.String()
+}
+
+// commentNewKeys adds a CI context comment to every key created by this push.
+// New keys are those tagged with the branch name and created after the push started.
+func commentNewKeys(ctx context.Context, cfg postPushConfig, results []uploadResult, factory ClientFactory) error {
+	clients := make(map[string]LokaliseAPI)
+	comment := ciContextComment(cfg)
+
+	var errs []error
+	for _, projectID := range pushedProjectIDs(cfg, results) {
+		since := pushStartedAt(results, projectID)
+		if since == 0 {
+			logs.Infof("Project %s: push start time unknown, comments not added", projectID)
+			continue
+		}
+
+		api, err := cachedAPI(clients, factory, cfg, projectID)
+		if err != nil {
+			return err
+		}
+
+		keys, err := api.ListKeysByTag(ctx, cfg.Branch)
+		if err != nil {
+			return fmt.Errorf("cannot list keys tagged %q in project %s: %w", cfg.Branch, projectID, err)
+		}
+
+		ids := newKeyIDs(keys, since)
+		if len(ids) > maxKeyComments {
+			logs.Infof("Project %s: %d new keys, commenting on the first %d only", projectID, len(ids), maxKeyComments)
+			ids = ids[:maxKeyComments]
+		}
+
+		commented := 0
+		for _, id := range ids {
+			if err := api.AddKeyComment(ctx, id, comment); err != nil {
+				errs = append(errs, fmt.Errorf("cannot comment on key %d in project %s: %w", id, projectID, err))
+				continue
+			}
+			commented++
+		}
+
+		logs.Infof("Project %s: added CI context to %d new keys", projectID, commented)
+	}
+
+	return errors.Join(errs...)
+}
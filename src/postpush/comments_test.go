@@ -0,0 +1,132 @@
+package postpush
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPushStartedAt(t *testing.T) {
+	results := []uploadResult{
+		{ProjectID: "p1", StartedAt: 300},
+		{ProjectID: "p1", StartedAt: 200},
+		{ProjectID: "p1"},
+		{ProjectID: "p2", StartedAt: 100},
+	}
+
+	if got := pushStartedAt(results, "p1"); got != 200 {
+		t.Fatalf("expected 200, got %d", got)
+	}
+	if got := pushStartedAt(results, "p3"); got != 0 {
+		t.Fatalf("expected 0 for unknown project, got %d", got)
+	}
+}
+
+func TestNewKeyIDs(t *testing.T) {
+	keys := []lokaliseKey{
+		{KeyID: 1, CreatedAtTimestamp: 99},
+		{KeyID: 2, CreatedAtTimestamp: 100},
+		{KeyID: 3, CreatedAtTimestamp: 150},
+	}
+	if got := newKeyIDs(keys, 100); !reflect.DeepEqual(got, []int64{2, 3}) {
+		t.Fatalf("unexpected ids: %v", got)
+	}
+}
+
+func TestCIContextComment(t *testing.T) {
+	cfg := postPushConfig{
+		Repository: "acme/app",
+		Branch:     "feature/x",
+		SHA:        "abc",
+		RunID:      "9",
+		PRNumber:   "42",
+		ServerURL:  "https://github.com/",
+	}
+
+	got := ciContextComment(cfg)
+	for _, want := range []string{
+		"Added by lokalise-push-action from acme/app (branch feature/x).",
+		"Pull request: https://github.com/acme/app/pull/42",
+		"Commit: https://github.com/acme/app/commit/abc",
+		"Workflow run: https://github.com/acme/app/actions/runs/9",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("comment missing %q:\n%s", want, got)
+		}
+	}
+
+	bare := ciContextComment(postPushConfig{SHA: "abc", PRNumber: "42"})
+	if bare != "Added by lokalise-push-action.\nPull request: #42\nCommit: abc" {
+		t.Fatalf("unexpected comment without repository: %q", bare)
+	}
+}
+
+func TestCommentNewKeys(t *testing.T) {
+	cfg := postPushConfig{Branch: "main", Repository: "acme/app"}
+	results := []uploadResult{{ProjectID: "p1", Status: resultStatusUploaded, StartedAt: 100}}
+
+	t.Run("comments only on keys created by this push", func(t *testing.T) {
+		api := &fakeAPI{keys: []lokaliseKey{
+			{KeyID: 1, CreatedAtTimestamp: 50},
+			{KeyID: 2, CreatedAtTimestamp: 120},
+		}}
+
+		if err := commentNewKeys(context.Background(), cfg, results, &fakeFactory{api: api}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.comments) != 1 || api.comments[2] == "" {
+			t.Fatalf("expected a comment on key 2 only, got %v", api.comments)
+		}
+		if !reflect.DeepEqual(api.tags, []string{"main"}) {
+			t.Fatalf("expected keys listed by branch tag, got %v", api.tags)
+		}
+	})
+
+	t.Run("caps the number of comments", func(t *testing.T) {
+		keys := make([]lokaliseKey, maxKeyComments+5)
+		for i := range keys {
+			keys[i] = lokaliseKey{KeyID: int64(i + 1), CreatedAtTimestamp: 200}
+		}
+		api := &fakeAPI{keys: keys}
+
+		if err := commentNewKeys(context.Background(), cfg, results, &fakeFactory{api: api}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.comments) != maxKeyComments {
+			t.Fatalf("expected %d comments, got %d", maxKeyComments, len(api.comments))
+		}
+	})
+
+	t.Run("unknown start time skips project", func(t *testing.T) {
+		api := &fakeAPI{keys: []lokaliseKey{{KeyID: 1, CreatedAtTimestamp: 200}}}
+		noStart := []uploadResult{{ProjectID: "p1", Status: resultStatusUploaded}}
+
+		if err := commentNewKeys(context.Background(), cfg, noStart, &fakeFactory{api: api}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.tags) != 0 {
+			t.Fatalf("expected no API calls, got %v", api.tags)
+		}
+	})
+
+	t.Run("comment failures are returned", func(t *testing.T) {
+		api := &fakeAPI{
+			keys:       []lokaliseKey{{KeyID: 7, CreatedAtTimestamp: 200}},
+			commentErr: errors.New("boom"),
+		}
+
+		err := commentNewKeys(context.Background(), cfg, results, &fakeFactory{api: api})
+		if err == nil || !strings.Contains(err.Error(), "key 7") {
+			t.Fatalf("expected comment error, got %v", err)
+		}
+	})
+
+	t.Run("list failures are returned", func(t *testing.T) {
+		err := commentNewKeys(context.Background(), cfg, results, &fakeFactory{api: &fakeAPI{err: errors.New("boom")}})
+		if err == nil || !strings.Contains(err.Error(), "cannot list keys") {
+			t.Fatalf("expected list error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package postpush
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/logging"
+)
+
+const defaultCommitStatusContext = "lokalise/push"
+
+// commitStatus is the body of a commit status request.
+type commitStatus struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description,omitempty"`
+	Context     string `json:"context"`
+}
+
+// CommitStatus sets the commit status of the push on the pushed commit, for
+// branch protection rules that require statuses rather than checks. Without
+// PUSH_OUTCOME the status is pending; run it again once the upload step is
+// done to set the final state. It exits the process on failure.
+func CommitStatus() {
+	if err := runCommitStatus(prepareConfig, loadUploadResults, newGitHubAPI); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// ValidateCommitStatus reads and validates the configuration of CommitStatus without running it.
+func ValidateCommitStatus() error {
+	cfg, err := prepareConfig()
+	if err != nil {
+		return err
+	}
+	return validateCommitStatus(cfg)
+}
+
+// validateCommitStatus ensures the commit status can be set.
+func validateCommitStatus(cfg postPushConfig) error {
+	var errs []error
+	if cfg.GitHubToken == "" {
+		errs = append(errs, fmt.Errorf("GitHub token (GITHUB_TOKEN) is required to set a commit status"))
+	}
+	if cfg.Repository == "" {
+		errs = append(errs, fmt.Errorf("GitHub repository (GITHUB_REPOSITORY) is required to set a commit status"))
+	}
+	if cfg.HeadSHA == "" {
+		errs = append(errs, fmt.Errorf("commit SHA (GITHUB_SHA) is required to set a commit status"))
+	}
+	return envconf.Join(errs)
+}
+
+func runCommitStatus(
+	prepare func() (postPushConfig, error),
+	load func(string) ([]uploadResult, error),
+	newAPI func(postPushConfig) GitHubAPI,
+) error {
+	cfg, err := prepare()
+	if err != nil {
+		return err
+	}
+	for _, secret := range []string{cfg.Token, cfg.GitHubToken} {
+		logging.Mask(os.Stdout, secret)
+	}
+	if err := validateCommitStatus(cfg); err != nil {
+		return err
+	}
+
+	// The upload results only matter once the push is over.
+	var results []uploadResult
+	if cfg.PushOutcome != "" && cfg.ReportDir != "" {
+		if results, err = load(cfg.ReportDir); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	status := buildCommitStatus(cfg, results)
+	if err := newAPI(cfg).CreateStatus(ctx, cfg.HeadSHA, status); err != nil {
+		return fmt.Errorf("cannot set commit status: %w", err)
+	}
+
+	logs.Infof("Commit status %q set: %s", status.Context, status.State)
+	return nil
+}
+
+// buildCommitStatus describes the push as a commit status: pending while the
+// outcome is unknown, otherwise the conclusion the check run would have.
+// GitHub statuses have no cancelled state, so a cancelled push is an error.
+func buildCommitStatus(cfg postPushConfig, results []uploadResult) commitStatus {
+	status := commitStatus{
+		State:       "pending",
+		TargetURL:   runURL(cfg),
+		Description: "Pushing translation files to Lokalise",
+		Context:     cfg.CommitStatusContext,
+	}
+	if cfg.PushOutcome == "" {
+		return status
+	}
+
+	failed := 0
+	for _, res := range results {
+		if res.Status == resultStatusFailed {
+			failed++
+		}
+	}
+
+	conclusion, description := checkConclusion(cfg.PushOutcome, len(results), failed)
+	if conclusion == "cancelled" {
+		conclusion = "error"
+	}
+	status.State = conclusion
+	status.Description = description
+	return status
+}
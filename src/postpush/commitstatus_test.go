@@ -0,0 +1,108 @@
+package postpush
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func commitStatusConfig() postPushConfig {
+	cfg := checkRunConfig()
+	cfg.CommitStatusContext = defaultCommitStatusContext
+	return cfg
+}
+
+func TestBuildCommitStatus(t *testing.T) {
+	uploaded := uploadResult{File: "en.json", ProjectID: "p1", Status: resultStatusUploaded}
+	failed := uploadResult{File: "fr.json", ProjectID: "p1", Status: resultStatusFailed, Error: "boom"}
+
+	tests := []struct {
+		name, outcome  string
+		results        []uploadResult
+		state, summary string
+	}{
+		{"pending", "", nil, "pending", "Pushing translation files to Lokalise"},
+		{"success", "success", []uploadResult{uploaded, uploaded}, "success", "2 files pushed to Lokalise"},
+		{"failed file", "failure", []uploadResult{uploaded, failed}, "failure", "1 of 2 files failed to push"},
+		{"failed step", "failure", nil, "failure", "The push failed"},
+		{"cancelled", "cancelled", nil, "error", "The push was cancelled"},
+		{"skipped", "skipped", nil, "success", "No translation files to push"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := commitStatusConfig()
+			cfg.PushOutcome = tt.outcome
+
+			got := buildCommitStatus(cfg, tt.results)
+			want := commitStatus{
+				State:       tt.state,
+				TargetURL:   "https://github.com/acme/app/actions/runs/42",
+				Description: tt.summary,
+				Context:     defaultCommitStatusContext,
+			}
+			if got != want {
+				t.Fatalf("expected %+v, got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestRunCommitStatus(t *testing.T) {
+	t.Run("pending does not read the report", func(t *testing.T) {
+		api := &fakeGitHubAPI{}
+		err := runCommitStatus(
+			func() (postPushConfig, error) { return commitStatusConfig(), nil },
+			func(string) ([]uploadResult, error) { return nil, errors.New("report must not be read") },
+			func(postPushConfig) GitHubAPI { return api },
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if api.sha != "abc" || len(api.statuses) != 1 || api.statuses[0].State != "pending" {
+			t.Fatalf("expected a pending status on abc, got %q %+v", api.sha, api.statuses)
+		}
+	})
+
+	t.Run("final state from the report", func(t *testing.T) {
+		cfg := commitStatusConfig()
+		cfg.PushOutcome = "success"
+		api := &fakeGitHubAPI{}
+		err := runCommitStatus(
+			func() (postPushConfig, error) { return cfg, nil },
+			func(dir string) ([]uploadResult, error) {
+				return []uploadResult{{File: "en.json", Status: resultStatusFailed, Error: "boom"}}, nil
+			},
+			func(postPushConfig) GitHubAPI { return api },
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.statuses) != 1 || api.statuses[0].State != "failure" {
+			t.Fatalf("expected a failure status, got %+v", api.statuses)
+		}
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		api := &fakeGitHubAPI{err: &statusError{StatusCode: 404, Body: "Not Found"}}
+		err := runCommitStatus(
+			func() (postPushConfig, error) { return commitStatusConfig(), nil },
+			loadUploadResults,
+			func(postPushConfig) GitHubAPI { return api },
+		)
+		if err == nil || !strings.Contains(err.Error(), "cannot set commit status") {
+			t.Fatalf("expected a commit status error, got %v", err)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		cfg := commitStatusConfig()
+		cfg.Repository = ""
+		err := runCommitStatus(func() (postPushConfig, error) { return cfg, nil }, loadUploadResults, func(postPushConfig) GitHubAPI {
+			t.Fatal("the API must not be used with an invalid config")
+			return nil
+		})
+		if err == nil || !strings.Contains(err.Error(), "GITHUB_REPOSITORY") {
+			t.Fatalf("expected a validation error, got %v", err)
+		}
+	})
+}
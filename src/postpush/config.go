@@ -0,0 +1,335 @@
+package postpush
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bodrovis/lokalise-actions-common/v2/parsers"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/ghcontext"
+	"lokalise-push-action/internal/projectmap"
+)
+
+const (
+	defaultMaxRetries       = 3   // Default number of retries on rate limits.
+	defaultInitialSleepTime = 1   // Initial backoff in seconds; client applies exponential backoff.
+	maxSleepTime            = 60  // Maximum backoff in seconds.
+	defaultTimeout          = 300 // Total timeout for all post-push integrations in seconds.
+	defaultHTTPTimeout      = 120 // Per-request HTTP timeout in seconds.
+
+	defaultGitHubAPIURL = "https://api.github.com"
+)
+
+// postPushConfig aggregates the inputs used after all files have been pushed.
+type postPushConfig struct {
+	ReportDir string
+	ProjectID string
+	Token     string
+	// ProjectTokens holds the dedicated tokens PROJECT_MAPPINGS, or the
+	// project_mappings of the pushed units, set for some projects; the others
+	// use Token.
+	ProjectTokens map[string]string
+	Repository    string
+	Branch        string
+	SHA           string
+	RunID         string
+	PRNumber      string
+	ServerURL     string
+	BaseLang      string
+	WebhookURL    string
+	WebhookSecret string
+	// WebhookFormat, WebhookTemplate, and WebhookTemplateFile shape the webhook body.
+	WebhookFormat       string
+	WebhookTemplate     string
+	WebhookTemplateFile string
+	// SlackWebhookURL is the Slack incoming webhook notified by Notify.
+	SlackWebhookURL string
+
+	// HeadSHA is the commit check runs and commit statuses are attached to.
+	HeadSHA      string
+	GitHubAPIURL string
+	GitHubToken  string
+	// CheckRunName names the check run reporting the push.
+	CheckRunName string
+	// CommitStatusContext is the context of the commit status reporting the push.
+	CommitStatusContext string
+	// PushOutcome is the outcome of the push step: success, failure,
+	// cancelled, or skipped. Empty when unknown.
+	PushOutcome string
+
+	TaskTitle string
+	// KeyContextFile is the repo-relative sidecar with key descriptions and char limits.
+	KeyContextFile string
+	// KeyTagsFile is the repo-relative mapping of key name patterns to extra tags.
+	KeyTagsFile string
+	// ScreenshotsDir and ScreenshotsMapping configure screenshot sync.
+	ScreenshotsDir     string
+	ScreenshotsMapping string
+	TaskGroupIDs       []int64
+	// DuplicateValuesFile receives the JSON report of DuplicateValues, the
+	// source strings shared by several keys.
+	DuplicateValuesFile string
+
+	ProjectStats    bool
+	DuplicateValues bool
+	CreateTask      bool
+	CommentNewKeys  bool
+	SkipTagging     bool
+
+	MaxRetries       int
+	InitialSleepTime time.Duration
+	MaxSleepTime     time.Duration
+	Timeout          time.Duration
+	HTTPTimeout      time.Duration
+}
+
+// prepareConfig reads env vars, validates booleans, and trims strings into a postPushConfig.
+// Only the first LOKALISE_PROJECT_ID entry (the primary project) is kept.
+// All invalid variables are reported together rather than stopping at the first one.
+func prepareConfig() (postPushConfig, error) {
+	var errs []error
+
+	projectStats, err := envconf.ParseBoolEnv("PROJECT_STATS")
+	errs = append(errs, err)
+
+	duplicateValues, err := envconf.ParseBoolEnv("DUPLICATE_VALUES")
+	errs = append(errs, err)
+
+	createTask, err := envconf.ParseBoolEnv("CREATE_TASK")
+	errs = append(errs, err)
+
+	commentNewKeys, err := envconf.ParseBoolEnv("COMMENT_NEW_KEYS")
+	errs = append(errs, err)
+
+	skipTagging, err := envconf.ParseBoolEnv("SKIP_TAGGING")
+	errs = append(errs, err)
+
+	taskGroupIDs, err := parseGroupIDs(os.Getenv("TASK_GROUP_IDS"))
+	errs = append(errs, err)
+
+	taskTitle := strings.TrimSpace(os.Getenv("TASK_TITLE"))
+	if taskTitle == "" {
+		taskTitle = defaultTaskTitle
+	}
+
+	keyContextFile, err := parseOptionalRepoPath("KEY_CONTEXT_FILE")
+	errs = append(errs, err)
+
+	keyTagsFile, err := parseOptionalRepoPath("KEY_TAGS_FILE")
+	errs = append(errs, err)
+
+	screenshotsDir, err := parseOptionalRepoPath("SCREENSHOTS_DIR")
+	errs = append(errs, err)
+
+	screenshotsMapping, err := parseOptionalRepoPath("SCREENSHOTS_MAPPING")
+	errs = append(errs, err)
+
+	webhookFormat, err := parseWebhookFormat(os.Getenv("WEBHOOK_FORMAT"))
+	errs = append(errs, err)
+
+	webhookTemplateFile, err := parseOptionalRepoPath("WEBHOOK_TEMPLATE_FILE")
+	errs = append(errs, err)
+
+	initialSleepTime, err := envconf.ParseDurationEnv("SLEEP_TIME", defaultInitialSleepTime*time.Second)
+	errs = append(errs, err)
+
+	timeout, err := envconf.ParseDurationEnv("POST_PUSH_TIMEOUT", defaultTimeout*time.Second)
+	errs = append(errs, err)
+
+	httpTimeout, err := envconf.ParseDurationEnv("HTTP_TIMEOUT", defaultHTTPTimeout*time.Second)
+	errs = append(errs, err)
+
+	rawProjectIDs, err := envconf.EnvOrFile("LOKALISE_PROJECT_ID")
+	errs = append(errs, err)
+
+	token, err := envconf.EnvOrFile("LOKALISE_API_TOKEN")
+	errs = append(errs, err)
+
+	mappings, err := projectmap.Parse()
+	errs = append(errs, err)
+
+	unitTokens, err := unitProjectTokens(os.Getenv("UNITS_REPORT"))
+	errs = append(errs, err)
+
+	gh, err := ghcontext.Read(os.Getenv)
+	errs = append(errs, err)
+
+	pushOutcome, err := parsePushOutcome(os.Getenv("PUSH_OUTCOME"))
+	errs = append(errs, err)
+
+	githubToken, err := envconf.EnvOrFile("GITHUB_TOKEN")
+	errs = append(errs, err)
+
+	githubAPIURL := gh.APIURL
+	if githubAPIURL == "" {
+		githubAPIURL = defaultGitHubAPIURL
+	}
+
+	checkRunName := strings.TrimSpace(os.Getenv("CHECK_RUN_NAME"))
+	if checkRunName == "" {
+		checkRunName = defaultCheckRunName
+	}
+
+	commitStatusContext := strings.TrimSpace(os.Getenv("COMMIT_STATUS_CONTEXT"))
+	if commitStatusContext == "" {
+		commitStatusContext = defaultCommitStatusContext
+	}
+
+	if err := envconf.Join(errs); err != nil {
+		return postPushConfig{}, err
+	}
+
+	projectID, _ := envconf.SplitProjectIDs(rawProjectIDs)
+
+	return postPushConfig{
+		ReportDir:     strings.TrimSpace(os.Getenv("REPORT_DIR")),
+		ProjectID:     projectID,
+		Token:         strings.TrimSpace(token),
+		ProjectTokens: mergeTokens(projectmap.TokensByProject(mappings), unitTokens),
+		Repository:    gh.Repository,
+		Branch:        gh.RefName,
+		SHA:           gh.SHA,
+		RunID:         gh.RunID,
+		PRNumber:      gh.PRNumber,
+		ServerURL:     gh.ServerURL,
+		BaseLang:      strings.TrimSpace(os.Getenv("BASE_LANG")),
+		WebhookURL:    strings.TrimSpace(os.Getenv("WEBHOOK_URL")),
+		WebhookSecret: strings.TrimSpace(os.Getenv("WEBHOOK_SECRET")),
+
+		WebhookFormat:       webhookFormat,
+		WebhookTemplate:     strings.TrimSpace(os.Getenv("WEBHOOK_TEMPLATE")),
+		WebhookTemplateFile: webhookTemplateFile,
+
+		SlackWebhookURL: strings.TrimSpace(os.Getenv("SLACK_WEBHOOK_URL")),
+
+		HeadSHA:             gh.HeadSHA,
+		GitHubAPIURL:        strings.TrimSuffix(githubAPIURL, "/"),
+		GitHubToken:         strings.TrimSpace(githubToken),
+		CheckRunName:        checkRunName,
+		CommitStatusContext: commitStatusContext,
+		PushOutcome:         pushOutcome,
+
+		TaskTitle:          taskTitle,
+		KeyContextFile:     keyContextFile,
+		KeyTagsFile:        keyTagsFile,
+		ScreenshotsDir:     screenshotsDir,
+		ScreenshotsMapping: screenshotsMapping,
+		TaskGroupIDs:       taskGroupIDs,
+
+		DuplicateValuesFile: strings.TrimSpace(os.Getenv("DUPLICATE_VALUES_FILE")),
+
+		ProjectStats:    projectStats,
+		DuplicateValues: duplicateValues,
+		CreateTask:      createTask,
+		CommentNewKeys:  commentNewKeys,
+		SkipTagging:     skipTagging,
+
+		MaxRetries:       parsers.ParseUintEnv("MAX_RETRIES", defaultMaxRetries),
+		InitialSleepTime: initialSleepTime,
+		MaxSleepTime:     time.Duration(maxSleepTime) * time.Second,
+		Timeout:          timeout,
+		HTTPTimeout:      httpTimeout,
+	}, nil
+}
+
+// validate performs input sanity checks before any network calls.
+func validate(cfg postPushConfig) error {
+	var errs []error
+	if cfg.ReportDir == "" {
+		errs = append(errs, fmt.Errorf("report directory (REPORT_DIR) is required and cannot be empty"))
+	}
+	if cfg.WebhookURL != "" {
+		errs = append(errs, validateWebhookURL("webhook_url", cfg.WebhookURL))
+		errs = append(errs, validateWebhookTemplate(cfg))
+	}
+	if cfg.CreateTask {
+		errs = append(errs, validateTaggedKeyInputs(cfg, "create_task"))
+		if len(cfg.TaskGroupIDs) == 0 {
+			errs = append(errs, fmt.Errorf("task_group_ids is required when create_task is enabled"))
+		}
+	}
+	if cfg.CommentNewKeys {
+		errs = append(errs, validateTaggedKeyInputs(cfg, "comment_new_keys"))
+	}
+	if cfg.KeyTagsFile != "" {
+		errs = append(errs, validateTaggedKeyInputs(cfg, "key_tags_file"))
+	}
+	if cfg.ScreenshotsMapping != "" && cfg.ScreenshotsDir == "" {
+		errs = append(errs, fmt.Errorf("screenshots_mapping requires screenshots_dir"))
+	}
+	return envconf.Join(errs)
+}
+
+// validateTaggedKeyInputs ensures integrations that select keys by the branch tag can find them.
+func validateTaggedKeyInputs(cfg postPushConfig, input string) error {
+	if cfg.SkipTagging {
+		return fmt.Errorf("%s requires tagging: keys are selected by the branch tag, so skip_tagging must be false", input)
+	}
+	if cfg.Branch == "" {
+		return fmt.Errorf("GitHub reference name (GITHUB_HEAD_REF or GITHUB_REF_NAME) is required when %s is enabled", input)
+	}
+	return nil
+}
+
+// validateWebhookTemplate ensures at most one source shapes the webhook body
+// and that its template parses.
+func validateWebhookTemplate(cfg postPushConfig) error {
+	sources := 0
+	for _, set := range []bool{cfg.WebhookTemplate != "", cfg.WebhookTemplateFile != "", cfg.WebhookFormat == webhookFormatTeams} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return fmt.Errorf("webhook_template, webhook_template_file, and webhook_format are mutually exclusive")
+	}
+	_, err := loadWebhookTemplate(cfg)
+	return err
+}
+
+// validateWebhookURL accepts absolute http(s) URLs only in the given input.
+func validateWebhookURL(input, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return fmt.Errorf("invalid %s: expected an absolute http(s) URL", input)
+	}
+	return nil
+}
+
+// parsePushOutcome reads the outcome of the push step as reported by
+// steps.<id>.outcome; empty means unknown.
+func parsePushOutcome(raw string) (string, error) {
+	switch outcome := strings.ToLower(strings.TrimSpace(raw)); outcome {
+	case "", "success", "failure", "cancelled", "skipped":
+		return outcome, nil
+	default:
+		return "", fmt.Errorf("invalid PUSH_OUTCOME %q: expected success, failure, cancelled, or skipped", raw)
+	}
+}
+
+// tokenFor returns the API token for projectID: the one PROJECT_MAPPINGS sets
+// for it, or the default token.
+func (c postPushConfig) tokenFor(projectID string) string {
+	if token, ok := c.ProjectTokens[projectID]; ok {
+		return token
+	}
+	return c.Token
+}
+
+// parseOptionalRepoPath reads a repo-relative file path from key; empty means unset.
+func parseOptionalRepoPath(key string) (string, error) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return "", nil
+	}
+
+	path, err := parsers.EnsureRepoRelativePath(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,258 @@
+package postpush
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrepareConfig(t *testing.T) {
+	t.Setenv("REPORT_DIR", "  /tmp/report  ")
+	t.Setenv("LOKALISE_PROJECT_ID", " , proj_1, proj_2 ")
+	t.Setenv("PROJECT_STATS", "true")
+	t.Setenv("DUPLICATE_VALUES", "true")
+	t.Setenv("DUPLICATE_VALUES_FILE", " /tmp/duplicates.json ")
+	t.Setenv("GITHUB_REF", "refs/pull/17/merge")
+	t.Setenv("BASE_LANG", " en ")
+	t.Setenv("CREATE_TASK", "true")
+	t.Setenv("COMMENT_NEW_KEYS", "true")
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("SKIP_TAGGING", "false")
+	t.Setenv("TASK_TITLE", "")
+	t.Setenv("TASK_GROUP_IDS", "12, 34")
+	t.Setenv("KEY_CONTEXT_FILE", " ./i18n/context.yml ")
+	t.Setenv("KEY_TAGS_FILE", "i18n/tags.yml")
+	t.Setenv("SCREENSHOTS_DIR", "docs/screens/")
+	t.Setenv("SCREENSHOTS_MAPPING", "docs/screens.yml")
+	t.Setenv("LOKALISE_API_TOKEN", " tok ")
+	t.Setenv("GITHUB_REPOSITORY", "acme/app")
+	t.Setenv("GITHUB_HEAD_REF", "")
+	t.Setenv("GITHUB_REF_NAME", "main")
+	t.Setenv("GITHUB_SHA", "abc123")
+	t.Setenv("GITHUB_RUN_ID", "42")
+	t.Setenv("WEBHOOK_URL", " https://example.com/hook ")
+	t.Setenv("WEBHOOK_SECRET", " s3cret ")
+	t.Setenv("WEBHOOK_FORMAT", " Teams ")
+	t.Setenv("WEBHOOK_TEMPLATE", "")
+	t.Setenv("WEBHOOK_TEMPLATE_FILE", "")
+	t.Setenv("SLACK_WEBHOOK_URL", " https://hooks.slack.com/services/T/B/x ")
+	t.Setenv("MAX_RETRIES", "5")
+	t.Setenv("SLEEP_TIME", "")
+	t.Setenv("POST_PUSH_TIMEOUT", "30")
+	t.Setenv("HTTP_TIMEOUT", "")
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	t.Setenv("GITHUB_API_URL", "https://ghe.example.com/api/v3/")
+	t.Setenv("GITHUB_TOKEN", " ghs_x ")
+	t.Setenv("GITHUB_TOKEN_FILE", "")
+	t.Setenv("CHECK_RUN_NAME", "")
+	t.Setenv("COMMIT_STATUS_CONTEXT", " ci/lokalise ")
+	t.Setenv("PUSH_OUTCOME", "failure")
+
+	got, err := prepareConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := postPushConfig{
+		ReportDir:           "/tmp/report",
+		ProjectID:           "proj_1",
+		Token:               "tok",
+		Repository:          "acme/app",
+		Branch:              "main",
+		SHA:                 "abc123",
+		RunID:               "42",
+		PRNumber:            "17",
+		ServerURL:           "https://github.com",
+		BaseLang:            "en",
+		WebhookURL:          "https://example.com/hook",
+		WebhookSecret:       "s3cret",
+		WebhookFormat:       webhookFormatTeams,
+		SlackWebhookURL:     "https://hooks.slack.com/services/T/B/x",
+		HeadSHA:             "abc123",
+		GitHubAPIURL:        "https://ghe.example.com/api/v3",
+		GitHubToken:         "ghs_x",
+		CheckRunName:        defaultCheckRunName,
+		CommitStatusContext: "ci/lokalise",
+		PushOutcome:         "failure",
+		TaskTitle:           defaultTaskTitle,
+		TaskGroupIDs:        []int64{12, 34},
+		KeyContextFile:      "i18n/context.yml",
+		KeyTagsFile:         "i18n/tags.yml",
+		ScreenshotsDir:      "docs/screens",
+		ScreenshotsMapping:  "docs/screens.yml",
+		DuplicateValuesFile: "/tmp/duplicates.json",
+		ProjectStats:        true,
+		DuplicateValues:     true,
+		CreateTask:          true,
+		CommentNewKeys:      true,
+		MaxRetries:          5,
+		InitialSleepTime:    defaultInitialSleepTime * time.Second,
+		MaxSleepTime:        maxSleepTime * time.Second,
+		Timeout:             30 * time.Second,
+		HTTPTimeout:         defaultHTTPTimeout * time.Second,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+
+	t.Run("head ref wins for pull requests", func(t *testing.T) {
+		t.Setenv("GITHUB_HEAD_REF", "feature/x")
+
+		got, err := prepareConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Branch != "feature/x" {
+			t.Fatalf("expected branch %q, got %q", "feature/x", got.Branch)
+		}
+	})
+
+	t.Run("timeouts accept duration syntax", func(t *testing.T) {
+		t.Setenv("SLEEP_TIME", "250ms")
+		t.Setenv("POST_PUSH_TIMEOUT", "10m")
+		t.Setenv("HTTP_TIMEOUT", "1m30s")
+
+		got, err := prepareConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.InitialSleepTime != 250*time.Millisecond || got.Timeout != 10*time.Minute || got.HTTPTimeout != 90*time.Second {
+			t.Fatalf("unexpected durations: sleep=%v timeout=%v http=%v", got.InitialSleepTime, got.Timeout, got.HTTPTimeout)
+		}
+	})
+}
+
+func TestPrepareConfig_InvalidInputs(t *testing.T) {
+	tests := []struct {
+		key, value, wantErr string
+	}{
+		{"PROJECT_STATS", "maybe", "invalid PROJECT_STATS"},
+		{"DUPLICATE_VALUES", "maybe", "invalid DUPLICATE_VALUES"},
+		{"CREATE_TASK", "maybe", "invalid CREATE_TASK"},
+		{"COMMENT_NEW_KEYS", "maybe", "invalid COMMENT_NEW_KEYS"},
+		{"SKIP_TAGGING", "maybe", "invalid SKIP_TAGGING"},
+		{"TASK_GROUP_IDS", "12,abc", "invalid task_group_ids"},
+		{"KEY_CONTEXT_FILE", "../context.yml", "invalid KEY_CONTEXT_FILE"},
+		{"KEY_TAGS_FILE", "~/tags.yml", "invalid KEY_TAGS_FILE"},
+		{"SCREENSHOTS_DIR", "/abs/dir", "invalid SCREENSHOTS_DIR"},
+		{"SCREENSHOTS_MAPPING", "../map.yml", "invalid SCREENSHOTS_MAPPING"},
+		{"SLEEP_TIME", "soon", "invalid SLEEP_TIME"},
+		{"POST_PUSH_TIMEOUT", "0s", "invalid POST_PUSH_TIMEOUT"},
+		{"HTTP_TIMEOUT", "-1", "invalid HTTP_TIMEOUT"},
+		{"PUSH_OUTCOME", "done", "invalid PUSH_OUTCOME"},
+		{"WEBHOOK_FORMAT", "xml", "invalid WEBHOOK_FORMAT"},
+		{"WEBHOOK_TEMPLATE_FILE", "../hook.tmpl", "invalid WEBHOOK_TEMPLATE_FILE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			for _, k := range []string{"PROJECT_STATS", "DUPLICATE_VALUES", "CREATE_TASK", "COMMENT_NEW_KEYS", "SKIP_TAGGING", "TASK_GROUP_IDS", "KEY_CONTEXT_FILE", "KEY_TAGS_FILE", "SCREENSHOTS_DIR", "SCREENSHOTS_MAPPING", "SLEEP_TIME", "POST_PUSH_TIMEOUT", "HTTP_TIMEOUT", "PUSH_OUTCOME", "WEBHOOK_FORMAT", "WEBHOOK_TEMPLATE_FILE"} {
+				t.Setenv(k, "")
+			}
+			t.Setenv(tt.key, tt.value)
+
+			_, err := prepareConfig()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPrepareConfig_CustomTaskTitle(t *testing.T) {
+	t.Setenv("TASK_TITLE", "  Review {branch}  ")
+
+	got, err := prepareConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.TaskTitle != "Review {branch}" {
+		t.Fatalf("unexpected task title %q", got.TaskTitle)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     postPushConfig
+		wantErr string
+	}{
+		{name: "report dir only", cfg: postPushConfig{ReportDir: "r"}},
+		{name: "valid webhook", cfg: postPushConfig{ReportDir: "r", WebhookURL: "https://example.com/hook"}},
+		{name: "missing report dir", cfg: postPushConfig{}, wantErr: "REPORT_DIR"},
+		{name: "relative webhook URL", cfg: postPushConfig{ReportDir: "r", WebhookURL: "/hook"}, wantErr: "invalid webhook_url"},
+		{name: "unsupported scheme", cfg: postPushConfig{ReportDir: "r", WebhookURL: "ftp://example.com"}, wantErr: "invalid webhook_url"},
+		{name: "webhook template", cfg: postPushConfig{ReportDir: "r", WebhookURL: "https://example.com/hook", WebhookTemplate: `{"text": {{ json .Summary }}}`}},
+		{name: "invalid webhook template", cfg: postPushConfig{ReportDir: "r", WebhookURL: "https://example.com/hook", WebhookTemplate: "{{ .Files"}, wantErr: "invalid webhook template"},
+		{name: "template conflicts with format", cfg: postPushConfig{ReportDir: "r", WebhookURL: "https://example.com/hook", WebhookTemplate: "{}", WebhookFormat: webhookFormatTeams}, wantErr: "mutually exclusive"},
+		{name: "missing template file", cfg: postPushConfig{ReportDir: "r", WebhookURL: "https://example.com/hook", WebhookTemplateFile: "missing.tmpl"}, wantErr: "cannot read webhook template"},
+		{name: "valid task inputs", cfg: postPushConfig{ReportDir: "r", CreateTask: true, Branch: "main", TaskGroupIDs: []int64{1}}},
+		{name: "task requires tagging", cfg: postPushConfig{ReportDir: "r", CreateTask: true, SkipTagging: true, Branch: "main", TaskGroupIDs: []int64{1}}, wantErr: "skip_tagging must be false"},
+		{name: "task requires branch", cfg: postPushConfig{ReportDir: "r", CreateTask: true, TaskGroupIDs: []int64{1}}, wantErr: "GITHUB_HEAD_REF"},
+		{name: "comments require tagging", cfg: postPushConfig{ReportDir: "r", CommentNewKeys: true, SkipTagging: true, Branch: "main"}, wantErr: "comment_new_keys requires tagging"},
+		{name: "comments require branch", cfg: postPushConfig{ReportDir: "r", CommentNewKeys: true}, wantErr: "when comment_new_keys is enabled"},
+		{name: "key tags require tagging", cfg: postPushConfig{ReportDir: "r", KeyTagsFile: "t.yml", SkipTagging: true, Branch: "main"}, wantErr: "key_tags_file requires tagging"},
+		{name: "screenshots mapping requires dir", cfg: postPushConfig{ReportDir: "r", ScreenshotsMapping: "m.yml"}, wantErr: "screenshots_mapping requires screenshots_dir"},
+		{name: "task requires groups", cfg: postPushConfig{ReportDir: "r", CreateTask: true, Branch: "main"}, wantErr: "task_group_ids is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate(tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPrepareConfig_FileIndirection(t *testing.T) {
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenPath, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	projectPath := filepath.Join(dir, "project")
+	if err := os.WriteFile(projectPath, []byte("proj_9,proj_10\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("LOKALISE_API_TOKEN", "env-token")
+	t.Setenv("LOKALISE_API_TOKEN_FILE", tokenPath)
+	t.Setenv("LOKALISE_PROJECT_ID", "proj_1")
+	t.Setenv("LOKALISE_PROJECT_ID_FILE", projectPath)
+
+	got, err := prepareConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Token != "file-token" || got.ProjectID != "proj_9" {
+		t.Fatalf("expected values from files, got token %q, project %q", got.Token, got.ProjectID)
+	}
+}
+
+func TestPrepareConfig_ReportsAllErrors(t *testing.T) {
+	t.Setenv("PROJECT_STATS", "maybe")
+	t.Setenv("TASK_GROUP_IDS", "12,abc")
+	t.Setenv("HTTP_TIMEOUT", "-1")
+	t.Setenv("PROJECT_MAPPINGS", "apps=")
+
+	_, err := prepareConfig()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"4 configuration problems", "invalid PROJECT_STATS", "invalid task_group_ids", "invalid HTTP_TIMEOUT", `invalid project_mappings: line 1: project ID for "apps" is empty`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %q", want, err.Error())
+		}
+	}
+}
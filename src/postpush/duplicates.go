@@ -0,0 +1,150 @@
+package postpush
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"lokalise-push-action/internal/ghoutput"
+	"lokalise-push-action/internal/keyfile"
+)
+
+// maxSummaryDuplicates caps the duplicate groups listed in the job summary;
+// the JSON report always has all of them.
+const maxSummaryDuplicates = 50
+
+// duplicateKey is one key holding a duplicated value.
+type duplicateKey struct {
+	File string `json:"file"`
+	Key  string `json:"key"`
+	Line int    `json:"line,omitempty"`
+}
+
+// duplicateValue is a source string shared by several keys.
+type duplicateValue struct {
+	Value string         `json:"value"`
+	Keys  []duplicateKey `json:"keys"`
+}
+
+// duplicateReport is the JSON report written to DUPLICATE_VALUES_FILE.
+type duplicateReport struct {
+	Files      []string         `json:"files"`
+	Duplicates []duplicateValue `json:"duplicates"`
+}
+
+// findDuplicateValues groups the keys of the pushed base-language files by
+// value and returns the values held by more than one key, the most shared
+// first. Values are compared after trimming whitespace; empty values, plural
+// and array items, and files in unsupported formats are ignored.
+func findDuplicateValues(cfg postPushConfig, results []uploadResult) duplicateReport {
+	report := duplicateReport{Files: []string{}, Duplicates: []duplicateValue{}}
+
+	byValue := make(map[string][]duplicateKey)
+	seen := make(map[string]bool)
+	for _, res := range results {
+		if res.Status != resultStatusUploaded || seen[res.File] || (cfg.BaseLang != "" && res.LangISO != cfg.BaseLang) {
+			continue
+		}
+		seen[res.File] = true
+
+		_, entries, err := keyfile.Parse(res.File)
+		if errors.Is(err, keyfile.ErrUnsupported) {
+			continue
+		}
+		if err != nil {
+			logs.Warnf("cannot check %q for duplicate values: %v", res.File, err)
+			continue
+		}
+		report.Files = append(report.Files, res.File)
+
+		for _, e := range entries {
+			value := strings.TrimSpace(e.Value)
+			if value == "" || e.Parent != "" {
+				continue
+			}
+			byValue[value] = append(byValue[value], duplicateKey{File: res.File, Key: e.Key, Line: e.Line})
+		}
+	}
+
+	for value, keys := range byValue {
+		if len(keys) > 1 {
+			report.Duplicates = append(report.Duplicates, duplicateValue{Value: value, Keys: keys})
+		}
+	}
+	sort.Slice(report.Duplicates, func(i, j int) bool {
+		a, b := report.Duplicates[i], report.Duplicates[j]
+		if len(a.Keys) != len(b.Keys) {
+			return len(a.Keys) > len(b.Keys)
+		}
+		return a.Value < b.Value
+	})
+
+	return report
+}
+
+// renderDuplicatesSummary renders the report as Markdown for the job summary.
+func renderDuplicatesSummary(report duplicateReport) string {
+	var b strings.Builder
+	b.WriteString("### Duplicate source strings\n\n")
+	if len(report.Duplicates) == 0 {
+		fmt.Fprintf(&b, "No value is shared by several keys in %d pushed file(s).\n", len(report.Files))
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%d value(s) are shared by several keys in %d pushed file(s); they may be candidates for consolidation.\n\n", len(report.Duplicates), len(report.Files))
+	b.WriteString("| Value | Keys |\n")
+	b.WriteString("| --- | --- |\n")
+	for i, d := range report.Duplicates {
+		if i == maxSummaryDuplicates {
+			fmt.Fprintf(&b, "\n%d more value(s) are listed in the JSON report.\n", len(report.Duplicates)-i)
+			break
+		}
+		keys := make([]string, len(d.Keys))
+		for j, k := range d.Keys {
+			keys[j] = fmt.Sprintf("`%s` (%s)", escapeTableCell(k.Key), escapeTableCell(k.File))
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", escapeTableCell(d.Value), strings.Join(keys, "<br>"))
+	}
+	return b.String()
+}
+
+// writeDuplicateReport writes the report as JSON to path, creating its directory.
+func writeDuplicateReport(path string, report duplicateReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode duplicate values report: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("cannot create duplicate values report directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("cannot write duplicate values report: %w", err)
+	}
+	return nil
+}
+
+// reportDuplicateValues publishes the values shared by several keys in the
+// job summary, in the JSON report at DUPLICATE_VALUES_FILE, and as outputs.
+// Like the project statistics it is best-effort: problems are only warned about.
+func reportDuplicateValues(cfg postPushConfig, results []uploadResult, write func(string, string) bool) {
+	report := findDuplicateValues(cfg, results)
+	logs.Infof("Found %d duplicate value(s) in %d pushed file(s)", len(report.Duplicates), len(report.Files))
+	write("duplicate_values_count", strconv.Itoa(len(report.Duplicates)))
+
+	if cfg.DuplicateValuesFile != "" {
+		if err := writeDuplicateReport(cfg.DuplicateValuesFile, report); err != nil {
+			logs.Warnf("%v", err)
+		} else {
+			write("duplicate_values_file", cfg.DuplicateValuesFile)
+		}
+	}
+
+	if err := ghoutput.AppendStepSummary(renderDuplicatesSummary(report)); err != nil {
+		logs.Warnf("%v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package postpush
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeTranslationFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestFindDuplicateValues(t *testing.T) {
+	dir := t.TempDir()
+	common := writeTranslationFile(t, dir, "en.json", "{\n  \"save\": \"Save\",\n  \"form\": {\n    \"submit\": \" Save \"\n  },\n  \"cancel\": \"Cancel\",\n  \"empty\": \"\"\n}\n")
+	admin := writeTranslationFile(t, dir, "admin.yml", "admin:\n  store: Save\n  abort: Cancel\n  blank: ''\n")
+	french := writeTranslationFile(t, dir, "fr.json", `{"enregistrer": "Save", "sauver": "Save"}`)
+	other := writeTranslationFile(t, dir, "en.po", "msgid \"a\"\nmsgstr \"Save\"\n")
+	android := writeTranslationFile(t, dir, "strings.xml", `<resources>
+    <plurals name="files">
+        <item quantity="many">%d files</item>
+        <item quantity="other">%d files</item>
+    </plurals>
+</resources>`)
+
+	results := []uploadResult{
+		{File: common, ProjectID: "p1", LangISO: "en", Status: resultStatusUploaded},
+		{File: common, ProjectID: "mirror", LangISO: "en", Status: resultStatusUploaded},
+		{File: admin, ProjectID: "p1", LangISO: "en", Status: resultStatusUploaded},
+		{File: french, ProjectID: "p1", LangISO: "fr", Status: resultStatusUploaded},
+		{File: other, ProjectID: "p1", LangISO: "en", Status: resultStatusUploaded},
+		{File: android, ProjectID: "p1", LangISO: "en", Status: resultStatusUploaded},
+		{File: filepath.Join(dir, "failed.json"), ProjectID: "p1", LangISO: "en", Status: resultStatusFailed},
+	}
+
+	got := findDuplicateValues(postPushConfig{BaseLang: "en"}, results)
+
+	want := duplicateReport{
+		Files: []string{common, admin, android},
+		Duplicates: []duplicateValue{
+			{Value: "Save", Keys: []duplicateKey{
+				{File: common, Key: "save", Line: 2},
+				{File: common, Key: "form.submit", Line: 4},
+				{File: admin, Key: "admin.store", Line: 2},
+			}},
+			{Value: "Cancel", Keys: []duplicateKey{
+				{File: common, Key: "cancel", Line: 6},
+				{File: admin, Key: "admin.abort", Line: 3},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestRenderDuplicatesSummary(t *testing.T) {
+	t.Run("no duplicates", func(t *testing.T) {
+		got := renderDuplicatesSummary(duplicateReport{Files: []string{"en.json"}})
+		if !strings.Contains(got, "No value is shared by several keys in 1 pushed file(s).") {
+			t.Fatalf("unexpected summary %q", got)
+		}
+	})
+
+	t.Run("lists values and keys", func(t *testing.T) {
+		got := renderDuplicatesSummary(duplicateReport{
+			Files: []string{"en.json"},
+			Duplicates: []duplicateValue{{Value: "Yes | No", Keys: []duplicateKey{
+				{File: "en.json", Key: "a"},
+				{File: "en.json", Key: "b"},
+			}}},
+		})
+		if !strings.Contains(got, "| Yes \\| No | `a` (en.json)<br>`b` (en.json) |") {
+			t.Fatalf("unexpected summary %q", got)
+		}
+	})
+
+	t.Run("caps the listed values", func(t *testing.T) {
+		report := duplicateReport{Files: []string{"en.json"}}
+		for range maxSummaryDuplicates + 3 {
+			report.Duplicates = append(report.Duplicates, duplicateValue{Value: "x", Keys: []duplicateKey{{Key: "a"}, {Key: "b"}}})
+		}
+		got := renderDuplicatesSummary(report)
+		if !strings.Contains(got, "3 more value(s) are listed in the JSON report.") {
+			t.Fatalf("unexpected summary %q", got)
+		}
+	})
+}
+
+func TestReportDuplicateValues(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTranslationFile(t, dir, "en.json", `{"a": "Same", "b": "Same"}`)
+	summary := filepath.Join(dir, "summary.md")
+	reportPath := filepath.Join(dir, "out", "duplicates.json")
+
+	t.Setenv("GITHUB_STEP_SUMMARY", summary)
+	cfg := postPushConfig{DuplicateValues: true, DuplicateValuesFile: reportPath}
+	results := []uploadResult{{File: file, ProjectID: "p1", Status: resultStatusUploaded}}
+
+	outputs := map[string]string{}
+	write := func(k, v string) bool { outputs[k] = v; return true }
+
+	if err := runIntegrations(context.Background(), cfg, results, &fakeFactory{}, write); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if outputs["duplicate_values_count"] != "1" || outputs["duplicate_values_file"] != reportPath {
+		t.Fatalf("unexpected outputs: %v", outputs)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report duplicateReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if len(report.Duplicates) != 1 || report.Duplicates[0].Value != "Same" || len(report.Duplicates[0].Keys) != 2 {
+		t.Fatalf("unexpected report %#v", report)
+	}
+
+	data, err = os.ReadFile(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "### Duplicate source strings") {
+		t.Fatalf("unexpected summary: %s", data)
+	}
+}
@@ -0,0 +1,91 @@
+package postpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// GitHubAPI abstracts the GitHub endpoints used to report a push for testability.
+type GitHubAPI interface {
+	CreateCheckRun(ctx context.Context, run checkRun) (int64, error)
+	UpdateCheckRun(ctx context.Context, id int64, run checkRun) error
+	CreateStatus(ctx context.Context, sha string, status commitStatus) error
+}
+
+// newGitHubAPI creates the GitHub client for cfg. Overridable in tests.
+var newGitHubAPI = func(cfg postPushConfig) GitHubAPI {
+	return &githubAPI{
+		baseURL:    cfg.GitHubAPIURL,
+		repository: cfg.Repository,
+		token:      cfg.GitHubToken,
+		http:       &http.Client{Timeout: cfg.HTTPTimeout},
+	}
+}
+
+// githubAPI calls the GitHub REST API of one repository with a token, such
+// as the GITHUB_TOKEN of the workflow run.
+type githubAPI struct {
+	baseURL    string
+	repository string
+	token      string
+	http       *http.Client
+}
+
+func (a *githubAPI) CreateCheckRun(ctx context.Context, run checkRun) (int64, error) {
+	var resp struct {
+		ID int64 `json:"id"`
+	}
+	if err := a.send(ctx, http.MethodPost, "check-runs", run, &resp); err != nil {
+		return 0, err
+	}
+	return resp.ID, nil
+}
+
+func (a *githubAPI) UpdateCheckRun(ctx context.Context, id int64, run checkRun) error {
+	return a.send(ctx, http.MethodPatch, fmt.Sprintf("check-runs/%d", id), run, nil)
+}
+
+func (a *githubAPI) CreateStatus(ctx context.Context, sha string, status commitStatus) error {
+	return a.send(ctx, http.MethodPost, "statuses/"+sha, status, nil)
+}
+
+// send sends payload as JSON to repos/<repository>/<path> and decodes the
+// response into v unless it is nil. Non-2xx responses are a statusError.
+func (a *githubAPI) send(ctx context.Context, method, path string, payload, v any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("cannot encode request: %w", err)
+	}
+
+	url := a.baseURL + "/repos/" + a.repository + "/" + path
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "lokalise-push-action")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<12))
+		return &statusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	if v == nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
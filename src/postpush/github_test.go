@@ -0,0 +1,94 @@
+package postpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGitHubAPI_CheckRuns(t *testing.T) {
+	var gotMethods, gotPaths []string
+	var gotBodies []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer ghs_x" {
+			t.Errorf("unexpected authorization %q", got)
+		}
+		if got := r.Header.Get("Accept"); got != "application/vnd.github+json" {
+			t.Errorf("unexpected accept %q", got)
+		}
+		gotMethods = append(gotMethods, r.Method)
+		gotPaths = append(gotPaths, r.URL.Path)
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		gotBodies = append(gotBodies, body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":99}`))
+	}))
+	defer srv.Close()
+
+	api := newGitHubAPI(postPushConfig{GitHubAPIURL: srv.URL, Repository: "acme/app", GitHubToken: "ghs_x", HTTPTimeout: 5 * time.Second})
+
+	id, err := api.CreateCheckRun(context.Background(), checkRun{Name: "Lokalise push", HeadSHA: "abc", Status: "completed", Conclusion: "success"})
+	if err != nil || id != 99 {
+		t.Fatalf("expected check run 99, got %d (%v)", id, err)
+	}
+	if err := api.UpdateCheckRun(context.Background(), id, checkRun{Output: &checkRunOutput{Title: "t", Summary: "s"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotMethods[0] != http.MethodPost || gotPaths[0] != "/repos/acme/app/check-runs" {
+		t.Fatalf("unexpected create request %s %s", gotMethods[0], gotPaths[0])
+	}
+	if gotBodies[0]["head_sha"] != "abc" || gotBodies[0]["conclusion"] != "success" || gotBodies[0]["output"] != nil {
+		t.Fatalf("unexpected create body %v", gotBodies[0])
+	}
+	if gotMethods[1] != http.MethodPatch || gotPaths[1] != "/repos/acme/app/check-runs/99" {
+		t.Fatalf("unexpected update request %s %s", gotMethods[1], gotPaths[1])
+	}
+	if _, ok := gotBodies[1]["name"]; ok {
+		t.Fatalf("expected empty fields to be omitted from the update, got %v", gotBodies[1])
+	}
+}
+
+func TestGitHubAPI_CreateStatus(t *testing.T) {
+	var gotPath string
+	var got commitStatus
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	api := newGitHubAPI(postPushConfig{GitHubAPIURL: srv.URL, Repository: "acme/app", HTTPTimeout: 5 * time.Second})
+	status := commitStatus{State: "pending", Description: "Pushing", Context: defaultCommitStatusContext}
+	if err := api.CreateStatus(context.Background(), "abc", status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/repos/acme/app/statuses/abc" || got != status {
+		t.Fatalf("unexpected request %s %+v", gotPath, got)
+	}
+}
+
+func TestGitHubAPI_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Resource not accessible by integration"}`, http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	api := newGitHubAPI(postPushConfig{GitHubAPIURL: srv.URL, Repository: "acme/app", HTTPTimeout: 5 * time.Second})
+	_, err := api.CreateCheckRun(context.Background(), checkRun{Name: "x"})
+
+	var se *statusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected a 403 status error, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package postpush
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// keyContext is translator context for one key from the KEY_CONTEXT_FILE sidecar.
+// Nil fields are left unchanged on Lokalise.
+type keyContext struct {
+	Description *string `yaml:"description"`
+	CharLimit   *int    `yaml:"char_limit"`
+}
+
+// parseKeyContext parses a YAML (or JSON) mapping of key names to context:
+//
+//	welcome.title:
+//	  description: Greeting on the home screen
+//	  char_limit: 40
+func parseKeyContext(data []byte) (map[string]keyContext, error) {
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+
+	var entries map[string]keyContext
+	if err := dec.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("invalid key context file (must map key names to description/char_limit): %w", err)
+	}
+
+	out := make(map[string]keyContext, len(entries))
+	for name, c := range entries {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("invalid key context file: key name cannot be empty")
+		}
+		if c.Description == nil && c.CharLimit == nil {
+			return nil, fmt.Errorf("invalid key context for %q: description or char_limit is required", name)
+		}
+		if c.CharLimit != nil && *c.CharLimit < 0 {
+			return nil, fmt.Errorf("invalid key context for %q: char_limit cannot be negative", name)
+		}
+		if _, dup := out[name]; dup {
+			return nil, fmt.Errorf("invalid key context file: key %q is defined more than once", name)
+		}
+		out[name] = c
+	}
+
+	return out, nil
+}
+
+// loadKeyContext reads and parses the sidecar file at path.
+func loadKeyContext(path string) (map[string]keyContext, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read key context file: %w", err)
+	}
+	return parseKeyContext(data)
+}
+
+// buildKeyContextUpdates matches keys by any of their platform names and returns
+// the updates plus the sorted names that were not found in the project.
+func buildKeyContextUpdates(contexts map[string]keyContext, keys []lokaliseKey) ([]keyUpdate, []string) {
+	found := make(map[string]struct{}, len(contexts))
+	var updates []keyUpdate
+
+	for _, k := range keys {
+		for _, name := range k.KeyName.names() {
+			c, ok := contexts[name]
+			if !ok {
+				continue
+			}
+			updates = append(updates, keyUpdate{KeyID: k.KeyID, Description: c.Description, CharLimit: c.CharLimit})
+			found[name] = struct{}{}
+			break
+		}
+	}
+
+	var missing []string
+	for name := range contexts {
+		if _, ok := found[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	slices.Sort(missing)
+
+	return updates, missing
+}
+
+// applyKeyContext pushes descriptions and character limits from the sidecar file
+// to matching keys in every project that received files.
+func applyKeyContext(ctx context.Context, cfg postPushConfig, results []uploadResult, factory ClientFactory) error {
+	contexts, err := loadKeyContext(cfg.KeyContextFile)
+	if err != nil {
+		return err
+	}
+	if len(contexts) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(contexts))
+	for name := range contexts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	clients := make(map[string]LokaliseAPI)
+	for _, projectID := range pushedProjectIDs(cfg, results) {
+		api, err := cachedAPI(clients, factory, cfg, projectID)
+		if err != nil {
+			return err
+		}
+
+		keys, err := api.ListKeysByName(ctx, names)
+		if err != nil {
+			return fmt.Errorf("cannot look up keys in project %s: %w", projectID, err)
+		}
+
+		updates, missing := buildKeyContextUpdates(contexts, keys)
+		if len(missing) > 0 {
+			logs.Infof("Project %s: %d keys from the context file were not found: %s", projectID, len(missing), strings.Join(missing, ", "))
+		}
+		if len(updates) == 0 {
+			continue
+		}
+
+		if err := api.UpdateKeys(ctx, updates); err != nil {
+			return fmt.Errorf("cannot update key context in project %s: %w", projectID, err)
+		}
+		logs.Infof("Project %s: updated context for %d keys", projectID, len(updates))
+	}
+
+	return nil
+}
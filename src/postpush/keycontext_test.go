@@ -0,0 +1,159 @@
+package postpush
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseKeyContext(t *testing.T) {
+	t.Run("YAML mapping", func(t *testing.T) {
+		got, err := parseKeyContext([]byte(`
+welcome.title:
+  description: Greeting on the home screen
+  char_limit: 40
+" checkout.pay ":
+  char_limit: 0
+`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 entries, got %#v", got)
+		}
+		w := got["welcome.title"]
+		if w.Description == nil || *w.Description != "Greeting on the home screen" || w.CharLimit == nil || *w.CharLimit != 40 {
+			t.Fatalf("unexpected welcome.title: %#v", w)
+		}
+		c := got["checkout.pay"]
+		if c.Description != nil || c.CharLimit == nil || *c.CharLimit != 0 {
+			t.Fatalf("unexpected checkout.pay: %#v", c)
+		}
+	})
+
+	t.Run("JSON object", func(t *testing.T) {
+		got, err := parseKeyContext([]byte(`{"a": {"description": "x"}}`))
+		if err != nil || got["a"].Description == nil {
+			t.Fatalf("unexpected result %#v err=%v", got, err)
+		}
+	})
+
+	tests := []struct {
+		name, raw, wantErr string
+	}{
+		{"unknown field", "a:\n  notes: x\n", "invalid key context file"},
+		{"not a mapping", "- a\n", "invalid key context file"},
+		{"empty entry", "a: {}\n", "description or char_limit is required"},
+		{"negative limit", "a:\n  char_limit: -1\n", "cannot be negative"},
+		{"blank name", "\" \":\n  char_limit: 1\n", "key name cannot be empty"},
+		{"duplicate after trim", "a:\n  char_limit: 1\n\" a\":\n  char_limit: 2\n", "defined more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseKeyContext([]byte(tt.raw))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadKeyContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "context.yml")
+	if err := os.WriteFile(path, []byte("a:\n  char_limit: 5\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadKeyContext(path)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("unexpected result %#v err=%v", got, err)
+	}
+
+	if _, err := loadKeyContext(filepath.Join(t.TempDir(), "missing.yml")); err == nil || !strings.Contains(err.Error(), "cannot read key context file") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+func TestBuildKeyContextUpdates(t *testing.T) {
+	desc := "Greeting"
+	limit := 40
+	contexts := map[string]keyContext{
+		"welcome.title": {Description: &desc},
+		"ios_only":      {CharLimit: &limit},
+		"missing.b":     {CharLimit: &limit},
+		"missing.a":     {CharLimit: &limit},
+	}
+	keys := []lokaliseKey{
+		{KeyID: 1, KeyName: keyName{Web: "welcome.title", Other: "welcome.title"}},
+		{KeyID: 2, KeyName: keyName{Web: "web_name", IOS: "ios_only"}},
+		{KeyID: 3, KeyName: keyName{Web: "unrelated"}},
+	}
+
+	updates, missing := buildKeyContextUpdates(contexts, keys)
+
+	want := []keyUpdate{
+		{KeyID: 1, Description: &desc},
+		{KeyID: 2, CharLimit: &limit},
+	}
+	if !reflect.DeepEqual(updates, want) {
+		t.Fatalf("expected %#v, got %#v", want, updates)
+	}
+	if !reflect.DeepEqual(missing, []string{"missing.a", "missing.b"}) {
+		t.Fatalf("unexpected missing: %v", missing)
+	}
+}
+
+func TestApplyKeyContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "context.yml")
+	if err := os.WriteFile(path, []byte("b:\n  char_limit: 5\na:\n  description: x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := postPushConfig{KeyContextFile: path}
+	results := []uploadResult{{ProjectID: "p1", Status: resultStatusUploaded}}
+
+	t.Run("updates matching keys", func(t *testing.T) {
+		api := &fakeAPI{keys: []lokaliseKey{{KeyID: 9, KeyName: keyName{Web: "a"}}}}
+
+		if err := applyKeyContext(context.Background(), cfg, results, &fakeFactory{api: api}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(api.names, []string{"a", "b"}) {
+			t.Fatalf("expected sorted lookup names, got %v", api.names)
+		}
+		if len(api.updates) != 1 || api.updates[0].KeyID != 9 {
+			t.Fatalf("unexpected updates: %#v", api.updates)
+		}
+	})
+
+	t.Run("no matching keys means no update", func(t *testing.T) {
+		api := &fakeAPI{}
+
+		if err := applyKeyContext(context.Background(), cfg, results, &fakeFactory{api: api}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.updates) != 0 {
+			t.Fatalf("expected no updates, got %#v", api.updates)
+		}
+	})
+
+	t.Run("update errors are returned", func(t *testing.T) {
+		api := &fakeAPI{keys: []lokaliseKey{{KeyID: 9, KeyName: keyName{Web: "a"}}}, updateErr: errors.New("boom")}
+
+		err := applyKeyContext(context.Background(), cfg, results, &fakeFactory{api: api})
+		if err == nil || !strings.Contains(err.Error(), "cannot update key context") {
+			t.Fatalf("expected update error, got %v", err)
+		}
+	})
+
+	t.Run("invalid file is returned before any API call", func(t *testing.T) {
+		factory := &fakeFactory{api: &fakeAPI{}}
+		err := applyKeyContext(context.Background(), postPushConfig{KeyContextFile: filepath.Join(t.TempDir(), "nope.yml")}, results, factory)
+		if err == nil || len(factory.projects) != 0 {
+			t.Fatalf("expected file error without API calls, got %v (%v)", err, factory.projects)
+		}
+	})
+}
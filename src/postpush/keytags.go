@@ -0,0 +1,166 @@
+package postpush
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// keyTagRule adds Tags to every key whose name matches Pattern.
+type keyTagRule struct {
+	Pattern string
+	Tags    []string
+	re      *regexp.Regexp
+}
+
+// compileKeyPattern converts a key name glob into a regexp. Key names are
+// dot-separated: "*" and "?" stay within one segment, "**" spans segments.
+func compileKeyPattern(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString(`[^.]*`)
+			}
+		case '?':
+			b.WriteString(`[^.]`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// parseKeyTagRules parses a YAML/JSON mapping of key name patterns to tag lists:
+//
+//	"legal.*": [needs-legal-review]
+//	"checkout.**": [payments, high-priority]
+func parseKeyTagRules(data []byte) ([]keyTagRule, error) {
+	var raw yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid key tags file: %w", err)
+	}
+	if len(raw.Content) == 0 {
+		return nil, nil
+	}
+
+	root := raw.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("invalid key tags file: expected a mapping of key patterns to tag lists")
+	}
+
+	// Walk the node to keep rules in file order; later rules only add tags.
+	rules := make([]keyTagRule, 0, len(root.Content)/2)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		pattern := strings.TrimSpace(root.Content[i].Value)
+		if pattern == "" {
+			return nil, fmt.Errorf("invalid key tags file: key pattern cannot be empty")
+		}
+
+		var tags []string
+		if err := root.Content[i+1].Decode(&tags); err != nil {
+			return nil, fmt.Errorf("invalid key tags for %q: expected a list of tags", pattern)
+		}
+
+		var clean []string
+		for _, t := range tags {
+			if t = strings.TrimSpace(t); t != "" && !slices.Contains(clean, t) {
+				clean = append(clean, t)
+			}
+		}
+		if len(clean) == 0 {
+			return nil, fmt.Errorf("invalid key tags for %q: at least one tag is required", pattern)
+		}
+
+		re, err := compileKeyPattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+		}
+
+		rules = append(rules, keyTagRule{Pattern: pattern, Tags: clean, re: re})
+	}
+
+	return rules, nil
+}
+
+// loadKeyTagRules reads and parses the key tags file at path.
+func loadKeyTagRules(path string) ([]keyTagRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read key tags file: %w", err)
+	}
+	return parseKeyTagRules(data)
+}
+
+// buildKeyTagUpdates returns merge-tag updates for keys matching any rule.
+// Keys that already carry every matching tag are left alone.
+func buildKeyTagUpdates(rules []keyTagRule, keys []lokaliseKey) []keyUpdate {
+	var updates []keyUpdate
+
+	for _, k := range keys {
+		var add []string
+		for _, r := range rules {
+			if !slices.ContainsFunc(k.KeyName.names(), r.re.MatchString) {
+				continue
+			}
+			for _, t := range r.Tags {
+				if !slices.Contains(k.Tags, t) && !slices.Contains(add, t) {
+					add = append(add, t)
+				}
+			}
+		}
+		if len(add) > 0 {
+			updates = append(updates, keyUpdate{KeyID: k.KeyID, Tags: add, MergeTags: true})
+		}
+	}
+
+	return updates
+}
+
+// applyKeyTags adds extra tags to pushed keys (those tagged with the branch name)
+// according to the rules in KEY_TAGS_FILE.
+func applyKeyTags(ctx context.Context, cfg postPushConfig, results []uploadResult, factory ClientFactory) error {
+	rules, err := loadKeyTagRules(cfg.KeyTagsFile)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	clients := make(map[string]LokaliseAPI)
+	for _, projectID := range pushedProjectIDs(cfg, results) {
+		api, err := cachedAPI(clients, factory, cfg, projectID)
+		if err != nil {
+			return err
+		}
+
+		keys, err := api.ListKeysByTag(ctx, cfg.Branch)
+		if err != nil {
+			return fmt.Errorf("cannot list keys tagged %q in project %s: %w", cfg.Branch, projectID, err)
+		}
+
+		updates := buildKeyTagUpdates(rules, keys)
+		if len(updates) == 0 {
+			continue
+		}
+
+		if err := api.UpdateKeys(ctx, updates); err != nil {
+			return fmt.Errorf("cannot tag keys in project %s: %w", projectID, err)
+		}
+		logs.Infof("Project %s: added tags to %d keys", projectID, len(updates))
+	}
+
+	return nil
+}
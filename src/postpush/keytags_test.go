@@ -0,0 +1,150 @@
+package postpush
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompileKeyPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"legal.*", "legal.terms", true},
+		{"legal.*", "legal.terms.title", false},
+		{"legal.**", "legal.terms.title", true},
+		{"legal.*", "illegal.terms", false},
+		{"*.title", "home.title", true},
+		{"btn_?", "btn_1", true},
+		{"btn_?", "btn_12", false},
+		{"price[usd]", "price[usd]", true},
+		{"a+b", "aab", false},
+	}
+
+	for _, tt := range tests {
+		re, err := compileKeyPattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("compile %q: %v", tt.pattern, err)
+		}
+		if got := re.MatchString(tt.name); got != tt.want {
+			t.Fatalf("%q matching %q: expected %v, got %v", tt.pattern, tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestParseKeyTagRules(t *testing.T) {
+	rules, err := parseKeyTagRules([]byte(`
+"legal.*": [needs-legal-review, " legal ", legal]
+checkout.**:
+  - payments
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %#v", rules)
+	}
+	if rules[0].Pattern != "legal.*" || !reflect.DeepEqual(rules[0].Tags, []string{"needs-legal-review", "legal"}) {
+		t.Fatalf("unexpected first rule: %#v", rules[0])
+	}
+	if rules[1].Pattern != "checkout.**" || !reflect.DeepEqual(rules[1].Tags, []string{"payments"}) {
+		t.Fatalf("unexpected second rule: %#v", rules[1])
+	}
+
+	if got, err := parseKeyTagRules([]byte("  ")); err != nil || got != nil {
+		t.Fatalf("expected no rules for empty file, got %#v err=%v", got, err)
+	}
+
+	tests := []struct{ raw, wantErr string }{
+		{"- a\n", "expected a mapping"},
+		{"a: tag\n", "expected a list of tags"},
+		{"a: []\n", "at least one tag is required"},
+		{"\"\": [x]\n", "key pattern cannot be empty"},
+		{"a: [\n", "invalid key tags file"},
+	}
+	for _, tt := range tests {
+		if _, err := parseKeyTagRules([]byte(tt.raw)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Fatalf("expected error containing %q for %q, got %v", tt.wantErr, tt.raw, err)
+		}
+	}
+}
+
+func TestBuildKeyTagUpdates(t *testing.T) {
+	rules, err := parseKeyTagRules([]byte(`
+"legal.*": [needs-legal-review]
+"**.title": [titles, needs-legal-review]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []lokaliseKey{
+		{KeyID: 1, KeyName: keyName{Web: "legal.title"}},
+		{KeyID: 2, KeyName: keyName{Web: "legal.terms"}, Tags: []string{"main", "needs-legal-review"}},
+		{KeyID: 3, KeyName: keyName{Web: "home.hero.title"}, Tags: []string{"titles"}},
+		{KeyID: 4, KeyName: keyName{Web: "home.body"}},
+		{KeyID: 5, KeyName: keyName{Web: "web_name", IOS: "legal.ios"}},
+	}
+
+	got := buildKeyTagUpdates(rules, keys)
+	want := []keyUpdate{
+		{KeyID: 1, Tags: []string{"needs-legal-review", "titles"}, MergeTags: true},
+		{KeyID: 3, Tags: []string{"needs-legal-review"}, MergeTags: true},
+		{KeyID: 5, Tags: []string{"needs-legal-review"}, MergeTags: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestApplyKeyTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tags.yml")
+	if err := os.WriteFile(path, []byte("\"legal.*\": [needs-legal-review]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := postPushConfig{KeyTagsFile: path, Branch: "main"}
+	results := []uploadResult{{ProjectID: "p1", Status: resultStatusUploaded}}
+
+	t.Run("tags matching branch keys", func(t *testing.T) {
+		api := &fakeAPI{keys: []lokaliseKey{
+			{KeyID: 1, KeyName: keyName{Web: "legal.terms"}},
+			{KeyID: 2, KeyName: keyName{Web: "home.title"}},
+		}}
+
+		if err := applyKeyTags(context.Background(), cfg, results, &fakeFactory{api: api}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(api.tags, []string{"main"}) {
+			t.Fatalf("expected keys listed by branch tag, got %v", api.tags)
+		}
+		want := []keyUpdate{{KeyID: 1, Tags: []string{"needs-legal-review"}, MergeTags: true}}
+		if !reflect.DeepEqual(api.updates, want) {
+			t.Fatalf("unexpected updates: %#v", api.updates)
+		}
+	})
+
+	t.Run("update errors are returned", func(t *testing.T) {
+		api := &fakeAPI{
+			keys:      []lokaliseKey{{KeyID: 1, KeyName: keyName{Web: "legal.terms"}}},
+			updateErr: errors.New("boom"),
+		}
+
+		err := applyKeyTags(context.Background(), cfg, results, &fakeFactory{api: api})
+		if err == nil || !strings.Contains(err.Error(), "cannot tag keys") {
+			t.Fatalf("expected update error, got %v", err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := applyKeyTags(context.Background(), postPushConfig{KeyTagsFile: filepath.Join(t.TempDir(), "x.yml")}, results, &fakeFactory{api: &fakeAPI{}})
+		if err == nil || !strings.Contains(err.Error(), "cannot read key tags file") {
+			t.Fatalf("expected read error, got %v", err)
+		}
+	})
+}
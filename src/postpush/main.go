@@ -0,0 +1,162 @@
+package postpush
+
+import (
+	"context"
+	"maps"
+	"os"
+	"slices"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/ghoutput"
+	"lokalise-push-action/internal/logging"
+	"lokalise-push-action/internal/projectmap"
+)
+
+// exitFunc is a function variable that defaults to os.Exit.
+// Overridable in tests to assert exit behavior without terminating the process.
+var exitFunc = os.Exit
+
+// logs is the logger of the command; Main configures it from the environment.
+var logs = logging.New(os.Stdout, os.Stderr)
+
+type postPushFunc func(context.Context, postPushConfig, []uploadResult, ClientFactory, func(string, string) bool) error
+
+// Main runs the post-push integrations, configured through environment variables.
+// It exits the process on failure.
+func Main() {
+	if err := logs.Configure(); err != nil {
+		returnWithError(err.Error())
+	}
+	if err := run(); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// Check reads and validates the configuration of Main without running it.
+func Check() error {
+	cfg, err := prepareConfig()
+	if err == nil {
+		err = validate(cfg)
+	}
+	return envconf.Join([]error{logs.Configure(), err})
+}
+
+func run() error {
+	return runWith(
+		prepareConfig,
+		validate,
+		loadUploadResults,
+		runIntegrations,
+		&LokaliseFactory{},
+		ghoutput.Writer(logs),
+	)
+}
+
+func runWith(
+	prepare func() (postPushConfig, error),
+	validate func(postPushConfig) error,
+	load func(string) ([]uploadResult, error),
+	integrate postPushFunc,
+	factory ClientFactory,
+	write func(string, string) bool,
+) error {
+	// PROJECT_MAPPINGS tokens are masked before the config is read, so a
+	// malformed entry is reported without them.
+	for _, token := range projectmap.Tokens() {
+		logging.Mask(os.Stdout, token)
+		logs.AddSecret(token)
+	}
+
+	cfg, err := prepare()
+	if err != nil {
+		return err
+	}
+
+	// Credentials may come from earlier steps rather than secrets; keep them out of the log.
+	secrets := []string{cfg.Token, cfg.WebhookSecret, cfg.WebhookURL, cfg.GitHubToken, cfg.SlackWebhookURL}
+	for _, secret := range append(secrets, slices.Collect(maps.Values(cfg.ProjectTokens))...) {
+		logging.Mask(os.Stdout, secret)
+		logs.AddSecret(secret)
+	}
+
+	if err := validate(cfg); err != nil {
+		return err
+	}
+
+	results, err := load(cfg.ReportDir)
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		logs.Infof("No upload results found, nothing to report")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	return integrate(ctx, cfg, results, factory, write)
+}
+
+// runIntegrations runs every post-push integration enabled in cfg.
+// Reporting integrations are best-effort; notification failures are returned.
+func runIntegrations(ctx context.Context, cfg postPushConfig, results []uploadResult, factory ClientFactory, write func(string, string) bool) error {
+	if cfg.ProjectStats {
+		reportProjectStats(ctx, cfg, results, factory, write)
+	}
+
+	if cfg.DuplicateValues {
+		reportDuplicateValues(cfg, results, write)
+	}
+
+	if cfg.KeyContextFile != "" {
+		if err := applyKeyContext(ctx, cfg, results, factory); err != nil {
+			return err
+		}
+	}
+
+	if cfg.KeyTagsFile != "" {
+		if err := applyKeyTags(ctx, cfg, results, factory); err != nil {
+			return err
+		}
+	}
+
+	if cfg.ScreenshotsDir != "" {
+		if err := syncScreenshots(ctx, cfg, results, factory); err != nil {
+			return err
+		}
+	}
+
+	if cfg.CreateTask {
+		if err := createTasks(ctx, cfg, results, factory); err != nil {
+			return err
+		}
+	}
+
+	if cfg.CommentNewKeys {
+		if err := commentNewKeys(ctx, cfg, results, factory); err != nil {
+			return err
+		}
+	}
+
+	if cfg.WebhookURL == "" {
+		return nil
+	}
+
+	stats := collectKeyStats(ctx, cfg, results, factory)
+	payload := buildWebhookPayload(cfg, results, stats)
+
+	if err := sendWebhook(ctx, cfg, payload); err != nil {
+		return err
+	}
+
+	logs.Infof("Webhook delivered")
+	return nil
+}
+
+// returnWithError prints an error message to stderr and exits the program with a non-zero status code.
+func returnWithError(message string) {
+	logs.Errorf("%s", message)
+	exitFunc(1)
+}
@@ -0,0 +1,204 @@
+package postpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// Hijack os.Exit so tests can assert hard exits.
+	exitFunc = func(code int) { panic(fmt.Sprintf("Exit called with code %d", code)) }
+
+	code := m.Run()
+
+	// Restore.
+	exitFunc = os.Exit
+	os.Exit(code)
+}
+
+func TestRunWith(t *testing.T) {
+	wantCfg := postPushConfig{ReportDir: "report", WebhookURL: "https://example.com", Timeout: 5 * time.Second}
+	prepare := func() (postPushConfig, error) { return wantCfg, nil }
+	okValidate := func(postPushConfig) error { return nil }
+	oneResult := func(string) ([]uploadResult, error) {
+		return []uploadResult{{File: "en.json", Status: resultStatusUploaded}}, nil
+	}
+
+	t.Run("happy path", func(t *testing.T) {
+		factory := &LokaliseFactory{}
+		called := false
+
+		integrate := func(ctx context.Context, cfg postPushConfig, results []uploadResult, gotFactory ClientFactory, _ func(string, string) bool) error {
+			called = true
+			if !reflect.DeepEqual(cfg, wantCfg) {
+				t.Fatalf("integrate got cfg=%#v, want %#v", cfg, wantCfg)
+			}
+			if len(results) != 1 || results[0].File != "en.json" {
+				t.Fatalf("unexpected results: %#v", results)
+			}
+			if gotFactory != factory {
+				t.Fatalf("integrate got unexpected factory: %#v", gotFactory)
+			}
+			if _, ok := ctx.Deadline(); !ok {
+				t.Fatal("integrate context has no deadline")
+			}
+			return nil
+		}
+
+		load := func(dir string) ([]uploadResult, error) {
+			if dir != "report" {
+				t.Fatalf("load got dir=%q", dir)
+			}
+			return oneResult(dir)
+		}
+
+		if err := runWith(prepare, okValidate, load, integrate, factory, noopWrite); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !called {
+			t.Fatal("expected integrate to be called")
+		}
+	})
+
+	t.Run("no results skips integrations", func(t *testing.T) {
+		load := func(string) ([]uploadResult, error) { return nil, nil }
+		integrate := func(context.Context, postPushConfig, []uploadResult, ClientFactory, func(string, string) bool) error {
+			t.Fatal("integrate must not be called")
+			return nil
+		}
+
+		if err := runWith(prepare, okValidate, load, integrate, nil, noopWrite); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		prepare   func() (postPushConfig, error)
+		validate  func(postPushConfig) error
+		load      func(string) ([]uploadResult, error)
+		integrate postPushFunc
+		wantErr   string
+	}{
+		{
+			name:     "prepare error",
+			prepare:  func() (postPushConfig, error) { return postPushConfig{}, errors.New("prepare boom") },
+			validate: okValidate,
+			load:     oneResult,
+			wantErr:  "prepare boom",
+		},
+		{
+			name:     "validate error",
+			prepare:  prepare,
+			validate: func(postPushConfig) error { return errors.New("validate boom") },
+			load:     oneResult,
+			wantErr:  "validate boom",
+		},
+		{
+			name:     "load error",
+			prepare:  prepare,
+			validate: okValidate,
+			load:     func(string) ([]uploadResult, error) { return nil, errors.New("load boom") },
+			wantErr:  "load boom",
+		},
+		{
+			name:     "integrate error",
+			prepare:  prepare,
+			validate: okValidate,
+			load:     oneResult,
+			integrate: func(context.Context, postPushConfig, []uploadResult, ClientFactory, func(string, string) bool) error {
+				return errors.New("integrate boom")
+			},
+			wantErr: "integrate boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			integrate := tt.integrate
+			if integrate == nil {
+				integrate = func(context.Context, postPushConfig, []uploadResult, ClientFactory, func(string, string) bool) error {
+					t.Fatal("integrate must not be called")
+					return nil
+				}
+			}
+
+			err := runWith(tt.prepare, tt.validate, tt.load, integrate, nil, noopWrite)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunIntegrations_NoWebhook(t *testing.T) {
+	factory := &fakeFactory{}
+	err := runIntegrations(context.Background(), postPushConfig{}, []uploadResult{{File: "en.json"}}, factory, noopWrite)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(factory.projects) != 0 {
+		t.Fatalf("expected no API calls, got %v", factory.projects)
+	}
+}
+
+func TestRunIntegrations_Webhook(t *testing.T) {
+	var got webhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	factory := &fakeFactory{api: &fakeAPI{processes: map[string]processResponse{
+		"pid1": processWithFiles([4]int{4, 4, 0, 0}),
+	}}}
+	cfg := postPushConfig{WebhookURL: srv.URL, HTTPTimeout: 5 * time.Second}
+	results := []uploadResult{{File: "en.json", ProjectID: "p1", ProcessID: "pid1", Status: resultStatusUploaded}}
+
+	if err := runIntegrations(context.Background(), cfg, results, factory, noopWrite); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Keys.Inserted != 4 || len(got.Files) != 1 {
+		t.Fatalf("unexpected payload: %#v", got)
+	}
+}
+
+func TestReturnWithError(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(fmt.Sprint(r), "Exit called with code 1") {
+			t.Fatalf("expected exit with code 1, got %v", r)
+		}
+	}()
+
+	returnWithError("boom")
+}
+
+func TestCheck(t *testing.T) {
+	t.Setenv("REPORT_DIR", "")
+	t.Setenv("PROJECT_STATS", "")
+	if err := Check(); err == nil || !strings.Contains(err.Error(), "REPORT_DIR") {
+		t.Fatalf("expected REPORT_DIR error, got %v", err)
+	}
+
+	t.Setenv("PROJECT_STATS", "maybe")
+	if err := Check(); err == nil || !strings.Contains(err.Error(), "invalid PROJECT_STATS") {
+		t.Fatalf("expected invalid PROJECT_STATS error, got %v", err)
+	}
+
+	t.Setenv("LOG_LEVEL", "verbose")
+	if err := Check(); err == nil || !strings.Contains(err.Error(), "invalid LOG_LEVEL") {
+		t.Fatalf("expected invalid LOG_LEVEL error, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package postpush
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"lokalise-push-action/internal/logging"
+)
+
+// Notify sends the result of the push to the configured notification
+// channels. Unlike Main it also runs after a failed push, so PUSH_OUTCOME
+// should carry the outcome of the upload step. It exits the process on failure.
+func Notify() {
+	if err := runNotify(prepareConfig, loadUploadResults, &LokaliseFactory{}); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// ValidateNotify reads and validates the configuration of Notify without running it.
+func ValidateNotify() error {
+	cfg, err := prepareConfig()
+	if err != nil {
+		return err
+	}
+	return validateNotify(cfg)
+}
+
+// validateNotify ensures at least one notification channel is configured.
+func validateNotify(cfg postPushConfig) error {
+	if cfg.SlackWebhookURL == "" {
+		return fmt.Errorf("Slack webhook URL (SLACK_WEBHOOK_URL) is required to send notifications")
+	}
+	return validateWebhookURL("slack_webhook_url", cfg.SlackWebhookURL)
+}
+
+func runNotify(
+	prepare func() (postPushConfig, error),
+	load func(string) ([]uploadResult, error),
+	factory ClientFactory,
+) error {
+	cfg, err := prepare()
+	if err != nil {
+		return err
+	}
+	for _, secret := range []string{cfg.Token, cfg.SlackWebhookURL} {
+		logging.Mask(os.Stdout, secret)
+	}
+	if err := validateNotify(cfg); err != nil {
+		return err
+	}
+
+	var results []uploadResult
+	if cfg.ReportDir != "" {
+		if results, err = load(cfg.ReportDir); err != nil {
+			return err
+		}
+	}
+
+	// A push without translation changes is not worth a message.
+	if len(results) == 0 && cfg.PushOutcome != "failure" && cfg.PushOutcome != "cancelled" {
+		logs.Infof("Nothing was pushed, no notification sent")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	// Key counters need the Lokalise API; without a token they are left out.
+	var stats map[int]keyStats
+	if cfg.Token != "" {
+		stats = collectKeyStats(ctx, cfg, results, factory)
+	}
+
+	if err := sendSlack(ctx, cfg, buildSlackMessage(cfg, results, stats)); err != nil {
+		return err
+	}
+
+	logs.Infof("Slack notification sent")
+	return nil
+}
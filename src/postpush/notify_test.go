@@ -0,0 +1,114 @@
+package postpush
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateNotify(t *testing.T) {
+	if err := validateNotify(postPushConfig{}); err == nil || !strings.Contains(err.Error(), "SLACK_WEBHOOK_URL") {
+		t.Fatalf("expected a missing channel error, got %v", err)
+	}
+	if err := validateNotify(postPushConfig{SlackWebhookURL: "hooks.slack.com/x"}); err == nil || !strings.Contains(err.Error(), "invalid slack_webhook_url") {
+		t.Fatalf("expected an invalid URL error, got %v", err)
+	}
+	if err := validateNotify(postPushConfig{SlackWebhookURL: "https://hooks.slack.com/x"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRunNotify(t *testing.T) {
+	var messages []slackMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		messages = append(messages, msg)
+	}))
+	defer srv.Close()
+
+	base := postPushConfig{ReportDir: "report", SlackWebhookURL: srv.URL, Timeout: 5 * time.Second, HTTPTimeout: 5 * time.Second}
+	uploaded := []uploadResult{{File: "en.json", ProjectID: "p1", ProcessID: "pid1", Status: resultStatusUploaded}}
+	factory := &fakeFactory{api: &fakeAPI{processes: map[string]processResponse{
+		"pid1": processWithFiles([4]int{4, 4, 0, 0}),
+	}}}
+
+	run := func(cfg postPushConfig, results []uploadResult) error {
+		return runNotify(
+			func() (postPushConfig, error) { return cfg, nil },
+			func(string) ([]uploadResult, error) { return results, nil },
+			factory,
+		)
+	}
+
+	t.Run("success with key stats", func(t *testing.T) {
+		messages = nil
+		cfg := base
+		cfg.Token = "tok"
+		cfg.PushOutcome = "success"
+		if err := run(cfg, uploaded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(messages) != 1 || !strings.Contains(messages[0].Blocks[1].Fields[0].Text, "4 inserted") {
+			t.Fatalf("expected a message with key stats, got %+v", messages)
+		}
+	})
+
+	t.Run("no token, no key stats", func(t *testing.T) {
+		messages = nil
+		factory.projects = nil
+		if err := run(base, uploaded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(messages) != 1 || len(messages[0].Blocks) != 2 || len(factory.projects) != 0 {
+			t.Fatalf("expected a message without key stats, got %+v", messages)
+		}
+	})
+
+	t.Run("nothing pushed", func(t *testing.T) {
+		messages = nil
+		cfg := base
+		cfg.PushOutcome = "skipped"
+		if err := run(cfg, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(messages) != 0 {
+			t.Fatalf("expected no message, got %+v", messages)
+		}
+	})
+
+	t.Run("failed push without results", func(t *testing.T) {
+		messages = nil
+		cfg := base
+		cfg.PushOutcome = "failure"
+		if err := run(cfg, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(messages) != 1 || messages[0].Text != "Lokalise push: The push failed" {
+			t.Fatalf("expected a failure message, got %+v", messages)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		err := runNotify(
+			func() (postPushConfig, error) { return base, nil },
+			func(string) ([]uploadResult, error) { return nil, errors.New("bad report") },
+			factory,
+		)
+		if err == nil || err.Error() != "bad report" {
+			t.Fatalf("expected the load error, got %v", err)
+		}
+
+		cfg := base
+		cfg.SlackWebhookURL = ""
+		if err := run(cfg, uploaded); err == nil || !strings.Contains(err.Error(), "SLACK_WEBHOOK_URL") {
+			t.Fatalf("expected a validation error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package postpush
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"lokalise-push-action/internal/ghoutput"
+)
+
+// projectResponse is the subset of GET /projects/{id} we use.
+type projectResponse struct {
+	ProjectID  string `json:"project_id"`
+	Name       string `json:"name"`
+	Statistics struct {
+		ProgressTotal int `json:"progress_total"`
+		KeysTotal     int `json:"keys_total"`
+		BaseWords     int `json:"base_words"`
+		Languages     []struct {
+			LanguageISO string `json:"language_iso"`
+			Progress    int    `json:"progress"`
+			WordsToDo   int    `json:"words_to_do"`
+		} `json:"languages"`
+	} `json:"statistics"`
+}
+
+// projectStats is a health snapshot of one project taken after the push.
+type projectStats struct {
+	ProjectID string
+	Name      string
+	KeysTotal int
+	Progress  int
+	Languages []languageStats
+}
+
+type languageStats struct {
+	LangISO   string
+	Progress  int
+	WordsToDo int
+}
+
+func newProjectStats(projectID string, resp projectResponse) projectStats {
+	s := projectStats{
+		ProjectID: projectID,
+		Name:      resp.Name,
+		KeysTotal: resp.Statistics.KeysTotal,
+		Progress:  resp.Statistics.ProgressTotal,
+		Languages: make([]languageStats, 0, len(resp.Statistics.Languages)),
+	}
+	for _, l := range resp.Statistics.Languages {
+		s.Languages = append(s.Languages, languageStats{LangISO: l.LanguageISO, Progress: l.Progress, WordsToDo: l.WordsToDo})
+	}
+	return s
+}
+
+// pushedProjectIDs lists the distinct projects that received files, primary project first.
+func pushedProjectIDs(cfg postPushConfig, results []uploadResult) []string {
+	seen := make(map[string]struct{}, len(results))
+	var ids []string
+	for _, res := range results {
+		if res.Status != resultStatusUploaded || res.ProjectID == "" {
+			continue
+		}
+		if _, dup := seen[res.ProjectID]; dup {
+			continue
+		}
+		seen[res.ProjectID] = struct{}{}
+		ids = append(ids, res.ProjectID)
+	}
+
+	for i, id := range ids {
+		if id == cfg.ProjectID && i > 0 {
+			copy(ids[1:i+1], ids[:i])
+			ids[0] = id
+			break
+		}
+	}
+	return ids
+}
+
+// collectProjectStats fetches statistics for every project that received files.
+// Lookups are best-effort: failures are logged and the project is left out.
+func collectProjectStats(ctx context.Context, cfg postPushConfig, results []uploadResult, factory ClientFactory) []projectStats {
+	clients := make(map[string]LokaliseAPI)
+	var out []projectStats
+
+	for _, projectID := range pushedProjectIDs(cfg, results) {
+		api, err := cachedAPI(clients, factory, cfg, projectID)
+		if err != nil {
+			logs.Warnf("%v", err)
+			continue
+		}
+
+		resp, err := api.FetchProject(ctx)
+		if err != nil {
+			logs.Warnf("cannot fetch statistics for project %s: %v", projectID, err)
+			continue
+		}
+		out = append(out, newProjectStats(projectID, resp))
+	}
+
+	return out
+}
+
+// writeProjectStatsOutputs exposes the first (primary) project's statistics as step outputs.
+func writeProjectStatsOutputs(stats []projectStats, write func(string, string) bool) {
+	if len(stats) == 0 {
+		return
+	}
+	s := stats[0]
+	write("project_keys_total", strconv.Itoa(s.KeysTotal))
+	write("project_languages_count", strconv.Itoa(len(s.Languages)))
+	write("project_progress", strconv.Itoa(s.Progress))
+}
+
+// renderProjectStatsSummary renders the statistics as Markdown for the job summary.
+func renderProjectStatsSummary(stats []projectStats) string {
+	if len(stats) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("### Lokalise project statistics\n\n")
+	b.WriteString("| Project | Keys | Languages | Progress |\n")
+	b.WriteString("| --- | ---: | ---: | ---: |\n")
+	for _, s := range stats {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d%% |\n", projectLabel(s), s.KeysTotal, len(s.Languages), s.Progress)
+	}
+
+	for _, s := range stats {
+		if len(s.Languages) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n#### %s\n\n", projectLabel(s))
+		b.WriteString("| Language | Progress | Words to do |\n")
+		b.WriteString("| --- | ---: | ---: |\n")
+		for _, l := range s.Languages {
+			fmt.Fprintf(&b, "| %s | %d%% | %d |\n", l.LangISO, l.Progress, l.WordsToDo)
+		}
+	}
+
+	return b.String()
+}
+
+func projectLabel(s projectStats) string {
+	if s.Name == "" {
+		return s.ProjectID
+	}
+	return fmt.Sprintf("%s (%s)", s.Name, s.ProjectID)
+}
+
+// reportProjectStats collects statistics and publishes them as outputs and job summary.
+func reportProjectStats(ctx context.Context, cfg postPushConfig, results []uploadResult, factory ClientFactory, write func(string, string) bool) {
+	stats := collectProjectStats(ctx, cfg, results, factory)
+	writeProjectStatsOutputs(stats, write)
+
+	if err := ghoutput.AppendStepSummary(renderProjectStatsSummary(stats)); err != nil {
+		logs.Warnf("%v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package postpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sampleProject(t *testing.T) projectResponse {
+	t.Helper()
+
+	raw := `{
+		"project_id": "p1",
+		"name": "Web app",
+		"statistics": {
+			"progress_total": 64,
+			"keys_total": 120,
+			"base_words": 900,
+			"languages": [
+				{"language_id": 640, "language_iso": "en", "progress": 100, "words_to_do": 0},
+				{"language_id": 597, "language_iso": "fr", "progress": 28, "words_to_do": 650}
+			]
+		}
+	}`
+
+	var resp projectResponse
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatalf("decode sample: %v", err)
+	}
+	return resp
+}
+
+func TestNewProjectStats(t *testing.T) {
+	got := newProjectStats("p1", sampleProject(t))
+	want := projectStats{
+		ProjectID: "p1",
+		Name:      "Web app",
+		KeysTotal: 120,
+		Progress:  64,
+		Languages: []languageStats{
+			{LangISO: "en", Progress: 100},
+			{LangISO: "fr", Progress: 28, WordsToDo: 650},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestPushedProjectIDs(t *testing.T) {
+	results := []uploadResult{
+		{ProjectID: "mirror", Status: resultStatusUploaded},
+		{ProjectID: "failed", Status: resultStatusFailed},
+		{ProjectID: "primary", Status: resultStatusUploaded},
+		{ProjectID: "mirror", Status: resultStatusUploaded},
+		{ProjectID: "other", Status: resultStatusUploaded},
+	}
+
+	got := pushedProjectIDs(postPushConfig{ProjectID: "primary"}, results)
+	want := []string{"primary", "mirror", "other"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	got = pushedProjectIDs(postPushConfig{ProjectID: "absent"}, results)
+	want = []string{"mirror", "primary", "other"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestCollectProjectStats(t *testing.T) {
+	results := []uploadResult{{ProjectID: "p1", Status: resultStatusUploaded}}
+
+	t.Run("fetches pushed projects", func(t *testing.T) {
+		api := &fakeAPI{project: sampleProject(t)}
+		got := collectProjectStats(context.Background(), postPushConfig{}, results, &fakeFactory{api: api})
+		if len(got) != 1 || got[0].KeysTotal != 120 {
+			t.Fatalf("unexpected stats: %#v", got)
+		}
+	})
+
+	t.Run("failures are skipped", func(t *testing.T) {
+		got := collectProjectStats(context.Background(), postPushConfig{}, results, &fakeFactory{api: &fakeAPI{err: errors.New("boom")}})
+		if len(got) != 0 {
+			t.Fatalf("expected no stats, got %#v", got)
+		}
+	})
+}
+
+func TestWriteProjectStatsOutputs(t *testing.T) {
+	got := map[string]string{}
+	write := func(k, v string) bool { got[k] = v; return true }
+
+	writeProjectStatsOutputs(nil, write)
+	if len(got) != 0 {
+		t.Fatalf("expected no outputs, got %v", got)
+	}
+
+	writeProjectStatsOutputs([]projectStats{newProjectStats("p1", sampleProject(t)), {ProjectID: "p2", KeysTotal: 1}}, write)
+	want := map[string]string{
+		"project_keys_total":      "120",
+		"project_languages_count": "2",
+		"project_progress":        "64",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRenderProjectStatsSummary(t *testing.T) {
+	if got := renderProjectStatsSummary(nil); got != "" {
+		t.Fatalf("expected empty summary, got %q", got)
+	}
+
+	got := renderProjectStatsSummary([]projectStats{
+		newProjectStats("p1", sampleProject(t)),
+		{ProjectID: "p2", KeysTotal: 3},
+	})
+
+	for _, want := range []string{
+		"### Lokalise project statistics",
+		"| Web app (p1) | 120 | 2 | 64% |",
+		"| p2 | 3 | 0 | 0% |",
+		"#### Web app (p1)",
+		"| fr | 28% | 650 |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("summary missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "#### p2") {
+		t.Fatalf("projects without languages must not get a language table:\n%s", got)
+	}
+}
+
+func TestReportProjectStats(t *testing.T) {
+	summary := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", summary)
+	cfg := postPushConfig{ProjectStats: true}
+	results := []uploadResult{{ProjectID: "p1", Status: resultStatusUploaded}}
+	factory := &fakeFactory{api: &fakeAPI{project: sampleProject(t)}}
+
+	outputs := map[string]string{}
+	write := func(k, v string) bool { outputs[k] = v; return true }
+
+	if err := runIntegrations(context.Background(), cfg, results, factory, write); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if outputs["project_keys_total"] != "120" {
+		t.Fatalf("unexpected outputs: %v", outputs)
+	}
+	data, err := os.ReadFile(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Lokalise project statistics") {
+		t.Fatalf("unexpected summary: %s", data)
+	}
+}
@@ -0,0 +1,65 @@
+package postpush
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	resultStatusUploaded = "uploaded"
+	resultStatusFailed   = "failed"
+)
+
+// uploadResult mirrors the per-file record written by lokaliseupload into REPORT_DIR.
+type uploadResult struct {
+	File      string `json:"file"`
+	ProjectID string `json:"project_id"`
+	LangISO   string `json:"lang_iso"`
+	ProcessID string `json:"process_id,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	StartedAt int64  `json:"started_at,omitempty"`
+}
+
+// loadUploadResults reads every *.json result in dir, sorted by file and project.
+// A missing directory means nothing was uploaded.
+func loadUploadResults(dir string) ([]uploadResult, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read report directory: %w", err)
+	}
+
+	var results []uploadResult
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot read report file %q: %w", e.Name(), err)
+		}
+
+		var res uploadResult
+		if err := json.Unmarshal(data, &res); err != nil {
+			return nil, fmt.Errorf("invalid report file %q: %w", e.Name(), err)
+		}
+		results = append(results, res)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].File != results[j].File {
+			return results[i].File < results[j].File
+		}
+		return results[i].ProjectID < results[j].ProjectID
+	})
+
+	return results, nil
+}
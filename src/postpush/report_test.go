@@ -0,0 +1,66 @@
+package postpush
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadUploadResults(t *testing.T) {
+	t.Run("missing dir yields no results", func(t *testing.T) {
+		got, err := loadUploadResults(filepath.Join(t.TempDir(), "missing"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 0 {
+			t.Fatalf("expected no results, got %#v", got)
+		}
+	})
+
+	t.Run("reads and sorts results, ignoring other files", func(t *testing.T) {
+		dir := t.TempDir()
+		files := map[string]string{
+			"result-2.json": `{"file":"fr.json","project_id":"p1","lang_iso":"fr","status":"uploaded"}`,
+			"result-1.json": `{"file":"en.json","project_id":"p2","lang_iso":"en","status":"failed","error":"boom"}`,
+			"result-3.json": `{"file":"en.json","project_id":"p1","lang_iso":"en","process_id":"pid","status":"uploaded"}`,
+			"notes.txt":     "ignored",
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got, err := loadUploadResults(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []uploadResult{
+			{File: "en.json", ProjectID: "p1", LangISO: "en", ProcessID: "pid", Status: resultStatusUploaded},
+			{File: "en.json", ProjectID: "p2", LangISO: "en", Status: resultStatusFailed, Error: "boom"},
+			{File: "fr.json", ProjectID: "p1", LangISO: "fr", Status: resultStatusUploaded},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("expected %d results, got %d: %#v", len(want), len(got), got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("result %d: expected %#v, got %#v", i, want[i], got[i])
+			}
+		}
+	})
+
+	t.Run("invalid JSON returns error", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "result-1.json"), []byte("{"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := loadUploadResults(dir)
+		if err == nil || !strings.Contains(err.Error(), "invalid report file") {
+			t.Fatalf("expected invalid report file error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,268 @@
+package postpush
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// screenshotMIMETypes lists the image formats accepted by the screenshots API.
+var screenshotMIMETypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+}
+
+// screenshotFile is a local image and the key names it should be linked to.
+type screenshotFile struct {
+	// Title is the slash-separated path relative to SCREENSHOTS_DIR; it identifies
+	// the screenshot on Lokalise across runs.
+	Title    string
+	Path     string
+	KeyNames []string
+}
+
+// lokaliseScreenshot is the subset of a screenshot object we use.
+type lokaliseScreenshot struct {
+	ScreenshotID int64   `json:"screenshot_id"`
+	Title        string  `json:"title"`
+	Description  string  `json:"description"`
+	KeyIDs       []int64 `json:"key_ids"`
+}
+
+// newScreenshot is one entry of POST /projects/{id}/screenshots.
+type newScreenshot struct {
+	Data        string  `json:"data"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	KeyIDs      []int64 `json:"key_ids"`
+	OCR         bool    `json:"ocr"`
+}
+
+// parseScreenshotMapping parses a YAML/JSON mapping of screenshot paths
+// (relative to SCREENSHOTS_DIR) to the key names shown on them.
+func parseScreenshotMapping(data []byte) (map[string][]string, error) {
+	var raw map[string][]string
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid screenshots mapping (must map file paths to lists of key names): %w", err)
+	}
+
+	out := make(map[string][]string, len(raw))
+	for file, keys := range raw {
+		file = path.Clean(filepath.ToSlash(strings.TrimSpace(file)))
+		var names []string
+		for _, k := range keys {
+			if k = strings.TrimSpace(k); k != "" && !slices.Contains(names, k) {
+				names = append(names, k)
+			}
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("invalid screenshots mapping for %q: at least one key name is required", file)
+		}
+		out[file] = names
+	}
+	return out, nil
+}
+
+// collectScreenshots finds images in dir. Files listed in mapping are linked to
+// the mapped keys; any other file is linked to the key named after the file
+// (e.g. "welcome.title.png" -> "welcome.title").
+func collectScreenshots(dir string, mapping map[string][]string) ([]screenshotFile, error) {
+	var out []screenshotFile
+	seen := make(map[string]struct{}, len(mapping))
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(p))
+		if _, ok := screenshotMIMETypes[ext]; !ok {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		title := filepath.ToSlash(rel)
+
+		names, ok := mapping[title]
+		if ok {
+			seen[title] = struct{}{}
+		} else {
+			names = []string{strings.TrimSuffix(path.Base(title), path.Ext(title))}
+		}
+
+		out = append(out, screenshotFile{Title: title, Path: p, KeyNames: names})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read screenshots directory: %w", err)
+	}
+
+	for file := range mapping {
+		if _, ok := seen[file]; !ok {
+			return nil, fmt.Errorf("screenshots mapping references %q, which is not an image in the screenshots directory", file)
+		}
+	}
+
+	return out, nil
+}
+
+// screenshotFingerprint is stored as the screenshot description to detect changed images.
+func screenshotFingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// screenshotDataURI encodes image data for the screenshots API.
+func screenshotDataURI(file string, data []byte) string {
+	mime := screenshotMIMETypes[strings.ToLower(filepath.Ext(file))]
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// loadScreenshotMapping reads the optional mapping file.
+func loadScreenshotMapping(file string) (map[string][]string, error) {
+	if file == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read screenshots mapping: %w", err)
+	}
+	return parseScreenshotMapping(data)
+}
+
+// syncScreenshots uploads screenshots to every project that received files and
+// links them to keys. Unchanged screenshots are only relinked when their keys
+// differ; changed ones are replaced.
+func syncScreenshots(ctx context.Context, cfg postPushConfig, results []uploadResult, factory ClientFactory) error {
+	mapping, err := loadScreenshotMapping(cfg.ScreenshotsMapping)
+	if err != nil {
+		return err
+	}
+
+	files, err := collectScreenshots(cfg.ScreenshotsDir, mapping)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		logs.Infof("No screenshots found")
+		return nil
+	}
+
+	var names []string
+	for _, f := range files {
+		for _, n := range f.KeyNames {
+			if !slices.Contains(names, n) {
+				names = append(names, n)
+			}
+		}
+	}
+	slices.Sort(names)
+
+	clients := make(map[string]LokaliseAPI)
+	for _, projectID := range pushedProjectIDs(cfg, results) {
+		api, err := cachedAPI(clients, factory, cfg, projectID)
+		if err != nil {
+			return err
+		}
+
+		if err := syncProjectScreenshots(ctx, api, files, names); err != nil {
+			return fmt.Errorf("cannot sync screenshots in project %s: %w", projectID, err)
+		}
+	}
+
+	return nil
+}
+
+func syncProjectScreenshots(ctx context.Context, api LokaliseAPI, files []screenshotFile, names []string) error {
+	keys, err := api.ListKeysByName(ctx, names)
+	if err != nil {
+		return err
+	}
+	keyIDs := make(map[string]int64, len(keys))
+	for _, k := range keys {
+		for _, n := range k.KeyName.names() {
+			keyIDs[n] = k.KeyID
+		}
+	}
+
+	existing, err := api.ListScreenshots(ctx)
+	if err != nil {
+		return err
+	}
+	byTitle := make(map[string]lokaliseScreenshot, len(existing))
+	for _, s := range existing {
+		byTitle[s.Title] = s
+	}
+
+	var created, relinked, unchanged int
+	for _, f := range files {
+		var ids []int64
+		for _, n := range f.KeyNames {
+			if id, ok := keyIDs[n]; ok && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			logs.Infof("Screenshot %q: no matching keys, skipped", f.Title)
+			continue
+		}
+		slices.Sort(ids)
+
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			return fmt.Errorf("cannot read screenshot %q: %w", f.Title, err)
+		}
+		fingerprint := screenshotFingerprint(data)
+
+		if old, ok := byTitle[f.Title]; ok {
+			if old.Description == fingerprint {
+				current := slices.Clone(old.KeyIDs)
+				slices.Sort(current)
+				if slices.Equal(current, ids) {
+					unchanged++
+					continue
+				}
+				if err := api.UpdateScreenshotKeys(ctx, old.ScreenshotID, ids); err != nil {
+					return fmt.Errorf("cannot relink screenshot %q: %w", f.Title, err)
+				}
+				relinked++
+				continue
+			}
+
+			if err := api.DeleteScreenshot(ctx, old.ScreenshotID); err != nil {
+				return fmt.Errorf("cannot replace screenshot %q: %w", f.Title, err)
+			}
+		}
+
+		err = api.CreateScreenshot(ctx, newScreenshot{
+			Data:        screenshotDataURI(f.Path, data),
+			Title:       f.Title,
+			Description: fingerprint,
+			KeyIDs:      ids,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot upload screenshot %q: %w", f.Title, err)
+		}
+		created++
+	}
+
+	logs.Infof("Screenshots: %d uploaded, %d relinked, %d unchanged", created, relinked, unchanged)
+	return nil
+}
@@ -0,0 +1,166 @@
+package postpush
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeScreens(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParseScreenshotMapping(t *testing.T) {
+	got, err := parseScreenshotMapping([]byte(`
+./home/welcome.png: [welcome.title, " welcome.body ", welcome.title]
+checkout.jpg:
+  - checkout.pay
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]string{
+		"home/welcome.png": {"welcome.title", "welcome.body"},
+		"checkout.jpg":     {"checkout.pay"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for _, raw := range []string{"a.png: key\n", "a.png: []\n", "- a.png\n"} {
+		if _, err := parseScreenshotMapping([]byte(raw)); err == nil || !strings.Contains(err.Error(), "invalid screenshots mapping") {
+			t.Fatalf("expected error for %q, got %v", raw, err)
+		}
+	}
+}
+
+func TestCollectScreenshots(t *testing.T) {
+	dir := writeScreens(t, map[string]string{
+		"welcome.title.png":     "a",
+		"home/overview.JPG":     "b",
+		"notes.txt":             "ignored",
+		"checkout/pay.jpeg":     "c",
+		"checkout/raw/skip.gif": "ignored",
+	})
+
+	got, err := collectScreenshots(dir, map[string][]string{"home/overview.JPG": {"home.title", "home.body"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	byTitle := map[string][]string{}
+	for _, f := range got {
+		byTitle[f.Title] = f.KeyNames
+	}
+	want := map[string][]string{
+		"welcome.title.png": {"welcome.title"},
+		"home/overview.JPG": {"home.title", "home.body"},
+		"checkout/pay.jpeg": {"pay"},
+	}
+	if !reflect.DeepEqual(byTitle, want) {
+		t.Fatalf("expected %v, got %v", want, byTitle)
+	}
+
+	_, err = collectScreenshots(dir, map[string][]string{"missing.png": {"k"}})
+	if err == nil || !strings.Contains(err.Error(), `"missing.png"`) {
+		t.Fatalf("expected unknown mapping error, got %v", err)
+	}
+
+	if _, err := collectScreenshots(filepath.Join(dir, "nope"), nil); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+}
+
+func TestScreenshotDataURI(t *testing.T) {
+	if got := screenshotDataURI("a.PNG", []byte("hi")); got != "data:image/png;base64,aGk=" {
+		t.Fatalf("unexpected data URI: %q", got)
+	}
+	if got := screenshotDataURI("a.jpg", []byte("hi")); !strings.HasPrefix(got, "data:image/jpeg;base64,") {
+		t.Fatalf("unexpected data URI: %q", got)
+	}
+}
+
+func TestSyncScreenshots(t *testing.T) {
+	dir := writeScreens(t, map[string]string{
+		"new.png":       "new",
+		"same.png":      "same",
+		"relink.png":    "relink",
+		"changed.png":   "changed",
+		"orphan.png":    "orphan",
+		"unrelated.txt": "x",
+	})
+	cfg := postPushConfig{ScreenshotsDir: dir}
+	results := []uploadResult{{ProjectID: "p1", Status: resultStatusUploaded}}
+
+	api := &fakeAPI{
+		keys: []lokaliseKey{
+			{KeyID: 1, KeyName: keyName{Web: "new"}},
+			{KeyID: 2, KeyName: keyName{Web: "same"}},
+			{KeyID: 3, KeyName: keyName{Web: "relink"}},
+			{KeyID: 4, KeyName: keyName{Web: "changed"}},
+		},
+		screenshots: []lokaliseScreenshot{
+			{ScreenshotID: 20, Title: "same.png", Description: screenshotFingerprint([]byte("same")), KeyIDs: []int64{2}},
+			{ScreenshotID: 30, Title: "relink.png", Description: screenshotFingerprint([]byte("relink")), KeyIDs: []int64{99}},
+			{ScreenshotID: 40, Title: "changed.png", Description: screenshotFingerprint([]byte("old")), KeyIDs: []int64{4}},
+		},
+	}
+
+	if err := syncScreenshots(context.Background(), cfg, results, &fakeFactory{api: api}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created := map[string][]int64{}
+	for _, s := range api.created {
+		created[s.Title] = s.KeyIDs
+		if s.Description != screenshotFingerprint([]byte(strings.TrimSuffix(s.Title, ".png"))) {
+			t.Fatalf("unexpected fingerprint for %s: %q", s.Title, s.Description)
+		}
+	}
+	if !reflect.DeepEqual(created, map[string][]int64{"new.png": {1}, "changed.png": {4}}) {
+		t.Fatalf("unexpected uploads: %v", created)
+	}
+	if !reflect.DeepEqual(api.deleted, []int64{40}) {
+		t.Fatalf("expected changed screenshot to be replaced, got %v", api.deleted)
+	}
+	if !reflect.DeepEqual(api.relinked, map[int64][]int64{30: {3}}) {
+		t.Fatalf("unexpected relinks: %v", api.relinked)
+	}
+}
+
+func TestSyncScreenshots_Errors(t *testing.T) {
+	results := []uploadResult{{ProjectID: "p1", Status: resultStatusUploaded}}
+
+	t.Run("invalid mapping file", func(t *testing.T) {
+		cfg := postPushConfig{ScreenshotsDir: t.TempDir(), ScreenshotsMapping: filepath.Join(t.TempDir(), "missing.yml")}
+		err := syncScreenshots(context.Background(), cfg, results, &fakeFactory{api: &fakeAPI{}})
+		if err == nil || !strings.Contains(err.Error(), "cannot read screenshots mapping") {
+			t.Fatalf("expected mapping error, got %v", err)
+		}
+	})
+
+	t.Run("empty directory is a no-op", func(t *testing.T) {
+		factory := &fakeFactory{api: &fakeAPI{}}
+		if err := syncScreenshots(context.Background(), postPushConfig{ScreenshotsDir: t.TempDir()}, results, factory); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(factory.projects) != 0 {
+			t.Fatalf("expected no API calls, got %v", factory.projects)
+		}
+	})
+}
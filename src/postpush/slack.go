@@ -0,0 +1,183 @@
+package postpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// maxSlackFiles is how many files the Slack message lists.
+	maxSlackFiles = 20
+	// maxSlackText is the limit of the text of a Slack section block.
+	maxSlackText = 3000
+)
+
+// slackMessage is the body posted to a Slack incoming webhook. Text is the
+// fallback shown in notifications; Blocks is the message itself.
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func mrkdwn(text string) slackText {
+	return slackText{Type: "mrkdwn", Text: text}
+}
+
+// buildSlackMessage describes the push: its outcome, where it ran, the key
+// counters, the files with their result, and a link to the workflow run.
+func buildSlackMessage(cfg postPushConfig, results []uploadResult, stats map[int]keyStats) slackMessage {
+	failed := 0
+	for _, res := range results {
+		if res.Status == resultStatusFailed {
+			failed++
+		}
+	}
+	conclusion, title := checkConclusion(cfg.PushOutcome, len(results), failed)
+
+	headline := ":white_check_mark: *Lokalise push succeeded*"
+	switch conclusion {
+	case "failure":
+		headline = ":x: *Lokalise push failed*"
+	case "cancelled":
+		headline = ":warning: *Lokalise push cancelled*"
+	}
+
+	repoURL := ""
+	if cfg.ServerURL != "" && cfg.Repository != "" {
+		repoURL = strings.TrimSuffix(cfg.ServerURL, "/") + "/" + cfg.Repository
+	}
+
+	var fields []slackText
+	if cfg.Repository != "" {
+		fields = append(fields, mrkdwn("*Repository*\n"+slackLink(repoURL, cfg.Repository)))
+	}
+	if cfg.Branch != "" {
+		fields = append(fields, mrkdwn("*Branch*\n"+slackEscape(cfg.Branch)))
+	}
+	if cfg.SHA != "" {
+		commitURL := ""
+		if repoURL != "" {
+			commitURL = repoURL + "/commit/" + cfg.SHA
+		}
+		fields = append(fields, mrkdwn("*Commit*\n"+slackLink(commitURL, cfg.SHA[:min(len(cfg.SHA), 7)])))
+	}
+	if len(stats) > 0 {
+		var keys keyStats
+		for _, s := range stats {
+			keys.add(s)
+		}
+		fields = append(fields, mrkdwn(fmt.Sprintf("*Keys*\n%d inserted, %d updated, %d skipped", keys.Inserted, keys.Updated, keys.Skipped)))
+	}
+
+	text := fmt.Sprintf("%s: %s.", headline, title)
+	blocks := []slackBlock{{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}}}
+	if len(fields) > 0 {
+		blocks = append(blocks, slackBlock{Type: "section", Fields: fields})
+	}
+	if files := renderSlackFiles(results); files != "" {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: files}})
+	}
+	if url := runURL(cfg); url != "" {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{mrkdwn(slackLink(url, "View workflow run"))}})
+	}
+
+	fallback := "Lokalise push: " + title
+	if cfg.Repository != "" {
+		fallback = fmt.Sprintf("Lokalise push to %s: %s", cfg.Repository, title)
+	}
+	return slackMessage{Text: fallback, Blocks: blocks}
+}
+
+// renderSlackFiles lists the results, failed files first, up to maxSlackFiles
+// entries and the size of a section block.
+func renderSlackFiles(results []uploadResult) string {
+	ordered := make([]uploadResult, 0, len(results))
+	for _, res := range results {
+		if res.Status == resultStatusFailed {
+			ordered = append(ordered, res)
+		}
+	}
+	for _, res := range results {
+		if res.Status != resultStatusFailed {
+			ordered = append(ordered, res)
+		}
+	}
+
+	var b strings.Builder
+	for i, res := range ordered {
+		line := fmt.Sprintf("• `%s` → %s (%s)", slackEscape(res.File), slackEscape(res.ProjectID), slackEscape(res.LangISO))
+		if res.Status == resultStatusFailed {
+			line = ":x: " + line + ": " + slackEscape(strings.ReplaceAll(res.Error, "\n", " "))
+		}
+
+		more := fmt.Sprintf("\n_and %d more_", len(ordered)-i)
+		if i == maxSlackFiles || b.Len()+len(line)+1+len(more) > maxSlackText {
+			b.WriteString(more)
+			break
+		}
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// slackLink renders a link to url labelled text, or just text without a URL.
+func slackLink(url, text string) string {
+	if url == "" {
+		return slackEscape(text)
+	}
+	return "<" + url + "|" + slackEscape(text) + ">"
+}
+
+// slackEscape escapes the characters Slack treats as markup in message text.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// sendSlack posts msg to a Slack incoming webhook. Any non-2xx response is an
+// error carrying the reason Slack returned, such as invalid_payload.
+func sendSlack(ctx context.Context, cfg postPushConfig, msg slackMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("cannot encode Slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.SlackWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot create Slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "lokalise-push-action")
+
+	httpClient := &http.Client{Timeout: cfg.HTTPTimeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Slack request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	reason, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Slack returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(reason)))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package postpush
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildSlackMessage(t *testing.T) {
+	cfg := postPushConfig{
+		Repository:  "acme/app",
+		Branch:      "feature/<x>",
+		SHA:         "0123456789abcdef",
+		RunID:       "42",
+		ServerURL:   "https://github.com",
+		PushOutcome: "failure",
+	}
+	results := []uploadResult{
+		{File: "en.json", ProjectID: "p1", LangISO: "en", Status: resultStatusUploaded},
+		{File: "fr.json", ProjectID: "p1", LangISO: "fr", Status: resultStatusFailed, Error: "bad\nformat"},
+	}
+	stats := map[int]keyStats{0: {Total: 10, Inserted: 2, Updated: 3, Skipped: 5}}
+
+	msg := buildSlackMessage(cfg, results, stats)
+
+	if msg.Text != "Lokalise push to acme/app: 1 of 2 files failed to push" {
+		t.Fatalf("unexpected fallback text %q", msg.Text)
+	}
+	if len(msg.Blocks) != 4 {
+		t.Fatalf("expected 4 blocks, got %+v", msg.Blocks)
+	}
+	if got := msg.Blocks[0].Text.Text; got != ":x: *Lokalise push failed*: 1 of 2 files failed to push." {
+		t.Fatalf("unexpected headline %q", got)
+	}
+
+	var fields []string
+	for _, f := range msg.Blocks[1].Fields {
+		fields = append(fields, f.Text)
+	}
+	want := []string{
+		"*Repository*\n<https://github.com/acme/app|acme/app>",
+		"*Branch*\nfeature/&lt;x&gt;",
+		"*Commit*\n<https://github.com/acme/app/commit/0123456789abcdef|0123456>",
+		"*Keys*\n2 inserted, 3 updated, 5 skipped",
+	}
+	if strings.Join(fields, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected fields %q, got %q", want, fields)
+	}
+
+	if got := msg.Blocks[2].Text.Text; got != ":x: • `fr.json` → p1 (fr): bad format\n• `en.json` → p1 (en)" {
+		t.Fatalf("expected failed files first, got %q", got)
+	}
+	if got := msg.Blocks[3].Elements[0].Text; got != "<https://github.com/acme/app/actions/runs/42|View workflow run>" {
+		t.Fatalf("unexpected run link %q", got)
+	}
+}
+
+func TestBuildSlackMessage_Minimal(t *testing.T) {
+	msg := buildSlackMessage(postPushConfig{PushOutcome: "success"}, []uploadResult{{File: "en.json", Status: resultStatusUploaded}}, nil)
+
+	if msg.Text != "Lokalise push: 1 file pushed to Lokalise" || len(msg.Blocks) != 2 {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+	if !strings.HasPrefix(msg.Blocks[0].Text.Text, ":white_check_mark: *Lokalise push succeeded*") {
+		t.Fatalf("unexpected headline %q", msg.Blocks[0].Text.Text)
+	}
+}
+
+func TestRenderSlackFiles_Limits(t *testing.T) {
+	results := make([]uploadResult, 25)
+	for i := range results {
+		results[i] = uploadResult{File: fmt.Sprintf("%02d.json", i), ProjectID: "p1", LangISO: "en", Status: resultStatusUploaded}
+	}
+	got := renderSlackFiles(results)
+	if strings.Count(got, "\n• ") != maxSlackFiles-1 || !strings.HasSuffix(got, "\n_and 5 more_") {
+		t.Fatalf("expected %d files and a note, got %q", maxSlackFiles, got)
+	}
+
+	results = []uploadResult{
+		{File: "a.json", Status: resultStatusFailed, Error: strings.Repeat("e", 2000)},
+		{File: "b.json", Status: resultStatusFailed, Error: strings.Repeat("e", 2000)},
+	}
+	got = renderSlackFiles(results)
+	if len(got) > maxSlackText || !strings.HasSuffix(got, "\n_and 1 more_") {
+		t.Fatalf("expected the text to fit a section block, got %d bytes", len(got))
+	}
+}
+
+func TestSendSlack(t *testing.T) {
+	var got slackMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		if got.Text == "broken" {
+			http.Error(w, "invalid_blocks", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	cfg := postPushConfig{SlackWebhookURL: srv.URL, HTTPTimeout: 5 * time.Second}
+	if err := sendSlack(context.Background(), cfg, slackMessage{Text: "hello"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Text != "hello" {
+		t.Fatalf("unexpected message %+v", got)
+	}
+
+	err := sendSlack(context.Background(), cfg, slackMessage{Text: "broken"})
+	if err == nil || err.Error() != "Slack returned status 400: invalid_blocks" {
+		t.Fatalf("expected the Slack error, got %v", err)
+	}
+}
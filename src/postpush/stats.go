@@ -0,0 +1,71 @@
+package postpush
+
+import "context"
+
+// keyStats holds key counters reported by Lokalise for a finished import.
+type keyStats struct {
+	Total    int `json:"total"`
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	Skipped  int `json:"skipped"`
+}
+
+func (s *keyStats) add(o keyStats) {
+	s.Total += o.Total
+	s.Inserted += o.Inserted
+	s.Updated += o.Updated
+	s.Skipped += o.Skipped
+}
+
+// processResponse is the subset of GET /projects/{id}/processes/{pid} we use.
+type processResponse struct {
+	Process struct {
+		Status  string `json:"status"`
+		Details struct {
+			Files []struct {
+				KeyCountTotal    int `json:"key_count_total"`
+				KeyCountInserted int `json:"key_count_inserted"`
+				KeyCountUpdated  int `json:"key_count_updated"`
+				KeyCountSkipped  int `json:"key_count_skipped"`
+			} `json:"files"`
+		} `json:"details"`
+	} `json:"process"`
+}
+
+// collectKeyStats fetches key counters for every successful upload, keyed by result index.
+// Stats are best-effort: lookup failures are logged and the result is reported without them.
+func collectKeyStats(ctx context.Context, cfg postPushConfig, results []uploadResult, factory ClientFactory) map[int]keyStats {
+	stats := make(map[int]keyStats, len(results))
+	clients := make(map[string]LokaliseAPI)
+
+	for i, res := range results {
+		if res.Status != resultStatusUploaded || res.ProcessID == "" {
+			continue
+		}
+
+		api, err := cachedAPI(clients, factory, cfg, res.ProjectID)
+		if err != nil {
+			logs.Warnf("%v", err)
+			continue
+		}
+
+		proc, err := api.FetchProcess(ctx, res.ProcessID)
+		if err != nil {
+			logs.Warnf("cannot fetch key stats for %q: %v", res.File, err)
+			continue
+		}
+
+		var s keyStats
+		for _, f := range proc.Process.Details.Files {
+			s.add(keyStats{
+				Total:    f.KeyCountTotal,
+				Inserted: f.KeyCountInserted,
+				Updated:  f.KeyCountUpdated,
+				Skipped:  f.KeyCountSkipped,
+			})
+		}
+		stats[i] = s
+	}
+
+	return stats
+}
@@ -0,0 +1,73 @@
+package postpush
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func processWithFiles(counts ...[4]int) processResponse {
+	var resp processResponse
+	for _, c := range counts {
+		resp.Process.Details.Files = append(resp.Process.Details.Files, struct {
+			KeyCountTotal    int `json:"key_count_total"`
+			KeyCountInserted int `json:"key_count_inserted"`
+			KeyCountUpdated  int `json:"key_count_updated"`
+			KeyCountSkipped  int `json:"key_count_skipped"`
+		}{c[0], c[1], c[2], c[3]})
+	}
+	return resp
+}
+
+func TestCollectKeyStats(t *testing.T) {
+	results := []uploadResult{
+		{File: "en.json", ProjectID: "p1", ProcessID: "pid1", Status: resultStatusUploaded},
+		{File: "fr.json", ProjectID: "p1", ProcessID: "pid2", Status: resultStatusUploaded},
+		{File: "de.json", ProjectID: "p1", ProcessID: "pid3", Status: resultStatusFailed},
+		{File: "es.json", ProjectID: "p1", Status: resultStatusUploaded},
+	}
+
+	t.Run("sums per-file counters and reuses clients", func(t *testing.T) {
+		fetcher := &fakeAPI{processes: map[string]processResponse{
+			"pid1": processWithFiles([4]int{10, 4, 3, 3}),
+			"pid2": processWithFiles([4]int{2, 1, 1, 0}, [4]int{1, 1, 0, 0}),
+		}}
+		factory := &fakeFactory{api: fetcher}
+
+		got := collectKeyStats(context.Background(), postPushConfig{}, results, factory)
+
+		if len(got) != 2 {
+			t.Fatalf("expected stats for 2 results, got %#v", got)
+		}
+		if got[0] != (keyStats{Total: 10, Inserted: 4, Updated: 3, Skipped: 3}) {
+			t.Fatalf("unexpected stats for en.json: %#v", got[0])
+		}
+		if got[1] != (keyStats{Total: 3, Inserted: 2, Updated: 1, Skipped: 0}) {
+			t.Fatalf("unexpected stats for fr.json: %#v", got[1])
+		}
+		if len(factory.projects) != 1 {
+			t.Fatalf("expected one client per project, got %v", factory.projects)
+		}
+		if len(fetcher.processCalls) != 2 {
+			t.Fatalf("expected 2 process lookups, got %v", fetcher.processCalls)
+		}
+	})
+
+	t.Run("lookup failures are skipped", func(t *testing.T) {
+		factory := &fakeFactory{api: &fakeAPI{err: errors.New("boom")}}
+
+		got := collectKeyStats(context.Background(), postPushConfig{}, results, factory)
+		if len(got) != 0 {
+			t.Fatalf("expected no stats, got %#v", got)
+		}
+	})
+
+	t.Run("client creation failures are skipped", func(t *testing.T) {
+		factory := &fakeFactory{err: errors.New("bad token")}
+
+		got := collectKeyStats(context.Background(), postPushConfig{}, results, factory)
+		if len(got) != 0 {
+			t.Fatalf("expected no stats, got %#v", got)
+		}
+	})
+}
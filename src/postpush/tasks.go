@@ -0,0 +1,154 @@
+package postpush
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const defaultTaskTitle = "Translate new keys from {branch}"
+
+// taskLanguage assigns one target language of a task to user groups.
+type taskLanguage struct {
+	LanguageISO string  `json:"language_iso"`
+	Groups      []int64 `json:"groups"`
+}
+
+// taskRequest is the body of POST /projects/{id}/tasks.
+type taskRequest struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	TaskType    string         `json:"task_type"`
+	Keys        []int64        `json:"keys"`
+	Languages   []taskLanguage `json:"languages"`
+}
+
+// taskResponse is the subset of the create task response we use.
+type taskResponse struct {
+	Task struct {
+		TaskID int64  `json:"task_id"`
+		Title  string `json:"title"`
+	} `json:"task"`
+}
+
+// renderTemplate replaces {branch}, {pr}, {repository}, {sha}, and {run_id} in tmpl.
+// {pr} renders as "#<number>" on pull request runs and as an empty string otherwise.
+func renderTemplate(tmpl string, cfg postPushConfig) string {
+	pr := ""
+	if cfg.PRNumber != "" {
+		pr = "#" + cfg.PRNumber
+	}
+
+	r := strings.NewReplacer(
+		"{branch}", cfg.Branch,
+		"{pr}", pr,
+		"{repository}", cfg.Repository,
+		"{sha}", cfg.SHA,
+		"{run_id}", cfg.RunID,
+	)
+	return strings.TrimSpace(r.Replace(tmpl))
+}
+
+// parseGroupIDs parses comma- or newline-separated numeric Lokalise group IDs.
+func parseGroupIDs(raw string) ([]int64, error) {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+
+	var ids []int64
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(f, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid task_group_ids: %q is not a positive integer", f)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// buildTaskRequest builds a translation task covering keys for every project
+// language except the base language, assigned to the configured groups.
+func buildTaskRequest(cfg postPushConfig, keyIDs []int64, project projectResponse) taskRequest {
+	req := taskRequest{
+		Title:       renderTemplate(cfg.TaskTitle, cfg),
+		Description: taskDescription(cfg),
+		TaskType:    "translation",
+		Keys:        keyIDs,
+	}
+
+	for _, l := range project.Statistics.Languages {
+		if l.LanguageISO == "" || l.LanguageISO == cfg.BaseLang {
+			continue
+		}
+		req.Languages = append(req.Languages, taskLanguage{LanguageISO: l.LanguageISO, Groups: cfg.TaskGroupIDs})
+	}
+
+	return req
+}
+
+func taskDescription(cfg postPushConfig) string {
+	parts := []string{"Created by lokalise-push-action"}
+	if cfg.Repository != "" {
+		parts = append(parts, "repository: "+cfg.Repository)
+	}
+	if cfg.Branch != "" {
+		parts = append(parts, "branch: "+cfg.Branch)
+	}
+	if cfg.SHA != "" {
+		parts = append(parts, "commit: "+cfg.SHA)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// createTasks creates one translation task per pushed project, covering keys
+// tagged with the branch name. Projects without tagged keys or without target
+// languages are skipped.
+func createTasks(ctx context.Context, cfg postPushConfig, results []uploadResult, factory ClientFactory) error {
+	clients := make(map[string]LokaliseAPI)
+
+	for _, projectID := range pushedProjectIDs(cfg, results) {
+		api, err := cachedAPI(clients, factory, cfg, projectID)
+		if err != nil {
+			return err
+		}
+
+		keys, err := api.ListKeysByTag(ctx, cfg.Branch)
+		if err != nil {
+			return fmt.Errorf("cannot list keys tagged %q in project %s: %w", cfg.Branch, projectID, err)
+		}
+
+		keyIDs := make([]int64, 0, len(keys))
+		for _, k := range keys {
+			keyIDs = append(keyIDs, k.KeyID)
+		}
+		if len(keyIDs) == 0 {
+			logs.Infof("Project %s: no keys tagged %q, task not created", projectID, cfg.Branch)
+			continue
+		}
+
+		project, err := api.FetchProject(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot fetch languages for project %s: %w", projectID, err)
+		}
+
+		req := buildTaskRequest(cfg, keyIDs, project)
+		if len(req.Languages) == 0 {
+			logs.Infof("Project %s: no target languages, task not created", projectID)
+			continue
+		}
+
+		resp, err := api.CreateTask(ctx, req)
+		if err != nil {
+			return fmt.Errorf("cannot create task in project %s: %w", projectID, err)
+		}
+
+		logs.Infof("Project %s: created task %d %q covering %d keys", projectID, resp.Task.TaskID, req.Title, len(keyIDs))
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package postpush
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	cfg := postPushConfig{Branch: "feature/x", Repository: "acme/app", SHA: "abc", RunID: "9", PRNumber: "42"}
+
+	got := renderTemplate("{repository}: {branch} {pr} @ {sha} run {run_id}", cfg)
+	if got != "acme/app: feature/x #42 @ abc run 9" {
+		t.Fatalf("unexpected render: %q", got)
+	}
+
+	cfg.PRNumber = ""
+	if got := renderTemplate("Keys from {branch} {pr}", cfg); got != "Keys from feature/x" {
+		t.Fatalf("unexpected render without PR: %q", got)
+	}
+}
+
+func TestParseGroupIDs(t *testing.T) {
+	got, err := parseGroupIDs(" 12,\n34 ,, ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []int64{12, 34}) {
+		t.Fatalf("unexpected ids: %v", got)
+	}
+
+	if got, err := parseGroupIDs(""); err != nil || len(got) != 0 {
+		t.Fatalf("expected no ids, got %v err=%v", got, err)
+	}
+
+	for _, raw := range []string{"abc", "0", "-3"} {
+		if _, err := parseGroupIDs(raw); err == nil || !strings.Contains(err.Error(), "invalid task_group_ids") {
+			t.Fatalf("expected error for %q, got %v", raw, err)
+		}
+	}
+}
+
+func TestBuildTaskRequest(t *testing.T) {
+	cfg := postPushConfig{
+		Branch:       "main",
+		Repository:   "acme/app",
+		SHA:          "abc",
+		BaseLang:     "en",
+		TaskTitle:    defaultTaskTitle,
+		TaskGroupIDs: []int64{5},
+	}
+
+	got := buildTaskRequest(cfg, []int64{1, 2}, sampleProject(t))
+
+	want := taskRequest{
+		Title:       "Translate new keys from main",
+		Description: "Created by lokalise-push-action, repository: acme/app, branch: main, commit: abc",
+		TaskType:    "translation",
+		Keys:        []int64{1, 2},
+		Languages:   []taskLanguage{{LanguageISO: "fr", Groups: []int64{5}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestCreateTasks(t *testing.T) {
+	cfg := postPushConfig{Branch: "main", BaseLang: "en", TaskTitle: defaultTaskTitle, TaskGroupIDs: []int64{5}}
+	results := []uploadResult{{ProjectID: "p1", Status: resultStatusUploaded}}
+
+	t.Run("creates task for tagged keys", func(t *testing.T) {
+		api := &fakeAPI{keys: []lokaliseKey{{KeyID: 10}, {KeyID: 11}}, project: sampleProject(t)}
+
+		if err := createTasks(context.Background(), cfg, results, &fakeFactory{api: api}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(api.tags, []string{"main"}) {
+			t.Fatalf("expected keys listed by branch tag, got %v", api.tags)
+		}
+		if len(api.tasks) != 1 || !reflect.DeepEqual(api.tasks[0].Keys, []int64{10, 11}) {
+			t.Fatalf("unexpected tasks: %#v", api.tasks)
+		}
+	})
+
+	t.Run("no tagged keys skips task", func(t *testing.T) {
+		api := &fakeAPI{project: sampleProject(t)}
+
+		if err := createTasks(context.Background(), cfg, results, &fakeFactory{api: api}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.tasks) != 0 || api.projectCalls != 0 {
+			t.Fatalf("expected no task and no project lookup, got %#v", api.tasks)
+		}
+	})
+
+	t.Run("no target languages skips task", func(t *testing.T) {
+		api := &fakeAPI{keys: []lokaliseKey{{KeyID: 1}}}
+
+		if err := createTasks(context.Background(), cfg, results, &fakeFactory{api: api}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(api.tasks) != 0 {
+			t.Fatalf("expected no task, got %#v", api.tasks)
+		}
+	})
+
+	t.Run("API errors are returned", func(t *testing.T) {
+		err := createTasks(context.Background(), cfg, results, &fakeFactory{api: &fakeAPI{err: errors.New("boom")}})
+		if err == nil || !strings.Contains(err.Error(), "cannot list keys") {
+			t.Fatalf("expected list error, got %v", err)
+		}
+	})
+
+	t.Run("client errors are returned", func(t *testing.T) {
+		err := createTasks(context.Background(), cfg, results, &fakeFactory{err: errors.New("bad token")})
+		if err == nil || !strings.Contains(err.Error(), "project p1") {
+			t.Fatalf("expected client error, got %v", err)
+		}
+	})
+}
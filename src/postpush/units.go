@@ -0,0 +1,63 @@
+package postpush
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"lokalise-push-action/internal/projectmap"
+	"lokalise-push-action/internal/repoconfig"
+)
+
+// unitProjectTokens returns the dedicated tokens set by the project_mappings of
+// the units listed in the units_report of a units_pattern push, so every
+// project is reported on with the token its files were uploaded with. The
+// other projects of the units use the default token, as their uploads did.
+func unitProjectTokens(rawReport string) (map[string]string, error) {
+	if strings.TrimSpace(rawReport) == "" {
+		return nil, nil
+	}
+
+	var units []struct {
+		Unit string `json:"unit"`
+	}
+	if err := json.Unmarshal([]byte(rawReport), &units); err != nil {
+		return nil, fmt.Errorf("invalid UNITS_REPORT: %w", err)
+	}
+
+	var tokens map[string]string
+	for _, u := range units {
+		values, err := repoconfig.Load(u.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("unit %s: %w", u.Unit, err)
+		}
+		mappings, err := projectmap.ParseValue(values["PROJECT_MAPPINGS"])
+		if err != nil {
+			return nil, fmt.Errorf("unit %s: %w", u.Unit, err)
+		}
+		for projectID, token := range projectmap.TokensByProject(mappings) {
+			if tokens == nil {
+				tokens = make(map[string]string)
+			}
+			if _, ok := tokens[projectID]; !ok {
+				tokens[projectID] = token
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// mergeTokens adds the tokens of extra missing from base, which wins for the
+// projects both configure.
+func mergeTokens(base, extra map[string]string) map[string]string {
+	if len(extra) == 0 {
+		return base
+	}
+	out := maps.Clone(extra)
+	if out == nil {
+		out = make(map[string]string)
+	}
+	maps.Copy(out, base)
+	return out
+}
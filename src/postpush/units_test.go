@@ -0,0 +1,77 @@
+package postpush
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnitProjectTokens(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for file, content := range map[string]string{
+		"web.yml":    "project_id: 1.web\nproject_mappings: [apps/web/admin=2.admin:admin-token]\n",
+		"mobile.yml": "project_id: 3.mobile\nproject_mappings: [apps/mobile/extra=2.admin:other-token, apps/mobile/ios=4.ios:ios-token]\n",
+		"plain.yml":  "project_id: 5.plain\n",
+		"bad.yml":    "project_mappings: [apps=]\n",
+	} {
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := unitProjectTokens(`[{"unit":"web.yml"},{"unit":"mobile.yml"},{"unit":"plain.yml"}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string]string{"2.admin": "admin-token", "4.ios": "ios-token"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got, err := unitProjectTokens(" "); err != nil || got != nil {
+		t.Fatalf("expected no tokens without a units report, got %v (%v)", got, err)
+	}
+
+	for raw, wantErr := range map[string]string{
+		"{":                        "invalid UNITS_REPORT",
+		`[{"unit":"bad.yml"}]`:     `unit bad.yml: invalid project_mappings`,
+		`[{"unit":"missing.yml"}]`: "unit missing.yml",
+	} {
+		if _, err := unitProjectTokens(raw); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected error containing %q, got %v", raw, wantErr, err)
+		}
+	}
+}
+
+func TestPrepareConfig_UnitTokens(t *testing.T) {
+	dir := t.TempDir()
+	unit := filepath.Join(dir, "unit.yml")
+	if err := os.WriteFile(unit, []byte("project_mappings: [apps/a=1.a:unit-token, apps/b=2.b:unit-b-token]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LOKALISE_API_TOKEN", "default-token")
+	t.Setenv("PROJECT_MAPPINGS", "apps/a=1.a:top-token")
+	t.Setenv("UNITS_REPORT", `[{"unit":"`+filepath.ToSlash(unit)+`"}]`)
+
+	cfg, err := prepareConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for projectID, want := range map[string]string{"1.a": "top-token", "2.b": "unit-b-token", "3.c": "default-token"} {
+		if got := cfg.tokenFor(projectID); got != want {
+			t.Fatalf("%s: expected %q, got %q", projectID, want, got)
+		}
+	}
+}
+
+func TestMergeTokens(t *testing.T) {
+	base := map[string]string{"1.a": "base"}
+	if got := mergeTokens(base, nil); !reflect.DeepEqual(got, base) {
+		t.Fatalf("expected base to be kept, got %v", got)
+	}
+	got := mergeTokens(nil, map[string]string{"1.a": "extra"})
+	if want := map[string]string{"1.a": "extra"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
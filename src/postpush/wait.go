@@ -0,0 +1,90 @@
+package postpush
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"lokalise-push-action/internal/ghoutput"
+)
+
+// Statuses of an upload process that Lokalise won't change anymore.
+const (
+	processFinished  = "finished"
+	processFailed    = "failed"
+	processCancelled = "cancelled"
+)
+
+// waitInterval is the pause between two rounds of process lookups.
+// Overridable in tests.
+var waitInterval = 5 * time.Second
+
+// Wait blocks until Lokalise has processed the uploads recorded in REPORT_DIR,
+// which only matters when they were started with polling disabled. It exits
+// the process when an import failed or POST_PUSH_TIMEOUT runs out first.
+func Wait() {
+	if err := runWith(
+		prepareConfig,
+		validate,
+		loadUploadResults,
+		waitForImports,
+		&LokaliseFactory{},
+		ghoutput.Writer(logs),
+	); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// waitForImports polls the upload process of every successful upload until
+// it is over. Failed or cancelled imports are reported together at the end.
+func waitForImports(ctx context.Context, cfg postPushConfig, results []uploadResult, factory ClientFactory, _ func(string, string) bool) error {
+	var pending []uploadResult
+	for _, res := range results {
+		if res.Status == resultStatusUploaded && res.ProcessID != "" {
+			pending = append(pending, res)
+		}
+	}
+
+	clients := make(map[string]LokaliseAPI)
+	var failed []string
+
+	for len(pending) > 0 {
+		var running []uploadResult
+		for _, res := range pending {
+			api, err := cachedAPI(clients, factory, cfg, res.ProjectID)
+			if err != nil {
+				return err
+			}
+
+			proc, err := api.FetchProcess(ctx, res.ProcessID)
+			if err != nil {
+				return fmt.Errorf("cannot fetch upload process %s of %q: %w", res.ProcessID, res.File, err)
+			}
+
+			switch status := proc.Process.Status; status {
+			case processFinished:
+				logs.Infof("Import of %q finished", res.File)
+			case processFailed, processCancelled:
+				failed = append(failed, fmt.Sprintf("%s (%s)", res.File, status))
+			default:
+				running = append(running, res)
+			}
+		}
+
+		if pending = running; len(pending) == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d upload process(es) still running: %w", len(pending), ctx.Err())
+		case <-time.After(waitInterval):
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("Lokalise could not import %d file(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
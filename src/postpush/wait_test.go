@@ -0,0 +1,94 @@
+package postpush
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sequenceAPI returns the next status of each process on every lookup.
+type sequenceAPI struct {
+	fakeAPI
+	statuses map[string][]string
+}
+
+func (a *sequenceAPI) FetchProcess(_ context.Context, processID string) (processResponse, error) {
+	a.processCalls = append(a.processCalls, processID)
+	if a.err != nil {
+		return processResponse{}, a.err
+	}
+
+	var resp processResponse
+	if queue := a.statuses[processID]; len(queue) > 0 {
+		resp.Process.Status = queue[0]
+		a.statuses[processID] = queue[1:]
+	}
+	return resp, nil
+}
+
+type sequenceFactory struct{ api *sequenceAPI }
+
+func (f *sequenceFactory) NewAPI(postPushConfig, string) (LokaliseAPI, error) { return f.api, nil }
+
+func TestWaitForImports(t *testing.T) {
+	interval := waitInterval
+	waitInterval = time.Millisecond
+	t.Cleanup(func() { waitInterval = interval })
+
+	results := []uploadResult{
+		{File: "en.json", ProjectID: "p1", ProcessID: "pid1", Status: resultStatusUploaded},
+		{File: "fr.json", ProjectID: "p1", ProcessID: "pid2", Status: resultStatusUploaded},
+		{File: "de.json", ProjectID: "p1", ProcessID: "pid3", Status: resultStatusFailed},
+		{File: "es.json", ProjectID: "p1", Status: resultStatusUploaded},
+	}
+
+	t.Run("polls until every import is over", func(t *testing.T) {
+		api := &sequenceAPI{statuses: map[string][]string{
+			"pid1": {"queued", "running", processFinished},
+			"pid2": {processFinished},
+		}}
+
+		err := waitForImports(context.Background(), postPushConfig{}, results, &sequenceFactory{api}, noopWrite)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "pid1,pid2,pid1,pid1"
+		if got := strings.Join(api.processCalls, ","); got != want {
+			t.Fatalf("expected lookups %s, got %s", want, got)
+		}
+	})
+
+	t.Run("failed imports are reported", func(t *testing.T) {
+		api := &sequenceAPI{statuses: map[string][]string{
+			"pid1": {processFailed},
+			"pid2": {"running", processCancelled},
+		}}
+
+		err := waitForImports(context.Background(), postPushConfig{}, results, &sequenceFactory{api}, noopWrite)
+		if err == nil || !strings.Contains(err.Error(), "could not import 2 file(s): en.json (failed), fr.json (cancelled)") {
+			t.Fatalf("expected failed imports error, got %v", err)
+		}
+	})
+
+	t.Run("lookup errors stop the wait", func(t *testing.T) {
+		api := &sequenceAPI{fakeAPI: fakeAPI{err: errors.New("boom")}}
+
+		err := waitForImports(context.Background(), postPushConfig{}, results, &sequenceFactory{api}, noopWrite)
+		if err == nil || !strings.Contains(err.Error(), `cannot fetch upload process pid1 of "en.json": boom`) {
+			t.Fatalf("expected lookup error, got %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		api := &sequenceAPI{statuses: map[string][]string{}}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := waitForImports(ctx, postPushConfig{}, results, &sequenceFactory{api}, noopWrite)
+		if err == nil || !strings.Contains(err.Error(), "2 upload process(es) still running") {
+			t.Fatalf("expected timeout error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package postpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	webhookEvent           = "push_completed"
+	webhookEventHeader     = "X-Lokalise-Push-Event"
+	webhookSignatureHeader = "X-Lokalise-Push-Signature-256"
+)
+
+// webhookFile describes one uploaded file in the webhook payload.
+type webhookFile struct {
+	File      string    `json:"file"`
+	ProjectID string    `json:"project_id"`
+	LangISO   string    `json:"lang_iso"`
+	ProcessID string    `json:"process_id,omitempty"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Keys      *keyStats `json:"keys,omitempty"`
+}
+
+// webhookPayload is the JSON body sent to WEBHOOK_URL.
+type webhookPayload struct {
+	Event      string        `json:"event"`
+	Repository string        `json:"repository"`
+	Branch     string        `json:"branch"`
+	SHA        string        `json:"sha"`
+	RunID      string        `json:"run_id"`
+	Files      []webhookFile `json:"files"`
+	Keys       keyStats      `json:"keys"`
+}
+
+// buildWebhookPayload combines run metadata, upload results, and key stats.
+// Keys holds the totals across all files with known stats.
+func buildWebhookPayload(cfg postPushConfig, results []uploadResult, stats map[int]keyStats) webhookPayload {
+	payload := webhookPayload{
+		Event:      webhookEvent,
+		Repository: cfg.Repository,
+		Branch:     cfg.Branch,
+		SHA:        cfg.SHA,
+		RunID:      cfg.RunID,
+		Files:      make([]webhookFile, 0, len(results)),
+	}
+
+	for i, res := range results {
+		f := webhookFile{
+			File:      res.File,
+			ProjectID: res.ProjectID,
+			LangISO:   res.LangISO,
+			ProcessID: res.ProcessID,
+			Status:    res.Status,
+			Error:     res.Error,
+		}
+		if s, ok := stats[i]; ok {
+			f.Keys = &s
+			payload.Keys.add(s)
+		}
+		payload.Files = append(payload.Files, f)
+	}
+
+	return payload
+}
+
+// signPayload returns the hex-encoded HMAC-SHA256 of body, prefixed with "sha256=".
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendWebhook POSTs payload to cfg.WebhookURL, rendered with the webhook template
+// when one is configured. When WEBHOOK_SECRET is set the body is signed so
+// receivers can verify its origin. Any non-2xx response is an error.
+func sendWebhook(ctx context.Context, cfg postPushConfig, payload webhookPayload) error {
+	body, err := renderWebhookBody(cfg, payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "lokalise-push-action")
+	req.Header.Set(webhookEventHeader, webhookEvent)
+	if cfg.WebhookSecret != "" {
+		req.Header.Set(webhookSignatureHeader, signPayload(cfg.WebhookSecret, body))
+	}
+
+	httpClient := &http.Client{Timeout: cfg.HTTPTimeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package postpush
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildWebhookPayload(t *testing.T) {
+	cfg := postPushConfig{Repository: "acme/app", Branch: "main", SHA: "abc", RunID: "7"}
+	results := []uploadResult{
+		{File: "en.json", ProjectID: "p1", LangISO: "en", ProcessID: "pid1", Status: resultStatusUploaded},
+		{File: "fr.json", ProjectID: "p1", LangISO: "fr", Status: resultStatusFailed, Error: "boom"},
+		{File: "de.json", ProjectID: "p1", LangISO: "de", ProcessID: "pid3", Status: resultStatusUploaded},
+	}
+	stats := map[int]keyStats{
+		0: {Total: 5, Inserted: 2, Updated: 1, Skipped: 2},
+		2: {Total: 3, Inserted: 3},
+	}
+
+	got := buildWebhookPayload(cfg, results, stats)
+
+	if got.Event != webhookEvent || got.Repository != "acme/app" || got.Branch != "main" || got.SHA != "abc" || got.RunID != "7" {
+		t.Fatalf("unexpected metadata: %#v", got)
+	}
+	if len(got.Files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(got.Files))
+	}
+	if got.Files[0].Keys == nil || *got.Files[0].Keys != stats[0] {
+		t.Fatalf("unexpected keys for en.json: %#v", got.Files[0].Keys)
+	}
+	if got.Files[1].Keys != nil || got.Files[1].Error != "boom" {
+		t.Fatalf("unexpected entry for fr.json: %#v", got.Files[1])
+	}
+	if got.Keys != (keyStats{Total: 8, Inserted: 5, Updated: 1, Skipped: 2}) {
+		t.Fatalf("unexpected totals: %#v", got.Keys)
+	}
+}
+
+func TestSignPayload(t *testing.T) {
+	// Reference value: printf '{"a":1}' | openssl dgst -sha256 -hmac secret
+	got := signPayload("secret", []byte(`{"a":1}`))
+	want := "sha256=aa9e2e3575f5d7098b6caccd790888c36d5fdb63342a73bada2d6a51747a8494"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSendWebhook(t *testing.T) {
+	payload := webhookPayload{Event: webhookEvent, Repository: "acme/app", Files: []webhookFile{{File: "en.json"}}}
+
+	t.Run("posts signed JSON", func(t *testing.T) {
+		var gotBody []byte
+		var gotHeaders http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				t.Errorf("expected POST, got %s", r.Method)
+			}
+			gotHeaders = r.Header.Clone()
+			gotBody, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		cfg := postPushConfig{WebhookURL: srv.URL, WebhookSecret: "s3cret", HTTPTimeout: 5 * time.Second}
+		if err := sendWebhook(context.Background(), cfg, payload); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if gotHeaders.Get("Content-Type") != "application/json" {
+			t.Fatalf("unexpected content type: %q", gotHeaders.Get("Content-Type"))
+		}
+		if gotHeaders.Get(webhookEventHeader) != webhookEvent {
+			t.Fatalf("unexpected event header: %q", gotHeaders.Get(webhookEventHeader))
+		}
+		if sig := gotHeaders.Get(webhookSignatureHeader); sig != signPayload("s3cret", gotBody) {
+			t.Fatalf("signature mismatch: %q", sig)
+		}
+
+		var decoded webhookPayload
+		if err := json.Unmarshal(gotBody, &decoded); err != nil {
+			t.Fatalf("invalid body: %v", err)
+		}
+		if decoded.Repository != "acme/app" || len(decoded.Files) != 1 {
+			t.Fatalf("unexpected body: %#v", decoded)
+		}
+	})
+
+	t.Run("no secret means no signature", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(webhookSignatureHeader) != "" {
+				t.Errorf("unexpected signature header")
+			}
+		}))
+		defer srv.Close()
+
+		cfg := postPushConfig{WebhookURL: srv.URL, HTTPTimeout: 5 * time.Second}
+		if err := sendWebhook(context.Background(), cfg, payload); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("non-2xx status is an error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer srv.Close()
+
+		cfg := postPushConfig{WebhookURL: srv.URL, HTTPTimeout: 5 * time.Second}
+		err := sendWebhook(context.Background(), cfg, payload)
+		if err == nil || !strings.Contains(err.Error(), "status 502") {
+			t.Fatalf("expected status error, got %v", err)
+		}
+	})
+}
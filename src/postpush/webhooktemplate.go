@@ -0,0 +1,135 @@
+package postpush
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+const (
+	webhookFormatJSON  = "json"
+	webhookFormatTeams = "teams"
+)
+
+// webhookTemplateData is what WEBHOOK_TEMPLATE renders: the fields of the
+// default payload plus links and a one-line summary of the push.
+type webhookTemplateData struct {
+	webhookPayload
+	Summary   string
+	RunURL    string
+	CommitURL string
+}
+
+// teamsWebhookTemplate renders the push as an Adaptive Card, the payload of
+// Microsoft Teams incoming webhooks and workflows.
+const teamsWebhookTemplate = `{
+  "type": "message",
+  "attachments": [{
+    "contentType": "application/vnd.microsoft.card.adaptive",
+    "content": {
+      "$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+      "type": "AdaptiveCard",
+      "version": "1.4",
+      "body": [
+        {"type": "TextBlock", "size": "Medium", "weight": "Bolder", "wrap": true, "text": {{ json (printf "Lokalise push: %s" .Summary) }}},
+        {"type": "FactSet", "facts": [
+          {"title": "Repository", "value": {{ json .Repository }}},
+          {"title": "Branch", "value": {{ json .Branch }}},
+          {"title": "Commit", "value": {{ json .SHA }}},
+          {"title": "Keys", "value": {{ json (printf "%d inserted, %d updated, %d skipped" .Keys.Inserted .Keys.Updated .Keys.Skipped) }}}
+        ]},
+        {"type": "FactSet", "facts": [{{ range $i, $f := .Files }}{{ if $i }},{{ end }}
+          {"title": {{ json $f.File }}, "value": {{ json (printf "%s · %s · %s" $f.ProjectID $f.LangISO $f.Status) }}}{{ end }}
+        ]}
+      ]{{ if .RunURL }},
+      "actions": [{"type": "Action.OpenUrl", "title": "View workflow run", "url": {{ json .RunURL }}}]{{ end }}
+    }
+  }]
+}`
+
+var webhookTemplateFuncs = template.FuncMap{
+	// json encodes a value as JSON, so templates never have to quote or escape.
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// loadWebhookTemplate returns the template rendering the webhook body, or nil
+// when the default payload is sent as is.
+func loadWebhookTemplate(cfg postPushConfig) (*template.Template, error) {
+	text := cfg.WebhookTemplate
+	switch {
+	case cfg.WebhookTemplateFile != "":
+		data, err := os.ReadFile(cfg.WebhookTemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read webhook template: %w", err)
+		}
+		text = string(data)
+	case text == "" && cfg.WebhookFormat == webhookFormatTeams:
+		text = teamsWebhookTemplate
+	}
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New("webhook").Funcs(webhookTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderWebhookBody encodes payload as JSON, or renders it with the webhook
+// template. A rendered body must be valid JSON; it is sent compacted.
+func renderWebhookBody(cfg postPushConfig, payload webhookPayload) ([]byte, error) {
+	tmpl, err := loadWebhookTemplate(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl == nil {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode webhook payload: %w", err)
+		}
+		return body, nil
+	}
+
+	failed := 0
+	for _, f := range payload.Files {
+		if f.Status == resultStatusFailed {
+			failed++
+		}
+	}
+	_, summary := checkConclusion("success", len(payload.Files), failed)
+
+	data := webhookTemplateData{webhookPayload: payload, Summary: summary, RunURL: runURL(cfg)}
+	if cfg.ServerURL != "" && cfg.Repository != "" && cfg.SHA != "" {
+		data.CommitURL = strings.TrimSuffix(cfg.ServerURL, "/") + "/" + cfg.Repository + "/commit/" + cfg.SHA
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return nil, fmt.Errorf("cannot render webhook template: %w", err)
+	}
+	var body bytes.Buffer
+	if err := json.Compact(&body, rendered.Bytes()); err != nil {
+		return nil, fmt.Errorf("webhook template did not render valid JSON: %w", err)
+	}
+	return body.Bytes(), nil
+}
+
+// parseWebhookFormat reads WEBHOOK_FORMAT; empty means the default JSON payload.
+func parseWebhookFormat(raw string) (string, error) {
+	switch format := strings.ToLower(strings.TrimSpace(raw)); format {
+	case "", webhookFormatJSON:
+		return webhookFormatJSON, nil
+	case webhookFormatTeams:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid WEBHOOK_FORMAT %q: expected json or teams", raw)
+	}
+}
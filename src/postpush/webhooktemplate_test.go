@@ -0,0 +1,174 @@
+package postpush
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func templatePayload() webhookPayload {
+	return webhookPayload{
+		Event:      webhookEvent,
+		Repository: "acme/app",
+		Branch:     "main",
+		SHA:        "abc",
+		RunID:      "7",
+		Files: []webhookFile{
+			{File: "en.json", ProjectID: "p1", LangISO: "en", Status: resultStatusUploaded},
+			{File: `fr "quoted".json`, ProjectID: "p1", LangISO: "fr", Status: resultStatusUploaded},
+		},
+		Keys: keyStats{Total: 9, Inserted: 4, Updated: 2, Skipped: 3},
+	}
+}
+
+func TestRenderWebhookBody_Default(t *testing.T) {
+	payload := templatePayload()
+	got, err := renderWebhookBody(postPushConfig{WebhookFormat: webhookFormatJSON}, payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, _ := json.Marshal(payload)
+	if string(got) != string(want) {
+		t.Fatalf("expected the default payload %s, got %s", want, got)
+	}
+}
+
+func TestRenderWebhookBody_Template(t *testing.T) {
+	cfg := postPushConfig{
+		ServerURL:  "https://github.com",
+		Repository: "acme/app",
+		SHA:        "abc",
+		RunID:      "7",
+		WebhookTemplate: `{
+  "text": {{ json .Summary }},
+  "files": [{{ range $i, $f := .Files }}{{ if $i }}, {{ end }}{{ json $f.File }}{{ end }}],
+  "inserted": {{ .Keys.Inserted }},
+  "run": {{ json .RunURL }},
+  "commit": {{ json .CommitURL }}
+}`,
+	}
+
+	got, err := renderWebhookBody(cfg, templatePayload())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"text":"2 files pushed to Lokalise","files":["en.json","fr \"quoted\".json"],"inserted":4,"run":"https://github.com/acme/app/actions/runs/7","commit":"https://github.com/acme/app/commit/abc"}`
+	if string(got) != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestRenderWebhookBody_TemplateFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hook.tmpl")
+	if err := os.WriteFile(path, []byte(`{"repo": {{ json .Repository }}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := renderWebhookBody(postPushConfig{WebhookTemplateFile: path}, templatePayload())
+	if err != nil || string(got) != `{"repo":"acme/app"}` {
+		t.Fatalf("unexpected body %s (%v)", got, err)
+	}
+}
+
+func TestRenderWebhookBody_Teams(t *testing.T) {
+	cfg := postPushConfig{ServerURL: "https://github.com", Repository: "acme/app", RunID: "7", WebhookFormat: webhookFormatTeams}
+
+	got, err := renderWebhookBody(cfg, templatePayload())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var msg struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Text  string `json:"text"`
+					Facts []struct {
+						Title string `json:"title"`
+						Value string `json:"value"`
+					} `json:"facts"`
+				} `json:"body"`
+				Actions []struct {
+					URL string `json:"url"`
+				} `json:"actions"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(got, &msg); err != nil {
+		t.Fatalf("invalid card: %v\n%s", err, got)
+	}
+	if msg.Type != "message" || len(msg.Attachments) != 1 || msg.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("unexpected message %s", got)
+	}
+	card := msg.Attachments[0].Content
+	if card.Type != "AdaptiveCard" || card.Body[0].Text != "Lokalise push: 2 files pushed to Lokalise" {
+		t.Fatalf("unexpected card %s", got)
+	}
+	if keys := card.Body[1].Facts[3]; keys.Value != "4 inserted, 2 updated, 3 skipped" {
+		t.Fatalf("unexpected keys fact %+v", keys)
+	}
+	if files := card.Body[2].Facts; len(files) != 2 || files[1].Title != `fr "quoted".json` || files[1].Value != "p1 · fr · uploaded" {
+		t.Fatalf("unexpected file facts %+v", files)
+	}
+	if len(card.Actions) != 1 || card.Actions[0].URL != "https://github.com/acme/app/actions/runs/7" {
+		t.Fatalf("unexpected actions %+v", card.Actions)
+	}
+}
+
+func TestRenderWebhookBody_Errors(t *testing.T) {
+	tests := []struct {
+		name, template, wantErr string
+	}{
+		{"parse error", "{{ .Files", "invalid webhook template"},
+		{"unknown field", "{{ .Nope }}", "cannot render webhook template"},
+		{"not JSON", "text: {{ .Repository }}", "did not render valid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := renderWebhookBody(postPushConfig{WebhookTemplate: tt.template}, templatePayload())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSendWebhook_SignsRenderedBody(t *testing.T) {
+	var gotBody []byte
+	var gotSignature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(webhookSignatureHeader)
+	}))
+	defer srv.Close()
+
+	cfg := postPushConfig{WebhookURL: srv.URL, WebhookSecret: "s3cret", WebhookTemplate: `{"repo": {{ json .Repository }}}`, HTTPTimeout: 5 * time.Second}
+	if err := sendWebhook(context.Background(), cfg, templatePayload()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(gotBody) != `{"repo":"acme/app"}` || gotSignature != signPayload("s3cret", gotBody) {
+		t.Fatalf("expected the rendered body to be signed, got %s with %q", gotBody, gotSignature)
+	}
+}
+
+func TestParseWebhookFormat(t *testing.T) {
+	for raw, want := range map[string]string{"": webhookFormatJSON, "JSON": webhookFormatJSON, " teams ": webhookFormatTeams} {
+		if got, err := parseWebhookFormat(raw); err != nil || got != want {
+			t.Errorf("%q: expected %q, got %q (%v)", raw, want, got, err)
+		}
+	}
+	if _, err := parseWebhookFormat("slack"); err == nil {
+		t.Fatalf("expected an error for an unknown format")
+	}
+}
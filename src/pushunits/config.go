@@ -0,0 +1,73 @@
+package pushunits
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bodrovis/lokalise-actions-common/v2/parsers"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+// defaultPattern matches the repository configuration file of every unit.
+const defaultPattern = "**/lokalise-push.yml"
+
+// config describes which units to push and how.
+type config struct {
+	Patterns []string // UNITS_PATTERN globs, "!" lines exclude
+	PushAll  bool     // UNITS_PUSH_ALL: upload every file instead of the changed ones
+	TempDir  string   // parent of the working directory of the run
+}
+
+// prepareConfig reads the configuration from the environment, reporting
+// every invalid variable at once.
+func prepareConfig() (config, error) {
+	var errs []error
+
+	patterns, err := parsePatterns()
+	errs = append(errs, err)
+
+	pushAll, err := envconf.ParseBoolEnv("UNITS_PUSH_ALL")
+	errs = append(errs, err)
+
+	if err := envconf.Join(errs); err != nil {
+		return config{}, err
+	}
+
+	return config{
+		Patterns: patterns,
+		PushAll:  pushAll,
+		TempDir:  strings.TrimSpace(os.Getenv("RUNNER_TEMP")),
+	}, nil
+}
+
+// parsePatterns reads the newline-separated UNITS_PATTERN, defaulting to
+// defaultPattern. Lines starting with "!" exclude matching files.
+func parsePatterns() ([]string, error) {
+	raw := parsers.ParseStringArrayEnv("UNITS_PATTERN")
+	if len(raw) == 0 {
+		return []string{defaultPattern}, nil
+	}
+
+	patterns := make([]string, 0, len(raw))
+	included := false
+	for _, p := range raw {
+		prefix := ""
+		if rest, ok := strings.CutPrefix(p, "!"); ok {
+			prefix, p = "!", rest
+		} else {
+			included = true
+		}
+		clean, err := pathnorm.EnsureRepoRelativePattern(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid UNITS_PATTERN %q: %w", p, err)
+		}
+		patterns = append(patterns, prefix+clean)
+	}
+	if !included {
+		return nil, fmt.Errorf("invalid UNITS_PATTERN: at least one pattern must not start with \"!\"")
+	}
+	return patterns, nil
+}
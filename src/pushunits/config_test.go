@@ -0,0 +1,64 @@
+package pushunits
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPrepareConfig(t *testing.T) {
+	t.Setenv("UNITS_PATTERN", "apps/*/lokalise-push.yml\n./packages/**/lokalise-push.yml\n!packages/legacy/**")
+	t.Setenv("UNITS_PUSH_ALL", "true")
+	t.Setenv("RUNNER_TEMP", "/tmp/runner")
+
+	cfg, err := prepareConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := config{
+		Patterns: []string{"apps/*/lokalise-push.yml", "packages/**/lokalise-push.yml", "!packages/legacy/**"},
+		PushAll:  true,
+		TempDir:  "/tmp/runner",
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("mismatch.\nwant=%+v\ngot=%+v", want, cfg)
+	}
+}
+
+func TestPrepareConfig_Defaults(t *testing.T) {
+	t.Setenv("UNITS_PATTERN", "")
+	t.Setenv("UNITS_PUSH_ALL", "")
+
+	cfg, err := prepareConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Patterns, []string{defaultPattern}) || cfg.PushAll {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+}
+
+func TestPrepareConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		pushAll string
+		wantErr string
+	}{
+		{"pattern outside the repository", "../lokalise-push.yml", "", `invalid UNITS_PATTERN "../lokalise-push.yml"`},
+		{"only exclusions", "!apps/**", "", "at least one pattern must not start with"},
+		{"invalid UNITS_PUSH_ALL", "", "maybe", "invalid UNITS_PUSH_ALL"},
+		{"every problem", "/abs.yml", "maybe", "2 configuration problems"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("UNITS_PATTERN", tt.pattern)
+			t.Setenv("UNITS_PUSH_ALL", tt.pushAll)
+
+			if _, err := prepareConfig(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package pushunits
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// skippedDirs are never searched for units: they hold third-party code, whose
+// configuration files describe someone else's project.
+var skippedDirs = []string{"node_modules", "vendor"}
+
+// findUnits returns the configuration files in fsys matching patterns, sorted
+// and without duplicates. Hidden directories and skippedDirs are not searched;
+// a "!" pattern removes the files it matches.
+func findUnits(fsys fs.FS, patterns []string) ([]string, error) {
+	var includes, excludes []string
+	for _, p := range patterns {
+		if rest, ok := strings.CutPrefix(p, "!"); ok {
+			excludes = append(excludes, rest)
+		} else {
+			includes = append(includes, p)
+		}
+	}
+
+	var units []string
+	for _, p := range includes {
+		matches, err := doublestar.Glob(fsys, p, doublestar.WithFilesOnly(), doublestar.WithNoFollow(), doublestar.WithNoHidden())
+		if err != nil {
+			return nil, fmt.Errorf("cannot search units with %q: %w", p, err)
+		}
+		for _, m := range matches {
+			if !inSkippedDir(m) && !excluded(m, excludes) {
+				units = append(units, m)
+			}
+		}
+	}
+
+	slices.Sort(units)
+	return slices.Compact(units), nil
+}
+
+func inSkippedDir(file string) bool {
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if slices.Contains(skippedDirs, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func excluded(file string, excludes []string) bool {
+	for _, p := range excludes {
+		if doublestar.MatchUnvalidated(p, file) {
+			return true
+		}
+	}
+	return false
+}
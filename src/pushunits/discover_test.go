@@ -0,0 +1,57 @@
+package pushunits
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFindUnits(t *testing.T) {
+	fsys := fstest.MapFS{
+		"lokalise-push.yml":                     {},
+		"apps/web/lokalise-push.yml":            {},
+		"apps/mobile/lokalise-push.yml":         {},
+		"apps/mobile/lokalise-push.yml.bak":     {},
+		"packages/legacy/lokalise-push.yml":     {},
+		"node_modules/lib/lokalise-push.yml":    {},
+		"apps/web/vendor/x/lokalise-push.yml":   {},
+		".github/lokalise-push.yml":             {},
+		"apps/web/locales/en.json":              {},
+		"apps/ios/lokalise-push.yml/readme.txt": {},
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{
+			"default pattern",
+			[]string{defaultPattern},
+			[]string{"apps/mobile/lokalise-push.yml", "apps/web/lokalise-push.yml", "lokalise-push.yml", "packages/legacy/lokalise-push.yml"},
+		},
+		{
+			"exclusions",
+			[]string{defaultPattern, "!packages/**", "!lokalise-push.yml"},
+			[]string{"apps/mobile/lokalise-push.yml", "apps/web/lokalise-push.yml"},
+		},
+		{
+			"overlapping patterns",
+			[]string{"apps/*/lokalise-push.yml", "apps/web/*.yml"},
+			[]string{"apps/mobile/lokalise-push.yml", "apps/web/lokalise-push.yml"},
+		},
+		{"no match", []string{"services/**/lokalise-push.yml"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findUnits(fsys, tt.patterns)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("mismatch. want=%v got=%v", tt.want, got)
+			}
+		})
+	}
+}
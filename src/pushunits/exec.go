@@ -0,0 +1,56 @@
+package pushunits
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"lokalise-push-action/internal/ghoutput"
+)
+
+// execStep returns a stepFunc running the binary itself, the way the action
+// runs each of its steps. Every command writes its outputs to a fresh file in
+// workDir, which is read back once it exits.
+func execStep(workDir string) stepFunc {
+	return func(args []string, env map[string]string) (map[string]string, error) {
+		self, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("cannot locate the lokalise_action binary: %w", err)
+		}
+
+		output, err := os.CreateTemp(workDir, "output-*")
+		if err != nil {
+			return nil, fmt.Errorf("cannot create the output file of %s: %w", args[0], err)
+		}
+		output.Close()
+
+		cmd := exec.Command(self, args...)
+		cmd.Env = withEnv(os.Environ(), merged(env, map[string]string{"GITHUB_OUTPUT": output.Name()}))
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("%s command failed: %w", args[0], err)
+		}
+
+		data, err := os.ReadFile(output.Name())
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the outputs of %s: %w", args[0], err)
+		}
+		return ghoutput.Parse(string(data))
+	}
+}
+
+// withEnv returns environ with the variables in env replacing or added to it.
+func withEnv(environ []string, env map[string]string) []string {
+	out := slices.DeleteFunc(slices.Clone(environ), func(kv string) bool {
+		name, _, _ := strings.Cut(kv, "=")
+		_, replaced := env[name]
+		return replaced
+	})
+	for name, value := range env {
+		out = append(out, name+"="+value)
+	}
+	return out
+}
@@ -0,0 +1,15 @@
+package pushunits
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestWithEnv(t *testing.T) {
+	got := withEnv([]string{"A=1", "B=2", "C=x=y"}, map[string]string{"B": "3", "D": ""})
+	slices.Sort(got)
+	if want := []string{"A=1", "B=3", "C=x=y", "D="}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatch. want=%v got=%v", want, got)
+	}
+}
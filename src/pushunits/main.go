@@ -0,0 +1,148 @@
+package pushunits
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/ghoutput"
+	"lokalise-push-action/internal/logging"
+	"lokalise-push-action/internal/repoconfig"
+)
+
+// exitFunc is a function variable that defaults to os.Exit.
+// Overridable in tests to assert exit behavior without terminating the process.
+var exitFunc = os.Exit
+
+// logs is the logger of the command; Main configures it from the environment.
+var logs = logging.New(os.Stdout, os.Stderr)
+
+type pushFunc func(cfg config, unit, workDir string) unitResult
+
+// Main pushes every unit of a monorepo, configured through environment variables.
+// It exits the process on failure.
+func Main() {
+	if err := logs.Configure(); err != nil {
+		returnWithError(err.Error())
+	}
+	if err := run(); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// Check reads and validates the configuration of Main without running it,
+// including every unit's configuration file.
+func Check() error {
+	errs := []error{logs.Configure()}
+	cfg, err := prepareConfig()
+	if err != nil {
+		return envconf.Join(append(errs, err))
+	}
+
+	units, err := findUnits(os.DirFS("."), cfg.Patterns)
+	if err == nil && len(units) == 0 {
+		err = fmt.Errorf("no unit configuration file matches UNITS_PATTERN")
+	}
+	errs = append(errs, err)
+	for _, unit := range units {
+		_, err := repoconfig.Load(unit)
+		errs = append(errs, err)
+	}
+	return envconf.Join(errs)
+}
+
+func run() error {
+	return runWith(
+		os.Args,
+		prepareConfig,
+		os.DirFS("."),
+		func(cfg config, unit, workDir string) unitResult {
+			return pushUnit(cfg, unit, workDir, execStep(workDir))
+		},
+		ghoutput.Writer(logs),
+	)
+}
+
+func runWith(
+	args []string,
+	prepare func() (config, error),
+	fsys fs.FS,
+	push pushFunc,
+	write func(string, string) bool,
+) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: lokalise_action units (configured through environment variables)")
+	}
+
+	cfg, err := prepare()
+	if err != nil {
+		return err
+	}
+
+	units, err := findUnits(fsys, cfg.Patterns)
+	if err != nil {
+		return err
+	}
+	if len(units) == 0 {
+		return fmt.Errorf("no unit configuration file matches UNITS_PATTERN")
+	}
+	logs.Infof("Found %d units", len(units))
+
+	workDir, err := os.MkdirTemp(cfg.TempDir, "lokalise-units-*")
+	if err != nil {
+		return fmt.Errorf("cannot create working directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	// Units run one after the other: they often share the API token, and
+	// Lokalise rate limits are per token.
+	results := make([]unitResult, 0, len(units))
+	failed, uploaded := 0, false
+	for i, unit := range units {
+		logs.Infof("Pushing unit %s", unit)
+		dir := filepath.Join(workDir, strconv.Itoa(i))
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			return fmt.Errorf("cannot create working directory: %w", err)
+		}
+
+		res := push(cfg, unit, dir)
+		if res.Status == statusFailed {
+			failed++
+		}
+		if res.Uploaded > 0 {
+			uploaded = true
+		}
+		results = append(results, res)
+	}
+
+	fmt.Fprint(os.Stdout, renderReport(results))
+	if err := ghoutput.AppendStepSummary(renderSummary(results)); err != nil {
+		logs.Warnf("%v", err)
+	}
+
+	report, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("cannot encode units_report: %w", err)
+	}
+	if !write("units_report", string(report)) {
+		return fmt.Errorf("cannot write units_report to GITHUB_OUTPUT")
+	}
+	if !write("files_uploaded", strconv.FormatBool(uploaded)) {
+		return fmt.Errorf("cannot write files_uploaded to GITHUB_OUTPUT")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d units failed", failed, len(units))
+	}
+	return nil
+}
+
+// returnWithError prints an error message to stderr and exits the program with a non-zero status code.
+func returnWithError(message string) {
+	logs.Errorf("%s", message)
+	exitFunc(1)
+}
@@ -0,0 +1,157 @@
+package pushunits
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMain(m *testing.M) {
+	// Hijack os.Exit so tests can assert hard exits.
+	exitFunc = func(code int) { panic(fmt.Sprintf("Exit called with code %d", code)) }
+
+	code := m.Run()
+
+	// Restore.
+	exitFunc = os.Exit
+	os.Exit(code)
+}
+
+func TestRunWith(t *testing.T) {
+	fsys := fstest.MapFS{
+		"apps/web/lokalise-push.yml":    {},
+		"apps/mobile/lokalise-push.yml": {},
+	}
+	cfg := config{Patterns: []string{defaultPattern}, TempDir: t.TempDir()}
+	prepare := func() (config, error) { return cfg, nil }
+
+	run := func(t *testing.T, push pushFunc) (map[string]string, error) {
+		t.Helper()
+		outputs := make(map[string]string)
+		write := func(name, value string) bool {
+			outputs[name] = value
+			return true
+		}
+		err := runWith([]string{"units"}, prepare, fsys, push, write)
+		return outputs, err
+	}
+
+	t.Run("pushes every unit", func(t *testing.T) {
+		var pushed []string
+		workDirs := make(map[string]bool)
+		outputs, err := run(t, func(_ config, unit, workDir string) unitResult {
+			pushed = append(pushed, unit)
+			if _, err := os.Stat(workDir); err != nil || workDirs[workDir] {
+				t.Fatalf("expected a fresh working directory per unit, got %q (%v)", workDir, err)
+			}
+			workDirs[workDir] = true
+			if unit == "apps/mobile/lokalise-push.yml" {
+				return unitResult{Unit: unit, Status: statusUnchanged}
+			}
+			return unitResult{Unit: unit, Files: 1, Uploaded: 1, Status: statusPushed}
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := []string{"apps/mobile/lokalise-push.yml", "apps/web/lokalise-push.yml"}; !reflect.DeepEqual(pushed, want) {
+			t.Fatalf("unexpected units %v", pushed)
+		}
+		var report []unitResult
+		if err := json.Unmarshal([]byte(outputs["units_report"]), &report); err != nil || len(report) != 2 || report[1].Status != statusPushed {
+			t.Fatalf("unexpected units_report %q (%v)", outputs["units_report"], err)
+		}
+		if outputs["files_uploaded"] != "true" {
+			t.Fatalf("expected files_uploaded=true, got %q", outputs["files_uploaded"])
+		}
+		for dir := range workDirs {
+			if _, err := os.Stat(dir); !os.IsNotExist(err) {
+				t.Fatalf("expected working directory %s to be removed", dir)
+			}
+		}
+	})
+
+	t.Run("a failed unit doesn't stop the others", func(t *testing.T) {
+		var pushed []string
+		outputs, err := run(t, func(_ config, unit, _ string) unitResult {
+			pushed = append(pushed, unit)
+			if unit == "apps/mobile/lokalise-push.yml" {
+				return unitResult{Unit: unit, Status: statusFailed, Error: "paths command failed"}
+			}
+			return unitResult{Unit: unit, Status: statusUnchanged}
+		})
+		if err == nil || err.Error() != "1 of 2 units failed" {
+			t.Fatalf("expected unit failure, got %v", err)
+		}
+		if len(pushed) != 2 {
+			t.Fatalf("expected both units to run, got %v", pushed)
+		}
+		if !strings.Contains(outputs["units_report"], "paths command failed") || outputs["files_uploaded"] != "false" {
+			t.Fatalf("unexpected outputs %v", outputs)
+		}
+	})
+
+	t.Run("no units", func(t *testing.T) {
+		empty := func() (config, error) { return config{Patterns: []string{"services/**/lokalise-push.yml"}}, nil }
+		err := runWith([]string{"units"}, empty, fsys, nil, nil)
+		if err == nil || !strings.Contains(err.Error(), "no unit configuration file matches UNITS_PATTERN") {
+			t.Fatalf("expected no units error, got %v", err)
+		}
+	})
+
+	t.Run("unexpected arguments", func(t *testing.T) {
+		err := runWith([]string{"units", "extra"}, prepare, fsys, nil, nil)
+		if err == nil || !strings.Contains(err.Error(), "usage") {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+
+	t.Run("prepare error stops the run", func(t *testing.T) {
+		failing := func() (config, error) { return config{}, errors.New("bad env") }
+		if err := runWith([]string{"units"}, failing, fsys, nil, nil); err == nil || err.Error() != "bad env" {
+			t.Fatalf("expected prepare error, got %v", err)
+		}
+	})
+
+	t.Run("output failure", func(t *testing.T) {
+		push := func(_ config, unit, _ string) unitResult { return unitResult{Unit: unit, Status: statusUnchanged} }
+		err := runWith([]string{"units"}, prepare, fsys, push, func(string, string) bool { return false })
+		if err == nil || !strings.Contains(err.Error(), "cannot write units_report") {
+			t.Fatalf("expected output error, got %v", err)
+		}
+	})
+}
+
+func TestCheck(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+	t.Setenv("UNITS_PUSH_ALL", "")
+	t.Setenv("UNITS_PATTERN", "")
+	t.Chdir(t.TempDir())
+
+	if err := Check(); err == nil || !strings.Contains(err.Error(), "no unit configuration file matches") {
+		t.Fatalf("expected no units error, got %v", err)
+	}
+
+	if err := os.MkdirAll("apps/web", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("apps/web/lokalise-push.yml", []byte("project_id: 123.abc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := os.WriteFile("lokalise-push.yml", []byte("retries: 3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(); err == nil || !strings.Contains(err.Error(), `invalid config file lokalise-push.yml: unknown key "retries"`) {
+		t.Fatalf("expected invalid unit error, got %v", err)
+	}
+}
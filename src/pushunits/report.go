@@ -0,0 +1,38 @@
+package pushunits
+
+import (
+	"fmt"
+	"strings"
+)
+
+// renderReport renders one line per unit for the job log.
+func renderReport(results []unitResult) string {
+	var b strings.Builder
+	for _, r := range results {
+		fmt.Fprintf(&b, "%s: %s", r.Unit, r.Status)
+		if r.Files > 0 {
+			fmt.Fprintf(&b, ", %d of %d files uploaded", r.Uploaded, r.Files)
+		}
+		if r.Error != "" {
+			fmt.Fprintf(&b, " (%s)", r.Error)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderSummary renders the results as a Markdown table for the job summary.
+func renderSummary(results []unitResult) string {
+	var b strings.Builder
+	b.WriteString("### Lokalise push units\n\n")
+	b.WriteString("| Unit | Project | Status | Uploaded | Failed |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, r := range results {
+		project := r.ProjectID
+		if project == "" {
+			project = "—"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %d | %d |\n", r.Unit, project, r.Status, r.Uploaded, r.Failed)
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package pushunits
+
+import (
+	"strings"
+	"testing"
+)
+
+var testResults = []unitResult{
+	{Unit: "apps/web/lokalise-push.yml", ProjectID: "123.abc", Files: 3, Uploaded: 3, Status: statusPushed},
+	{Unit: "apps/mobile/lokalise-push.yml", Status: statusUnchanged},
+	{Unit: "lokalise-push.yml", ProjectID: "456.def", Files: 2, Uploaded: 1, Failed: 1, Status: statusFailed, Error: "1 of 2 uploads failed: fr.json"},
+}
+
+func TestRenderReport(t *testing.T) {
+	want := "apps/web/lokalise-push.yml: pushed, 3 of 3 files uploaded\n" +
+		"apps/mobile/lokalise-push.yml: unchanged\n" +
+		"lokalise-push.yml: failed, 1 of 2 files uploaded (1 of 2 uploads failed: fr.json)\n"
+	if got := renderReport(testResults); got != want {
+		t.Fatalf("unexpected report.\nwant=%q\ngot=%q", want, got)
+	}
+}
+
+func TestRenderSummary(t *testing.T) {
+	got := renderSummary(testResults)
+	for _, want := range []string{
+		"### Lokalise push units",
+		"| `apps/web/lokalise-push.yml` | 123.abc | pushed | 3 | 0 |",
+		"| `apps/mobile/lokalise-push.yml` | — | unchanged | 0 | 0 |",
+		"| `lokalise-push.yml` | 456.def | failed | 1 | 1 |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected summary to contain %q, got %q", want, got)
+		}
+	}
+}
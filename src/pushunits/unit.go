@@ -0,0 +1,190 @@
+package pushunits
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"lokalise-push-action/internal/repoconfig"
+)
+
+// stepFunc runs a command of the binary with env added to its environment and
+// returns the outputs the command wrote.
+type stepFunc func(args []string, env map[string]string) (map[string]string, error)
+
+// Unit statuses reported in units_report.
+const (
+	statusPushed    = "pushed"
+	statusUnchanged = "unchanged"
+	statusFailed    = "failed"
+)
+
+// uploadWorkers is how many files of a unit are uploaded at once, as in the
+// single-unit action.
+const uploadWorkers = 6
+
+// unitResult is the outcome of pushing one unit.
+type unitResult struct {
+	Unit      string `json:"unit"`
+	ProjectID string `json:"project_id,omitempty"`
+	Files     int    `json:"files"`
+	Uploaded  int    `json:"uploaded"`
+	Failed    int    `json:"failed"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// pushUnit runs the action's steps for the unit configured by the file unit:
+// paths, then changes (or discover for a full push), then one upload per file.
+// workDir receives the files passed between the steps. Failures are recorded
+// in the result rather than returned, so one unit can't stop the others.
+func pushUnit(cfg config, unit, workDir string, step stepFunc) unitResult {
+	res := unitResult{Unit: unit, ProjectID: projectID(unit)}
+
+	files, env, err := collectFiles(cfg, unit, workDir, step)
+	if err != nil {
+		res.Status = statusFailed
+		res.Error = err.Error()
+		return res
+	}
+
+	res.Files = len(files)
+	if len(files) == 0 {
+		res.Status = statusUnchanged
+		return res
+	}
+
+	failed := uploadFiles(files, env, step)
+	res.Uploaded = len(files) - len(failed)
+	res.Failed = len(failed)
+	res.Status = statusPushed
+	if len(failed) > 0 {
+		res.Status = statusFailed
+		res.Error = fmt.Sprintf("%d of %d uploads failed: %s", len(failed), len(files), strings.Join(failed, ", "))
+	}
+	return res
+}
+
+// collectFiles returns the files of the unit to upload and the environment
+// shared by their uploads.
+func collectFiles(cfg config, unit, workDir string, step stepFunc) ([]string, map[string]string, error) {
+	paths, err := step([]string{"paths"}, map[string]string{
+		"CONFIG_FILE":       unit,
+		"PATHS_OUTPUT_FILE": filepath.Join(workDir, "paths.txt"),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Later steps use the resolved roots, as they do in the action.
+	env := map[string]string{"CONFIG_FILE": unit}
+	for name, key := range map[string]string{"TRANSLATIONS_PATH": "translations_path", "FLAT_NAMING": "flat_naming"} {
+		if value := paths[key]; value != "" {
+			env[name] = value
+		}
+	}
+
+	if !cfg.PushAll {
+		changes, err := step([]string{"changes"}, map[string]string{
+			"CONFIG_FILE":       unit,
+			"PATHS_FILE":        paths["paths_file"],
+			"PATHS_IGNORE_FILE": paths["ignore_file"],
+			"RUNNER_TEMP":       workDir,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		// A changed watched file pushes every file of the unit.
+		if changes["watched_changed"] != "true" {
+			data, err := os.ReadFile(changes["changed_files_path"])
+			if err != nil {
+				return nil, nil, fmt.Errorf("cannot read the files listed by changes: %w", err)
+			}
+			return splitList(string(data), "\x00"), env, nil
+		}
+	}
+
+	listPath := filepath.Join(workDir, "files.txt")
+	discover, err := step([]string{"discover"}, merged(env, map[string]string{
+		"FILES_LIST_PATH":    listPath,
+		"ALL_FILES_ENCODING": "nul",
+	}))
+	if err != nil {
+		return nil, nil, err
+	}
+	// discover writes the languages it infers next to the list.
+	if langs := discover["FILE_LANG_MAP_PATH"]; langs != "" {
+		env["FILE_LANG_MAP_PATH"] = langs
+	}
+
+	data, err := os.ReadFile(listPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read the files collected by discover: %w", err)
+	}
+	return splitList(string(data), "\x00"), env, nil
+}
+
+// uploadFiles uploads every file with uploadWorkers concurrent uploads and
+// returns the files that failed, in input order.
+func uploadFiles(files []string, env map[string]string, step stepFunc) []string {
+	errs := make([]error, len(files))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(uploadWorkers, len(files)) {
+		wg.Go(func() {
+			for i := range jobs {
+				_, errs[i] = step([]string{"upload", files[i]}, env)
+			}
+		})
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, files[i])
+		}
+	}
+	return failed
+}
+
+// projectID returns the project the unit pushes to, for the report: the
+// LOKALISE_PROJECT_ID variable, which overrides the file, or the file's
+// project_id. It's empty when neither is set or the file is invalid, which
+// the paths step reports.
+func projectID(unit string) string {
+	if id := strings.TrimSpace(os.Getenv("LOKALISE_PROJECT_ID")); id != "" {
+		return id
+	}
+	values, err := repoconfig.Load(unit)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(values["LOKALISE_PROJECT_ID"])
+}
+
+// merged returns a copy of base with extra added.
+func merged(base, extra map[string]string) map[string]string {
+	out := maps.Clone(base)
+	maps.Copy(out, extra)
+	return out
+}
+
+// splitList splits raw on sep, dropping empty entries.
+func splitList(raw, sep string) []string {
+	var out []string
+	for item := range strings.SplitSeq(raw, sep) {
+		if item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
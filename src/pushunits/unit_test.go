@@ -0,0 +1,232 @@
+package pushunits
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSteps records the commands run for a unit and answers them from outputs.
+type fakeSteps struct {
+	mu      sync.Mutex
+	calls   []string
+	envs    map[string]map[string]string
+	outputs map[string]map[string]string
+	fail    map[string]error
+	// files is written to FILES_LIST_PATH by discover, NUL-terminated.
+	files []string
+	// changed is listed by changes in a NUL-terminated file under RUNNER_TEMP.
+	changed []string
+}
+
+func (f *fakeSteps) step(args []string, env map[string]string) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	call := strings.Join(args, " ")
+	f.calls = append(f.calls, call)
+	if f.envs == nil {
+		f.envs = make(map[string]map[string]string)
+	}
+	f.envs[call] = env
+
+	if err := f.fail[call]; err != nil {
+		return nil, err
+	}
+	switch args[0] {
+	case "discover":
+		if err := writeNULList(env["FILES_LIST_PATH"], f.files); err != nil {
+			return nil, err
+		}
+	case "changes":
+		path := filepath.Join(env["RUNNER_TEMP"], "changed.txt")
+		if err := writeNULList(path, f.changed); err != nil {
+			return nil, err
+		}
+		return merged(f.outputs["changes"], map[string]string{"changed_files_path": path}), nil
+	}
+	return f.outputs[args[0]], nil
+}
+
+func writeNULList(path string, files []string) error {
+	var b strings.Builder
+	for _, file := range files {
+		b.WriteString(file + "\x00")
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+func (f *fakeSteps) sortedCalls() []string {
+	calls := slices.Clone(f.calls)
+	slices.Sort(calls)
+	return calls
+}
+
+func pathsOutputs() map[string]string {
+	return map[string]string{
+		"paths_file":        "/tmp/paths.txt",
+		"translations_path": "apps/web/locales",
+		"flat_naming":       "true",
+	}
+}
+
+func TestPushUnit_ChangedFiles(t *testing.T) {
+	t.Setenv("LOKALISE_PROJECT_ID", "")
+	unit := filepath.Join(t.TempDir(), "lokalise-push.yml")
+	if err := os.WriteFile(unit, []byte("project_id: 123.abc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := &fakeSteps{
+		outputs: map[string]map[string]string{
+			"paths":   pathsOutputs(),
+			"changes": {"any_changed": "true"},
+		},
+		changed: []string{"apps/web/locales/en.json", "apps/web/locales/fr,CA.json"},
+	}
+
+	workDir := t.TempDir()
+	res := pushUnit(config{}, unit, workDir, steps.step)
+
+	want := unitResult{Unit: unit, ProjectID: "123.abc", Files: 2, Uploaded: 2, Status: statusPushed}
+	if !reflect.DeepEqual(res, want) {
+		t.Fatalf("mismatch.\nwant=%+v\ngot=%+v", want, res)
+	}
+
+	wantCalls := []string{"changes", "paths", "upload apps/web/locales/en.json", "upload apps/web/locales/fr,CA.json"}
+	if got := steps.sortedCalls(); !reflect.DeepEqual(got, wantCalls) {
+		t.Fatalf("unexpected calls %v", got)
+	}
+	if env := steps.envs["paths"]; env["CONFIG_FILE"] != unit || env["PATHS_OUTPUT_FILE"] != filepath.Join(workDir, "paths.txt") {
+		t.Fatalf("unexpected paths env %v", env)
+	}
+	if env := steps.envs["changes"]; env["PATHS_FILE"] != "/tmp/paths.txt" || env["CONFIG_FILE"] != unit || env["RUNNER_TEMP"] != workDir {
+		t.Fatalf("unexpected changes env %v", env)
+	}
+	wantUploadEnv := map[string]string{"CONFIG_FILE": unit, "TRANSLATIONS_PATH": "apps/web/locales", "FLAT_NAMING": "true"}
+	if env := steps.envs["upload apps/web/locales/en.json"]; !reflect.DeepEqual(env, wantUploadEnv) {
+		t.Fatalf("unexpected upload env %v", env)
+	}
+}
+
+func TestPushUnit_Unchanged(t *testing.T) {
+	t.Setenv("LOKALISE_PROJECT_ID", "789.ghi")
+	steps := &fakeSteps{outputs: map[string]map[string]string{
+		"paths":   pathsOutputs(),
+		"changes": {"any_changed": "false"},
+	}}
+
+	res := pushUnit(config{}, "missing.yml", t.TempDir(), steps.step)
+	want := unitResult{Unit: "missing.yml", ProjectID: "789.ghi", Status: statusUnchanged}
+	if !reflect.DeepEqual(res, want) {
+		t.Fatalf("mismatch.\nwant=%+v\ngot=%+v", want, res)
+	}
+}
+
+func TestPushUnit_AllFiles(t *testing.T) {
+	t.Setenv("LOKALISE_PROJECT_ID", "")
+
+	for _, tt := range []struct {
+		name  string
+		cfg   config
+		calls []string
+	}{
+		{"push all", config{PushAll: true}, []string{"discover", "paths", "upload a/en.json", "upload b/en.json"}},
+		{"watched file changed", config{}, []string{"changes", "discover", "paths", "upload a/en.json", "upload b/en.json"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := &fakeSteps{
+				outputs: map[string]map[string]string{
+					"paths":    pathsOutputs(),
+					"changes":  {"any_changed": "false", "watched_changed": "true"},
+					"discover": {"has_files": "true", "FILE_LANG_MAP_PATH": "/tmp/unit/files.txt.langs.json"},
+				},
+				files: []string{"a/en.json", "b/en.json"},
+			}
+
+			workDir := t.TempDir()
+			res := pushUnit(tt.cfg, "unit.yml", workDir, steps.step)
+			if res.Status != statusPushed || res.Files != 2 || res.Uploaded != 2 {
+				t.Fatalf("unexpected result %+v", res)
+			}
+			if got := steps.sortedCalls(); !reflect.DeepEqual(got, tt.calls) {
+				t.Fatalf("unexpected calls %v", got)
+			}
+			if env := steps.envs["discover"]; env["FILES_LIST_PATH"] != filepath.Join(workDir, "files.txt") || env["ALL_FILES_ENCODING"] != "nul" || env["TRANSLATIONS_PATH"] != "apps/web/locales" {
+				t.Fatalf("unexpected discover env %v", env)
+			}
+			if env := steps.envs["upload a/en.json"]; env["FILE_LANG_MAP_PATH"] != "/tmp/unit/files.txt.langs.json" || env["FILES_LIST_PATH"] != "" {
+				t.Fatalf("unexpected upload env %v", env)
+			}
+		})
+	}
+}
+
+func TestPushUnit_Failures(t *testing.T) {
+	t.Setenv("LOKALISE_PROJECT_ID", "")
+
+	t.Run("step failure", func(t *testing.T) {
+		steps := &fakeSteps{
+			outputs: map[string]map[string]string{"paths": pathsOutputs()},
+			fail:    map[string]error{"changes": errors.New("changes command failed: exit status 1")},
+		}
+		res := pushUnit(config{}, "unit.yml", t.TempDir(), steps.step)
+		if res.Status != statusFailed || res.Error != "changes command failed: exit status 1" {
+			t.Fatalf("unexpected result %+v", res)
+		}
+		if slices.Contains(steps.calls, "discover") {
+			t.Fatalf("expected no further steps, got %v", steps.calls)
+		}
+	})
+
+	t.Run("upload failure", func(t *testing.T) {
+		steps := &fakeSteps{
+			outputs: map[string]map[string]string{
+				"paths":   pathsOutputs(),
+				"changes": {"any_changed": "true"},
+			},
+			changed: []string{"en.json", "fr.json", "de.json"},
+			fail:    map[string]error{"upload fr.json": errors.New("upload command failed: exit status 1")},
+		}
+		res := pushUnit(config{}, "unit.yml", t.TempDir(), steps.step)
+		want := unitResult{Unit: "unit.yml", Files: 3, Uploaded: 2, Failed: 1, Status: statusFailed, Error: "1 of 3 uploads failed: fr.json"}
+		if !reflect.DeepEqual(res, want) {
+			t.Fatalf("mismatch.\nwant=%+v\ngot=%+v", want, res)
+		}
+	})
+}
+
+func TestUploadFiles(t *testing.T) {
+	var files []string
+	fail := map[string]error{}
+	for i := range 20 {
+		name := filepath.Join("locales", strings.Repeat("x", i+1)+".json")
+		files = append(files, name)
+		if i%7 == 0 {
+			fail["upload "+name] = errors.New("boom")
+		}
+	}
+	steps := &fakeSteps{fail: fail}
+
+	failed := uploadFiles(files, nil, steps.step)
+	if len(steps.calls) != len(files) {
+		t.Fatalf("expected %d uploads, got %d", len(files), len(steps.calls))
+	}
+	if want := []string{files[0], files[7], files[14]}; !reflect.DeepEqual(failed, want) {
+		t.Fatalf("expected failures %v in input order, got %v", want, failed)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	if got := splitList("a,,b,", ","); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("unexpected split %v", got)
+	}
+	if got := splitList("", "\x00"); got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+}
@@ -0,0 +1,50 @@
+package recordpush
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/pushstate"
+)
+
+var shaRe = regexp.MustCompile(`^[0-9a-fA-F]{1,64}$`)
+
+// config describes where the push state lives and what was pushed.
+type config struct {
+	CacheDir   string   // LOKALISE_CACHE_DIR
+	SHA        string   // GITHUB_SHA, the pushed commit
+	ProjectIDs []string // LOKALISE_PROJECT_ID: projects pushed even when every file was unchanged
+}
+
+// prepareConfig reads the configuration from the environment, reporting
+// every invalid variable at once.
+func prepareConfig() (config, error) {
+	var errs []error
+
+	cacheDir := strings.TrimSpace(os.Getenv("LOKALISE_CACHE_DIR"))
+	if cacheDir == "" {
+		errs = append(errs, fmt.Errorf("push state cache (LOKALISE_CACHE_DIR) is required and cannot be empty"))
+	}
+
+	sha := strings.TrimSpace(os.Getenv("GITHUB_SHA"))
+	if !shaRe.MatchString(sha) {
+		errs = append(errs, fmt.Errorf("invalid GITHUB_SHA %q: expected a commit SHA", sha))
+	}
+
+	rawProjectIDs, err := envconf.EnvOrFile("LOKALISE_PROJECT_ID")
+	errs = append(errs, err)
+	projectIDs := envconf.ProjectIDs(rawProjectIDs)
+	for _, id := range projectIDs {
+		_, err := pushstate.Dir(cacheDir, id)
+		errs = append(errs, err)
+	}
+
+	if err := envconf.Join(errs); err != nil {
+		return config{}, err
+	}
+
+	return config{CacheDir: cacheDir, SHA: sha, ProjectIDs: projectIDs}, nil
+}
@@ -0,0 +1,77 @@
+package recordpush
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPrepareConfig(t *testing.T) {
+	t.Setenv("LOKALISE_CACHE_DIR", " /tmp/lokalise-cache ")
+	t.Setenv("GITHUB_SHA", "0123456789abcdef0123456789abcdef01234567")
+	t.Setenv("LOKALISE_PROJECT_ID", "111.abc, 222.def\n")
+	t.Setenv("LOKALISE_PROJECT_ID_FILE", "")
+
+	cfg, err := prepareConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := config{
+		CacheDir:   "/tmp/lokalise-cache",
+		SHA:        "0123456789abcdef0123456789abcdef01234567",
+		ProjectIDs: []string{"111.abc", "222.def"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("mismatch.\nwant=%+v\ngot=%+v", want, cfg)
+	}
+}
+
+func TestPrepareConfig_ProjectIDFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "project")
+	if err := os.WriteFile(file, []byte("333.ghi\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LOKALISE_CACHE_DIR", "/tmp/lokalise-cache")
+	t.Setenv("GITHUB_SHA", "abc123")
+	t.Setenv("LOKALISE_PROJECT_ID", "")
+	t.Setenv("LOKALISE_PROJECT_ID_FILE", file)
+
+	cfg, err := prepareConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.ProjectIDs, []string{"333.ghi"}) {
+		t.Fatalf("unexpected project IDs %v", cfg.ProjectIDs)
+	}
+}
+
+func TestPrepareConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		cacheDir string
+		sha      string
+		project  string
+		wantErr  string
+	}{
+		{"missing cache directory", "", "abc123", "", "LOKALISE_CACHE_DIR) is required"},
+		{"missing sha", "/cache", "", "", `invalid GITHUB_SHA ""`},
+		{"invalid sha", "/cache", "HEAD~1", "", `invalid GITHUB_SHA "HEAD~1"`},
+		{"invalid project", "/cache", "abc123", "../escape", `invalid project ID "../escape"`},
+		{"every problem", "", "nope", "", "2 configuration problems"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LOKALISE_CACHE_DIR", tt.cacheDir)
+			t.Setenv("GITHUB_SHA", tt.sha)
+			t.Setenv("LOKALISE_PROJECT_ID", tt.project)
+			t.Setenv("LOKALISE_PROJECT_ID_FILE", "")
+
+			if _, err := prepareConfig(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package recordpush
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"lokalise-push-action/internal/logging"
+	"lokalise-push-action/internal/pushstate"
+)
+
+// exitFunc is a function variable that defaults to os.Exit.
+// Overridable in tests to assert exit behavior without terminating the process.
+var exitFunc = os.Exit
+
+// logs is the logger of the command; Main configures it from the environment.
+var logs = logging.New(os.Stdout, os.Stderr)
+
+// Main records a successful push in the push state cache, configured through
+// environment variables. It exits the process on failure.
+func Main() {
+	if err := logs.Configure(); err != nil {
+		returnWithError(err.Error())
+	}
+	if err := run(); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// Check reads and validates the configuration of Main without running it.
+func Check() error {
+	if err := logs.Configure(); err != nil {
+		return err
+	}
+	_, err := prepareConfig()
+	return err
+}
+
+func run() error {
+	return runWith(os.Args, prepareConfig, time.Now)
+}
+
+// runWith saves the state of every project pushed by the run: those with
+// upload records waiting to be merged, and those of LOKALISE_PROJECT_ID,
+// whose files may all have been unchanged. Their pending records are folded
+// into the state and the pushed commit becomes their last pushed commit.
+// Other projects in the cache, pushed by earlier runs, are left alone.
+func runWith(args []string, prepare func() (config, error), now func() time.Time) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: lokalise_action record (configured through environment variables)")
+	}
+
+	cfg, err := prepare()
+	if err != nil {
+		return err
+	}
+
+	cached, err := pushstate.Projects(cfg.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	projects := slices.Clone(cfg.ProjectIDs)
+	for _, id := range cached {
+		if !slices.Contains(projects, id) {
+			projects = append(projects, id)
+		}
+	}
+
+	for _, id := range projects {
+		state, err := pushstate.Load(cfg.CacheDir, id)
+		if err != nil {
+			return fmt.Errorf("project %s: %w", id, err)
+		}
+		uploads := state.Pending()
+		if uploads == 0 && !slices.Contains(cfg.ProjectIDs, id) {
+			continue
+		}
+
+		state.LastPushedSHA = cfg.SHA
+		state.UpdatedAt = now().Unix()
+		if err := state.Save(cfg.CacheDir); err != nil {
+			return fmt.Errorf("project %s: %w", id, err)
+		}
+		logs.Infof("Project %s: recorded push of %s (%d uploaded files)", id, cfg.SHA, uploads)
+	}
+	return nil
+}
+
+// returnWithError prints an error message to stderr and exits the program with a non-zero status code.
+func returnWithError(message string) {
+	logs.Errorf("%s", message)
+	exitFunc(1)
+}
@@ -0,0 +1,127 @@
+package recordpush
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"lokalise-push-action/internal/pushstate"
+)
+
+func TestMain(m *testing.M) {
+	// Hijack os.Exit so tests can assert hard exits.
+	exitFunc = func(code int) { panic(fmt.Sprintf("Exit called with code %d", code)) }
+
+	code := m.Run()
+
+	// Restore.
+	exitFunc = os.Exit
+	os.Exit(code)
+}
+
+func TestRunWith(t *testing.T) {
+	now := func() time.Time { return time.Unix(1700000000, 0) }
+
+	t.Run("records the projects pushed by the run", func(t *testing.T) {
+		cache := t.TempDir()
+
+		// An earlier run pushed "old"; this run uploaded to "pushed" and
+		// found every file of "listed" unchanged.
+		old, err := pushstate.Load(cache, "old")
+		if err != nil {
+			t.Fatal(err)
+		}
+		old.LastPushedSHA = "aaa"
+		if err := old.Save(cache); err != nil {
+			t.Fatal(err)
+		}
+		if err := pushstate.RecordUpload(cache, "pushed", pushstate.Upload{File: "en.json", Fingerprint: "f1", PushedAt: 1}); err != nil {
+			t.Fatal(err)
+		}
+
+		prepare := func() (config, error) {
+			return config{CacheDir: cache, SHA: "bbb", ProjectIDs: []string{"listed"}}, nil
+		}
+		if err := runWith([]string{"record"}, prepare, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for id, wantSHA := range map[string]string{"old": "aaa", "pushed": "bbb", "listed": "bbb"} {
+			state, err := pushstate.Load(cache, id)
+			if err != nil {
+				t.Fatalf("project %s: unexpected error: %v", id, err)
+			}
+			if state.LastPushedSHA != wantSHA {
+				t.Fatalf("project %s: expected last pushed SHA %q, got %q", id, wantSHA, state.LastPushedSHA)
+			}
+			if state.Pending() != 0 {
+				t.Fatalf("project %s: expected pending records to be saved, got %d", id, state.Pending())
+			}
+		}
+
+		pushed, _ := pushstate.Load(cache, "pushed")
+		if !pushed.Unchanged("en.json", "f1") || pushed.UpdatedAt != now().Unix() {
+			t.Fatalf("expected the upload to be merged, got %+v", pushed)
+		}
+	})
+
+	t.Run("empty cache", func(t *testing.T) {
+		cache := filepath.Join(t.TempDir(), "missing")
+		prepare := func() (config, error) { return config{CacheDir: cache, SHA: "bbb"}, nil }
+		if err := runWith([]string{"record"}, prepare, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(cache); !os.IsNotExist(err) {
+			t.Fatalf("expected nothing to be written, got %v", err)
+		}
+	})
+
+	t.Run("invalid state", func(t *testing.T) {
+		cache := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(cache, "broken"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(cache, "broken", "state.json"), []byte("{"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		prepare := func() (config, error) { return config{CacheDir: cache, SHA: "bbb"}, nil }
+		if err := runWith([]string{"record"}, prepare, now); err == nil || !strings.Contains(err.Error(), "project broken: invalid push state") {
+			t.Fatalf("expected invalid state error, got %v", err)
+		}
+	})
+
+	t.Run("unexpected arguments", func(t *testing.T) {
+		if err := runWith([]string{"record", "extra"}, nil, now); err == nil || !strings.Contains(err.Error(), "usage") {
+			t.Fatalf("expected usage error, got %v", err)
+		}
+	})
+
+	t.Run("prepare error stops the run", func(t *testing.T) {
+		failing := func() (config, error) { return config{}, errors.New("bad env") }
+		if err := runWith([]string{"record"}, failing, now); err == nil || err.Error() != "bad env" {
+			t.Fatalf("expected prepare error, got %v", err)
+		}
+	})
+}
+
+func TestCheck(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+	t.Setenv("LOKALISE_PROJECT_ID", "")
+	t.Setenv("LOKALISE_PROJECT_ID_FILE", "")
+	t.Setenv("GITHUB_SHA", "abc123")
+
+	t.Setenv("LOKALISE_CACHE_DIR", "")
+	if err := Check(); err == nil || !strings.Contains(err.Error(), "LOKALISE_CACHE_DIR") {
+		t.Fatalf("expected missing cache error, got %v", err)
+	}
+
+	t.Setenv("LOKALISE_CACHE_DIR", t.TempDir())
+	if err := Check(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
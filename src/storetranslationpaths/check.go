@@ -0,0 +1,83 @@
+package storetranslationpaths
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/logging"
+)
+
+// CHECK_PATHSPECS modes.
+const (
+	checkOff  = "off"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// errMatched stops a glob walk at the first matching file.
+var errMatched = errors.New("matched")
+
+// parseCheckPathspecs reads CHECK_PATHSPECS, defaulting to no check.
+func parseCheckPathspecs() (string, error) {
+	return envconf.ParseEnumEnv("CHECK_PATHSPECS", []string{checkOff, checkWarn, checkFail}, checkOff)
+}
+
+// checkPathspecs reports every generated pathspec that matches no file in fsys,
+// catching a mistyped translations_path, base_lang, or file_ext before the push
+// silently does nothing. Each one is logged as a warning, or as an error in
+// fail mode, where an error is returned as well. Exclusions ("!" lines) are not checked.
+func checkPathspecs(mode string, fsys fs.FS, pathspecs []string) error {
+	if mode == checkOff || mode == "" {
+		return nil
+	}
+
+	unmatched, err := unmatchedPathspecs(fsys, pathspecs)
+	if err != nil {
+		return err
+	}
+	if len(unmatched) == 0 {
+		return nil
+	}
+
+	report := logs.WarnAt
+	if mode == checkFail {
+		report = logs.ErrorAt
+	}
+	for _, p := range unmatched {
+		report(logging.Location{Title: "Pathspec matches no files"},
+			"%q matches no files; check translations_path, base_lang, and file_ext", p)
+	}
+
+	if mode == checkFail {
+		return fmt.Errorf("%d pathspec(s) match no files: %s", len(unmatched), strings.Join(unmatched, ", "))
+	}
+	return nil
+}
+
+// unmatchedPathspecs returns the include pathspecs that match no file in fsys.
+func unmatchedPathspecs(fsys fs.FS, pathspecs []string) ([]string, error) {
+	var unmatched []string
+	for _, p := range pathspecs {
+		if p == "" || strings.HasPrefix(p, "!") {
+			continue
+		}
+
+		err := doublestar.GlobWalk(fsys, filepath.ToSlash(p), func(string, fs.DirEntry) error {
+			return errMatched
+		}, doublestar.WithFilesOnly())
+		switch {
+		case errors.Is(err, errMatched):
+		case err != nil:
+			return nil, fmt.Errorf("cannot check pathspec %q: %w", p, err)
+		default:
+			unmatched = append(unmatched, p)
+		}
+	}
+	return unmatched, nil
+}
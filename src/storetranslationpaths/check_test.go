@@ -0,0 +1,111 @@
+package storetranslationpaths
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"lokalise-push-action/internal/logging"
+)
+
+func TestParseCheckPathspecs(t *testing.T) {
+	for value, want := range map[string]string{"": checkOff, "off": checkOff, " Warn ": checkWarn, "fail": checkFail} {
+		t.Setenv("CHECK_PATHSPECS", value)
+		got, err := parseCheckPathspecs()
+		if err != nil || got != want {
+			t.Fatalf("%q: expected %q, got %q (%v)", value, want, got, err)
+		}
+	}
+
+	t.Setenv("CHECK_PATHSPECS", "strict")
+	if _, err := parseCheckPathspecs(); err == nil || !strings.Contains(err.Error(), "invalid CHECK_PATHSPECS") {
+		t.Fatalf("expected error, got %v", err)
+	}
+}
+
+func TestUnmatchedPathspecs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.json":          {},
+		"app/i18n/en/common.yaml":  {},
+		"app/i18n/en/nested/x.yml": {},
+		"web/locales/en/.keep":     {},
+	}
+
+	got, err := unmatchedPathspecs(fsys, []string{
+		"locales/en.json",
+		"locales/en.yaml",
+		"app/i18n/en/**/*.{yaml,yml}",
+		"web/locales/en/**/*.json",
+		"web/locales/en",
+		"!locales/missing/**",
+		"",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"locales/en.yaml", "web/locales/en/**/*.json", "web/locales/en"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+// captureLogs redirects the package logger to a buffer for the duration of the
+// test, rendering warnings and errors as GitHub annotations.
+func captureLogs(t *testing.T) *strings.Builder {
+	t.Helper()
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+
+	var buf strings.Builder
+	orig := logs
+	t.Cleanup(func() { logs = orig })
+	logs = logging.New(&buf, &buf)
+	if err := logs.Configure(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestCheckPathspecs(t *testing.T) {
+	fsys := fstest.MapFS{"locales/en.json": {}}
+	pathspecs := []string{"locales/en.json", "locales/en_US.json", "locales/100%.json"}
+
+	t.Run("off", func(t *testing.T) {
+		buf := captureLogs(t)
+		if err := checkPathspecs(checkOff, fsys, pathspecs); err != nil || buf.Len() != 0 {
+			t.Fatalf("expected no check, got %q (%v)", buf.String(), err)
+		}
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		buf := captureLogs(t)
+		if err := checkPathspecs(checkWarn, fsys, pathspecs); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "::warning title=Pathspec matches no files::\"locales/en_US.json\" matches no files; check translations_path, base_lang, and file_ext\n" +
+			"::warning title=Pathspec matches no files::\"locales/100%25.json\" matches no files; check translations_path, base_lang, and file_ext\n"
+		if buf.String() != want {
+			t.Fatalf("unexpected annotations:\n%s", buf.String())
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		buf := captureLogs(t)
+		err := checkPathspecs(checkFail, fsys, pathspecs)
+		if err == nil || err.Error() != "2 pathspec(s) match no files: locales/en_US.json, locales/100%.json" {
+			t.Fatalf("expected error, got %v", err)
+		}
+		if !strings.HasPrefix(buf.String(), "::error title=Pathspec matches no files::") {
+			t.Fatalf("expected error annotations, got %q", buf.String())
+		}
+	})
+
+	t.Run("all matched", func(t *testing.T) {
+		buf := captureLogs(t)
+		if err := checkPathspecs(checkFail, fsys, pathspecs[:1]); err != nil || buf.Len() != 0 {
+			t.Fatalf("expected no findings, got %q (%v)", buf.String(), err)
+		}
+	})
+}
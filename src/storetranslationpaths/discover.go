@@ -0,0 +1,146 @@
+package storetranslationpaths
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// discoveredRoot is a translation root found by AUTO_DISCOVER_PATHS together
+// with its layout.
+type discoveredRoot struct {
+	Path string
+	Flat bool
+}
+
+// discoverySkipDirs regularly contain locale files of third-party packages,
+// so discovery never enters them (nor any dot-directory such as .git).
+var discoverySkipDirs = map[string]struct{}{
+	"node_modules": {},
+	"vendor":       {},
+}
+
+// autoDiscoverPaths discovers translation roots below dir and returns their
+// repo-relative paths along with the per-root FLAT_NAMING values.
+func autoDiscoverPaths(dir, baseLang string, fileExts []string) ([]string, map[string]bool, error) {
+	roots, err := discoverTranslationRoots(dir, baseLang, fileExts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot discover translation roots: %w", err)
+	}
+	if len(roots) == 0 {
+		return nil, nil, fmt.Errorf(
+			"cannot discover translation roots: found no %s.<ext> files or %s/ folders with .%s files; set translations_path instead",
+			baseLang, baseLang, strings.Join(fileExts, ", ."),
+		)
+	}
+
+	paths := make([]string, 0, len(roots))
+	flatByRoot := make(map[string]bool, len(roots))
+	for _, root := range roots {
+		paths = append(paths, root.Path)
+		flatByRoot[root.Path] = root.Flat
+	}
+	return paths, flatByRoot, nil
+}
+
+// discoverTranslationRoots walks dir in lexical order looking for directories
+// that hold a <baseLang>.<ext> file (flat layout) or a <baseLang>/ folder with
+// files of an allowed extension (nested layout). When both are present the flat
+// layout wins. Discovered roots are not searched for nested roots.
+func discoverTranslationRoots(dir, baseLang string, fileExts []string) ([]discoveredRoot, error) {
+	var roots []discoveredRoot
+
+	err := filepath.WalkDir(dir, func(fp string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if fp != dir && skipDiscoveryDir(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		flat, ok, err := detectRootLayout(fp, baseLang, fileExts)
+		if err != nil || !ok {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, fp)
+		if err != nil {
+			return err
+		}
+		roots = append(roots, discoveredRoot{Path: filepath.ToSlash(rel), Flat: flat})
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return roots, nil
+}
+
+// skipDiscoveryDir reports whether discovery must not enter a directory named name.
+func skipDiscoveryDir(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	_, ok := discoverySkipDirs[name]
+	return ok
+}
+
+// detectRootLayout reports whether dir is a translation root and, if so,
+// whether it uses the flat layout.
+func detectRootLayout(dir, baseLang string, fileExts []string) (flat, ok bool, err error) {
+	for _, ext := range fileExts {
+		info, err := os.Stat(filepath.Join(dir, baseLang+"."+ext))
+		if err == nil && info.Mode().IsRegular() {
+			return true, true, nil
+		}
+	}
+
+	langDir := filepath.Join(dir, baseLang)
+	info, err := os.Stat(langDir)
+	if err != nil || !info.IsDir() {
+		return false, false, nil
+	}
+
+	found, err := containsFileWithExt(langDir, fileExts)
+	if err != nil {
+		return false, false, err
+	}
+	return false, found, nil
+}
+
+// containsFileWithExt reports whether any file below dir has one of the extensions.
+func containsFileWithExt(dir string, fileExts []string) (bool, error) {
+	found := false
+	err := filepath.WalkDir(dir, func(fp string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if fp != dir && skipDiscoveryDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() && hasAnyExt(d.Name(), fileExts) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found, err
+}
+
+func hasAnyExt(name string, fileExts []string) bool {
+	for _, ext := range fileExts {
+		if strings.HasSuffix(name, "."+ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package storetranslationpaths
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeTree(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		fp := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(fp), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fp, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiscoverTranslationRoots(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir,
+		"apps/web/locales/en.json",
+		"apps/web/locales/fr.json",
+		"apps/mobile/i18n/en/common.json",
+		"apps/mobile/i18n/en/nested/errors.json",
+		"apps/mobile/i18n/fr/common.json",
+		"packages/ui/locales/en.yml", // wrong extension
+		"docs/en/index.md",           // lang folder without translation files
+		"node_modules/pkg/locales/en.json",
+		"vendor/lib/en.json",
+		".github/en.json",
+		"both/en.json",
+		"both/en/extra.json",
+		"both/sub/en.json", // inside a discovered root
+	)
+
+	got, err := discoverTranslationRoots(dir, "en", []string{"json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []discoveredRoot{
+		{Path: "apps/mobile/i18n", Flat: false},
+		{Path: "apps/web/locales", Flat: true},
+		{Path: "both", Flat: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("roots mismatch.\nwant=%v\ngot=%v", want, got)
+	}
+}
+
+func TestDiscoverTranslationRoots_RepoRoot(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, "en.json", "sub/locales/en.json")
+
+	got, err := discoverTranslationRoots(dir, "en", []string{"json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []discoveredRoot{{Path: ".", Flat: true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("roots mismatch.\nwant=%v\ngot=%v", want, got)
+	}
+}
+
+func TestAutoDiscoverPaths(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, "locales/en.json", "web/i18n/en/app.yaml")
+
+	paths, flat, err := autoDiscoverPaths(dir, "en", []string{"json", "yaml"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"locales", "web/i18n"}; !reflect.DeepEqual(paths, want) {
+		t.Fatalf("paths mismatch. want=%v got=%v", want, paths)
+	}
+	if want := map[string]bool{"locales": true, "web/i18n": false}; !reflect.DeepEqual(flat, want) {
+		t.Fatalf("flat mismatch. want=%v got=%v", want, flat)
+	}
+}
+
+func TestAutoDiscoverPaths_NoRoots(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, "locales/fr.json")
+
+	_, _, err := autoDiscoverPaths(dir, "en", []string{"json"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "found no en.<ext> files or en/ folders with .json files") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package storetranslationpaths
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"lokalise-push-action/internal/pathnorm"
+)
+
+// hasGlobMeta reports whether p holds glob metacharacters.
+func hasGlobMeta(p string) bool {
+	return strings.ContainsAny(p, `*?[]{}`)
+}
+
+// ensureRepoRelativeRoot validates a translation root like
+// pathnorm.EnsureRepoRelativePath. With allowGlobs set, glob segments such as
+// "packages/*/locales" are accepted as long as the pattern is well-formed; parent
+// escapes, absolute paths, and the like are still rejected.
+func ensureRepoRelativeRoot(p string, allowGlobs bool) (string, error) {
+	if !allowGlobs {
+		return pathnorm.EnsureRepoRelativePath(p)
+	}
+
+	clean, err := pathnorm.EnsureRepoRelativePattern(p)
+	if err != nil {
+		return "", err
+	}
+	if !doublestar.ValidatePattern(filepath.ToSlash(clean)) {
+		return "", fmt.Errorf("invalid glob pattern %q", p)
+	}
+	return clean, nil
+}
+
+// expandRootGlobs replaces glob roots with the directories of fsys they match,
+// sorted and without duplicates; other roots are kept as they are. The returned
+// map tells the glob each expanded directory came from, so per-root values keyed
+// by the glob can follow it. A glob matching no directory is an error, and
+// directories inside .git are never matched.
+func expandRootGlobs(fsys fs.FS, roots []string) ([]string, map[string]string, error) {
+	var (
+		out     []string
+		origins map[string]string
+		seen    = make(map[string]struct{}, len(roots))
+	)
+	add := func(root string) {
+		if _, ok := seen[root]; ok {
+			return
+		}
+		seen[root] = struct{}{}
+		out = append(out, root)
+	}
+
+	for _, root := range roots {
+		if !hasGlobMeta(root) {
+			add(root)
+			continue
+		}
+
+		var matches []string
+		err := doublestar.GlobWalk(fsys, root, func(p string, d fs.DirEntry) error {
+			if d.IsDir() && p != ".git" && !strings.HasPrefix(p, ".git/") {
+				matches = append(matches, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot expand TRANSLATIONS_PATH glob %q: %w", root, err)
+		}
+		if len(matches) == 0 {
+			return nil, nil, fmt.Errorf("TRANSLATIONS_PATH glob %q matches no directories", root)
+		}
+
+		slices.Sort(matches)
+		if origins == nil {
+			origins = make(map[string]string)
+		}
+		for _, m := range matches {
+			if _, ok := origins[m]; !ok {
+				origins[m] = root
+			}
+			add(m)
+		}
+	}
+	return out, origins, nil
+}
+
+// expandRootMap copies the value of each glob root in byRoot to the directories
+// it expanded to. Explicitly listed roots keep their own value.
+func expandRootMap[V any](byRoot map[string]V, origins map[string]string) map[string]V {
+	if byRoot == nil || len(origins) == 0 {
+		return byRoot
+	}
+	for dir, glob := range origins {
+		if _, ok := byRoot[dir]; ok {
+			continue
+		}
+		if v, ok := byRoot[glob]; ok {
+			byRoot[dir] = v
+		}
+	}
+	return byRoot
+}
@@ -0,0 +1,91 @@
+package storetranslationpaths
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEnsureRepoRelativeRoot(t *testing.T) {
+	got, err := ensureRepoRelativeRoot("./packages/*/locales/", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "packages/*/locales" {
+		t.Fatalf("expected cleaned glob, got %q", got)
+	}
+
+	tests := []struct {
+		path       string
+		allowGlobs bool
+		wantErr    string
+	}{
+		{"packages/*/locales", false, "glob characters are not allowed"},
+		{"../*/locales", true, "escapes repo root"},
+		{"/abs/*/locales", true, "must be relative"},
+		{"packages/[a/locales", true, "invalid glob pattern"},
+	}
+	for _, tt := range tests {
+		if _, err := ensureRepoRelativeRoot(tt.path, tt.allowGlobs); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Fatalf("%q: expected error containing %q, got %v", tt.path, tt.wantErr, err)
+		}
+	}
+}
+
+func TestExpandRootGlobs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"packages/b/locales/en.json": {},
+		"packages/a/locales/en.json": {},
+		"packages/c/README.md":       {},
+		"packages/d/locales":         {}, // a file, not a directory
+		".git/x/locales/en.json":     {},
+		"locales/en.json":            {},
+	}
+
+	got, origins, err := expandRootGlobs(fsys, []string{"locales", "packages/*/locales", "packages/a/locales"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"locales", "packages/a/locales", "packages/b/locales"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	wantOrigins := map[string]string{
+		"packages/a/locales": "packages/*/locales",
+		"packages/b/locales": "packages/*/locales",
+	}
+	if !reflect.DeepEqual(origins, wantOrigins) {
+		t.Fatalf("expected origins %v, got %v", wantOrigins, origins)
+	}
+
+	if _, _, err := expandRootGlobs(fsys, []string{"*/locales"}); err == nil || !strings.Contains(err.Error(), `glob "*/locales" matches no directories`) {
+		t.Fatalf("expected no-match error, got %v", err)
+	}
+
+	got, origins, err = expandRootGlobs(fsys, []string{"locales"})
+	if err != nil || origins != nil || !reflect.DeepEqual(got, []string{"locales"}) {
+		t.Fatalf("expected plain roots untouched, got %q %v %v", got, origins, err)
+	}
+}
+
+func TestExpandRootMap(t *testing.T) {
+	byRoot := map[string]bool{"packages/*/locales": true, "packages/b/locales": false}
+	origins := map[string]string{
+		"packages/a/locales": "packages/*/locales",
+		"packages/b/locales": "packages/*/locales",
+	}
+
+	got := expandRootMap(byRoot, origins)
+	want := map[string]bool{
+		"packages/*/locales": true,
+		"packages/a/locales": true,
+		"packages/b/locales": false,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got := expandRootMap[bool](nil, origins); got != nil {
+		t.Fatalf("expected nil map to stay nil, got %v", got)
+	}
+}
@@ -0,0 +1,185 @@
+package storetranslationpaths
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/ghoutput"
+	"lokalise-push-action/internal/logging"
+)
+
+// exitFunc is a function variable that defaults to os.Exit.
+// Overridable in tests to assert exit behavior without terminating the process.
+var exitFunc = os.Exit
+
+// logs is the logger of the command; Main configures it from the environment.
+var logs = logging.New(os.Stdout, os.Stderr)
+
+// Main writes the translation pathspecs, configured through environment variables.
+// It exits the process on failure.
+func Main() {
+	if err := logs.Configure(); err != nil {
+		returnWithError(err.Error())
+	}
+	if err := run(); err != nil {
+		returnWithError(err.Error())
+	}
+}
+
+// Check reads and validates the configuration of Main without running it.
+func Check() error {
+	_, err := validateEnvironment()
+	return envconf.Join([]error{logs.Configure(), err})
+}
+
+func run() error {
+	return runWith(
+		validateEnvironment,
+		createOutputFile,
+		storeTranslationPaths,
+		closeOutputFile,
+		ghoutput.Writer(logs),
+	)
+}
+
+func runWith(
+	validate func() (envConfig, error),
+	createFile func(string) (*os.File, error),
+	store storePathsFunc,
+	closeFile func(*os.File) error,
+	write func(string, string) bool,
+) (err error) {
+	// Read and validate inputs from the environment.
+	cfg, err := validate()
+	if err != nil {
+		return err
+	}
+
+	// Discovered, manifest, or glob-expanded roots replace TRANSLATIONS_PATH in
+	// every later step.
+	if cfg.AutoDiscovered || cfg.Manifest != "" || cfg.GlobsExpanded {
+		if err := writeResolvedRoots(cfg, os.Stdout, write); err != nil {
+			return err
+		}
+	}
+
+	// We persist the generated pathspecs to a file that is later consumed by
+	// detectchanges to filter the changed files.
+	file, err := createFile(cfg.PathsFile)
+	if err != nil {
+		return fmt.Errorf("cannot create output file: %w", err)
+	}
+
+	defer func() {
+		if closeErr := closeFile(file); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("cannot close output file: %w", closeErr))
+		}
+	}()
+
+	// Emit one pathspec per line. Consumers expect newline-separated patterns.
+	// Each line can be a direct file path or a glob (git pathspec-style).
+	if err := store(cfg, file); err != nil {
+		return fmt.Errorf("cannot store translation paths: %w", err)
+	}
+
+	// Tell later steps where the pathspecs are; the name may have been randomized.
+	if !write("paths_file", file.Name()) {
+		return fmt.Errorf("cannot write paths_file output")
+	}
+	// A post step wrapping the action can remove the file through its state.
+	if err := ghoutput.SaveState("paths_file", file.Name()); err != nil {
+		return fmt.Errorf("cannot save paths_file state: %w", err)
+	}
+
+	// Mirror the file as a multiline output for actions taking a files: list.
+	pathspecs, err := os.ReadFile(file.Name())
+	if err != nil {
+		return fmt.Errorf("cannot read stored translation paths: %w", err)
+	}
+	if !write("pathspecs", strings.TrimSuffix(string(pathspecs), "\n")) {
+		return fmt.Errorf("cannot write pathspecs output")
+	}
+
+	specs, err := pathspecsJSON(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot encode pathspecs: %w", err)
+	}
+	if !write("pathspecs_json", specs) {
+		return fmt.Errorf("cannot write pathspecs_json output")
+	}
+	if !write("pathspecs_hash", pathspecsHash(cfg)) {
+		return fmt.Errorf("cannot write pathspecs_hash output")
+	}
+
+	// The check works on the canonical patterns; the file may be anchored for other consumers.
+	var canonical []string
+	for _, p := range buildPathspecs(cfg) {
+		canonical = append(canonical, p.line())
+	}
+	if err := checkPathspecs(cfg.CheckPathspecs, os.DirFS("."), canonical); err != nil {
+		return err
+	}
+
+	// The summary is informational, so failing to write it doesn't fail the step.
+	if cfg.SummarizePathspecs {
+		if err := ghoutput.AppendStepSummary(renderPathspecsSummary(cfg)); err != nil {
+			logs.Warnf("%v", err)
+		}
+	}
+
+	// Exclusions go to a second file so generated or vendored files never trigger a push.
+	if len(cfg.Excludes) > 0 {
+		if err := storeIgnoreFile(cfg, ignoreFileFor(file.Name()), createFile, closeFile, write); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// storeIgnoreFile writes the exclusion pathspecs to path and publishes the file
+// as ignore_file and its contents as ignore_pathspecs.
+func storeIgnoreFile(
+	cfg envConfig,
+	path string,
+	createFile func(string) (*os.File, error),
+	closeFile func(*os.File) error,
+	write func(string, string) bool,
+) (err error) {
+	file, err := createFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot create ignore file: %w", err)
+	}
+
+	defer func() {
+		if closeErr := closeFile(file); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("cannot close ignore file: %w", closeErr))
+		}
+	}()
+
+	var pathspecs strings.Builder
+	if err := storeExclusions(cfg, io.MultiWriter(file, &pathspecs)); err != nil {
+		return fmt.Errorf("cannot store exclusions: %w", err)
+	}
+
+	if !write("ignore_file", file.Name()) {
+		return fmt.Errorf("cannot write ignore_file output")
+	}
+	if err := ghoutput.SaveState("ignore_file", file.Name()); err != nil {
+		return fmt.Errorf("cannot save ignore_file state: %w", err)
+	}
+	if !write("ignore_pathspecs", strings.TrimSuffix(pathspecs.String(), "\n")) {
+		return fmt.Errorf("cannot write ignore_pathspecs output")
+	}
+	return nil
+}
+
+// returnWithError prints an error and exits non-zero.
+func returnWithError(message string) {
+	logs.Errorf("%s", message)
+	exitFunc(1)
+}
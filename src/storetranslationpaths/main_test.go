@@ -0,0 +1,549 @@
+package storetranslationpaths
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/ghoutput"
+)
+
+func TestMain(m *testing.M) {
+	// Override exitFunc for testing.
+	exitFunc = func(code int) {
+		panic(fmt.Sprintf("Exit called with code %d", code))
+	}
+
+	code := m.Run()
+
+	// Restore exitFunc after testing.
+	exitFunc = os.Exit
+
+	os.Exit(code)
+}
+
+func TestRunWith(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		t.Parallel()
+
+		wantCfg := envConfig{
+			Paths:       []string{"translations"},
+			BaseLang:    "en",
+			FileExts:    []string{"json"},
+			NamePattern: "",
+			FlatNaming:  true,
+		}
+
+		validateCalled := false
+		createCalled := false
+		storeCalled := false
+		closeCalled := false
+
+		var createdFile *os.File
+
+		validate := func() (envConfig, error) {
+			validateCalled = true
+			return wantCfg, nil
+		}
+
+		createFile := func(string) (*os.File, error) {
+			createCalled = true
+
+			f, err := os.CreateTemp(t.TempDir(), "pathspecs-*.txt")
+			if err != nil {
+				t.Fatalf("failed to create temp file: %v", err)
+			}
+			createdFile = f
+			return f, nil
+		}
+
+		store := func(cfg envConfig, writer io.Writer) error {
+			storeCalled = true
+
+			if !reflect.DeepEqual(cfg, wantCfg) {
+				t.Fatalf("cfg mismatch.\nwant=%#v\ngot=%#v", wantCfg, cfg)
+			}
+			if writer != createdFile {
+				t.Fatalf("writer mismatch. want=%v got=%v", createdFile, writer)
+			}
+
+			return nil
+		}
+
+		closeFile := func(file *os.File) error {
+			closeCalled = true
+			if file != createdFile {
+				return fmt.Errorf("closeFile got unexpected file. want=%v got=%v", createdFile, file)
+			}
+
+			return file.Close()
+		}
+
+		err := runWith(validate, createFile, store, closeFile, noopWrite)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !validateCalled {
+			t.Fatal("validate was not called")
+		}
+		if !createCalled {
+			t.Fatal("createFile was not called")
+		}
+		if !storeCalled {
+			t.Fatal("store was not called")
+		}
+		if !closeCalled {
+			t.Fatal("closeFile was not called")
+		}
+	})
+
+	t.Run("returns validate error and stops", func(t *testing.T) {
+		t.Parallel()
+
+		validate := func() (envConfig, error) {
+			return envConfig{}, errors.New("bad env")
+		}
+
+		createFile := func(string) (*os.File, error) {
+			t.Fatal("createFile should not be called")
+			return nil, nil
+		}
+
+		store := func(envConfig, io.Writer) error {
+			t.Fatal("store should not be called")
+			return nil
+		}
+
+		closeFile := func(*os.File) error {
+			t.Helper()
+			t.Fatal("closeFile should not be called")
+			return nil
+		}
+
+		err := runWith(validate, createFile, store, closeFile, noopWrite)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if !strings.Contains(err.Error(), "bad env") {
+			t.Fatalf("expected error containing %q, got %q", "bad env", err.Error())
+		}
+	})
+
+	t.Run("wraps create output file error and stops", func(t *testing.T) {
+		t.Parallel()
+
+		validate := func() (envConfig, error) {
+			return envConfig{
+				Paths:      []string{"translations"},
+				BaseLang:   "en",
+				FileExts:   []string{"json"},
+				FlatNaming: true,
+			}, nil
+		}
+
+		createFile := func(string) (*os.File, error) {
+			return nil, errors.New("permission denied")
+		}
+
+		store := func(envConfig, io.Writer) error {
+			t.Fatal("store should not be called")
+			return nil
+		}
+
+		closeFile := func(*os.File) error {
+			t.Helper()
+			t.Fatal("closeFile should not be called")
+			return nil
+		}
+
+		err := runWith(validate, createFile, store, closeFile, noopWrite)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "cannot create output file") {
+			t.Fatalf("expected wrapped error containing %q, got %q", "cannot create output file", err.Error())
+		}
+		if !strings.Contains(err.Error(), "permission denied") {
+			t.Fatalf("expected wrapped error containing %q, got %q", "permission denied", err.Error())
+		}
+	})
+
+	t.Run("wraps store error and still closes file", func(t *testing.T) {
+		t.Parallel()
+
+		wantCfg := envConfig{
+			Paths:      []string{"translations"},
+			BaseLang:   "en",
+			FileExts:   []string{"json"},
+			FlatNaming: true,
+		}
+
+		var createdFile *os.File
+		closeCalled := false
+
+		validate := func() (envConfig, error) {
+			return wantCfg, nil
+		}
+
+		createFile := func(string) (*os.File, error) {
+			f, err := os.CreateTemp(t.TempDir(), "pathspecs-*.txt")
+			if err != nil {
+				t.Fatalf("failed to create temp file: %v", err)
+			}
+			createdFile = f
+			return f, nil
+		}
+
+		store := func(cfg envConfig, writer io.Writer) error {
+			if !reflect.DeepEqual(cfg, wantCfg) {
+				t.Fatalf("cfg mismatch.\nwant=%#v\ngot=%#v", wantCfg, cfg)
+			}
+			if writer != createdFile {
+				t.Fatalf("writer mismatch. want=%v got=%v", createdFile, writer)
+			}
+			return errors.New("disk full")
+		}
+
+		closeFile := func(file *os.File) error {
+			closeCalled = true
+			if file != createdFile {
+				return fmt.Errorf("closeFile got unexpected file. want=%v got=%v", createdFile, file)
+			}
+
+			return file.Close()
+		}
+
+		err := runWith(validate, createFile, store, closeFile, noopWrite)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if !strings.Contains(err.Error(), "cannot store translation paths") {
+			t.Fatalf("expected wrapped error containing %q, got %q", "cannot store translation paths", err.Error())
+		}
+		if !strings.Contains(err.Error(), "disk full") {
+			t.Fatalf("expected wrapped error containing %q, got %q", "disk full", err.Error())
+		}
+		if !closeCalled {
+			t.Fatal("closeFile was not called")
+		}
+	})
+}
+
+func noopWrite(string, string) bool { return true }
+
+func TestRunWith_AutoDiscovered(t *testing.T) {
+	cfg := envConfig{
+		Paths:            []string{"apps/web/locales", "config"},
+		BaseLang:         "en",
+		FileExts:         []string{"json"},
+		FlatNamingByRoot: map[string]bool{"apps/web/locales": false, "config": true},
+		AutoDiscovered:   true,
+	}
+	pathsFile := filepath.Join(t.TempDir(), "paths.txt")
+
+	got := map[string]string{}
+	write := func(name, value string) bool {
+		got[name] = value
+		return true
+	}
+
+	err := runWith(
+		func() (envConfig, error) { return cfg, nil },
+		func(string) (*os.File, error) { return os.Create(pathsFile) },
+		storeTranslationPaths,
+		closeOutputFile,
+		write,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"translations_path": "apps/web/locales\nconfig",
+		"flat_naming":       "false,true",
+		"paths_file":        pathsFile,
+		"pathspecs":         "apps/web/locales/en/**/*.json\nconfig/en.json",
+		"pathspecs_json": `[{"pattern":"apps/web/locales/en/**/*.json","root":"apps/web/locales","layout":"nested","lang":"en","extensions":["json"]},` +
+			`{"pattern":"config/en.json","root":"config","layout":"flat","lang":"en","extensions":["json"]}]`,
+		"pathspecs_hash": pathspecsHash(cfg),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("outputs mismatch.\nwant=%v\ngot=%v", want, got)
+	}
+
+	t.Run("output failure", func(t *testing.T) {
+		err := runWith(
+			func() (envConfig, error) { return cfg, nil },
+			func(string) (*os.File, error) {
+				t.Fatal("createFile should not be called")
+				return nil, nil
+			},
+			storeTranslationPaths,
+			closeOutputFile,
+			func(string, string) bool { return false },
+		)
+		if err == nil || !strings.Contains(err.Error(), "cannot write translations_path output") {
+			t.Fatalf("expected output error, got %v", err)
+		}
+	})
+}
+
+func TestRunWith_PathsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "job-1", "paths.txt")
+	cfg := envConfig{
+		Paths:      []string{"locales"},
+		BaseLang:   "en",
+		FileExts:   []string{"json"},
+		FlatNaming: true,
+		PathsFile:  path,
+	}
+
+	got := map[string]string{}
+	err := runWith(
+		func() (envConfig, error) { return cfg, nil },
+		createOutputFile,
+		storeTranslationPaths,
+		closeOutputFile,
+		func(name, value string) bool {
+			got[name] = value
+			return true
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"paths_file":     path,
+		"pathspecs":      "locales/en.json",
+		"pathspecs_json": `[{"pattern":"locales/en.json","root":"locales","layout":"flat","lang":"en","extensions":["json"]}]`,
+		"pathspecs_hash": "c9218bed23f0aeb93d7e1a7d5f6e1345fae20f4b8692b27fb97fd0f37b3d26d7",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("outputs mismatch. want=%v got=%v", want, got)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cannot read paths file: %v", err)
+	}
+	if string(data) != "locales/en.json\n" {
+		t.Fatalf("unexpected paths file content %q", data)
+	}
+
+	t.Run("output failure", func(t *testing.T) {
+		err := runWith(
+			func() (envConfig, error) { return cfg, nil },
+			createOutputFile,
+			storeTranslationPaths,
+			closeOutputFile,
+			func(string, string) bool { return false },
+		)
+		if err == nil || !strings.Contains(err.Error(), "cannot write paths_file output") {
+			t.Fatalf("expected output error, got %v", err)
+		}
+	})
+
+	t.Run("pathspecs_json output failure", func(t *testing.T) {
+		err := runWith(
+			func() (envConfig, error) { return cfg, nil },
+			createOutputFile,
+			storeTranslationPaths,
+			closeOutputFile,
+			func(name, _ string) bool { return name != "pathspecs_json" },
+		)
+		if err == nil || !strings.Contains(err.Error(), "cannot write pathspecs_json output") {
+			t.Fatalf("expected output error, got %v", err)
+		}
+	})
+
+	t.Run("pathspecs_hash output failure", func(t *testing.T) {
+		err := runWith(
+			func() (envConfig, error) { return cfg, nil },
+			createOutputFile,
+			storeTranslationPaths,
+			closeOutputFile,
+			func(name, _ string) bool { return name != "pathspecs_hash" },
+		)
+		if err == nil || !strings.Contains(err.Error(), "cannot write pathspecs_hash output") {
+			t.Fatalf("expected output error, got %v", err)
+		}
+	})
+
+	t.Run("pathspecs output failure", func(t *testing.T) {
+		err := runWith(
+			func() (envConfig, error) { return cfg, nil },
+			createOutputFile,
+			storeTranslationPaths,
+			closeOutputFile,
+			func(name, _ string) bool { return name != "pathspecs" },
+		)
+		if err == nil || !strings.Contains(err.Error(), "cannot write pathspecs output") {
+			t.Fatalf("expected output error, got %v", err)
+		}
+	})
+}
+
+func TestRunWith_UniquePathsFile(t *testing.T) {
+	dir := t.TempDir()
+	cfg := envConfig{
+		Paths:      []string{"locales"},
+		BaseLang:   "en",
+		FileExts:   []string{"json"},
+		FlatNaming: true,
+		PathsFile:  filepath.Join(dir, runnerPathsFile),
+	}
+
+	var written []string
+	for range 2 {
+		err := runWith(
+			func() (envConfig, error) { return cfg, nil },
+			createOutputFile,
+			storeTranslationPaths,
+			closeOutputFile,
+			func(name, value string) bool {
+				if name == "paths_file" {
+					written = append(written, value)
+				}
+				return true
+			},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if written[0] == written[1] {
+		t.Fatalf("expected unique files, got %q twice", written[0])
+	}
+	for _, path := range written {
+		if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "lokalise-paths-") || strings.Contains(path, "*") {
+			t.Fatalf("unexpected paths file %q", path)
+		}
+		if data, err := os.ReadFile(path); err != nil || string(data) != "locales/en.json\n" {
+			t.Fatalf("unexpected paths file content %q (%v)", data, err)
+		}
+	}
+}
+
+func TestRunWith_Excludes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "paths.txt")
+	cfg := envConfig{
+		Paths:      []string{"locales"},
+		BaseLang:   "en",
+		FileExts:   []string{"json"},
+		FlatNaming: true,
+		PathsFile:  path,
+		Excludes:   []string{"locales/vendor", "locales/vendor/**"},
+	}
+	state := filepath.Join(t.TempDir(), "state")
+	if err := os.WriteFile(state, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_STATE", state)
+
+	got := map[string]string{}
+	err := runWith(
+		func() (envConfig, error) { return cfg, nil },
+		createOutputFile,
+		storeTranslationPaths,
+		closeOutputFile,
+		func(name, value string) bool {
+			got[name] = value
+			return true
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ignorePath := filepath.Join(filepath.Dir(path), "paths.ignore.txt")
+	want := map[string]string{
+		"paths_file":       path,
+		"pathspecs":        "locales/en.json",
+		"pathspecs_json":   `[{"pattern":"locales/en.json","root":"locales","layout":"flat","lang":"en","extensions":["json"]}]`,
+		"pathspecs_hash":   "c9218bed23f0aeb93d7e1a7d5f6e1345fae20f4b8692b27fb97fd0f37b3d26d7",
+		"ignore_file":      ignorePath,
+		"ignore_pathspecs": "locales/vendor\nlocales/vendor/**",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("outputs mismatch. want=%v got=%v", want, got)
+	}
+	if data, err := os.ReadFile(ignorePath); err != nil || string(data) != "locales/vendor\nlocales/vendor/**\n" {
+		t.Fatalf("unexpected ignore file content %q (%v)", data, err)
+	}
+	data, err := os.ReadFile(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved, err := ghoutput.Parse(string(data)); err != nil || !reflect.DeepEqual(saved, map[string]string{"paths_file": path, "ignore_file": ignorePath}) {
+		t.Fatalf("unexpected saved state %v (%v)", saved, err)
+	}
+
+	t.Run("output failure", func(t *testing.T) {
+		err := runWith(
+			func() (envConfig, error) { return cfg, nil },
+			createOutputFile,
+			storeTranslationPaths,
+			closeOutputFile,
+			func(name, _ string) bool { return name != "ignore_pathspecs" },
+		)
+		if err == nil || !strings.Contains(err.Error(), "cannot write ignore_pathspecs output") {
+			t.Fatalf("expected output error, got %v", err)
+		}
+	})
+}
+
+func TestRunWith_StepSummary(t *testing.T) {
+	dir := t.TempDir()
+	summary := filepath.Join(dir, "summary.md")
+	cfg := envConfig{
+		Paths:              []string{"locales"},
+		BaseLang:           "en",
+		FileExts:           []string{"json"},
+		FlatNaming:         true,
+		PathsFile:          filepath.Join(dir, "paths.txt"),
+		SummarizePathspecs: true,
+	}
+	t.Setenv("GITHUB_STEP_SUMMARY", summary)
+
+	err := runWith(
+		func() (envConfig, error) { return cfg, nil },
+		createOutputFile,
+		storeTranslationPaths,
+		closeOutputFile,
+		noopWrite,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(summary)
+	if err != nil {
+		t.Fatalf("cannot read step summary: %v", err)
+	}
+	if !strings.Contains(string(data), "```text\nlocales/en.json\n```") {
+		t.Fatalf("unexpected step summary %q", data)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("TRANSLATIONS_PATH", "")
+
+	err := Check()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"invalid LOG_LEVEL", "TRANSLATIONS_PATH"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %q", want, err.Error())
+		}
+	}
+}
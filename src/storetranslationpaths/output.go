@@ -0,0 +1,86 @@
+package storetranslationpaths
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// writeResolvedRoots reports roots that were discovered, read from the manifest,
+// or expanded from TRANSLATIONS_PATH globs, and publishes them for later steps:
+// translations_path lists one root per line and flat_naming holds the matching
+// comma-separated layouts, ready to be passed as TRANSLATIONS_PATH and FLAT_NAMING.
+func writeResolvedRoots(cfg envConfig, log io.Writer, write func(string, string) bool) error {
+	flat := make([]string, 0, len(cfg.Paths))
+	switch {
+	case cfg.Manifest != "":
+		fmt.Fprintf(log, "Loaded %d translation root(s) from %s:\n", len(cfg.Paths), cfg.Manifest)
+	case cfg.GlobsExpanded:
+		fmt.Fprintf(log, "Expanded TRANSLATIONS_PATH globs to %d translation root(s):\n", len(cfg.Paths))
+	default:
+		fmt.Fprintf(log, "Discovered %d translation root(s):\n", len(cfg.Paths))
+	}
+	for _, root := range cfg.Paths {
+		isFlat := cfg.flatNamingFor(root)
+		layout := "nested"
+		if isFlat {
+			layout = "flat"
+		}
+		fmt.Fprintf(log, "  %s (%s)\n", root, layout)
+		flat = append(flat, strconv.FormatBool(isFlat))
+	}
+
+	// Later steps also split translations_path on commas, so escape them.
+	roots := make([]string, len(cfg.Paths))
+	for i, root := range cfg.Paths {
+		roots[i] = strings.ReplaceAll(root, ",", `\,`)
+	}
+	if !write("translations_path", strings.Join(roots, "\n")) {
+		return fmt.Errorf("cannot write translations_path output")
+	}
+	if !write("flat_naming", strings.Join(flat, ",")) {
+		return fmt.Errorf("cannot write flat_naming output")
+	}
+	return nil
+}
+
+// pathspecsJSON encodes the generated pathspecs, without duplicates, as a JSON
+// array with their root, layout, language, and extensions, for tooling that
+// consumes the watch configuration.
+func pathspecsJSON(cfg envConfig) (string, error) {
+	seen := make(map[string]struct{})
+	specs := []pathspec{}
+	for _, p := range buildPathspecs(cfg) {
+		if _, ok := seen[p.line()]; ok {
+			continue
+		}
+		seen[p.line()] = struct{}{}
+		specs = append(specs, p)
+	}
+
+	data, err := json.Marshal(specs)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// pathspecsHash returns the hex-encoded SHA-256 of the generated pathspecs,
+// sorted and without duplicates, so it only changes when the set of watched
+// patterns does. The anchoring style doesn't affect it.
+func pathspecsHash(cfg envConfig) string {
+	var lines []string
+	for _, p := range buildPathspecs(cfg) {
+		lines = append(lines, p.line())
+	}
+	slices.Sort(lines)
+	lines = slices.Compact(lines)
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,152 @@
+package storetranslationpaths
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/namepattern"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+func TestWriteResolvedRoots(t *testing.T) {
+	cfg := envConfig{
+		Paths:            []string{"locales", "packages/ui/i18n"},
+		FlatNamingByRoot: map[string]bool{"locales": true, "packages/ui/i18n": false},
+	}
+
+	got := map[string]string{}
+	var log strings.Builder
+	err := writeResolvedRoots(cfg, &log, func(name, value string) bool {
+		got[name] = value
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got["translations_path"] != "locales\npackages/ui/i18n" {
+		t.Fatalf("unexpected translations_path %q", got["translations_path"])
+	}
+	if got["flat_naming"] != "true,false" {
+		t.Fatalf("unexpected flat_naming %q", got["flat_naming"])
+	}
+	for _, want := range []string{"Discovered 2 translation root(s)", "locales (flat)", "packages/ui/i18n (nested)"} {
+		if !strings.Contains(log.String(), want) {
+			t.Fatalf("log %q does not contain %q", log.String(), want)
+		}
+	}
+}
+
+func TestWriteResolvedRoots_EscapesCommas(t *testing.T) {
+	cfg := envConfig{Paths: []string{"apps/a,b/locales", "locales"}}
+
+	var got string
+	err := writeResolvedRoots(cfg, io.Discard, func(name, value string) bool {
+		if name == "translations_path" {
+			got = value
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "apps/a\\,b/locales\nlocales"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	t.Setenv("TRANSLATIONS_PATH", got)
+	roots, err := envconf.ParseRepoRelativePathsEnv("TRANSLATIONS_PATH", pathnorm.EnsureRepoRelativePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(roots, cfg.Paths) {
+		t.Fatalf("expected roots to round-trip, got %q", roots)
+	}
+}
+
+func TestWriteResolvedRoots_Manifest(t *testing.T) {
+	cfg := envConfig{
+		Paths:            []string{"locales"},
+		FlatNamingByRoot: map[string]bool{"locales": true},
+		Manifest:         "lokalise.yml",
+	}
+
+	var log strings.Builder
+	if err := writeResolvedRoots(cfg, &log, noopWrite); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(log.String(), "Loaded 1 translation root(s) from lokalise.yml") {
+		t.Fatalf("unexpected log %q", log.String())
+	}
+}
+
+func TestWriteResolvedRoots_GlobsExpanded(t *testing.T) {
+	cfg := envConfig{
+		Paths:         []string{"packages/a/locales", "packages/b/locales"},
+		GlobsExpanded: true,
+	}
+
+	var log strings.Builder
+	if err := writeResolvedRoots(cfg, &log, noopWrite); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(log.String(), "Expanded TRANSLATIONS_PATH globs to 2 translation root(s)") {
+		t.Fatalf("unexpected log %q", log.String())
+	}
+}
+
+func TestPathspecsJSON(t *testing.T) {
+	cfg := envConfig{
+		Paths:             []string{"web/locales", "app/i18n", "web/locales"},
+		FlatNamingByRoot:  map[string]bool{"web/locales": true},
+		NamePatternByRoot: map[string]namepattern.Rule{"app/i18n": {Pattern: "**/*.yaml", Excludes: []string{"**/*.gen.yaml"}}},
+		BaseLang:          "en",
+		FileExts:          []string{"yaml", "json"},
+		CollapseExts:      true,
+		WatchPatterns:     []string{"lokalise.yml"},
+	}
+
+	got, err := pathspecsJSON(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `[` +
+		`{"pattern":"web/locales/en.{json,yaml}","root":"web/locales","layout":"flat","lang":"en","extensions":["json","yaml"]},` +
+		`{"pattern":"app/i18n/**/*.yaml","root":"app/i18n","layout":"name_pattern"},` +
+		`{"pattern":"app/i18n/**/*.gen.yaml","root":"app/i18n","layout":"name_pattern","exclude":true},` +
+		`{"pattern":"lokalise.yml","layout":"watch"}` +
+		`]`
+	if got != want {
+		t.Fatalf("unexpected JSON.\nwant=%s\ngot= %s", want, got)
+	}
+
+	if got, err := pathspecsJSON(envConfig{}); err != nil || got != "[]" {
+		t.Fatalf("expected empty array, got %q (%v)", got, err)
+	}
+}
+
+func TestPathspecsHash(t *testing.T) {
+	cfg := envConfig{
+		Paths:      []string{"locales"},
+		BaseLang:   "en",
+		FileExts:   []string{"json"},
+		FlatNaming: true,
+	}
+	if got, want := pathspecsHash(cfg), "c9218bed23f0aeb93d7e1a7d5f6e1345fae20f4b8692b27fb97fd0f37b3d26d7"; got != want {
+		t.Fatalf("unexpected hash. want=%s got=%s", want, got)
+	}
+
+	a := envConfig{Paths: []string{"web", "app"}, BaseLang: "en", FileExts: []string{"json"}}
+	b := envConfig{Paths: []string{"app", "web", "app"}, BaseLang: "en", FileExts: []string{"json"}, PathspecStyle: styleGlob}
+	if pathspecsHash(a) != pathspecsHash(b) {
+		t.Fatal("expected the hash to ignore root order, duplicates, and anchoring")
+	}
+
+	b.FileExts = []string{"yaml"}
+	if pathspecsHash(a) == pathspecsHash(b) {
+		t.Fatal("expected the hash to change with the pathspecs")
+	}
+}
@@ -0,0 +1,228 @@
+package storetranslationpaths
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type storePathsFunc func(cfg envConfig, writer io.Writer) error
+
+// pathspec is a generated pattern together with the configuration it comes from.
+// Pattern uses forward slashes and never starts with "!"; Exclude marks negated
+// pathspecs instead.
+type pathspec struct {
+	Pattern    string   `json:"pattern"`
+	Root       string   `json:"root,omitempty"`
+	Layout     string   `json:"layout"`
+	Lang       string   `json:"lang,omitempty"`
+	Extensions []string `json:"extensions,omitempty"`
+	Exclude    bool     `json:"exclude,omitempty"`
+}
+
+// Layouts reported for pathspecs.
+const (
+	layoutFlat        = "flat"
+	layoutNested      = "nested"
+	layoutNamePattern = "name_pattern"
+	layoutNameRegex   = "name_regex"
+	layoutWatch       = "watch"
+	layoutExtra       = "extra"
+)
+
+// line returns the pathspec as written to the paths file.
+func (p pathspec) line() string {
+	if p.Exclude {
+		return "!" + p.Pattern
+	}
+	return p.Pattern
+}
+
+// storeTranslationPaths emits one pathspec per root and (if applicable) per extension.
+// Output is newline-separated, ready for consumption by detectchanges.
+// See buildPathspecs for the rules.
+func storeTranslationPaths(cfg envConfig, writer io.Writer) error {
+	seen := make(map[string]struct{}) // avoid duplicates across roots/exts
+
+	for _, p := range buildPathspecs(cfg) {
+		if err := writeUniqueLine(writer, seen, p.line(), cfg.PathspecStyle); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildPathspecs expands the configuration into pathspecs, in root order.
+// Rules:
+//   - If nameRegex is set -> "<root>/**" (the uploader filters by the expression)
+//   - If namePattern is set, it fully overrides defaults and is written once per root.
+//     The pattern may include globs (e.g., "**/*.yaml") and/or a concrete filename.
+//     Its exclusions follow as "!<root>/<pattern>" lines.
+//   - If flatNaming is true  -> "<root>/<baseLang>.<ext>"
+//   - If flatNaming is false -> "<root>/<baseLang>/**/*.ext"
+//
+// Default layouts are expanded for BASE_LANG and every ADDITIONAL_BASE_LANGS entry,
+// or for any language ("<root>/*.<ext>", "<root>/*/**/*.ext") with WATCH_ALL_LANGS.
+// With COLLAPSE_EXTENSIONS, several extensions share one "*.{json,yaml}" pattern.
+// The layout is resolved per root, so per-root NAME_PATTERN, FLAT_NAMING, and FILE_EXT values are honored.
+// WATCH_PATTERNS and then the EXTRA_PATHS_FILE lines follow the roots as they are.
+// Duplicates are kept; writers drop them.
+func buildPathspecs(cfg envConfig) []pathspec {
+	var out []pathspec
+	add := func(p pathspec) {
+		p.Pattern = escapeLeading(path.Clean(p.Pattern))
+		p.Root = filepath.ToSlash(p.Root)
+		out = append(out, p)
+	}
+
+	for _, root := range cfg.Paths {
+		// Roots are literal paths (discovered ones may contain "[" or "{"), while
+		// NAME_PATTERN and WATCH_PATTERNS are globs and are kept as they are.
+		literalRoot := escapeGlob(root)
+
+		if cfg.NameRegex != nil {
+			// Regular expressions can't be expressed as pathspecs: watch the whole
+			// root and let the uploader skip files that don't match NAME_REGEX.
+			add(pathspec{Pattern: path.Join(literalRoot, "**"), Root: root, Layout: layoutNameRegex})
+			continue
+		}
+
+		if rule := cfg.nameRuleFor(root); rule.Pattern != "" {
+			// Custom pattern takes precedence; caller is responsible for including
+			// filename/ext or globs. We don't expand it per-extension.
+			add(pathspec{Pattern: path.Join(literalRoot, filepath.ToSlash(rule.Pattern)), Root: root, Layout: layoutNamePattern})
+			// Exclusions are passed through as negated pathspecs.
+			for _, exclude := range rule.Excludes {
+				add(pathspec{Pattern: path.Join(literalRoot, filepath.ToSlash(exclude)), Root: root, Layout: layoutNamePattern, Exclude: true})
+			}
+			continue
+		}
+
+		// Generate per-language, per-extension patterns based on layout.
+		flat := cfg.flatNamingFor(root)
+		layout := layoutNested
+		if flat {
+			layout = layoutFlat
+		}
+		extGroups := patternExtensions(cfg.fileExtsFor(root), cfg.CollapseExts)
+		langs := cfg.baseLangs()
+		if cfg.WatchAllLangs {
+			langs = []string{""}
+		}
+		for _, lang := range langs {
+			langPattern := "*"
+			if lang != "" {
+				langPattern = escapeGlob(lang)
+			}
+			for _, exts := range extGroups {
+				add(pathspec{
+					Pattern:    buildTranslationPattern(literalRoot, flat, langPattern, extPattern(exts)),
+					Root:       root,
+					Layout:     layout,
+					Lang:       lang,
+					Extensions: exts,
+				})
+			}
+		}
+	}
+
+	for _, pattern := range cfg.WatchPatterns {
+		add(pathspec{Pattern: filepath.ToSlash(pattern), Layout: layoutWatch})
+	}
+
+	for _, line := range cfg.ExtraPathspecs {
+		pattern, exclude := strings.CutPrefix(line, "!")
+		add(pathspec{Pattern: pattern, Layout: layoutExtra, Exclude: exclude})
+	}
+
+	return out
+}
+
+// patternExtensions groups the sorted, deduplicated extensions of a root to
+// build patterns for: one group per extension, or a single group with all of
+// them when COLLAPSE_EXTENSIONS is set.
+func patternExtensions(fileExts []string, collapse bool) [][]string {
+	// Sort extensions to keep output deterministic while preserving root order.
+	exts := make([]string, 0, len(fileExts))
+	for _, ext := range fileExts {
+		if ext = strings.TrimSpace(ext); ext != "" {
+			exts = append(exts, ext)
+		}
+	}
+	sort.Strings(exts)
+	exts = slices.Compact(exts)
+
+	if collapse && len(exts) > 1 {
+		return [][]string{exts}
+	}
+
+	groups := make([][]string, 0, len(exts))
+	for _, ext := range exts {
+		groups = append(groups, []string{ext})
+	}
+	return groups
+}
+
+// extPattern returns the extension part of a pattern: the extension itself, or
+// a "{a,b}" brace group that brace-aware consumers expand back.
+func extPattern(exts []string) string {
+	escaped := make([]string, len(exts))
+	for i, ext := range exts {
+		escaped[i] = escapeGlob(ext)
+	}
+	if len(escaped) == 1 {
+		return escaped[0]
+	}
+	return "{" + strings.Join(escaped, ",") + "}"
+}
+
+// globEscaper backslash-escapes the characters with a special meaning in globs
+// (doublestar, git pathspecs, and the micromatch-style globs of other actions).
+var globEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`, "{", `\{`, "}", `\}`,
+)
+
+// escapeGlob turns a literal path, such as a translation root, into a glob that
+// matches only that path. Spaces need no quoting since pathspecs are written one
+// per line.
+func escapeGlob(s string) string {
+	return globEscaper.Replace(filepath.ToSlash(s))
+}
+
+// escapeLeading escapes a leading "#" or "!", which line-based consumers would
+// read as a comment or a negation.
+func escapeLeading(pattern string) string {
+	if strings.HasPrefix(pattern, "#") || strings.HasPrefix(pattern, "!") {
+		return `\` + pattern
+	}
+	return pattern
+}
+
+// buildTranslationPattern builds the slash-separated pathspec for a single
+// root/extension pair. The parts must already be escaped.
+func buildTranslationPattern(root string, flatNaming bool, baseLang, ext string) string {
+	if flatNaming {
+		// <root>/<baseLang>.<ext>
+		return path.Join(root, fmt.Sprintf("%s.%s", baseLang, ext))
+	}
+
+	// <root>/<baseLang>/**/*.ext
+	return path.Join(root, baseLang, "**", fmt.Sprintf("*.%s", ext))
+}
+
+// storeExclusions emits one exclusion pathspec per line, ready for detectchanges
+// or the files_ignore input of other actions.
+func storeExclusions(cfg envConfig, writer io.Writer) error {
+	seen := make(map[string]struct{})
+	for _, exclude := range cfg.Excludes {
+		if err := writeUniqueLine(writer, seen, exclude, cfg.PathspecStyle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,665 @@
+package storetranslationpaths
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/namepattern"
+)
+
+func TestStoreTranslationPaths(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         envConfig
+		expected    []string
+		shouldError bool
+		exactOrder  bool
+		writer      io.Writer
+	}{
+		{
+			name: "Flat naming with valid paths",
+			cfg: envConfig{
+				Paths:      []string{"translations", "more_translations"},
+				FlatNaming: true,
+				BaseLang:   "en",
+				FileExts:   []string{"json"},
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "en.json"),
+				filepath.Join(".", "more_translations", "en.json"),
+			},
+		},
+		{
+			name: "Per-root layout",
+			cfg: envConfig{
+				Paths:            []string{"web/locales", "app/i18n"},
+				FlatNamingByRoot: map[string]bool{"web/locales": true, "app/i18n": false},
+				BaseLang:         "en",
+				FileExts:         []string{"json"},
+			},
+			expected: []string{
+				filepath.Join(".", "web", "locales", "en.json"),
+				filepath.Join(".", "app", "i18n", "en", "**", "*.json"),
+			},
+		},
+		{
+			name: "Per-root name pattern",
+			cfg: envConfig{
+				Paths:             []string{"web/locales", "app/i18n"},
+				NamePatternByRoot: map[string]namepattern.Rule{"web/locales": {Pattern: "**/*.yaml"}},
+				BaseLang:          "en",
+				FileExts:          []string{"json"},
+			},
+			expected: []string{
+				filepath.Join(".", "web", "locales", "**", "*.yaml"),
+				filepath.Join(".", "app", "i18n", "en", "**", "*.json"),
+			},
+		},
+		{
+			name: "Name pattern with exclusions",
+			cfg: envConfig{
+				Paths:        []string{"config"},
+				NamePattern:  "**/*.yaml",
+				NameExcludes: []string{"**/*.generated.yaml"},
+				BaseLang:     "en",
+				FileExts:     []string{"json"},
+			},
+			expected: []string{
+				filepath.Join(".", "config", "**", "*.yaml"),
+				"!" + filepath.Join(".", "config", "**", "*.generated.yaml"),
+			},
+		},
+		{
+			name: "Name regex watches whole roots",
+			cfg: envConfig{
+				Paths:     []string{"src/main/resources", "admin"},
+				NameRegex: regexp.MustCompile(`^(?:messages_(?P<lang>[a-z]{2})\.properties)$`),
+				BaseLang:  "en",
+				FileExts:  []string{"properties"},
+			},
+			expected: []string{
+				filepath.Join(".", "src", "main", "resources", "**"),
+				filepath.Join(".", "admin", "**"),
+			},
+		},
+		{
+			name: "Additional base languages",
+			cfg: envConfig{
+				Paths:            []string{"web/locales", "app/i18n"},
+				FlatNamingByRoot: map[string]bool{"web/locales": true, "app/i18n": false},
+				BaseLang:         "en",
+				ExtraBaseLangs:   []string{"en_US"},
+				FileExts:         []string{"json", "yaml"},
+			},
+			expected: []string{
+				filepath.Join(".", "web", "locales", "en.json"),
+				filepath.Join(".", "web", "locales", "en.yaml"),
+				filepath.Join(".", "web", "locales", "en_US.json"),
+				filepath.Join(".", "web", "locales", "en_US.yaml"),
+				filepath.Join(".", "app", "i18n", "en", "**", "*.json"),
+				filepath.Join(".", "app", "i18n", "en", "**", "*.yaml"),
+				filepath.Join(".", "app", "i18n", "en_US", "**", "*.json"),
+				filepath.Join(".", "app", "i18n", "en_US", "**", "*.yaml"),
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Flat naming with valid path and multiple exts",
+			cfg: envConfig{
+				Paths:      []string{"translations"},
+				FlatNaming: true,
+				BaseLang:   "en",
+				FileExts:   []string{"json", "yaml"},
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "en.json"),
+				filepath.Join(".", "translations", "en.yaml"),
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Watch patterns follow the roots",
+			cfg: envConfig{
+				Paths:         []string{"locales"},
+				FlatNaming:    true,
+				BaseLang:      "en",
+				FileExts:      []string{"json"},
+				WatchPatterns: []string{"lokalise.yml", "config/i18n.*.ts", "lokalise.yml"},
+			},
+			expected: []string{
+				filepath.Join(".", "locales", "en.json"),
+				"lokalise.yml",
+				filepath.Join("config", "i18n.*.ts"),
+			},
+			exactOrder: true,
+		},
+		{
+			name: "All languages",
+			cfg: envConfig{
+				Paths:            []string{"web/locales", "app/i18n"},
+				FlatNamingByRoot: map[string]bool{"web/locales": true, "app/i18n": false},
+				BaseLang:         "en",
+				ExtraBaseLangs:   []string{"en_US"},
+				WatchAllLangs:    true,
+				FileExts:         []string{"json"},
+			},
+			expected: []string{
+				filepath.Join(".", "web", "locales", "*.json"),
+				filepath.Join(".", "app", "i18n", "*", "**", "*.json"),
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Literal parts are escaped",
+			cfg: envConfig{
+				Paths:         []string{"special chars dir", "apps/[legacy] {v1}", "#i18n", "!locales"},
+				FlatNaming:    true,
+				BaseLang:      "en",
+				FileExts:      []string{"json"},
+				WatchPatterns: []string{"#config/*.yml"},
+			},
+			expected: []string{
+				"special chars dir/en.json",
+				`apps/\[legacy\] \{v1\}/en.json`,
+				`\#i18n/en.json`,
+				`\!locales/en.json`,
+				`\#config/*.yml`,
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Name pattern globs are kept",
+			cfg: envConfig{
+				Paths:        []string{"apps/[legacy]"},
+				NamePattern:  "**/*.{yaml,yml}",
+				NameExcludes: []string{"**/*.gen.yaml"},
+				BaseLang:     "en",
+				FileExts:     []string{"json"},
+			},
+			expected: []string{
+				`apps/\[legacy\]/**/*.{yaml,yml}`,
+				`!apps/\[legacy\]/**/*.gen.yaml`,
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Extra pathspecs merged without duplicates",
+			cfg: envConfig{
+				Paths:          []string{"locales"},
+				BaseLang:       "en",
+				FileExts:       []string{"json"},
+				FlatNaming:     true,
+				ExtraPathspecs: []string{"legacy/en/*.json", "locales/en.json", "!legacy/en/tmp.json"},
+			},
+			expected: []string{
+				"locales/en.json",
+				"legacy/en/*.json",
+				"!legacy/en/tmp.json",
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Glob style",
+			cfg: envConfig{
+				Paths:         []string{"config"},
+				NamePattern:   "**/*.yaml",
+				NameExcludes:  []string{"**/*.generated.yaml"},
+				BaseLang:      "en",
+				FileExts:      []string{"json"},
+				PathspecStyle: styleGlob,
+			},
+			expected: []string{
+				":(glob)config/**/*.yaml",
+				":(glob,exclude)config/**/*.generated.yaml",
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Collapsed extensions",
+			cfg: envConfig{
+				Paths:            []string{"web/locales", "app/i18n"},
+				FlatNamingByRoot: map[string]bool{"web/locales": true, "app/i18n": false},
+				BaseLang:         "en",
+				FileExts:         []string{"yaml", "json", "yaml"},
+				CollapseExts:     true,
+			},
+			expected: []string{
+				filepath.Join(".", "web", "locales", "en.{json,yaml}"),
+				filepath.Join(".", "app", "i18n", "en", "**", "*.{json,yaml}"),
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Collapsing a single extension keeps it as is",
+			cfg: envConfig{
+				Paths:        []string{"translations"},
+				FlatNaming:   true,
+				BaseLang:     "en",
+				FileExts:     []string{"json"},
+				CollapseExts: true,
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "en.json"),
+			},
+		},
+		{
+			name: "Duplicate extensions are deduped and sorted deterministically",
+			cfg: envConfig{
+				Paths:      []string{"translations"},
+				FlatNaming: true,
+				BaseLang:   "en",
+				FileExts:   []string{"yaml", "json", "yaml", "json"},
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "en.json"),
+				filepath.Join(".", "translations", "en.yaml"),
+			},
+			exactOrder: true,
+		},
+		{
+			name: "No paths produces no output",
+			cfg: envConfig{
+				Paths:      []string{},
+				FlatNaming: true,
+				BaseLang:   "en",
+				FileExts:   []string{"json"},
+			},
+			expected:   []string{},
+			exactOrder: true,
+		},
+		{
+			name: "No file extensions and no name pattern produce no output",
+			cfg: envConfig{
+				Paths:      []string{"translations"},
+				FlatNaming: true,
+				BaseLang:   "en",
+				FileExts:   []string{},
+			},
+			expected:   []string{},
+			exactOrder: true,
+		},
+		{
+			name: "Name pattern override works with empty extensions",
+			cfg: envConfig{
+				Paths:       []string{"translations"},
+				FlatNaming:  true,
+				BaseLang:    "en",
+				FileExts:    []string{},
+				NamePattern: "**/*.yaml",
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "**", "*.yaml"),
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Custom naming pattern",
+			cfg: envConfig{
+				Paths:       []string{"translations", "more_translations"},
+				FlatNaming:  true,
+				BaseLang:    "en",
+				FileExts:    []string{"json"},
+				NamePattern: "custom_name.json",
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "custom_name.json"),
+				filepath.Join(".", "more_translations", "custom_name.json"),
+			},
+		},
+		{
+			name: "Nested naming with custom pattern",
+			cfg: envConfig{
+				Paths:       []string{"translations", "translations"},
+				FlatNaming:  false,
+				BaseLang:    "en",
+				FileExts:    []string{"json"},
+				NamePattern: "**.yaml",
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "**.yaml"),
+			},
+		},
+		{
+			name: "Flat naming with nested paths",
+			cfg: envConfig{
+				Paths:      []string{"dir1/dir2/dir3", "another/nested/dir"},
+				FlatNaming: true,
+				BaseLang:   "fr",
+				FileExts:   []string{"xml"},
+			},
+			expected: []string{
+				filepath.Join(".", "dir1", "dir2", "dir3", "fr.xml"),
+				filepath.Join(".", "another", "nested", "dir", "fr.xml"),
+			},
+		},
+		{
+			name: "Nested naming with nested paths",
+			cfg: envConfig{
+				Paths:      []string{"dir1/dir2/dir3", "another/nested/dir"},
+				FlatNaming: false,
+				BaseLang:   "de",
+				FileExts:   []string{"properties"},
+			},
+			expected: []string{
+				filepath.Join(".", "dir1", "dir2", "dir3", "de", "**", "*.properties"),
+				filepath.Join(".", "another", "nested", "dir", "de", "**", "*.properties"),
+			},
+		},
+		{
+			name: "Root path with flat naming",
+			cfg: envConfig{
+				Paths:      []string{"."},
+				FlatNaming: true,
+				BaseLang:   "en",
+				FileExts:   []string{"json"},
+			},
+			expected: []string{
+				filepath.Join(".", ".", "en.json"),
+			},
+		},
+		{
+			name: "Root path with custom name pattern",
+			cfg: envConfig{
+				Paths:       []string{"."},
+				FlatNaming:  false,
+				BaseLang:    "en",
+				FileExts:    []string{"json"},
+				NamePattern: "some_dir/**.yaml",
+			},
+			expected: []string{
+				filepath.Join(".", ".", "some_dir", "**.yaml"),
+			},
+		},
+		{
+			name: "Complex custom name pattern",
+			cfg: envConfig{
+				Paths:       []string{"translations"},
+				FlatNaming:  false,
+				BaseLang:    "en",
+				FileExts:    []string{"json"},
+				NamePattern: "en/**/custom_*.json",
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "en", "**", "custom_*.json"),
+			},
+		},
+		{
+			name: "Nested naming with root path",
+			cfg: envConfig{
+				Paths:      []string{"."},
+				FlatNaming: false,
+				BaseLang:   "en",
+				FileExts:   []string{"json"},
+			},
+			expected: []string{
+				filepath.Join(".", ".", "en", "**", "*.json"),
+			},
+		},
+		{
+			name: "Duplicate paths and duplicate extensions are deduped",
+			cfg: envConfig{
+				Paths:      []string{"translations", "translations"},
+				FlatNaming: true,
+				BaseLang:   "en",
+				FileExts:   []string{"json", "json"},
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "en.json"),
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Name pattern overrides extension expansion",
+			cfg: envConfig{
+				Paths:       []string{"translations"},
+				FlatNaming:  true,
+				BaseLang:    "en",
+				FileExts:    []string{"json", "yaml", "xml"},
+				NamePattern: "custom_name.txt",
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "custom_name.txt"),
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Extensions are sorted deterministically",
+			cfg: envConfig{
+				Paths:      []string{"translations"},
+				FlatNaming: true,
+				BaseLang:   "en",
+				FileExts:   []string{"yaml", "json"},
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "en.json"),
+				filepath.Join(".", "translations", "en.yaml"),
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Empty extensions are skipped",
+			cfg: envConfig{
+				Paths:      []string{"translations"},
+				FlatNaming: true,
+				BaseLang:   "en",
+				FileExts:   []string{"json", "", "   ", "yaml"},
+			},
+			expected: []string{
+				filepath.Join(".", "translations", "en.json"),
+				filepath.Join(".", "translations", "en.yaml"),
+			},
+			exactOrder: true,
+		},
+		{
+			name: "Writer error is returned",
+			cfg: envConfig{
+				Paths:      []string{"translations"},
+				FlatNaming: true,
+				BaseLang:   "en",
+				FileExts:   []string{"json"},
+			},
+			shouldError: true,
+			writer:      failingWriter{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			writer := tt.writer
+			if writer == nil {
+				writer = &buf
+			}
+
+			err := storeTranslationPaths(tt.cfg, writer)
+
+			if tt.shouldError {
+				if err == nil {
+					t.Fatal("expected an error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			lines := normalizeLines(strings.Split(strings.TrimSpace(buf.String()), "\n"))
+			expected := normalizeLines(tt.expected)
+
+			if tt.exactOrder {
+				if !reflect.DeepEqual(lines, expected) {
+					t.Fatalf("unexpected lines.\nwant=%v\ngot=%v", expected, lines)
+				}
+				return
+			}
+
+			for _, expectedLine := range expected {
+				if !slices.Contains(lines, expectedLine) {
+					t.Errorf("missing expected line: %s", expectedLine)
+				}
+			}
+
+			if len(lines) != len(expected) {
+				t.Errorf("unexpected number of lines. expected %d, got %d", len(expected), len(lines))
+			}
+		})
+	}
+}
+
+func normalizeLines(lines []string) []string {
+	var normalized []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		normalized = append(normalized, filepath.ToSlash(line))
+	}
+	return normalized
+}
+
+func TestStoreExclusions(t *testing.T) {
+	cfg := envConfig{Excludes: []string{"locales/vendor", "locales/vendor/**", "**/*.bak", "locales/vendor"}}
+
+	var buf bytes.Buffer
+	if err := storeExclusions(cfg, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "locales/vendor\nlocales/vendor/**\n**/*.bak\n"; buf.String() != want {
+		t.Fatalf("unexpected exclusions. want=%q got=%q", want, buf.String())
+	}
+}
+
+func TestStoreTranslationPaths_NamePatternPerRootFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    string
+	}{
+		{
+			name:    "aligned list",
+			pattern: "**/*.yaml\n*.strings",
+			want:    "web/locales/**/*.yaml\napp/i18n/*.strings\n",
+		},
+		{
+			name:    "mapping with default layout",
+			pattern: "app/i18n: ['*.strings', '!*.generated.strings']",
+			want:    "web/locales/en/**/*.json\napp/i18n/*.strings\n!app/i18n/*.generated.strings\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "web/locales\napp/i18n")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", "json")
+			t.Setenv("NAME_PATTERN", tt.pattern)
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", "")
+
+			cfg, err := validateEnvironment()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := storeTranslationPaths(cfg, &buf); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := filepath.ToSlash(buf.String()); got != tt.want {
+				t.Fatalf("unexpected pathspecs. want=%q got=%q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStoreExclusions_Style(t *testing.T) {
+	cfg := envConfig{Excludes: []string{"locales/vendor", "locales/vendor/**"}, PathspecStyle: styleDot}
+
+	var buf bytes.Buffer
+	if err := storeExclusions(cfg, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "./locales/vendor\n./locales/vendor/**\n"; buf.String() != want {
+		t.Fatalf("unexpected exclusions. want=%q got=%q", want, buf.String())
+	}
+}
+
+func TestStoreTranslationPaths_FlatNamingPerRootFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		flat string
+		want string
+	}{
+		{
+			name: "single value",
+			flat: "true",
+			want: "web/locales/en.json\napp/i18n/en.json\n",
+		},
+		{
+			name: "aligned list",
+			flat: "false,true",
+			want: "web/locales/en/**/*.json\napp/i18n/en.json\n",
+		},
+		{
+			name: "aligned multiline list",
+			flat: "true\nfalse",
+			want: "web/locales/en.json\napp/i18n/en/**/*.json\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "web/locales\napp/i18n")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", "json")
+			t.Setenv("NAME_PATTERN", "")
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", tt.flat)
+
+			cfg, err := validateEnvironment()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := storeTranslationPaths(cfg, &buf); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := filepath.ToSlash(buf.String()); got != tt.want {
+				t.Fatalf("unexpected pathspecs. want=%q got=%q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStoreTranslationPaths_FileExtPerRootFromEnv(t *testing.T) {
+	t.Setenv("TRANSLATIONS_PATH", "apps/web/locales\napps/ios/i18n")
+	t.Setenv("BASE_LANG", "en")
+	t.Setenv("FILE_EXT", "apps/web/locales: [yaml, json]\napps/ios/i18n: strings")
+	t.Setenv("NAME_PATTERN", "")
+	t.Setenv("NAME_REGEX", "")
+	t.Setenv("FLAT_NAMING", "false,true")
+
+	cfg, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := storeTranslationPaths(cfg, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "apps/web/locales/en/**/*.json\napps/web/locales/en/**/*.yaml\napps/ios/i18n/en.strings\n"
+	if got := filepath.ToSlash(buf.String()); got != want {
+		t.Fatalf("unexpected pathspecs. want=%q got=%q", want, got)
+	}
+}
@@ -0,0 +1,43 @@
+package storetranslationpaths
+
+import (
+	"fmt"
+	"strings"
+)
+
+// summaryPreviewLimit caps the pathspecs listed in the job summary; the rest
+// are only counted.
+const summaryPreviewLimit = 20
+
+// renderPathspecsSummary renders the number of generated pathspecs and a
+// preview of them as Markdown for the job summary.
+func renderPathspecsSummary(cfg envConfig) string {
+	seen := make(map[string]struct{})
+	var lines []string
+	for _, p := range buildPathspecs(cfg) {
+		if _, ok := seen[p.line()]; ok {
+			continue
+		}
+		seen[p.line()] = struct{}{}
+		lines = append(lines, p.line())
+	}
+
+	var b strings.Builder
+	b.WriteString("### Translation pathspecs\n\n")
+	fmt.Fprintf(&b, "Changes to files matching these %d pathspec(s) trigger a push", len(lines))
+	if len(cfg.Excludes) > 0 {
+		fmt.Fprintf(&b, ", except for %d exclusion(s)", len(cfg.Excludes))
+	}
+	b.WriteString(":\n\n```text\n")
+	for i, line := range lines {
+		if i == summaryPreviewLimit {
+			break
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("```\n")
+	if len(lines) > summaryPreviewLimit {
+		fmt.Fprintf(&b, "\n…and %d more; see the `pathspecs` output for the full list.\n", len(lines)-summaryPreviewLimit)
+	}
+	return b.String()
+}
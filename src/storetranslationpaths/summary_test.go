@@ -0,0 +1,44 @@
+package storetranslationpaths
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/namepattern"
+)
+
+func TestRenderPathspecsSummary(t *testing.T) {
+	cfg := envConfig{
+		Paths:    []string{"web/locales", "app/i18n", "web/locales"},
+		BaseLang: "en",
+		FileExts: []string{"json"},
+		NamePatternByRoot: map[string]namepattern.Rule{
+			"app/i18n": {Pattern: "**/*.yaml", Excludes: []string{"**/*.gen.yaml"}},
+		},
+		FlatNaming: true,
+		Excludes:   []string{"web/locales/vendor", "web/locales/vendor/**"},
+	}
+
+	want := "### Translation pathspecs\n\n" +
+		"Changes to files matching these 3 pathspec(s) trigger a push, except for 2 exclusion(s):\n\n" +
+		"```text\nweb/locales/en.json\napp/i18n/**/*.yaml\n!app/i18n/**/*.gen.yaml\n```\n"
+	if got := renderPathspecsSummary(cfg); got != want {
+		t.Fatalf("unexpected summary.\nwant=%q\ngot= %q", want, got)
+	}
+}
+
+func TestRenderPathspecsSummary_Preview(t *testing.T) {
+	cfg := envConfig{BaseLang: "en", FileExts: []string{"json"}, FlatNaming: true}
+	for i := range summaryPreviewLimit + 3 {
+		cfg.Paths = append(cfg.Paths, fmt.Sprintf("root%02d", i))
+	}
+
+	got := renderPathspecsSummary(cfg)
+	if !strings.Contains(got, "root19/en.json\n```") || strings.Contains(got, "root20/en.json") {
+		t.Fatalf("expected the preview to stop after %d pathspecs, got %q", summaryPreviewLimit, got)
+	}
+	if !strings.HasSuffix(got, "…and 3 more; see the `pathspecs` output for the full list.\n") {
+		t.Fatalf("expected the remaining pathspecs to be counted, got %q", got)
+	}
+}
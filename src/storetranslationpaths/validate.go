@@ -0,0 +1,507 @@
+package storetranslationpaths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bodrovis/lokalise-actions-common/v2/normalizers"
+	"github.com/bodrovis/lokalise-actions-common/v2/parsers"
+
+	"lokalise-push-action/internal/envconf"
+	"lokalise-push-action/internal/manifest"
+	"lokalise-push-action/internal/namepattern"
+	"lokalise-push-action/internal/pathnorm"
+)
+
+type envConfig struct {
+	Paths              []string
+	BaseLang           string
+	ExtraBaseLangs     []string
+	WatchAllLangs      bool
+	FileExts           []string
+	FileExtsByRoot     map[string][]string
+	CollapseExts       bool
+	NamePattern        string
+	NameExcludes       []string
+	NamePatternByRoot  map[string]namepattern.Rule
+	NameRegex          *regexp.Regexp
+	FlatNaming         bool
+	FlatNamingByRoot   map[string]bool
+	AutoDiscovered     bool
+	GlobsExpanded      bool
+	Manifest           string
+	PathsFile          string
+	Excludes           []string
+	CheckPathspecs     string
+	WatchPatterns      []string
+	ExtraPathspecs     []string
+	SummarizePathspecs bool
+	PathspecStyle      string
+}
+
+// defaultPathsFile is used outside GitHub Actions. It lives inside .git so it
+// never shows up as a working tree change.
+var defaultPathsFile = filepath.Join(".git", "lokalise-action", "paths.txt")
+
+// runnerPathsFile is created under RUNNER_TEMP when running in a workflow; the
+// "*" makes the name unique, so concurrent jobs never share a file.
+const runnerPathsFile = "lokalise-paths-*.txt"
+
+// flatNamingFor reports whether root uses the flat layout, honoring per-root
+// FLAT_NAMING values when given.
+func (c envConfig) flatNamingFor(root string) bool {
+	if flat, ok := c.FlatNamingByRoot[filepath.ToSlash(root)]; ok {
+		return flat
+	}
+	return c.FlatNaming
+}
+
+// fileExtsFor returns the FILE_EXT extensions of root, honoring the per-root
+// mapping when given.
+func (c envConfig) fileExtsFor(root string) []string {
+	if c.FileExtsByRoot != nil {
+		return c.FileExtsByRoot[filepath.ToSlash(root)]
+	}
+	return c.FileExts
+}
+
+// baseLangs returns BASE_LANG followed by the ADDITIONAL_BASE_LANGS.
+func (c envConfig) baseLangs() []string {
+	return append([]string{c.BaseLang}, c.ExtraBaseLangs...)
+}
+
+// nameRuleFor returns the NAME_PATTERN rule for root, honoring per-root
+// patterns when given. An empty pattern means the default layout applies.
+func (c envConfig) nameRuleFor(root string) namepattern.Rule {
+	if c.NamePatternByRoot != nil {
+		return c.NamePatternByRoot[filepath.ToSlash(root)]
+	}
+	return namepattern.Rule{Pattern: c.NamePattern, Excludes: c.NameExcludes}
+}
+
+// validateEnvironment reads required variables and applies simple inference.
+// Every invalid variable is reported, not just the first one, so a workflow
+// can be fixed in one pass; checks that depend on an invalid value are skipped.
+func validateEnvironment() (envConfig, error) {
+	autoDiscover, err := parseAutoDiscover()
+	if err != nil {
+		return envConfig{}, err
+	}
+
+	// A manifest replaces the root and layout env variables.
+	m, err := manifest.ParseFile()
+	if err != nil {
+		return envConfig{}, err
+	}
+	if m != nil && autoDiscover {
+		return envConfig{}, fmt.Errorf("AUTO_DISCOVER_PATHS and MANIFEST_FILE cannot be used together")
+	}
+
+	var errs []error
+
+	allowGlobs, globsErr := parsers.ParseBoolEnv("TRANSLATIONS_PATH_GLOBS")
+	if globsErr != nil {
+		errs = append(errs, fmt.Errorf("invalid TRANSLATIONS_PATH_GLOBS: expected true or false: %w", globsErr))
+	}
+
+	var (
+		paths        []string
+		manifestFile string
+		globOrigins  map[string]string
+	)
+	switch {
+	case m != nil:
+		paths, manifestFile = m.Paths, m.File
+	case !autoDiscover:
+		paths, err = parseTranslationsPaths(allowGlobs)
+		if err == nil {
+			// Glob roots become the directories they match before anything is keyed by root.
+			paths, globOrigins, err = expandRootGlobs(os.DirFS("."), paths)
+		}
+		errs = append(errs, err)
+	}
+	rootsOK := err == nil
+
+	baseLang, err := parsers.ParseLangEnv("BASE_LANG")
+	errs = append(errs, err)
+	baseLangOK := err == nil
+
+	var extraBaseLangs []string
+	if baseLangOK {
+		extraBaseLangs, err = parseAdditionalBaseLangs(baseLang)
+		errs = append(errs, err)
+	}
+
+	watchAllLangs, err := parseWatchAllLangs()
+	errs = append(errs, err)
+
+	var (
+		fileExts       []string
+		fileExtsByRoot map[string][]string
+		fileExtsOK     = true
+	)
+	switch {
+	case m != nil && len(m.FileExts) > 0:
+		fileExts = m.FileExts
+	case autoDiscover && envconf.IsPatternMapping(os.Getenv("FILE_EXT")):
+		// Roots are only known after discovery, which needs the extensions.
+		errs = append(errs, fmt.Errorf("AUTO_DISCOVER_PATHS cannot be used with a FILE_EXT mapping"))
+		fileExtsOK = false
+	case rootsOK:
+		fileExts, fileExtsByRoot, err = parseFileExtensions(paths)
+		errs = append(errs, err)
+		fileExtsOK = err == nil
+	}
+
+	// Discovery needs the base language and extensions, so it runs after them.
+	var discoveredFlat map[string]bool
+	if autoDiscover {
+		if baseLangOK && fileExtsOK {
+			paths, discoveredFlat, err = autoDiscoverPaths(".", baseLang, fileExts)
+			errs = append(errs, err)
+			rootsOK = err == nil
+		} else {
+			rootsOK = false
+		}
+	}
+
+	var (
+		defaultRule       namepattern.Rule
+		namePatternByRoot map[string]namepattern.Rule
+		namePatternOK     = true
+	)
+	if m != nil {
+		namePatternByRoot = m.NamePatternByRoot
+	} else if rootsOK {
+		defaultRule, namePatternByRoot, err = namepattern.ParseEnv(paths, patternLineRoot)
+		errs = append(errs, err)
+		namePatternOK = err == nil
+	}
+
+	nameRegex, err := namepattern.ParseRegexEnv()
+	errs = append(errs, err)
+	if nameRegex != nil && namePatternOK && (defaultRule.Pattern != "" || namePatternByRoot != nil) {
+		errs = append(errs, fmt.Errorf("NAME_REGEX and NAME_PATTERN cannot be used together"))
+	}
+
+	var (
+		flatNaming       bool
+		flatNamingByRoot map[string]bool
+	)
+	switch {
+	case m != nil:
+		flatNamingByRoot = m.FlatNamingByRoot
+	case autoDiscover:
+		// The layout of each discovered root is known, so it overrides FLAT_NAMING.
+		flatNamingByRoot = discoveredFlat
+	default:
+		flatNaming, flatNamingByRoot, err = envconf.ParseFlatNaming(rootNormalizer(allowGlobs))
+		errs = append(errs, err)
+		flatNamingByRoot = expandRootMap(flatNamingByRoot, globOrigins)
+	}
+
+	pathsFile, err := parsePathsOutputFile()
+	errs = append(errs, err)
+
+	excludes, err := parseExcludes()
+	errs = append(errs, err)
+
+	collapseExts, err := parsers.ParseBoolEnv("COLLAPSE_EXTENSIONS")
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid COLLAPSE_EXTENSIONS: expected true or false: %w", err))
+	}
+
+	checkMode, err := parseCheckPathspecs()
+	errs = append(errs, err)
+
+	watchPatterns, err := envconf.ParseWatchPatternsEnv(pathnorm.EnsureRepoRelativePattern)
+	errs = append(errs, err)
+
+	style, err := parsePathspecStyle()
+	errs = append(errs, err)
+
+	extraPathspecs, err := parseExtraPathsFile()
+	errs = append(errs, err)
+
+	summarize, err := parsers.ParseBoolEnv("SUMMARIZE_PATHSPECS")
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid SUMMARIZE_PATHSPECS: expected true or false: %w", err))
+	}
+
+	if err := envconf.Join(errs); err != nil {
+		return envConfig{}, err
+	}
+
+	return envConfig{
+		Paths:              paths,
+		BaseLang:           baseLang,
+		ExtraBaseLangs:     extraBaseLangs,
+		WatchAllLangs:      watchAllLangs,
+		FileExts:           fileExts,
+		FileExtsByRoot:     fileExtsByRoot,
+		CollapseExts:       collapseExts,
+		NamePattern:        defaultRule.Pattern,
+		NameExcludes:       defaultRule.Excludes,
+		NamePatternByRoot:  namePatternByRoot,
+		NameRegex:          nameRegex,
+		FlatNaming:         flatNaming,
+		FlatNamingByRoot:   flatNamingByRoot,
+		AutoDiscovered:     autoDiscover,
+		GlobsExpanded:      len(globOrigins) > 0,
+		Manifest:           manifestFile,
+		PathsFile:          pathsFile,
+		Excludes:           excludes,
+		CheckPathspecs:     checkMode,
+		WatchPatterns:      watchPatterns,
+		ExtraPathspecs:     extraPathspecs,
+		SummarizePathspecs: summarize,
+		PathspecStyle:      style,
+	}, nil
+}
+
+// parseAdditionalBaseLangs reads ADDITIONAL_BASE_LANGS, further source locales
+// (e.g. en_US next to en) watched alongside BASE_LANG. Duplicates and BASE_LANG
+// itself are dropped.
+func parseAdditionalBaseLangs(baseLang string) ([]string, error) {
+	var langs []string
+	for _, raw := range envconf.SplitListEnv("ADDITIONAL_BASE_LANGS") {
+		lang, err := parsers.ParseLang("ADDITIONAL_BASE_LANGS", raw)
+		if err != nil {
+			return nil, err
+		}
+		if lang != baseLang && !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	return langs, nil
+}
+
+// parseWatchAllLangs reads WATCH_ALL_LANGS. Files in other languages are only
+// uploaded with their own language under PUSH_ALL_LANGS, so it's required.
+func parseWatchAllLangs() (bool, error) {
+	watchAll, err := parsers.ParseBoolEnv("WATCH_ALL_LANGS")
+	if err != nil {
+		return false, fmt.Errorf("invalid WATCH_ALL_LANGS: expected true or false: %w", err)
+	}
+	if !watchAll {
+		return false, nil
+	}
+
+	pushAll, err := parsers.ParseBoolEnv("PUSH_ALL_LANGS")
+	if err != nil {
+		return false, fmt.Errorf("invalid PUSH_ALL_LANGS: expected true or false: %w", err)
+	}
+	if !pushAll {
+		return false, fmt.Errorf("WATCH_ALL_LANGS requires PUSH_ALL_LANGS")
+	}
+	return true, nil
+}
+
+// parsePathspecStyle reads PATHSPEC_STYLE, defaulting to plain repo-relative patterns.
+func parsePathspecStyle() (string, error) {
+	return envconf.ParseEnumEnv("PATHSPEC_STYLE", []string{stylePlain, styleDot, styleGlob}, stylePlain)
+}
+
+// parseExtraPathsFile reads the optional EXTRA_PATHS_FILE, a user-maintained file
+// with one repo-relative pathspec per line that is merged into the generated
+// ones. Blank lines and lines starting with "#" are skipped; a leading "!"
+// excludes files.
+func parseExtraPathsFile() ([]string, error) {
+	raw := strings.TrimSpace(os.Getenv("EXTRA_PATHS_FILE"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	path, err := pathnorm.EnsureRepoRelativePath(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXTRA_PATHS_FILE: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read extra paths file: %w", err)
+	}
+
+	var pathspecs []string
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pattern, exclude := strings.CutPrefix(line, "!")
+		clean, err := pathnorm.EnsureRepoRelativePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pathspec on line %d of %s: %w", n+1, filepath.ToSlash(path), err)
+		}
+		if clean == "." {
+			return nil, fmt.Errorf("invalid pathspec on line %d of %s: %q would match the whole repository", n+1, filepath.ToSlash(path), line)
+		}
+
+		pathspec := filepath.ToSlash(clean)
+		if exclude {
+			pathspec = "!" + pathspec
+		}
+		pathspecs = append(pathspecs, pathspec)
+	}
+	return pathspecs, nil
+}
+
+// parseExcludes reads the optional EXCLUDE_PATTERNS globs and EXCLUDE_PATHS
+// files or directories, and returns them as repo-relative exclusion pathspecs.
+// A path excludes itself and everything below it.
+func parseExcludes() ([]string, error) {
+	var excludes []string
+	for _, p := range parsers.ParseStringArrayEnv("EXCLUDE_PATTERNS") {
+		clean, err := pathnorm.EnsureRepoRelativePattern(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EXCLUDE_PATTERNS: %w", err)
+		}
+		excludes = append(excludes, filepath.ToSlash(clean))
+	}
+
+	for _, p := range envconf.SplitListEnv("EXCLUDE_PATHS") {
+		clean, err := pathnorm.EnsureRepoRelativePath(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EXCLUDE_PATHS: %w", err)
+		}
+		if clean == "." {
+			return nil, fmt.Errorf("invalid EXCLUDE_PATHS: %q would exclude the whole repository", p)
+		}
+		path := filepath.ToSlash(clean)
+		excludes = append(excludes, path, path+"/**")
+	}
+
+	for _, e := range excludes {
+		if strings.HasPrefix(e, "!") {
+			return nil, fmt.Errorf("invalid exclusion %q: exclusions must not start with \"!\"", e)
+		}
+	}
+	return excludes, nil
+}
+
+// parsePathsOutputFile reads PATHS_OUTPUT_FILE, the file receiving the pathspecs.
+// Any location works; a "*" in the file name is replaced with a random string.
+// By default a unique file is created under RUNNER_TEMP, falling back to
+// defaultPathsFile outside GitHub Actions.
+func parsePathsOutputFile() (string, error) {
+	raw := strings.TrimSpace(os.Getenv("PATHS_OUTPUT_FILE"))
+	if raw == "" {
+		if runnerTemp := strings.TrimSpace(os.Getenv("RUNNER_TEMP")); runnerTemp != "" {
+			return filepath.Join(runnerTemp, runnerPathsFile), nil
+		}
+		return defaultPathsFile, nil
+	}
+	if strings.ContainsRune(raw, '\x00') || strings.HasSuffix(filepath.ToSlash(raw), "/") || filepath.Clean(raw) == "." {
+		return "", fmt.Errorf("invalid PATHS_OUTPUT_FILE: %q is not a file path", raw)
+	}
+	return filepath.Clean(raw), nil
+}
+
+// parseAutoDiscover reads AUTO_DISCOVER_PATHS. Discovered roots replace
+// TRANSLATIONS_PATH, so setting both is rejected.
+func parseAutoDiscover() (bool, error) {
+	autoDiscover, err := parsers.ParseBoolEnv("AUTO_DISCOVER_PATHS")
+	if err != nil {
+		return false, fmt.Errorf("invalid AUTO_DISCOVER_PATHS: expected true or false: %w", err)
+	}
+	if autoDiscover && len(envconf.SplitListEnv("TRANSLATIONS_PATH")) > 0 {
+		return false, fmt.Errorf("AUTO_DISCOVER_PATHS and TRANSLATIONS_PATH cannot be used together")
+	}
+	return autoDiscover, nil
+}
+
+// rootNormalizer cleans a TRANSLATIONS_PATH entry, accepting glob roots only
+// when allowGlobs is set.
+func rootNormalizer(allowGlobs bool) func(string) (string, error) {
+	return func(p string) (string, error) {
+		return ensureRepoRelativeRoot(p, allowGlobs)
+	}
+}
+
+// patternLineRoot cleans a TRANSLATIONS_PATH entry that a NAME_PATTERN line is
+// aligned with. findallfiles aligns the lines with the expanded roots, so
+// they'd drift apart from glob roots.
+func patternLineRoot(p string) (string, error) {
+	if hasGlobMeta(p) {
+		return "", fmt.Errorf("one NAME_PATTERN per line can't be used with the glob %q; use a mapping of the matched directories instead", p)
+	}
+	return pathnorm.EnsureRepoRelativePath(p)
+}
+
+// parseTranslationsPaths reads TRANSLATIONS_PATH. Glob roots are only accepted
+// when allowGlobs is set (TRANSLATIONS_PATH_GLOBS).
+func parseTranslationsPaths(allowGlobs bool) ([]string, error) {
+	paths, err := envconf.ParseRepoRelativePathsEnv("TRANSLATIONS_PATH", rootNormalizer(allowGlobs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to process params: %w", err)
+	}
+	return paths, nil
+}
+
+// parseFileExtensions reads FILE_EXT: either extensions applied to every root,
+// or a JSON/YAML mapping of roots to an extension or a list of extensions.
+// Every root must be listed in the mapping, since no extensions apply otherwise.
+func parseFileExtensions(roots []string) ([]string, map[string][]string, error) {
+	raw := os.Getenv("FILE_EXT")
+	if !envconf.IsPatternMapping(raw) {
+		fileExts, err := normalizers.NormalizeFileExtensions(envconf.SplitListEnv("FILE_EXT"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: %w", err)
+		}
+		return fileExts, nil, nil
+	}
+
+	obj, err := envconf.ParseMap(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid FILE_EXT: %w", err)
+	}
+
+	known := make(map[string]struct{}, len(roots))
+	for _, r := range roots {
+		known[filepath.ToSlash(r)] = struct{}{}
+	}
+
+	byRoot := make(map[string][]string, len(obj))
+	for key, value := range obj {
+		clean, err := pathnorm.EnsureRepoRelativePath(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: %w", err)
+		}
+		root := filepath.ToSlash(clean)
+		if _, ok := known[root]; !ok {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: %q is not listed in TRANSLATIONS_PATH", key)
+		}
+
+		lines, ok := envconf.StringList(value)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: extensions for %q must be a string or a list of strings", key)
+		}
+		exts, err := normalizers.NormalizeFileExtensions(lines)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT for %q: %w", key, err)
+		}
+		if prev, ok := byRoot[root]; ok && !slices.Equal(prev, exts) {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: conflicting extensions for %q", root)
+		}
+		byRoot[root] = exts
+	}
+
+	var all []string
+	for _, r := range roots {
+		exts, ok := byRoot[filepath.ToSlash(r)]
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid FILE_EXT: no extensions for %q", r)
+		}
+		for _, ext := range exts {
+			if !slices.Contains(all, ext) {
+				all = append(all, ext)
+			}
+		}
+	}
+	return all, byRoot, nil
+}
@@ -0,0 +1,1200 @@
+package storetranslationpaths
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lokalise-push-action/internal/namepattern"
+)
+
+func TestValidateEnvironment(t *testing.T) {
+	tests := []struct {
+		name            string
+		env             map[string]string
+		wantPaths       []string
+		wantBaseLang    string
+		wantFileExt     []string
+		wantNamePattern string
+		wantFlatNaming  bool
+		wantErr         string
+	}{
+		{
+			name: "Valid environment variables",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "\npath1\n\npath2\n",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "custom_name.json",
+				"FLAT_NAMING":       "true",
+			},
+			wantPaths:       []string{"path1", "path2"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "custom_name.json",
+			wantFlatNaming:  true,
+		},
+		{
+			name: "Missing environment variables",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "",
+				"BASE_LANG":         "",
+				"FILE_EXT":          "",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "false",
+			},
+			wantErr: "failed to process params: environment variable TRANSLATIONS_PATH is required",
+		},
+		{
+			name: "NAME_PATTERN trims and normalizes path-like pattern",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "  ./custom//../custom/**/*.json  ",
+				"FLAT_NAMING":       "false",
+			},
+			wantPaths:       []string{"locales"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "custom/**/*.json",
+			wantFlatNaming:  false,
+		},
+		{
+			name: "Root translation path",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": ".",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "true",
+			},
+			wantPaths:       []string{"."},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "",
+			wantFlatNaming:  true,
+		},
+		{
+			name: "Name pattern with ../",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "../**/*.json",
+				"FLAT_NAMING":       "false",
+			},
+			wantErr: "path escapes repo root",
+		},
+		{
+			name: "Translation path with ../",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "../locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "false",
+			},
+			wantErr: "failed to process params: invalid path",
+		},
+		{
+			name: "TRANSLATIONS_PATH cleans to .. fails",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "a/../..",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "false",
+			},
+			wantErr: "failed to process params: invalid path",
+		},
+		{
+			name: "TRANSLATIONS_PATH ./path is OK",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "./path",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "true",
+			},
+			wantPaths:       []string{"path"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "",
+			wantFlatNaming:  true,
+		},
+		{
+			name: "TRANSLATIONS_PATH /path fails",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "/path",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "true",
+			},
+			wantErr: "failed to process params: invalid path",
+		},
+		{
+			name: "NamePattern glob OK",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "translations",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "**/*.yaml",
+				"FLAT_NAMING":       "false",
+			},
+			wantPaths:       []string{"translations"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "**/*.yaml",
+			wantFlatNaming:  false,
+		},
+		{
+			name: "NamePattern nested glob OK",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "pkg/i18n",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "en/**/custom_*.json",
+				"FLAT_NAMING":       "false",
+			},
+			wantPaths:       []string{"pkg/i18n"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: filepath.Clean("en/**/custom_*.json"),
+			wantFlatNaming:  false,
+		},
+		{
+			name: "FILE_EXT normalization and dedupe",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          " JSON \n.yaml\njson\n YML \n .xml ",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "true",
+			},
+			wantPaths:       []string{"locales"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json", "yaml", "yml", "xml"},
+			wantNamePattern: "",
+			wantFlatNaming:  true,
+		},
+		{
+			name: "FILE_EXT empty after normalization fails",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          ".\n \n",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "true",
+			},
+			wantErr: "invalid FILE_EXT",
+		},
+		{
+			name: "Absolute NAME_PATTERN fails",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "/tmp/file.json",
+				"FLAT_NAMING":       "true",
+			},
+			wantErr: "must be relative",
+		},
+		{
+			name: "Whitespace NAME_PATTERN is treated as empty",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "   ",
+				"FLAT_NAMING":       "true",
+			},
+			wantPaths:       []string{"locales"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "",
+			wantFlatNaming:  true,
+		},
+		{
+			name: "NAME_PATTERN tilde fails",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "~/.config/*.json",
+				"FLAT_NAMING":       "true",
+			},
+			wantErr: "must be relative",
+		},
+		{
+			name: "FILE_EXT skips empty entries after normalization",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "\n \n.json\n.\n yaml \n",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "true",
+			},
+			wantPaths:       []string{"locales"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json", "yaml"},
+			wantNamePattern: "",
+			wantFlatNaming:  true,
+		},
+		{
+			name: "Leading dots and casing in FILE_EXT normalize correctly",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          ".json\n.JSON\n json ",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "true",
+			},
+			wantPaths:       []string{"locales"},
+			wantBaseLang:    "en",
+			wantFileExt:     []string{"json"},
+			wantNamePattern: "",
+			wantFlatNaming:  true,
+		},
+		{
+			name: "Invalid FLAT_NAMING fails",
+			env: map[string]string{
+				"TRANSLATIONS_PATH": "locales",
+				"BASE_LANG":         "en",
+				"FILE_EXT":          "json",
+				"NAME_PATTERN":      "",
+				"FLAT_NAMING":       "wat",
+			},
+			wantErr: "invalid FLAT_NAMING",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{
+				"TRANSLATIONS_PATH",
+				"BASE_LANG",
+				"FILE_EXT",
+				"NAME_PATTERN",
+				"FLAT_NAMING",
+			} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			got, err := validateEnvironment()
+
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %q", tt.wantErr, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got.Paths, tt.wantPaths) {
+				t.Fatalf("paths mismatch. want=%v got=%v", tt.wantPaths, got.Paths)
+			}
+			if got.BaseLang != tt.wantBaseLang {
+				t.Fatalf("baseLang mismatch. want=%q got=%q", tt.wantBaseLang, got.BaseLang)
+			}
+			if !reflect.DeepEqual(got.FileExts, tt.wantFileExt) {
+				t.Fatalf("fileExt mismatch. want=%v got=%v", tt.wantFileExt, got.FileExts)
+			}
+			if filepath.ToSlash(got.NamePattern) != filepath.ToSlash(tt.wantNamePattern) {
+				t.Fatalf("namePattern mismatch. want=%q got=%q", tt.wantNamePattern, got.NamePattern)
+			}
+			if got.FlatNaming != tt.wantFlatNaming {
+				t.Fatalf("flatNaming mismatch. want=%v got=%v", tt.wantFlatNaming, got.FlatNaming)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_FlatNamingPerRoot(t *testing.T) {
+	setEnv := func(t *testing.T, paths, flat string) {
+		t.Helper()
+		t.Setenv("TRANSLATIONS_PATH", paths)
+		t.Setenv("BASE_LANG", "en")
+		t.Setenv("FILE_EXT", "json")
+		t.Setenv("NAME_PATTERN", "")
+		t.Setenv("FLAT_NAMING", flat)
+	}
+
+	t.Run("list aligned with roots", func(t *testing.T) {
+		setEnv(t, "web/locales\n./app/i18n", "true, false")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]bool{"web/locales": true, "app/i18n": false}
+		if !reflect.DeepEqual(got.FlatNamingByRoot, want) {
+			t.Fatalf("expected %v, got %v", want, got.FlatNamingByRoot)
+		}
+	})
+
+	t.Run("count mismatch fails", func(t *testing.T) {
+		setEnv(t, "a\nb", "true\nfalse\ntrue")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "got 3 values for 2 TRANSLATIONS_PATH entries") {
+			t.Fatalf("expected count mismatch error, got %v", err)
+		}
+	})
+
+	t.Run("invalid value fails", func(t *testing.T) {
+		setEnv(t, "a\nb", "true\nnope")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "invalid FLAT_NAMING") {
+			t.Fatalf("expected FLAT_NAMING error, got %v", err)
+		}
+	})
+}
+
+func TestValidateEnvironment_NamePatternPerRoot(t *testing.T) {
+	setEnv := func(t *testing.T, paths, pattern string) {
+		t.Helper()
+		t.Setenv("TRANSLATIONS_PATH", paths)
+		t.Setenv("BASE_LANG", "en")
+		t.Setenv("FILE_EXT", "json")
+		t.Setenv("NAME_PATTERN", pattern)
+		t.Setenv("FLAT_NAMING", "")
+	}
+
+	t.Run("list aligned with roots", func(t *testing.T) {
+		setEnv(t, "web/locales\n./app/i18n", "**/*.yaml\nstrings/*.json")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]namepattern.Rule{"web/locales": {Pattern: "**/*.yaml"}, "app/i18n": {Pattern: "strings/*.json"}}
+		if !reflect.DeepEqual(got.NamePatternByRoot, want) {
+			t.Fatalf("expected %v, got %v", want, got.NamePatternByRoot)
+		}
+	})
+
+	t.Run("mapping covers a subset of roots", func(t *testing.T) {
+		setEnv(t, "web/locales\napp/i18n", "app/i18n: '*.strings'")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.nameRuleFor("app/i18n").Pattern != "*.strings" || got.nameRuleFor("web/locales").Pattern != "" {
+			t.Fatalf("unexpected per-root patterns: %v", got.NamePatternByRoot)
+		}
+	})
+
+	t.Run("count mismatch fails", func(t *testing.T) {
+		setEnv(t, "a\nb\nc", "*.json\n*.yaml")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "got 2 patterns for 3 TRANSLATIONS_PATH entries") {
+			t.Fatalf("expected count mismatch error, got %v", err)
+		}
+	})
+
+	t.Run("unknown root in mapping fails", func(t *testing.T) {
+		setEnv(t, "a", "b: '*.json'")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), `"b" is not listed in TRANSLATIONS_PATH`) {
+			t.Fatalf("expected unknown root error, got %v", err)
+		}
+	})
+}
+
+func TestValidateEnvironment_NamePatternExclusions(t *testing.T) {
+	setEnv := func(t *testing.T, paths, pattern string) {
+		t.Helper()
+		t.Setenv("TRANSLATIONS_PATH", paths)
+		t.Setenv("BASE_LANG", "en")
+		t.Setenv("FILE_EXT", "json")
+		t.Setenv("NAME_PATTERN", pattern)
+		t.Setenv("FLAT_NAMING", "")
+	}
+
+	t.Run("single pattern with exclusions", func(t *testing.T) {
+		setEnv(t, "config", "**/*.yaml\n!**/*.generated.yaml")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.NamePattern != "**/*.yaml" || !reflect.DeepEqual(got.NameExcludes, []string{"**/*.generated.yaml"}) {
+			t.Fatalf("unexpected config: %#v", got)
+		}
+	})
+
+	t.Run("mapping with exclusions", func(t *testing.T) {
+		setEnv(t, "web\nmobile", `{"web": ["**/*.yaml", "!**/*.generated.yaml"]}`)
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := namepattern.Rule{Pattern: "**/*.yaml", Excludes: []string{"**/*.generated.yaml"}}
+		if !reflect.DeepEqual(got.nameRuleFor("web"), want) {
+			t.Fatalf("expected %v, got %v", want, got.nameRuleFor("web"))
+		}
+	})
+
+	t.Run("exclusion without pattern fails", func(t *testing.T) {
+		setEnv(t, "config", "!**/*.generated.yaml")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "exclusions require a pattern") {
+			t.Fatalf("expected exclusion error, got %v", err)
+		}
+	})
+}
+
+func TestValidateEnvironment_NameRegex(t *testing.T) {
+	setEnv := func(t *testing.T, regex, pattern string) {
+		t.Helper()
+		t.Setenv("TRANSLATIONS_PATH", "src/main/resources")
+		t.Setenv("BASE_LANG", "en")
+		t.Setenv("FILE_EXT", "properties")
+		t.Setenv("NAME_PATTERN", pattern)
+		t.Setenv("NAME_REGEX", regex)
+		t.Setenv("FLAT_NAMING", "")
+	}
+
+	t.Run("valid expression", func(t *testing.T) {
+		setEnv(t, `messages_(?P<lang>[a-z]{2})\.properties`, "")
+
+		got, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.NameRegex == nil || !got.NameRegex.MatchString("messages_en.properties") || got.NameRegex.MatchString("x/messages_en.properties") {
+			t.Fatalf("unexpected NameRegex: %v", got.NameRegex)
+		}
+	})
+
+	t.Run("missing lang group fails", func(t *testing.T) {
+		setEnv(t, `messages_[a-z]{2}\.properties`, "")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "missing (?P<lang>...) capture group") {
+			t.Fatalf("expected capture group error, got %v", err)
+		}
+	})
+
+	t.Run("combined with name pattern fails", func(t *testing.T) {
+		setEnv(t, `(?P<lang>[a-z]{2})\.json`, "*.json")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+			t.Fatalf("expected conflict error, got %v", err)
+		}
+	})
+}
+
+func TestValidateEnvironment_AutoDiscoverPaths(t *testing.T) {
+	setEnv := func(t *testing.T, auto, paths string) {
+		t.Helper()
+		t.Setenv("AUTO_DISCOVER_PATHS", auto)
+		t.Setenv("MANIFEST_FILE", "")
+		t.Setenv("TRANSLATIONS_PATH", paths)
+		t.Setenv("BASE_LANG", "en")
+		t.Setenv("FILE_EXT", "json")
+		t.Setenv("NAME_PATTERN", "")
+		t.Setenv("NAME_REGEX", "")
+		t.Setenv("FLAT_NAMING", "false")
+	}
+
+	t.Run("discovers roots and layouts", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTree(t, dir, "apps/web/locales/en.json", "apps/api/i18n/en/errors.json")
+		t.Chdir(dir)
+		setEnv(t, "true", "")
+
+		cfg, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !cfg.AutoDiscovered {
+			t.Fatal("expected AutoDiscovered to be set")
+		}
+		if want := []string{"apps/api/i18n", "apps/web/locales"}; !reflect.DeepEqual(cfg.Paths, want) {
+			t.Fatalf("paths mismatch. want=%v got=%v", want, cfg.Paths)
+		}
+		if !cfg.flatNamingFor("apps/web/locales") || cfg.flatNamingFor("apps/api/i18n") {
+			t.Fatalf("unexpected layouts: %v", cfg.FlatNamingByRoot)
+		}
+	})
+
+	t.Run("rejects explicit paths", func(t *testing.T) {
+		setEnv(t, "true", "locales")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "AUTO_DISCOVER_PATHS and TRANSLATIONS_PATH cannot be used together") {
+			t.Fatalf("expected conflict error, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid value", func(t *testing.T) {
+		setEnv(t, "maybe", "locales")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "invalid AUTO_DISCOVER_PATHS") {
+			t.Fatalf("expected invalid value error, got %v", err)
+		}
+	})
+
+	t.Run("disabled keeps TRANSLATIONS_PATH", func(t *testing.T) {
+		setEnv(t, "false", "locales")
+
+		cfg, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.AutoDiscovered || !reflect.DeepEqual(cfg.Paths, []string{"locales"}) {
+			t.Fatalf("unexpected config: %+v", cfg)
+		}
+	})
+}
+
+func TestValidateEnvironment_ManifestFile(t *testing.T) {
+	setEnv := func(t *testing.T, manifest string) {
+		t.Helper()
+		dir := t.TempDir()
+		t.Chdir(dir)
+		if err := os.WriteFile(filepath.Join(dir, "lokalise.yml"), []byte(manifest), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("MANIFEST_FILE", "lokalise.yml")
+		t.Setenv("AUTO_DISCOVER_PATHS", "")
+		t.Setenv("TRANSLATIONS_PATH", "locales")
+		t.Setenv("BASE_LANG", "en")
+		t.Setenv("FILE_EXT", "json")
+		t.Setenv("NAME_PATTERN", "ignored.json")
+		t.Setenv("NAME_REGEX", "")
+		t.Setenv("FLAT_NAMING", "true")
+	}
+
+	t.Run("replaces roots and layouts", func(t *testing.T) {
+		setEnv(t, `
+file_ext: yaml
+roots:
+  - apps/web/locales
+  - path: apps/mobile/i18n
+    flat_naming: true
+  - path: packages/ui/i18n
+    name_pattern: "**/*.yaml"
+`)
+
+		cfg, err := validateEnvironment()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Manifest != "lokalise.yml" {
+			t.Fatalf("unexpected manifest %q", cfg.Manifest)
+		}
+		if want := []string{"apps/web/locales", "apps/mobile/i18n", "packages/ui/i18n"}; !reflect.DeepEqual(cfg.Paths, want) {
+			t.Fatalf("paths mismatch. want=%v got=%v", want, cfg.Paths)
+		}
+		if !reflect.DeepEqual(cfg.FileExts, []string{"yaml"}) {
+			t.Fatalf("unexpected file exts %v", cfg.FileExts)
+		}
+		if cfg.flatNamingFor("apps/web/locales") || !cfg.flatNamingFor("apps/mobile/i18n") {
+			t.Fatalf("unexpected layouts: %v", cfg.FlatNamingByRoot)
+		}
+		if rule := cfg.nameRuleFor("packages/ui/i18n"); rule.Pattern != "**/*.yaml" {
+			t.Fatalf("unexpected name rule %+v", rule)
+		}
+		if rule := cfg.nameRuleFor("apps/web/locales"); rule.Pattern != "" {
+			t.Fatalf("expected default layout, got %+v", rule)
+		}
+	})
+
+	t.Run("rejects auto-discovery", func(t *testing.T) {
+		setEnv(t, "roots: [locales]\n")
+		t.Setenv("TRANSLATIONS_PATH", "")
+		t.Setenv("AUTO_DISCOVER_PATHS", "true")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "AUTO_DISCOVER_PATHS and MANIFEST_FILE cannot be used together") {
+			t.Fatalf("expected conflict error, got %v", err)
+		}
+	})
+
+	t.Run("reports invalid manifest", func(t *testing.T) {
+		setEnv(t, "roots: locales\n")
+
+		_, err := validateEnvironment()
+		if err == nil || !strings.Contains(err.Error(), "invalid manifest lokalise.yml: roots must be a non-empty list") {
+			t.Fatalf("expected manifest error, got %v", err)
+		}
+	})
+}
+
+func TestValidateEnvironment_PathsOutputFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		runnerTemp string
+		want       string
+		wantErr    string
+	}{
+		{name: "default", value: "", want: filepath.Join(".git", "lokalise-action", "paths.txt")},
+		{name: "runner temp", value: "", runnerTemp: "/runner/temp", want: filepath.Join("/runner/temp", "lokalise-paths-*.txt")},
+		{name: "explicit wins over runner temp", value: "out/paths.txt", runnerTemp: "/runner/temp", want: filepath.Join("out", "paths.txt")},
+		{name: "unique name pattern", value: "out/paths-*.txt", want: filepath.Join("out", "paths-*.txt")},
+		{name: "relative", value: " tmp//paths.txt ", want: filepath.Join("tmp", "paths.txt")},
+		{name: "absolute", value: "/runner/temp/paths.txt", want: filepath.Clean("/runner/temp/paths.txt")},
+		{name: "directory", value: "tmp/", wantErr: `invalid PATHS_OUTPUT_FILE: "tmp/" is not a file path`},
+		{name: "dot", value: ".", wantErr: `invalid PATHS_OUTPUT_FILE: "." is not a file path`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "locales")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", "json")
+			t.Setenv("NAME_PATTERN", "")
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", "")
+			t.Setenv("PATHS_OUTPUT_FILE", tt.value)
+			t.Setenv("RUNNER_TEMP", tt.runnerTemp)
+
+			cfg, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.PathsFile != tt.want {
+				t.Fatalf("paths file mismatch. want=%q got=%q", tt.want, cfg.PathsFile)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_Excludes(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns string
+		paths    string
+		want     []string
+		wantErr  string
+	}{
+		{name: "none"},
+		{name: "patterns", patterns: "locales/**/*.generated.json\n./locales/tmp/*.json", want: []string{"locales/**/*.generated.json", "locales/tmp/*.json"}},
+		{name: "paths", paths: "locales/vendor\nlocales/en/legacy.json/", want: []string{"locales/vendor", "locales/vendor/**", "locales/en/legacy.json", "locales/en/legacy.json/**"}},
+		{name: "patterns before paths", patterns: "**/*.bak", paths: "tmp", want: []string{"**/*.bak", "tmp", "tmp/**"}},
+		{name: "negated pattern", patterns: "!locales/keep.json", wantErr: `invalid exclusion "!locales/keep.json"`},
+		{name: "escaping pattern", patterns: "../secrets/*.json", wantErr: "invalid EXCLUDE_PATTERNS"},
+		{name: "escaping path", paths: "../secrets", wantErr: "invalid EXCLUDE_PATHS"},
+		{name: "repository root", paths: ".", wantErr: `invalid EXCLUDE_PATHS: "." would exclude the whole repository`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "locales")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", "json")
+			t.Setenv("NAME_PATTERN", "")
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", "")
+			t.Setenv("EXCLUDE_PATTERNS", tt.patterns)
+			t.Setenv("EXCLUDE_PATHS", tt.paths)
+
+			cfg, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Excludes, tt.want) {
+				t.Fatalf("excludes mismatch. want=%q got=%q", tt.want, cfg.Excludes)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_AdditionalBaseLangs(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr string
+	}{
+		{name: "none"},
+		{name: "list", value: "en_US\n en-GB \n\nen_US\nen", want: []string{"en_US", "en-GB"}},
+		{name: "path separator", value: "en/US", wantErr: "ADDITIONAL_BASE_LANGS must not contain path separators"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "locales")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", "json")
+			t.Setenv("NAME_PATTERN", "")
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", "")
+			t.Setenv("ADDITIONAL_BASE_LANGS", tt.value)
+
+			cfg, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.ExtraBaseLangs, tt.want) {
+				t.Fatalf("additional base languages mismatch. want=%q got=%q", tt.want, cfg.ExtraBaseLangs)
+			}
+			if want := append([]string{"en"}, tt.want...); !reflect.DeepEqual(cfg.baseLangs(), want) {
+				t.Fatalf("base languages mismatch. want=%q got=%q", want, cfg.baseLangs())
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_CollapseExtensions(t *testing.T) {
+	for _, tt := range []struct {
+		value   string
+		want    bool
+		wantErr string
+	}{
+		{value: "", want: false},
+		{value: "true", want: true},
+		{value: "maybe", wantErr: "invalid COLLAPSE_EXTENSIONS"},
+	} {
+		t.Run("value "+tt.value, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "locales")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", "json\nyaml")
+			t.Setenv("NAME_PATTERN", "")
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", "")
+			t.Setenv("COLLAPSE_EXTENSIONS", tt.value)
+
+			cfg, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.CollapseExts != tt.want {
+				t.Fatalf("expected CollapseExts=%v, got %v", tt.want, cfg.CollapseExts)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_WatchPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr string
+	}{
+		{name: "none"},
+		{name: "files and globs", value: "lokalise.yml\n ./config//i18n.*.ts ", want: []string{"lokalise.yml", "config/i18n.*.ts"}},
+		{name: "comma-separated", value: "lokalise.yml, config/{a\\,b}.ts", want: []string{"lokalise.yml", "config/{a,b}.ts"}},
+		{name: "negated", value: "!lokalise.yml", wantErr: `invalid WATCH_PATTERNS: "!lokalise.yml" must not start with "!"`},
+		{name: "escaping", value: "../lokalise.yml", wantErr: "invalid WATCH_PATTERNS"},
+		{name: "repository root", value: "./", wantErr: "would watch the whole repository"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "locales")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", "json")
+			t.Setenv("NAME_PATTERN", "")
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", "")
+			t.Setenv("WATCH_PATTERNS", tt.value)
+
+			cfg, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.WatchPatterns, tt.want) {
+				t.Fatalf("watch patterns mismatch. want=%q got=%q", tt.want, cfg.WatchPatterns)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_WatchAllLangs(t *testing.T) {
+	tests := []struct {
+		name     string
+		watchAll string
+		pushAll  string
+		want     bool
+		wantErr  string
+	}{
+		{name: "disabled", pushAll: "true"},
+		{name: "enabled", watchAll: "true", pushAll: "true", want: true},
+		{name: "requires push_all_langs", watchAll: "true", pushAll: "false", wantErr: "WATCH_ALL_LANGS requires PUSH_ALL_LANGS"},
+		{name: "invalid", watchAll: "always", wantErr: "invalid WATCH_ALL_LANGS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "locales")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", "json")
+			t.Setenv("NAME_PATTERN", "")
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", "")
+			t.Setenv("WATCH_ALL_LANGS", tt.watchAll)
+			t.Setenv("PUSH_ALL_LANGS", tt.pushAll)
+
+			cfg, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.WatchAllLangs != tt.want {
+				t.Fatalf("expected WatchAllLangs=%v, got %v", tt.want, cfg.WatchAllLangs)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_PathspecStyle(t *testing.T) {
+	for _, tt := range []struct {
+		value   string
+		want    string
+		wantErr string
+	}{
+		{value: "", want: stylePlain},
+		{value: " Dot ", want: styleDot},
+		{value: "glob", want: styleGlob},
+		{value: "quoted", wantErr: "invalid PATHSPEC_STYLE"},
+	} {
+		t.Run("value "+tt.value, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "locales")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", "json")
+			t.Setenv("NAME_PATTERN", "")
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", "")
+			t.Setenv("PATHSPEC_STYLE", tt.value)
+
+			cfg, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.PathspecStyle != tt.want {
+				t.Fatalf("expected style %q, got %q", tt.want, cfg.PathspecStyle)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_FileExtMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		want     map[string][]string
+		wantExts []string
+		wantErr  string
+	}{
+		{
+			name:     "yaml mapping",
+			value:    "apps/web/locales: json\napps/ios/i18n: [strings, .StringsDict]",
+			want:     map[string][]string{"apps/web/locales": {"json"}, "apps/ios/i18n": {"strings", "stringsdict"}},
+			wantExts: []string{"json", "strings", "stringsdict"},
+		},
+		{
+			name:     "json mapping",
+			value:    `{"apps/web/locales/": ["json", "yaml"], "apps/ios/i18n": "json"}`,
+			want:     map[string][]string{"apps/web/locales": {"json", "yaml"}, "apps/ios/i18n": {"json"}},
+			wantExts: []string{"json", "yaml"},
+		},
+		{
+			name:    "missing root",
+			value:   "apps/web/locales: json",
+			wantErr: `invalid FILE_EXT: no extensions for "apps/ios/i18n"`,
+		},
+		{
+			name:    "unknown root",
+			value:   "apps/web/locales: json\napps/ios/i18n: strings\napps/api: json",
+			wantErr: `"apps/api" is not listed in TRANSLATIONS_PATH`,
+		},
+		{
+			name:    "empty extensions",
+			value:   "apps/web/locales: []\napps/ios/i18n: strings",
+			wantErr: `invalid FILE_EXT for "apps/web/locales"`,
+		},
+		{
+			name:    "not a list of strings",
+			value:   "apps/web/locales: {json: true}\napps/ios/i18n: strings",
+			wantErr: "must be a string or a list of strings",
+		},
+		{
+			name:    "conflicting extensions",
+			value:   `{"apps/web/locales": "json", "apps/web/locales/": "yaml", "apps/ios/i18n": "strings"}`,
+			wantErr: `conflicting extensions for "apps/web/locales"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "apps/web/locales\napps/ios/i18n")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", tt.value)
+			t.Setenv("NAME_PATTERN", "")
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", "")
+
+			cfg, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.FileExtsByRoot, tt.want) {
+				t.Fatalf("expected FileExtsByRoot=%v, got %v", tt.want, cfg.FileExtsByRoot)
+			}
+			if !reflect.DeepEqual(cfg.FileExts, tt.wantExts) {
+				t.Fatalf("expected FileExts=%v, got %v", tt.wantExts, cfg.FileExts)
+			}
+		})
+	}
+
+	t.Run("auto discovery", func(t *testing.T) {
+		t.Setenv("TRANSLATIONS_PATH", "")
+		t.Setenv("AUTO_DISCOVER_PATHS", "true")
+		t.Setenv("BASE_LANG", "en")
+		t.Setenv("FILE_EXT", "apps/web/locales: json")
+
+		if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "AUTO_DISCOVER_PATHS cannot be used with a FILE_EXT mapping") {
+			t.Fatalf("expected mapping error, got %v", err)
+		}
+	})
+}
+
+func TestValidateEnvironment_ExtraPathsFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "merged lines",
+			content: "# Legacy exports\nlegacy/en/*.json\n\n  ./docs/i18n/en.yml  \n!legacy/en/tmp.json\r\n",
+			want:    []string{"legacy/en/*.json", "docs/i18n/en.yml", "!legacy/en/tmp.json"},
+		},
+		{
+			name:    "outside the repository",
+			content: "legacy/en.json\n../shared/en.json\n",
+			wantErr: "invalid pathspec on line 2 of extra-paths.txt",
+		},
+		{
+			name:    "whole repository",
+			content: "./\n",
+			wantErr: "would match the whole repository",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+			if err := os.WriteFile(filepath.Join(dir, "extra-paths.txt"), []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("TRANSLATIONS_PATH", "locales")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", "json")
+			t.Setenv("NAME_PATTERN", "")
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", "")
+			t.Setenv("EXTRA_PATHS_FILE", "extra-paths.txt")
+
+			cfg, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.ExtraPathspecs, tt.want) {
+				t.Fatalf("expected ExtraPathspecs=%v, got %v", tt.want, cfg.ExtraPathspecs)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		t.Setenv("TRANSLATIONS_PATH", "locales")
+		t.Setenv("BASE_LANG", "en")
+		t.Setenv("FILE_EXT", "json")
+		t.Setenv("EXTRA_PATHS_FILE", "extra-paths.txt")
+
+		if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "cannot read extra paths file") {
+			t.Fatalf("expected read error, got %v", err)
+		}
+	})
+}
+
+func TestValidateEnvironment_SummarizePathspecs(t *testing.T) {
+	for _, tt := range []struct {
+		value   string
+		want    bool
+		wantErr string
+	}{
+		{value: "", want: false},
+		{value: "false", want: false},
+		{value: "true", want: true},
+		{value: "often", wantErr: "invalid SUMMARIZE_PATHSPECS"},
+	} {
+		t.Run("value "+tt.value, func(t *testing.T) {
+			t.Setenv("TRANSLATIONS_PATH", "locales")
+			t.Setenv("BASE_LANG", "en")
+			t.Setenv("FILE_EXT", "json")
+			t.Setenv("NAME_PATTERN", "")
+			t.Setenv("NAME_REGEX", "")
+			t.Setenv("FLAT_NAMING", "")
+			t.Setenv("SUMMARIZE_PATHSPECS", tt.value)
+
+			cfg, err := validateEnvironment()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.SummarizePathspecs != tt.want {
+				t.Fatalf("expected SummarizePathspecs=%v, got %v", tt.want, cfg.SummarizePathspecs)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment_ReportsAllErrors(t *testing.T) {
+	t.Setenv("AUTO_DISCOVER_PATHS", "")
+	t.Setenv("MANIFEST_FILE", "")
+	t.Setenv("TRANSLATIONS_PATH", "locales")
+	t.Setenv("BASE_LANG", "")
+	t.Setenv("FILE_EXT", "json")
+	t.Setenv("NAME_PATTERN", "")
+	t.Setenv("FLAT_NAMING", "")
+	t.Setenv("COLLAPSE_EXTENSIONS", "maybe")
+	t.Setenv("PATHSPEC_STYLE", "quoted")
+
+	_, err := validateEnvironment()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	for _, want := range []string{"3 configuration problems", "BASE_LANG", "invalid COLLAPSE_EXTENSIONS", "invalid PATHSPEC_STYLE"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %q", want, err.Error())
+		}
+	}
+}
+
+func TestValidateEnvironment_TranslationsPathGlobs(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTree(t, dir,
+		"packages/web/locales/en.json",
+		"packages/app/locales/en/main.json",
+		"packages/docs/README.md",
+		"shared/en.json",
+	)
+	t.Setenv("AUTO_DISCOVER_PATHS", "")
+	t.Setenv("MANIFEST_FILE", "")
+	t.Setenv("TRANSLATIONS_PATH", "packages/*/locales\nshared")
+	t.Setenv("BASE_LANG", "en")
+	t.Setenv("FILE_EXT", "json")
+	t.Setenv("NAME_PATTERN", "")
+	t.Setenv("NAME_REGEX", "")
+	t.Setenv("FLAT_NAMING", "true,true")
+
+	t.Setenv("TRANSLATIONS_PATH_GLOBS", "")
+	if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "glob characters are not allowed") {
+		t.Fatalf("expected globs to be rejected by default, got %v", err)
+	}
+
+	t.Setenv("TRANSLATIONS_PATH_GLOBS", "true")
+	cfg, err := validateEnvironment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"packages/app/locales", "packages/web/locales", "shared"}; !reflect.DeepEqual(cfg.Paths, want) {
+		t.Fatalf("expected Paths=%q, got %q", want, cfg.Paths)
+	}
+	if !cfg.GlobsExpanded {
+		t.Fatal("expected GlobsExpanded to be set")
+	}
+	for _, root := range cfg.Paths {
+		if !cfg.flatNamingFor(root) {
+			t.Fatalf("expected %q to inherit the flat layout", root)
+		}
+	}
+
+	t.Setenv("FLAT_NAMING", "")
+	t.Setenv("NAME_PATTERN", "*.json\n**/*.json")
+	if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "use a mapping of the matched directories instead") {
+		t.Fatalf("expected aligned NAME_PATTERN to be rejected, got %v", err)
+	}
+
+	t.Setenv("NAME_PATTERN", "")
+	t.Setenv("TRANSLATIONS_PATH", "apps/*/locales")
+	if _, err := validateEnvironment(); err == nil || !strings.Contains(err.Error(), "matches no directories") {
+		t.Fatalf("expected no-match error, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package storetranslationpaths
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// PATHSPEC_STYLE values: how written pathspecs are anchored to the repository root.
+const (
+	stylePlain = "plain" // locales/en.json
+	styleDot   = "dot"   // ./locales/en.json
+	styleGlob  = "glob"  // :(glob)locales/en.json, exclusions use :(glob,exclude)
+)
+
+// writeUniqueLine writes a normalized newline-terminated pathspec once, anchored
+// according to style. Pathspecs are slash-separated; backslashes escape glob
+// characters, and a leading "!" marks an exclusion.
+func writeUniqueLine(writer io.Writer, seen map[string]struct{}, pathspec, style string) error {
+	exclude := strings.HasPrefix(pathspec, "!")
+	line := path.Clean(strings.TrimPrefix(pathspec, "!"))
+	if line == "." || line == "" {
+		return fmt.Errorf("empty pathspec")
+	}
+	line = anchorPathspec(line, exclude, style)
+
+	if _, ok := seen[line]; ok {
+		return nil
+	}
+
+	if _, err := fmt.Fprintln(writer, line); err != nil {
+		return err
+	}
+
+	seen[line] = struct{}{}
+	return nil
+}
+
+// anchorPathspec prefixes a cleaned pathspec for the given style.
+func anchorPathspec(pattern string, exclude bool, style string) string {
+	switch style {
+	case styleGlob:
+		if exclude {
+			return ":(glob,exclude)" + pattern
+		}
+		return ":(glob)" + pattern
+	case styleDot:
+		pattern = "./" + pattern
+	}
+	if exclude {
+		return "!" + pattern
+	}
+	return pattern
+}
+
+// createOutputFile creates the file consumed later by detectchanges,
+// along with any missing parent directories. A "*" in the file name is replaced
+// with a random string (see os.CreateTemp), so every run gets its own file.
+func createOutputFile(path string) (*os.File, error) {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create output directory: %w", err)
+	}
+
+	if strings.Contains(name, "*") {
+		return os.CreateTemp(dir, name)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// ignoreFileFor names the exclusions file stored next to the pathspecs file,
+// e.g. paths.txt -> paths.ignore.txt.
+func ignoreFileFor(pathsFile string) string {
+	ext := filepath.Ext(pathsFile)
+	return strings.TrimSuffix(pathsFile, ext) + ".ignore" + ext
+}
+
+// closeOutputFile closes the output file.
+func closeOutputFile(file *os.File) error {
+	return file.Close()
+}
@@ -1,4 +1,4 @@
-package main
+package storetranslationpaths
 
 import (
 	"bytes"
@@ -17,6 +17,7 @@ func TestWriteUniqueLine(t *testing.T) {
 		initialSeen map[string]struct{}
 		wantOutput  string
 		wantSeenKey string
+		style       string
 		wantErr     string
 		writer      *failingWriter
 	}{
@@ -43,6 +44,44 @@ func TestWriteUniqueLine(t *testing.T) {
 			wantOutput:  "translations/en.json\n",
 			wantSeenKey: "translations/en.json",
 		},
+		{
+			name:        "dot style",
+			path:        "translations/en.json",
+			style:       styleDot,
+			initialSeen: map[string]struct{}{},
+			wantOutput:  "./translations/en.json\n",
+			wantSeenKey: "./translations/en.json",
+		},
+		{
+			name:        "dot style exclusion",
+			path:        "!translations/en/fixtures/**",
+			style:       styleDot,
+			initialSeen: map[string]struct{}{},
+			wantOutput:  "!./translations/en/fixtures/**\n",
+			wantSeenKey: "!./translations/en/fixtures/**",
+		},
+		{
+			name:        "glob style",
+			path:        "./translations/en/**/*.json",
+			style:       styleGlob,
+			initialSeen: map[string]struct{}{},
+			wantOutput:  ":(glob)translations/en/**/*.json\n",
+			wantSeenKey: ":(glob)translations/en/**/*.json",
+		},
+		{
+			name:        "glob style exclusion",
+			path:        "!translations/en/fixtures/**",
+			style:       styleGlob,
+			initialSeen: map[string]struct{}{},
+			wantOutput:  ":(glob,exclude)translations/en/fixtures/**\n",
+			wantSeenKey: ":(glob,exclude)translations/en/fixtures/**",
+		},
+		{
+			name:        "rejects empty pathspec",
+			path:        "!./",
+			initialSeen: map[string]struct{}{},
+			wantErr:     "empty pathspec",
+		},
 		{
 			name:        "returns writer error",
 			path:        "translations/en.json",
@@ -65,7 +104,7 @@ func TestWriteUniqueLine(t *testing.T) {
 				w = tt.writer
 			}
 
-			err := writeUniqueLine(w, seen, tt.path)
+			err := writeUniqueLine(w, seen, tt.path, tt.style)
 
 			if tt.wantErr != "" {
 				if err == nil {
@@ -106,7 +145,7 @@ func TestCreateOutputFile(t *testing.T) {
 		t.Fatalf("failed to chdir: %v", err)
 	}
 
-	file, err := createOutputFile()
+	file, err := createOutputFile(defaultPathsFile)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -146,3 +185,52 @@ type failingWriter struct{}
 func (f failingWriter) Write([]byte) (int, error) {
 	return 0, errors.New("write failed")
 }
+
+func TestCreateOutputFile_CustomPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "dir", "pathspecs.txt")
+
+	file, err := createOutputFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if file.Name() != path {
+		t.Fatalf("unexpected file name: want=%q got=%q", path, file.Name())
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected file to exist, stat failed: %v", err)
+	}
+}
+
+func TestCreateOutputFile_UniqueName(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+
+	file, err := createOutputFile(filepath.Join(dir, "paths-*.txt"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	name := filepath.Base(file.Name())
+	if filepath.Dir(file.Name()) != dir || !strings.HasPrefix(name, "paths-") || !strings.HasSuffix(name, ".txt") || name == "paths-*.txt" {
+		t.Fatalf("unexpected file name %q", file.Name())
+	}
+}
+
+func TestIgnoreFileFor(t *testing.T) {
+	tests := map[string]string{
+		"paths.txt":                       "paths.ignore.txt",
+		"/tmp/lokalise-paths-123.txt":     "/tmp/lokalise-paths-123.ignore.txt",
+		filepath.Join("out", "pathspecs"): filepath.Join("out", "pathspecs.ignore"),
+	}
+	for in, want := range tests {
+		if got := ignoreFileFor(in); got != want {
+			t.Errorf("ignoreFileFor(%q) = %q, want %q", in, got, want)
+		}
+	}
+}